@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package factory
+
+import (
+	"os"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+)
+
+// VaultKeystoreOpts configures a key store backed by the KV (version 2)
+// secrets engine of a HashiCorp Vault server.
+type VaultKeystoreOpts struct {
+	Address string `mapstructure:"address" json:"address" yaml:"Address"`
+	// Token authenticates to Vault. If empty, TokenEnv is consulted.
+	Token string `mapstructure:"token,omitempty" json:"token,omitempty" yaml:"Token,omitempty"`
+	// TokenEnv names the environment variable holding the Vault token.
+	// Defaults to VAULT_TOKEN.
+	TokenEnv string `mapstructure:"tokenenv,omitempty" json:"tokenenv,omitempty" yaml:"TokenEnv,omitempty"`
+	Mount    string `mapstructure:"mount,omitempty" json:"mount,omitempty" yaml:"Mount,omitempty"`
+	Prefix   string `mapstructure:"prefix,omitempty" json:"prefix,omitempty" yaml:"Prefix,omitempty"`
+
+	// PasswordEnv and PasswordProvider supply the passphrase encrypting
+	// the private keys, as for FileKeystoreOpts.
+	PasswordEnv      string           `mapstructure:"passwordenv,omitempty" json:"passwordenv,omitempty" yaml:"PasswordEnv,omitempty"`
+	PasswordProvider PasswordProvider `mapstructure:"-" json:"-" yaml:"-"`
+
+	// TLS secures the connection to a Vault server reached over https.
+	TLS *VaultTLSOpts `mapstructure:"tls,omitempty" json:"tls,omitempty" yaml:"TLS,omitempty"`
+}
+
+// VaultTLSOpts holds the PEM files securing the connection to Vault.
+type VaultTLSOpts struct {
+	// RootCertFile holds the CA certificates the certificate of the Vault
+	// server is verified against. If empty, the system roots are used.
+	RootCertFile string `mapstructure:"rootcertfile,omitempty" json:"rootcertfile,omitempty" yaml:"RootCertFile,omitempty"`
+	// ClientCert and ClientKey authenticate the peer to Vault, when it
+	// requires client certificates.
+	ClientCert string `mapstructure:"clientcert,omitempty" json:"clientcert,omitempty" yaml:"ClientCert,omitempty"`
+	ClientKey  string `mapstructure:"clientkey,omitempty" json:"clientkey,omitempty" yaml:"ClientKey,omitempty"`
+}
+
+func newVaultKeyStore(opts *VaultKeystoreOpts) (bccsp.KeyStore, error) {
+	token := opts.Token
+	if token == "" {
+		env := opts.TokenEnv
+		if env == "" {
+			env = "VAULT_TOKEN"
+		}
+		token = os.Getenv(env)
+	}
+
+	pwd, err := getKeyStorePassword(opts.PasswordProvider, opts.PasswordEnv)
+	if err != nil {
+		return nil, err
+	}
+	conf := &sw.VaultConfig{
+		Address: opts.Address,
+		Token:   token,
+		Mount:   opts.Mount,
+		Prefix:  opts.Prefix,
+	}
+	if opts.TLS != nil {
+		conf.TLS = sw.VaultTLSConfig{
+			RootCertFile: opts.TLS.RootCertFile,
+			ClientCert:   opts.TLS.ClientCert,
+			ClientKey:    opts.TLS.ClientKey,
+		}
+	}
+	backend, err := sw.NewVaultKeyStoreBackend(conf)
+	if err != nil {
+		return nil, err
+	}
+	return sw.NewBackendKeyStore(pwd, backend, false)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package factory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVaultKeystoreMissingToken(t *testing.T) {
+	opts := &FactoryOpts{
+		SwOpts: &SwOpts{
+			SecLevel:      256,
+			HashFamily:    "SHA2",
+			KeyStoreType:  VaultKeyStoreType,
+			VaultKeystore: &VaultKeystoreOpts{Address: "http://localhost:8200", TokenEnv: "FABRIC_TEST_UNSET_VAULT_TOKEN"},
+		},
+	}
+	_, err := (&SWFactory{}).Get(opts)
+	assert.Error(t, err)
+}
+
+func TestVaultKeystoreTLS(t *testing.T) {
+	opts := &FactoryOpts{
+		SwOpts: &SwOpts{
+			SecLevel:     256,
+			HashFamily:   "SHA2",
+			KeyStoreType: VaultKeyStoreType,
+			VaultKeystore: &VaultKeystoreOpts{Address: "https://localhost:8200", Token: "s3cr3t",
+				TLS: &VaultTLSOpts{RootCertFile: "/non/existent/ca.pem"}},
+		},
+	}
+	_, err := (&SWFactory{}).Get(opts)
+	assert.Error(t, err)
+
+	opts.SwOpts.VaultKeystore.TLS = &VaultTLSOpts{ClientCert: "/non/existent/client.pem"}
+	_, err = (&SWFactory{}).Get(opts)
+	assert.Error(t, err, "a client certificate without its key should be rejected")
+
+	opts.SwOpts.VaultKeystore.TLS = &VaultTLSOpts{}
+	_, err = (&SWFactory{}).Get(opts)
+	assert.NoError(t, err)
+}
+
+func TestKeyStoreType(t *testing.T) {
+	vault := &VaultKeystoreOpts{Address: "http://localhost:8200"}
+	for _, test := range []struct {
+		opts     SwOpts
+		expected string
+	}{
+		{SwOpts{}, FileKeyStoreType},
+		{SwOpts{FileKeystore: &FileKeystoreOpts{KeyStorePath: "/tmp"}}, FileKeyStoreType},
+		{SwOpts{KeyStoreType: VaultKeyStoreType, VaultKeystore: vault}, VaultKeyStoreType},
+		// Empty file keystore options, as left by the sample configuration, don't conflict
+		{SwOpts{KeyStoreType: VaultKeyStoreType, VaultKeystore: vault, FileKeystore: &FileKeystoreOpts{}}, VaultKeyStoreType},
+	} {
+		ksType, err := test.opts.keyStoreType()
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, ksType)
+	}
+
+	for _, opts := range []SwOpts{
+		{KeyStoreType: "pkcs12"},
+		{VaultKeystore: vault},
+		{KeyStoreType: VaultKeyStoreType},
+		{KeyStoreType: "kms"},
+		{KeyStoreType: VaultKeyStoreType, VaultKeystore: vault, FileKeystore: &FileKeystoreOpts{KeyStorePath: "/tmp"}},
+	} {
+		_, err := opts.keyStoreType()
+		assert.Error(t, err, "options %+v should be rejected", opts)
+	}
+
+	_, err := (&SWFactory{}).Get(&FactoryOpts{SwOpts: &SwOpts{SecLevel: 256, HashFamily: "SHA2", VaultKeystore: vault}})
+	assert.Error(t, err, "Vault keystore options without selecting the vault keystore type should be rejected")
+}
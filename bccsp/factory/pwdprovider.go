@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package factory
+
+import (
+	"fmt"
+	"os"
+)
+
+// PasswordProvider supplies the passphrase used by the file-based
+// keystore to encrypt private keys at rest.
+type PasswordProvider interface {
+	// GetPassword returns the keystore passphrase.
+	// A nil or empty passphrase means keys are stored unencrypted.
+	GetPassword() ([]byte, error)
+}
+
+// EnvPasswordProvider reads the keystore passphrase from the
+// environment variable it names.
+type EnvPasswordProvider string
+
+// GetPassword returns the value of the environment variable.
+func (e EnvPasswordProvider) GetPassword() ([]byte, error) {
+	pwd, ok := os.LookupEnv(string(e))
+	if !ok || pwd == "" {
+		return nil, fmt.Errorf("Keystore password environment variable [%s] is not set", string(e))
+	}
+	return []byte(pwd), nil
+}
+
+// getKeyStorePassword resolves the passphrase of a keystore.
+// An explicitly set PasswordProvider takes precedence over passwordEnv.
+func getKeyStorePassword(provider PasswordProvider, passwordEnv string) ([]byte, error) {
+	if provider == nil {
+		if passwordEnv == "" {
+			return nil, nil
+		}
+		provider = EnvPasswordProvider(passwordEnv)
+	}
+
+	pwd, err := provider.GetPassword()
+	if err != nil {
+		return nil, fmt.Errorf("Failed obtaining keystore password: %s", err)
+	}
+	return pwd, nil
+}
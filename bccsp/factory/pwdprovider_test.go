@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package factory
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPasswordProvider struct {
+	pwd []byte
+	err error
+}
+
+func (m *mockPasswordProvider) GetPassword() ([]byte, error) {
+	return m.pwd, m.err
+}
+
+func TestGetKeyStorePassword(t *testing.T) {
+	pwd, err := getKeyStorePassword(nil, "")
+	assert.NoError(t, err)
+	assert.Nil(t, pwd)
+
+	_, err = getKeyStorePassword(nil, "FABRIC_TEST_UNSET_KEYSTORE_PWD")
+	assert.Error(t, err)
+
+	os.Setenv("FABRIC_TEST_KEYSTORE_PWD", "secret")
+	defer os.Unsetenv("FABRIC_TEST_KEYSTORE_PWD")
+	pwd, err = getKeyStorePassword(nil, "FABRIC_TEST_KEYSTORE_PWD")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("secret"), pwd)
+
+	// The provider takes precedence over the environment
+	pwd, err = getKeyStorePassword(&mockPasswordProvider{pwd: []byte("fromprovider")}, "FABRIC_TEST_KEYSTORE_PWD")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("fromprovider"), pwd)
+
+	_, err = getKeyStorePassword(&mockPasswordProvider{err: errors.New("unavailable")}, "")
+	assert.Error(t, err)
+}
+
+func TestSWFactoryEncryptedKeyStore(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "swfactoryks")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	f := &SWFactory{}
+	opts := &FactoryOpts{
+		SwOpts: &SwOpts{
+			SecLevel:   256,
+			HashFamily: "SHA2",
+			FileKeystore: &FileKeystoreOpts{
+				KeyStorePath:     tempDir,
+				PasswordProvider: &mockPasswordProvider{err: errors.New("unavailable")},
+			},
+		},
+	}
+	_, err = f.Get(opts)
+	assert.Error(t, err)
+
+	opts.SwOpts.FileKeystore.PasswordProvider = &mockPasswordProvider{pwd: []byte("secret")}
+	csp, err := f.Get(opts)
+	assert.NoError(t, err)
+	assert.NotNil(t, csp)
+}
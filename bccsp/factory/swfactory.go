@@ -26,6 +26,11 @@ import (
 const (
 	// SoftwareBasedFactoryName is the name of the factory of the software-based BCCSP implementation
 	SoftwareBasedFactoryName = "SW"
+
+	// FileKeyStoreType selects the file-based keystore
+	FileKeyStoreType = "file"
+	// VaultKeyStoreType selects the keystore backed by HashiCorp Vault
+	VaultKeyStoreType = "vault"
 )
 
 // SWFactory is the factory of the software-based BCCSP.
@@ -48,15 +53,34 @@ func (f *SWFactory) Get(config *FactoryOpts) (bccsp.BCCSP, error) {
 	var ks bccsp.KeyStore
 	if swOpts.Ephemeral == true {
 		ks = sw.NewDummyKeyStore()
-	} else if swOpts.FileKeystore != nil {
-		fks, err := sw.NewFileBasedKeyStore(nil, swOpts.FileKeystore.KeyStorePath, false)
+	} else {
+		ksType, err := swOpts.keyStoreType()
 		if err != nil {
-			return nil, fmt.Errorf("Failed to initialize software key store: %s", err)
+			return nil, fmt.Errorf("Invalid config. %s", err)
+		}
+		switch ksType {
+		case VaultKeyStoreType:
+			vks, err := newVaultKeyStore(swOpts.VaultKeystore)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to initialize Vault key store: %s", err)
+			}
+			ks = vks
+		default:
+			if swOpts.FileKeystore == nil {
+				// Default to DummyKeystore
+				ks = sw.NewDummyKeyStore()
+				break
+			}
+			pwd, err := getKeyStorePassword(swOpts.FileKeystore.PasswordProvider, swOpts.FileKeystore.PasswordEnv)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to initialize software key store: %s", err)
+			}
+			fks, err := sw.NewFileBasedKeyStore(pwd, swOpts.FileKeystore.KeyStorePath, false)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to initialize software key store: %s", err)
+			}
+			ks = fks
 		}
-		ks = fks
-	} else {
-		// Default to DummyKeystore
-		ks = sw.NewDummyKeyStore()
 	}
 
 	return sw.New(swOpts.SecLevel, swOpts.HashFamily, ks)
@@ -69,14 +93,56 @@ type SwOpts struct {
 	HashFamily string `mapstructure:"hash" json:"hash" yaml:"Hash"`
 
 	// Keystore Options
-	Ephemeral     bool               `mapstructure:"tempkeys,omitempty" json:"tempkeys,omitempty"`
+	Ephemeral bool `mapstructure:"tempkeys,omitempty" json:"tempkeys,omitempty"`
+	// KeyStoreType selects the keystore backend, file (the default) or
+	// vault. Only the options of the selected backend may be set.
+	KeyStoreType  string             `mapstructure:"keystoretype,omitempty" json:"keystoretype,omitempty" yaml:"KeyStoreType,omitempty"`
 	FileKeystore  *FileKeystoreOpts  `mapstructure:"filekeystore,omitempty" json:"filekeystore,omitempty" yaml:"FileKeyStore"`
 	DummyKeystore *DummyKeystoreOpts `mapstructure:"dummykeystore,omitempty" json:"dummykeystore,omitempty"`
+	VaultKeystore *VaultKeystoreOpts `mapstructure:"vaultkeystore,omitempty" json:"vaultkeystore,omitempty" yaml:"VaultKeyStore,omitempty"`
 }
 
 // Pluggable Keystores, could add JKS, P12, etc..
 type FileKeystoreOpts struct {
 	KeyStorePath string `mapstructure:"keystore" yaml:"KeyStore"`
+
+	// PasswordEnv is the name of the environment variable holding the
+	// passphrase used to encrypt private keys at rest. If empty, and no
+	// PasswordProvider is set, keys are stored unencrypted.
+	PasswordEnv string `mapstructure:"passwordenv,omitempty" json:"passwordenv,omitempty" yaml:"PasswordEnv,omitempty"`
+
+	// PasswordProvider, when set programmatically, takes precedence over PasswordEnv
+	PasswordProvider PasswordProvider `mapstructure:"-" json:"-" yaml:"-"`
+}
+
+// isEmpty returns true if none of the options are set
+func (o *FileKeystoreOpts) isEmpty() bool {
+	return o.KeyStorePath == "" && o.PasswordEnv == "" && o.PasswordProvider == nil
 }
 
 type DummyKeystoreOpts struct{}
+
+// keyStoreType returns the keystore backend selected by the options,
+// failing if the options of another backend are set as well.
+func (o *SwOpts) keyStoreType() (string, error) {
+	configured := map[string]bool{
+		FileKeyStoreType:  o.FileKeystore != nil && !o.FileKeystore.isEmpty(),
+		VaultKeyStoreType: o.VaultKeystore != nil,
+	}
+	ksType := o.KeyStoreType
+	if ksType == "" {
+		ksType = FileKeyStoreType
+	}
+	if _, known := configured[ksType]; !known {
+		return "", fmt.Errorf("Unknown keystore type [%s]", o.KeyStoreType)
+	}
+	for other, set := range configured {
+		if set && other != ksType {
+			return "", fmt.Errorf("The keystore type is [%s], but %s keystore options are set", ksType, other)
+		}
+	}
+	if ksType == VaultKeyStoreType && o.VaultKeystore == nil {
+		return "", errors.New("The keystore type is [vault], but no Vault keystore options are set")
+	}
+	return ksType, nil
+}
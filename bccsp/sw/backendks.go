@@ -0,0 +1,263 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sw
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/utils"
+)
+
+// KeyStoreBackend persists the PEM encoded keys of a KeyStore.
+// Entries are addressed by an alias of the form <hex(SKI)>_<suffix>, where
+// suffix is one of "sk" (private key), "pk" (public key) or "key" (AES key).
+type KeyStoreBackend interface {
+	// Load returns the raw entry stored under the given alias.
+	Load(alias string) ([]byte, error)
+
+	// Store saves the raw entry under the given alias.
+	Store(alias string, raw []byte) error
+
+	// List returns the aliases of all the stored entries.
+	List() ([]string, error)
+}
+
+// NewBackendKeyStore instantiates a KeyStore that encodes keys as PEM
+// and persists them through the passed backend.
+// The PEMs are encrypted if a non-empty password is specified.
+// It can be also be set as read only. In this case, any store operation
+// will be forbidden
+func NewBackendKeyStore(pwd []byte, backend KeyStoreBackend, readOnly bool) (bccsp.KeyStore, error) {
+	if backend == nil {
+		return nil, errors.New("Invalid KeyStore backend. It must be different from nil.")
+	}
+
+	return &backendKeyStore{
+		backend:  backend,
+		pwd:      utils.Clone(pwd),
+		readOnly: readOnly,
+	}, nil
+}
+
+// backendKeyStore is a KeyStore delegating the persistence of keys
+// to a KeyStoreBackend.
+type backendKeyStore struct {
+	backend  KeyStoreBackend
+	pwd      []byte
+	readOnly bool
+
+	m sync.Mutex
+}
+
+// ReadOnly returns true if this KeyStore is read only, false otherwise.
+// If ReadOnly is true then StoreKey will fail.
+func (ks *backendKeyStore) ReadOnly() bool {
+	return ks.readOnly
+}
+
+// GetKey returns a key object whose SKI is the one passed.
+func (ks *backendKeyStore) GetKey(ski []byte) (bccsp.Key, error) {
+	if len(ski) == 0 {
+		return nil, errors.New("Invalid SKI. Cannot be of zero length.")
+	}
+
+	ks.m.Lock()
+	defer ks.m.Unlock()
+
+	alias := hex.EncodeToString(ski)
+	aliases, err := ks.backend.List()
+	if err != nil {
+		return nil, fmt.Errorf("Failed listing keys [%s]", err)
+	}
+
+	for _, suffix := range []string{"sk", "pk", "key"} {
+		name := alias + "_" + suffix
+		if !containsAlias(aliases, name) {
+			continue
+		}
+		raw, err := ks.backend.Load(name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading key [%x] [%s]", ski, err)
+		}
+		return ks.decodeKey(suffix, raw)
+	}
+
+	return ks.searchForSKI(aliases, ski)
+}
+
+// StoreKey stores the key k in this KeyStore.
+// If this KeyStore is read only then the method will fail.
+func (ks *backendKeyStore) StoreKey(k bccsp.Key) error {
+	if ks.readOnly {
+		return errors.New("Read only KeyStore.")
+	}
+	if k == nil {
+		return errors.New("Invalid key. It must be different from nil.")
+	}
+
+	var (
+		raw    []byte
+		suffix string
+		err    error
+	)
+	switch kk := k.(type) {
+	case *ecdsaPrivateKey:
+		raw, err = utils.PrivateKeyToPEM(kk.privKey, ks.pwd)
+		suffix = "sk"
+	case *rsaPrivateKey:
+		raw, err = utils.PrivateKeyToPEM(kk.privKey, ks.pwd)
+		suffix = "sk"
+	case *ecdsaPublicKey:
+		raw, err = utils.PublicKeyToPEM(kk.pubKey, ks.pwd)
+		suffix = "pk"
+	case *rsaPublicKey:
+		raw, err = utils.PublicKeyToPEM(kk.pubKey, ks.pwd)
+		suffix = "pk"
+	case *aesPrivateKey:
+		raw, err = utils.AEStoEncryptedPEM(kk.privKey, ks.pwd)
+		suffix = "key"
+	default:
+		return fmt.Errorf("Key type not reconigned [%s]", k)
+	}
+	if err != nil {
+		return fmt.Errorf("Failed converting key to PEM [%s]", err)
+	}
+
+	ks.m.Lock()
+	defer ks.m.Unlock()
+
+	if err := ks.backend.Store(hex.EncodeToString(k.SKI())+"_"+suffix, raw); err != nil {
+		return fmt.Errorf("Failed storing key [%s]", err)
+	}
+	return nil
+}
+
+func (ks *backendKeyStore) decodeKey(suffix string, raw []byte) (bccsp.Key, error) {
+	switch suffix {
+	case "key":
+		key, err := utils.PEMtoAES(raw, ks.pwd)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing key [%s]", err)
+		}
+		return &aesPrivateKey{key, false}, nil
+	case "sk":
+		key, err := utils.PEMtoPrivateKey(raw, ks.pwd)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing secret key [%s]", err)
+		}
+		switch k := key.(type) {
+		case *ecdsa.PrivateKey:
+			return &ecdsaPrivateKey{k}, nil
+		case *rsa.PrivateKey:
+			return &rsaPrivateKey{k}, nil
+		default:
+			return nil, errors.New("Secret key type not recognized")
+		}
+	default:
+		key, err := utils.PEMtoPublicKey(raw, ks.pwd)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing public key [%s]", err)
+		}
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			return &ecdsaPublicKey{k}, nil
+		case *rsa.PublicKey:
+			return &rsaPublicKey{k}, nil
+		default:
+			return nil, errors.New("Public key type not recognized")
+		}
+	}
+}
+
+// searchForSKI looks for a private key whose SKI matches among the entries
+// not following the alias naming convention, e.g. keys imported externally.
+func (ks *backendKeyStore) searchForSKI(aliases []string, ski []byte) (bccsp.Key, error) {
+	for _, alias := range aliases {
+		raw, err := ks.backend.Load(alias)
+		if err != nil {
+			continue
+		}
+		k, err := ks.decodeKey("sk", raw)
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(k.SKI(), ski) {
+			return k, nil
+		}
+	}
+	return nil, errors.New("Key type not recognized")
+}
+
+func containsAlias(aliases []string, alias string) bool {
+	for _, a := range aliases {
+		if a == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// NewFileKeyStoreBackend returns a KeyStoreBackend storing each entry
+// in a separate file of the given folder.
+func NewFileKeyStoreBackend(path string) (KeyStoreBackend, error) {
+	if len(path) == 0 {
+		return nil, errors.New("An invalid KeyStore path provided. Path cannot be an empty string.")
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, fmt.Errorf("Failed creating KeyStore at [%s]: [%s]", path, err)
+	}
+	return &fileKeyStoreBackend{path: path}, nil
+}
+
+type fileKeyStoreBackend struct {
+	path string
+}
+
+func (b *fileKeyStoreBackend) Load(alias string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(b.path, alias))
+}
+
+func (b *fileKeyStoreBackend) Store(alias string, raw []byte) error {
+	if strings.ContainsRune(alias, filepath.Separator) {
+		return fmt.Errorf("Invalid alias [%s]", alias)
+	}
+	return ioutil.WriteFile(filepath.Join(b.path, alias), raw, 0700)
+}
+
+func (b *fileKeyStoreBackend) List() ([]string, error) {
+	files, err := ioutil.ReadDir(b.path)
+	if err != nil {
+		return nil, err
+	}
+	var aliases []string
+	for _, f := range files {
+		if !f.IsDir() {
+			aliases = append(aliases, f.Name())
+		}
+	}
+	return aliases, nil
+}
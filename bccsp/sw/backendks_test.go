@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sw
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/stretchr/testify/assert"
+)
+
+type xorKMSClient struct {
+	fail bool
+}
+
+func (c *xorKMSClient) Encrypt(plaintext []byte) ([]byte, error) {
+	if c.fail {
+		return nil, errors.New("kms unavailable")
+	}
+	out := make([]byte, len(plaintext))
+	for i, b := range plaintext {
+		out[i] = b ^ 0x5a
+	}
+	return out, nil
+}
+
+func (c *xorKMSClient) Decrypt(ciphertext []byte) ([]byte, error) {
+	return c.Encrypt(ciphertext)
+}
+
+func TestBackendKeyStoreFile(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "backendks")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	backend, err := NewFileKeyStoreBackend(tempDir)
+	assert.NoError(t, err)
+	ks, err := NewBackendKeyStore(nil, backend, false)
+	assert.NoError(t, err)
+	assert.False(t, ks.ReadOnly())
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	sk := &ecdsaPrivateKey{privKey}
+	pk := &ecdsaPublicKey{&privKey.PublicKey}
+	aesKey := &aesPrivateKey{[]byte("0123456789abcdef0123456789abcdef"), false}
+
+	for _, k := range []bccsp.Key{sk, pk, aesKey} {
+		assert.NoError(t, ks.StoreKey(k))
+	}
+
+	k, err := ks.GetKey(sk.SKI())
+	assert.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.Equal(t, sk.SKI(), k.SKI())
+
+	k, err = ks.GetKey(aesKey.SKI())
+	assert.NoError(t, err)
+	assert.Equal(t, aesKey.SKI(), k.SKI())
+
+	_, err = ks.GetKey([]byte{1, 2, 3})
+	assert.Error(t, err)
+	_, err = ks.GetKey(nil)
+	assert.Error(t, err)
+
+	ro, err := NewBackendKeyStore(nil, backend, true)
+	assert.NoError(t, err)
+	assert.Error(t, ro.StoreKey(sk))
+
+	_, err = NewBackendKeyStore(nil, nil, false)
+	assert.Error(t, err)
+}
+
+func TestBackendKeyStoreKMS(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "kmsks")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	fileBackend, err := NewFileKeyStoreBackend(tempDir)
+	assert.NoError(t, err)
+	backend, err := NewKMSKeyStoreBackend(&xorKMSClient{}, fileBackend)
+	assert.NoError(t, err)
+	ks, err := NewBackendKeyStore(nil, backend, false)
+	assert.NoError(t, err)
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	sk := &ecdsaPrivateKey{privKey}
+	assert.NoError(t, ks.StoreKey(sk))
+
+	// What lands on disk is not a plain PEM
+	aliases, err := fileBackend.List()
+	assert.NoError(t, err)
+	assert.Len(t, aliases, 1)
+	raw, err := fileBackend.Load(aliases[0])
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(string(raw), "PRIVATE KEY"))
+
+	k, err := ks.GetKey(sk.SKI())
+	assert.NoError(t, err)
+	assert.Equal(t, sk.SKI(), k.SKI())
+
+	failing, err := NewKMSKeyStoreBackend(&xorKMSClient{fail: true}, fileBackend)
+	assert.NoError(t, err)
+	ks, err = NewBackendKeyStore(nil, failing, false)
+	assert.NoError(t, err)
+	assert.Error(t, ks.StoreKey(sk))
+
+	_, err = NewKMSKeyStoreBackend(nil, fileBackend)
+	assert.Error(t, err)
+	_, err = NewKMSKeyStoreBackend(&xorKMSClient{}, nil)
+	assert.Error(t, err)
+}
@@ -16,10 +16,19 @@ limitations under the License.
 package sw
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestInvalidStoreKey(t *testing.T) {
@@ -64,3 +73,34 @@ func TestInvalidStoreKey(t *testing.T) {
 		t.Fatal("Error should be different from nil in this case")
 	}
 }
+
+func TestEncryptedPrivateKeyAtRest(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "bccspks")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	pwd := []byte("passphrase")
+	ks, err := NewFileBasedKeyStore(pwd, tempDir, false)
+	assert.NoError(t, err)
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	k := &ecdsaPrivateKey{privKey}
+	assert.NoError(t, ks.StoreKey(k))
+
+	raw, err := ioutil.ReadFile(filepath.Join(tempDir, hex.EncodeToString(k.SKI())+"_sk"))
+	assert.NoError(t, err)
+	block, _ := pem.Decode(raw)
+	assert.NotNil(t, block)
+	assert.Equal(t, utils.EncryptedPKCS8PEMType, block.Type)
+
+	loaded, err := ks.GetKey(k.SKI())
+	assert.NoError(t, err)
+	assert.Equal(t, k.SKI(), loaded.SKI())
+
+	// A keystore opened with the wrong passphrase cannot load the key
+	ks2, err := NewFileBasedKeyStore([]byte("wrong"), tempDir, false)
+	assert.NoError(t, err)
+	_, err = ks2.GetKey(k.SKI())
+	assert.Error(t, err)
+}
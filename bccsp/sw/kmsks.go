@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sw
+
+import (
+	"errors"
+	"fmt"
+)
+
+// KMSClient is implemented by clients of a key management service holding
+// the master key used to wrap the entries of a KeyStore.
+type KMSClient interface {
+	// Encrypt wraps the plaintext with the master key.
+	Encrypt(plaintext []byte) ([]byte, error)
+
+	// Decrypt unwraps a ciphertext previously returned by Encrypt.
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// NewKMSKeyStoreBackend returns a KeyStoreBackend that wraps every entry
+// with the KMS master key before handing it over to the underlying backend.
+func NewKMSKeyStoreBackend(client KMSClient, backend KeyStoreBackend) (KeyStoreBackend, error) {
+	if client == nil {
+		return nil, errors.New("Invalid KMS client. It must be different from nil.")
+	}
+	if backend == nil {
+		return nil, errors.New("Invalid KeyStore backend. It must be different from nil.")
+	}
+	return &kmsKeyStoreBackend{client: client, backend: backend}, nil
+}
+
+type kmsKeyStoreBackend struct {
+	client  KMSClient
+	backend KeyStoreBackend
+}
+
+func (b *kmsKeyStoreBackend) Load(alias string) ([]byte, error) {
+	wrapped, err := b.backend.Load(alias)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := b.client.Decrypt(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("Failed unwrapping key [%s] with KMS [%s]", alias, err)
+	}
+	return raw, nil
+}
+
+func (b *kmsKeyStoreBackend) Store(alias string, raw []byte) error {
+	wrapped, err := b.client.Encrypt(raw)
+	if err != nil {
+		return fmt.Errorf("Failed wrapping key [%s] with KMS [%s]", alias, err)
+	}
+	return b.backend.Store(alias, wrapped)
+}
+
+func (b *kmsKeyStoreBackend) List() ([]string, error) {
+	return b.backend.List()
+}
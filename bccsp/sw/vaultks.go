@@ -0,0 +1,231 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sw
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	vaultTokenHeader    = "X-Vault-Token"
+	vaultDefaultMount   = "secret"
+	vaultDefaultTimeout = 10 * time.Second
+)
+
+// VaultConfig describes how to reach the key-value (version 2) secrets
+// engine of a HashiCorp Vault server storing the keys.
+type VaultConfig struct {
+	// Address is the base URL of the Vault server, e.g. https://vault:8200
+	Address string
+	// Token is the Vault token used to authenticate requests
+	Token string
+	// Mount is the mount path of the KV secrets engine. Defaults to "secret"
+	Mount string
+	// Prefix is the path, under the mount, where the keys are stored
+	Prefix string
+	// TLS configures the connection to a Vault server reached over https.
+	// It is ignored when Client is set.
+	TLS VaultTLSConfig
+	// Client is the HTTP client used to reach Vault. If nil, a client
+	// with a default timeout is used.
+	Client *http.Client
+}
+
+// VaultTLSConfig holds the PEM files securing the connection to Vault.
+type VaultTLSConfig struct {
+	// RootCertFile holds the CA certificates the certificate of the Vault
+	// server is verified against. If empty, the system roots are used.
+	RootCertFile string
+	// ClientCert and ClientKey hold the certificate and private key the
+	// peer authenticates to Vault with, when Vault requires client
+	// certificates. Both or neither must be set.
+	ClientCert string
+	ClientKey  string
+}
+
+// NewVaultKeyStoreBackend returns a KeyStoreBackend storing each entry as
+// a secret of a HashiCorp Vault KV version 2 secrets engine.
+func NewVaultKeyStoreBackend(conf *VaultConfig) (KeyStoreBackend, error) {
+	if conf == nil || conf.Address == "" {
+		return nil, errors.New("Invalid Vault config. Address must be different from nil.")
+	}
+	if conf.Token == "" {
+		return nil, errors.New("Invalid Vault config. Token must be different from nil.")
+	}
+
+	b := &vaultKeyStoreBackend{
+		address: strings.TrimRight(conf.Address, "/"),
+		token:   conf.Token,
+		mount:   strings.Trim(conf.Mount, "/"),
+		prefix:  strings.Trim(conf.Prefix, "/"),
+		client:  conf.Client,
+	}
+	if b.mount == "" {
+		b.mount = vaultDefaultMount
+	}
+	if b.client == nil {
+		tlsConfig, err := conf.TLS.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		b.client = &http.Client{
+			Timeout:   vaultDefaultTimeout,
+			Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+		}
+	}
+	return b, nil
+}
+
+// tlsConfig returns the TLS configuration of the connections to Vault,
+// nil to use the defaults if no option is set.
+func (c *VaultTLSConfig) tlsConfig() (*tls.Config, error) {
+	if c.RootCertFile == "" && c.ClientCert == "" && c.ClientKey == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if c.RootCertFile != "" {
+		pem, err := ioutil.ReadFile(c.RootCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("Failed reading Vault root certificates [%s]", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("No certificate found in Vault root certificates file [%s]", c.RootCertFile)
+		}
+	}
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return nil, errors.New("Invalid Vault TLS config. ClientCert and ClientKey must be set together.")
+		}
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading Vault client certificate [%s]", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+type vaultKeyStoreBackend struct {
+	address string
+	token   string
+	mount   string
+	prefix  string
+	client  *http.Client
+}
+
+type vaultSecret struct {
+	Data struct {
+		Data map[string]string `json:"data"`
+		Keys []string          `json:"keys"`
+	} `json:"data"`
+}
+
+func (b *vaultKeyStoreBackend) Load(alias string) ([]byte, error) {
+	var secret vaultSecret
+	if err := b.do("GET", b.url("data", alias), nil, &secret); err != nil {
+		return nil, err
+	}
+	pem, ok := secret.Data.Data["pem"]
+	if !ok {
+		return nil, fmt.Errorf("Vault secret [%s] does not contain a key", alias)
+	}
+	return []byte(pem), nil
+}
+
+func (b *vaultKeyStoreBackend) Store(alias string, raw []byte) error {
+	body := map[string]interface{}{
+		"data": map[string]string{"pem": string(raw)},
+	}
+	return b.do("POST", b.url("data", alias), body, nil)
+}
+
+func (b *vaultKeyStoreBackend) List() ([]string, error) {
+	var secret vaultSecret
+	err := b.do("LIST", b.url("metadata", ""), nil, &secret)
+	if err == errVaultNotFound {
+		// Nothing stored yet
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return secret.Data.Keys, nil
+}
+
+func (b *vaultKeyStoreBackend) url(kind, alias string) string {
+	parts := []string{b.address, "v1", b.mount, kind}
+	if b.prefix != "" {
+		parts = append(parts, b.prefix)
+	}
+	if alias != "" {
+		parts = append(parts, alias)
+	}
+	return strings.Join(parts, "/")
+}
+
+var errVaultNotFound = errors.New("Vault secret not found")
+
+func (b *vaultKeyStoreBackend) do(method, url string, in interface{}, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(vaultTokenHeader, b.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Failed reaching Vault [%s]", err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("Failed reading Vault response [%s]", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return errVaultNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Vault request %s %s failed with status %d: %s", method, url, resp.StatusCode, payload)
+	}
+	if out == nil || len(payload) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(payload, out); err != nil {
+		return fmt.Errorf("Failed decoding Vault response [%s]", err)
+	}
+	return nil
+}
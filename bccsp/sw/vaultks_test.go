@@ -0,0 +1,185 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sw
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeVault emulates the subset of the Vault KV v2 API used by the backend
+type fakeVault struct {
+	sync.Mutex
+	secrets map[string]string
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.Lock()
+	defer v.Unlock()
+
+	if r.Header.Get(vaultTokenHeader) != "s3cr3t" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case r.Method == "LIST" && r.URL.Path == "/v1/secret/metadata/fabric":
+		if len(v.secrets) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var keys []string
+		for k := range v.secrets {
+			keys = append(keys, fmt.Sprintf("%q", k))
+		}
+		fmt.Fprintf(w, `{"data":{"keys":[%s]}}`, strings.Join(keys, ","))
+	case r.Method == "POST" && strings.HasPrefix(r.URL.Path, "/v1/secret/data/fabric/"):
+		var body struct {
+			Data map[string]string `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		v.secrets[strings.TrimPrefix(r.URL.Path, "/v1/secret/data/fabric/")] = body.Data["pem"]
+	case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/v1/secret/data/fabric/"):
+		pem, ok := v.secrets[strings.TrimPrefix(r.URL.Path, "/v1/secret/data/fabric/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		out, _ := json.Marshal(map[string]interface{}{"data": map[string]interface{}{"data": map[string]string{"pem": pem}}})
+		w.Write(out)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestVaultKeyStoreBackend(t *testing.T) {
+	server := httptest.NewServer(&fakeVault{secrets: map[string]string{}})
+	defer server.Close()
+
+	_, err := NewVaultKeyStoreBackend(nil)
+	assert.Error(t, err)
+	_, err = NewVaultKeyStoreBackend(&VaultConfig{Address: server.URL})
+	assert.Error(t, err)
+
+	backend, err := NewVaultKeyStoreBackend(&VaultConfig{Address: server.URL + "/", Token: "s3cr3t", Prefix: "fabric"})
+	assert.NoError(t, err)
+	ks, err := NewBackendKeyStore(nil, backend, false)
+	assert.NoError(t, err)
+
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	sk := &ecdsaPrivateKey{privKey}
+
+	_, err = ks.GetKey(sk.SKI())
+	assert.Error(t, err)
+
+	assert.NoError(t, ks.StoreKey(sk))
+	k, err := ks.GetKey(sk.SKI())
+	assert.NoError(t, err)
+	assert.Equal(t, sk.SKI(), k.SKI())
+
+	badToken, err := NewVaultKeyStoreBackend(&VaultConfig{Address: server.URL, Token: "wrong", Prefix: "fabric"})
+	assert.NoError(t, err)
+	_, err = badToken.List()
+	assert.Error(t, err)
+}
+
+func TestVaultKeyStoreBackendTLS(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "vaulttls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	// Self-signed client certificate, trusted by the server
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "peer0"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientCertDER, err := x509.CreateCertificate(rand.Reader, template, template, &clientKey.PublicKey, clientKey)
+	assert.NoError(t, err)
+	clientCert, err := x509.ParseCertificate(clientCertDER)
+	assert.NoError(t, err)
+	clientKeyDER, err := x509.MarshalECPrivateKey(clientKey)
+	assert.NoError(t, err)
+	clientCertFile := filepath.Join(tempDir, "client.pem")
+	clientKeyFile := filepath.Join(tempDir, "client.key")
+	assert.NoError(t, ioutil.WriteFile(clientCertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientCertDER}), 0600))
+	assert.NoError(t, ioutil.WriteFile(clientKeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: clientKeyDER}), 0600))
+
+	server := httptest.NewUnstartedServer(&fakeVault{secrets: map[string]string{}})
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	rootCertFile := filepath.Join(tempDir, "ca.pem")
+	assert.NoError(t, ioutil.WriteFile(rootCertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	conf := &VaultConfig{Address: server.URL, Token: "s3cr3t", Prefix: "fabric"}
+	list := func(conf *VaultConfig) error {
+		backend, err := NewVaultKeyStoreBackend(conf)
+		if err != nil {
+			return err
+		}
+		_, err = backend.List()
+		return err
+	}
+
+	// The certificate of the server is not trusted by default
+	assert.Error(t, list(conf))
+	// Nor is the client without its certificate
+	conf.TLS = VaultTLSConfig{RootCertFile: rootCertFile}
+	assert.Error(t, list(conf))
+	conf.TLS = VaultTLSConfig{RootCertFile: rootCertFile, ClientCert: clientCertFile, ClientKey: clientKeyFile}
+	assert.NoError(t, list(conf))
+
+	for _, tlsConf := range []VaultTLSConfig{
+		{RootCertFile: filepath.Join(tempDir, "missing.pem")},
+		{RootCertFile: clientKeyFile},
+		{RootCertFile: rootCertFile, ClientCert: clientCertFile},
+		{RootCertFile: rootCertFile, ClientCert: rootCertFile, ClientKey: clientKeyFile},
+	} {
+		_, err := NewVaultKeyStoreBackend(&VaultConfig{Address: server.URL, Token: "s3cr3t", TLS: tlsConf})
+		assert.Error(t, err, "TLS config %+v should be rejected", tlsConf)
+	}
+}
@@ -147,7 +147,8 @@ func PrivateKeyToPEM(privateKey interface{}, pwd []byte) ([]byte, error) {
 	}
 }
 
-// PrivateKeyToEncryptedPEM converts a private key to an encrypted PEM
+// PrivateKeyToEncryptedPEM converts a private key to an encrypted PEM.
+// The key is encoded as a PKCS#8 EncryptedPrivateKeyInfo.
 func PrivateKeyToEncryptedPEM(privateKey interface{}, pwd []byte) ([]byte, error) {
 	if privateKey == nil {
 		return nil, errors.New("Invalid private key. It must be different from nil.")
@@ -158,28 +159,25 @@ func PrivateKeyToEncryptedPEM(privateKey interface{}, pwd []byte) ([]byte, error
 		if k == nil {
 			return nil, errors.New("Invalid ecdsa private key. It must be different from nil.")
 		}
-		raw, err := x509.MarshalECPrivateKey(k)
-
-		if err != nil {
-			return nil, err
-		}
-
-		block, err := x509.EncryptPEMBlock(
-			rand.Reader,
-			"PRIVATE KEY",
-			raw,
-			pwd,
-			x509.PEMCipherAES256)
-
-		if err != nil {
-			return nil, err
+	case *rsa.PrivateKey:
+		if k == nil {
+			return nil, errors.New("Invalid rsa private key. It must be different from nil.")
 		}
-
-		return pem.EncodeToMemory(block), nil
-
 	default:
-		return nil, errors.New("Invalid key type. It must be *ecdsa.PrivateKey")
+		return nil, errors.New("Invalid key type. It must be *ecdsa.PrivateKey or *rsa.PrivateKey")
 	}
+
+	raw, err := PrivateKeyToEncryptedPKCS8(privateKey, pwd)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(
+		&pem.Block{
+			Type:  EncryptedPKCS8PEMType,
+			Bytes: raw,
+		},
+	), nil
 }
 
 // DERToPrivateKey unmarshals a der to private key
@@ -217,6 +215,10 @@ func PEMtoPrivateKey(raw []byte, pwd []byte) (interface{}, error) {
 
 	// TODO: derive from header the type of the key
 
+	if block.Type == EncryptedPKCS8PEMType {
+		return EncryptedPKCS8ToPrivateKey(block.Bytes, pwd)
+	}
+
 	if x509.IsEncryptedPEMBlock(block) {
 		if len(pwd) == 0 {
 			return nil, errors.New("Encrypted Key. Need a password")
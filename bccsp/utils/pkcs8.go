@@ -0,0 +1,255 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// EncryptedPKCS8PEMType is the PEM block type of a PKCS#8 EncryptedPrivateKeyInfo
+const EncryptedPKCS8PEMType = "ENCRYPTED PRIVATE KEY"
+
+const (
+	pkcs8SaltSize       = 16
+	pkcs8IterationCount = 10000
+	pkcs8KeySize        = 32
+	// pkcs8MaxIterationCount bounds the work a crafted key file can cause
+	pkcs8MaxIterationCount = 10000000
+)
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// encryptedPrivateKeyInfo is the PKCS#8 (RFC 5208) EncryptedPrivateKeyInfo structure
+type encryptedPrivateKeyInfo struct {
+	EncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedData       []byte
+}
+
+// pbes2Params is the PKCS#5 (RFC 8018) PBES2-params structure
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params is the PKCS#5 (RFC 8018) PBKDF2-params structure
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// PrivateKeyToEncryptedPKCS8 marshals a private key to a PKCS#8 EncryptedPrivateKeyInfo
+// using PBES2 with PBKDF2-HMAC-SHA256 and AES-256-CBC.
+func PrivateKeyToEncryptedPKCS8(privateKey interface{}, pwd []byte) ([]byte, error) {
+	if len(pwd) == 0 {
+		return nil, errors.New("Invalid password. It must be different from nil.")
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed marshalling private key to PKCS#8 [%s]", err)
+	}
+
+	salt := make([]byte, pkcs8SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("Failed generating salt [%s]", err)
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("Failed generating IV [%s]", err)
+	}
+
+	key := pbkdf2Key(pwd, salt, pkcs8IterationCount, pkcs8KeySize, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	padded := pkcs7Pad(der, aes.BlockSize)
+	encrypted := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, padded)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: pkcs8IterationCount,
+		KeyLength:      pkcs8KeySize,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return nil, err
+	}
+	rawIV, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+	schemeParams, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: rawIV}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		EncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: schemeParams}},
+		EncryptedData:       encrypted,
+	})
+}
+
+// EncryptedPKCS8ToPrivateKey decrypts a PKCS#8 EncryptedPrivateKeyInfo
+// and unmarshals the private key it contains.
+func EncryptedPKCS8ToPrivateKey(der []byte, pwd []byte) (interface{}, error) {
+	if len(pwd) == 0 {
+		return nil, errors.New("Encrypted Key. Need a password")
+	}
+
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("Failed unmarshalling EncryptedPrivateKeyInfo [%s]", err)
+	}
+	if !info.EncryptionAlgorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("Unsupported encryption algorithm [%s]", info.EncryptionAlgorithm.Algorithm)
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.EncryptionAlgorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("Failed unmarshalling PBES2 parameters [%s]", err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("Unsupported key derivation function [%s]", params.KeyDerivationFunc.Algorithm)
+	}
+	if !params.EncryptionScheme.Algorithm.Equal(oidAES256CBC) {
+		return nil, fmt.Errorf("Unsupported encryption scheme [%s]", params.EncryptionScheme.Algorithm)
+	}
+
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		return nil, fmt.Errorf("Failed unmarshalling PBKDF2 parameters [%s]", err)
+	}
+	if kdf.IterationCount < 1 || kdf.IterationCount > pkcs8MaxIterationCount {
+		return nil, fmt.Errorf("Invalid PBKDF2 iteration count [%d]", kdf.IterationCount)
+	}
+	// The key length is optional, AES-256 takes a 32 bytes key
+	if kdf.KeyLength != 0 && kdf.KeyLength != pkcs8KeySize {
+		return nil, fmt.Errorf("Unsupported PBKDF2 key length [%d]", kdf.KeyLength)
+	}
+	var prf func() hash.Hash
+	switch {
+	case len(kdf.PRF.Algorithm) == 0 || kdf.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case kdf.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	default:
+		return nil, fmt.Errorf("Unsupported PBKDF2 pseudorandom function [%s]", kdf.PRF.Algorithm)
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, fmt.Errorf("Failed unmarshalling IV [%s]", err)
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, errors.New("Invalid IV length")
+	}
+	if len(info.EncryptedData) == 0 || len(info.EncryptedData)%aes.BlockSize != 0 {
+		return nil, errors.New("Invalid encrypted data length")
+	}
+
+	key := pbkdf2Key(pwd, kdf.Salt, kdf.IterationCount, pkcs8KeySize, prf)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	decrypted := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, info.EncryptedData)
+
+	decrypted, err = pkcs7Unpad(decrypted, aes.BlockSize)
+	if err != nil {
+		return nil, errors.New("Failed PEM decryption. Wrong password?")
+	}
+
+	return DERToPrivateKey(decrypted)
+}
+
+// pbkdf2Key derives a key from the password and salt as defined in RFC 8018, Section 5.2
+func pbkdf2Key(password, salt []byte, iter, keyLen int, h func() hash.Hash) []byte {
+	prf := hmac.New(h, password)
+	hashLen := prf.Size()
+	numBlocks := (keyLen + hashLen - 1) / hashLen
+
+	var buf [4]byte
+	dk := make([]byte, 0, numBlocks*hashLen)
+	u := make([]byte, hashLen)
+	for block := 1; block <= numBlocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(buf[:], uint32(block))
+		prf.Write(buf[:4])
+		dk = prf.Sum(dk)
+		t := dk[len(dk)-hashLen:]
+		copy(u, t)
+
+		for n := 2; n <= iter; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = u[:0]
+			u = prf.Sum(u)
+			for x := range u {
+				t[x] ^= u[x]
+			}
+		}
+	}
+	return dk[:keyLen]
+}
+
+func pkcs7Unpad(src []byte, blockSize int) ([]byte, error) {
+	length := len(src)
+	if length == 0 || length%blockSize != 0 {
+		return nil, errors.New("Invalid padding")
+	}
+	unpadding := int(src[length-1])
+	if unpadding == 0 || unpadding > blockSize {
+		return nil, errors.New("Invalid padding")
+	}
+	if !bytes.Equal(src[length-unpadding:], bytes.Repeat([]byte{byte(unpadding)}, unpadding)) {
+		return nil, errors.New("Invalid padding")
+	}
+	return src[:length-unpadding], nil
+}
+
+func pkcs7Pad(src []byte, blockSize int) []byte {
+	padding := blockSize - len(src)%blockSize
+	return append(src, bytes.Repeat([]byte{byte(padding)}, padding)...)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/asn1"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPBKDF2Vector(t *testing.T) {
+	// RFC 6070 test vector
+	dk := pbkdf2Key([]byte("password"), []byte("salt"), 2, 20, sha1.New)
+	assert.Equal(t, "ea6c014dc72d6f8ccd1ed92ace1d41f0d8de8957", hex.EncodeToString(dk))
+}
+
+func TestEncryptedPKCS8RoundTrip(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+
+	for _, k := range []interface{}{ecKey, rsaKey} {
+		raw, err := PrivateKeyToEncryptedPEM(k, []byte("passwd"))
+		assert.NoError(t, err)
+
+		_, err = PEMtoPrivateKey(raw, nil)
+		assert.Error(t, err)
+		_, err = PEMtoPrivateKey(raw, []byte("wrong"))
+		assert.Error(t, err)
+
+		decoded, err := PEMtoPrivateKey(raw, []byte("passwd"))
+		assert.NoError(t, err)
+		assert.Equal(t, k, decoded)
+	}
+}
+
+func TestPrivateKeyToEncryptedPKCS8NoPassword(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	_, err = PrivateKeyToEncryptedPKCS8(ecKey, nil)
+	assert.Error(t, err)
+}
+
+func TestEncryptedPKCS8KDFParams(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := PrivateKeyToEncryptedPKCS8(ecKey, []byte("passwd"))
+	assert.NoError(t, err)
+
+	// withKDF re-encodes der with the PBKDF2 parameters changed by update
+	withKDF := func(update func(*pbkdf2Params)) []byte {
+		var info encryptedPrivateKeyInfo
+		_, err := asn1.Unmarshal(der, &info)
+		assert.NoError(t, err)
+		var params pbes2Params
+		_, err = asn1.Unmarshal(info.EncryptionAlgorithm.Parameters.FullBytes, &params)
+		assert.NoError(t, err)
+		var kdf pbkdf2Params
+		_, err = asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf)
+		assert.NoError(t, err)
+
+		update(&kdf)
+		kdfParams, err := asn1.Marshal(kdf)
+		assert.NoError(t, err)
+		params.KeyDerivationFunc.Parameters = asn1.RawValue{FullBytes: kdfParams}
+		schemeParams, err := asn1.Marshal(params)
+		assert.NoError(t, err)
+		info.EncryptionAlgorithm.Parameters = asn1.RawValue{FullBytes: schemeParams}
+		raw, err := asn1.Marshal(info)
+		assert.NoError(t, err)
+		return raw
+	}
+
+	_, err = EncryptedPKCS8ToPrivateKey(withKDF(func(*pbkdf2Params) {}), []byte("passwd"))
+	assert.NoError(t, err)
+	// Without the optional key length
+	_, err = EncryptedPKCS8ToPrivateKey(withKDF(func(kdf *pbkdf2Params) { kdf.KeyLength = 0 }), []byte("passwd"))
+	assert.NoError(t, err)
+
+	for _, update := range []func(*pbkdf2Params){
+		func(kdf *pbkdf2Params) { kdf.IterationCount = 0 },
+		func(kdf *pbkdf2Params) { kdf.IterationCount = -1 },
+		func(kdf *pbkdf2Params) { kdf.IterationCount = pkcs8MaxIterationCount + 1 },
+		func(kdf *pbkdf2Params) { kdf.KeyLength = 16 },
+	} {
+		_, err = EncryptedPKCS8ToPrivateKey(withKDF(update), []byte("passwd"))
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cauthdsl
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"runtime"
+	"sync"
+
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// defaultVerificationCacheSize is the number of signature verification
+// outcomes remembered by the shared cache
+const defaultVerificationCacheSize = 10000
+
+// verificationWorkers is the number of goroutines verifying the signatures
+// of a signature set concurrently
+var verificationWorkers = runtime.NumCPU()
+
+// sigCache is shared by all the policies: the outcome of a signature
+// verification only depends on the serialized identity, the data
+// and the signature, so it does not matter which deserializer produced it.
+var sigCache = newVerificationCache(defaultVerificationCacheSize)
+
+// verificationCache remembers the outcome of signature verifications,
+// keyed by a hash of (identity, data hash, signature). Once full, the
+// oldest entries are evicted first.
+type verificationCache struct {
+	sync.RWMutex
+	size    int
+	entries map[string]error
+	order   []string
+}
+
+func newVerificationCache(size int) *verificationCache {
+	return &verificationCache{
+		size:    size,
+		entries: make(map[string]error),
+	}
+}
+
+// verificationKey hashes the identity, the hash of the data and the
+// signature, each length-prefixed so that no two triples share a key
+func verificationKey(identity, data, signature []byte) string {
+	dataHash := sha256.Sum256(data)
+	h := sha256.New()
+	var length [4]byte
+	for _, field := range [][]byte{identity, dataHash[:], signature} {
+		binary.BigEndian.PutUint32(length[:], uint32(len(field)))
+		h.Write(length[:])
+		h.Write(field)
+	}
+	return string(h.Sum(nil))
+}
+
+func (c *verificationCache) get(key string) (error, bool) {
+	c.RLock()
+	defer c.RUnlock()
+	err, ok := c.entries[key]
+	return err, ok
+}
+
+func (c *verificationCache) put(key string, err error) {
+	c.Lock()
+	defer c.Unlock()
+	if _, exists := c.entries[key]; exists {
+		return
+	}
+	if len(c.order) >= c.size {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	c.entries[key] = err
+	c.order = append(c.order, key)
+}
+
+// cachingDeserializer wraps an IdentityDeserializer so that the returned
+// identities consult the verification cache before verifying signatures
+type cachingDeserializer struct {
+	msp.IdentityDeserializer
+	cache *verificationCache
+}
+
+func (d *cachingDeserializer) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	id, err := d.IdentityDeserializer.DeserializeIdentity(serializedIdentity)
+	if err != nil {
+		return nil, err
+	}
+	return &cachingIdentity{Identity: id, serialized: serializedIdentity, cache: d.cache}, nil
+}
+
+type cachingIdentity struct {
+	msp.Identity
+	serialized []byte
+	cache      *verificationCache
+}
+
+func (id *cachingIdentity) Verify(msg []byte, sig []byte) error {
+	key := verificationKey(id.serialized, msg, sig)
+	if err, ok := id.cache.get(key); ok {
+		return err
+	}
+	err := id.Identity.Verify(msg, sig)
+	id.cache.put(key, err)
+	return err
+}
+
+// verifyBatch verifies the signatures of the signature set concurrently,
+// populating the verification cache consulted by the policy evaluation.
+// Signatures whose identity cannot be deserialized are left to the
+// evaluator, which reports them.
+func verifyBatch(signatureSet []*cb.SignedData, deserializer msp.IdentityDeserializer) {
+	workers := verificationWorkers
+	if workers > len(signatureSet) {
+		workers = len(signatureSet)
+	}
+	if workers < 2 {
+		// Nothing to gain, the evaluator verifies the signature itself
+		return
+	}
+
+	work := make(chan *cb.SignedData, len(signatureSet))
+	for _, sd := range signatureSet {
+		work <- sd
+	}
+	close(work)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for sd := range work {
+				identity, err := deserializer.DeserializeIdentity(sd.Identity)
+				if err != nil {
+					continue
+				}
+				identity.Verify(sd.Data, sd.Signature)
+			}
+		}()
+	}
+	wg.Wait()
+}
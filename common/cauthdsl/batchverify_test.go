@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cauthdsl
+
+import (
+	"crypto/sha256"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingIdentity struct {
+	mockIdentity
+	verifications *int32
+}
+
+func (id *countingIdentity) Verify(msg []byte, sig []byte) error {
+	atomic.AddInt32(id.verifications, 1)
+	return id.mockIdentity.Verify(msg, sig)
+}
+
+type countingDeserializer struct {
+	verifications int32
+}
+
+func (d *countingDeserializer) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	if string(serializedIdentity) == "garbage" {
+		return nil, errors.New("cannot deserialize")
+	}
+	return &countingIdentity{mockIdentity: mockIdentity{idBytes: serializedIdentity}, verifications: &d.verifications}, nil
+}
+
+func TestVerificationCacheEviction(t *testing.T) {
+	c := newVerificationCache(2)
+	c.put("a", nil)
+	c.put("b", errors.New("bad"))
+	c.put("b", nil)
+
+	err, ok := c.get("b")
+	assert.True(t, ok)
+	assert.Error(t, err, "the first outcome stored is kept")
+
+	c.put("c", nil)
+	_, ok = c.get("a")
+	assert.False(t, ok, "the oldest entry should have been evicted")
+	_, ok = c.get("c")
+	assert.True(t, ok)
+}
+
+func TestVerificationKeyIsUnambiguous(t *testing.T) {
+	assert.NotEqual(t, verificationKey([]byte("ab"), nil, []byte("c")), verificationKey([]byte("a"), nil, []byte("bc")))
+	assert.NotEqual(t, verificationKey([]byte("a"), []byte("x"), []byte("c")), verificationKey([]byte("a"), []byte("y"), []byte("c")))
+
+	// Separators within the fields don't shift them into one another
+	data := []byte("data")
+	dataHash := sha256.Sum256(data)
+	identity := append([]byte("a\x00"), dataHash[:]...)
+	signature := append(append(dataHash[:], 0), 's')
+	assert.NotEqual(t, verificationKey([]byte("a"), data, signature), verificationKey(identity, data, []byte("s")))
+}
+
+func TestBatchVerificationUsesCache(t *testing.T) {
+	defer func(workers int) { verificationWorkers = workers }(verificationWorkers)
+	verificationWorkers = 4
+	defer func(c *verificationCache) { sigCache = c }(sigCache)
+	sigCache = newVerificationCache(defaultVerificationCacheSize)
+
+	deserializer := &countingDeserializer{}
+	envelope := Envelope(And(SignedBy(0), SignedBy(1)), signers)
+	policyBytes, _ := proto.Marshal(envelope)
+	policy, _, err := NewPolicyProvider(deserializer).NewPolicy(policyBytes)
+	assert.NoError(t, err)
+
+	signatureSet := []*cb.SignedData{
+		{Identity: []byte("garbage"), Data: []byte("data"), Signature: validSignature},
+		{Identity: signers[0], Data: []byte("data"), Signature: validSignature},
+		{Identity: signers[1], Data: []byte("data"), Signature: validSignature},
+	}
+	assert.NoError(t, policy.Evaluate(signatureSet))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&deserializer.verifications), "each signature should be verified once")
+
+	// Evaluating the same signatures again hits the cache
+	assert.NoError(t, policy.Evaluate(signatureSet))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&deserializer.verifications))
+
+	// Failed verifications are cached as well
+	signatureSet[2].Signature = invalidSignature
+	assert.Error(t, policy.Evaluate(signatureSet))
+	assert.Error(t, policy.Evaluate(signatureSet))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&deserializer.verifications))
+}
@@ -139,6 +139,35 @@ func SignedByAnyMember(ids []string) []byte {
 	return utils.MarshalOrPanic(p)
 }
 
+// SignedByNMembers returns a policy that requires n valid
+// signatures from members of any of the orgs whose ids are
+// listed in the supplied string array, each taken from a
+// distinct signature of the signature set
+func SignedByNMembers(n int32, ids []string) *cb.SignaturePolicyEnvelope {
+	sorted := make([]string, len(ids))
+	copy(sorted, ids)
+	sort.Strings(sorted)
+
+	// every principal may provide all n signatures, the policy
+	// is satisfied by any n of them
+	principals := make([]*msp.MSPPrincipal, len(sorted))
+	var sigspolicy []*cb.SignaturePolicy
+	for i, id := range sorted {
+		principals[i] = &msp.MSPPrincipal{
+			PrincipalClassification: msp.MSPPrincipal_ROLE,
+			Principal:               utils.MarshalOrPanic(&msp.MSPRole{Role: msp.MSPRole_MEMBER, MspIdentifier: id})}
+		for j := int32(0); j < n; j++ {
+			sigspolicy = append(sigspolicy, SignedBy(int32(i)))
+		}
+	}
+
+	return &cb.SignaturePolicyEnvelope{
+		Version:    0,
+		Policy:     NOutOf(n, sigspolicy),
+		Identities: principals,
+	}
+}
+
 // And is a convenience method which utilizes NOutOf to produce And equivalent behavior
 func And(lhs, rhs *cb.SignaturePolicy) *cb.SignaturePolicy {
 	return NOutOf(2, []*cb.SignaturePolicy{lhs, rhs})
@@ -160,3 +189,58 @@ func NOutOf(n int32, policies []*cb.SignaturePolicy) *cb.SignaturePolicy {
 		},
 	}
 }
+
+// Member returns the principal satisfied by any member of the specified MSP
+func Member(mspId string) *msp.MSPPrincipal {
+	return rolePrincipal(mspId, msp.MSPRole_MEMBER)
+}
+
+// Admin returns the principal satisfied by any admin of the specified MSP
+func Admin(mspId string) *msp.MSPPrincipal {
+	return rolePrincipal(mspId, msp.MSPRole_ADMIN)
+}
+
+func rolePrincipal(mspId string, role msp.MSPRole_MSPRoleType) *msp.MSPPrincipal {
+	return &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ROLE,
+		Principal:               utils.MarshalOrPanic(&msp.MSPRole{Role: role, MspIdentifier: mspId})}
+}
+
+// EnvelopeBuilder builds a SignaturePolicyEnvelope out of the principals
+// required by its policy, keeping track of their indexes in the envelope.
+// The policy itself is composed with And, Or and NOutOf, e.g.
+//
+//	b := NewEnvelopeBuilder()
+//	env := b.Envelope(And(b.SignedBy(Member("Org1MSP")), Or(b.SignedBy(Admin("Org2MSP")), b.SignedBy(Admin("Org3MSP")))))
+type EnvelopeBuilder struct {
+	principals []*msp.MSPPrincipal
+}
+
+// NewEnvelopeBuilder creates an EnvelopeBuilder without principals
+func NewEnvelopeBuilder() *EnvelopeBuilder {
+	return &EnvelopeBuilder{}
+}
+
+// SignedBy creates a SignaturePolicy requiring a signature of the principal,
+// which is added to the identities of the envelope unless it already is
+func (b *EnvelopeBuilder) SignedBy(principal *msp.MSPPrincipal) *cb.SignaturePolicy {
+	for i, p := range b.principals {
+		if proto.Equal(p, principal) {
+			return SignedBy(int32(i))
+		}
+	}
+	b.principals = append(b.principals, principal)
+	return SignedBy(int32(len(b.principals) - 1))
+}
+
+// Envelope returns the envelope embedding the policy, with the principals
+// the policy was built with
+func (b *EnvelopeBuilder) Envelope(policy *cb.SignaturePolicy) *cb.SignaturePolicyEnvelope {
+	principals := make([]*msp.MSPPrincipal, len(b.principals))
+	copy(principals, b.principals)
+	return &cb.SignaturePolicyEnvelope{
+		Version:    0,
+		Policy:     policy,
+		Identities: principals,
+	}
+}
@@ -144,6 +144,31 @@ func TestMultipleSignature(t *testing.T) {
 	}
 }
 
+func TestSignedByNMembers(t *testing.T) {
+	policy := SignedByNMembers(2, []string{"Org2", "Org1"})
+
+	spe, err := compile(policy.Policy, policy.Identities, &mockDeserializer{})
+	if err != nil {
+		t.Fatalf("Could not create a new SignaturePolicyEvaluator using the given policy, crypto-helper: %s", err)
+	}
+
+	org1, _ := proto.Marshal(&mb.MSPRole{Role: mb.MSPRole_MEMBER, MspIdentifier: "Org1"})
+	org2, _ := proto.Marshal(&mb.MSPRole{Role: mb.MSPRole_MEMBER, MspIdentifier: "Org2"})
+
+	if spe(toSignedData([][]byte{nil}, [][]byte{org1}, [][]byte{validSignature})) {
+		t.Errorf("Expected authentication to fail with a single signature")
+	}
+	if !spe(toSignedData([][]byte{nil, nil}, [][]byte{org1, org1}, [][]byte{validSignature, validSignature})) {
+		t.Errorf("Expected authentication to succeed with two signatures of members of the same org")
+	}
+	if !spe(toSignedData([][]byte{nil, nil}, [][]byte{org1, org2}, [][]byte{validSignature, validSignature})) {
+		t.Errorf("Expected authentication to succeed with two signatures of members of different orgs")
+	}
+	if spe(toSignedData([][]byte{nil, nil}, [][]byte{org1, org2}, [][]byte{validSignature, invalidSignature})) {
+		t.Errorf("Expected authentication to fail given one of two invalid signatures")
+	}
+}
+
 func TestComplexNestedSignature(t *testing.T) {
 	policy := Envelope(And(Or(And(SignedBy(0), SignedBy(1)), And(SignedBy(0), SignedBy(0))), SignedBy(0)), signers)
 
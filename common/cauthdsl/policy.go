@@ -49,19 +49,22 @@ func (pr *provider) NewPolicy(data []byte) (policies.Policy, proto.Message, erro
 		return nil, nil, fmt.Errorf("This evaluator only understands messages of version 0, but version was %d", sigPolicy.Version)
 	}
 
-	compiled, err := compile(sigPolicy.Policy, sigPolicy.Identities, pr.deserializer)
+	deserializer := &cachingDeserializer{IdentityDeserializer: pr.deserializer, cache: sigCache}
+	compiled, err := compile(sigPolicy.Policy, sigPolicy.Identities, deserializer)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	return &policy{
-		evaluator: compiled,
+		evaluator:    compiled,
+		deserializer: deserializer,
 	}, sigPolicy, nil
 
 }
 
 type policy struct {
-	evaluator func([]*cb.SignedData, []bool) bool
+	evaluator    func([]*cb.SignedData, []bool) bool
+	deserializer msp.IdentityDeserializer
 }
 
 // Evaluate takes a set of SignedData and evaluates whether this set of signatures satisfies the policy
@@ -70,6 +73,12 @@ func (p *policy) Evaluate(signatureSet []*cb.SignedData) error {
 		return fmt.Errorf("No such policy")
 	}
 
+	if p.deserializer != nil {
+		// Verify the signatures up front and concurrently, the evaluator
+		// then picks the outcomes up from the verification cache
+		verifyBatch(signatureSet, p.deserializer)
+	}
+
 	ok := p.evaluator(signatureSet, make([]bool, len(signatureSet)))
 	if !ok {
 		return errors.New("Failed to authenticate policy")
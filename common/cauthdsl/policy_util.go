@@ -32,3 +32,16 @@ func TemplatePolicy(key string, sigPolicyEnv *cb.SignaturePolicyEnvelope) *cb.Co
 	}
 	return configGroup
 }
+
+// TemplatePolicyWithPath creates a headerless configuration item representing a policy for a given key
+// in the group at the given path
+func TemplatePolicyWithPath(path []string, key string, sigPolicyEnv *cb.SignaturePolicyEnvelope) *cb.ConfigGroup {
+	root := cb.NewConfigGroup()
+	group := root
+	for _, element := range path {
+		group.Groups[element] = cb.NewConfigGroup()
+		group = group.Groups[element]
+	}
+	group.Policies[key] = TemplatePolicy(key, sigPolicyEnv).Policies[key]
+	return root
+}
@@ -68,6 +68,33 @@ func or(args ...interface{}) (interface{}, error) {
 	return toret + ")", nil
 }
 
+func outof(args ...interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("Expected at least 2 arguments, got %d", len(args))
+	}
+	n, ok := args[0].(float64)
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type %s, expected a number", reflect.TypeOf(args[0]))
+	}
+
+	toret := "outof(" + strconv.Itoa(int(n))
+	for _, arg := range args[1:] {
+		toret += ", "
+		switch t := arg.(type) {
+		case string:
+			if regex.MatchString(t) {
+				toret += "'" + t + "'"
+			} else {
+				toret += t
+			}
+		default:
+			return nil, fmt.Errorf("Unexpected type %s", reflect.TypeOf(arg))
+		}
+	}
+
+	return toret + ")", nil
+}
+
 func firstPass(args ...interface{}) (interface{}, error) {
 	toret := "outof(ID"
 	for _, arg := range args {
@@ -191,9 +218,14 @@ func newContext() *context {
 //
 // GATE(P[, P])
 //
+// or
+//
+// OutOf(N, P[, P])
+//
 // where
 //	- GATE is either "and" or "or"
-//	- P is either a principal or another nested call to GATE
+//	- N is the number of P that have to be satisfied
+//	- P is either a principal or another nested call to GATE or OutOf
 //
 // a principal is defined as
 //
@@ -204,7 +236,7 @@ func newContext() *context {
 //	- ROLE is either the string "member" or the string "admin" representing the required role
 func FromString(policy string) (*common.SignaturePolicyEnvelope, error) {
 	// first we translate the and/or business into outof gates
-	intermediate, err := govaluate.NewEvaluableExpressionWithFunctions(policy, map[string]govaluate.ExpressionFunction{"AND": and, "and": and, "OR": or, "or": or})
+	intermediate, err := govaluate.NewEvaluableExpressionWithFunctions(policy, map[string]govaluate.ExpressionFunction{"AND": and, "and": and, "OR": or, "or": or, "OutOf": outof, "outof": outof})
 	if err != nil {
 		return nil, err
 	}
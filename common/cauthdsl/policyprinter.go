@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cauthdsl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+)
+
+// PolicyString returns the human-readable representation of a signature
+// policy, in the language parsed by FromString, e.g.
+//
+//	AND('Org1MSP.member', OR('Org2MSP.admin', 'Org3MSP.admin'))
+//
+// A gate requiring all of its policies is printed as AND, one requiring one
+// of them as OR, and the others as OutOf(N, P[, P]). Only the principals
+// identifying the members or the admins of an MSP can be represented.
+func PolicyString(envelope *common.SignaturePolicyEnvelope) (string, error) {
+	if envelope == nil || envelope.Policy == nil {
+		return "", fmt.Errorf("Empty policy")
+	}
+
+	principals := make([]string, len(envelope.Identities))
+	for i, principal := range envelope.Identities {
+		p, err := principalString(principal)
+		if err != nil {
+			return "", err
+		}
+		principals[i] = p
+	}
+
+	return policyString(envelope.Policy, principals)
+}
+
+func principalString(principal *msp.MSPPrincipal) (string, error) {
+	if principal.PrincipalClassification != msp.MSPPrincipal_ROLE {
+		return "", fmt.Errorf("Principal of type %s cannot be represented", principal.PrincipalClassification)
+	}
+
+	role := &msp.MSPRole{}
+	if err := proto.Unmarshal(principal.Principal, role); err != nil {
+		return "", fmt.Errorf("Invalid role principal: %s", err)
+	}
+
+	switch role.Role {
+	case msp.MSPRole_MEMBER:
+		return "'" + role.MspIdentifier + ".member'", nil
+	case msp.MSPRole_ADMIN:
+		return "'" + role.MspIdentifier + ".admin'", nil
+	default:
+		return "", fmt.Errorf("Role %s cannot be represented", role.Role)
+	}
+}
+
+func policyString(policy *common.SignaturePolicy, principals []string) (string, error) {
+	switch t := policy.Type.(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(principals) {
+			return "", fmt.Errorf("Identity index out of range, requested %d, but identities length is %d", t.SignedBy, len(principals))
+		}
+		return principals[t.SignedBy], nil
+	case *common.SignaturePolicy_NOutOf_:
+		rules := make([]string, len(t.NOutOf.Policies))
+		for i, p := range t.NOutOf.Policies {
+			rule, err := policyString(p, principals)
+			if err != nil {
+				return "", err
+			}
+			rules[i] = rule
+		}
+
+		switch {
+		case len(rules) == 0:
+			return fmt.Sprintf("OutOf(%d)", t.NOutOf.N), nil
+		case int(t.NOutOf.N) == len(rules):
+			return "AND(" + strings.Join(rules, ", ") + ")", nil
+		case t.NOutOf.N == 1:
+			return "OR(" + strings.Join(rules, ", ") + ")", nil
+		default:
+			return fmt.Sprintf("OutOf(%d, %s)", t.NOutOf.N, strings.Join(rules, ", ")), nil
+		}
+	default:
+		return "", fmt.Errorf("Unknown type: %T:%v", t, t)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cauthdsl
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvelopeBuilder(t *testing.T) {
+	b := NewEnvelopeBuilder()
+	env := b.Envelope(And(b.SignedBy(Member("A")), Or(b.SignedBy(Admin("B")), b.SignedBy(Member("A")))))
+
+	assert.Equal(t, []*msp.MSPPrincipal{Member("A"), Admin("B")}, env.Identities, "Should have deduplicated the principals")
+	assert.Equal(t, And(SignedBy(0), Or(SignedBy(1), SignedBy(0))), env.Policy)
+}
+
+func TestPolicyString(t *testing.T) {
+	for _, policy := range []string{
+		"AND('A.member', 'B.member')",
+		"OR('A.member', AND('B.admin', 'C.member'))",
+		"OutOf(2, 'A.member', 'B.member', 'C.admin')",
+		"AND('A.member')",
+	} {
+		env, err := FromString(policy)
+		assert.NoError(t, err)
+		s, err := PolicyString(env)
+		assert.NoError(t, err)
+		assert.Equal(t, policy, s)
+	}
+
+	s, err := PolicyString(SignedByNMembers(2, []string{"A", "B"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "OutOf(2, 'A.member', 'A.member', 'B.member', 'B.member')", s)
+
+	s, err = PolicyString(AcceptAllPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, "OutOf(0)", s)
+
+	s, err = PolicyString(RejectAllPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, "OutOf(1)", s)
+}
+
+func TestPolicyStringErrors(t *testing.T) {
+	_, err := PolicyString(nil)
+	assert.Error(t, err)
+
+	_, err = PolicyString(Envelope(SignedBy(0), [][]byte{[]byte("identity")}))
+	assert.Error(t, err, "Identity principals cannot be represented")
+
+	_, err = PolicyString(&common.SignaturePolicyEnvelope{Policy: SignedBy(1), Identities: []*msp.MSPPrincipal{Member("A")}})
+	assert.Error(t, err, "Out of range identity index")
+}
+
+func TestOutOf(t *testing.T) {
+	p1, err := FromString("OutOf(1, 'A.member', AND('B.member', 'C.member'))")
+	assert.NoError(t, err)
+
+	b := NewEnvelopeBuilder()
+	p2 := b.Envelope(NOutOf(1, []*common.SignaturePolicy{b.SignedBy(Member("A")), And(b.SignedBy(Member("B")), b.SignedBy(Member("C")))}))
+	s1, err := PolicyString(p1)
+	assert.NoError(t, err)
+	s2, err := PolicyString(p2)
+	assert.NoError(t, err)
+	assert.Equal(t, s2, s1)
+
+	_, err = FromString("OutOf(4, 'A.member', 'B.member')")
+	assert.Error(t, err)
+}
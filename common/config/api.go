@@ -21,6 +21,7 @@ package config
 import (
 	"time"
 
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
@@ -32,6 +33,9 @@ type Org interface {
 
 	// MSPID returns the MSP ID associated with this org
 	MSPID() string
+
+	// MSPConfig returns the configuration of the MSP of this org
+	MSPConfig() *mspprotos.MSPConfig
 }
 
 // ApplicationOrg stores the per org application config
@@ -42,10 +46,27 @@ type ApplicationOrg interface {
 	AnchorPeers() []*pb.AnchorPeer
 }
 
+// OrdererOrg stores the per org orderer config
+type OrdererOrg interface {
+	Org
+
+	// Endpoints returns the list of orderer addresses operated by this org
+	Endpoints() []string
+}
+
 // Application stores the common shared application config
 type Application interface {
 	// Organizations returns a map of org ID to ApplicationOrg
 	Organizations() map[string]ApplicationOrg
+
+	// HasCapability returns true if the channel requires the capability of its peers
+	HasCapability(name string) bool
+}
+
+// Consortium stores the config of a consortium
+type Consortium interface {
+	// Organizations returns a map of org name to the member orgs of the consortium
+	Organizations() map[string]Org
 }
 
 // Channel gives read only access to the channel configuration
@@ -60,6 +81,14 @@ type Channel interface {
 
 	// OrdererAddresses returns the list of valid orderer addresses to connect to to invoke Broadcast/Deliver
 	OrdererAddresses() []string
+
+	// ConsortiumName returns the name of the consortium the channel was created by
+	// This field is not set for the system ordering chain
+	ConsortiumName() string
+
+	// Consortiums returns a map of consortium name to the consortiums which may create channels
+	// This field is only set for the system ordering chain
+	Consortiums() map[string]Consortium
 }
 
 // Orderer stores the common shared orderer config
@@ -67,6 +96,10 @@ type Orderer interface {
 	// ConsensusType returns the configured consensus type
 	ConsensusType() string
 
+	// ConsensusState returns the configured state of the consensus, in which
+	// maintenance only config transactions are accepted
+	ConsensusState() ab.ConsensusType_State
+
 	// BatchSize returns the maximum number of messages to include in a block
 	BatchSize() *ab.BatchSize
 
@@ -84,6 +117,9 @@ type Orderer interface {
 	// Kafka brokers, i.e. this is not necessarily the entire set of Kafka brokers
 	// used for ordering
 	KafkaBrokers() []string
+
+	// Organizations returns a map of org ID to OrdererOrg
+	Organizations() map[string]OrdererOrg
 }
 
 type ValueProposer interface {
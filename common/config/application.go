@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/fabric/common/config/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
 )
 
 const (
@@ -27,6 +28,67 @@ const (
 	ApplicationGroupKey = "Application"
 )
 
+const (
+	// CapabilitiesKey is the key name for the Capabilities message
+	CapabilitiesKey = "Capabilities"
+)
+
+// The capabilities the Capabilities of the Application config may require.
+// Each changes the rules the peers of the channel validate transactions
+// with, so it must only be required once all of them support it
+const (
+	// ChaincodeIdentityCapability rejects the deployments and upgrades
+	// recording the package data of the chaincode in the legacy format
+	ChaincodeIdentityCapability = "ChaincodeIdentity"
+
+	// LSCCWritesCapability rejects the transactions writing to the namespace
+	// of lscc without invoking it, e.g. through a chaincode to chaincode call
+	LSCCWritesCapability = "LSCCWrites"
+
+	// CanonicalEncodingCapability rejects the transactions whose payload or
+	// proposal response payloads, as signed, are not canonically encoded
+	CanonicalEncodingCapability = "CanonicalEncoding"
+
+	// EnvelopeStructureCapability rejects the transactions whose envelope
+	// fails the structure and size checks of the orderers and endorsers
+	EnvelopeStructureCapability = "EnvelopeStructure"
+
+	// BlockTxIDUniquenessCapability rejects the transactions reusing the
+	// transaction ID of an earlier transaction of the same block
+	BlockTxIDUniquenessCapability = "BlockTxIDUniqueness"
+
+	// KeyMetadataCapability accepts the transactions writing the metadata of
+	// keys, which the peers predating it would not apply to their state
+	KeyMetadataCapability = "KeyMetadata"
+
+	// DependencyInvalidationCapability marks invalid the transactions reading
+	// the keys written by the invalid transactions preceding them in the block
+	DependencyInvalidationCapability = "DependencyInvalidation"
+
+	// ChaincodeVersionCapability rejects the transactions endorsed against
+	// another version of the chaincode than the committed one
+	ChaincodeVersionCapability = "ChaincodeVersion"
+)
+
+// applicationCapabilities holds the capabilities known to this peer: it
+// cannot apply a config requiring another, as it would validate the
+// transactions of the channel differently than the peers supporting it
+var applicationCapabilities = map[string]struct{}{
+	ChaincodeIdentityCapability:      {},
+	LSCCWritesCapability:             {},
+	CanonicalEncodingCapability:      {},
+	EnvelopeStructureCapability:      {},
+	BlockTxIDUniquenessCapability:    {},
+	KeyMetadataCapability:            {},
+	DependencyInvalidationCapability: {},
+	ChaincodeVersionCapability:       {},
+}
+
+// ApplicationProtos is used as the source of the ApplicationConfig
+type ApplicationProtos struct {
+	Capabilities *cb.Capabilities
+}
+
 // ApplicationGroup represents the application config group
 type ApplicationGroup struct {
 	*Proposer
@@ -36,6 +98,7 @@ type ApplicationGroup struct {
 
 type ApplicationConfig struct {
 	*standardValues
+	protos *ApplicationProtos
 
 	applicationGroup *ApplicationGroup
 	applicationOrgs  map[string]ApplicationOrg
@@ -61,17 +124,17 @@ func (ag *ApplicationGroup) Allocate() Values {
 }
 
 func NewApplicationConfig(ag *ApplicationGroup) *ApplicationConfig {
-	sv, err := NewStandardValues(&(struct{}{}))
-	if err != nil {
-		logger.Panicf("Programming error: %s", err)
-	}
-
-	return &ApplicationConfig{
+	ac := &ApplicationConfig{
+		protos:           &ApplicationProtos{},
 		applicationGroup: ag,
+	}
 
-		// Currently there are no config values
-		standardValues: sv,
+	var err error
+	ac.standardValues, err = NewStandardValues(ac.protos)
+	if err != nil {
+		logger.Panicf("Programming error: %s", err)
 	}
+	return ac
 }
 
 func (ac *ApplicationConfig) Validate(tx interface{}, groups map[string]ValueProposer) error {
@@ -83,6 +146,11 @@ func (ac *ApplicationConfig) Validate(tx interface{}, groups map[string]ValuePro
 			return fmt.Errorf("Application sub-group %s was not an ApplicationOrgGroup, actually %T", key, value)
 		}
 	}
+	for name := range ac.protos.Capabilities.Capabilities {
+		if _, ok := applicationCapabilities[name]; !ok {
+			return fmt.Errorf("Application capability %s is not supported by this peer", name)
+		}
+	}
 	return nil
 }
 
@@ -94,3 +162,9 @@ func (ac *ApplicationConfig) Commit() {
 func (ac *ApplicationConfig) Organizations() map[string]ApplicationOrg {
 	return ac.applicationOrgs
 }
+
+// HasCapability returns true if the channel requires the capability of its peers
+func (ac *ApplicationConfig) HasCapability(name string) bool {
+	_, ok := ac.protos.Capabilities.Capabilities[name]
+	return ok
+}
@@ -19,7 +19,11 @@ package config
 import (
 	"testing"
 
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+
 	logging "github.com/op/go-logging"
+	"github.com/stretchr/testify/assert"
 )
 
 func init() {
@@ -29,3 +33,21 @@ func init() {
 func TestApplicationInterface(t *testing.T) {
 	_ = Application((*ApplicationGroup)(nil))
 }
+
+func TestApplicationCapabilities(t *testing.T) {
+	ac := NewApplicationConfig(NewApplicationGroup(nil))
+	assert.NoError(t, ac.Validate(nil, nil), "No capability is valid")
+	assert.False(t, ac.HasCapability(ChaincodeIdentityCapability), "Should not require an unset capability")
+
+	capabilities := &cb.Capabilities{Capabilities: map[string]*cb.Capability{ChaincodeIdentityCapability: {}}}
+	_, err := ac.Deserialize(CapabilitiesKey, utils.MarshalOrPanic(capabilities))
+	assert.NoError(t, err)
+	assert.NoError(t, ac.Validate(nil, nil), "Known capabilities are valid")
+	assert.True(t, ac.HasCapability(ChaincodeIdentityCapability), "Should require the set capability")
+
+	ac = NewApplicationConfig(NewApplicationGroup(nil))
+	capabilities = &cb.Capabilities{Capabilities: map[string]*cb.Capability{"Unknown": {}}}
+	_, err = ac.Deserialize(CapabilitiesKey, utils.MarshalOrPanic(capabilities))
+	assert.NoError(t, err)
+	assert.Error(t, ac.Validate(nil, nil), "Unknown capabilities are not supported")
+}
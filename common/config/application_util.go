@@ -36,3 +36,17 @@ func applicationConfigGroup(orgID string, key string, value []byte) *cb.ConfigGr
 func TemplateAnchorPeers(orgID string, anchorPeers []*pb.AnchorPeer) *cb.ConfigGroup {
 	return applicationConfigGroup(orgID, AnchorPeersKey, utils.MarshalOrPanic(&pb.AnchorPeers{AnchorPeers: anchorPeers}))
 }
+
+// TemplateApplicationCapabilities creates a headerless config item requiring the capabilities of the peers of the channel
+func TemplateApplicationCapabilities(names []string) *cb.ConfigGroup {
+	capabilities := &cb.Capabilities{Capabilities: make(map[string]*cb.Capability)}
+	for _, name := range names {
+		capabilities.Capabilities[name] = &cb.Capability{}
+	}
+	result := cb.NewConfigGroup()
+	result.Groups[ApplicationGroupKey] = cb.NewConfigGroup()
+	result.Groups[ApplicationGroupKey].Values[CapabilitiesKey] = &cb.ConfigValue{
+		Value: utils.MarshalOrPanic(capabilities),
+	}
+	return result
+}
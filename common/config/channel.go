@@ -37,6 +37,9 @@ const (
 	// OrdererAddressesKey is the cb.ConfigItem type key name for the OrdererAddresses message
 	OrdererAddressesKey = "OrdererAddresses"
 
+	// ConsortiumKey is the cb.ConfigItem type key name for the Consortium message
+	ConsortiumKey = "Consortium"
+
 	// GroupKey is the name of the channel group
 	ChannelGroupKey = "Channel"
 )
@@ -60,6 +63,7 @@ type ChannelProtos struct {
 	HashingAlgorithm          *cb.HashingAlgorithm
 	BlockDataHashingStructure *cb.BlockDataHashingStructure
 	OrdererAddresses          *cb.OrdererAddresses
+	Consortium                *cb.Consortium
 }
 
 type channelConfigSetter struct {
@@ -105,13 +109,20 @@ func (cg *ChannelGroup) ApplicationConfig() *ApplicationGroup {
 	return cg.ChannelConfig.appConfig
 }
 
-// NewGroup instantiates either a new application or orderer config
+// ConsortiumsConfig returns the consortiums config associated with this channel
+func (cg *ChannelGroup) ConsortiumsConfig() *ConsortiumsGroup {
+	return cg.ChannelConfig.consortiumsConfig
+}
+
+// NewGroup instantiates either a new application, orderer or consortiums config
 func (cg *ChannelGroup) NewGroup(group string) (ValueProposer, error) {
 	switch group {
 	case ApplicationGroupKey:
 		return NewApplicationGroup(cg.mspConfigHandler), nil
 	case OrdererGroupKey:
 		return NewOrdererGroup(cg.mspConfigHandler), nil
+	case ConsortiumsGroupKey:
+		return NewConsortiumsGroup(cg.mspConfigHandler), nil
 	default:
 		return nil, fmt.Errorf("Disallowed channel group: %s", group)
 	}
@@ -124,8 +135,9 @@ type ChannelConfig struct {
 
 	hashingAlgorithm func(input []byte) []byte
 
-	appConfig     *ApplicationGroup
-	ordererConfig *OrdererGroup
+	appConfig         *ApplicationGroup
+	ordererConfig     *OrdererGroup
+	consortiumsConfig *ConsortiumsGroup
 }
 
 // NewChannelConfig creates a new ChannelConfig
@@ -157,6 +169,19 @@ func (cc *ChannelConfig) OrdererAddresses() []string {
 	return cc.protos.OrdererAddresses.Addresses
 }
 
+// ConsortiumName returns the name of the consortium the channel was created by
+func (cc *ChannelConfig) ConsortiumName() string {
+	return cc.protos.Consortium.Name
+}
+
+// Consortiums returns a map of consortium name to the consortiums which may create channels
+func (cc *ChannelConfig) Consortiums() map[string]Consortium {
+	if cc.consortiumsConfig == nil {
+		return nil
+	}
+	return cc.consortiumsConfig.Consortiums()
+}
+
 // Validate inspects the generated configuration protos, ensures that the values are correct, and
 // sets the ChannelConfig fields that may be referenced after Commit
 func (cc *ChannelConfig) Validate(tx interface{}, groups map[string]ValueProposer) error {
@@ -183,6 +208,11 @@ func (cc *ChannelConfig) Validate(tx interface{}, groups map[string]ValuePropose
 			if !ok {
 				return fmt.Errorf("Orderer group was not Orderer config")
 			}
+		case ConsortiumsGroupKey:
+			cc.consortiumsConfig, ok = value.(*ConsortiumsGroup)
+			if !ok {
+				return fmt.Errorf("Consortiums group was not Consortiums config")
+			}
 		default:
 			return fmt.Errorf("Disallowed channel group: %s", key)
 		}
@@ -68,3 +68,8 @@ func TemplateOrdererAddresses(addresses []string) *cb.ConfigGroup {
 func DefaultOrdererAddresses() *cb.ConfigGroup {
 	return TemplateOrdererAddresses(defaultOrdererAddresses)
 }
+
+// TemplateConsortium creates a headerless config item representing the consortium the channel is created by
+func TemplateConsortium(name string) *cb.ConfigGroup {
+	return configGroup(ConsortiumKey, utils.MarshalOrPanic(&cb.Consortium{Name: name}))
+}
@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/config/msp"
+)
+
+const (
+	// ChannelCreationPolicyKey is the name of the policy of a consortium
+	// which the creation transactions of its channels must satisfy
+	ChannelCreationPolicyKey = "ChannelCreationPolicy"
+)
+
+// ConsortiumGroup represents a consortium config group, whose sub-groups are
+// the member orgs of the consortium
+type ConsortiumGroup struct {
+	*Proposer
+	*ConsortiumConfig
+	name      string
+	mspConfig *msp.MSPConfigHandler
+}
+
+// ConsortiumConfig holds the member orgs of a consortium
+type ConsortiumConfig struct {
+	*standardValues
+
+	consortiumGroup *ConsortiumGroup
+	orgs            map[string]Org
+}
+
+// NewConsortiumGroup creates a new ConsortiumGroup
+func NewConsortiumGroup(name string, mspConfig *msp.MSPConfigHandler) *ConsortiumGroup {
+	cg := &ConsortiumGroup{
+		name:      name,
+		mspConfig: mspConfig,
+	}
+	cg.Proposer = NewProposer(cg)
+	return cg
+}
+
+// NewGroup creates a new member org group
+func (cg *ConsortiumGroup) NewGroup(name string) (ValueProposer, error) {
+	return NewOrganizationGroup(name, cg.mspConfig), nil
+}
+
+// Allocate returns a new instance of the ConsortiumConfig
+func (cg *ConsortiumGroup) Allocate() Values {
+	return NewConsortiumConfig(cg)
+}
+
+// NewConsortiumConfig creates a new ConsortiumConfig
+func NewConsortiumConfig(cg *ConsortiumGroup) *ConsortiumConfig {
+	sv, err := NewStandardValues(&(struct{}{}))
+	if err != nil {
+		logger.Panicf("Programming error: %s", err)
+	}
+
+	return &ConsortiumConfig{
+		consortiumGroup: cg,
+
+		// The channel creation policy is a policy of the group, there are no config values
+		standardValues: sv,
+	}
+}
+
+// Validate sets the member orgs from the org groups
+func (cc *ConsortiumConfig) Validate(tx interface{}, groups map[string]ValueProposer) error {
+	cc.orgs = make(map[string]Org)
+	var ok bool
+	for key, value := range groups {
+		cc.orgs[key], ok = value.(*OrganizationGroup)
+		if !ok {
+			return fmt.Errorf("Consortium %s sub-group %s was not an OrganizationGroup, actually %T", cc.consortiumGroup.name, key, value)
+		}
+	}
+	return nil
+}
+
+// Commit makes the config the current one of the group
+func (cc *ConsortiumConfig) Commit() {
+	cc.consortiumGroup.ConsortiumConfig = cc
+}
+
+// Organizations returns a map of org name to the member orgs of the consortium
+func (cc *ConsortiumConfig) Organizations() map[string]Org {
+	return cc.orgs
+}
@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/config/msp"
+)
+
+const (
+	// ConsortiumsGroupKey is the group name for the consortiums config
+	ConsortiumsGroupKey = "Consortiums"
+)
+
+// ConsortiumsGroup represents the consortiums config group, which is only
+// defined in the ordering system channel
+type ConsortiumsGroup struct {
+	*Proposer
+	*ConsortiumsConfig
+	mspConfig *msp.MSPConfigHandler
+}
+
+// ConsortiumsConfig holds the consortiums which may create channels
+type ConsortiumsConfig struct {
+	*standardValues
+
+	consortiumsGroup *ConsortiumsGroup
+	consortiums      map[string]Consortium
+}
+
+// NewConsortiumsGroup creates a new ConsortiumsGroup
+func NewConsortiumsGroup(mspConfig *msp.MSPConfigHandler) *ConsortiumsGroup {
+	cg := &ConsortiumsGroup{
+		mspConfig: mspConfig,
+	}
+	cg.Proposer = NewProposer(cg)
+	return cg
+}
+
+// NewGroup creates a new consortium group
+func (cg *ConsortiumsGroup) NewGroup(name string) (ValueProposer, error) {
+	return NewConsortiumGroup(name, cg.mspConfig), nil
+}
+
+// Allocate returns a new instance of the ConsortiumsConfig
+func (cg *ConsortiumsGroup) Allocate() Values {
+	return NewConsortiumsConfig(cg)
+}
+
+// NewConsortiumsConfig creates a new ConsortiumsConfig
+func NewConsortiumsConfig(cg *ConsortiumsGroup) *ConsortiumsConfig {
+	sv, err := NewStandardValues(&(struct{}{}))
+	if err != nil {
+		logger.Panicf("Programming error: %s", err)
+	}
+
+	return &ConsortiumsConfig{
+		consortiumsGroup: cg,
+
+		// There are no config values, only the consortium groups
+		standardValues: sv,
+	}
+}
+
+// Validate sets the consortiums from the consortium groups
+func (cc *ConsortiumsConfig) Validate(tx interface{}, groups map[string]ValueProposer) error {
+	cc.consortiums = make(map[string]Consortium)
+	var ok bool
+	for key, value := range groups {
+		cc.consortiums[key], ok = value.(*ConsortiumGroup)
+		if !ok {
+			return fmt.Errorf("Consortiums sub-group %s was not a ConsortiumGroup, actually %T", key, value)
+		}
+	}
+	return nil
+}
+
+// Commit makes the config the current one of the group
+func (cc *ConsortiumsConfig) Commit() {
+	cc.consortiumsGroup.ConsortiumsConfig = cc
+}
+
+// Consortiums returns a map of consortium name to Consortium
+func (cc *ConsortiumsConfig) Consortiums() map[string]Consortium {
+	return cc.consortiums
+}
@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsortiumInterface(t *testing.T) {
+	_ = Consortium((*ConsortiumGroup)(nil))
+}
+
+func TestConsortiumsValidate(t *testing.T) {
+	cg := NewConsortiumsGroup(nil)
+	cc := NewConsortiumsConfig(cg)
+
+	assert.Error(t, cc.Validate(nil, map[string]ValueProposer{"foo": NewOrganizationGroup("foo", nil)}), "Consortiums should only contain consortium groups")
+
+	assert.NoError(t, cc.Validate(nil, map[string]ValueProposer{"foo": NewConsortiumGroup("foo", nil)}))
+	cc.Commit()
+	_, ok := cg.Consortiums()["foo"]
+	assert.True(t, ok, "Should have committed consortium foo")
+}
+
+func TestConsortiumValidate(t *testing.T) {
+	cg := NewConsortiumGroup("foo", nil)
+	cc := NewConsortiumConfig(cg)
+
+	assert.Error(t, cc.Validate(nil, map[string]ValueProposer{"bar": NewConsortiumGroup("bar", nil)}), "Consortium should only contain org groups")
+
+	assert.NoError(t, cc.Validate(nil, map[string]ValueProposer{"bar": NewOrganizationGroup("bar", nil)}))
+	cc.Commit()
+	_, ok := cg.Organizations()["bar"]
+	assert.True(t, ok, "Should have committed member org bar")
+}
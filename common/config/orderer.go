@@ -19,6 +19,7 @@ package config
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -80,9 +81,9 @@ func NewOrdererGroup(mspConfig *msp.MSPConfigHandler) *OrdererGroup {
 	return og
 }
 
-// NewGroup returns an Org instance
+// NewGroup returns an OrdererOrg instance
 func (og *OrdererGroup) NewGroup(name string) (ValueProposer, error) {
-	return NewOrganizationGroup(name, og.mspConfig), nil
+	return NewOrdererOrgGroup(name, og.mspConfig), nil
 }
 
 func (og *OrdererGroup) Allocate() Values {
@@ -96,6 +97,7 @@ type OrdererConfig struct {
 	ordererGroup *OrdererGroup
 
 	batchTimeout time.Duration
+	ordererOrgs  map[string]OrdererOrg
 }
 
 // NewOrdererConfig creates a new instance of the orderer config
@@ -123,6 +125,11 @@ func (oc *OrdererConfig) ConsensusType() string {
 	return oc.protos.ConsensusType.Type
 }
 
+// ConsensusState returns the configured state of the consensus
+func (oc *OrdererConfig) ConsensusState() ab.ConsensusType_State {
+	return oc.protos.ConsensusType.State
+}
+
 // BatchSize returns the maximum number of messages to include in a block
 func (oc *OrdererConfig) BatchSize() *ab.BatchSize {
 	return oc.protos.BatchSize
@@ -151,7 +158,21 @@ func (oc *OrdererConfig) MaxChannelsCount() uint64 {
 	return oc.protos.ChannelRestrictions.MaxCount
 }
 
+// Organizations returns a map of org ID to OrdererOrg
+func (oc *OrdererConfig) Organizations() map[string]OrdererOrg {
+	return oc.ordererOrgs
+}
+
 func (oc *OrdererConfig) Validate(tx interface{}, groups map[string]ValueProposer) error {
+	oc.ordererOrgs = make(map[string]OrdererOrg)
+	var ok bool
+	for key, value := range groups {
+		oc.ordererOrgs[key], ok = value.(*OrdererOrgGroup)
+		if !ok {
+			return fmt.Errorf("Orderer sub-group %s was not an OrdererOrgGroup, actually %T", key, value)
+		}
+	}
+
 	for _, validator := range []func() error{
 		oc.validateConsensusType,
 		oc.validateBatchSize,
@@ -171,6 +192,9 @@ func (oc *OrdererConfig) validateConsensusType() error {
 		// The first config we accept the consensus type regardless
 		return fmt.Errorf("Attempted to change the consensus type from %s to %s after init", oc.ordererGroup.ConsensusType(), oc.protos.ConsensusType.Type)
 	}
+	if _, ok := ab.ConsensusType_State_name[int32(oc.protos.ConsensusType.State)]; !ok {
+		return fmt.Errorf("Attempted to set the consensus state to an unknown value: %d", oc.protos.ConsensusType.State)
+	}
 	return nil
 }
 
@@ -241,3 +265,39 @@ func brokerEntrySeemsValid(broker string) bool {
 	matched := re.FindString(host)
 	return len(matched) == len(host)
 }
+
+// OrdererAddressesOfOrg resolves the orderer addresses the members of the org
+// with the given MSP ID should connect to. The endpoints the org declares in
+// its own orderer org take precedence, as they may only be resolvable within
+// the org. Otherwise the global orderer addresses of the channel are used, and
+// if there are none, the endpoints of all the orderer orgs.
+func OrdererAddressesOfOrg(mspID string, channel Channel, orderer Orderer) []string {
+	if endpoints := OrdererEndpointsOfOrg(mspID, orderer); len(endpoints) > 0 {
+		return endpoints
+	}
+	var orgsEndpoints []string
+	if orderer != nil {
+		for _, org := range orderer.Organizations() {
+			orgsEndpoints = append(orgsEndpoints, org.Endpoints()...)
+		}
+	}
+	if addresses := channel.OrdererAddresses(); len(addresses) > 0 {
+		return addresses
+	}
+	sort.Strings(orgsEndpoints)
+	return orgsEndpoints
+}
+
+// OrdererEndpointsOfOrg returns the orderer endpoints declared by the orderer
+// org with the given MSP ID, or nil if there is no such org or it declares none.
+func OrdererEndpointsOfOrg(mspID string, orderer Orderer) []string {
+	if orderer == nil {
+		return nil
+	}
+	for _, org := range orderer.Organizations() {
+		if org.MSPID() == mspID {
+			return org.Endpoints()
+		}
+	}
+	return nil
+}
@@ -44,6 +44,13 @@ func TestConsensusType(t *testing.T) {
 		protos:       &OrdererProtos{ConsensusType: &ab.ConsensusType{Type: "foo"}},
 	}
 	assert.Error(t, oc.validateConsensusType(), "Should have failed to change consensus type")
+
+	oc = &OrdererConfig{ordererGroup: &OrdererGroup{}, protos: &OrdererProtos{ConsensusType: &ab.ConsensusType{Type: "foo", State: ab.ConsensusType_STATE_MAINTENANCE}}}
+	assert.NoError(t, oc.validateConsensusType(), "Should have validly set the maintenance state")
+	assert.Equal(t, ab.ConsensusType_STATE_MAINTENANCE, oc.ConsensusState(), "Should have returned the maintenance state")
+
+	oc = &OrdererConfig{ordererGroup: &OrdererGroup{}, protos: &OrdererProtos{ConsensusType: &ab.ConsensusType{Type: "foo", State: 5}}}
+	assert.Error(t, oc.validateConsensusType(), "Should have failed to set an unknown consensus state")
 }
 
 func TestBatchSize(t *testing.T) {
@@ -60,3 +60,18 @@ func TemplateChannelRestrictions(maxChannels uint64) *cb.ConfigGroup {
 func TemplateKafkaBrokers(brokers []string) *cb.ConfigGroup {
 	return ordererConfigGroup(KafkaBrokersKey, utils.MarshalOrPanic(&ab.KafkaBrokers{Brokers: brokers}))
 }
+
+func ordererOrgConfigGroup(orgID string, key string, value []byte) *cb.ConfigGroup {
+	result := cb.NewConfigGroup()
+	result.Groups[OrdererGroupKey] = cb.NewConfigGroup()
+	result.Groups[OrdererGroupKey].Groups[orgID] = cb.NewConfigGroup()
+	result.Groups[OrdererGroupKey].Groups[orgID].Values[key] = &cb.ConfigValue{
+		Value: value,
+	}
+	return result
+}
+
+// TemplateOrdererEndpoints creates a headerless config item representing the orderer endpoints of an org
+func TemplateOrdererEndpoints(orgID string, endpoints []string) *cb.ConfigGroup {
+	return ordererOrgConfigGroup(orgID, OrdererEndpointsKey, utils.MarshalOrPanic(&cb.OrdererAddresses{Addresses: endpoints}))
+}
@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	mspconfig "github.com/hyperledger/fabric/common/config/msp"
+	cb "github.com/hyperledger/fabric/protos/common"
+
+	logging "github.com/op/go-logging"
+)
+
+// Orderer org config keys
+const (
+	// OrdererEndpointsKey is the key name for the OrdererEndpoints ConfigValue
+	OrdererEndpointsKey = "OrdererEndpoints"
+)
+
+type OrdererOrgProtos struct {
+	OrdererEndpoints *cb.OrdererAddresses
+}
+
+type OrdererOrgConfig struct {
+	*OrganizationConfig
+	protos *OrdererOrgProtos
+
+	ordererOrgGroup *OrdererOrgGroup
+}
+
+// OrdererOrgGroup defines the configuration for an orderer org
+type OrdererOrgGroup struct {
+	*Proposer
+	*OrganizationGroup
+	*OrdererOrgConfig
+}
+
+// NewOrdererOrgGroup creates a new OrdererOrgGroup
+func NewOrdererOrgGroup(id string, mspConfig *mspconfig.MSPConfigHandler) *OrdererOrgGroup {
+	oog := &OrdererOrgGroup{
+		OrganizationGroup: NewOrganizationGroup(id, mspConfig),
+	}
+	oog.Proposer = NewProposer(oog)
+	return oog
+}
+
+// Endpoints returns the list of orderer addresses operated by this org
+func (ooc *OrdererOrgConfig) Endpoints() []string {
+	return ooc.protos.OrdererEndpoints.Addresses
+}
+
+func (oog *OrdererOrgGroup) Allocate() Values {
+	return NewOrdererOrgConfig(oog)
+}
+
+func (ooc *OrdererOrgConfig) Commit() {
+	ooc.ordererOrgGroup.OrdererOrgConfig = ooc
+	ooc.OrganizationConfig.Commit()
+}
+
+func NewOrdererOrgConfig(oog *OrdererOrgGroup) *OrdererOrgConfig {
+	ooc := &OrdererOrgConfig{
+		protos:             &OrdererOrgProtos{},
+		OrganizationConfig: NewOrganizationConfig(oog.OrganizationGroup),
+
+		ordererOrgGroup: oog,
+	}
+	var err error
+	ooc.standardValues, err = NewStandardValues(ooc.protos, ooc.OrganizationConfig.protos)
+	if err != nil {
+		logger.Panicf("Programming error: %s", err)
+	}
+
+	return ooc
+}
+
+func (ooc *OrdererOrgConfig) Validate(tx interface{}, groups map[string]ValueProposer) error {
+	if logger.IsEnabledFor(logging.DEBUG) {
+		logger.Debugf("Orderer endpoints for org %s are %v", ooc.ordererOrgGroup.name, ooc.protos.OrdererEndpoints)
+	}
+	for _, endpoint := range ooc.protos.OrdererEndpoints.Addresses {
+		if !brokerEntrySeemsValid(endpoint) {
+			return fmt.Errorf("Invalid orderer endpoint for org %s: %s", ooc.ordererOrgGroup.name, endpoint)
+		}
+	}
+	return ooc.OrganizationConfig.Validate(tx, groups)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrdererOrgInterface(t *testing.T) {
+	_ = ValueProposer(NewOrdererOrgGroup("id", nil))
+	_ = OrdererOrg(NewOrdererOrgGroup("id", nil))
+}
+
+func TestOrdererOrgEndpoints(t *testing.T) {
+	ooc := NewOrdererOrgConfig(NewOrdererOrgGroup("id", nil))
+	ooc.protos.OrdererEndpoints = &cb.OrdererAddresses{Addresses: []string{"orderer.org1.example.com:7050", "foo"}}
+	assert.Error(t, ooc.Validate(nil, nil), "Endpoint without port should be rejected")
+
+	ooc.protos.OrdererEndpoints = &cb.OrdererAddresses{Addresses: []string{"orderer.org1.example.com:7050"}}
+	assert.Equal(t, []string{"orderer.org1.example.com:7050"}, ooc.Endpoints())
+}
+
+func ordererOrg(mspID string, endpoints ...string) *OrdererOrgGroup {
+	oog := NewOrdererOrgGroup(mspID, nil)
+	oog.OrganizationGroup.OrganizationConfig = &OrganizationConfig{mspID: mspID}
+	oog.OrdererOrgConfig = &OrdererOrgConfig{protos: &OrdererOrgProtos{OrdererEndpoints: &cb.OrdererAddresses{Addresses: endpoints}}}
+	return oog
+}
+
+func TestOrdererAddressesOfOrg(t *testing.T) {
+	channel := &ChannelConfig{protos: &ChannelProtos{OrdererAddresses: &cb.OrdererAddresses{Addresses: []string{"orderer:7050"}}}}
+	orderer := &OrdererConfig{ordererOrgs: map[string]OrdererOrg{
+		"Org1": ordererOrg("Org1MSP", "orderer.org1:7050"),
+		"Org2": ordererOrg("Org2MSP", "orderer.org2:7050", "orderer.org2:8050"),
+		"Org3": ordererOrg("Org3MSP"),
+	}}
+
+	assert.Equal(t, []string{"orderer.org1:7050"}, OrdererAddressesOfOrg("Org1MSP", channel, orderer))
+	assert.Equal(t, []string{"orderer.org2:7050", "orderer.org2:8050"}, OrdererAddressesOfOrg("Org2MSP", channel, orderer))
+	assert.Equal(t, []string{"orderer:7050"}, OrdererAddressesOfOrg("Org3MSP", channel, orderer), "Org without endpoints should use the global addresses")
+	assert.Equal(t, []string{"orderer:7050"}, OrdererAddressesOfOrg("Org1MSP", channel, nil))
+
+	channel.protos.OrdererAddresses.Addresses = nil
+	assert.Equal(t, []string{"orderer.org1:7050", "orderer.org2:7050", "orderer.org2:8050"}, OrdererAddressesOfOrg("Org3MSP", channel, orderer),
+		"Without global addresses, the endpoints of all the orgs should be used")
+
+	assert.Equal(t, []string{"orderer.org2:7050", "orderer.org2:8050"}, OrdererEndpointsOfOrg("Org2MSP", orderer))
+	assert.Empty(t, OrdererEndpointsOfOrg("Org3MSP", orderer), "Org without endpoints should declare none")
+	assert.Nil(t, OrdererEndpointsOfOrg("Org4MSP", orderer))
+	assert.Nil(t, OrdererEndpointsOfOrg("Org1MSP", nil))
+}
@@ -72,6 +72,11 @@ func (og *OrganizationGroup) MSPID() string {
 	return og.mspID
 }
 
+// MSPConfig returns the configuration of the MSP of this org
+func (og *OrganizationGroup) MSPConfig() *mspprotos.MSPConfig {
+	return og.protos.MSP
+}
+
 // NewGroup always errors
 func (og *OrganizationGroup) NewGroup(name string) (ValueProposer, error) {
 	return nil, fmt.Errorf("Organization does not support subgroups")
@@ -83,3 +83,7 @@ func TestSimpleConfig(t *testing.T) {
 func TestOneMSPConfig(t *testing.T) {
 	commonTest(t, localconfig.SampleSingleMSPSoloProfile)
 }
+
+func TestConsortiumChannelConfig(t *testing.T) {
+	commonTest(t, localconfig.SampleConsortiumChannelProfile)
+}
@@ -25,8 +25,6 @@ import (
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
-
-	"github.com/golang/protobuf/proto"
 )
 
 const (
@@ -60,7 +58,7 @@ func NewSimpleTemplate(configGroups ...*cb.ConfigGroup) Template {
 
 // Envelope returns a ConfigUpdateEnvelope for the given chainID
 func (st *simpleTemplate) Envelope(chainID string) (*cb.ConfigUpdateEnvelope, error) {
-	config, err := proto.Marshal(&cb.ConfigUpdate{
+	config, err := utils.Marshal(&cb.ConfigUpdate{
 		ChannelId: chainID,
 		WriteSet:  st.configGroup,
 	})
@@ -133,7 +131,7 @@ func (ct *compositeTemplate) Envelope(chainID string) (*cb.ConfigUpdateEnvelope,
 		}
 	}
 
-	marshaledConfig, err := proto.Marshal(&cb.ConfigUpdate{
+	marshaledConfig, err := utils.Marshal(&cb.ConfigUpdate{
 		ChannelId: chainID,
 		WriteSet:  channel,
 	})
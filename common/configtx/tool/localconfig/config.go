@@ -40,6 +40,10 @@ const (
 	SampleInsecureProfile = "SampleInsecureSolo"
 	// SampleSingleMSPSoloProfile references the sample profile which includes only the sample MSP and uses solo for ordering.
 	SampleSingleMSPSoloProfile = "SampleSingleMSPSolo"
+	// SampleConsortiumSoloProfile references the sample profile which defines a consortium of the sample MSP and uses solo for ordering.
+	SampleConsortiumSoloProfile = "SampleConsortiumSolo"
+	// SampleConsortiumChannelProfile references the sample profile of a channel created by the consortium of SampleConsortiumSoloProfile.
+	SampleConsortiumChannelProfile = "SampleConsortiumChannel"
 
 	// Prefix identifies the prefix for the configtxgen-related ENV vars.
 	Prefix string = "CONFIGTX"
@@ -55,13 +59,23 @@ type TopLevel struct {
 
 // Profile encodes orderer/application configuration combinations for the configtxgen tool.
 type Profile struct {
-	Application *Application `yaml:"Application"`
-	Orderer     *Orderer     `yaml:"Orderer"`
+	Application *Application           `yaml:"Application"`
+	Orderer     *Orderer               `yaml:"Orderer"`
+	Consortiums map[string]*Consortium `yaml:"Consortiums"`
+	Consortium  string                 `yaml:"Consortium"`
+}
+
+// Consortium encodes the orgs which may create channels together, and the
+// signatures of their admins their channel creation transactions require.
+type Consortium struct {
+	Organizations         []*Organization `yaml:"Organizations"`
+	ChannelCreationPolicy string          `yaml:"ChannelCreationPolicy"`
 }
 
 // Application encodes the application-level configuration needed in config transactions.
 type Application struct {
 	Organizations []*Organization `yaml:"Organizations"`
+	Capabilities  []string        `yaml:"Capabilities"`
 }
 
 // Organization encodes the organization-level configuration needed in config transactions.
@@ -74,7 +88,8 @@ type Organization struct {
 	// Note: Viper deserialization does not seem to care for
 	// embedding of types, so we use one organization struct
 	// for both orderers and applications.
-	AnchorPeers []*AnchorPeer `yaml:"AnchorPeers"`
+	AnchorPeers      []*AnchorPeer `yaml:"AnchorPeers"`
+	OrdererEndpoints []string      `yaml:"OrdererEndpoints"`
 }
 
 // AnchorPeer encodes the necessary fields to identify an anchor peer.
@@ -92,13 +107,14 @@ type ApplicationOrganization struct {
 // Orderer contains configuration which is used for the
 // bootstrapping of an orderer by the provisional bootstrapper.
 type Orderer struct {
-	OrdererType   string          `yaml:"OrdererType"`
-	Addresses     []string        `yaml:"Addresses"`
-	BatchTimeout  time.Duration   `yaml:"BatchTimeout"`
-	BatchSize     BatchSize       `yaml:"BatchSize"`
-	Kafka         Kafka           `yaml:"Kafka"`
-	Organizations []*Organization `yaml:"Organizations"`
-	MaxChannels   uint64          `yaml:"MaxChannels"`
+	OrdererType     string          `yaml:"OrdererType"`
+	Addresses       []string        `yaml:"Addresses"`
+	BatchTimeout    time.Duration   `yaml:"BatchTimeout"`
+	BatchSize       BatchSize       `yaml:"BatchSize"`
+	Kafka           Kafka           `yaml:"Kafka"`
+	Organizations   []*Organization `yaml:"Organizations"`
+	MaxChannels     uint64          `yaml:"MaxChannels"`
+	BlockSignatures uint32          `yaml:"BlockSignatures"`
 }
 
 // BatchSize contains configuration affecting the size of batches.
@@ -129,6 +145,10 @@ var genesisDefaults = TopLevel{
 	},
 }
 
+// defaultChannelCreationPolicy requires the signature of an admin of any
+// member org of a consortium to create a channel
+const defaultChannelCreationPolicy = "ANY"
+
 func (p *Profile) initDefaults() {
 	for {
 		switch {
@@ -175,6 +195,16 @@ func (p *Profile) completeInitialization(configDir string) {
 	for _, org := range p.Orderer.Organizations {
 		translatePaths(configDir, org)
 	}
+
+	for name, consortium := range p.Consortiums {
+		if consortium.ChannelCreationPolicy == "" {
+			logger.Infof("Consortiums.%s.ChannelCreationPolicy unset, setting to %s", name, defaultChannelCreationPolicy)
+			consortium.ChannelCreationPolicy = defaultChannelCreationPolicy
+		}
+		for _, org := range consortium.Organizations {
+			translatePaths(configDir, org)
+		}
+	}
 }
 
 // Load returns the orderer/application config combination that corresponds to a given profile.
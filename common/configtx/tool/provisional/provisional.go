@@ -96,7 +96,20 @@ func New(conf *genesisconfig.Profile) Generator {
 		},
 	}
 
+	if conf.Consortium != "" {
+		bs.channelGroups = append(bs.channelGroups, config.TemplateConsortium(conf.Consortium))
+	}
+
 	if conf.Orderer != nil {
+		blockValidationPolicy := policies.TemplateImplicitMetaPolicyWithSubPolicy([]string{config.OrdererGroupKey}, BlockValidationPolicyKey, configvaluesmsp.WritersPolicyKey, cb.ImplicitMetaPolicy_ANY)
+		if conf.Orderer.BlockSignatures > 1 {
+			var mspIDs []string
+			for _, org := range conf.Orderer.Organizations {
+				mspIDs = append(mspIDs, org.ID)
+			}
+			blockValidationPolicy = cauthdsl.TemplatePolicyWithPath([]string{config.OrdererGroupKey}, BlockValidationPolicyKey, cauthdsl.SignedByNMembers(int32(conf.Orderer.BlockSignatures), mspIDs))
+		}
+
 		bs.ordererGroups = []*cb.ConfigGroup{
 			// Orderer Config Types
 			config.TemplateConsensusType(conf.Orderer.OrdererType),
@@ -109,7 +122,7 @@ func New(conf *genesisconfig.Profile) Generator {
 			config.TemplateChannelRestrictions(conf.Orderer.MaxChannels),
 
 			// Initialize the default Reader/Writer/Admins orderer policies, as well as block validation policy
-			policies.TemplateImplicitMetaPolicyWithSubPolicy([]string{config.OrdererGroupKey}, BlockValidationPolicyKey, configvaluesmsp.WritersPolicyKey, cb.ImplicitMetaPolicy_ANY),
+			blockValidationPolicy,
 			policies.TemplateImplicitMetaAnyPolicy([]string{config.OrdererGroupKey}, configvaluesmsp.ReadersPolicyKey),
 			policies.TemplateImplicitMetaAnyPolicy([]string{config.OrdererGroupKey}, configvaluesmsp.WritersPolicyKey),
 			policies.TemplateImplicitMetaMajorityPolicy([]string{config.OrdererGroupKey}, configvaluesmsp.AdminsPolicyKey),
@@ -121,6 +134,9 @@ func New(conf *genesisconfig.Profile) Generator {
 				logger.Panicf("Error loading MSP configuration for org %s: %s", org.Name, err)
 			}
 			bs.ordererGroups = append(bs.ordererGroups, configvaluesmsp.TemplateGroupMSP([]string{config.OrdererGroupKey, org.Name}, mspConfig))
+			if len(org.OrdererEndpoints) > 0 {
+				bs.ordererGroups = append(bs.ordererGroups, config.TemplateOrdererEndpoints(org.Name, org.OrdererEndpoints))
+			}
 		}
 
 		switch conf.Orderer.OrdererType {
@@ -137,6 +153,25 @@ func New(conf *genesisconfig.Profile) Generator {
 		}
 	}
 
+	for name, consortium := range conf.Consortiums {
+		rule, ok := cb.ImplicitMetaPolicy_Rule_value[consortium.ChannelCreationPolicy]
+		if !ok {
+			logger.Panicf("Unknown channel creation policy %s for consortium %s", consortium.ChannelCreationPolicy, name)
+		}
+
+		// The channel creation policy requires the signatures of the admins of the member orgs
+		bs.ordererSystemChannelGroups = append(bs.ordererSystemChannelGroups, policies.TemplateImplicitMetaPolicyWithSubPolicy(
+			[]string{config.ConsortiumsGroupKey, name}, config.ChannelCreationPolicyKey, configvaluesmsp.AdminsPolicyKey, cb.ImplicitMetaPolicy_Rule(rule)))
+
+		for _, org := range consortium.Organizations {
+			mspConfig, err := msp.GetVerifyingMspConfig(org.MSPDir, org.BCCSP, org.ID)
+			if err != nil {
+				logger.Panicf("Error loading MSP configuration for org %s: %s", org.Name, err)
+			}
+			bs.ordererSystemChannelGroups = append(bs.ordererSystemChannelGroups, configvaluesmsp.TemplateGroupMSP([]string{config.ConsortiumsGroupKey, name, org.Name}, mspConfig))
+		}
+	}
+
 	if conf.Application != nil {
 
 		bs.applicationGroups = []*cb.ConfigGroup{
@@ -145,6 +180,9 @@ func New(conf *genesisconfig.Profile) Generator {
 			policies.TemplateImplicitMetaAnyPolicy([]string{config.ApplicationGroupKey}, configvaluesmsp.WritersPolicyKey),
 			policies.TemplateImplicitMetaMajorityPolicy([]string{config.ApplicationGroupKey}, configvaluesmsp.AdminsPolicyKey),
 		}
+		if len(conf.Application.Capabilities) > 0 {
+			bs.applicationGroups = append(bs.applicationGroups, config.TemplateApplicationCapabilities(conf.Application.Capabilities))
+		}
 		for _, org := range conf.Application.Organizations {
 			mspConfig, err := msp.GetVerifyingMspConfig(org.MSPDir, org.BCCSP, org.ID)
 			if err != nil {
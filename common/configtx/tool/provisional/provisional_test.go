@@ -22,8 +22,12 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
+	"github.com/hyperledger/fabric/common/configtx"
 	genesisconfig "github.com/hyperledger/fabric/common/configtx/tool/localconfig"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
 var confSolo, confKafka *genesisconfig.Profile
@@ -67,3 +71,107 @@ func TestGenesisMetadata(t *testing.T) {
 		}
 	}
 }
+
+func TestGenesisOrdererEndpoints(t *testing.T) {
+	conf := genesisconfig.Load(genesisconfig.SampleSingleMSPSoloProfile)
+	conf.Orderer.Organizations[0].OrdererEndpoints = []string{"orderer.sampleorg:7050"}
+
+	genesisBlock := New(conf).GenesisBlock()
+	envelopeConfig, err := utils.ExtractEnvelope(genesisBlock, 0)
+	if err != nil {
+		t.Fatalf("Error extracting config envelope: %s", err)
+	}
+	configtxManager, err := configtx.NewManagerImpl(envelopeConfig, configtx.NewInitializer(), nil)
+	if err != nil {
+		t.Fatalf("Error loading genesis block: %s", err)
+	}
+
+	org, ok := configtxManager.OrdererConfig().Organizations()[conf.Orderer.Organizations[0].Name]
+	if !ok {
+		t.Fatalf("Expected orderer org %s", conf.Orderer.Organizations[0].Name)
+	}
+	if len(org.Endpoints()) != 1 || org.Endpoints()[0] != "orderer.sampleorg:7050" {
+		t.Fatalf("Expected orderer endpoints [orderer.sampleorg:7050], got %v", org.Endpoints())
+	}
+}
+
+func TestGenesisBlockSignatures(t *testing.T) {
+	conf := genesisconfig.Load(genesisconfig.SampleSingleMSPSoloProfile)
+	conf.Orderer.BlockSignatures = 2
+
+	genesisBlock := New(conf).GenesisBlock()
+	envelopeConfig, err := utils.ExtractEnvelope(genesisBlock, 0)
+	if err != nil {
+		t.Fatalf("Error extracting config envelope: %s", err)
+	}
+	if _, err = configtx.NewManagerImpl(envelopeConfig, configtx.NewInitializer(), nil); err != nil {
+		t.Fatalf("Error loading genesis block: %s", err)
+	}
+
+	payload := utils.UnmarshalPayloadOrPanic(envelopeConfig.Payload)
+	configEnv := &cb.ConfigEnvelope{}
+	if err = proto.Unmarshal(payload.Data, configEnv); err != nil {
+		t.Fatalf("Error unmarshaling config envelope: %s", err)
+	}
+	policy := configEnv.Config.ChannelGroup.Groups[config.OrdererGroupKey].Policies[BlockValidationPolicyKey].Policy
+	if policy.Type != int32(cb.Policy_SIGNATURE) {
+		t.Fatalf("Expected a signature block validation policy, got type %d", policy.Type)
+	}
+	sigPolicy := &cb.SignaturePolicyEnvelope{}
+	if err = proto.Unmarshal(policy.Policy, sigPolicy); err != nil {
+		t.Fatalf("Error unmarshaling block validation policy: %s", err)
+	}
+	if n := sigPolicy.Policy.GetNOutOf().N; n != 2 {
+		t.Fatalf("Expected the block validation policy to require 2 signatures, got %d", n)
+	}
+}
+
+func TestGenesisConsortiums(t *testing.T) {
+	conf := genesisconfig.Load(genesisconfig.SampleConsortiumSoloProfile)
+
+	genesisBlock := New(conf).GenesisBlock()
+	envelopeConfig, err := utils.ExtractEnvelope(genesisBlock, 0)
+	if err != nil {
+		t.Fatalf("Error extracting config envelope: %s", err)
+	}
+	configtxManager, err := configtx.NewManagerImpl(envelopeConfig, configtx.NewInitializer(), nil)
+	if err != nil {
+		t.Fatalf("Error loading genesis block: %s", err)
+	}
+
+	consortium, ok := configtxManager.ChannelConfig().Consortiums()["SampleConsortium"]
+	if !ok {
+		t.Fatalf("Expected consortium SampleConsortium")
+	}
+	org, ok := consortium.Organizations()["SampleOrg"]
+	if !ok || org.MSPID() != "DEFAULT" {
+		t.Fatalf("Expected member org SampleOrg with MSP ID DEFAULT, got %v", consortium.Organizations())
+	}
+
+	policy, ok := configtxManager.PolicyManager().GetPolicy("/Channel/Consortiums/SampleConsortium/" + config.ChannelCreationPolicyKey)
+	if !ok {
+		t.Fatalf("Expected the channel creation policy of the consortium")
+	}
+	if err := policy.Evaluate(nil); err == nil {
+		t.Fatalf("Channel creation policy should require signatures")
+	}
+}
+
+func TestChannelTemplateConsortium(t *testing.T) {
+	conf := genesisconfig.Load(genesisconfig.SampleConsortiumChannelProfile)
+
+	configUpdateEnv, err := New(conf).ChannelTemplate().Envelope("foo")
+	if err != nil {
+		t.Fatalf("Error generating channel template: %s", err)
+	}
+	configUpdate := configtx.UnmarshalConfigUpdateOrPanic(configUpdateEnv.ConfigUpdate)
+
+	consortiumValue, ok := configUpdate.WriteSet.Values[config.ConsortiumKey]
+	if !ok {
+		t.Fatalf("Expected the channel template to name its consortium")
+	}
+	consortium := &cb.Consortium{}
+	if err := proto.Unmarshal(consortiumValue.Value, consortium); err != nil || consortium.Name != "SampleConsortium" {
+		t.Fatalf("Expected consortium SampleConsortium, got %v (%v)", consortium, err)
+	}
+}
@@ -140,7 +140,11 @@ func (cm *configManager) authorizeUpdate(configUpdateEnv *cb.ConfigUpdateEnvelop
 }
 
 func (cm *configManager) policyForItem(item comparable) (policies.Policy, bool) {
-	// path is always at least of length 1
+	// path is only empty for the channel group, whose policies are the ones of the root manager
+	if len(item.path) == 0 {
+		return cm.PolicyManager().GetPolicy(item.modPolicy())
+	}
+
 	manager, ok := cm.PolicyManager().Manager(item.path[1:])
 	if !ok {
 		return nil, ok
@@ -0,0 +1,49 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configtx
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/configtx/api"
+	cb "github.com/hyperledger/fabric/protos/common"
+)
+
+// ValidateConfigUpdate checks a config update of an existing channel against
+// the current config of the channel, the way the ordering service does before
+// ordering it: the read set must match the current config, the modification
+// policies of the changed elements must be satisfied by the signatures of the
+// update, and the resulting config must pass the checks of its values and
+// policies. It allows admins to catch broken updates before submitting them,
+// and returns the config the update would produce.
+func ValidateConfigUpdate(config *cb.Envelope, configUpdate *cb.Envelope, initializer api.Initializer) (*cb.ConfigEnvelope, error) {
+	cm, err := NewManagerImpl(config, initializer, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error loading the current config: %s", err)
+	}
+
+	configEnv, err := cm.ProposeConfigUpdate(configUpdate)
+	if err != nil {
+		return nil, fmt.Errorf("Config update rejected: %s", err)
+	}
+
+	if err := cm.Validate(configEnv); err != nil {
+		return nil, fmt.Errorf("Config update produces an invalid config: %s", err)
+	}
+
+	return configEnv, nil
+}
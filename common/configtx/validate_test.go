@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"testing"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfigUpdate(t *testing.T) {
+	config := makeEnvelopeConfig(defaultChain, makeConfigPair("foo", "foo", 0, []byte("foo")))
+
+	update := makeConfigUpdateEnvelope(defaultChain, makeConfigSet(), makeConfigSet(makeConfigPair("foo", "foo", 1, []byte("bar"))))
+	configEnv, err := ValidateConfigUpdate(config, update, defaultInitializer())
+	assert.NoError(t, err)
+	if assert.NotNil(t, configEnv) {
+		assert.Equal(t, uint64(1), configEnv.Config.Sequence)
+		assert.Equal(t, []byte("bar"), configEnv.Config.ChannelGroup.Values["foo"].Value)
+		assert.Equal(t, update, configEnv.LastUpdate)
+	}
+
+	t.Run("InvalidConfig", func(t *testing.T) {
+		_, err := ValidateConfigUpdate(&cb.Envelope{Payload: []byte("garbage")}, update, defaultInitializer())
+		assert.Error(t, err)
+	})
+
+	t.Run("OtherChannel", func(t *testing.T) {
+		other := makeConfigUpdateEnvelope("other", makeConfigSet(), makeConfigSet(makeConfigPair("foo", "foo", 1, []byte("bar"))))
+		_, err := ValidateConfigUpdate(config, other, defaultInitializer())
+		assert.Error(t, err)
+	})
+
+	t.Run("StaleReadSet", func(t *testing.T) {
+		stale := makeConfigUpdateEnvelope(defaultChain, makeConfigSet(makeConfigPair("foo", "foo", 1, nil)), makeConfigSet(makeConfigPair("foo", "foo", 2, []byte("bar"))))
+		_, err := ValidateConfigUpdate(config, stale, defaultInitializer())
+		assert.Error(t, err)
+	})
+
+	t.Run("PolicyViolated", func(t *testing.T) {
+		initializer := defaultInitializer()
+		initializer.Resources.PolicyManagerVal.Policy.Err = fmt.Errorf("err")
+		_, err := ValidateConfigUpdate(config, update, initializer)
+		assert.Error(t, err)
+	})
+}
@@ -73,3 +73,10 @@ type BlockStore interface {
 	RetrieveTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
 	Shutdown()
 }
+
+// BootstrappableBlockStore - a `BlockStore` whose chain may start from a later block than the genesis block,
+// to bootstrap the chain from a snapshot of it
+type BootstrappableBlockStore interface {
+	BlockStore
+	BootstrapBlock(block *common.Block) error
+}
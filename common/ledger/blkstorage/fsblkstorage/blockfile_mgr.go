@@ -198,8 +198,9 @@ func syncCPInfoFromFS(rootDir string, cpInfo *checkpointInfo) {
 		return
 	}
 	//Scan the file system to verify that the checkpoint info stored in db is correct
+	startingOffset := int64(cpInfo.latestFileChunksize)
 	endOffsetLastBlock, numBlocks, err := scanForLastCompleteBlock(
-		rootDir, cpInfo.latestFileChunkSuffixNum, int64(cpInfo.latestFileChunksize))
+		rootDir, cpInfo.latestFileChunkSuffixNum, startingOffset)
 	if err != nil {
 		panic(fmt.Sprintf("Could not open current file for detecting last block in the file: %s", err))
 	}
@@ -209,7 +210,12 @@ func syncCPInfoFromFS(rootDir string, cpInfo *checkpointInfo) {
 	}
 	//Updates the checkpoint info for the actual last block number stored and it's end location
 	if cpInfo.isChainEmpty {
-		cpInfo.lastBlockNumber = uint64(numBlocks - 1)
+		// The first block is not the genesis block if the chain was bootstrapped from a later block
+		firstBlockNum, err := scanForFirstBlockNumber(rootDir, cpInfo.latestFileChunkSuffixNum, startingOffset)
+		if err != nil {
+			panic(fmt.Sprintf("Could not read the first block of the current file: %s", err))
+		}
+		cpInfo.lastBlockNumber = firstBlockNum + uint64(numBlocks-1)
 	} else {
 		cpInfo.lastBlockNumber += uint64(numBlocks)
 	}
@@ -254,6 +260,19 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	if block.Header.Number != mgr.getBlockchainInfo().Height {
 		return fmt.Errorf("Block number should have been %d but was %d", mgr.getBlockchainInfo().Height, block.Header.Number)
 	}
+	return mgr.appendBlock(block)
+}
+
+// bootstrapBlock adds the first block of an empty chain, which may be a later block than the genesis block
+// to bootstrap the chain from it
+func (mgr *blockfileMgr) bootstrapBlock(block *common.Block) error {
+	if !mgr.cpInfo.isChainEmpty {
+		return fmt.Errorf("Cannot bootstrap a chain of height %d from block %d", mgr.getBlockchainInfo().Height, block.Header.Number)
+	}
+	return mgr.appendBlock(block)
+}
+
+func (mgr *blockfileMgr) appendBlock(block *common.Block) error {
 	blockBytes, info, err := serializeBlock(block)
 	if err != nil {
 		return fmt.Errorf("Error while serializing block: %s", err)
@@ -424,9 +443,8 @@ func (mgr *blockfileMgr) updateCheckpoint(cpInfo *checkpointInfo) {
 }
 
 func (mgr *blockfileMgr) updateBlockchainInfo(latestBlockHash []byte, latestBlock *common.Block) {
-	currentBCInfo := mgr.getBlockchainInfo()
 	newBCInfo := &common.BlockchainInfo{
-		Height:            currentBCInfo.Height + 1,
+		Height:            latestBlock.Header.Number + 1,
 		CurrentBlockHash:  latestBlockHash,
 		PreviousBlockHash: latestBlock.Header.PreviousHash}
 
@@ -617,6 +635,27 @@ func scanForLastCompleteBlock(rootDir string, fileNum int, startingOffset int64)
 	return blockStream.currentOffset, numBlocks, errRead
 }
 
+// scanForFirstBlockNumber returns the number of the block starting at the passed offset of the passed file number suffix
+func scanForFirstBlockNumber(rootDir string, fileNum int, startingOffset int64) (uint64, error) {
+	blockStream, err := newBlockfileStream(rootDir, fileNum, startingOffset)
+	if err != nil {
+		return 0, err
+	}
+	defer blockStream.close()
+	blockBytes, err := blockStream.nextBlockBytes()
+	if err != nil {
+		return 0, err
+	}
+	if blockBytes == nil {
+		return 0, fmt.Errorf("No block found at offset %d of file %d", startingOffset, fileNum)
+	}
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return 0, err
+	}
+	return info.blockHeader.Number, nil
+}
+
 // checkpointInfo
 type checkpointInfo struct {
 	latestFileChunkSuffixNum int
@@ -204,6 +204,35 @@ func TestBlockfileMgrRestart(t *testing.T) {
 	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height, expectedHeight)
 }
 
+func TestBlockfileMgrBootstrapFromLaterBlock(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	testutil.AssertError(t, blkfileMgrWrapper.blockfileMgr.addBlock(blocks[5]), "Block 5 should not be added as the first block")
+	testutil.AssertNoError(t, blkfileMgrWrapper.blockfileMgr.bootstrapBlock(blocks[5]), "Error while bootstrapping from block 5")
+	testutil.AssertError(t, blkfileMgrWrapper.blockfileMgr.bootstrapBlock(blocks[6]), "Block 6 should not bootstrap a chain that is not empty")
+	blkfileMgrWrapper.addBlocks(blocks[6:7])
+	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height, uint64(7))
+	testutil.AssertError(t, blkfileMgrWrapper.blockfileMgr.addBlock(blocks[8]), "Block 8 should not follow block 6")
+
+	// simulate a crash before the checkpoint of the blocks was saved
+	blkfileMgrWrapper.blockfileMgr.saveCurrentInfo(&checkpointInfo{0, 0, true, 0}, true)
+	blkfileMgrWrapper.close()
+
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	testutil.AssertEquals(t, int(blkfileMgrWrapper.blockfileMgr.cpInfo.lastBlockNumber), 6)
+	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height, uint64(7))
+	blkfileMgrWrapper.addBlocks(blocks[7:])
+	blkfileMgrWrapper.testGetBlockByHash(blocks[5:])
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[5:], 5)
+	_, err := blkfileMgrWrapper.blockfileMgr.retrieveBlockByNumber(4)
+	testutil.AssertError(t, err, "Block 4 should not be found")
+	testutil.AssertEquals(t, blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height, uint64(10))
+}
+
 func TestBlockfileMgrFileRolling(t *testing.T) {
 	blocks := testutil.ConstructTestBlocks(t, 200)
 	size := 0
@@ -117,7 +117,10 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 
 	//Index3 Used to find a transaction by it's transaction id
 	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxID]; ok {
-		for _, txoffset := range txOffsets {
+		for idx, txoffset := range txOffsets {
+			if isDuplicateTx(txsfltr, idx) {
+				continue
+			}
 			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
 			logger.Debugf("Adding txLoc [%s] for tx ID: [%s] to index", txFlp, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
@@ -143,7 +146,10 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 
 	// Index5 - Store BlockNumber will be used to find block by transaction id
 	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockTxID]; ok {
-		for _, txoffset := range txOffsets {
+		for idx, txoffset := range txOffsets {
+			if isDuplicateTx(txsfltr, idx) {
+				continue
+			}
 			batch.Put(constructBlockTxIDKey(txoffset.txID), flpBytes)
 		}
 	}
@@ -151,6 +157,9 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	// Index6 - Store transaction validation result by transaction id
 	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxValidationCode]; ok {
 		for idx, txoffset := range txOffsets {
+			if isDuplicateTx(txsfltr, idx) {
+				continue
+			}
 			batch.Put(constructTxValidationCodeIDKey(txoffset.txID), []byte{byte(txsfltr.Flag(idx))})
 		}
 	}
@@ -162,6 +171,13 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	return nil
 }
 
+// isDuplicateTx tells whether the committer marked the transaction as a replay
+// of a previous transaction. The entries indexed by transaction id are those of
+// the original transaction, which the duplicates must not overwrite
+func isDuplicateTx(txsfltr ledgerUtil.TxValidationFlags, idx int) bool {
+	return idx < len(txsfltr) && txsfltr.IsSetTo(idx, peer.TxValidationCode_DUPLICATE_TXID)
+}
+
 func (index *blockIndex) getBlockLocByHash(blockHash []byte) (*fileLocPointer, error) {
 	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockHash]; !ok {
 		return nil, blkstorage.ErrAttrNotIndexed
@@ -252,7 +268,7 @@ func (index *blockIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidati
 	if err != nil {
 		return peer.TxValidationCode(-1), err
 	} else if raw == nil {
-		return peer.TxValidationCode(-1), blkstorage.ErrAttrNotIndexed
+		return peer.TxValidationCode(-1), blkstorage.ErrNotFoundInIndex
 	} else if len(raw) != 1 {
 		return peer.TxValidationCode(-1), errors.New("Invalid value in indexItems")
 	}
@@ -223,3 +223,38 @@ func testBlockIndexSelectiveIndexing(t *testing.T, indexItems []blkstorage.Index
 		}
 	})
 }
+
+func TestBlockIndexDuplicateTx(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testledger")
+	defer blkfileMgrWrapper.close()
+
+	// replay the first transaction of the first block in the last block,
+	// which the committer marked as a duplicate
+	blocks := testutil.ConstructTestBlocks(t, 3)
+	blocks[2].Data.Data[0] = blocks[0].Data.Data[0]
+	util.TxValidationFlags(blocks[2].Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]).SetFlag(0, peer.TxValidationCode_DUPLICATE_TXID)
+	blkfileMgrWrapper.addBlocks(blocks)
+	blockfileMgr := blkfileMgrWrapper.blockfileMgr
+
+	txid, err := extractTxID(blocks[0].Data.Data[0])
+	testutil.AssertNoError(t, err, "")
+
+	// the transaction id still refers to the original transaction
+	block, err := blockfileMgr.retrieveBlockByTxID(txid)
+	testutil.AssertNoError(t, err, "Error while retrieving block by txID")
+	testutil.AssertEquals(t, block, blocks[0])
+
+	reason, err := blockfileMgr.retrieveTxValidationCodeByTxID(txid)
+	testutil.AssertNoError(t, err, "Error while retrieving tx validation code by txID")
+	testutil.AssertEquals(t, reason, peer.TxValidationCode_VALID)
+
+	txLoc, err := blockfileMgr.index.getTxLoc(txid)
+	testutil.AssertNoError(t, err, "Error while retrieving tx location by txID")
+	nextBlockLoc, err := blockfileMgr.index.getBlockLocByBlockNum(1)
+	testutil.AssertNoError(t, err, "Error while retrieving block location")
+	if txLoc.offset >= nextBlockLoc.offset {
+		t.Fatalf("The transaction should have been located in the first block")
+	}
+}
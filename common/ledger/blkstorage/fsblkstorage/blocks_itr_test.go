@@ -52,6 +52,22 @@ func TestBlocksItrBlockingNext(t *testing.T) {
 	<-doneChan
 }
 
+func TestBlocksItrCloseBeforeNext(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+
+	blkfileMgrWrapper.addBlocks(testutil.ConstructTestBlocks(t, 2))
+	itr, err := blkfileMgr.retrieveBlocks(1)
+	testutil.AssertNoError(t, err, "")
+	itr.Close()
+	bh, err := itr.Next()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, bh)
+}
+
 func testIterateAndVerify(t *testing.T, itr *blocksItr, blocks []*common.Block, doneChan chan bool) {
 	blocksIterated := 0
 	for {
@@ -16,7 +16,11 @@ limitations under the License.
 
 package fsblkstorage
 
-import "path/filepath"
+import (
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/common/ledger/util"
+)
 
 const (
 	// ChainsDir is the name of the directory containing the channel ledgers.
@@ -30,6 +34,7 @@ const (
 type Conf struct {
 	blockStorageDir  string
 	maxBlockfileSize int
+	ledgerDirs       func(ledgerid string) string
 }
 
 // NewConf constructs new `Conf`.
@@ -38,7 +43,17 @@ func NewConf(blockStorageDir string, maxBlockfileSize int) *Conf {
 	if maxBlockfileSize <= 0 {
 		maxBlockfileSize = defaultMaxBlockfileSize
 	}
-	return &Conf{blockStorageDir, maxBlockfileSize}
+	return &Conf{blockStorageDir: blockStorageDir, maxBlockfileSize: maxBlockfileSize}
+}
+
+// NewConfWithLedgerDirs constructs new `Conf` which keeps the blocks of a
+// ledger under the folder ledgerDirs returns for it, for instance on a
+// dedicated volume, or under blockStorageDir if it returns an empty string.
+// The block indexes of all ledgers stay under blockStorageDir
+func NewConfWithLedgerDirs(blockStorageDir string, maxBlockfileSize int, ledgerDirs func(ledgerid string) string) *Conf {
+	conf := NewConf(blockStorageDir, maxBlockfileSize)
+	conf.ledgerDirs = ledgerDirs
+	return conf
 }
 
 func (conf *Conf) getIndexDir() string {
@@ -50,5 +65,23 @@ func (conf *Conf) getChainsDir() string {
 }
 
 func (conf *Conf) getLedgerBlockDir(ledgerid string) string {
-	return filepath.Join(conf.getChainsDir(), ledgerid)
+	defaultDir := filepath.Join(conf.getChainsDir(), ledgerid)
+	if conf.ledgerDirs == nil {
+		return defaultDir
+	}
+	ledgerDir := conf.ledgerDirs(ledgerid)
+	if ledgerDir == "" {
+		return defaultDir
+	}
+	dir := filepath.Join(ledgerDir, ledgerid)
+
+	// the blocks of a ledger created before its folder was configured stay
+	// where they are until moved
+	if exists, _, _ := util.FileExists(dir); !exists {
+		if exists, _, _ = util.FileExists(defaultDir); exists {
+			logger.Warningf("Blocks of ledger [%s] are kept under [%s] instead of [%s] until moved", ledgerid, defaultDir, dir)
+			return defaultDir
+		}
+	}
+	return dir
 }
@@ -43,6 +43,11 @@ func (store *fsBlockStore) AddBlock(block *common.Block) error {
 	return store.fileMgr.addBlock(block)
 }
 
+// BootstrapBlock adds the first block of an empty chain, which may be a later block than the genesis block
+func (store *fsBlockStore) BootstrapBlock(block *common.Block) error {
+	return store.fileMgr.bootstrapBlock(block)
+}
+
 // GetBlockchainInfo returns the current info about blockchain
 func (store *fsBlockStore) GetBlockchainInfo() (*common.BlockchainInfo, error) {
 	return store.fileMgr.getBlockchainInfo(), nil
@@ -54,7 +54,8 @@ func (p *FsBlockstoreProvider) Exists(ledgerid string) (bool, error) {
 	return exists, err
 }
 
-// List lists the ids of the existing ledgers
+// List lists the ids of the existing ledgers, except the ones kept out of the
+// block storage folder of the provider
 func (p *FsBlockstoreProvider) List() ([]string, error) {
 	return util.ListSubdirs(p.conf.getChainsDir())
 }
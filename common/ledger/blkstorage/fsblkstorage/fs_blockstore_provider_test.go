@@ -17,13 +17,14 @@ limitations under the License.
 package fsblkstorage
 
 import (
-	"testing"
-
 	"fmt"
+	"path/filepath"
+	"testing"
 
 	"github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 )
 
@@ -51,6 +52,51 @@ func TestMultipleBlockStores(t *testing.T) {
 	checkBlocks(t, blocks2, store2)
 }
 
+func TestBlockStoresWithLedgerDirs(t *testing.T) {
+	root := testPath()
+	ledgerDir := filepath.Join(root, "volume")
+
+	// ledger1 is created before being configured in its own folder
+	env := newTestEnv(t, NewConf(root, 0))
+	defer env.Cleanup()
+	store1, _ := env.provider.OpenBlockStore("ledger1")
+	blocks1 := testutil.ConstructTestBlocks(t, 5)
+	for _, b := range blocks1 {
+		store1.AddBlock(b)
+	}
+	store1.Shutdown()
+	indexConfig := env.provider.indexConfig
+	env.provider.Close()
+
+	conf := NewConfWithLedgerDirs(root, 0, func(ledgerid string) string {
+		if ledgerid == "ledger1" || ledgerid == "ledger2" {
+			return ledgerDir
+		}
+		return ""
+	})
+	env.provider = NewProvider(conf, indexConfig).(*FsBlockstoreProvider)
+	store1, _ = env.provider.OpenBlockStore("ledger1")
+	defer store1.Shutdown()
+	checkBlocks(t, blocks1, store1)
+
+	store2, _ := env.provider.OpenBlockStore("ledger2")
+	defer store2.Shutdown()
+	blocks2 := testutil.ConstructTestBlocks(t, 3)
+	for _, b := range blocks2 {
+		store2.AddBlock(b)
+	}
+	checkBlocks(t, blocks2, store2)
+
+	exists, _, err := util.FileExists(filepath.Join(ledgerDir, "ledger2"))
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, exists, true)
+	exists, _, _ = util.FileExists(filepath.Join(ledgerDir, "ledger1"))
+	testutil.AssertEquals(t, exists, false)
+	exists, err = env.provider.Exists("ledger2")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, exists, true)
+}
+
 func checkBlocks(t *testing.T, expectedBlocks []*common.Block, store blkstorage.BlockStore) {
 	bcInfo, _ := store.GetBlockchainInfo()
 	testutil.AssertEquals(t, bcInfo.Height, uint64(len(expectedBlocks)))
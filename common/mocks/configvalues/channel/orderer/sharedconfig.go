@@ -16,13 +16,19 @@ limitations under the License.
 
 package sharedconfig
 
-import ab "github.com/hyperledger/fabric/protos/orderer"
-import "time"
+import (
+	"time"
+
+	"github.com/hyperledger/fabric/common/config"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+)
 
 // SharedConfig is a mock implementation of sharedconfig.SharedConfig
 type SharedConfig struct {
 	// ConsensusTypeVal is returned as the result of ConsensusType()
 	ConsensusTypeVal string
+	// ConsensusStateVal is returned as the result of ConsensusState()
+	ConsensusStateVal ab.ConsensusType_State
 	// BatchSizeVal is returned as the result of BatchSize()
 	BatchSizeVal *ab.BatchSize
 	// BatchTimeoutVal is returned as the result of BatchTimeout()
@@ -37,6 +43,8 @@ type SharedConfig struct {
 	EgressPolicyNamesVal []string
 	// MaxChannelsCountVal is returns as the result of MaxChannelsCount()
 	MaxChannelsCountVal uint64
+	// OrganizationsVal is returned as the result of Organizations()
+	OrganizationsVal map[string]config.OrdererOrg
 }
 
 // ConsensusType returns the ConsensusTypeVal
@@ -44,6 +52,11 @@ func (scm *SharedConfig) ConsensusType() string {
 	return scm.ConsensusTypeVal
 }
 
+// ConsensusState returns the ConsensusStateVal
+func (scm *SharedConfig) ConsensusState() ab.ConsensusType_State {
+	return scm.ConsensusStateVal
+}
+
 // BatchSize returns the BatchSizeVal
 func (scm *SharedConfig) BatchSize() *ab.BatchSize {
 	return scm.BatchSizeVal
@@ -69,6 +82,11 @@ func (scm *SharedConfig) MaxChannelsCount() uint64 {
 	return scm.MaxChannelsCountVal
 }
 
+// Organizations returns the OrganizationsVal
+func (scm *SharedConfig) Organizations() map[string]config.OrdererOrg {
+	return scm.OrganizationsVal
+}
+
 // IngressPolicyNames returns the IngressPolicyNamesVal
 func (scm *SharedConfig) IngressPolicyNames() []string {
 	return scm.IngressPolicyNamesVal
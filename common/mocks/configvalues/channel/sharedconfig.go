@@ -16,7 +16,11 @@ limitations under the License.
 
 package channel
 
-import "github.com/hyperledger/fabric/common/util"
+import (
+	"github.com/hyperledger/fabric/common/config"
+	"github.com/hyperledger/fabric/common/util"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+)
 
 func nearIdentityHash(input []byte) []byte {
 	return util.ConcatenateBytes([]byte("FakeHash("), input, []byte(""))
@@ -30,6 +34,10 @@ type SharedConfig struct {
 	BlockDataHashingStructureWidthVal uint32
 	// OrdererAddressesVal is returned as the result of OrdererAddresses()
 	OrdererAddressesVal []string
+	// ConsortiumNameVal is returned as the result of ConsortiumName()
+	ConsortiumNameVal string
+	// ConsortiumsVal is returned as the result of Consortiums()
+	ConsortiumsVal map[string]config.Consortium
 }
 
 // HashingAlgorithm returns the HashingAlgorithmVal if set, otherwise a fake simple hash function
@@ -49,3 +57,49 @@ func (scm *SharedConfig) BlockDataHashingStructureWidth() uint32 {
 func (scm *SharedConfig) OrdererAddresses() []string {
 	return scm.OrdererAddressesVal
 }
+
+// ConsortiumName returns the ConsortiumNameVal
+func (scm *SharedConfig) ConsortiumName() string {
+	return scm.ConsortiumNameVal
+}
+
+// Consortiums returns the ConsortiumsVal
+func (scm *SharedConfig) Consortiums() map[string]config.Consortium {
+	return scm.ConsortiumsVal
+}
+
+// Consortium is a mock implementation of config.Consortium
+type Consortium struct {
+	// OrganizationsVal is returned as the result of Organizations()
+	OrganizationsVal map[string]config.Org
+}
+
+// Organizations returns the OrganizationsVal
+func (c *Consortium) Organizations() map[string]config.Org {
+	return c.OrganizationsVal
+}
+
+// Org is a mock implementation of config.Org
+type Org struct {
+	// NameVal is returned as the result of Name()
+	NameVal string
+	// MSPIDVal is returned as the result of MSPID()
+	MSPIDVal string
+	// MSPConfigVal is returned as the result of MSPConfig()
+	MSPConfigVal *mspprotos.MSPConfig
+}
+
+// Name returns the NameVal
+func (o *Org) Name() string {
+	return o.NameVal
+}
+
+// MSPID returns the MSPIDVal
+func (o *Org) MSPID() string {
+	return o.MSPIDVal
+}
+
+// MSPConfig returns the MSPConfigVal
+func (o *Org) MSPConfig() *mspprotos.MSPConfig {
+	return o.MSPConfigVal
+}
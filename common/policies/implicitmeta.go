@@ -18,6 +18,7 @@ package policies
 
 import (
 	"fmt"
+	"strings"
 
 	cb "github.com/hyperledger/fabric/protos/common"
 
@@ -81,3 +82,35 @@ func (imp *implicitMetaPolicy) Evaluate(signatureSet []*cb.SignedData) error {
 	}
 	return fmt.Errorf("Failed to reach implicit threshold of %d sub-policies, required %d remaining", imp.threshold, remaining)
 }
+
+// NewImplicitMetaPolicyOf creates an implicit meta policy evaluating the sub
+// policy of the sub managers of the manager, the way the implicit meta
+// policies of the config are evaluated. The sub policies are resolved when the
+// policy is created, so it should not be kept across config updates
+func NewImplicitMetaPolicyOf(manager Manager, conf *cb.ImplicitMetaPolicy) (Policy, error) {
+	pm, ok := manager.(*ManagerImpl)
+	if !ok {
+		return nil, fmt.Errorf("Implicit meta policies can only be resolved against a config policy manager, not %T", manager)
+	}
+	if len(pm.config.managers) == 0 {
+		return nil, fmt.Errorf("No sub manager of %s to evaluate the implicit meta policy against", pm.basePath)
+	}
+
+	imp := &implicitMetaPolicy{conf: conf}
+	imp.initialize(pm.config)
+	return imp, nil
+}
+
+// ImplicitMetaFromString parses an implicit meta policy of the form
+// "<ANY|ALL|MAJORITY> <SubPolicy>", e.g. "MAJORITY Admins"
+func ImplicitMetaFromString(policy string) (*cb.ImplicitMetaPolicy, error) {
+	fields := strings.Fields(policy)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("Implicit meta policy %q is not of the form \"<ANY|ALL|MAJORITY> <SubPolicy>\"", policy)
+	}
+	rule, ok := cb.ImplicitMetaPolicy_Rule_value[fields[0]]
+	if !ok {
+		return nil, fmt.Errorf("Unknown implicit meta policy rule %s", fields[0])
+	}
+	return &cb.ImplicitMetaPolicy{Rule: cb.ImplicitMetaPolicy_Rule(rule), SubPolicy: fields[1]}, nil
+}
@@ -99,3 +99,35 @@ func TestImplicitMetaMajority(t *testing.T) {
 	assert.Error(t, runPolicyTest(cb.ImplicitMetaPolicy_MAJORITY, 10, 0))
 	assert.NoError(t, runPolicyTest(cb.ImplicitMetaPolicy_MAJORITY, 0, 0))
 }
+
+func TestNewImplicitMetaPolicyOf(t *testing.T) {
+	conf := &cb.ImplicitMetaPolicy{Rule: cb.ImplicitMetaPolicy_MAJORITY, SubPolicy: TestPolicyName}
+
+	pm := &ManagerImpl{basePath: "Application", config: &policyConfig{managers: makeManagers(3, 2)}}
+	imp, err := NewImplicitMetaPolicyOf(pm, conf)
+	assert.NoError(t, err)
+	assert.NoError(t, imp.Evaluate(nil))
+
+	pm = &ManagerImpl{basePath: "Application", config: &policyConfig{managers: makeManagers(3, 1)}}
+	imp, err = NewImplicitMetaPolicyOf(pm, conf)
+	assert.NoError(t, err)
+	assert.Error(t, imp.Evaluate(nil))
+
+	_, err = NewImplicitMetaPolicyOf(&ManagerImpl{basePath: "Application", config: &policyConfig{}}, conf)
+	assert.Error(t, err, "An implicit meta policy without sub managers should not be satisfied trivially")
+}
+
+func TestImplicitMetaFromString(t *testing.T) {
+	imp, err := ImplicitMetaFromString("MAJORITY Admins")
+	assert.NoError(t, err)
+	assert.Equal(t, &cb.ImplicitMetaPolicy{Rule: cb.ImplicitMetaPolicy_MAJORITY, SubPolicy: "Admins"}, imp)
+
+	imp, err = ImplicitMetaFromString("  ANY   Readers ")
+	assert.NoError(t, err)
+	assert.Equal(t, &cb.ImplicitMetaPolicy{Rule: cb.ImplicitMetaPolicy_ANY, SubPolicy: "Readers"}, imp)
+
+	for _, policy := range []string{"", "MAJORITY", "SOME Admins", "MAJORITY Admins Readers", "AND('Org1.admin')"} {
+		_, err = ImplicitMetaFromString(policy)
+		assert.Error(t, err, "%q should not be parsed", policy)
+	}
+}
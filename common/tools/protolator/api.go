@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protolator
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+// The interfaces below are implemented by the messages whose bytes fields
+// hold other marshaled messages, so that these fields are rendered as nested
+// JSON instead of base64 strings. Fields are designated by their name in the
+// .proto file. A factory returning a nil message leaves the field as bytes.
+
+// StaticallyOpaqueFieldProto is implemented by messages with bytes fields
+// always holding a message of the same type
+type StaticallyOpaqueFieldProto interface {
+	// StaticallyOpaqueFields returns the names of the statically opaque fields
+	StaticallyOpaqueFields() []string
+
+	// StaticallyOpaqueFieldProto returns a new message of the type held by the field
+	StaticallyOpaqueFieldProto(name string) (proto.Message, error)
+}
+
+// StaticallyOpaqueSliceFieldProto is implemented by messages with repeated
+// bytes fields always holding messages of the same type
+type StaticallyOpaqueSliceFieldProto interface {
+	// StaticallyOpaqueSliceFields returns the names of the statically opaque repeated fields
+	StaticallyOpaqueSliceFields() []string
+
+	// StaticallyOpaqueSliceFieldProto returns a new message of the type held by the
+	// element of the field at the index
+	StaticallyOpaqueSliceFieldProto(name string, index int) (proto.Message, error)
+}
+
+// VariablyOpaqueFieldProto is implemented by messages with bytes fields holding
+// a message whose type depends on the other fields of the message. When decoding
+// JSON, VariablyOpaqueFieldProto is called once all the other fields are set
+type VariablyOpaqueFieldProto interface {
+	// VariablyOpaqueFields returns the names of the variably opaque fields
+	VariablyOpaqueFields() []string
+
+	// VariablyOpaqueFieldProto returns a new message of the type held by the field
+	VariablyOpaqueFieldProto(name string) (proto.Message, error)
+}
+
+// VariablyOpaqueSliceFieldProto is implemented by messages with repeated bytes
+// fields holding messages whose type depends on their index or on the other
+// fields of the message
+type VariablyOpaqueSliceFieldProto interface {
+	// VariablyOpaqueSliceFields returns the names of the variably opaque repeated fields
+	VariablyOpaqueSliceFields() []string
+
+	// VariablyOpaqueSliceFieldProto returns a new message of the type held by the
+	// element of the field at the index
+	VariablyOpaqueSliceFieldProto(name string, index int) (proto.Message, error)
+}
+
+// DynamicFieldProto is implemented by messages with message fields whose own
+// opaque fields can only be decoded in the context of the enclosing message
+type DynamicFieldProto interface {
+	// DynamicFields returns the names of the dynamic fields
+	DynamicFields() []string
+
+	// DynamicFieldProto returns the message of the field, usually wrapped in a
+	// DecoratedProto giving it the context of the enclosing message
+	DynamicFieldProto(name string, base proto.Message) (proto.Message, error)
+}
+
+// DynamicMapFieldProto is implemented by messages with map fields of messages
+// whose own opaque fields can only be decoded knowing their key and the
+// enclosing message
+type DynamicMapFieldProto interface {
+	// DynamicMapFields returns the names of the dynamic map fields
+	DynamicMapFields() []string
+
+	// DynamicMapFieldProto returns the message at key in the field, usually
+	// wrapped in a DecoratedProto giving it the context of its key
+	DynamicMapFieldProto(name string, key string, base proto.Message) (proto.Message, error)
+}
+
+// DecoratedProto wraps a message to give it the context needed to decode its
+// opaque fields. The fields rendered are those of the underlying message, the
+// interfaces above are looked up on the DecoratedProto. Messages implementing
+// these interfaces need not import this package: the methods returning a
+// DecoratedProto are declared as returning a proto.Message
+type DecoratedProto interface {
+	proto.Message
+
+	// Underlying returns the wrapped message
+	Underlying() proto.Message
+}
@@ -0,0 +1,564 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protolator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// DeepMarshalJSON writes msg to w as JSON, rendering the messages marshaled
+// in its opaque fields, and recursively theirs, as nested JSON objects. Fields
+// are named as in the .proto files. Oneof fields and the fields of
+// well-known types are rendered as by jsonpb, without descending into them.
+// The messages of protos/peer and protos/orderer carried by the messages of
+// protos/common are only rendered if these packages are linked in
+func DeepMarshalJSON(w io.Writer, msg proto.Message) error {
+	root, err := recursivelyCreateTreeFromMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(root)
+}
+
+// DeepUnmarshalJSON reads the JSON produced by DeepMarshalJSON from r into
+// msg, marshaling back the nested messages into the opaque fields. Messages
+// with map fields are not marshaled deterministically, so the opaque fields
+// holding them, and the signatures over these fields, may differ from the
+// ones of the message originally rendered
+func DeepUnmarshalJSON(r io.Reader, msg proto.Message) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	tree := map[string]interface{}{}
+	if err := decoder.Decode(&tree); err != nil {
+		return err
+	}
+	return recursivelyPopulateMessageFromTree(tree, msg)
+}
+
+type fieldKind int
+
+const (
+	plainField fieldKind = iota
+	nestedField
+	staticallyOpaqueField
+	staticallyOpaqueSliceField
+	variablyOpaqueField
+	variablyOpaqueSliceField
+	dynamicField
+	dynamicMapField
+)
+
+// protoField is a field of the struct of a message
+type protoField struct {
+	name     string
+	jsonName string
+	value    reflect.Value
+}
+
+var protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+type wellKnownType interface {
+	XXX_WellKnownType() string
+}
+
+var wellKnownTypeType = reflect.TypeOf((*wellKnownType)(nil)).Elem()
+
+func underlyingMessage(msg proto.Message) proto.Message {
+	if decorated, ok := msg.(DecoratedProto); ok {
+		return decorated.Underlying()
+	}
+	return msg
+}
+
+func protoFields(msg proto.Message) ([]*protoField, error) {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("message of type %T is not a pointer to a struct", msg)
+	}
+	v = v.Elem()
+
+	sprops := proto.GetProperties(v.Type())
+	var fields []*protoField
+	for i := 0; i < v.NumField(); i++ {
+		structField := v.Type().Field(i)
+		// oneof fields are tagged protobuf_oneof and left to jsonpb
+		if strings.HasPrefix(structField.Name, "XXX_") || structField.Tag.Get("protobuf") == "" {
+			continue
+		}
+		prop := sprops.Prop[i]
+		fields = append(fields, &protoField{name: prop.OrigName, jsonName: prop.JSONName, value: v.Field(i)})
+	}
+	return fields, nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isMessageType returns whether t is the type of a message to descend into
+func isMessageType(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct &&
+		t.Implements(protoMessageType) && !t.Implements(wellKnownTypeType)
+}
+
+func kindOf(msg proto.Message, field *protoField) fieldKind {
+	if m, ok := msg.(StaticallyOpaqueFieldProto); ok && contains(m.StaticallyOpaqueFields(), field.name) {
+		return staticallyOpaqueField
+	}
+	if m, ok := msg.(StaticallyOpaqueSliceFieldProto); ok && contains(m.StaticallyOpaqueSliceFields(), field.name) {
+		return staticallyOpaqueSliceField
+	}
+	if m, ok := msg.(VariablyOpaqueFieldProto); ok && contains(m.VariablyOpaqueFields(), field.name) {
+		return variablyOpaqueField
+	}
+	if m, ok := msg.(VariablyOpaqueSliceFieldProto); ok && contains(m.VariablyOpaqueSliceFields(), field.name) {
+		return variablyOpaqueSliceField
+	}
+	if m, ok := msg.(DynamicFieldProto); ok && contains(m.DynamicFields(), field.name) {
+		return dynamicField
+	}
+	if m, ok := msg.(DynamicMapFieldProto); ok && contains(m.DynamicMapFields(), field.name) {
+		return dynamicMapField
+	}
+
+	t := field.value.Type()
+	switch t.Kind() {
+	case reflect.Ptr:
+		if isMessageType(t) {
+			return nestedField
+		}
+	case reflect.Slice, reflect.Map:
+		if isMessageType(t.Elem()) {
+			return nestedField
+		}
+	}
+	return plainField
+}
+
+func checkFieldType(field *protoField, kind reflect.Kind, elemKind reflect.Kind) error {
+	t := field.value.Type()
+	if t.Kind() != kind || (elemKind != reflect.Invalid && t.Elem().Kind() != elemKind) {
+		return fmt.Errorf("field %s of type %s cannot be decoded as declared", field.name, t)
+	}
+	return nil
+}
+
+// opaqueFactory returns the message held by an opaque field, or by the element
+// at index of an opaque repeated field
+type opaqueFactory func(index int) (proto.Message, error)
+
+func opaqueFactoryFor(msg proto.Message, field *protoField, kind fieldKind) opaqueFactory {
+	switch kind {
+	case staticallyOpaqueField:
+		return func(int) (proto.Message, error) {
+			return msg.(StaticallyOpaqueFieldProto).StaticallyOpaqueFieldProto(field.name)
+		}
+	case staticallyOpaqueSliceField:
+		return func(index int) (proto.Message, error) {
+			return msg.(StaticallyOpaqueSliceFieldProto).StaticallyOpaqueSliceFieldProto(field.name, index)
+		}
+	case variablyOpaqueField:
+		return func(int) (proto.Message, error) {
+			return msg.(VariablyOpaqueFieldProto).VariablyOpaqueFieldProto(field.name)
+		}
+	case variablyOpaqueSliceField:
+		return func(index int) (proto.Message, error) {
+			return msg.(VariablyOpaqueSliceFieldProto).VariablyOpaqueSliceFieldProto(field.name, index)
+		}
+	}
+	return nil
+}
+
+// jsonTree returns the jsonpb rendering of msg as a tree of JSON values
+func jsonTree(msg proto.Message) (map[string]interface{}, error) {
+	var buf bytes.Buffer
+	if err := (&jsonpb.Marshaler{OrigName: true}).Marshal(&buf, msg); err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(&buf)
+	decoder.UseNumber()
+	tree := map[string]interface{}{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func recursivelyCreateTreeFromMessage(msg proto.Message) (map[string]interface{}, error) {
+	underlying := underlyingMessage(msg)
+	fields, err := protoFields(underlying)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := jsonTree(underlying)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range fields {
+		value, ok := tree[field.name]
+		if !ok {
+			continue
+		}
+
+		var subtree interface{}
+		switch kind := kindOf(msg, field); kind {
+		case plainField:
+			continue
+		case nestedField:
+			subtree, err = nestedToTree(field.value)
+		case staticallyOpaqueField, variablyOpaqueField:
+			if err = checkFieldType(field, reflect.Slice, reflect.Uint8); err == nil {
+				subtree, err = opaqueToTree(field.value.Bytes(), opaqueFactoryFor(msg, field, kind), 0, value)
+			}
+		case staticallyOpaqueSliceField, variablyOpaqueSliceField:
+			if err = checkFieldType(field, reflect.Slice, reflect.Slice); err == nil {
+				subtree, err = opaqueSliceToTree(field.value.Interface().([][]byte), opaqueFactoryFor(msg, field, kind), value)
+			}
+		case dynamicField:
+			var decorated proto.Message
+			if decorated, err = msg.(DynamicFieldProto).DynamicFieldProto(field.name, field.value.Interface().(proto.Message)); err == nil {
+				subtree, err = recursivelyCreateTreeFromMessage(decorated)
+			}
+		case dynamicMapField:
+			subtree, err = dynamicMapToTree(msg.(DynamicMapFieldProto), field)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error in field %s of %T: %s", field.name, underlying, err)
+		}
+		tree[field.name] = subtree
+	}
+
+	return tree, nil
+}
+
+func nestedToTree(v reflect.Value) (interface{}, error) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return recursivelyCreateTreeFromMessage(v.Interface().(proto.Message))
+	case reflect.Slice:
+		elements := make([]interface{}, v.Len())
+		for i := range elements {
+			element, err := nestedToTree(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = element
+		}
+		return elements, nil
+	default:
+		entries := map[string]interface{}{}
+		for _, key := range v.MapKeys() {
+			entry, err := nestedToTree(v.MapIndex(key))
+			if err != nil {
+				return nil, err
+			}
+			entries[fmt.Sprint(key.Interface())] = entry
+		}
+		return entries, nil
+	}
+}
+
+func opaqueToTree(opaque []byte, factory opaqueFactory, index int, value interface{}) (interface{}, error) {
+	msg, err := factory(index)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return value, nil
+	}
+	if err = proto.Unmarshal(opaque, underlyingMessage(msg)); err != nil {
+		return nil, err
+	}
+	return recursivelyCreateTreeFromMessage(msg)
+}
+
+func opaqueSliceToTree(opaques [][]byte, factory opaqueFactory, value interface{}) (interface{}, error) {
+	values, ok := value.([]interface{})
+	if !ok || len(values) != len(opaques) {
+		return nil, fmt.Errorf("unexpected rendering of repeated field")
+	}
+	elements := make([]interface{}, len(opaques))
+	for i, opaque := range opaques {
+		element, err := opaqueToTree(opaque, factory, i, values[i])
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = element
+	}
+	return elements, nil
+}
+
+func dynamicMapToTree(msg DynamicMapFieldProto, field *protoField) (interface{}, error) {
+	if err := checkFieldType(field, reflect.Map, reflect.Ptr); err != nil {
+		return nil, err
+	}
+	entries := map[string]interface{}{}
+	for _, key := range field.value.MapKeys() {
+		if key.Kind() != reflect.String {
+			return nil, fmt.Errorf("dynamic map fields must have string keys")
+		}
+		decorated, err := msg.DynamicMapFieldProto(field.name, key.String(), field.value.MapIndex(key).Interface().(proto.Message))
+		if err != nil {
+			return nil, err
+		}
+		entry, err := recursivelyCreateTreeFromMessage(decorated)
+		if err != nil {
+			return nil, err
+		}
+		entries[key.String()] = entry
+	}
+	return entries, nil
+}
+
+// pendingField is a field decoded after the plain fields of its message
+type pendingField struct {
+	field *protoField
+	kind  fieldKind
+	value interface{}
+}
+
+func recursivelyPopulateMessageFromTree(tree map[string]interface{}, msg proto.Message) error {
+	underlying := underlyingMessage(msg)
+	fields, err := protoFields(underlying)
+	if err != nil {
+		return err
+	}
+
+	// the plain fields are decoded first by jsonpb, then the fields holding
+	// messages, and last the variably opaque ones, which may depend on any other
+	plain := map[string]interface{}{}
+	for key, value := range tree {
+		plain[key] = value
+	}
+	var pending, variablyOpaque []*pendingField
+	for _, field := range fields {
+		key := field.name
+		value, ok := tree[key]
+		if !ok {
+			key = field.jsonName
+			if value, ok = tree[key]; !ok {
+				continue
+			}
+		}
+		kind := kindOf(msg, field)
+		if kind == plainField {
+			continue
+		}
+		delete(plain, key)
+		if value == nil {
+			continue
+		}
+		if kind == variablyOpaqueField || kind == variablyOpaqueSliceField {
+			variablyOpaque = append(variablyOpaque, &pendingField{field, kind, value})
+		} else {
+			pending = append(pending, &pendingField{field, kind, value})
+		}
+	}
+
+	plainJSON, err := json.Marshal(plain)
+	if err != nil {
+		return err
+	}
+	if err = jsonpb.Unmarshal(bytes.NewReader(plainJSON), underlying); err != nil {
+		return fmt.Errorf("error decoding %T: %s", underlying, err)
+	}
+
+	for _, p := range append(pending, variablyOpaque...) {
+		if err = populateField(msg, p); err != nil {
+			return fmt.Errorf("error in field %s of %T: %s", p.field.name, underlying, err)
+		}
+	}
+	return nil
+}
+
+func populateField(msg proto.Message, p *pendingField) error {
+	field := p.field
+	switch p.kind {
+	case nestedField:
+		v, err := nestedFromTree(field.value.Type(), p.value)
+		if err != nil {
+			return err
+		}
+		field.value.Set(v)
+	case staticallyOpaqueField, variablyOpaqueField:
+		if err := checkFieldType(field, reflect.Slice, reflect.Uint8); err != nil {
+			return err
+		}
+		opaque, err := opaqueFromTree(opaqueFactoryFor(msg, field, p.kind), 0, p.value)
+		if err != nil {
+			return err
+		}
+		field.value.SetBytes(opaque)
+	case staticallyOpaqueSliceField, variablyOpaqueSliceField:
+		if err := checkFieldType(field, reflect.Slice, reflect.Slice); err != nil {
+			return err
+		}
+		values, ok := p.value.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected a JSON array")
+		}
+		factory := opaqueFactoryFor(msg, field, p.kind)
+		opaques := make([][]byte, len(values))
+		for i, value := range values {
+			opaque, err := opaqueFromTree(factory, i, value)
+			if err != nil {
+				return err
+			}
+			opaques[i] = opaque
+		}
+		field.value.Set(reflect.ValueOf(opaques))
+	case dynamicField:
+		if !isMessageType(field.value.Type()) {
+			return fmt.Errorf("dynamic fields must hold messages")
+		}
+		subtree, ok := p.value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected a JSON object")
+		}
+		base := reflect.New(field.value.Type().Elem())
+		decorated, err := msg.(DynamicFieldProto).DynamicFieldProto(field.name, base.Interface().(proto.Message))
+		if err != nil {
+			return err
+		}
+		if err = recursivelyPopulateMessageFromTree(subtree, decorated); err != nil {
+			return err
+		}
+		field.value.Set(base)
+	case dynamicMapField:
+		if err := checkFieldType(field, reflect.Map, reflect.Ptr); err != nil {
+			return err
+		}
+		entries, ok := p.value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected a JSON object")
+		}
+		mapType := field.value.Type()
+		m := reflect.MakeMap(mapType)
+		for key, value := range entries {
+			subtree, ok := value.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("expected a JSON object for key %s", key)
+			}
+			base := reflect.New(mapType.Elem().Elem())
+			decorated, err := msg.(DynamicMapFieldProto).DynamicMapFieldProto(field.name, key, base.Interface().(proto.Message))
+			if err != nil {
+				return err
+			}
+			if err = recursivelyPopulateMessageFromTree(subtree, decorated); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(mapType.Key()), base)
+		}
+		field.value.Set(m)
+	}
+	return nil
+}
+
+func nestedFromTree(t reflect.Type, value interface{}) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(t), nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		subtree, ok := value.(map[string]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected a JSON object")
+		}
+		v := reflect.New(t.Elem())
+		if err := recursivelyPopulateMessageFromTree(subtree, v.Interface().(proto.Message)); err != nil {
+			return reflect.Value{}, err
+		}
+		return v, nil
+	case reflect.Slice:
+		values, ok := value.([]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected a JSON array")
+		}
+		s := reflect.MakeSlice(t, len(values), len(values))
+		for i, element := range values {
+			v, err := nestedFromTree(t.Elem(), element)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			s.Index(i).Set(v)
+		}
+		return s, nil
+	default:
+		if t.Key().Kind() != reflect.String {
+			return reflect.Value{}, fmt.Errorf("maps of messages must have string keys")
+		}
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected a JSON object")
+		}
+		m := reflect.MakeMap(t)
+		for key, entry := range entries {
+			v, err := nestedFromTree(t.Elem(), entry)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), v)
+		}
+		return m, nil
+	}
+}
+
+func opaqueFromTree(factory opaqueFactory, index int, value interface{}) ([]byte, error) {
+	msg, err := factory(index)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		encoded, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a base64 encoded JSON string")
+		}
+		return base64.StdEncoding.DecodeString(encoded)
+	}
+
+	subtree, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+	if err = recursivelyPopulateMessageFromTree(subtree, msg); err != nil {
+		return nil, err
+	}
+	return proto.Marshal(underlyingMessage(msg))
+}
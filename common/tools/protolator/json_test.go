@@ -0,0 +1,147 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protolator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/protos/msp"
+	_ "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+func endorserTxEnvelope() *common.Envelope {
+	kvRWSet := &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: "key", Value: []byte("value")}}}
+	txRWSet := &rwset.TxReadWriteSet{NsRwset: []*rwset.NsReadWriteSet{{Namespace: "mycc", Rwset: utils.MarshalOrPanic(kvRWSet)}}}
+	creator := utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "SampleOrg", IdBytes: []byte("certificate")})
+
+	ccap := &peer.ChaincodeActionPayload{
+		ChaincodeProposalPayload: utils.MarshalOrPanic(&peer.ChaincodeProposalPayload{
+			Input: utils.MarshalOrPanic(&peer.ChaincodeInvocationSpec{ChaincodeSpec: &peer.ChaincodeSpec{
+				ChaincodeId: &peer.ChaincodeID{Name: "mycc"},
+				Input:       &peer.ChaincodeInput{Args: [][]byte{[]byte("put"), []byte("key"), []byte("value")}},
+			}}),
+		}),
+		Action: &peer.ChaincodeEndorsedAction{
+			ProposalResponsePayload: utils.MarshalOrPanic(&peer.ProposalResponsePayload{
+				ProposalHash: []byte("hash"),
+				Extension: utils.MarshalOrPanic(&peer.ChaincodeAction{
+					Results:  utils.MarshalOrPanic(txRWSet),
+					Response: &peer.Response{Status: 200},
+				}),
+			}),
+			Endorsements: []*peer.Endorsement{{Endorser: creator, Signature: []byte("signature")}},
+		},
+	}
+	tx := &peer.Transaction{Actions: []*peer.TransactionAction{{
+		Header:  utils.MarshalOrPanic(&common.SignatureHeader{Creator: creator, Nonce: []byte("nonce")}),
+		Payload: utils.MarshalOrPanic(ccap),
+	}}}
+
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader:   utils.MarshalOrPanic(&common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION), ChannelId: "mychannel", TxId: "txid"}),
+			SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{Creator: creator, Nonce: []byte("nonce")}),
+		},
+		Data: utils.MarshalOrPanic(tx),
+	}
+	return &common.Envelope{Payload: utils.MarshalOrPanic(payload), Signature: []byte("signature")}
+}
+
+func roundTrip(t *testing.T, msg proto.Message, decoded proto.Message, expected ...string) []byte {
+	buf := &bytes.Buffer{}
+	if err := DeepMarshalJSON(buf, msg); err != nil {
+		t.Fatalf("DeepMarshalJSON failed: %s", err)
+	}
+	for _, e := range expected {
+		if !strings.Contains(buf.String(), e) {
+			t.Fatalf("Expected %s in the JSON of the %T, got %s", e, msg, buf.String())
+		}
+	}
+
+	if err := DeepUnmarshalJSON(bytes.NewReader(buf.Bytes()), decoded); err != nil {
+		t.Fatalf("DeepUnmarshalJSON failed: %s", err)
+	}
+	return buf.Bytes()
+}
+
+func TestEndorserTransaction(t *testing.T) {
+	block := &common.Block{
+		Header:   &common.BlockHeader{Number: 1, DataHash: []byte("hash")},
+		Data:     &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(endorserTxEnvelope())}},
+		Metadata: &common.BlockMetadata{Metadata: [][]byte{{}, {}, {}, {}}},
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&common.Metadata{
+		Value: utils.MarshalOrPanic(&common.LastConfig{Index: 0}),
+	})
+
+	decoded := &common.Block{}
+	roundTrip(t, block, decoded, `"channel_header"`, `"mychannel"`, `"ns_rwset"`, `"writes"`, `"mspid": "SampleOrg"`, `"proposal_hash"`)
+	if !proto.Equal(block, decoded) {
+		t.Fatalf("The block decoded from JSON differs from the original")
+	}
+}
+
+func TestConfigBlock(t *testing.T) {
+	block, err := test.MakeGenesisBlock("mychannel")
+	if err != nil {
+		t.Fatalf("MakeGenesisBlock failed: %s", err)
+	}
+
+	decoded := &common.Block{}
+	rendered := roundTrip(t, block, decoded, `"channel_group"`, `"Orderer"`, `"MSP"`, `"HashingAlgorithm"`, `"BatchSize"`, `"maxMessageCount"`, `"root_certs"`)
+
+	// The config maps are not marshaled deterministically, compare the renderings
+	buf := &bytes.Buffer{}
+	if err = DeepMarshalJSON(buf, decoded); err != nil {
+		t.Fatalf("DeepMarshalJSON failed: %s", err)
+	}
+	if !bytes.Equal(rendered, buf.Bytes()) {
+		t.Fatalf("The block decoded from JSON differs from the original")
+	}
+}
+
+func TestOpaqueFieldsLeftAsBytes(t *testing.T) {
+	env := &common.Envelope{Payload: utils.MarshalOrPanic(&common.Payload{
+		Header: &common.Header{ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{Type: int32(common.HeaderType_MESSAGE)})},
+		Data:   []byte("opaque data"),
+	})}
+
+	decoded := &common.Envelope{}
+	roundTrip(t, env, decoded, `"data": "b3BhcXVlIGRhdGE="`)
+	if !proto.Equal(env, decoded) {
+		t.Fatalf("The envelope decoded from JSON differs from the original")
+	}
+}
+
+func TestInvalidJSON(t *testing.T) {
+	if err := DeepUnmarshalJSON(strings.NewReader(`{"payload": "not an object"}`), &common.Envelope{}); err == nil {
+		t.Fatalf("DeepUnmarshalJSON should have failed for a payload that is not a JSON object")
+	}
+
+	if err := DeepUnmarshalJSON(strings.NewReader(`{"unknown_field": 1}`), &common.Envelope{}); err == nil {
+		t.Fatalf("DeepUnmarshalJSON should have failed for an unknown field")
+	}
+}
@@ -0,0 +1,254 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+)
+
+var logger = flogging.MustGetLogger("tracing")
+
+const (
+	queueSize     = 4096
+	batchSize     = 512
+	flushInterval = time.Second
+	postTimeout   = 10 * time.Second
+)
+
+var current atomic.Value // *exporter
+
+func getExporter() *exporter {
+	e, _ := current.Load().(*exporter)
+	return e
+}
+
+// Init exports the spans finished from now on to the OTLP/HTTP traces
+// endpoint of an OpenTelemetry collector, e.g. http://localhost:4318/v1/traces
+// for Jaeger, as the given service. An empty endpoint disables the export.
+func Init(service, endpoint string) {
+	if endpoint == "" {
+		return
+	}
+	logger.Infof("Exporting the transaction traces of %s to %s", service, endpoint)
+	current.Store(newExporter(service, endpoint, flushInterval))
+}
+
+type finished struct {
+	span *Span
+	end  time.Time
+}
+
+type exporter struct {
+	service  string
+	endpoint string
+	client   *http.Client
+	queue    chan finished
+}
+
+func newExporter(service, endpoint string, interval time.Duration) *exporter {
+	e := &exporter{
+		service:  service,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: postTimeout},
+		queue:    make(chan finished, queueSize),
+	}
+	go e.run(interval)
+	return e
+}
+
+// export queues a finished span, or drops it if the collector lags behind
+func (e *exporter) export(s *Span, end time.Time) {
+	select {
+	case e.queue <- finished{span: s, end: end}:
+	default:
+		logger.Debugf("Dropping the span %s %s, the export queue is full", s.family, s.title)
+	}
+}
+
+func (e *exporter) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var batch []otlpSpan
+	for {
+		select {
+		case f := <-e.queue:
+			batch = append(batch, toOTLP(f.span, f.end)...)
+			if len(batch) < batchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		if err := e.post(batch); err != nil {
+			logger.Warningf("Failed exporting %d spans to %s: %s", len(batch), e.endpoint, err)
+		}
+		batch = nil
+	}
+}
+
+func (e *exporter) post(spans []otlpSpan) error {
+	body, err := json.Marshal(otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{Attributes: []otlpAttribute{stringAttribute("service.name", e.service)}},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "github.com/hyperledger/fabric"},
+				Spans: spans,
+			}},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// traceID derives the trace of a transaction from its ID, so that all the
+// processes put their spans of the transaction in the same trace
+func traceID(txID string) string {
+	sum := sha256.Sum256([]byte(txID))
+	return hex.EncodeToString(sum[:16])
+}
+
+func spanID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// toOTLP converts a span into one OTLP span per transaction
+func toOTLP(s *Span, end time.Time) []otlpSpan {
+	events := make([]otlpEvent, len(s.events))
+	for i, ev := range s.events {
+		events[i] = otlpEvent{TimeUnixNano: unixNano(ev.time), Name: fmt.Sprintf(ev.format, ev.args...)}
+	}
+	status := otlpStatus{Code: statusOK}
+	if s.failed {
+		status.Code = statusError
+	}
+
+	var spans []otlpSpan
+	for _, txID := range s.txIDs() {
+		if txID == "" {
+			continue
+		}
+		spans = append(spans, otlpSpan{
+			TraceID:           traceID(txID),
+			SpanID:            spanID(),
+			Name:              s.family,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: unixNano(s.start),
+			EndTimeUnixNano:   unixNano(end),
+			Attributes: []otlpAttribute{
+				stringAttribute("fabric.txid", txID),
+				stringAttribute("fabric.span", s.title),
+			},
+			Events: events,
+			Status: status,
+		})
+	}
+	return spans
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// The OTLP/HTTP JSON encoding of the trace export requests, see
+// https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+
+const (
+	spanKindInternal = 1
+	statusOK         = 1
+	statusError      = 2
+)
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes"`
+	Events            []otlpEvent     `json:"events,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string `json:"timeUnixNano"`
+	Name         string `json:"name"`
+}
+
+type otlpStatus struct {
+	Code int `json:"code"`
+}
+
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: value}}
+}
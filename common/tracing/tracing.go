@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing traces the flow of the transactions through the endorser,
+// the orderer and the committer. The spans are shown on /debug/requests of
+// the profiling server and, once Init is called with an endpoint, exported
+// to an OpenTelemetry collector such as Jaeger. All the spans of a
+// transaction belong to the trace derived from its ID, so that the processes
+// need not propagate any context for the path of a transaction to be joined.
+package tracing
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"golang.org/x/net/trace"
+)
+
+// TxIDs returns the IDs of the transactions a span belongs to. It is only
+// called when the span is rendered or exported, and prints the IDs as a
+// fmt.Stringer, so it may be passed to LazyPrintf as is.
+type TxIDs func() []string
+
+// String implements fmt.Stringer
+func (ids TxIDs) String() string {
+	return fmt.Sprint(ids())
+}
+
+// TxID returns the TxIDs of a span of a single transaction
+func TxID(txID string) TxIDs {
+	return func() []string {
+		return []string{txID}
+	}
+}
+
+// BlockTxIDs returns the TxIDs of a span of the transactions of a block.
+// They are extracted from the block at most once, when first needed.
+func BlockTxIDs(block *cb.Block) TxIDs {
+	var once sync.Once
+	var txIDs []string
+	return func() []string {
+		once.Do(func() {
+			txIDs = utils.GetTxIDsFromBlock(block)
+		})
+		return txIDs
+	}
+}
+
+// Span traces one step of the flow of one or more transactions
+type Span struct {
+	tr     trace.Trace
+	family string
+	title  string
+	txIDs  TxIDs
+	start  time.Time
+	events []event
+	failed bool
+}
+
+type event struct {
+	time   time.Time
+	format string
+	args   []interface{}
+}
+
+// New starts a span of the given family, e.g. "endorser", over the given
+// transactions
+func New(family, title string, txIDs TxIDs) *Span {
+	s := &Span{
+		tr:     trace.New(family, title),
+		family: family,
+		title:  title,
+		txIDs:  txIDs,
+		start:  time.Now(),
+	}
+	return s
+}
+
+// LazyPrintf records an event of the span. The arguments are formatted only
+// when the span is rendered or exported.
+func (s *Span) LazyPrintf(format string, a ...interface{}) {
+	s.tr.LazyPrintf(format, a...)
+	if getExporter() != nil {
+		s.events = append(s.events, event{time: time.Now(), format: format, args: a})
+	}
+}
+
+// SetError marks the span as failed
+func (s *Span) SetError() {
+	s.tr.SetError()
+	s.failed = true
+}
+
+// Finish ends the span and exports it, if an exporter was initialized
+func (s *Span) Finish() {
+	s.tr.Finish()
+	if e := getExporter(); e != nil {
+		e.export(s, time.Now())
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxIDsLazy(t *testing.T) {
+	calls := 0
+	ids := TxIDs(func() []string {
+		calls++
+		return []string{"a", "b"}
+	})
+
+	s := New("test", "lazy", ids)
+	s.LazyPrintf("transactions %v", ids)
+	s.Finish()
+	assert.Equal(t, 0, calls, "the IDs must not be computed while nothing renders or exports the span")
+	assert.Equal(t, "[a b]", fmt.Sprint(ids))
+	assert.Equal(t, 1, calls)
+}
+
+func TestExport(t *testing.T) {
+	requests := make(chan otlpRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req otlpRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests <- req
+	}))
+	defer server.Close()
+
+	current.Store(newExporter("peer", server.URL, 10*time.Millisecond))
+	defer current.Store((*exporter)(nil))
+
+	s := New("committer", "block 1", TxIDs(func() []string { return []string{"tx1", "tx2"} }))
+	s.LazyPrintf("committing %d transactions", 2)
+	s.SetError()
+	s.Finish()
+
+	var req otlpRequest
+	select {
+	case req = <-requests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the spans were not exported")
+	}
+
+	assert.Len(t, req.ResourceSpans, 1)
+	assert.Equal(t, stringAttribute("service.name", "peer"), req.ResourceSpans[0].Resource.Attributes[0])
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	assert.Len(t, spans, 2, "one span per transaction")
+	for i, txID := range []string{"tx1", "tx2"} {
+		assert.Equal(t, traceID(txID), spans[i].TraceID)
+		assert.Len(t, spans[i].TraceID, 32)
+		assert.Len(t, spans[i].SpanID, 16)
+		assert.Equal(t, "committer", spans[i].Name)
+		assert.Contains(t, spans[i].Attributes, stringAttribute("fabric.txid", txID))
+		assert.Equal(t, "committing 2 transactions", spans[i].Events[0].Name)
+		assert.Equal(t, statusError, spans[i].Status.Code)
+	}
+	assert.NotEqual(t, spans[0].TraceID, spans[1].TraceID)
+}
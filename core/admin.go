@@ -17,10 +17,13 @@ limitations under the License.
 package core
 
 import (
+	"fmt"
 	"os"
+	"sync"
 
 	"golang.org/x/net/context"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/config"
@@ -35,29 +38,93 @@ func NewAdminServer() *ServerAdmin {
 	return s
 }
 
+// NewAdminServerWithAccessControl creates and returns a Admin service instance
+// authorizing every request with the passed AdminAccessControl.
+func NewAdminServerWithAccessControl(ac AdminAccessControl) *ServerAdmin {
+	return &ServerAdmin{accessControl: ac}
+}
+
 // ServerAdmin implementation of the Admin service for the Peer
 type ServerAdmin struct {
+	accessControl AdminAccessControl
+
+	lock        sync.Mutex
+	stopHandler func()
+	stopping    bool
+}
+
+// SetStopHandler registers the function shutting the peer down when a
+// StopServer request is received, instead of exiting the process. It is
+// invoked once, after the response to the request is returned.
+func (s *ServerAdmin) SetStopHandler(stopHandler func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.stopHandler = stopHandler
+}
+
+func (s *ServerAdmin) isStopping() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.stopping
+}
+
+func (s *ServerAdmin) checkAccess(ctx context.Context, operation string, request proto.Message) error {
+	if s.accessControl == nil {
+		return nil
+	}
+	if err := s.accessControl.CheckAccess(ctx, operation, request); err != nil {
+		log.Warningf("Admin request %s denied: %s", operation, err)
+		return fmt.Errorf("Access denied for %s", operation)
+	}
+	return nil
 }
 
 // GetStatus reports the status of the server
-func (*ServerAdmin) GetStatus(context.Context, *empty.Empty) (*pb.ServerStatus, error) {
+func (s *ServerAdmin) GetStatus(ctx context.Context, in *empty.Empty) (*pb.ServerStatus, error) {
+	if err := s.checkAccess(ctx, GetStatusOp, in); err != nil {
+		return nil, err
+	}
 	status := &pb.ServerStatus{Status: pb.ServerStatus_STARTED}
+	if s.isStopping() {
+		status.Status = pb.ServerStatus_STOPPED
+	}
 	log.Debugf("returning status: %s", status)
 	return status, nil
 }
 
 // StartServer starts the server
-func (*ServerAdmin) StartServer(context.Context, *empty.Empty) (*pb.ServerStatus, error) {
+func (s *ServerAdmin) StartServer(ctx context.Context, in *empty.Empty) (*pb.ServerStatus, error) {
+	if err := s.checkAccess(ctx, StartServerOp, in); err != nil {
+		return nil, err
+	}
+	if s.isStopping() {
+		return nil, fmt.Errorf("The peer is shutting down")
+	}
 	status := &pb.ServerStatus{Status: pb.ServerStatus_STARTED}
 	log.Debugf("returning status: %s", status)
 	return status, nil
 }
 
 // StopServer stops the server
-func (*ServerAdmin) StopServer(context.Context, *empty.Empty) (*pb.ServerStatus, error) {
+func (s *ServerAdmin) StopServer(ctx context.Context, in *empty.Empty) (*pb.ServerStatus, error) {
+	if err := s.checkAccess(ctx, StopServerOp, in); err != nil {
+		return nil, err
+	}
 	status := &pb.ServerStatus{Status: pb.ServerStatus_STOPPED}
 	log.Debugf("returning status: %s", status)
 
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.stopping {
+		log.Debug("The peer is already shutting down")
+		return status, nil
+	}
+	s.stopping = true
+	if s.stopHandler != nil {
+		go s.stopHandler()
+		return status, nil
+	}
+
 	pidFile := config.GetPath("peer.fileSystemPath") + "/peer.pid"
 	log.Debugf("Remove pid file  %s", pidFile)
 	os.Remove(pidFile)
@@ -67,14 +134,20 @@ func (*ServerAdmin) StopServer(context.Context, *empty.Empty) (*pb.ServerStatus,
 
 // GetModuleLogLevel gets the current logging level for the specified module
 // TODO Modify the signature so as to remove the error return - it's always been nil
-func (*ServerAdmin) GetModuleLogLevel(ctx context.Context, request *pb.LogLevelRequest) (*pb.LogLevelResponse, error) {
+func (s *ServerAdmin) GetModuleLogLevel(ctx context.Context, request *pb.LogLevelRequest) (*pb.LogLevelResponse, error) {
+	if err := s.checkAccess(ctx, GetModuleLogLevelOp, request); err != nil {
+		return nil, err
+	}
 	logLevelString := flogging.GetModuleLevel(request.LogModule)
 	logResponse := &pb.LogLevelResponse{LogModule: request.LogModule, LogLevel: logLevelString}
 	return logResponse, nil
 }
 
 // SetModuleLogLevel sets the logging level for the specified module
-func (*ServerAdmin) SetModuleLogLevel(ctx context.Context, request *pb.LogLevelRequest) (*pb.LogLevelResponse, error) {
+func (s *ServerAdmin) SetModuleLogLevel(ctx context.Context, request *pb.LogLevelRequest) (*pb.LogLevelResponse, error) {
+	if err := s.checkAccess(ctx, SetModuleLogLevelOp, request); err != nil {
+		return nil, err
+	}
 	logLevelString, err := flogging.SetModuleLevel(request.LogModule, request.LogLevel)
 	logResponse := &pb.LogLevelResponse{LogModule: request.LogModule, LogLevel: logLevelString}
 	return logResponse, err
@@ -82,7 +155,10 @@ func (*ServerAdmin) SetModuleLogLevel(ctx context.Context, request *pb.LogLevelR
 
 // RevertLogLevels reverts the log levels for all modules to the level
 // defined at the end of peer startup.
-func (*ServerAdmin) RevertLogLevels(context.Context, *empty.Empty) (*empty.Empty, error) {
+func (s *ServerAdmin) RevertLogLevels(ctx context.Context, in *empty.Empty) (*empty.Empty, error) {
+	if err := s.checkAccess(ctx, RevertLogLevelsOp, in); err != nil {
+		return nil, err
+	}
 	err := flogging.RevertToPeerStartupLevels()
 
 	return &empty.Empty{}, err
@@ -16,9 +16,42 @@ limitations under the License.
 
 package core
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
 
 func TestServer_Status(t *testing.T) {
 	t.Skip("TBD")
 	//performHandshake(t, peerClientConn)
 }
+
+func TestServerStop(t *testing.T) {
+	s := NewAdminServer()
+	stopped := make(chan struct{})
+	s.SetStopHandler(func() { close(stopped) })
+
+	status, err := s.StopServer(context.Background(), &empty.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.ServerStatus_STOPPED, status.Status)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("The stop handler should have been invoked")
+	}
+
+	status, err = s.GetStatus(context.Background(), &empty.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.ServerStatus_STOPPED, status.Status, "The peer should be reported stopped while shutting down")
+	_, err = s.StartServer(context.Background(), &empty.Empty{})
+	assert.Error(t, err, "The peer should not be started while shutting down")
+
+	// The handler closing the channel again would panic
+	_, err = s.StopServer(context.Background(), &empty.Empty{})
+	assert.NoError(t, err)
+}
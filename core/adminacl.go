@@ -0,0 +1,275 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Metadata keys carrying the enrollment certificate based authentication
+// of an admin service request
+const (
+	// AdminIdentityKey carries the serialized identity of the client
+	AdminIdentityKey = "fabric-admin-identity-bin"
+	// AdminTimestampKey carries the time the request was signed at, in RFC3339 format
+	AdminTimestampKey = "fabric-admin-timestamp"
+	// AdminNonceKey carries the nonce the client generated for the request
+	AdminNonceKey = "fabric-admin-nonce-bin"
+	// AdminSignatureKey carries the signature of AdminRequestMessage
+	AdminSignatureKey = "fabric-admin-signature-bin"
+)
+
+// adminRequestTimeWindow is the maximum skew between the time an admin
+// request was signed at and the time it is received. The nonces of the
+// requests are remembered for that long so that a request cannot be replayed
+const adminRequestTimeWindow = 15 * time.Minute
+
+// The operations of the admin service
+const (
+	GetStatusOp         = "GetStatus"
+	StartServerOp       = "StartServer"
+	StopServerOp        = "StopServer"
+	GetModuleLogLevelOp = "GetModuleLogLevel"
+	SetModuleLogLevelOp = "SetModuleLogLevel"
+	RevertLogLevelsOp   = "RevertLogLevels"
+)
+
+// DefaultAdminPolicies returns the local MSP policy each operation of the
+// admin service requires when none is configured
+func DefaultAdminPolicies() map[string]string {
+	return map[string]string{
+		GetStatusOp:         mgmt.Members,
+		StartServerOp:       mgmt.Admins,
+		StopServerOp:        mgmt.Admins,
+		GetModuleLogLevelOp: mgmt.Members,
+		SetModuleLogLevelOp: mgmt.Admins,
+		RevertLogLevelsOp:   mgmt.Admins,
+	}
+}
+
+// AdminRequestMessage returns the message a client signs with its enrollment
+// certificate to authenticate a request of the given admin operation. It
+// binds the signature to the marshaled request and to the nonce of the client
+func AdminRequestMessage(operation, timestamp string, nonce []byte, request []byte) []byte {
+	requestHash := sha256.Sum256(request)
+	return []byte(operation + "|" + timestamp + "|" + hex.EncodeToString(nonce) + "|" + hex.EncodeToString(requestHash[:]))
+}
+
+// AdminAccessControl authorizes the requests of the admin service
+type AdminAccessControl interface {
+	// CheckAccess returns an error if the client of the request carried by ctx
+	// is not authorized to invoke the given operation with the given request
+	CheckAccess(ctx context.Context, operation string, request proto.Message) error
+}
+
+// NewAdminAccessControl returns an AdminAccessControl authenticating the
+// clients through their TLS client certificate, or else through the signature
+// of their enrollment certificate, against the local MSP. A client is
+// authorized if it satisfies the principal of the policy of the operation.
+func NewAdminAccessControl(policies map[string]string, mspID string, localMSP msp.IdentityDeserializer, principalGetter mgmt.MSPPrincipalGetter) (AdminAccessControl, error) {
+	if mspID == "" {
+		return nil, errors.New("Invalid local MSP ID. It must be different from nil.")
+	}
+	if localMSP == nil {
+		return nil, errors.New("Invalid local MSP. It must be different from nil.")
+	}
+	if principalGetter == nil {
+		return nil, errors.New("Invalid MSP principal getter. It must be different from nil.")
+	}
+	ac := &adminAccessControl{
+		policies:        DefaultAdminPolicies(),
+		mspID:           mspID,
+		localMSP:        localMSP,
+		principalGetter: principalGetter,
+		now:             time.Now,
+		nonces:          make(map[string]time.Time),
+	}
+	for operation, policy := range policies {
+		ac.policies[operation] = policy
+	}
+	return ac, nil
+}
+
+type adminAccessControl struct {
+	policies        map[string]string
+	mspID           string
+	localMSP        msp.IdentityDeserializer
+	principalGetter mgmt.MSPPrincipalGetter
+	now             func() time.Time
+
+	// nonces maps the nonces of the signed requests received to the time
+	// until which their timestamp is accepted
+	nonces     map[string]time.Time
+	noncesLock sync.Mutex
+}
+
+func (ac *adminAccessControl) CheckAccess(ctx context.Context, operation string, request proto.Message) error {
+	policy, ok := ac.policies[operation]
+	if !ok {
+		return fmt.Errorf("No policy defined for admin operation [%s]", operation)
+	}
+
+	id, err := ac.clientIdentity(ctx, operation, request)
+	if err != nil {
+		return fmt.Errorf("Failed authenticating client of admin operation [%s]: [%s]", operation, err)
+	}
+
+	principal, err := ac.principalGetter.Get(policy)
+	if err != nil {
+		return fmt.Errorf("Failed getting local MSP principal for policy [%s]: [%s]", policy, err)
+	}
+
+	if err := id.SatisfiesPrincipal(principal); err != nil {
+		return fmt.Errorf("Client of admin operation [%s] does not satisfy policy [%s]: [%s]", operation, policy, err)
+	}
+	return nil
+}
+
+// clientIdentity returns the identity of the client, preferring the
+// certificate the client presented during the TLS handshake
+func (ac *adminAccessControl) clientIdentity(ctx context.Context, operation string, request proto.Message) (msp.Identity, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, isTLS := p.AuthInfo.(credentials.TLSInfo); isTLS && len(tlsInfo.State.PeerCertificates) > 0 {
+			idBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsInfo.State.PeerCertificates[0].Raw})
+			serializedID, err := proto.Marshal(&mspprotos.SerializedIdentity{Mspid: ac.mspID, IdBytes: idBytes})
+			if err != nil {
+				return nil, err
+			}
+			return ac.localMSP.DeserializeIdentity(serializedID)
+		}
+	}
+
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return nil, errors.New("No client certificate nor signature provided")
+	}
+	serializedID, err := singleValue(md, AdminIdentityKey)
+	if err != nil {
+		return nil, err
+	}
+	timestamp, err := singleValue(md, AdminTimestampKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := singleValue(md, AdminNonceKey)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := singleValue(md, AdminSignatureKey)
+	if err != nil {
+		return nil, err
+	}
+	requestBytes, err := proto.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("Failed marshaling request [%s]", err)
+	}
+
+	signedAt, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid timestamp [%s]", err)
+	}
+	skew := ac.now().Sub(signedAt)
+	if skew > adminRequestTimeWindow || skew < -adminRequestTimeWindow {
+		return nil, fmt.Errorf("Request timestamp %s is not within %s of the peer time", timestamp, adminRequestTimeWindow)
+	}
+
+	id, err := ac.localMSP.DeserializeIdentity([]byte(serializedID))
+	if err != nil {
+		return nil, fmt.Errorf("Failed deserializing identity [%s]", err)
+	}
+	if err := id.Validate(); err != nil {
+		return nil, fmt.Errorf("Invalid identity [%s]", err)
+	}
+	if err := id.Verify(AdminRequestMessage(operation, timestamp, []byte(nonce), requestBytes), []byte(signature)); err != nil {
+		return nil, fmt.Errorf("Invalid signature [%s]", err)
+	}
+	if err := ac.checkNonce(nonce, signedAt); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// checkNonce records the nonce of a signed request, failing if it was
+// already used by a request whose timestamp is still accepted
+func (ac *adminAccessControl) checkNonce(nonce string, signedAt time.Time) error {
+	ac.noncesLock.Lock()
+	defer ac.noncesLock.Unlock()
+
+	now := ac.now()
+	for n, expiry := range ac.nonces {
+		if now.After(expiry) {
+			delete(ac.nonces, n)
+		}
+	}
+	if _, seen := ac.nonces[nonce]; seen {
+		return errors.New("Replayed request")
+	}
+	ac.nonces[nonce] = signedAt.Add(adminRequestTimeWindow)
+	return nil
+}
+
+func singleValue(md metadata.MD, key string) (string, error) {
+	values := md[key]
+	if len(values) != 1 || len(values[0]) == 0 {
+		return "", fmt.Errorf("Expected a single %s value, got %d", key, len(values))
+	}
+	return values[0], nil
+}
+
+// SignAdminRequest returns a context carrying the signature of the given
+// admin operation and request by the signer, to be used by admin service clients
+func SignAdminRequest(ctx context.Context, operation string, request proto.Message, signer msp.SigningIdentity) (context.Context, error) {
+	serializedID, err := signer.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("Failed serializing signer identity [%s]", err)
+	}
+	requestBytes, err := proto.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("Failed marshaling admin request [%s]", err)
+	}
+	nonce, err := crypto.GetRandomNonce()
+	if err != nil {
+		return nil, fmt.Errorf("Failed generating nonce [%s]", err)
+	}
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	signature, err := signer.Sign(AdminRequestMessage(operation, timestamp, nonce, requestBytes))
+	if err != nil {
+		return nil, fmt.Errorf("Failed signing admin request [%s]", err)
+	}
+	return metadata.NewContext(ctx, metadata.Pairs(
+		AdminIdentityKey, string(serializedID),
+		AdminTimestampKey, timestamp,
+		AdminNonceKey, string(nonce),
+		AdminSignatureKey, string(signature),
+	)), nil
+}
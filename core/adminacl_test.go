@@ -0,0 +1,207 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+type mockIdentity struct {
+	msp.Identity
+	name  string
+	roles []string
+}
+
+func (id *mockIdentity) Validate() error {
+	return nil
+}
+
+func (id *mockIdentity) Verify(msg []byte, sig []byte) error {
+	if string(sig) != id.name+":"+string(msg) {
+		return errors.New("Invalid signature")
+	}
+	return nil
+}
+
+func (id *mockIdentity) SatisfiesPrincipal(principal *mspprotos.MSPPrincipal) error {
+	for _, role := range id.roles {
+		if role == string(principal.Principal) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not among %v", principal.Principal, id.roles)
+}
+
+type mockSigner struct {
+	msp.SigningIdentity
+	id *mockIdentity
+}
+
+func (s *mockSigner) Serialize() ([]byte, error) {
+	return []byte(s.id.name), nil
+}
+
+func (s *mockSigner) Sign(msg []byte) ([]byte, error) {
+	return []byte(s.id.name + ":" + string(msg)), nil
+}
+
+type mockDeserializer map[string]*mockIdentity
+
+func (d mockDeserializer) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	id, ok := d[string(serializedIdentity)]
+	if !ok {
+		return nil, errors.New("Unknown identity")
+	}
+	return id, nil
+}
+
+type rolePrincipalGetter struct{}
+
+func (rolePrincipalGetter) Get(role string) (*mspprotos.MSPPrincipal, error) {
+	return &mspprotos.MSPPrincipal{Principal: []byte(role)}, nil
+}
+
+var (
+	admin  = &mockIdentity{name: "admin", roles: []string{mgmt.Admins, mgmt.Members}}
+	member = &mockIdentity{name: "member", roles: []string{mgmt.Members}}
+)
+
+func newTestAccessControl(t *testing.T, policies map[string]string) *adminAccessControl {
+	ac, err := NewAdminAccessControl(policies, "SampleOrg", mockDeserializer{"admin": admin, "member": member}, rolePrincipalGetter{})
+	assert.NoError(t, err)
+	return ac.(*adminAccessControl)
+}
+
+// signedContext returns the context the admin service receives for a request signed by id
+func signedContext(t *testing.T, operation string, request proto.Message, id *mockIdentity) context.Context {
+	ctx, err := SignAdminRequest(context.Background(), operation, request, &mockSigner{id: id})
+	assert.NoError(t, err)
+	outgoing, _ := metadata.FromContext(ctx)
+	incoming := metadata.MD{}
+	for k, values := range outgoing {
+		for _, v := range values {
+			key, val, err := metadata.DecodeKeyValue(k, v)
+			assert.NoError(t, err)
+			incoming[key] = append(incoming[key], val)
+		}
+	}
+	return metadata.NewContext(context.Background(), incoming)
+}
+
+func TestNewAdminAccessControl(t *testing.T) {
+	_, err := NewAdminAccessControl(nil, "", mockDeserializer{}, rolePrincipalGetter{})
+	assert.Error(t, err)
+	_, err = NewAdminAccessControl(nil, "SampleOrg", nil, rolePrincipalGetter{})
+	assert.Error(t, err)
+	_, err = NewAdminAccessControl(nil, "SampleOrg", mockDeserializer{}, nil)
+	assert.Error(t, err)
+}
+
+func TestAdminAccessControlSignedRequests(t *testing.T) {
+	ac := newTestAccessControl(t, nil)
+
+	assert.NoError(t, ac.CheckAccess(signedContext(t, SetModuleLogLevelOp, &empty.Empty{}, admin), SetModuleLogLevelOp, &empty.Empty{}))
+	assert.NoError(t, ac.CheckAccess(signedContext(t, GetStatusOp, &empty.Empty{}, member), GetStatusOp, &empty.Empty{}))
+	assert.Error(t, ac.CheckAccess(signedContext(t, SetModuleLogLevelOp, &empty.Empty{}, member), SetModuleLogLevelOp, &empty.Empty{}), "Members should not be able to set log levels")
+	assert.Error(t, ac.CheckAccess(signedContext(t, GetStatusOp, &empty.Empty{}, admin), StopServerOp, &empty.Empty{}), "Signature of another operation should be rejected")
+	assert.Error(t, ac.CheckAccess(context.Background(), GetStatusOp, &empty.Empty{}), "Unauthenticated request should be rejected")
+	assert.Error(t, ac.CheckAccess(signedContext(t, GetStatusOp, &empty.Empty{}, admin), "Unknown", &empty.Empty{}))
+
+	ac.now = func() time.Time {
+		return time.Now().Add(adminRequestTimeWindow * 2)
+	}
+	assert.Error(t, ac.CheckAccess(signedContext(t, GetStatusOp, &empty.Empty{}, admin), GetStatusOp, &empty.Empty{}), "Stale request should be rejected")
+}
+
+func TestAdminAccessControlReplayedRequests(t *testing.T) {
+	ac := newTestAccessControl(t, nil)
+
+	request := &pb.LogLevelRequest{LogModule: "server", LogLevel: "debug"}
+	ctx := signedContext(t, SetModuleLogLevelOp, request, admin)
+	assert.Error(t, ac.CheckAccess(ctx, SetModuleLogLevelOp, &pb.LogLevelRequest{LogModule: "server", LogLevel: "error"}), "Signature of another request should be rejected")
+	assert.NoError(t, ac.CheckAccess(ctx, SetModuleLogLevelOp, request))
+	assert.Error(t, ac.CheckAccess(ctx, SetModuleLogLevelOp, request), "Replayed request should be rejected")
+	assert.NoError(t, ac.CheckAccess(signedContext(t, SetModuleLogLevelOp, request, admin), SetModuleLogLevelOp, request), "A new request with the same content should be accepted")
+
+	// nonces are forgotten once the timestamp of their request is no longer accepted
+	ac.now = func() time.Time {
+		return time.Now().Add(adminRequestTimeWindow * 2)
+	}
+	assert.Error(t, ac.CheckAccess(ctx, SetModuleLogLevelOp, request))
+	assert.NoError(t, ac.checkNonce("nonce", ac.now()))
+	assert.Len(t, ac.nonces, 1)
+}
+
+func TestAdminAccessControlPolicies(t *testing.T) {
+	ac := newTestAccessControl(t, map[string]string{SetModuleLogLevelOp: mgmt.Members, GetStatusOp: mgmt.Admins})
+
+	assert.NoError(t, ac.CheckAccess(signedContext(t, SetModuleLogLevelOp, &empty.Empty{}, member), SetModuleLogLevelOp, &empty.Empty{}))
+	assert.Error(t, ac.CheckAccess(signedContext(t, GetStatusOp, &empty.Empty{}, member), GetStatusOp, &empty.Empty{}))
+	assert.Error(t, ac.CheckAccess(signedContext(t, StopServerOp, &empty.Empty{}, member), StopServerOp, &empty.Empty{}), "Default policies should apply to operations not configured")
+}
+
+func TestAdminAccessControlTLSClientCert(t *testing.T) {
+	cert := &x509.Certificate{Raw: []byte("tls client certificate")}
+	serializedID, err := proto.Marshal(&mspprotos.SerializedIdentity{
+		Mspid:   "SampleOrg",
+		IdBytes: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}),
+	})
+	assert.NoError(t, err)
+	ac, err := NewAdminAccessControl(nil, "SampleOrg", mockDeserializer{string(serializedID): admin}, rolePrincipalGetter{})
+	assert.NoError(t, err)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+	})
+	assert.NoError(t, ac.CheckAccess(ctx, StopServerOp, &empty.Empty{}))
+
+	ctx = peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Raw: []byte("unknown")}}}},
+	})
+	assert.Error(t, ac.CheckAccess(ctx, StopServerOp, &empty.Empty{}))
+}
+
+func TestAdminServerAccessControl(t *testing.T) {
+	s := NewAdminServerWithAccessControl(newTestAccessControl(t, nil))
+
+	setRequest := &pb.LogLevelRequest{LogModule: "server", LogLevel: "debug"}
+	_, err := s.SetModuleLogLevel(signedContext(t, SetModuleLogLevelOp, setRequest, member), setRequest)
+	assert.Error(t, err)
+
+	getRequest := &pb.LogLevelRequest{LogModule: "server"}
+	resp, err := s.GetModuleLogLevel(signedContext(t, GetModuleLogLevelOp, getRequest, member), getRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, "server", resp.LogModule)
+}
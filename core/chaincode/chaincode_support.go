@@ -57,6 +57,9 @@ const (
 
 	//HistoryQueryExecutorKey is used to attach ledger history query executor context
 	HistoryQueryExecutorKey key = "historyqueryexecutorkey"
+
+	//nestedExecutionKey marks the context of the executions invoked by chaincodes
+	nestedExecutionKey key = "nestedexecutionkey"
 )
 
 //this is basically the singleton that supports the
@@ -89,6 +92,11 @@ func getHistoryQueryExecutor(context context.Context) ledger.HistoryQueryExecuto
 //This is where the VM that's running the chaincode would hook in
 type chaincodeRTEnv struct {
 	handler *Handler
+
+	// cccid and cds are set when the peer launches the chaincode, they are
+	// used to stop its container when the peer shuts down
+	cccid *ccprovider.CCContext
+	cds   *pb.ChaincodeDeploymentSpec
 }
 
 // runningChaincodes contains maps of chaincodeIDs to their chaincodeRTEs
@@ -180,6 +188,28 @@ func NewChaincodeSupport(getPeerEndpoint func() (*pb.PeerEndpoint, error), userr
 
 	theChaincodeSupport.executetimeout = time.Duration(execto) * time.Millisecond
 
+	theChaincodeSupport.executetimeouts = getExecuteTimeoutOverrides()
+
+	//a value <= 0 never ends the stream of unresponsive chaincodes
+	if maxMissed := viper.GetInt("chaincode.keepaliveMaxMissed"); maxMissed > 0 && theChaincodeSupport.keepalive > 0 {
+		chaincodeLogger.Debugf("Ending the stream of chaincodes missing %d keepalives", maxMissed)
+		theChaincodeSupport.keepaliveMaxMissed = maxMissed
+	}
+
+	//a value <= 0 does not limit the number of concurrent launches
+	if maxLaunches := viper.GetInt("chaincode.maxConcurrentLaunches"); maxLaunches > 0 {
+		chaincodeLogger.Debugf("Limiting concurrent chaincode launches to %d", maxLaunches)
+		theChaincodeSupport.launchSlots = make(chan struct{}, maxLaunches)
+	}
+
+	//a value <= 0 does not limit the number of concurrent executions
+	if maxExecutions := viper.GetInt("chaincode.maxConcurrentExecutions"); maxExecutions > 0 {
+		chaincodeLogger.Debugf("Limiting concurrent executions per chaincode to %d", maxExecutions)
+		theChaincodeSupport.maxConcurrentExecutions = maxExecutions
+	}
+	theChaincodeSupport.warmStart = viper.GetBool("chaincode.warmStart")
+	theChaincodeSupport.reapOrphans = viper.GetBool("chaincode.reapOrphans")
+
 	viper.SetEnvPrefix("CORE")
 	viper.AutomaticEnv()
 	replacer := strings.NewReplacer(".", "_")
@@ -196,6 +226,15 @@ func NewChaincodeSupport(getPeerEndpoint func() (*pb.PeerEndpoint, error), userr
 	}
 	theChaincodeSupport.logFormat = viper.GetString("chaincode.logFormat")
 
+	// the level of the loggers of the chaincode is left to the shim when not set
+	if defaultLogLevelString := viper.GetString("chaincode.defaultLogLevel"); defaultLogLevelString != "" {
+		if defaultLogLevel, err := logging.LogLevel(defaultLogLevelString); err == nil {
+			theChaincodeSupport.defaultLogLevel = defaultLogLevel.String()
+		} else {
+			chaincodeLogger.Warningf("Chaincode default logging level %s is invalid; ignoring it", defaultLogLevelString)
+		}
+	}
+
 	return theChaincodeSupport
 }
 
@@ -219,8 +258,59 @@ type ChaincodeSupport struct {
 	peerTLSSvrHostOrd string
 	keepalive         time.Duration
 	chaincodeLogLevel string
+	defaultLogLevel   string
 	logFormat         string
 	executetimeout    time.Duration
+	launchSlots       chan struct{}
+	warmStart         bool
+	reapOrphans       bool
+
+	// executetimeouts overrides executetimeout by chaincode name
+	executetimeouts map[string]time.Duration
+
+	// keepaliveMaxMissed is the number of keepalive periods without message
+	// from a chaincode after which its stream is ended, 0 to never end it
+	keepaliveMaxMissed int
+
+	// maxConcurrentExecutions is the number of transactions each chaincode
+	// executes at the same time, 0 to not limit them
+	maxConcurrentExecutions int
+}
+
+// ExecuteTimeoutConfig overrides the execute timeout of a chaincode, as found
+// in the chaincode.executetimeoutOverrides section of core.yaml
+type ExecuteTimeoutConfig struct {
+	Name    string `mapstructure:"name" yaml:"name"`
+	Timeout int    `mapstructure:"timeout" yaml:"timeout"`
+}
+
+// getExecuteTimeoutOverrides reads the execute timeouts of the chaincodes
+// overriding chaincode.executetimeout, skipping the invalid ones
+func getExecuteTimeoutOverrides() map[string]time.Duration {
+	var configs []*ExecuteTimeoutConfig
+	if err := viper.UnmarshalKey("chaincode.executetimeoutOverrides", &configs); err != nil {
+		chaincodeLogger.Errorf("Invalid execute timeout overrides (%s), ignoring them", err)
+		return nil
+	}
+
+	timeouts := map[string]time.Duration{}
+	for _, conf := range configs {
+		if conf == nil || conf.Name == "" || conf.Timeout < 1000 {
+			chaincodeLogger.Errorf("Invalid execute timeout override %v (should have a name and be at least 1000ms), ignoring it", conf)
+			continue
+		}
+		chaincodeLogger.Debugf("Setting execute timeout value of chaincode %s to %d ms", conf.Name, conf.Timeout)
+		timeouts[conf.Name] = time.Duration(conf.Timeout) * time.Millisecond
+	}
+	return timeouts
+}
+
+// getExecuteTimeout returns the execute timeout of the chaincode
+func (chaincodeSupport *ChaincodeSupport) getExecuteTimeout(ccName string) time.Duration {
+	if timeout, ok := chaincodeSupport.executetimeouts[ccName]; ok {
+		return timeout
+	}
+	return chaincodeSupport.executetimeout
 }
 
 // DuplicateChaincodeHandlerError returned if attempt to register same chaincodeID while a stream already exists.
@@ -363,6 +453,10 @@ func (chaincodeSupport *ChaincodeSupport) getArgsAndEnv(cccid *ccprovider.CCCont
 		envs = append(envs, "CORE_CHAINCODE_LOGLEVEL="+chaincodeSupport.chaincodeLogLevel)
 	}
 
+	if chaincodeSupport.defaultLogLevel != "" {
+		envs = append(envs, "CORE_CHAINCODE_DEFAULTLOGLEVEL="+chaincodeSupport.defaultLogLevel)
+	}
+
 	if chaincodeSupport.logFormat != "" {
 		envs = append(envs, "CORE_CHAINCODE_LOGFORMAT="+chaincodeSupport.logFormat)
 	}
@@ -396,6 +490,8 @@ func (chaincodeSupport *ChaincodeSupport) launchAndWaitForRegister(ctxt context.
 
 	//chaincodeHasBeenLaunch false... its not in the map, add it and proceed to launch
 	notfy := chaincodeSupport.preLaunchSetup(canName)
+	chrte := chaincodeSupport.runningChaincodes.chaincodeMap[canName]
+	chrte.cccid, chrte.cds = cccid, cds
 	chaincodeSupport.runningChaincodes.Unlock()
 
 	//launch the chaincode
@@ -411,6 +507,18 @@ func (chaincodeSupport *ChaincodeSupport) launchAndWaitForRegister(ctxt context.
 
 	vmtype, _ := chaincodeSupport.getVMType(cds)
 
+	//system chaincodes run in process and are not throttled
+	if vmtype != container.SYSTEM {
+		if err = chaincodeSupport.acquireLaunchSlot(); err != nil {
+			err = fmt.Errorf("%s for %s(networkid:%s,peerid:%s,tx:%s)", err, canName, chaincodeSupport.peerNetworkID, chaincodeSupport.peerID, cccid.TxID)
+			chaincodeSupport.runningChaincodes.Lock()
+			delete(chaincodeSupport.runningChaincodes.chaincodeMap, canName)
+			chaincodeSupport.runningChaincodes.Unlock()
+			return err
+		}
+		defer chaincodeSupport.releaseLaunchSlot()
+	}
+
 	sir := container.StartImageReq{CCID: ccintf.CCID{ChaincodeSpec: cds.ChaincodeSpec, NetworkID: chaincodeSupport.peerNetworkID, PeerID: chaincodeSupport.peerID, Version: cccid.Version}, Builder: builder, Args: args, Env: env}
 
 	ipcCtxt := context.WithValue(ctxt, ccintf.GetCCHandlerKey(), chaincodeSupport)
@@ -446,6 +554,26 @@ func (chaincodeSupport *ChaincodeSupport) launchAndWaitForRegister(ctxt context.
 	return err
 }
 
+// acquireLaunchSlot blocks until the number of chaincode containers being
+// launched is below the configured limit, or the startup timeout expires
+func (chaincodeSupport *ChaincodeSupport) acquireLaunchSlot() error {
+	if chaincodeSupport.launchSlots == nil {
+		return nil
+	}
+	select {
+	case chaincodeSupport.launchSlots <- struct{}{}:
+		return nil
+	case <-time.After(chaincodeSupport.ccStartupTimeout):
+		return fmt.Errorf("Timeout expired while waiting to launch chaincode")
+	}
+}
+
+func (chaincodeSupport *ChaincodeSupport) releaseLaunchSlot() {
+	if chaincodeSupport.launchSlots != nil {
+		<-chaincodeSupport.launchSlots
+	}
+}
+
 //Stop stops a chaincode if running
 func (chaincodeSupport *ChaincodeSupport) Stop(context context.Context, cccid *ccprovider.CCContext, cds *pb.ChaincodeDeploymentSpec) error {
 	canName := cccid.GetCanonicalName()
@@ -481,6 +609,27 @@ func (chaincodeSupport *ChaincodeSupport) Stop(context context.Context, cccid *c
 	return err
 }
 
+// StopAll stops the containers of the chaincodes launched by the peer. It is
+// invoked when the peer shuts down, system chaincodes run in process and are
+// left alone
+func (chaincodeSupport *ChaincodeSupport) StopAll(context context.Context) {
+	var launched []*chaincodeRTEnv
+	chaincodeSupport.runningChaincodes.RLock()
+	for _, chrte := range chaincodeSupport.runningChaincodes.chaincodeMap {
+		if chrte.cds != nil && chrte.cds.ExecEnv != pb.ChaincodeDeploymentSpec_SYSTEM {
+			launched = append(launched, chrte)
+		}
+	}
+	chaincodeSupport.runningChaincodes.RUnlock()
+
+	for _, chrte := range launched {
+		chaincodeLogger.Infof("Stopping chaincode %s", chrte.cccid.GetCanonicalName())
+		if err := chaincodeSupport.Stop(context, chrte.cccid, chrte.cds); err != nil {
+			chaincodeLogger.Warningf("Failed stopping chaincode %s: %s", chrte.cccid.GetCanonicalName(), err)
+		}
+	}
+}
+
 // Launch will launch the chaincode if not running (if running return nil) and will wait for handler of the chaincode to get into FSM ready state.
 func (chaincodeSupport *ChaincodeSupport) Launch(context context.Context, cccid *ccprovider.CCContext, spec interface{}) (*pb.ChaincodeID, *pb.ChaincodeInput, error) {
 	//build the chaincode
@@ -653,6 +802,18 @@ func (chaincodeSupport *ChaincodeSupport) Execute(ctxt context.Context, cccid *c
 	}
 	chaincodeSupport.runningChaincodes.Unlock()
 
+	//the time spent waiting for an execution slot counts towards the timeout.
+	//Chaincode to chaincode invocations don't take a slot: their transaction
+	//already holds one, and waiting for another could deadlock the chaincodes
+	if nested, _ := ctxt.Value(nestedExecutionKey).(bool); !nested {
+		start := time.Now()
+		if err := chrte.handler.acquireExecutionSlot(timeout); err != nil {
+			return nil, fmt.Errorf("Cannot execute transaction for %s: %s", canName, err)
+		}
+		defer chrte.handler.releaseExecutionSlot()
+		timeout -= time.Since(start)
+	}
+
 	var notfy chan *pb.ChaincodeMessage
 	var err error
 	if notfy, err = chrte.handler.sendExecuteMessage(ctxt, cccid.ChainID, msg, cccid.SignedProposal, cccid.Proposal); err != nil {
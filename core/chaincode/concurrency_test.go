@@ -18,12 +18,17 @@ package chaincode
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
 
 	"golang.org/x/net/context"
 )
@@ -145,3 +150,203 @@ func TestExecuteConcurrentInvokes(t *testing.T) {
 		}
 	}
 }
+
+//TestLaunchSlots checks that no more than the configured number of
+//launches are allowed at the same time
+func TestLaunchSlots(t *testing.T) {
+	chaincodeSupport := &ChaincodeSupport{launchSlots: make(chan struct{}, 2), ccStartupTimeout: 100 * time.Millisecond}
+
+	if err := chaincodeSupport.acquireLaunchSlot(); err != nil {
+		t.Fatalf("Failed acquiring first launch slot: %s", err)
+	}
+	if err := chaincodeSupport.acquireLaunchSlot(); err != nil {
+		t.Fatalf("Failed acquiring second launch slot: %s", err)
+	}
+	if err := chaincodeSupport.acquireLaunchSlot(); err == nil {
+		t.Fatalf("Acquiring a third launch slot should have timed out")
+	}
+
+	chaincodeSupport.releaseLaunchSlot()
+	if err := chaincodeSupport.acquireLaunchSlot(); err != nil {
+		t.Fatalf("Failed acquiring a released launch slot: %s", err)
+	}
+
+	unlimited := &ChaincodeSupport{ccStartupTimeout: 100 * time.Millisecond}
+	for i := 0; i < 10; i++ {
+		if err := unlimited.acquireLaunchSlot(); err != nil {
+			t.Fatalf("Launches should not be limited: %s", err)
+		}
+	}
+}
+
+//TestExecuteTimeoutOverrides checks that chaincodes get their own execute
+//timeout if configured, and the default one otherwise
+func TestExecuteTimeoutOverrides(t *testing.T) {
+	viper.Set("chaincode.executetimeoutOverrides", []map[string]interface{}{
+		{"name": "slowcc", "timeout": 120000},
+		{"name": "invalidcc", "timeout": 10},
+		{"name": "fastcc", "timeout": 1000},
+		{"timeout": 60000},
+	})
+	defer viper.Set("chaincode.executetimeoutOverrides", nil)
+
+	chaincodeSupport := &ChaincodeSupport{executetimeout: 30 * time.Second, executetimeouts: getExecuteTimeoutOverrides()}
+	if timeout := chaincodeSupport.getExecuteTimeout("slowcc"); timeout != 120*time.Second {
+		t.Fatalf("Expected the execute timeout of slowcc to be overridden, got %s", timeout)
+	}
+	if timeout := chaincodeSupport.getExecuteTimeout("fastcc"); timeout != time.Second {
+		t.Fatalf("Expected the execute timeout of fastcc to be overridden, got %s", timeout)
+	}
+	for _, ccName := range []string{"invalidcc", "othercc"} {
+		if timeout := chaincodeSupport.getExecuteTimeout(ccName); timeout != 30*time.Second {
+			t.Fatalf("Expected the default execute timeout for %s, got %s", ccName, timeout)
+		}
+	}
+}
+
+//TestAbortTxContexts checks that the transactions in progress are notified
+//when the stream of their chaincode ends
+func TestAbortTxContexts(t *testing.T) {
+	handler := &Handler{txCtxs: make(map[string]*transactionContext)}
+	pending, err := handler.createTxContext(context.Background(), "chain", "pending", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed creating transaction context: %s", err)
+	}
+	completed, err := handler.createTxContext(context.Background(), "chain", "completed", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed creating transaction context: %s", err)
+	}
+	completed.responseNotifier <- &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "completed"}
+
+	handler.abortTxContexts()
+
+	if msg := <-pending.responseNotifier; msg.Type != pb.ChaincodeMessage_ERROR {
+		t.Fatalf("Expected the pending transaction to be aborted, got %s", msg.Type)
+	}
+	if msg := <-completed.responseNotifier; msg.Type != pb.ChaincodeMessage_COMPLETED {
+		t.Fatalf("Expected the completed transaction to keep its response, got %s", msg.Type)
+	}
+}
+
+type stubTxSimulator struct {
+	ledger.TxSimulator
+}
+
+//TestSysCCTxState checks that system chaincodes read the state through the
+//tx simulator they are invoked with, for the duration of the invocation
+func TestSysCCTxState(t *testing.T) {
+	txsim := &stubTxSimulator{}
+	ctxt := context.WithValue(context.Background(), TXSimulatorKey, ledger.TxSimulator(txsim))
+
+	handler := &Handler{txCtxs: make(map[string]*transactionContext), ccCompParts: chaincodeIDParts("escc")}
+	if _, err := handler.createTxContext(ctxt, "chain", "txid", nil, nil); err != nil {
+		t.Fatalf("Failed creating transaction context: %s", err)
+	}
+	if qe, err := sysccprovider.GetTxQueryExecutor("chain", "txid"); err != nil || qe != txsim {
+		t.Fatalf("Expected the tx simulator of the invocation, got %v (err %v)", qe, err)
+	}
+	handler.deleteTxContext("txid")
+	if _, err := sysccprovider.GetTxQueryExecutor("chain", "txid"); err == nil {
+		t.Fatalf("Expected no tx simulator once the invocation is over")
+	}
+
+	// User chaincodes read the state through the shim only
+	handler = &Handler{txCtxs: make(map[string]*transactionContext), ccCompParts: chaincodeIDParts("mycc")}
+	if _, err := handler.createTxContext(ctxt, "chain", "txid", nil, nil); err != nil {
+		t.Fatalf("Failed creating transaction context: %s", err)
+	}
+	if _, err := sysccprovider.GetTxQueryExecutor("chain", "txid"); err == nil {
+		t.Fatalf("Expected no tx simulator for a user chaincode")
+	}
+}
+
+//TestExecutionSlots checks that a chaincode executes no more than the
+//configured number of transactions at the same time
+func TestExecutionSlots(t *testing.T) {
+	handler := newChaincodeSupportHandler(&ChaincodeSupport{maxConcurrentExecutions: 2}, nil)
+
+	if err := handler.acquireExecutionSlot(100 * time.Millisecond); err != nil {
+		t.Fatalf("Failed acquiring first execution slot: %s", err)
+	}
+	if err := handler.acquireExecutionSlot(100 * time.Millisecond); err != nil {
+		t.Fatalf("Failed acquiring second execution slot: %s", err)
+	}
+	if err := handler.acquireExecutionSlot(100 * time.Millisecond); err == nil {
+		t.Fatalf("Acquiring a third execution slot should have timed out")
+	}
+
+	handler.releaseExecutionSlot()
+	if err := handler.acquireExecutionSlot(100 * time.Millisecond); err != nil {
+		t.Fatalf("Failed acquiring a released execution slot: %s", err)
+	}
+
+	unlimited := newChaincodeSupportHandler(&ChaincodeSupport{}, nil)
+	for i := 0; i < 10; i++ {
+		if err := unlimited.acquireExecutionSlot(100 * time.Millisecond); err != nil {
+			t.Fatalf("Executions should not be limited: %s", err)
+		}
+	}
+}
+
+//TestNestedExecutionSlots checks that chaincode to chaincode invocations
+//are executed even if all the execution slots of the chaincode are taken
+func TestNestedExecutionSlots(t *testing.T) {
+	chaincodeSupport := &ChaincodeSupport{maxConcurrentExecutions: 1, runningChaincodes: &runningChaincodes{chaincodeMap: make(map[string]*chaincodeRTEnv)}}
+	handler := newChaincodeSupportHandler(chaincodeSupport, nil)
+	handler.txCtxs = make(map[string]*transactionContext)
+	cccid := ccprovider.NewCCContext("testchainid", "mycc", "0", "txid", false, nil, nil)
+	chaincodeSupport.runningChaincodes.chaincodeMap[cccid.GetCanonicalName()] = &chaincodeRTEnv{handler: handler}
+	if err := handler.acquireExecutionSlot(100 * time.Millisecond); err != nil {
+		t.Fatalf("Failed acquiring the execution slot: %s", err)
+	}
+	// the transaction context exists, so that sending the message fails
+	if _, err := handler.createTxContext(context.Background(), "testchainid", "txid", nil, nil); err != nil {
+		t.Fatalf("Failed creating the transaction context: %s", err)
+	}
+	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_TRANSACTION, Txid: "txid"}
+
+	_, err := chaincodeSupport.Execute(context.Background(), cccid, msg, 100*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "waiting to execute") {
+		t.Fatalf("Expected the execution to time out waiting for a slot, got %v", err)
+	}
+
+	nested := context.WithValue(context.Background(), nestedExecutionKey, true)
+	_, err = chaincodeSupport.Execute(nested, cccid, msg, 100*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "exists") {
+		t.Fatalf("Expected the nested execution to be sent without a slot, got %v", err)
+	}
+}
+
+//TestNotifyConcurrentTxContexts checks that the transactions executed at the
+//same time by a chaincode are notified independently of each other
+func TestNotifyConcurrentTxContexts(t *testing.T) {
+	handler := &Handler{txCtxs: make(map[string]*transactionContext)}
+	var txctxs []*transactionContext
+	for i := 0; i < 10; i++ {
+		txctx, err := handler.createTxContext(context.Background(), "chain", fmt.Sprintf("tx%d", i), nil, nil)
+		if err != nil {
+			t.Fatalf("Failed creating transaction context: %s", err)
+		}
+		txctxs = append(txctxs, txctx)
+	}
+
+	var wg sync.WaitGroup
+	for i := len(txctxs) - 1; i >= 0; i-- {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			handler.notify(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: fmt.Sprintf("tx%d", i)})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, txctx := range txctxs {
+		if msg := <-txctx.responseNotifier; msg.Txid != fmt.Sprintf("tx%d", i) {
+			t.Fatalf("Transaction tx%d got the response of %s", i, msg.Txid)
+		}
+	}
+
+	//a second response, e.g. after the stream was terminated, must not block
+	handler.notify(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Txid: "tx0"})
+	handler.notify(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_COMPLETED, Txid: "tx0"})
+}
@@ -218,7 +218,7 @@ func endTxSimulation(chainID string, txsim ledger.TxSimulator, _ []byte, commit
 			}
 
 			// assemble a (signed) proposal response message
-			resp, err := putils.CreateProposalResponse(prop.Header, prop.Payload, &pb.Response{Status: 200}, txSimulationResults, nil, nil, signer)
+			resp, err := putils.CreateProposalResponse(prop.Header, prop.Payload, &pb.Response{Status: 200}, txSimulationResults, nil, nil, nil, signer)
 			if err != nil {
 				return err
 			}
@@ -724,7 +724,7 @@ func runChaincodeInvokeChaincode(t *testing.T, chainID1 string, chainID2 string,
 	// Therefore the chaincode invocation should fail.
 	pm := peer.GetPolicyManager(chainID1)
 	pm.(*mockpolicies.Manager).PolicyMap = map[string]policies.Policy{
-		policies.ChannelApplicationWriters: &CreatorPolicy{Creators: [][]byte{[]byte("Alice")}},
+		policies.ChannelApplicationReaders: &CreatorPolicy{Creators: [][]byte{[]byte("Alice")}},
 	}
 
 	pm = peer.GetPolicyManager(chainID2)
@@ -761,8 +761,24 @@ func runChaincodeInvokeChaincode(t *testing.T, chainID1 string, chainID2 string,
 	args = util.ToChaincodeArgs(f, cid, "e", "1", chainID1)
 
 	spec2 = &pb.ChaincodeSpec{Type: 1, ChaincodeId: cID2, Input: &pb.ChaincodeInput{Args: args}}
-	// Invoke chaincode
-	//var uuid string
+
+	// The first chaincode is invoked in read-only mode on the other channel,
+	// it should not be able to change its state
+	_, _, _, err = invoke(ctxt, chainID2, spec2, 2, []byte("Alice"))
+	if err == nil {
+		t.Fail()
+		t.Logf("Alice invoking <%s> to change the state of another channel should fail. It did not happen instead: %s", chaincodeID2, err)
+		theChaincodeSupport.Stop(ctxt, cccid1, &pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec1})
+		theChaincodeSupport.Stop(ctxt, cccid2, &pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec2})
+		theChaincodeSupport.Stop(ctxt, cccid3, &pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec3})
+		return
+	}
+
+	// - Query second chaincode passing the first chaincode's name, which will in turn query the first chaincode
+	f = "query"
+	args = util.ToChaincodeArgs(f, "e", cid, "a", chainID1)
+
+	spec2 = &pb.ChaincodeSpec{Type: 1, ChaincodeId: cID2, Input: &pb.ChaincodeInput{Args: args}}
 
 	// Bob should not be able to call
 	_, _, _, err = invoke(ctxt, chainID2, spec2, 2, []byte("Bob"))
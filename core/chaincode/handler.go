@@ -20,10 +20,12 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/policies"
@@ -68,6 +70,14 @@ type transactionContext struct {
 
 	txsimulator          ledger.TxSimulator
 	historyQueryExecutor ledger.HistoryQueryExecutor
+
+	// metadata written by the transaction, by key, which the next metadata
+	// writes of the same keys update
+	metadataWrites map[string]map[string][]byte
+
+	// withdraws the txsimulator from the system chaincode reading the state
+	// through it, nil when the chaincode is not a system chaincode
+	releaseState func()
 }
 
 type nextStateInfo struct {
@@ -115,6 +125,10 @@ type Handler struct {
 	nextState chan *nextStateInfo
 
 	policyChecker policy.PolicyChecker
+
+	// executionSlots bounds the number of transactions executed at the same
+	// time by the chaincode, nil when not bounded
+	executionSlots chan struct{}
 }
 
 func shorttxid(txid string) string {
@@ -204,17 +218,21 @@ func (handler *Handler) createTxContext(ctxt context.Context, chainID string, tx
 	}
 	txctx := &transactionContext{chainID: chainID, signedProp: signedProp,
 		proposal: prop, responseNotifier: make(chan *pb.ChaincodeMessage, 1),
-		queryIteratorMap: make(map[string]commonledger.ResultsIterator)}
+		queryIteratorMap: make(map[string]commonledger.ResultsIterator),
+		metadataWrites:   make(map[string]map[string][]byte)}
 	handler.txCtxs[txid] = txctx
 	txctx.txsimulator = getTxSimulator(ctxt)
 	txctx.historyQueryExecutor = getHistoryQueryExecutor(ctxt)
+	if txctx.txsimulator != nil && sysccprovider.GetSystemChaincodeProvider().IsSysCC(handler.getCCRootName()) {
+		txctx.releaseState = sysccprovider.RegisterTxQueryExecutor(ctxt, chainID, txid, txctx.txsimulator)
+	}
 
 	return txctx, nil
 }
 
 func (handler *Handler) getTxContext(txid string) *transactionContext {
-	handler.Lock()
-	defer handler.Unlock()
+	handler.RLock()
+	defer handler.RUnlock()
 	return handler.txCtxs[txid]
 }
 
@@ -222,6 +240,9 @@ func (handler *Handler) deleteTxContext(txid string) {
 	handler.Lock()
 	defer handler.Unlock()
 	if handler.txCtxs != nil {
+		if txctx := handler.txCtxs[txid]; txctx != nil && txctx.releaseState != nil {
+			txctx.releaseState()
+		}
 		delete(handler.txCtxs, txid)
 	}
 }
@@ -234,8 +255,8 @@ func (handler *Handler) putQueryIterator(txContext *transactionContext, txid str
 }
 
 func (handler *Handler) getQueryIterator(txContext *transactionContext, txid string) commonledger.ResultsIterator {
-	handler.Lock()
-	defer handler.Unlock()
+	handler.RLock()
+	defer handler.RUnlock()
 	return txContext.queryIteratorMap[txid]
 }
 
@@ -246,7 +267,7 @@ func (handler *Handler) deleteQueryIterator(txContext *transactionContext, txid
 }
 
 // Check if the transactor is allow to call this chaincode on this channel
-func (handler *Handler) checkACL(signedProp *pb.SignedProposal, proposal *pb.Proposal, calledCC *ccParts) error {
+func (handler *Handler) checkACL(signedProp *pb.SignedProposal, proposal *pb.Proposal, calledCC *ccParts, readOnly bool) error {
 	// ensure that we don't invoke a system chaincode
 	// that is not invokable through a cc2cc invocation
 	if sysccprovider.GetSystemChaincodeProvider().IsSysCCAndNotInvokableCC2CC(calledCC.name) {
@@ -270,6 +291,11 @@ func (handler *Handler) checkACL(signedProp *pb.SignedProposal, proposal *pb.Pro
 		return fmt.Errorf("Signed Proposal must not be nil from caller [%s]", calledCC.String())
 	}
 
+	// a chaincode on another channel is invoked in read-only mode,
+	// so the creator only needs to be a reader of that channel
+	if readOnly {
+		return handler.policyChecker.CheckPolicy(calledCC.suffix, policies.ChannelApplicationReaders, signedProp)
+	}
 	return handler.policyChecker.CheckPolicy(calledCC.suffix, policies.ChannelApplicationWriters, signedProp)
 }
 
@@ -294,9 +320,25 @@ func (handler *Handler) deregister() error {
 	if handler.registered {
 		handler.chaincodeSupport.deregisterHandler(handler)
 	}
+	handler.abortTxContexts()
 	return nil
 }
 
+// abortTxContexts notifies the transactions in progress that the stream of the
+// chaincode ended, so that they fail now instead of after their timeout
+func (handler *Handler) abortTxContexts() {
+	handler.Lock()
+	defer handler.Unlock()
+	for txid, txctx := range handler.txCtxs {
+		msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte("chaincode stream terminated"), Txid: txid}
+		select {
+		case txctx.responseNotifier <- msg:
+		default:
+			// already notified of its completion
+		}
+	}
+}
+
 func (handler *Handler) triggerNextState(msg *pb.ChaincodeMessage, send bool) {
 	//this will send Async
 	handler.nextState <- &nextStateInfo{msg: msg, sendToCC: send, sendSync: false}
@@ -330,6 +372,9 @@ func (handler *Handler) processStream() error {
 
 	//catch send errors and bail now that sends aren't synchronous
 	errc := make(chan error, 1)
+
+	//keepalive periods without any message from the chaincode
+	missedKeepalives := 0
 	for {
 		in = nil
 		err = nil
@@ -369,6 +414,7 @@ func (handler *Handler) processStream() error {
 
 			// we can spin off another Recv again
 			recv = true
+			missedKeepalives = 0
 
 			if in.Type == pb.ChaincodeMessage_KEEPALIVE {
 				chaincodeLogger.Debug("Received KEEPALIVE Response")
@@ -390,6 +436,17 @@ func (handler *Handler) processStream() error {
 				continue
 			}
 
+			//a chaincode answers keepalives even while executing transactions, one
+			//that stays silent is hung: fail its transactions now
+			if maxMissed := handler.chaincodeSupport.keepaliveMaxMissed; maxMissed > 0 {
+				if missedKeepalives >= maxMissed {
+					err = fmt.Errorf("Chaincode did not respond to %d keepalives, ending chaincode support stream", missedKeepalives)
+					chaincodeLogger.Errorf("%s", err)
+					return err
+				}
+				missedKeepalives++
+			}
+
 			//if no error message from serialSend, KEEPALIVE happy, and don't care about error
 			//(maybe it'll work later)
 			handler.serialSendAsync(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_KEEPALIVE}, nil)
@@ -433,6 +490,9 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 		ChatStream: peerChatStream,
 	}
 	v.chaincodeSupport = chaincodeSupport
+	if chaincodeSupport.maxConcurrentExecutions > 0 {
+		v.executionSlots = make(chan struct{}, chaincodeSupport.maxConcurrentExecutions)
+	}
 	//we want this to block
 	v.nextState = make(chan *nextStateInfo)
 
@@ -447,6 +507,8 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			{Name: pb.ChaincodeMessage_INVOKE_CHAINCODE.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_COMPLETED.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_STATE.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_GET_STATE_METADATA.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_PUT_STATE_METADATA.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_STATE_BY_RANGE.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_QUERY_RESULT.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY.String(), Src: []string{readystate}, Dst: readystate},
@@ -461,6 +523,7 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			"before_" + pb.ChaincodeMessage_REGISTER.String():           func(e *fsm.Event) { v.beforeRegisterEvent(e, v.FSM.Current()) },
 			"before_" + pb.ChaincodeMessage_COMPLETED.String():          func(e *fsm.Event) { v.beforeCompletedEvent(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_STATE.String():           func(e *fsm.Event) { v.afterGetState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_GET_STATE_METADATA.String():  func(e *fsm.Event) { v.afterGetStateMetadata(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_STATE_BY_RANGE.String():  func(e *fsm.Event) { v.afterGetStateByRange(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_QUERY_RESULT.String():    func(e *fsm.Event) { v.afterGetQueryResult(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_HISTORY_FOR_KEY.String(): func(e *fsm.Event) { v.afterGetHistoryForKey(e, v.FSM.Current()) },
@@ -468,6 +531,7 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			"after_" + pb.ChaincodeMessage_QUERY_STATE_CLOSE.String():   func(e *fsm.Event) { v.afterQueryStateClose(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_PUT_STATE.String():           func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_DEL_STATE.String():           func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_PUT_STATE_METADATA.String():  func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_INVOKE_CHAINCODE.String():    func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
 			"enter_" + establishedstate:                                 func(e *fsm.Event) { v.enterEstablishedState(e, v.FSM.Current()) },
 			"enter_" + readystate:                                       func(e *fsm.Event) { v.enterReadyState(e, v.FSM.Current()) },
@@ -570,20 +634,51 @@ func (handler *Handler) beforeRegisterEvent(e *fsm.Event, state string) {
 	}
 }
 
+//notify hands the response to the transaction waiting for it. The registry
+//is only locked for the lookup so that completing a transaction does not hold
+//up the other transactions executed by the chaincode
 func (handler *Handler) notify(msg *pb.ChaincodeMessage) {
-	handler.Lock()
-	defer handler.Unlock()
-	tctx := handler.txCtxs[msg.Txid]
+	tctx := handler.getTxContext(msg.Txid)
 	if tctx == nil {
 		chaincodeLogger.Debugf("notifier Txid:%s does not exist", msg.Txid)
-	} else {
-		chaincodeLogger.Debugf("notifying Txid:%s", msg.Txid)
-		tctx.responseNotifier <- msg
+		return
+	}
 
-		// clean up queryIteratorMap
-		for _, v := range tctx.queryIteratorMap {
-			v.Close()
-		}
+	chaincodeLogger.Debugf("notifying Txid:%s", msg.Txid)
+	select {
+	case tctx.responseNotifier <- msg:
+	default:
+		// already notified, e.g. the stream was terminated
+		chaincodeLogger.Debugf("notifier Txid:%s already notified", msg.Txid)
+	}
+
+	// clean up queryIteratorMap
+	handler.Lock()
+	iterators := tctx.queryIteratorMap
+	tctx.queryIteratorMap = make(map[string]commonledger.ResultsIterator)
+	handler.Unlock()
+	for _, v := range iterators {
+		v.Close()
+	}
+}
+
+// acquireExecutionSlot blocks until the number of transactions executed by
+// the chaincode is below the configured limit, or the timeout expires
+func (handler *Handler) acquireExecutionSlot(timeout time.Duration) error {
+	if handler.executionSlots == nil {
+		return nil
+	}
+	select {
+	case handler.executionSlots <- struct{}{}:
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("Timeout expired while waiting to execute transaction")
+	}
+}
+
+func (handler *Handler) releaseExecutionSlot() {
+	if handler.executionSlots != nil {
+		<-handler.executionSlots
 	}
 }
 
@@ -689,6 +784,119 @@ func (handler *Handler) handleGetState(msg *pb.ChaincodeMessage) {
 	}()
 }
 
+// afterGetStateMetadata handles a GET_STATE_METADATA request from the chaincode.
+func (handler *Handler) afterGetStateMetadata(e *fsm.Event, state string) {
+	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
+	if !ok {
+		e.Cancel(fmt.Errorf("Received unexpected message type"))
+		return
+	}
+	chaincodeLogger.Debugf("[%s]Received %s, invoking get state metadata from ledger", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE_METADATA)
+
+	// Query ledger for the metadata of the key
+	handler.handleGetStateMetadata(msg)
+}
+
+// Handles query to ledger to get the metadata of a key
+func (handler *Handler) handleGetStateMetadata(msg *pb.ChaincodeMessage) {
+	go func() {
+		// Check if this is the unique state request from this chaincode txid
+		uniqueReq := handler.createTXIDEntry(msg.Txid)
+		if !uniqueReq {
+			// Drop this request
+			chaincodeLogger.Error("Another state request pending for this Txid. Cannot process.")
+			return
+		}
+
+		var serialSendMsg *pb.ChaincodeMessage
+		var txContext *transactionContext
+		txContext, serialSendMsg = handler.isValidTxSim(msg.Txid,
+			"[%s]No ledger context for GetStateMetadata. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+
+		defer func() {
+			handler.deleteTXIDEntry(msg.Txid)
+			if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
+				chaincodeLogger.Debugf("[%s]handleGetStateMetadata serial send %s",
+					shorttxid(serialSendMsg.Txid), serialSendMsg.Type)
+			}
+			handler.serialSendAsync(serialSendMsg, nil)
+		}()
+
+		if txContext == nil {
+			return
+		}
+
+		getStateMetadata := &pb.GetStateMetadata{}
+		if err := proto.Unmarshal(msg.Payload, getStateMetadata); err != nil {
+			chaincodeLogger.Errorf("[%s]Failed to unmarshall get state metadata request. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid}
+			return
+		}
+
+		chaincodeID := handler.getCCRootName()
+		metadata, err := txContext.txsimulator.GetStateMetadata(chaincodeID, getStateMetadata.Key)
+		if err != nil {
+			chaincodeLogger.Errorf("[%s]Failed to get chaincode state metadata(%s). Sending %s",
+				shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid}
+			return
+		}
+
+		result := &pb.StateMetadataResult{}
+		metakeys := make([]string, 0, len(metadata))
+		for metakey := range metadata {
+			metakeys = append(metakeys, metakey)
+		}
+		sort.Strings(metakeys)
+		for _, metakey := range metakeys {
+			result.Entries = append(result.Entries, &pb.StateMetadata{Metakey: metakey, Value: metadata[metakey]})
+		}
+		resultBytes, err := proto.Marshal(result)
+		if err != nil {
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid}
+			return
+		}
+
+		chaincodeLogger.Debugf("[%s]Got state metadata. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_RESPONSE)
+		serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: resultBytes, Txid: msg.Txid}
+	}()
+}
+
+// putStateMetadata sets one entry of the metadata of a key, on top of the
+// metadata the transaction already wrote for the key, or else of the committed one
+func putStateMetadata(txContext *transactionContext, chaincodeID string, putStateMetadata *pb.PutStateMetadata) error {
+	if putStateMetadata.Metadata == nil {
+		return fmt.Errorf("no metadata entry provided for key %s", putStateMetadata.Key)
+	}
+	ac, ok := peer.GetApplicationConfig(txContext.chainID)
+	if !ok || !ac.HasCapability(config.KeyMetadataCapability) {
+		return fmt.Errorf("channel %s does not require the %s capability, the metadata of keys cannot be written", txContext.chainID, config.KeyMetadataCapability)
+	}
+
+	metadata, ok := txContext.metadataWrites[putStateMetadata.Key]
+	if !ok {
+		committed, err := txContext.txsimulator.GetStateMetadata(chaincodeID, putStateMetadata.Key)
+		if err != nil {
+			return err
+		}
+		metadata = make(map[string][]byte)
+		for metakey, value := range committed {
+			metadata[metakey] = value
+		}
+	}
+
+	if len(putStateMetadata.Metadata.Value) == 0 {
+		delete(metadata, putStateMetadata.Metadata.Metakey)
+	} else {
+		metadata[putStateMetadata.Metadata.Metakey] = putStateMetadata.Metadata.Value
+	}
+	if err := txContext.txsimulator.SetStateMetadata(chaincodeID, putStateMetadata.Key, metadata); err != nil {
+		return err
+	}
+	txContext.metadataWrites[putStateMetadata.Key] = metadata
+	return nil
+}
+
 // afterGetStateByRange handles a GET_STATE_BY_RANGE request from the chaincode.
 func (handler *Handler) afterGetStateByRange(e *fsm.Event, state string) {
 	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
@@ -1119,6 +1327,13 @@ func (handler *Handler) handleGetHistoryForKey(msg *pb.ChaincodeMessage) {
 		if txContext == nil {
 			return
 		}
+		if txContext.historyQueryExecutor == nil {
+			// query-only proposals are executed without history
+			payload := []byte("The history of keys is not available for this invocation")
+			chaincodeLogger.Errorf("[%s]No history query executor for GetHistoryForKey. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+			return
+		}
 		chaincodeID := handler.getCCRootName()
 
 		historyIter, err := txContext.historyQueryExecutor.GetHistoryForKey(chaincodeID, getHistoryForKey.Key)
@@ -1247,6 +1462,17 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 			}
 
 			err = txContext.txsimulator.SetState(chaincodeID, putStateInfo.Key, putStateInfo.Value)
+		} else if msg.Type.String() == pb.ChaincodeMessage_PUT_STATE_METADATA.String() {
+			putStateMetadataInfo := &pb.PutStateMetadata{}
+			unmarshalErr := proto.Unmarshal(msg.Payload, putStateMetadataInfo)
+			if unmarshalErr != nil {
+				payload := []byte(unmarshalErr.Error())
+				chaincodeLogger.Debugf("[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+				triggerNextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: payload, Txid: msg.Txid}
+				return
+			}
+
+			err = putStateMetadata(txContext, chaincodeID, putStateMetadataInfo)
 		} else if msg.Type.String() == pb.ChaincodeMessage_DEL_STATE.String() {
 			// Invoke ledger to delete state
 			key := string(msg.Payload)
@@ -1278,7 +1504,12 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 					shorttxid(msg.Txid), calledCcParts.name, calledCcParts.suffix)
 			}
 
-			err := handler.checkACL(txContext.signedProp, txContext.proposal, calledCcParts)
+			// A chaincode on a different channel is invoked in read-only mode: the
+			// transaction is validated on the channel of the caller, whose peers
+			// neither check the reads nor apply the writes on the other channel
+			readOnly := calledCcParts.suffix != txContext.chainID
+
+			err := handler.checkACL(txContext.signedProp, txContext.proposal, calledCcParts, readOnly)
 			if err != nil {
 				chaincodeLogger.Errorf("[%s] C-call-C %s on channel %s failed check ACL [%v]: [%s]",
 					shorttxid(msg.Txid), calledCcParts.name, calledCcParts.suffix, txContext.signedProp, err)
@@ -1288,11 +1519,11 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 			}
 
 			// Set up a new context for the called chaincode if on a different channel
-			// We grab the called channel's ledger simulator to hold the new state
+			// We grab the called channel's ledger to read its state
 			ctxt := context.Background()
 			txsim := txContext.txsimulator
 			historyQueryExecutor := txContext.historyQueryExecutor
-			if calledCcParts.suffix != txContext.chainID {
+			if readOnly {
 				lgr := peer.GetLedger(calledCcParts.suffix)
 				if lgr == nil {
 					payload := "Failed to find ledger for called channel " + calledCcParts.suffix
@@ -1300,17 +1531,24 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 						Payload: []byte(payload), Txid: msg.Txid}
 					return
 				}
-				txsim2, err2 := lgr.NewTxSimulator()
+				qe, err2 := lgr.NewQueryExecutor()
+				if err2 != nil {
+					triggerNextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR,
+						Payload: []byte(err2.Error()), Txid: msg.Txid}
+					return
+				}
+				defer qe.Done()
+				txsim = NewReadOnlyTxSimulator(calledCcParts.suffix, qe)
+				historyQueryExecutor, err2 = lgr.NewHistoryQueryExecutor()
 				if err2 != nil {
 					triggerNextStateMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR,
 						Payload: []byte(err2.Error()), Txid: msg.Txid}
 					return
 				}
-				defer txsim2.Done()
-				txsim = txsim2
 			}
 			ctxt = context.WithValue(ctxt, TXSimulatorKey, txsim)
 			ctxt = context.WithValue(ctxt, HistoryQueryExecutorKey, historyQueryExecutor)
+			ctxt = context.WithValue(ctxt, nestedExecutionKey, true)
 
 			if chaincodeLogger.IsEnabledFor(logging.DEBUG) {
 				chaincodeLogger.Debugf("[%s] calling lscc to get chaincode data for %s on channel %s",
@@ -1356,8 +1594,7 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 				return
 			}
 
-			// TODO: Need to handle timeout correctly
-			timeout := time.Duration(30000) * time.Millisecond
+			timeout := handler.chaincodeSupport.getExecuteTimeout(calledCcParts.name)
 
 			ccMsg, _ := createCCMessage(pb.ChaincodeMessage_TRANSACTION, msg.Txid, chaincodeInput)
 
@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger"
+)
+
+// readOnlyTxSimulator executes chaincodes against a read-only view of the
+// state of a channel. It serves the chaincodes invoked from a chaincode of
+// another channel: the transaction is endorsed and validated on the channel
+// of the calling chaincode only, which neither records the reads nor applies
+// the writes made on the other channel, so the writes are rejected rather
+// than silently discarded. It also serves the proposals hinted as query-only,
+// which are not meant to be submitted as transactions.
+type readOnlyTxSimulator struct {
+	ledger.QueryExecutor
+	chainID string
+}
+
+// NewReadOnlyTxSimulator returns a TxSimulator which reads the state of the
+// channel through the given QueryExecutor, and fails the writes
+func NewReadOnlyTxSimulator(chainID string, qe ledger.QueryExecutor) ledger.TxSimulator {
+	return &readOnlyTxSimulator{QueryExecutor: qe, chainID: chainID}
+}
+
+func (s *readOnlyTxSimulator) errReadOnly() error {
+	return fmt.Errorf("The state of channel %s is read-only for this invocation", s.chainID)
+}
+
+// SetState is not allowed on a read-only simulator
+func (s *readOnlyTxSimulator) SetState(namespace string, key string, value []byte) error {
+	return s.errReadOnly()
+}
+
+// DeleteState is not allowed on a read-only simulator
+func (s *readOnlyTxSimulator) DeleteState(namespace string, key string) error {
+	return s.errReadOnly()
+}
+
+// SetStateMultipleKeys is not allowed on a read-only simulator
+func (s *readOnlyTxSimulator) SetStateMultipleKeys(namespace string, kvs map[string][]byte) error {
+	return s.errReadOnly()
+}
+
+// SetStateMetadata is not allowed on a read-only simulator
+func (s *readOnlyTxSimulator) SetStateMetadata(namespace, key string, metadata map[string][]byte) error {
+	return s.errReadOnly()
+}
+
+// ExecuteUpdate is not allowed on a read-only simulator
+func (s *readOnlyTxSimulator) ExecuteUpdate(query string) error {
+	return s.errReadOnly()
+}
+
+// GetTxSimulationResults returns no results, the reads are not recorded
+func (s *readOnlyTxSimulator) GetTxSimulationResults() ([]byte, error) {
+	return nil, nil
+}
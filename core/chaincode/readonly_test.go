@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockQueryExecutor struct {
+	ledger.QueryExecutor
+	state map[string][]byte
+}
+
+func (qe *mockQueryExecutor) GetState(namespace string, key string) ([]byte, error) {
+	return qe.state[namespace+"/"+key], nil
+}
+
+func TestReadOnlyTxSimulator(t *testing.T) {
+	qe := &mockQueryExecutor{state: map[string][]byte{"example02/a": []byte("100")}}
+	txsim := NewReadOnlyTxSimulator("otherchain", qe)
+
+	value, err := txsim.GetState("example02", "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("100"), value, "The reads should be served by the query executor")
+
+	assert.Error(t, txsim.SetState("example02", "a", []byte("90")))
+	assert.Error(t, txsim.DeleteState("example02", "a"))
+	assert.Error(t, txsim.SetStateMultipleKeys("example02", map[string][]byte{"a": []byte("90")}))
+	assert.Error(t, txsim.ExecuteUpdate("update"))
+	results, err := txsim.GetTxSimulationResults()
+	assert.NoError(t, err)
+	assert.Nil(t, results, "The reads should not be recorded")
+}
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"golang.org/x/net/context"
+)
+
+// ReapOrphansEnabled returns whether the leftovers of the chaincode containers
+// of the peer are to be removed when the peer starts
+func (chaincodeSupport *ChaincodeSupport) ReapOrphansEnabled() bool {
+	return chaincodeSupport.reapOrphans && !chaincodeSupport.userRunsCC
+}
+
+// ReapOrphans removes the chaincode containers of the peer which are not
+// running, and the images of the chaincodes which are not instantiated on any
+// of the given chains. Only the containers and images of the chaincodes
+// installed or instantiated on the peer are considered, as the peer launched
+// no other. It should be invoked before any chaincode is launched. Nothing is
+// removed if the instantiated chaincodes of a chain cannot be read.
+func (chaincodeSupport *ChaincodeSupport) ReapOrphans(chainIDs []string) error {
+	var keep []ccintf.CCID
+	for _, chainID := range chainIDs {
+		ccs, err := getInstantiatedChaincodes(chainID)
+		if err != nil {
+			return err
+		}
+		for _, cc := range ccs {
+			keep = append(keep, chaincodeSupport.reapCCID(cc.Name, cc.Version))
+		}
+	}
+
+	installed, err := ccprovider.GetInstalledChaincodes()
+	if err != nil {
+		return err
+	}
+	var candidates []ccintf.CCID
+	for _, cc := range installed.Chaincodes {
+		candidates = append(candidates, chaincodeSupport.reapCCID(cc.Name, cc.Version))
+	}
+	return container.ReapOrphans(context.Background(), candidates, keep)
+}
+
+// reapCCID returns the ID the containers and images of a chaincode of the peer are named after
+func (chaincodeSupport *ChaincodeSupport) reapCCID(name string, version string) ccintf.CCID {
+	return ccintf.CCID{
+		ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: name}},
+		NetworkID:     chaincodeSupport.peerNetworkID,
+		PeerID:        chaincodeSupport.peerID,
+		Version:       version,
+	}
+}
@@ -19,6 +19,8 @@ limitations under the License.
 package shim
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"errors"
 	"flag"
 	"fmt"
@@ -123,7 +125,8 @@ func IsEnabledForLogLevel(logLevel string) bool {
 
 // SetupChaincodeLogging sets the chaincode logging format and the level
 // to the values of CORE_CHAINCODE_LOGFORMAT and CORE_CHAINCODE_LOGLEVEL set
-// from core.yaml by chaincode_support.go
+// from core.yaml by chaincode_support.go. CORE_CHAINCODE_DEFAULTLOGLEVEL, when
+// set, is the level of the loggers created by the chaincode with NewLogger
 func SetupChaincodeLogging() {
 	viper.SetEnvPrefix("CORE")
 	viper.AutomaticEnv()
@@ -134,6 +137,14 @@ func SetupChaincodeLogging() {
 	logFormat := flogging.SetFormat(viper.GetString("chaincode.logFormat"))
 	flogging.InitBackend(logFormat, logOutput)
 
+	if defaultLogLevelString := viper.GetString("chaincode.defaultLogLevel"); defaultLogLevelString != "" {
+		if defaultLogLevel, err := LogLevel(defaultLogLevelString); err == nil {
+			logging.SetLevel(logging.Level(defaultLogLevel), "")
+		} else {
+			chaincodeLogger.Warningf("Error: %s for chaincode default log level: %s", err, defaultLogLevelString)
+		}
+	}
+
 	chaincodeLogLevelString := viper.GetString("chaincode.logLevel")
 	if chaincodeLogLevelString == "" {
 		shimLogLevelDefault := logging.Level(shimLoggingLevel)
@@ -358,6 +369,24 @@ func (stub *ChaincodeStub) DelState(key string) error {
 	return stub.handler.handleDelState(key, stub.TxID)
 }
 
+// ValidationParameterKey is the entry of the metadata of a key which holds
+// its key-level endorsement policy
+const ValidationParameterKey = "VALIDATION_PARAMETER"
+
+// SetStateValidationParameter sets the key-level endorsement policy of `key`.
+func (stub *ChaincodeStub) SetStateValidationParameter(key string, ep []byte) error {
+	return stub.handler.handlePutStateMetadataEntry(key, ValidationParameterKey, ep, stub.TxID)
+}
+
+// GetStateValidationParameter returns the key-level endorsement policy of `key`.
+func (stub *ChaincodeStub) GetStateValidationParameter(key string) ([]byte, error) {
+	metadata, err := stub.handler.handleGetStateMetadata(key, stub.TxID)
+	if err != nil {
+		return nil, err
+	}
+	return metadata[ValidationParameterKey], nil
+}
+
 // CommonIterator allows a chaincode to iterate over a set of
 // key/value pairs in the state.
 type CommonIterator struct {
@@ -447,6 +476,9 @@ func createCompositeKey(objectType string, attributes []string) (string, error)
 }
 
 func splitCompositeKey(compositeKey string) (string, []string, error) {
+	if len(compositeKey) == 0 || compositeKey[len(compositeKey)-1] != minUnicodeRuneValue {
+		return "", nil, fmt.Errorf("Not a composite key, it should end with %#U: [%x]", minUnicodeRuneValue, compositeKey)
+	}
 	componentIndex := 0
 	components := []string{}
 	for i := 0; i < len(compositeKey); i++ {
@@ -455,6 +487,11 @@ func splitCompositeKey(compositeKey string) (string, []string, error) {
 			componentIndex = i + 1
 		}
 	}
+	for _, component := range components {
+		if err := validateCompositeKeyAttribute(component); err != nil {
+			return "", nil, err
+		}
+	}
 	return components[0], components[1:], nil
 }
 
@@ -482,14 +519,29 @@ func (stub *ChaincodeStub) GetStateByPartialCompositeKey(objectType string, attr
 }
 
 func getStateByPartialCompositeKey(stub ChaincodeStubInterface, objectType string, attributes []string) (StateQueryIteratorInterface, error) {
-	partialCompositeKey, _ := stub.CreateCompositeKey(objectType, attributes)
-	keysIter, err := stub.GetStateByRange(partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue))
+	startKey, endKey, err := partialCompositeKeyRange(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+	keysIter, err := stub.GetStateByRange(startKey, endKey)
 	if err != nil {
 		return nil, fmt.Errorf("Error fetching rows: %s", err)
 	}
 	return keysIter, nil
 }
 
+// partialCompositeKeyRange returns the range of the composite keys starting with the
+// given attributes. Since attributes cannot contain U+10FFFF and are terminated with
+// U+0000, the composite keys of another object type or of an attribute the given one
+// is a prefix of (e.g. "ab" for "a") fall outside of the range
+func partialCompositeKeyRange(objectType string, attributes []string) (string, string, error) {
+	partialCompositeKey, err := createCompositeKey(objectType, attributes)
+	if err != nil {
+		return "", "", err
+	}
+	return partialCompositeKey, partialCompositeKey + string(maxUnicodeRuneValue), nil
+}
+
 func (iter *StateQueryIterator) Next() (*queryresult.KV, error) {
 	result, err := iter.nextResult(STATE_QUERY_RESULT)
 	if err != nil {
@@ -669,6 +721,36 @@ func (stub *ChaincodeStub) GetTxTimestamp() (*timestamp.Timestamp, error) {
 	return chdr.GetTimestamp(), nil
 }
 
+// GetTxSeed returns a seed derived from the transaction ID, so it will be the
+// same across all endorsers.
+func (stub *ChaincodeStub) GetTxSeed() (int64, error) {
+	return txSeed(stub.TxID)
+}
+
+// txSeed derives a seed from the first bytes of the hash of a transaction ID
+func txSeed(txID string) (int64, error) {
+	if txID == "" {
+		return 0, errors.New("the transaction ID is not set")
+	}
+	hash := sha256.Sum256([]byte(txID))
+	return int64(binary.BigEndian.Uint64(hash[:8])), nil
+}
+
+// GetTLSCertHash returns the hash of the TLS client certificate of the
+// creator, as bound to the transaction ChannelHeader.
+func (stub *ChaincodeStub) GetTLSCertHash() ([]byte, error) {
+	hdr, err := utils.GetHeader(stub.proposal.Header)
+	if err != nil {
+		return nil, err
+	}
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	return chdr.TlsCertHash, nil
+}
+
 // ------------- ChaincodeEvent API ----------------------
 
 // SetEvent saves the event to be sent when a transaction is made part of a block
@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ownership provides helpers for chaincodes which store assets owned
+// by organizations: the transactions writing an asset must then be endorsed
+// by all of its owners, rather than satisfy the endorsement policy of the
+// chaincode, and the owners transfer the asset to other organizations.
+package ownership
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+)
+
+// Policy returns the marshaled endorsement policy requiring a signature from
+// a member of each of the owners, which are MSP identifiers
+func Policy(owners ...string) ([]byte, error) {
+	if len(owners) == 0 {
+		return nil, fmt.Errorf("at least one owner is required")
+	}
+	sorted := make([]string, len(owners))
+	copy(sorted, owners)
+	sort.Strings(sorted)
+
+	b := cauthdsl.NewEnvelopeBuilder()
+	signedBy := make([]*cb.SignaturePolicy, len(sorted))
+	for i, owner := range sorted {
+		if owner == "" {
+			return nil, fmt.Errorf("empty owner")
+		}
+		if i > 0 && owner == sorted[i-1] {
+			return nil, fmt.Errorf("duplicate owner %s", owner)
+		}
+		signedBy[i] = b.SignedBy(cauthdsl.Member(owner))
+	}
+
+	return proto.Marshal(b.Envelope(cauthdsl.NOutOf(int32(len(signedBy)), signedBy)))
+}
+
+// SetStateWithOwner writes the value of key, and sets its key-level
+// endorsement policy so that the next transactions writing key must be
+// endorsed by all of the owners
+func SetStateWithOwner(stub shim.ChaincodeStubInterface, key string, value []byte, owners ...string) error {
+	ep, err := Policy(owners...)
+	if err != nil {
+		return err
+	}
+	if err = stub.PutState(key, value); err != nil {
+		return err
+	}
+	return stub.SetStateValidationParameter(key, ep)
+}
+
+// TransferOwnership replaces the owners of an existing key with newOwners.
+// The transaction is validated against the key-level endorsement policy
+// committed before, so it has to be endorsed by all of the current owners.
+func TransferOwnership(stub shim.ChaincodeStubInterface, key string, newOwners ...string) error {
+	value, err := stub.GetState(key)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return fmt.Errorf("key %s does not exist", key)
+	}
+	ep, err := Policy(newOwners...)
+	if err != nil {
+		return err
+	}
+	return stub.SetStateValidationParameter(key, ep)
+}
+
+// GetOwners returns the MSP identifiers of the owners of key, or nil if key
+// has no key-level endorsement policy
+func GetOwners(stub shim.ChaincodeStubInterface, key string) ([]string, error) {
+	ep, err := stub.GetStateValidationParameter(key)
+	if err != nil || ep == nil {
+		return nil, err
+	}
+	envelope := &cb.SignaturePolicyEnvelope{}
+	if err = proto.Unmarshal(ep, envelope); err != nil {
+		return nil, fmt.Errorf("invalid endorsement policy for key %s: %s", key, err)
+	}
+
+	var owners []string
+	for _, principal := range envelope.Identities {
+		if principal.PrincipalClassification != msp.MSPPrincipal_ROLE {
+			return nil, fmt.Errorf("the endorsement policy of key %s is not an ownership policy", key)
+		}
+		role := &msp.MSPRole{}
+		if err = proto.Unmarshal(principal.Principal, role); err != nil {
+			return nil, fmt.Errorf("invalid principal in the endorsement policy of key %s: %s", key, err)
+		}
+		owners = append(owners, role.MspIdentifier)
+	}
+	return owners, nil
+}
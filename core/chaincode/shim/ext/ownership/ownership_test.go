@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ownership
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy(t *testing.T) {
+	ep, err := Policy("Org2MSP", "Org1MSP")
+	assert.NoError(t, err)
+	envelope := &cb.SignaturePolicyEnvelope{}
+	assert.NoError(t, proto.Unmarshal(ep, envelope))
+	assert.Len(t, envelope.Identities, 2)
+	nOutOf := envelope.Policy.GetNOutOf()
+	if assert.NotNil(t, nOutOf) {
+		assert.Equal(t, int32(2), nOutOf.N, "Should require all of the owners")
+		assert.Len(t, nOutOf.Policies, 2)
+	}
+
+	_, err = Policy()
+	assert.Error(t, err, "Should require an owner")
+	_, err = Policy("Org1MSP", "Org1MSP")
+	assert.Error(t, err, "Should reject duplicate owners")
+	_, err = Policy("")
+	assert.Error(t, err, "Should reject an empty owner")
+}
+
+func TestOwnership(t *testing.T) {
+	stub := shim.NewMockStub("ownership", nil)
+	stub.MockTransactionStart("1")
+	defer stub.MockTransactionEnd("1")
+
+	assert.Error(t, TransferOwnership(stub, "asset", "Org2MSP"), "Should not transfer a key which does not exist")
+
+	assert.NoError(t, SetStateWithOwner(stub, "asset", []byte("value"), "Org2MSP", "Org1MSP"))
+	value, err := stub.GetState("asset")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	owners, err := GetOwners(stub, "asset")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP"}, owners)
+
+	assert.NoError(t, TransferOwnership(stub, "asset", "Org3MSP"))
+	owners, err = GetOwners(stub, "asset")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Org3MSP"}, owners)
+	value, _ = stub.GetState("asset")
+	assert.Equal(t, []byte("value"), value, "Should have kept the value of the key")
+
+	assert.Error(t, TransferOwnership(stub, "asset"), "Should require a new owner")
+
+	owners, err = GetOwners(stub, "other")
+	assert.NoError(t, err)
+	assert.Nil(t, owners, "A key without key-level endorsement policy has no owners")
+}
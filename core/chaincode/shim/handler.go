@@ -506,6 +506,104 @@ func (handler *Handler) handleDelState(key string, txid string) error {
 	return errors.New("Incorrect chaincode message received")
 }
 
+// handleGetStateMetadata communicates with the validator to fetch the metadata of a key from the ledger.
+func (handler *Handler) handleGetStateMetadata(key string, txid string) (map[string][]byte, error) {
+	// Check if this is a transaction
+	chaincodeLogger.Debugf("[%s]Inside getstatemetadata", shorttxid(txid))
+	payloadBytes, err := proto.Marshal(&pb.GetStateMetadata{Key: key})
+	if err != nil {
+		return nil, errors.New("Failed to process get state metadata request")
+	}
+
+	// Create the channel on which to communicate the response from validating peer
+	respChan, uniqueReqErr := handler.createChannel(txid)
+	if uniqueReqErr != nil {
+		chaincodeLogger.Debug("Another state request pending for this Txid. Cannot process.")
+		return nil, uniqueReqErr
+	}
+
+	defer handler.deleteChannel(txid)
+
+	// Send GET_STATE_METADATA message to validator chaincode support
+	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_GET_STATE_METADATA, Payload: payloadBytes, Txid: txid}
+	chaincodeLogger.Debugf("[%s]Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE_METADATA)
+	responseMsg, err := handler.sendReceive(msg, respChan)
+	if err != nil {
+		chaincodeLogger.Errorf("[%s]error sending GET_STATE_METADATA %s", shorttxid(txid), err)
+		return nil, errors.New("could not send msg")
+	}
+
+	if responseMsg.Type.String() == pb.ChaincodeMessage_RESPONSE.String() {
+		// Success response
+		chaincodeLogger.Debugf("[%s]GetStateMetadata received payload %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_RESPONSE)
+		result := &pb.StateMetadataResult{}
+		if err = proto.Unmarshal(responseMsg.Payload, result); err != nil {
+			chaincodeLogger.Errorf("[%s]GetStateMetadata received bad payload: %s", shorttxid(responseMsg.Txid), err)
+			return nil, err
+		}
+		metadata := make(map[string][]byte, len(result.Entries))
+		for _, entry := range result.Entries {
+			metadata[entry.Metakey] = entry.Value
+		}
+		return metadata, nil
+	}
+	if responseMsg.Type.String() == pb.ChaincodeMessage_ERROR.String() {
+		// Error response
+		chaincodeLogger.Errorf("[%s]GetStateMetadata received error %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_ERROR)
+		return nil, errors.New(string(responseMsg.Payload[:]))
+	}
+
+	// Incorrect chaincode message received
+	chaincodeLogger.Errorf("[%s]Incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+	return nil, errors.New("Incorrect chaincode message received")
+}
+
+// handlePutStateMetadataEntry communicates with the validator to set one entry
+// of the metadata of a key in the ledger, an empty value removes the entry.
+func (handler *Handler) handlePutStateMetadataEntry(key string, metakey string, value []byte, txid string) error {
+	// Check if this is a transaction
+	chaincodeLogger.Debugf("[%s]Inside putstatemetadata", shorttxid(txid))
+	payload := &pb.PutStateMetadata{Key: key, Metadata: &pb.StateMetadata{Metakey: metakey, Value: value}}
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return errors.New("Failed to process put state metadata request")
+	}
+
+	// Create the channel on which to communicate the response from validating peer
+	respChan, uniqueReqErr := handler.createChannel(txid)
+	if uniqueReqErr != nil {
+		chaincodeLogger.Errorf("[%s]Another state request pending for this Txid. Cannot process.", shorttxid(txid))
+		return uniqueReqErr
+	}
+
+	defer handler.deleteChannel(txid)
+
+	// Send PUT_STATE_METADATA message to validator chaincode support
+	msg := &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_PUT_STATE_METADATA, Payload: payloadBytes, Txid: txid}
+	chaincodeLogger.Debugf("[%s]Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_PUT_STATE_METADATA)
+	responseMsg, err := handler.sendReceive(msg, respChan)
+	if err != nil {
+		chaincodeLogger.Errorf("[%s]error sending PUT_STATE_METADATA %s", shorttxid(msg.Txid), err)
+		return errors.New("could not send msg")
+	}
+
+	if responseMsg.Type.String() == pb.ChaincodeMessage_RESPONSE.String() {
+		// Success response
+		chaincodeLogger.Debugf("[%s]Received %s. Successfully updated state metadata", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_RESPONSE)
+		return nil
+	}
+
+	if responseMsg.Type.String() == pb.ChaincodeMessage_ERROR.String() {
+		// Error response
+		chaincodeLogger.Errorf("[%s]Received %s. Payload: %s", shorttxid(responseMsg.Txid), pb.ChaincodeMessage_ERROR, responseMsg.Payload)
+		return errors.New(string(responseMsg.Payload[:]))
+	}
+
+	// Incorrect chaincode message received
+	chaincodeLogger.Errorf("[%s]Incorrect chaincode message %s received. Expecting %s or %s", shorttxid(responseMsg.Txid), responseMsg.Type, pb.ChaincodeMessage_RESPONSE, pb.ChaincodeMessage_ERROR)
+	return errors.New("Incorrect chaincode message received")
+}
+
 func (handler *Handler) handleGetStateByRange(startKey, endKey string, txid string) (*pb.QueryResponse, error) {
 	// Create the channel on which to communicate the response from validating peer
 	respChan, uniqueReqErr := handler.createChannel(txid)
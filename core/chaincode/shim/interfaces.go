@@ -51,10 +51,16 @@ type ChaincodeStubInterface interface {
 
 	// InvokeChaincode locally calls the specified chaincode `Invoke` using the
 	// same transaction context; that is, chaincode calling chaincode doesn't
-	// create a new transaction message. If the called chaincode is on a different
-	// channel, only the Response is returned to the caller; any PutState calls
-	// will not have any effect on the ledger of the channel; effectively it is
-	// a `Query`. If `channel` is empty, the caller's channel is assumed.
+	// create a new transaction message. If `channel` is empty, the caller's
+	// channel is assumed.
+	// If the called chaincode is on a different channel, it is invoked in
+	// read-only mode: it reads the state of its channel as committed on the
+	// endorsing peer, any PutState or DelState call fails, and only the
+	// Response is returned to the caller. The transaction is validated on the
+	// caller's channel only, whose peers do not check what the called chaincode
+	// read and cannot tell whether the state of the other channel changed
+	// since; the caller trusts the endorsing peers, which have to be members
+	// of both channels, for the Response it got.
 	InvokeChaincode(chaincodeName string, args [][]byte, channel string) pb.Response
 
 	// GetState returns the byte array value specified by the `key`.
@@ -66,6 +72,16 @@ type ChaincodeStubInterface interface {
 	// DelState removes the specified `key` and its value from the ledger.
 	DelState(key string) error
 
+	// SetStateValidationParameter sets the key-level endorsement policy of
+	// `key`, a marshaled SignaturePolicyEnvelope. The transactions writing
+	// `key` must then satisfy it instead of the endorsement policy of the
+	// chaincode. An empty `ep` removes the key-level endorsement policy.
+	SetStateValidationParameter(key string, ep []byte) error
+
+	// GetStateValidationParameter returns the key-level endorsement policy of
+	// `key`, as committed in the ledger, or nil if `key` has none.
+	GetStateValidationParameter(key string) ([]byte, error)
+
 	// GetStateByRange function can be invoked by a chaincode to query of a range
 	// of keys in the state. Assuming the startKey and endKey are in lexical
 	// an iterator will be returned that can be used to iterate over all keys
@@ -124,9 +140,26 @@ type ChaincodeStubInterface interface {
 
 	// GetTxTimestamp returns the timestamp when the transaction was created. This
 	// is taken from the transaction ChannelHeader, so it will be the same across
-	// all endorsers.
+	// all endorsers. Chaincodes should use it instead of time.Now(), which differs
+	// between endorsers and makes their endorsements mismatch.
 	GetTxTimestamp() (*timestamp.Timestamp, error)
 
+	// GetTxSeed returns a seed derived from the transaction ID, so it will be the
+	// same across all endorsers. Chaincodes needing random numbers should draw
+	// them from rand.New(rand.NewSource(seed)) instead of the global source of
+	// math/rand, which differs between endorsers and makes their endorsements
+	// mismatch. The numbers are predictable by the client, which chooses the
+	// transaction ID, and so must not be used for secrets or lotteries.
+	GetTxSeed() (int64, error)
+
+	// GetTLSCertHash returns the hash of the TLS client certificate of the
+	// creator, taken from the transaction ChannelHeader. When mutual TLS is
+	// enabled, endorsers reject proposals whose hash differs from the one of
+	// the client certificate of their connection, so a chaincode comparing it
+	// with an expected hash confirms the proposal was submitted over the
+	// expected client connection. It is nil if the creator sets none.
+	GetTLSCertHash() ([]byte, error)
+
 	// SetEvent saves the event to be sent when a transaction is made part of a block
 	SetEvent(name string, payload []byte) error
 }
@@ -53,6 +53,12 @@ type MockStub struct {
 	// Keys stores the list of mapped values in lexical order
 	Keys *list.List
 
+	// ValidationParameters keeps the key-level endorsement policies by key
+	ValidationParameters map[string][]byte
+
+	// History keeps the modifications of each key, oldest first, one per transaction
+	History map[string][]*queryresult.KeyModification
+
 	// registered list of other MockStub chaincodes that can be called from this MockStub
 	Invokables map[string]*MockStub
 
@@ -62,6 +68,18 @@ type MockStub struct {
 
 	TxTimestamp *timestamp.Timestamp
 
+	// TLSCertHash is the hash of the TLS client certificate of the creator
+	TLSCertHash []byte
+
+	// Creator is the serialized identity returned by GetCreator
+	Creator []byte
+
+	// TransientMap is the transient data of the proposal returned by GetTransient
+	TransientMap map[string][]byte
+
+	// ChaincodeEvent is the event set by the last transaction, if any
+	ChaincodeEvent *pb.ChaincodeEvent
+
 	// mocked signedProposal
 	signedProposal *pb.SignedProposal
 }
@@ -99,6 +117,7 @@ func (stub *MockStub) GetFunctionAndParameters() (function string, params []stri
 // MockStub doesn't support concurrent transactions at present.
 func (stub *MockStub) MockTransactionStart(txid string) {
 	stub.TxID = txid
+	stub.ChaincodeEvent = nil
 	stub.setSignedProposal(&pb.SignedProposal{})
 	stub.setTxTimestamp(util.CreateUtcTimestamp())
 }
@@ -160,6 +179,7 @@ func (stub *MockStub) PutState(key string, value []byte) error {
 
 	mockLogger.Debug("MockStub", stub.Name, "Putting", key, value)
 	stub.State[key] = value
+	stub.recordModification(key, value, false)
 
 	// insert key into ordered list of keys
 	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
@@ -197,7 +217,11 @@ func (stub *MockStub) PutState(key string, value []byte) error {
 // DelState removes the specified `key` and its value from the ledger.
 func (stub *MockStub) DelState(key string) error {
 	mockLogger.Debug("MockStub", stub.Name, "Deleting", key, stub.State[key])
+	if _, ok := stub.State[key]; ok {
+		stub.recordModification(key, nil, true)
+	}
 	delete(stub.State, key)
+	delete(stub.ValidationParameters, key)
 
 	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
 		if strings.Compare(key, elem.Value.(string)) == 0 {
@@ -208,6 +232,37 @@ func (stub *MockStub) DelState(key string) error {
 	return nil
 }
 
+// recordModification appends the modification of key to its history, or
+// replaces the last one when made by the same transaction, as the ledger only
+// keeps the final write of each transaction
+func (stub *MockStub) recordModification(key string, value []byte, isDelete bool) {
+	modification := &queryresult.KeyModification{TxId: stub.TxID, Value: value, Timestamp: stub.TxTimestamp, IsDelete: isDelete}
+	history := stub.History[key]
+	if len(history) > 0 && history[len(history)-1].TxId == stub.TxID {
+		history[len(history)-1] = modification
+		return
+	}
+	stub.History[key] = append(history, modification)
+}
+
+// SetStateValidationParameter sets the key-level endorsement policy of `key`.
+func (stub *MockStub) SetStateValidationParameter(key string, ep []byte) error {
+	if stub.TxID == "" {
+		return errors.New("Cannot SetStateValidationParameter without a transactions - call stub.MockTransactionStart()?")
+	}
+	if len(ep) == 0 {
+		delete(stub.ValidationParameters, key)
+		return nil
+	}
+	stub.ValidationParameters[key] = ep
+	return nil
+}
+
+// GetStateValidationParameter returns the key-level endorsement policy of `key`.
+func (stub *MockStub) GetStateValidationParameter(key string) ([]byte, error) {
+	return stub.ValidationParameters[key], nil
+}
+
 func (stub *MockStub) GetStateByRange(startKey, endKey string) (StateQueryIteratorInterface, error) {
 	return NewMockStateRangeQueryIterator(stub, startKey, endKey), nil
 }
@@ -226,8 +281,12 @@ func (stub *MockStub) GetQueryResult(query string) (StateQueryIteratorInterface,
 
 // GetHistoryForKey function can be invoked by a chaincode to return a history of
 // key values across time. GetHistoryForKey is intended to be used for read-only queries.
+// The modifications made through the MockStub are returned oldest first, like the
+// history database does, including the ones of the current transaction.
 func (stub *MockStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error) {
-	return nil, errors.New("Not Implemented")
+	history := make([]*queryresult.KeyModification, len(stub.History[key]))
+	copy(history, stub.History[key])
+	return &MockHistoryQueryIterator{modifications: history}, nil
 }
 
 //GetStateByPartialCompositeKey function can be invoked by a chaincode to query the
@@ -270,14 +329,14 @@ func (stub *MockStub) InvokeChaincode(chaincodeName string, args [][]byte, chann
 	return res
 }
 
-// Not implemented
+// GetCreator returns the Creator set on the MockStub
 func (stub *MockStub) GetCreator() ([]byte, error) {
-	return nil, nil
+	return stub.Creator, nil
 }
 
-// Not implemented
+// GetTransient returns the TransientMap set on the MockStub
 func (stub *MockStub) GetTransient() (map[string][]byte, error) {
-	return nil, nil
+	return stub.TransientMap, nil
 }
 
 // Not implemented
@@ -294,9 +353,14 @@ func (stub *MockStub) setSignedProposal(sp *pb.SignedProposal) {
 	stub.signedProposal = sp
 }
 
-// Not implemented
+// GetArgsSlice returns the arguments of the invocation concatenated, like
+// ChaincodeStub does
 func (stub *MockStub) GetArgsSlice() ([]byte, error) {
-	return nil, nil
+	res := []byte{}
+	for _, barg := range stub.args {
+		res = append(res, barg...)
+	}
+	return res, nil
 }
 
 func (stub *MockStub) setTxTimestamp(time *timestamp.Timestamp) {
@@ -310,8 +374,20 @@ func (stub *MockStub) GetTxTimestamp() (*timestamp.Timestamp, error) {
 	return stub.TxTimestamp, nil
 }
 
-// Not implemented
+func (stub *MockStub) GetTxSeed() (int64, error) {
+	return txSeed(stub.TxID)
+}
+
+func (stub *MockStub) GetTLSCertHash() ([]byte, error) {
+	return stub.TLSCertHash, nil
+}
+
+// SetEvent records the event in ChaincodeEvent, where tests can check it
 func (stub *MockStub) SetEvent(name string, payload []byte) error {
+	if name == "" {
+		return errors.New("Event name can not be nil string.")
+	}
+	stub.ChaincodeEvent = &pb.ChaincodeEvent{EventName: name, Payload: payload}
 	return nil
 }
 
@@ -324,10 +400,49 @@ func NewMockStub(name string, cc Chaincode) *MockStub {
 	s.State = make(map[string][]byte)
 	s.Invokables = make(map[string]*MockStub)
 	s.Keys = list.New()
+	s.ValidationParameters = make(map[string][]byte)
+	s.History = make(map[string][]*queryresult.KeyModification)
 
 	return s
 }
 
+/*****************************
+ History Query Iterator
+*****************************/
+
+// MockHistoryQueryIterator iterates over the modifications of a key made through a MockStub
+type MockHistoryQueryIterator struct {
+	modifications []*queryresult.KeyModification
+	closed        bool
+}
+
+// HasNext returns true if the history query iterator contains additional modifications
+func (iter *MockHistoryQueryIterator) HasNext() bool {
+	return !iter.closed && len(iter.modifications) > 0
+}
+
+// Next returns the next modification of the key
+func (iter *MockHistoryQueryIterator) Next() (*queryresult.KeyModification, error) {
+	if iter.closed {
+		return nil, errors.New("MockHistoryQueryIterator.Next() called after Close()")
+	}
+	if len(iter.modifications) == 0 {
+		return nil, errors.New("MockHistoryQueryIterator.Next() called when it does not HaveNext()")
+	}
+	modification := iter.modifications[0]
+	iter.modifications = iter.modifications[1:]
+	return modification, nil
+}
+
+// Close closes the history query iterator
+func (iter *MockHistoryQueryIterator) Close() error {
+	if iter.closed {
+		return errors.New("MockHistoryQueryIterator.Close() called after Close()")
+	}
+	iter.closed = true
+	return nil
+}
+
 /*****************************
  Range Query Iterator
 *****************************/
@@ -363,7 +478,8 @@ func (iter *MockStateRangeQueryIterator) HasNext() bool {
 		comp1 := strings.Compare(current.Value.(string), iter.StartKey)
 		comp2 := strings.Compare(current.Value.(string), iter.EndKey)
 		if comp1 >= 0 {
-			if comp2 <= 0 {
+			// an empty end key leaves the range open-ended
+			if comp2 <= 0 || iter.EndKey == "" {
 				mockLogger.Debug("HasNext() got next")
 				return true
 			} else {
@@ -397,7 +513,7 @@ func (iter *MockStateRangeQueryIterator) Next() (*queryresult.KV, error) {
 		comp2 := strings.Compare(iter.Current.Value.(string), iter.EndKey)
 		// compare to start and end keys. or, if this is an open-ended query for
 		// all keys, it should always return the key and value
-		if (comp1 >= 0 && comp2 <= 0) || (iter.StartKey == "" && iter.EndKey == "") {
+		if (comp1 >= 0 && (comp2 <= 0 || iter.EndKey == "")) || (iter.StartKey == "" && iter.EndKey == "") {
 			key := iter.Current.Value.(string)
 			value, err := iter.Stub.GetState(key)
 			iter.Current = iter.Current.Next()
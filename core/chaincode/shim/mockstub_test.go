@@ -21,7 +21,10 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"unicode/utf8"
 
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/spf13/viper"
 )
 
@@ -115,6 +118,26 @@ func TestSetupChaincodeLogging(t *testing.T) {
 	}
 }
 
+// TestSetupChaincodeLogging_defaultLevel checks that the default level applies
+// to the loggers of the chaincode while the shim keeps its own level
+func TestSetupChaincodeLogging_defaultLevel(t *testing.T) {
+	defer viper.Set("chaincode.defaultLogLevel", "")
+	defer SetLoggingLevel(LogInfo)
+	defer NewLogger("").SetLevel(LogInfo)
+
+	viper.Set("chaincode.logLevel", "debug")
+	viper.Set("chaincode.defaultLogLevel", "error")
+
+	SetupChaincodeLogging()
+
+	if !IsEnabledForLogLevel("debug") {
+		t.Fatal("The shim should log at the debug level")
+	}
+	if NewLogger("mycc").IsEnabledFor(LogWarning) {
+		t.Fatal("The loggers of the chaincode should log at the error level")
+	}
+}
+
 type Marble struct {
 	ObjectType string `json:"docType"` //docType is used to distinguish the various types of objects in state database
 	Name       string `json:"name"`    //the fieldtags are needed to keep case from bouncing around
@@ -220,6 +243,64 @@ func TestGetStateByPartialCompositeKeyCollision(t *testing.T) {
 	}
 }
 
+func TestCompositeKeys(t *testing.T) {
+	stub := NewMockStub("CompositeKeysTest", nil)
+
+	compositeKey, err := stub.CreateCompositeKey("marble", []string{"set-1", "red"})
+	if err != nil {
+		t.Fatalf("CreateCompositeKey failed: %s", err)
+	}
+	objectType, attributes, err := stub.SplitCompositeKey(compositeKey)
+	if err != nil || objectType != "marble" || !reflect.DeepEqual(attributes, []string{"set-1", "red"}) {
+		t.Fatalf("Unexpected split of %q: %s %v (err %v)", compositeKey, objectType, attributes, err)
+	}
+
+	// empty attributes are preserved
+	compositeKey, _ = stub.CreateCompositeKey("marble", []string{"", "red"})
+	if _, attributes, err = stub.SplitCompositeKey(compositeKey); err != nil || !reflect.DeepEqual(attributes, []string{"", "red"}) {
+		t.Fatalf("Unexpected attributes of %q: %v (err %v)", compositeKey, attributes, err)
+	}
+
+	for _, att := range []string{"a\x00b", "a" + string(utf8.MaxRune), "\xff"} {
+		if _, err = stub.CreateCompositeKey("marble", []string{att}); err == nil {
+			t.Fatalf("CreateCompositeKey should have rejected attribute %q", att)
+		}
+		if _, err = stub.GetStateByPartialCompositeKey("marble", []string{att}); err == nil {
+			t.Fatalf("GetStateByPartialCompositeKey should have rejected attribute %q", att)
+		}
+	}
+
+	for _, key := range []string{"", "simplekey", "marble\x00set-1", "marble\x00\xff\x00"} {
+		if _, _, err = stub.SplitCompositeKey(key); err == nil {
+			t.Fatalf("SplitCompositeKey should have rejected %q", key)
+		}
+	}
+}
+
+func TestPartialCompositeKeyRange(t *testing.T) {
+	startKey, endKey, err := partialCompositeKeyRange("marble", []string{"a"})
+	if err != nil {
+		t.Fatalf("partialCompositeKeyRange failed: %s", err)
+	}
+	inRange := func(key string) bool { return key >= startKey && key < endKey }
+
+	for _, attributes := range [][]string{{"a"}, {"a", "red"}, {"a", string(utf8.MaxRune - 1)}} {
+		key, _ := createCompositeKey("marble", attributes)
+		if !inRange(key) {
+			t.Fatalf("Key with attributes %v should be in range", attributes)
+		}
+	}
+	for _, attributes := range [][]string{{"ab"}, {"b"}, {""}} {
+		key, _ := createCompositeKey("marble", attributes)
+		if inRange(key) {
+			t.Fatalf("Key with attributes %v should not be in range", attributes)
+		}
+	}
+	if key, _ := createCompositeKey("marbles", []string{"a"}); inRange(key) {
+		t.Fatalf("Key of another object type should not be in range")
+	}
+}
+
 func TestGetTxTimestamp(t *testing.T) {
 	stub := NewMockStub("GetTxTimestamp", nil)
 	stub.MockTransactionStart("init")
@@ -231,3 +312,122 @@ func TestGetTxTimestamp(t *testing.T) {
 
 	stub.MockTransactionEnd("init")
 }
+
+func TestGetTxSeed(t *testing.T) {
+	stub := NewMockStub("GetTxSeed", nil)
+	if _, err := stub.GetTxSeed(); err == nil {
+		t.Fatalf("Expected an error outside of a transaction")
+	}
+
+	stub.MockTransactionStart("tx1")
+	seed1, err := stub.GetTxSeed()
+	if err != nil {
+		t.Fatalf("Failed to get the seed: %s", err)
+	}
+	if again, _ := stub.GetTxSeed(); again != seed1 {
+		t.Fatalf("Expected the same seed for the same transaction, got %d and %d", seed1, again)
+	}
+	stub.MockTransactionEnd("tx1")
+
+	stub.MockTransactionStart("tx2")
+	if seed2, _ := stub.GetTxSeed(); seed2 == seed1 {
+		t.Fatalf("Expected different seeds for different transactions")
+	}
+	stub.MockTransactionEnd("tx2")
+}
+
+func TestMockStubHistory(t *testing.T) {
+	stub := NewMockStub("history", nil)
+	stub.MockTransactionStart("tx1")
+	stub.PutState("key", []byte("v0"))
+	stub.PutState("key", []byte("v1"))
+	stub.MockTransactionEnd("tx1")
+	stub.MockTransactionStart("tx2")
+	stub.DelState("key")
+	stub.DelState("missing")
+	stub.MockTransactionEnd("tx2")
+
+	iter, err := stub.GetHistoryForKey("key")
+	if err != nil {
+		t.Fatalf("Failed to get the history: %s", err)
+	}
+	var history []*queryresult.KeyModification
+	for iter.HasNext() {
+		modification, err := iter.Next()
+		if err != nil {
+			t.Fatalf("Failed to iterate over the history: %s", err)
+		}
+		history = append(history, modification)
+	}
+	iter.Close()
+	if len(history) != 2 || history[0].TxId != "tx1" || string(history[0].Value) != "v1" || !history[1].IsDelete {
+		t.Fatalf("Unexpected history %v", history)
+	}
+	if iter, _ = stub.GetHistoryForKey("missing"); iter.HasNext() {
+		t.Fatalf("Expected no history for a key never written")
+	}
+}
+
+func TestMockStubProposalContext(t *testing.T) {
+	cc := &eventChaincode{}
+	stub := NewMockStub("context", cc)
+	stub.Creator = []byte("creator")
+	stub.TransientMap = map[string][]byte{"secret": []byte("value")}
+
+	res := stub.MockInvoke("tx1", [][]byte{[]byte("fn"), []byte("arg")})
+	if res.Status != OK {
+		t.Fatalf("Invoke failed: %s", res.Message)
+	}
+	if string(cc.creator) != "creator" || string(cc.transient["secret"]) != "value" || string(cc.argsSlice) != "fnarg" {
+		t.Fatalf("Unexpected proposal context %s %v %s", cc.creator, cc.transient, cc.argsSlice)
+	}
+	if stub.ChaincodeEvent == nil || stub.ChaincodeEvent.EventName != "evt" || string(stub.ChaincodeEvent.Payload) != "payload" {
+		t.Fatalf("Unexpected event %v", stub.ChaincodeEvent)
+	}
+	if err := stub.SetEvent("", nil); err == nil {
+		t.Fatalf("Expected an error for an event without name")
+	}
+}
+
+// eventChaincode records what the stub returns and sets an event
+type eventChaincode struct {
+	creator   []byte
+	transient map[string][]byte
+	argsSlice []byte
+}
+
+func (cc *eventChaincode) Init(stub ChaincodeStubInterface) pb.Response {
+	return Success(nil)
+}
+
+func (cc *eventChaincode) Invoke(stub ChaincodeStubInterface) pb.Response {
+	cc.creator, _ = stub.GetCreator()
+	cc.transient, _ = stub.GetTransient()
+	cc.argsSlice, _ = stub.GetArgsSlice()
+	if err := stub.SetEvent("evt", []byte("payload")); err != nil {
+		return Error(err.Error())
+	}
+	return Success(nil)
+}
+
+func TestMockStateRangeQueryIteratorNoEndKey(t *testing.T) {
+	stub := NewMockStub("rangeTest", nil)
+	stub.MockTransactionStart("init")
+	for _, key := range []string{"1", "3", "5"} {
+		stub.PutState(key, []byte(key))
+	}
+	stub.MockTransactionEnd("init")
+
+	rqi := NewMockStateRangeQueryIterator(stub, "2", "")
+	var keys []string
+	for rqi.HasNext() {
+		kv, err := rqi.Next()
+		if err != nil {
+			t.Fatalf("Failed to iterate: %s", err)
+		}
+		keys = append(keys, kv.Key)
+	}
+	if len(keys) != 2 || keys[0] != "3" || keys[1] != "5" {
+		t.Fatalf("Unexpected keys %v", keys)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"golang.org/x/net/context"
+)
+
+// WarmStartEnabled returns whether the containers of the instantiated
+// chaincodes are to be launched when the peer starts
+func (chaincodeSupport *ChaincodeSupport) WarmStartEnabled() bool {
+	return chaincodeSupport.warmStart && !chaincodeSupport.userRunsCC
+}
+
+// WarmStart launches the containers of the chaincodes instantiated on the
+// chain and installed on this peer, so that the first invocations after a
+// peer restart do not wait for the containers to be launched. Chaincodes
+// that fail to launch are left to be launched on their first invocation.
+func (chaincodeSupport *ChaincodeSupport) WarmStart(chainID string) error {
+	ccs, err := getInstantiatedChaincodes(chainID)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, cc := range ccs {
+		ccpack, err := ccprovider.GetChaincodeFromFS(cc.Name, cc.Version)
+		if err != nil {
+			chaincodeLogger.Debugf("Chaincode %s:%s is not installed, skipping warm start on chain %s", cc.Name, cc.Version, chainID)
+			continue
+		}
+
+		wg.Add(1)
+		go func(cc *ccprovider.ChaincodeData, ccpack ccprovider.CCPackage) {
+			defer wg.Done()
+			cccid := ccprovider.NewCCContext(chainID, cc.Name, cc.Version, util.GenerateUUID(), false, nil, nil)
+			if _, _, err := chaincodeSupport.Launch(context.Background(), cccid, ccpack.GetDepSpec()); err != nil {
+				chaincodeLogger.Warningf("Warm start of chaincode %s on chain %s failed: %s", cccid.GetCanonicalName(), chainID, err)
+				return
+			}
+			chaincodeLogger.Infof("Warm started chaincode %s on chain %s", cccid.GetCanonicalName(), chainID)
+		}(cc, ccpack)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// getInstantiatedChaincodes returns the chaincodes recorded by lscc
+// on the ledger of the chain
+func getInstantiatedChaincodes(chainID string) ([]*ccprovider.ChaincodeData, error) {
+	lgr := peer.GetLedger(chainID)
+	if lgr == nil {
+		return nil, fmt.Errorf("chain does not exist(%s)", chainID)
+	}
+
+	qe, err := lgr.NewQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	defer qe.Done()
+
+	itr, err := qe.GetStateRangeScanIterator("lscc", "", "")
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+
+	return chaincodeDefinitions(chainID, itr)
+}
+
+// chaincodeDefinitions collects the chaincode definitions of the lscc values
+// of the iterator. lscc keeps other values than chaincode definitions, which
+// are keyed by the name of their chaincode.
+func chaincodeDefinitions(chainID string, itr commonledger.ResultsIterator) ([]*ccprovider.ChaincodeData, error) {
+	var ccs []*ccprovider.ChaincodeData
+	for {
+		res, err := itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			break
+		}
+
+		kv := res.(*queryresult.KV)
+		ccdata := &ccprovider.ChaincodeData{}
+		if err = proto.Unmarshal(kv.Value, ccdata); err != nil || ccdata.Name != kv.Key {
+			chaincodeLogger.Debugf("Skipping lscc key %s of chain %s, not a chaincode definition", kv.Key, chainID)
+			continue
+		}
+		ccs = append(ccs, ccdata)
+	}
+
+	return ccs, nil
+}
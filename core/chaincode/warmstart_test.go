@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/stretchr/testify/assert"
+)
+
+// kvIterator iterates over a fixed set of key values
+type kvIterator struct {
+	kvs []*queryresult.KV
+}
+
+func (itr *kvIterator) Next() (commonledger.QueryResult, error) {
+	if len(itr.kvs) == 0 {
+		return nil, nil
+	}
+	kv := itr.kvs[0]
+	itr.kvs = itr.kvs[1:]
+	return kv, nil
+}
+
+func (itr *kvIterator) Close() {}
+
+func TestChaincodeDefinitions(t *testing.T) {
+	cd, err := proto.Marshal(&ccprovider.ChaincodeData{Name: "mycc", Version: "1.0"})
+	assert.NoError(t, err)
+	itr := &kvIterator{kvs: []*queryresult.KV{
+		{Key: "mycc", Value: cd},
+		// Values which aren't chaincode definitions are skipped
+		{Key: "garbage", Value: []byte("not a chaincode definition")},
+		{Key: "mycc~other", Value: cd},
+	}}
+
+	ccs, err := chaincodeDefinitions("testchain", itr)
+	assert.NoError(t, err)
+	assert.Len(t, ccs, 1)
+	assert.Equal(t, "mycc", ccs[0].Name)
+	assert.Equal(t, "1.0", ccs[0].Version)
+}
@@ -20,6 +20,8 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"sync"
 	"time"
@@ -29,6 +31,7 @@ import (
 	"google.golang.org/grpc/grpclog"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/spf13/viper"
 )
@@ -170,14 +173,24 @@ func NewClientConnectionWithAddress(peerAddress string, block bool, tslEnabled b
 
 // InitTLSForPeer returns TLS credentials for peer
 func InitTLSForPeer() credentials.TransportCredentials {
+	return InitTLSForPeerWithRootCert(config.GetPath("peer.tls.rootcert.file"))
+}
+
+// InitTLSForPeerWithRootCert returns TLS credentials for a peer whose TLS
+// certificate is issued by the root certificate in rootCertFile, e.g. a peer of
+// another organization. The system roots are used if rootCertFile is empty
+func InitTLSForPeerWithRootCert(rootCertFile string) credentials.TransportCredentials {
 	var sn string
 	if viper.GetString("peer.tls.serverhostoverride") != "" {
 		sn = viper.GetString("peer.tls.serverhostoverride")
 	}
+	if viper.GetBool("peer.tls.clientAuthRequired") {
+		return initMutualTLSForPeer(sn, rootCertFile)
+	}
 	var creds credentials.TransportCredentials
-	if config.GetPath("peer.tls.rootcert.file") != "" {
+	if rootCertFile != "" {
 		var err error
-		creds, err = credentials.NewClientTLSFromFile(config.GetPath("peer.tls.rootcert.file"), sn)
+		creds, err = credentials.NewClientTLSFromFile(rootCertFile, sn)
 		if err != nil {
 			grpclog.Fatalf("Failed to create TLS credentials %v", err)
 		}
@@ -186,3 +199,54 @@ func InitTLSForPeer() credentials.TransportCredentials {
 	}
 	return creds
 }
+
+// GetClientCertHash returns the hash of the TLS client certificate presented
+// to peers requiring TLS client authentication, or nil if none is presented
+func GetClientCertHash() ([]byte, error) {
+	if !TLSEnabled() || !viper.GetBool("peer.tls.clientAuthRequired") {
+		return nil, nil
+	}
+	certFile, keyFile := clientCertFiles()
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS client certificate: %s", err)
+	}
+	return util.ComputeSHA256(cert.Certificate[0]), nil
+}
+
+// clientCertFiles returns the files of the TLS client certificate and key of
+// the peer, falling back to the ones of its server certificate
+func clientCertFiles() (string, string) {
+	certFile := config.GetPath("peer.tls.clientCert.file")
+	keyFile := config.GetPath("peer.tls.clientKey.file")
+	if certFile == "" || keyFile == "" {
+		certFile = config.GetPath("peer.tls.cert.file")
+		keyFile = config.GetPath("peer.tls.key.file")
+	}
+	return certFile, keyFile
+}
+
+// initMutualTLSForPeer returns TLS credentials presenting the client
+// certificate of the peer, for peers requiring TLS client authentication
+func initMutualTLSForPeer(sn string, rootCertFile string) credentials.TransportCredentials {
+	certFile, keyFile := clientCertFiles()
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		grpclog.Fatalf("Failed to load TLS client certificate %v", err)
+	}
+	tlsConfig := &tls.Config{
+		ServerName:   sn,
+		Certificates: []tls.Certificate{cert},
+	}
+	if rootCertFile != "" {
+		rootCert, err := ioutil.ReadFile(rootCertFile)
+		if err != nil {
+			grpclog.Fatalf("Failed to load TLS root certificate %v", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(rootCert) {
+			grpclog.Fatalf("Failed to append TLS root certificate")
+		}
+	}
+	return credentials.NewTLS(tlsConfig)
+}
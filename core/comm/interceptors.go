@@ -0,0 +1,235 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package comm
+
+import (
+	"encoding/hex"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/util"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// ChainUnaryServerInterceptors returns an interceptor running the given
+// interceptors in order, each one calling the next as its handler
+func ChainUnaryServerInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return chained(ctx, req)
+	}
+}
+
+// ChainStreamServerInterceptors returns an interceptor running the given
+// interceptors in order, each one calling the next as its handler
+func ChainStreamServerInterceptors(interceptors ...grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(srv interface{}, stream grpc.ServerStream) error {
+				return interceptor(srv, stream, info, next)
+			}
+		}
+		return chained(srv, stream)
+	}
+}
+
+// LoggingUnaryServerInterceptor logs the method, the client, the duration
+// and the outcome of each call
+func LoggingUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logCall(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+// LoggingStreamServerInterceptor logs the method, the client, the duration
+// and the outcome of each stream
+func LoggingStreamServerInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	logCall(stream.Context(), info.FullMethod, start, err)
+	return err
+}
+
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	client := "unknown"
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		client = p.Addr.String()
+	}
+	commLogger.Infof("%s from %s completed in %s with code %s", method, client, time.Since(start), grpc.Code(err))
+}
+
+// PayloadSizeUnaryServerInterceptor returns an interceptor refusing the
+// requests larger than maxBytes
+func PayloadSizeUnaryServerInterceptor(maxBytes int) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := checkPayloadSize(req, maxBytes); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// PayloadSizeStreamServerInterceptor returns an interceptor closing the
+// streams on which a message larger than maxBytes is received
+func PayloadSizeStreamServerInterceptor(maxBytes int) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &payloadSizeStream{ServerStream: stream, maxBytes: maxBytes})
+	}
+}
+
+type payloadSizeStream struct {
+	grpc.ServerStream
+	maxBytes int
+}
+
+func (s *payloadSizeStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return checkPayloadSize(m, s.maxBytes)
+}
+
+func checkPayloadSize(msg interface{}, maxBytes int) error {
+	pm, ok := msg.(proto.Message)
+	if !ok {
+		return nil
+	}
+	if size := proto.Size(pm); size > maxBytes {
+		return grpc.Errorf(codes.InvalidArgument, "message of %d bytes exceeds the maximum of %d bytes", size, maxBytes)
+	}
+	return nil
+}
+
+// RateLimiter limits the rate of the calls of each client identity, which is
+// the TLS client certificate of the client when it presents one and its IP
+// address otherwise. The calls of each identity draw from a bucket of burst
+// tokens, refilled at rate tokens per second.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	lock    sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a RateLimiter allowing each identity rate calls per
+// second, and bursts of up to burst calls
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{rate: rate, burst: float64(burst), buckets: make(map[string]*tokenBucket)}
+}
+
+// UnaryServerInterceptor returns an interceptor refusing the calls of the
+// identities exceeding their rate
+func (rl *RateLimiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := rl.allow(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor refusing the streams of the
+// identities exceeding their rate. The messages received on an open stream
+// are not limited.
+func (rl *RateLimiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := rl.allow(stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+func (rl *RateLimiter) allow(ctx context.Context, method string) error {
+	identity := clientIdentity(ctx)
+	now := time.Now()
+
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	bucket, ok := rl.buckets[identity]
+	if !ok {
+		rl.purge(now)
+		bucket = &tokenBucket{tokens: rl.burst, last: now}
+		rl.buckets[identity] = bucket
+	}
+	bucket.tokens += now.Sub(bucket.last).Seconds() * rl.rate
+	if bucket.tokens > rl.burst {
+		bucket.tokens = rl.burst
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		commLogger.Warningf("Refusing %s from %s: rate limit of %g calls per second exceeded", method, identity, rl.rate)
+		return grpc.Errorf(codes.ResourceExhausted, "rate limit of %g calls per second exceeded", rl.rate)
+	}
+	bucket.tokens--
+	return nil
+}
+
+// purge drops the buckets which are full again, as they are identical to
+// the bucket of a new identity
+func (rl *RateLimiter) purge(now time.Time) {
+	for identity, bucket := range rl.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*rl.rate >= rl.burst {
+			delete(rl.buckets, identity)
+		}
+	}
+}
+
+// clientIdentity returns the hash of the TLS client certificate of the
+// client, or its IP address when it presented none
+func clientIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+		return "cert:" + hex.EncodeToString(util.ComputeSHA256(tlsInfo.State.PeerCertificates[0].Raw))
+	}
+	if p.Addr == nil {
+		return "unknown"
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package comm_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/comm"
+	testpb "github.com/hyperledger/fabric/core/comm/testdata/grpc"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+)
+
+func TestChainUnaryServerInterceptors(t *testing.T) {
+	var calls []string
+	interceptor := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			calls = append(calls, name)
+			return handler(ctx, req)
+		}
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls = append(calls, "handler")
+		return req, nil
+	}
+
+	chain := comm.ChainUnaryServerInterceptors(interceptor("first"), interceptor("second"))
+	resp, err := chain(context.Background(), "request", &grpc.UnaryServerInfo{}, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "request", resp)
+	assert.Equal(t, []string{"first", "second", "handler"}, calls)
+
+	// the chain can be called again
+	calls = nil
+	chain(context.Background(), "request", &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, []string{"first", "second", "handler"}, calls)
+}
+
+func TestChainStreamServerInterceptors(t *testing.T) {
+	var calls []string
+	interceptor := func(name string) grpc.StreamServerInterceptor {
+		return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			calls = append(calls, name)
+			return handler(srv, stream)
+		}
+	}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		calls = append(calls, "handler")
+		return nil
+	}
+
+	chain := comm.ChainStreamServerInterceptors(interceptor("first"), interceptor("second"))
+	assert.NoError(t, chain(nil, nil, &grpc.StreamServerInfo{}, handler))
+	assert.Equal(t, []string{"first", "second", "handler"}, calls)
+}
+
+func contextFrom(address string) context.Context {
+	addr, _ := net.ResolveTCPAddr("tcp", address)
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+}
+
+func TestRateLimiter(t *testing.T) {
+	rl := comm.NewRateLimiter(0.001, 2)
+	interceptor := rl.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/protos.Endorser/ProcessProposal"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := interceptor(contextFrom("127.0.0.1:5000"), nil, info, handler)
+		assert.NoError(t, err, "Should have allowed a burst of 2 calls")
+	}
+	_, err := interceptor(contextFrom("127.0.0.1:5001"), nil, info, handler)
+	assert.Equal(t, codes.ResourceExhausted, grpc.Code(err), "Should have limited the calls of the address, whatever the port")
+	_, err = interceptor(contextFrom("127.0.0.2:5000"), nil, info, handler)
+	assert.NoError(t, err, "Should not have limited another identity")
+
+	rl = comm.NewRateLimiter(1000, 1)
+	interceptor = rl.UnaryServerInterceptor()
+	for i := 0; i < 3; i++ {
+		_, err := interceptor(contextFrom("127.0.0.1:5000"), nil, info, handler)
+		assert.NoError(t, err, "Should have refilled the bucket")
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPayloadSizeServerInterceptors(t *testing.T) {
+	interceptor := comm.PayloadSizeUnaryServerInterceptor(100)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	_, err := interceptor(context.Background(), &cb.Envelope{Payload: make([]byte, 50)}, &grpc.UnaryServerInfo{}, handler)
+	assert.NoError(t, err)
+	_, err = interceptor(context.Background(), &cb.Envelope{Payload: make([]byte, 200)}, &grpc.UnaryServerInfo{}, handler)
+	assert.Equal(t, codes.InvalidArgument, grpc.Code(err))
+}
+
+func TestGRPCServerInterceptors(t *testing.T) {
+	t.Parallel()
+	testAddress := "localhost:9063"
+	denied := 0
+	srv, err := comm.NewGRPCServer(testAddress, comm.SecureServerConfig{
+		UnaryInterceptors: []grpc.UnaryServerInterceptor{
+			comm.LoggingUnaryServerInterceptor,
+			func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				denied++
+				return nil, grpc.Errorf(codes.PermissionDenied, "denied %s", info.FullMethod)
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to return new GRPC server: %v", err)
+	}
+	testpb.RegisterTestServiceServer(srv.Server(), &testServiceServer{})
+	go srv.Start()
+	defer srv.Stop()
+	time.Sleep(10 * time.Millisecond)
+
+	_, err = invokeEmptyCall(testAddress, []grpc.DialOption{grpc.WithInsecure()})
+	assert.Equal(t, codes.PermissionDenied, grpc.Code(err), "Should have run the interceptors of the server")
+	assert.Equal(t, 1, denied)
+}
@@ -40,12 +40,20 @@ type ConnectionProducer interface {
 	// UpdateEndpoints updates the endpoints of the ConnectionProducer
 	// to be the given endpoints
 	UpdateEndpoints(endpoints []string)
+	// UpdatePreferredEndpoints sets the endpoints that are tried before
+	// the other endpoints of the ConnectionProducer
+	UpdatePreferredEndpoints(endpoints []string)
 }
 
 type connProducer struct {
 	sync.RWMutex
 	endpoints []string
-	connect   ConnectionFactory
+	preferred map[string]struct{}
+	// lastEndpoint is the endpoint of the last connection produced.
+	// A new connection is only needed when the previous one failed,
+	// hence it is tried last.
+	lastEndpoint string
+	connect      ConnectionFactory
 }
 
 // NewConnectionProducer creates a new ConnectionProducer with given endpoints and connection factory.
@@ -58,24 +66,50 @@ func NewConnectionProducer(factory ConnectionFactory, endpoints []string) Connec
 }
 
 // NewConnection creates a new connection.
+// Preferred endpoints are tried first, in random order, and then the others.
 // Returns the connection, the endpoint selected, nil on success.
 // Returns nil, "", error on failure
 func (cp *connProducer) NewConnection() (*grpc.ClientConn, string, error) {
-	cp.RLock()
-	defer cp.RUnlock()
-
-	endpoints := shuffle(cp.endpoints)
+	endpoints := cp.candidates()
 	for _, endpoint := range endpoints {
 		conn, err := cp.connect(endpoint)
 		if err != nil {
 			logger.Error("Failed connecting to", endpoint, ", error:", err)
 			continue
 		}
+		cp.Lock()
+		cp.lastEndpoint = endpoint
+		cp.Unlock()
 		return conn, endpoint, nil
 	}
 	return nil, "", fmt.Errorf("Could not connect to any of the endpoints: %v", endpoints)
 }
 
+// candidates returns the endpoints in the order they should be tried
+func (cp *connProducer) candidates() []string {
+	cp.RLock()
+	defer cp.RUnlock()
+
+	var preferred, others []string
+	var last string
+	for _, endpoint := range cp.endpoints {
+		if endpoint == cp.lastEndpoint && len(cp.endpoints) > 1 {
+			last = endpoint
+			continue
+		}
+		if _, isPreferred := cp.preferred[endpoint]; isPreferred {
+			preferred = append(preferred, endpoint)
+		} else {
+			others = append(others, endpoint)
+		}
+	}
+	endpoints := append(shuffle(preferred), shuffle(others)...)
+	if last != "" {
+		endpoints = append(endpoints, last)
+	}
+	return endpoints
+}
+
 // UpdateEndpoints updates the endpoints of the ConnectionProducer
 // to be the given endpoints
 func (cp *connProducer) UpdateEndpoints(endpoints []string) {
@@ -88,6 +122,19 @@ func (cp *connProducer) UpdateEndpoints(endpoints []string) {
 	cp.endpoints = endpoints
 }
 
+// UpdatePreferredEndpoints sets the endpoints that are tried before
+// the other endpoints of the ConnectionProducer.
+// Preferred endpoints that aren't endpoints of the ConnectionProducer are ignored.
+func (cp *connProducer) UpdatePreferredEndpoints(endpoints []string) {
+	preferred := make(map[string]struct{}, len(endpoints))
+	for _, endpoint := range endpoints {
+		preferred[endpoint] = struct{}{}
+	}
+	cp.Lock()
+	defer cp.Unlock()
+	cp.preferred = preferred
+}
+
 func shuffle(a []string) []string {
 	n := len(a)
 	returnedSlice := make([]string, n)
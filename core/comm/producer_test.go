@@ -109,3 +109,53 @@ func TestUpdateEndpoints(t *testing.T) {
 	conn, _, err = producer.NewConnection()
 	assert.Equal(t, "b", conn2Endpoint[fmt.Sprintf("%p", conn)])
 }
+
+func TestPreferredEndpoints(t *testing.T) {
+	conn2Endpoint := make(map[string]string)
+	shouldConnFail := map[string]bool{}
+	connFactory := func(endpoint string) (*grpc.ClientConn, error) {
+		if shouldConnFail[endpoint] {
+			return nil, fmt.Errorf("Failed connecting to %s", endpoint)
+		}
+		conn := &grpc.ClientConn{}
+		conn2Endpoint[fmt.Sprintf("%p", conn)] = endpoint
+		return conn, nil
+	}
+	producer := NewConnectionProducer(connFactory, []string{"a", "b", "c", "d"})
+	producer.UpdatePreferredEndpoints([]string{"c", "d", "e"})
+	for i := 0; i < 100; i++ {
+		_, endpoint, err := producer.NewConnection()
+		assert.NoError(t, err)
+		assert.Contains(t, []string{"c", "d"}, endpoint)
+	}
+	// Fall back to the other endpoints when the preferred ones are down
+	shouldConnFail["c"] = true
+	shouldConnFail["d"] = true
+	_, endpoint, err := producer.NewConnection()
+	assert.NoError(t, err)
+	assert.Contains(t, []string{"a", "b"}, endpoint)
+}
+
+func TestFailover(t *testing.T) {
+	connFactory := func(endpoint string) (*grpc.ClientConn, error) {
+		return &grpc.ClientConn{}, nil
+	}
+	// A new connection is requested only when the previous one broke,
+	// so the endpoint of the previous connection should be tried last
+	producer := NewConnectionProducer(connFactory, []string{"a", "b", "c"})
+	_, prev, err := producer.NewConnection()
+	assert.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		_, endpoint, err := producer.NewConnection()
+		assert.NoError(t, err)
+		assert.NotEqual(t, prev, endpoint)
+		prev = endpoint
+	}
+	// Unless there is no other endpoint
+	producer = NewConnectionProducer(connFactory, []string{"a"})
+	for i := 0; i < 2; i++ {
+		_, endpoint, err := producer.NewConnection()
+		assert.NoError(t, err)
+		assert.Equal(t, "a", endpoint)
+	}
+}
@@ -46,6 +46,10 @@ type SecureServerConfig struct {
 	//Set of PEM-encoded X509 certificate authorities to use when verifying
 	//client certificates
 	ClientRootCAs [][]byte
+	//Interceptors run in order on the unary calls, before the service
+	UnaryInterceptors []grpc.UnaryServerInterceptor
+	//Interceptors run in order on the streams, before the service
+	StreamInterceptors []grpc.StreamServerInterceptor
 }
 
 //GRPCServer defines an interface representing a GRPC-based server
@@ -179,6 +183,12 @@ func NewGRPCServerFromListener(listener net.Listener, secureConfig SecureServerC
 				"ServerCertificate when UseTLS is true")
 		}
 	}
+	if len(secureConfig.UnaryInterceptors) > 0 {
+		serverOpts = append(serverOpts, grpc.UnaryInterceptor(ChainUnaryServerInterceptors(secureConfig.UnaryInterceptors...)))
+	}
+	if len(secureConfig.StreamInterceptors) > 0 {
+		serverOpts = append(serverOpts, grpc.StreamInterceptor(ChainStreamServerInterceptors(secureConfig.StreamInterceptors...)))
+	}
 	grpcServer.server = grpc.NewServer(serverOpts...)
 
 	return grpcServer, nil
@@ -39,3 +39,31 @@ type Committer interface {
 	// Closes committing service
 	Close()
 }
+
+// Prevalidator is implemented by the committers able to validate a block
+// while the previous one is written to the ledger
+type Prevalidator interface {
+	// Prevalidate announces the block to be committed after the one passed
+	// to the next Commit call
+	Prevalidate(block *common.Block)
+}
+
+// CommittingLedger is the part of the ledger of a channel the committer
+// writes the blocks to and reads them back from, ledger.PeerLedger
+// implements it
+type CommittingLedger interface {
+	// Commit writes a validated block to the ledger
+	Commit(block *common.Block) error
+
+	// GetBlockchainInfo returns the height and the hashes of the chain
+	GetBlockchainInfo() (*common.BlockchainInfo, error)
+
+	// GetBlockByNumber returns the block of the given number
+	GetBlockByNumber(blockNumber uint64) (*common.Block, error)
+
+	// Close closes the ledger
+	Close()
+}
+
+// BlockPublisher publishes the blocks once they are committed
+type BlockPublisher func(block *common.Block) error
@@ -17,11 +17,19 @@ limitations under the License.
 package committer
 
 import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/events/producer"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
 )
 
@@ -40,30 +48,184 @@ func init() {
 // it keeps the reference to the ledger to commit blocks and retreive
 // chain information
 type LedgerCommitter struct {
-	ledger    ledger.PeerLedger
+	ledger    CommittingLedger
 	validator txvalidator.Validator
+	publish   BlockPublisher
+
+	// pipeline is non-nil when the validation of a block is allowed
+	// to overlap the write of the previous one
+	pipeline *commitPipeline
+
+	// maxBlockBytes and maxBlockTxs limit the blocks accepted for commit,
+	// 0 disables a limit
+	maxBlockBytes int
+	maxBlockTxs   int
+
+	// verifyHashChain is true when the previous hash of a block is checked
+	// against the hash of the header of the last block before commit
+	verifyHashChain bool
 }
 
 // NewLedgerCommitter is a factory function to create an instance of the committer
 func NewLedgerCommitter(ledger ledger.PeerLedger, validator txvalidator.Validator) *LedgerCommitter {
-	return &LedgerCommitter{ledger: ledger, validator: validator}
+	return NewLedgerCommitterWithPublisher(ledger, validator, producer.SendProducerBlockEvent)
+}
+
+// NewLedgerCommitterWithPublisher creates a committer which writes the blocks
+// validated by the validator to the ledger, and hands them to publish once
+// committed rather than to the event producer of the peer
+func NewLedgerCommitterWithPublisher(ledger CommittingLedger, validator txvalidator.Validator, publish BlockPublisher) *LedgerCommitter {
+	return &LedgerCommitter{
+		ledger:          ledger,
+		validator:       validator,
+		publish:         publish,
+		maxBlockBytes:   ledgerconfig.GetMaxBlockBytes(),
+		maxBlockTxs:     ledgerconfig.GetMaxBlockTransactions(),
+		verifyHashChain: ledgerconfig.IsHashChainVerificationEnabled(),
+	}
+}
+
+// NewPipelinedLedgerCommitter creates a committer which validates the block
+// announced with Prevalidate while the previous one is written to the ledger.
+// Commit still returns once the block is written, with the error writing it.
+func NewPipelinedLedgerCommitter(ledger ledger.PeerLedger, validator txvalidator.Validator) *LedgerCommitter {
+	lc := NewLedgerCommitter(ledger, validator)
+	lc.pipeline = &commitPipeline{}
+	return lc
 }
 
 // Commit commits block to into the ledger
 // Note, it is important that this always be called serially
 func (lc *LedgerCommitter) Commit(block *common.Block) error {
+	if err := lc.checkBlockLimits(block); err != nil {
+		return err
+	}
+	if err := lc.checkBlockContinuity(block); err != nil {
+		return err
+	}
+
+	if lc.pipeline != nil {
+		return lc.commitPipelined(block)
+	}
+
 	// Validate and mark invalid transactions
 	logger.Debug("Validating block")
 	if err := lc.validator.Validate(block); err != nil {
 		return err
 	}
 
+	return lc.commitToLedger(block)
+}
+
+// checkBlockLimits rejects a block exceeding the configured size or number of
+// transactions, before the resources to validate it are spent
+func (lc *LedgerCommitter) checkBlockLimits(block *common.Block) error {
+	var reason string
+	if lc.maxBlockTxs > 0 && block.Data != nil && len(block.Data.Data) > lc.maxBlockTxs {
+		reason = fmt.Sprintf("%d transactions exceed the limit of %d", len(block.Data.Data), lc.maxBlockTxs)
+	} else if lc.maxBlockBytes > 0 {
+		if size := proto.Size(block); size > lc.maxBlockBytes {
+			reason = fmt.Sprintf("%d bytes exceed the limit of %d", size, lc.maxBlockBytes)
+		}
+	}
+	if reason == "" {
+		return nil
+	}
+
+	chainID := chainIDOf(block)
+	oversizedBlocks.Add(chainID, 1)
+	logger.Errorf("Rejecting block %d of channel %s, %s: the ordering service may be misconfigured or compromised",
+		block.Header.Number, chainID, reason)
+	return fmt.Errorf("block %d rejected, %s", block.Header.Number, reason)
+}
+
+// BlockMismatchError is returned by Commit when a block doesn't extend the
+// chain of the ledger: its number leaves a gap after the last block, or its
+// previous hash isn't the hash of the last block, which indicates a fork
+type BlockMismatchError struct {
+	ChainID string
+	Reason  string
+}
+
+func (e *BlockMismatchError) Error() string {
+	return fmt.Sprintf("block doesn't extend the chain of channel %s, %s", e.ChainID, e.Reason)
+}
+
+// checkBlockContinuity refuses a block which doesn't follow the last block
+// of the ledger, and marks the channel unhealthy until a block extending the
+// chain is committed
+func (lc *LedgerCommitter) checkBlockContinuity(block *common.Block) error {
+	info, err := lc.ledger.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	height := info.Height
+
+	var reason string
+	if block.Header.Number != height {
+		reason = fmt.Sprintf("got block %d while the next block is %d", block.Header.Number, height)
+	} else if lc.verifyHashChain && height > 0 {
+		linked, err := lc.verifyPreviousHash(block)
+		if err != nil {
+			return err
+		}
+		if !linked {
+			reason = fmt.Sprintf("the previous hash of block %d doesn't match the hash of block %d", block.Header.Number, height-1)
+		}
+	}
+
+	chainID := chainIDOf(block)
+	if reason == "" {
+		setHealthy(chainID)
+		return nil
+	}
+	logger.Errorf("Refusing block %d of channel %s, %s", block.Header.Number, chainID, reason)
+	setUnhealthy(chainID, reason)
+	return &BlockMismatchError{ChainID: chainID, Reason: reason}
+}
+
+// verifyPreviousHash checks that the previous hash of the block is the hash
+// computed over the header of the last block read back from the ledger,
+// rather than trusting the relayed block
+func (lc *LedgerCommitter) verifyPreviousHash(block *common.Block) (bool, error) {
+	start := time.Now()
+	defer func() { recordHashChainVerification(time.Since(start)) }()
+
+	last, err := lc.ledger.GetBlockByNumber(block.Header.Number - 1)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(block.Header.PreviousHash, last.Header.Hash()), nil
+}
+
+// chainIDOf returns the channel of a block, for reporting
+func chainIDOf(block *common.Block) string {
+	chainID, err := utils.GetChainIDFromBlock(block)
+	if err != nil {
+		return "unknown"
+	}
+	return chainID
+}
+
+// commitToLedger writes an already validated block to the ledger
+func (lc *LedgerCommitter) commitToLedger(block *common.Block) error {
+	// trace the commit of the transactions of the block, to follow them from
+	// the endorser
+	txIDs := tracing.BlockTxIDs(block)
+	tr := tracing.New("committer", fmt.Sprintf("block %d", block.Header.Number), txIDs)
+	defer tr.Finish()
+	tr.LazyPrintf("committing transactions %v", txIDs)
+
 	if err := lc.ledger.Commit(block); err != nil {
+		tr.LazyPrintf("commit failed: %s", err)
+		tr.SetError()
 		return err
 	}
+	tr.LazyPrintf("committed")
+	recordRejectedTxs(block)
 
 	// send block event *after* the block has been committed
-	if err := producer.SendProducerBlockEvent(block); err != nil {
+	if err := lc.publish(block); err != nil {
 		logger.Errorf("Error publishing block %d, because: %v", block.Header.Number, err)
 	}
 
@@ -101,5 +263,6 @@ func (lc *LedgerCommitter) GetBlocks(blockSeqs []uint64) []*common.Block {
 
 // Close the ledger
 func (lc *LedgerCommitter) Close() {
+	lc.flush()
 	lc.ledger.Close()
 }
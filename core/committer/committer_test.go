@@ -17,16 +17,28 @@ limitations under the License.
 package committer
 
 import (
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/core/mocks/validator"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
 func TestKVLedgerBlockStorage(t *testing.T) {
@@ -73,3 +85,344 @@ func TestKVLedgerBlockStorage(t *testing.T) {
 	testutil.AssertEquals(t, bcInfo, &common.BlockchainInfo{
 		Height: 2, CurrentBlockHash: block1Hash, PreviousBlockHash: gbHash})
 }
+
+func TestPipelinedKVLedgerBlockStorage(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/committertest")
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+	gb, _ := test.MakeGenesisBlock("TestLedger")
+	ledger, err := ledgermgmt.CreateLedger(gb)
+	assert.NoError(t, err, "Error while creating ledger: %s", err)
+	defer ledger.Close()
+
+	committer := NewPipelinedLedgerCommitter(ledger, &validator.MockValidator{})
+
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+
+	block1 := testutil.ConstructBlock(t, 1, gb.Header.Hash(), [][]byte{simRes}, true)
+	// block2 carries the very same transaction as block1, and is validated
+	// while block1 is written
+	block2 := testutil.ConstructBlock(t, 2, block1.Header.Hash(), [][]byte{simRes}, true)
+	block2.Data.Data[0] = block1.Data.Data[0]
+
+	committer.Prevalidate(block2)
+	err = committer.Commit(block1)
+	assert.NoError(t, err)
+	err = committer.Commit(block2)
+	assert.NoError(t, err)
+
+	height, err := committer.LedgerHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), height)
+
+	txsFilter := ledgerUtil.TxValidationFlags(block2.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.True(t, txsFilter.IsSetTo(0, peer.TxValidationCode_DUPLICATE_TXID))
+
+	blocks := committer.GetBlocks([]uint64{1, 2})
+	assert.Equal(t, 2, len(blocks))
+}
+
+// countingValidator counts the validations of each block
+type countingValidator struct {
+	sync.Mutex
+	validations map[uint64]int
+}
+
+func (v *countingValidator) Validate(block *common.Block) error {
+	v.Lock()
+	defer v.Unlock()
+	v.validations[block.Header.Number]++
+	return nil
+}
+
+func (v *countingValidator) count(number uint64) int {
+	v.Lock()
+	defer v.Unlock()
+	return v.validations[number]
+}
+
+func TestPipelinedCommitterReportsWriteErrors(t *testing.T) {
+	ledger := &memLedger{}
+	v := &countingValidator{validations: make(map[uint64]int)}
+	committer := NewLedgerCommitterWithPublisher(ledger, v, func(*common.Block) error { return nil })
+	committer.pipeline = &commitPipeline{}
+
+	gb, _ := test.MakeGenesisBlock("TestLedger")
+	assert.NoError(t, committer.Commit(gb))
+	block1 := testutil.ConstructBlock(t, 1, gb.Header.Hash(), [][]byte{}, true)
+	block2 := testutil.ConstructBlock(t, 2, block1.Header.Hash(), [][]byte{}, true)
+
+	// The error writing a block is returned by its own Commit call,
+	// and the validation of the next block is discarded
+	ledger.err = errors.New("disk full")
+	committer.Prevalidate(block2)
+	assert.EqualError(t, committer.Commit(block1), "disk full")
+	height, err := committer.LedgerHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), height)
+	assert.Equal(t, 1, v.count(2))
+
+	// The block can be retried
+	ledger.err = nil
+	committer.Prevalidate(block2)
+	assert.NoError(t, committer.Commit(block1))
+	assert.NoError(t, committer.Commit(block2))
+	height, err = committer.LedgerHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), height)
+	assert.Equal(t, 2, v.count(1))
+	assert.Equal(t, 2, v.count(2), "The block validated ahead should not have been validated again")
+}
+
+func TestPipelinedCommitterBarrier(t *testing.T) {
+	ledger := &memLedger{}
+	v := &countingValidator{validations: make(map[uint64]int)}
+	committer := NewLedgerCommitterWithPublisher(ledger, v, func(*common.Block) error { return nil })
+	committer.pipeline = &commitPipeline{}
+
+	// The validation of the block after a config block isn't started ahead
+	gb, _ := test.MakeGenesisBlock("TestLedger")
+	block1 := testutil.ConstructBlock(t, 1, gb.Header.Hash(), [][]byte{}, true)
+	committer.Prevalidate(block1)
+	assert.NoError(t, committer.Commit(gb))
+	assert.Equal(t, 0, v.count(1))
+	assert.NoError(t, committer.Commit(block1))
+	assert.Equal(t, 1, v.count(1))
+
+	// Nor is the validation of a config block, which changes the config
+	// the block before it is written with
+	block2 := testutil.ConstructBlock(t, 2, block1.Header.Hash(), [][]byte{}, true)
+	block3, _ := test.MakeGenesisBlock("TestLedger")
+	block3.Header.Number = 3
+	block3.Header.PreviousHash = block2.Header.Hash()
+	committer.Prevalidate(block3)
+	assert.NoError(t, committer.Commit(block2))
+	assert.Equal(t, 0, v.count(3))
+	assert.NoError(t, committer.Commit(block3))
+	assert.Equal(t, 1, v.count(3))
+}
+
+// aheadFailingValidator fails the validations ahead with aheadErr, e.g.
+// as a validator reading the values of the state would
+type aheadFailingValidator struct {
+	countingValidator
+	aheadErr error
+	aheads   int
+}
+
+func (v *aheadFailingValidator) ValidateAhead(block *common.Block) error {
+	v.Lock()
+	defer v.Unlock()
+	v.aheads++
+	return v.aheadErr
+}
+
+func TestPipelinedCommitterUnsettledState(t *testing.T) {
+	testPipelinedCommitterAheadFailure(t, sysccprovider.ErrStateNotSettled)
+}
+
+func TestPipelinedCommitterAheadError(t *testing.T) {
+	testPipelinedCommitterAheadFailure(t, errors.New("cannot read the chaincode definition"))
+}
+
+func testPipelinedCommitterAheadFailure(t *testing.T, aheadErr error) {
+	ledger := &memLedger{}
+	v := &aheadFailingValidator{countingValidator: countingValidator{validations: make(map[uint64]int)}, aheadErr: aheadErr}
+	committer := NewLedgerCommitterWithPublisher(ledger, v, func(*common.Block) error { return nil })
+	committer.pipeline = &commitPipeline{}
+
+	gb, _ := test.MakeGenesisBlock("TestLedger")
+	assert.NoError(t, committer.Commit(gb))
+	block1 := testutil.ConstructBlock(t, 1, gb.Header.Hash(), [][]byte{}, true)
+	block2 := testutil.ConstructBlock(t, 2, block1.Header.Hash(), [][]byte{}, true)
+
+	// The block whose validation ahead failed, having run before the block
+	// before it was committed, is validated again
+	committer.Prevalidate(block2)
+	assert.NoError(t, committer.Commit(block1))
+	assert.NoError(t, committer.Commit(block2))
+	assert.Equal(t, 1, v.aheads)
+	assert.Equal(t, 1, v.count(2))
+	height, err := committer.LedgerHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), height)
+}
+
+func TestCommitBlockContinuity(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/committertest")
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+	gb, _ := test.MakeGenesisBlock("TestLedger")
+	ledger, err := ledgermgmt.CreateLedger(gb)
+	assert.NoError(t, err, "Error while creating ledger: %s", err)
+	defer ledger.Close()
+
+	committer := NewLedgerCommitter(ledger, &validator.MockValidator{})
+	health := func() int {
+		rec := httptest.NewRecorder()
+		HealthHandler(rec, httptest.NewRequest("GET", "/healthz", nil))
+		return rec.Code
+	}
+
+	// A gap after the last block
+	block2 := testutil.ConstructBlock(t, 2, gb.Header.Hash(), [][]byte{}, true)
+	err = committer.Commit(block2)
+	assert.IsType(t, &BlockMismatchError{}, err)
+	assert.Equal(t, http.StatusServiceUnavailable, health())
+
+	// A fork of the last block
+	block1 := testutil.ConstructBlock(t, 1, []byte("other hash"), [][]byte{}, true)
+	err = committer.Commit(block1)
+	assert.IsType(t, &BlockMismatchError{}, err)
+	assert.Equal(t, http.StatusServiceUnavailable, health())
+
+	// The chain grows again
+	verified := hashChainVerification.Get("blocks").(*expvar.Int).Value()
+	block1 = testutil.ConstructBlock(t, 1, gb.Header.Hash(), [][]byte{}, true)
+	assert.NoError(t, committer.Commit(block1))
+	assert.Equal(t, http.StatusOK, health())
+	assert.Equal(t, verified+1, hashChainVerification.Get("blocks").(*expvar.Int).Value())
+
+	// Without hash chain verification, only the numbering of the blocks is checked
+	committer.verifyHashChain = false
+	block2 = testutil.ConstructBlock(t, 2, []byte("other hash"), [][]byte{}, true)
+	assert.NoError(t, committer.Commit(block2))
+	height, err := committer.LedgerHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), height)
+}
+
+func TestTxInfoBarrier(t *testing.T) {
+	txInfoOf := func(build func(*rwsetutil.RWSetBuilder)) txInfo {
+		rwsb := rwsetutil.NewRWSetBuilder()
+		build(rwsb)
+		results, err := rwsb.GetTxReadWriteSet().ToProtoBytes()
+		assert.NoError(t, err)
+		env, _, err := testutil.ConstructTransaction(t, results, false)
+		assert.NoError(t, err)
+		envBytes, _ := proto.Marshal(env)
+		return getTxInfo(envBytes)
+	}
+
+	info := txInfoOf(func(rwsb *rwsetutil.RWSetBuilder) { rwsb.AddToWriteSet("ns", "key", []byte("value")) })
+	assert.NotEmpty(t, info.txID)
+	assert.False(t, info.barrier)
+
+	// The VSCC of the next block reads the key-level endorsement policies
+	// from the metadata of keys, which deleting a key removes
+	info = txInfoOf(func(rwsb *rwsetutil.RWSetBuilder) {
+		rwsb.AddToMetadataWriteSet("ns", "key", map[string][]byte{"VALIDATION_PARAMETER": []byte("policy")})
+	})
+	assert.True(t, info.barrier)
+	info = txInfoOf(func(rwsb *rwsetutil.RWSetBuilder) { rwsb.AddToWriteSet("ns", "key", nil) })
+	assert.True(t, info.barrier)
+
+	env, _, err := testutil.ConstructTransaction(t, []byte("results"), false)
+	assert.NoError(t, err)
+	envBytes, _ := proto.Marshal(env)
+	assert.True(t, getTxInfo(envBytes).barrier)
+
+	assert.Equal(t, txInfo{}, getTxInfo([]byte("garbage")))
+
+	configBlock, _ := test.MakeGenesisBlock("TestLedger")
+	info = getTxInfo(configBlock.Data.Data[0])
+	assert.True(t, info.barrier)
+	assert.True(t, updatesConfig(configBlock))
+	assert.False(t, updatesConfig(testutil.ConstructBlock(t, 1, configBlock.Header.Hash(), [][]byte{envBytes}, false)))
+}
+
+func TestRecordRejectedTxs(t *testing.T) {
+	block := testutil.ConstructBlock(t, 1, []byte("hash"), [][]byte{[]byte("results1"), []byte("results2")}, false)
+	chainID, err := utils.GetChainIDFromBlock(block)
+	assert.NoError(t, err)
+	rejected := func() int64 {
+		if counter, ok := channelRejectedTxs(chainID).Get(peer.TxValidationCode_MVCC_READ_CONFLICT.String()).(*expvar.Int); ok {
+			return counter.Value()
+		}
+		return 0
+	}
+	before := rejected()
+
+	txsFilter := ledgerUtil.NewTxValidationFlags(len(block.Data.Data))
+	txsFilter.SetFlag(0, peer.TxValidationCode_VALID)
+	txsFilter.SetFlag(1, peer.TxValidationCode_MVCC_READ_CONFLICT)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
+	recordRejectedTxs(block)
+	assert.Equal(t, before+1, rejected(), "Should have counted the transaction with the read conflict only")
+
+	// Blocks without transactions filter are not counted
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = nil
+	recordRejectedTxs(block)
+	assert.Equal(t, before+1, rejected())
+}
+
+func TestCommitBlockLimits(t *testing.T) {
+	block := testutil.ConstructBlock(t, 1, []byte("hash"), [][]byte{[]byte("results1"), []byte("results2")}, false)
+
+	// The ledger isn't reached by blocks exceeding the limits
+	committer := &LedgerCommitter{validator: &validator.MockValidator{}, maxBlockTxs: 1}
+	assert.Error(t, committer.Commit(block))
+
+	committer = &LedgerCommitter{validator: &validator.MockValidator{}, maxBlockBytes: proto.Size(block) - 1}
+	assert.Error(t, committer.Commit(block))
+
+	committer = &LedgerCommitter{maxBlockTxs: 2, maxBlockBytes: proto.Size(block)}
+	assert.NoError(t, committer.checkBlockLimits(block))
+	assert.NoError(t, (&LedgerCommitter{}).checkBlockLimits(block), "Blocks shouldn't be limited by default")
+}
+
+// memLedger keeps the committed blocks in memory
+type memLedger struct {
+	blocks []*common.Block
+	// err, when set, fails the writes
+	err error
+}
+
+func (l *memLedger) Commit(block *common.Block) error {
+	if l.err != nil {
+		return l.err
+	}
+	l.blocks = append(l.blocks, block)
+	return nil
+}
+
+func (l *memLedger) GetBlockchainInfo() (*common.BlockchainInfo, error) {
+	info := &common.BlockchainInfo{Height: uint64(len(l.blocks))}
+	if len(l.blocks) > 0 {
+		info.CurrentBlockHash = l.blocks[len(l.blocks)-1].Header.Hash()
+	}
+	return info, nil
+}
+
+func (l *memLedger) GetBlockByNumber(blockNumber uint64) (*common.Block, error) {
+	if blockNumber >= uint64(len(l.blocks)) {
+		return nil, fmt.Errorf("no block %d", blockNumber)
+	}
+	return l.blocks[blockNumber], nil
+}
+
+func (l *memLedger) Close() {}
+
+func TestCommitterWithPublisher(t *testing.T) {
+	var published []uint64
+	publish := func(block *common.Block) error {
+		published = append(published, block.Header.Number)
+		return nil
+	}
+	ledger := &memLedger{}
+	committer := NewLedgerCommitterWithPublisher(ledger, &validator.MockValidator{}, publish)
+
+	gb, _ := test.MakeGenesisBlock("TestLedger")
+	assert.NoError(t, committer.Commit(gb))
+	block1 := testutil.ConstructBlock(t, 1, gb.Header.Hash(), [][]byte{}, true)
+	assert.NoError(t, committer.Commit(block1))
+
+	height, err := committer.LedgerHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), height)
+	assert.Equal(t, []uint64{0, 1}, published, "Committed blocks should have been handed to the publisher")
+	assert.Equal(t, []*common.Block{block1}, committer.GetBlocks([]uint64{1}))
+}
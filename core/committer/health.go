@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package committer
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// unhealthyChannels holds, by channel, the reason why the committer refused
+// the last block it received, until a block extending the chain is committed
+var unhealthyChannels = struct {
+	sync.RWMutex
+	reasons map[string]string
+}{reasons: make(map[string]string)}
+
+// failedCheck describes an unhealthy channel in the response of HealthHandler
+type failedCheck struct {
+	Component string `json:"component"`
+	Reason    string `json:"reason"`
+}
+
+type healthStatus struct {
+	Status       string        `json:"status"`
+	FailedChecks []failedCheck `json:"failed_checks,omitempty"`
+}
+
+func setUnhealthy(chainID string, reason string) {
+	unhealthyChannels.Lock()
+	defer unhealthyChannels.Unlock()
+	unhealthyChannels.reasons[chainID] = reason
+}
+
+func setHealthy(chainID string) {
+	unhealthyChannels.Lock()
+	defer unhealthyChannels.Unlock()
+	delete(unhealthyChannels.reasons, chainID)
+}
+
+// HealthHandler reports whether the chains of all the channels of the peer
+// grow, it responds 503 listing the channels whose committer refused a block
+// that doesn't extend the chain of the ledger
+func HealthHandler(w http.ResponseWriter, r *http.Request) {
+	unhealthyChannels.RLock()
+	var chainIDs []string
+	for chainID := range unhealthyChannels.reasons {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Strings(chainIDs)
+	status := healthStatus{Status: "OK"}
+	for _, chainID := range chainIDs {
+		status.FailedChecks = append(status.FailedChecks, failedCheck{Component: "channel " + chainID, Reason: unhealthyChannels.reasons[chainID]})
+	}
+	unhealthyChannels.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(status.FailedChecks) > 0 {
+		status.Status = http.StatusText(http.StatusServiceUnavailable)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(status)
+}
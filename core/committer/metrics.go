@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package committer
+
+import (
+	"expvar"
+	"sync"
+	"time"
+
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// rejectedTxs counts the transactions committed as invalid, by channel and
+// validation code, e.g. {"mychannel": {"MVCC_READ_CONFLICT": 3}}. It is
+// published with the other expvar variables on /debug/vars of the profiling
+// server of the peer
+var rejectedTxs = expvar.NewMap("committer_rejected_transactions")
+
+// oversizedBlocks counts by channel the blocks rejected because they exceed
+// the configured size or number of transactions
+var oversizedBlocks = expvar.NewMap("committer_oversized_blocks")
+
+// hashChainVerification accumulates the number of blocks whose previous hash
+// was verified before commit and the time spent, e.g.
+// {"blocks": 120, "seconds": 0.015}
+var hashChainVerification = expvar.NewMap("committer_hash_chain_verification")
+
+// rejectedTxsLock serializes the creation of the counters of a channel
+var rejectedTxsLock sync.Mutex
+
+// channelRejectedTxs returns the counters of the rejected transactions of a channel
+func channelRejectedTxs(chainID string) *expvar.Map {
+	rejectedTxsLock.Lock()
+	defer rejectedTxsLock.Unlock()
+
+	if counters, ok := rejectedTxs.Get(chainID).(*expvar.Map); ok {
+		return counters
+	}
+	counters := new(expvar.Map).Init()
+	rejectedTxs.Set(chainID, counters)
+	return counters
+}
+
+// recordRejectedTxs counts the transactions a committed block marks invalid,
+// and logs a record for each of them
+func recordRejectedTxs(block *common.Block) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		return
+	}
+	txsFilter := ledgerUtil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+
+	// the transactions of a block all belong to the channel of the block
+	chainID, err := utils.GetChainIDFromBlock(block)
+	if err != nil {
+		logger.Warningf("Not counting the rejected transactions of block %d: %s", block.Header.Number, err)
+		return
+	}
+
+	var counters *expvar.Map
+	var txIDs []string
+	for txIndex := range block.Data.Data {
+		if txIndex >= len(txsFilter) || txsFilter.IsValid(txIndex) {
+			continue
+		}
+		if counters == nil {
+			counters = channelRejectedTxs(chainID)
+			txIDs = utils.GetTxIDsFromBlock(block)
+		}
+
+		code := txsFilter.Flag(txIndex)
+		counters.Add(code.String(), 1)
+		logger.Infof("Rejected transaction: channel=%s block=%d index=%d txid=%s code=%s",
+			chainID, block.Header.Number, txIndex, txIDs[txIndex], code)
+	}
+}
+
+// recordHashChainVerification accounts for the verification of the previous
+// hash of a block
+func recordHashChainVerification(elapsed time.Duration) {
+	hashChainVerification.Add("blocks", 1)
+	hashChainVerification.AddFloat("seconds", elapsed.Seconds())
+}
@@ -0,0 +1,282 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package committer
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/core/committer/txvalidator"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// lsccName is the name of the lifecycle system chaincode; the validation
+// of a block relies on the chaincode definitions it writes
+const lsccName = "lscc"
+
+// commitPipeline keeps track of the block announced to be committed next,
+// and of its validation while the previous block is written to the ledger
+type commitPipeline struct {
+	sync.Mutex
+	next  *common.Block
+	ahead *aheadBlock
+}
+
+// aheadBlock is a block validated while the previous one is written
+type aheadBlock struct {
+	block *common.Block
+	// txIDs of the previous block, not yet visible to the duplicate check
+	// of the validator while this block was validated
+	prevTxIDs map[string]struct{}
+	done      chan struct{}
+	err       error
+}
+
+// announce records the block committed after the one being committed
+func (p *commitPipeline) announce(block *common.Block) {
+	p.Lock()
+	defer p.Unlock()
+	p.next = block
+}
+
+// takeNext returns the announced block if it follows the given block number
+func (p *commitPipeline) takeNext(number uint64) *common.Block {
+	p.Lock()
+	defer p.Unlock()
+	next := p.next
+	p.next = nil
+	if next == nil || next.Header == nil || next.Header.Number != number+1 {
+		return nil
+	}
+	return next
+}
+
+func (p *commitPipeline) setAhead(ab *aheadBlock) {
+	p.Lock()
+	defer p.Unlock()
+	p.ahead = ab
+}
+
+// takeAhead removes the block validated ahead, waiting for its validation
+func (p *commitPipeline) takeAhead() *aheadBlock {
+	p.Lock()
+	ab := p.ahead
+	p.ahead = nil
+	p.Unlock()
+	if ab != nil {
+		<-ab.done
+	}
+	return ab
+}
+
+// Prevalidate announces the block to be committed after the one passed to
+// the next Commit call. With the pipeline enabled, it is validated while the
+// block before it is written to the ledger, unless its validation depends on
+// the state written by that block (chaincode definitions, config), or it
+// updates the config of the channel, which the ledger validates that block with.
+func (lc *LedgerCommitter) Prevalidate(block *common.Block) {
+	if lc.pipeline == nil || block == nil {
+		return
+	}
+	lc.pipeline.announce(block)
+}
+
+// commitPipelined validates the block, unless it was validated ahead, then
+// writes it to the ledger while validating the block announced after it
+func (lc *LedgerCommitter) commitPipelined(block *common.Block) error {
+	next := lc.pipeline.takeNext(block.Header.Number)
+	if ab := lc.pipeline.takeAhead(); ab != nil && ab.block == block && ab.err == nil {
+		markDuplicateTxs(block, ab.prevTxIDs)
+	} else {
+		// The validation ahead ran against the state of the previous block
+		// before it was written, so its failure is not final
+		if ab != nil && ab.block == block && ab.err != sysccprovider.ErrStateNotSettled {
+			logger.Debugf("Validating block [%d] ahead failed, validating it again: %s", block.Header.Number, ab.err)
+		}
+		logger.Debug("Validating block")
+		if err := lc.validator.Validate(block); err != nil {
+			return err
+		}
+	}
+
+	if next != nil {
+		if txIDs, barrier := blockTxs(block); barrier {
+			logger.Debugf("Block [%d] depends on the state written by block [%d], not validating it ahead", next.Header.Number, block.Header.Number)
+		} else if updatesConfig(next) {
+			logger.Debugf("Block [%d] updates the config block [%d] is written with, not validating it ahead", next.Header.Number, block.Header.Number)
+		} else {
+			ab := &aheadBlock{block: next, prevTxIDs: txIDs, done: make(chan struct{})}
+			lc.pipeline.setAhead(ab)
+			go func() {
+				defer close(ab.done)
+				ab.err = lc.validateAhead(next)
+			}()
+		}
+	}
+
+	if err := lc.commitToLedger(block); err != nil {
+		// The block is committed again, the validation of the next one
+		// may have relied on state which isn't there
+		lc.flush()
+		return err
+	}
+	return nil
+}
+
+// validateAhead validates the block while the block before it is written;
+// a validator reading the values of the state then leaves it to validate
+// once the block before is committed
+func (lc *LedgerCommitter) validateAhead(block *common.Block) error {
+	if av, ok := lc.validator.(txvalidator.AheadValidator); ok {
+		return av.ValidateAhead(block)
+	}
+	return lc.validator.Validate(block)
+}
+
+// flush waits for the block validated ahead, if any, and discards it
+func (lc *LedgerCommitter) flush() {
+	if lc.pipeline == nil {
+		return
+	}
+	lc.pipeline.takeAhead()
+}
+
+// txInfo is the part of a transaction the pipeline depends on
+type txInfo struct {
+	txID    string
+	barrier bool
+	config  bool
+}
+
+// blockTxs returns the IDs of the valid transactions of the block, and
+// whether the validation of the next block depends on the state they write
+func blockTxs(block *common.Block) (map[string]struct{}, bool) {
+	txIDs := make(map[string]struct{})
+	barrier := false
+	txsFilter := txsFilterOf(block)
+	for tIdx, d := range block.Data.Data {
+		info := getTxInfo(d)
+		if info.txID != "" {
+			txIDs[info.txID] = struct{}{}
+		}
+		if info.barrier && txsFilter.IsValid(tIdx) {
+			barrier = true
+		}
+	}
+	return txIDs, barrier
+}
+
+// updatesConfig returns whether the block carries a config transaction, which
+// the validator applies to the config of the channel as it validates the block
+func updatesConfig(block *common.Block) bool {
+	for _, d := range block.Data.Data {
+		if getTxInfo(d).config {
+			return true
+		}
+	}
+	return false
+}
+
+// markDuplicateTxs invalidates the transactions of the block whose ID was
+// already used in the previous block, which the validator could not see
+// because the previous block had not reached the ledger yet
+func markDuplicateTxs(block *common.Block, prevTxIDs map[string]struct{}) {
+	txsFilter := txsFilterOf(block)
+	for tIdx, d := range block.Data.Data {
+		if !txsFilter.IsValid(tIdx) {
+			continue
+		}
+		info := getTxInfo(d)
+		if _, exists := prevTxIDs[info.txID]; info.txID != "" && exists {
+			logger.Errorf("Duplicate transaction found, %s, skipping", info.txID)
+			txsFilter.SetFlag(tIdx, peer.TxValidationCode_DUPLICATE_TXID)
+		}
+	}
+}
+
+// txsFilterOf returns the transactions filter set by the validator,
+// initializing it if the validator did not
+func txsFilterOf(block *common.Block) ledgerUtil.TxValidationFlags {
+	utils.InitBlockMetadata(block)
+	txsFilter := ledgerUtil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	if len(txsFilter) != len(block.Data.Data) {
+		txsFilter = ledgerUtil.NewTxValidationFlags(len(block.Data.Data))
+		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
+	}
+	return txsFilter
+}
+
+func getTxInfo(data []byte) txInfo {
+	env, err := utils.GetEnvelopeFromBlock(data)
+	if err != nil || env == nil {
+		return txInfo{}
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil || payload.Header == nil {
+		return txInfo{}
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return txInfo{}
+	}
+
+	switch common.HeaderType(chdr.Type) {
+	case common.HeaderType_ENDORSER_TRANSACTION:
+		info := txInfo{txID: chdr.TxId}
+		hdrExt, err := utils.GetChaincodeHeaderExtension(payload.Header)
+		if err != nil || hdrExt.ChaincodeId == nil || hdrExt.ChaincodeId.Name == lsccName {
+			// Be conservative with what cannot be inspected
+			info.barrier = true
+		} else {
+			info.barrier = writesMetadata(data)
+		}
+		return info
+	case common.HeaderType_CONFIG:
+		return txInfo{barrier: true, config: true}
+	default:
+		return txInfo{}
+	}
+}
+
+// writesMetadata returns whether the transaction writes or deletes the metadata
+// of keys, which the VSCC reads the key-level endorsement policies from; a
+// deleted key loses its metadata
+func writesMetadata(data []byte) bool {
+	action, err := utils.GetActionFromEnvelope(data)
+	if err != nil {
+		return true
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(action.Results); err != nil {
+		return true
+	}
+	for _, ns := range txRWSet.NsRwSets {
+		if len(ns.KvRwSet.MetadataWrites) > 0 {
+			return true
+		}
+		for _, write := range ns.KvRwSet.Writes {
+			if write.IsDelete {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -20,9 +20,13 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	util2 "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/validation"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
@@ -32,6 +36,7 @@ import (
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	msptesttools "github.com/hyperledger/fabric/msp/mgmt/testtools"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
@@ -133,6 +138,106 @@ func TestNewTxValidator_DuplicateTransactions(t *testing.T) {
 	assert.True(t, txsfltr.IsInvalid(0))
 }
 
+func TestEnvelopeStructureCapability(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/txvalidatortest")
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+
+	err := msptesttools.LoadMSPSetupForTesting()
+	if err != nil {
+		t.Fatalf("Could not initialize msp, err: %s", err)
+	}
+	signer, err := mspmgmt.GetLocalMSP().GetDefaultSigningIdentity()
+	if err != nil {
+		t.Fatalf("Could not initialize signer, err: %s", err)
+	}
+	creator, err := signer.Serialize()
+	assert.NoError(t, err)
+
+	gb, _ := test.MakeGenesisBlock("TestLedger")
+	ledger, _ := ledgermgmt.CreateLedger(gb)
+	defer ledger.Close()
+
+	// a signature header above the size limit, which the peers predating
+	// the structure checks accept
+	nonce := make([]byte, validation.MaxHeaderBytes)
+	txID, err := utils.ComputeProposalTxID(nonce, creator)
+	assert.NoError(t, err)
+	cis := &peer.ChaincodeInvocationSpec{ChaincodeSpec: &peer.ChaincodeSpec{ChaincodeId: &peer.ChaincodeID{Name: "foo"}}}
+	prop, _, err := utils.CreateChaincodeProposalWithTxIDNonceAndTransient(txID, common.HeaderType_ENDORSER_TRANSACTION, util2.GetTestChainID(), cis, nonce, creator, nil)
+	assert.NoError(t, err)
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &peer.Response{Status: 200}, nil, nil, nil, nil, signer)
+	assert.NoError(t, err)
+	env, err := utils.CreateSignedTx(prop, signer, presp)
+	assert.NoError(t, err)
+
+	for _, capable := range []bool{false, true} {
+		block := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(env)}}}
+		block.Header = &common.BlockHeader{Number: 1, DataHash: block.Data.Hash()}
+		utils.InitBlockMetadata(block)
+
+		support := &mocktxvalidator.Support{LedgerVal: ledger, CapabilitiesVal: map[string]bool{config.EnvelopeStructureCapability: capable}}
+		tValidator := &txValidator{support, &validator.MockVsccValidator{}}
+		tValidator.Validate(block)
+
+		txsfltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+		if capable {
+			assert.True(t, txsfltr.IsSetTo(0, peer.TxValidationCode_BAD_PAYLOAD))
+		} else {
+			assert.True(t, txsfltr.IsSetTo(0, peer.TxValidationCode_VALID))
+		}
+	}
+}
+
+func TestBlockTxIDUniquenessCapability(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/txvalidatortest")
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+
+	err := msptesttools.LoadMSPSetupForTesting()
+	if err != nil {
+		t.Fatalf("Could not initialize msp, err: %s", err)
+	}
+	signer, err := mspmgmt.GetLocalMSP().GetDefaultSigningIdentity()
+	if err != nil {
+		t.Fatalf("Could not initialize signer, err: %s", err)
+	}
+	creator, err := signer.Serialize()
+	assert.NoError(t, err)
+
+	gb, _ := test.MakeGenesisBlock("TestLedger")
+	ledger, _ := ledgermgmt.CreateLedger(gb)
+	defer ledger.Close()
+
+	cis := &peer.ChaincodeInvocationSpec{ChaincodeSpec: &peer.ChaincodeSpec{ChaincodeId: &peer.ChaincodeID{Name: "foo"}}}
+	prop, _, err := utils.CreateChaincodeProposal(common.HeaderType_ENDORSER_TRANSACTION, util2.GetTestChainID(), cis, creator)
+	assert.NoError(t, err)
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &peer.Response{Status: 200}, nil, nil, nil, nil, signer)
+	assert.NoError(t, err)
+	env, err := utils.CreateSignedTx(prop, signer, presp)
+	assert.NoError(t, err)
+
+	// the same transaction twice in the block, which the peers predating the
+	// check within the block accept
+	for _, capable := range []bool{false, true} {
+		block := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(env), utils.MarshalOrPanic(env)}}}
+		block.Header = &common.BlockHeader{Number: 1, DataHash: block.Data.Hash()}
+		utils.InitBlockMetadata(block)
+
+		support := &mocktxvalidator.Support{LedgerVal: ledger, CapabilitiesVal: map[string]bool{config.BlockTxIDUniquenessCapability: capable}}
+		tValidator := &txValidator{support, &validator.MockVsccValidator{}}
+		tValidator.Validate(block)
+
+		txsfltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+		assert.True(t, txsfltr.IsSetTo(0, peer.TxValidationCode_VALID))
+		if capable {
+			assert.True(t, txsfltr.IsSetTo(1, peer.TxValidationCode_DUPLICATE_TXID))
+		} else {
+			assert.True(t, txsfltr.IsSetTo(1, peer.TxValidationCode_VALID))
+		}
+	}
+}
+
 func createCCUpgradeEnvelope(chainID, chaincodeName, chaincodeVersion string, signer msp.SigningIdentity) (*common.Envelope, error) {
 	creator, err := signer.Serialize()
 	if err != nil {
@@ -248,3 +353,63 @@ func TestInvalidTXsForUpgradeCC(t *testing.T) {
 
 	assert.EqualValues(t, expectTxsFltr, finalfltr)
 }
+
+func TestValidateLSCCWrites(t *testing.T) {
+	rwset := func(writes map[string]bool) *rwsetutil.TxRwSet {
+		b := rwsetutil.NewRWSetBuilder()
+		for ns, write := range writes {
+			if write {
+				b.AddToWriteSet(ns, "key", []byte("value"))
+			} else {
+				b.AddToReadSet(ns, "key", nil)
+			}
+		}
+		return b.GetTxReadWriteSet()
+	}
+
+	// lscc writes to its own namespace
+	assert.NoError(t, validateLSCCWrites("lscc", rwset(map[string]bool{"lscc": true})))
+
+	// application chaincodes can write to any other namespace and read lscc's
+	assert.NoError(t, validateLSCCWrites("mycc", rwset(map[string]bool{"mycc": true, "lscc": false, "othercc": true, "qscc": true})))
+
+	// application chaincodes cannot write to lscc's namespace
+	assert.Error(t, validateLSCCWrites("mycc", rwset(map[string]bool{"mycc": true, "lscc": true})))
+
+	// writing the metadata of keys counts as writing
+	metadataWrite := &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{{NameSpace: "lscc",
+		KvRwSet: &kvrwset.KVRWSet{MetadataWrites: []*kvrwset.KVMetadataWrite{{Key: "mycc"}}}}}}
+	assert.Error(t, validateLSCCWrites("mycc", metadataWrite))
+}
+
+func TestValidateNoMetadataWrites(t *testing.T) {
+	b := rwsetutil.NewRWSetBuilder()
+	b.AddToWriteSet("mycc", "key", []byte("value"))
+	assert.NoError(t, validateNoMetadataWrites(b.GetTxReadWriteSet()))
+
+	metadataWrite := &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{{NameSpace: "mycc",
+		KvRwSet: &kvrwset.KVRWSet{MetadataWrites: []*kvrwset.KVMetadataWrite{{Key: "key"}}}}}}
+	assert.Error(t, validateNoMetadataWrites(metadataWrite))
+}
+
+func TestCheckChaincodeVersion(t *testing.T) {
+	cd := &ccprovider.ChaincodeData{Name: "mycc", Version: "2.0", Vscc: "vscc"}
+	action := func(ccid *peer.ChaincodeID) *peer.ChaincodeAction {
+		return &peer.ChaincodeAction{ChaincodeId: ccid}
+	}
+
+	assert.NoError(t, checkChaincodeVersion("mycc", action(&peer.ChaincodeID{Name: "mycc", Version: "2.0"}), cd))
+
+	// endorsers which don't record the chaincode are trusted as before
+	assert.NoError(t, checkChaincodeVersion("mycc", action(nil), cd))
+	assert.NoError(t, checkChaincodeVersion("mycc", nil, cd))
+
+	// a transaction endorsed before an upgrade expired with the old version
+	err := checkChaincodeVersion("mycc", action(&peer.ChaincodeID{Name: "mycc", Version: "1.0"}), cd)
+	assert.Error(t, err)
+	assert.IsType(t, chaincodeVersionMismatchErr(""), err)
+
+	err = checkChaincodeVersion("mycc", action(&peer.ChaincodeID{Name: "othercc", Version: "2.0"}), cd)
+	assert.Error(t, err)
+	assert.NotEqual(t, chaincodeVersionMismatchErr(""), err)
+}
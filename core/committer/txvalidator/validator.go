@@ -20,13 +20,17 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	coreUtil "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/common/validation"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/msp"
 
@@ -56,6 +60,9 @@ type Support interface {
 	// GetMSPIDs returns the IDs for the application MSPs
 	// that have been defined in the channel
 	GetMSPIDs(cid string) []string
+
+	// HasCapability returns true if the channel requires the capability of its peers
+	HasCapability(name string) bool
 }
 
 //Validator interface which defines API to validate block transactions
@@ -65,11 +72,22 @@ type Validator interface {
 	Validate(block *common.Block) error
 }
 
+// AheadValidator validates a block while the block before it is committed
+type AheadValidator interface {
+	// ValidateAhead validates the block like Validate, except that the block
+	// before it may not have reached the state yet. It returns
+	// sysccprovider.ErrStateNotSettled, leaving the block to validate once the
+	// block before is committed, when a VSCC reads the values of the state.
+	ValidateAhead(block *common.Block) error
+}
+
 // private interface to decouple tx validator
 // and vscc execution, in order to increase
 // testability of txValidator
 type vsccValidator interface {
-	VSCCValidateTx(payload *common.Payload, envBytes []byte, env *common.Envelope) error
+	// VSCCValidateTx runs the vscc of the transaction; ahead is true when
+	// the block before the transaction is being committed meanwhile
+	VSCCValidateTx(payload *common.Payload, envBytes []byte, env *common.Envelope, ahead bool) error
 }
 
 // vsccValidator implementation which used to call
@@ -96,8 +114,14 @@ func init() {
 
 // NewTxValidator creates new transactions validator
 func NewTxValidator(support Support) Validator {
+	return NewTxValidatorWithProviders(support, ccprovider.GetChaincodeProvider())
+}
+
+// NewTxValidatorWithProviders creates a transactions validator which runs
+// the vscc of the transactions through the chaincode provider
+func NewTxValidatorWithProviders(support Support, ccp ccprovider.ChaincodeProvider) Validator {
 	// Encapsulates interface implementation
-	return &txValidator{support, &vsccValidatorImpl{support: support, ccprovider: ccprovider.GetChaincodeProvider()}}
+	return &txValidator{support, &vsccValidatorImpl{support: support, ccprovider: ccp}}
 }
 
 func (v *txValidator) chainExists(chain string) bool {
@@ -113,6 +137,15 @@ type ChaincodeInstance struct {
 }
 
 func (v *txValidator) Validate(block *common.Block) error {
+	return v.validate(block, false)
+}
+
+// ValidateAhead validates the block while the block before it is committed
+func (v *txValidator) ValidateAhead(block *common.Block) error {
+	return v.validate(block, true)
+}
+
+func (v *txValidator) validate(block *common.Block, ahead bool) error {
 	logger.Debug("START Block Validation")
 	defer logger.Debug("END Block Validation")
 	// Initialize trans as valid here, then set invalidation reason code upon invalidation below
@@ -121,6 +154,9 @@ func (v *txValidator) Validate(block *common.Block) error {
 	txsChaincodeNames := make(map[int]*ChaincodeInstance)
 	// upgradedChaincodes records all the chaincodes that are upgrded in a block
 	txsUpgradedChaincodes := make(map[int]*ChaincodeInstance)
+	// txIDs records the ids of the transactions of the block, to detect
+	// the transactions replayed within the block
+	txIDs := make(map[string]bool)
 	for tIdx, d := range block.Data.Data {
 		if d != nil {
 			if env, err := utils.GetEnvelopeFromBlock(d); err != nil {
@@ -153,6 +189,17 @@ func (v *txValidator) Validate(block *common.Block) error {
 				channel := chdr.ChannelId
 				logger.Debug("Transaction is for chain %s", channel)
 
+				// the peers predating the structure checks of the envelopes
+				// accept the transactions failing them, hence they are only
+				// enforced once the channel requires it of every peer
+				if v.support.HasCapability(config.EnvelopeStructureCapability) {
+					if _, _, err = validation.CheckEnvelope(env); err != nil {
+						logger.Errorf("Invalid transaction with index %d, error %s", tIdx, err)
+						txsfltr.SetFlag(tIdx, peer.TxValidationCode_BAD_PAYLOAD)
+						continue
+					}
+				}
+
 				if !v.chainExists(channel) {
 					logger.Errorf("Dropping transaction for non-existent chain %s", channel)
 					txsfltr.SetFlag(tIdx, peer.TxValidationCode_TARGET_CHAIN_NOT_FOUND)
@@ -160,20 +207,42 @@ func (v *txValidator) Validate(block *common.Block) error {
 				}
 
 				if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
-					// Check duplicate transactions
+					// Check duplicate transactions, ValidateTransaction checked
+					// that the TxID is derived from the nonce and the creator.
+					// The peers predating the check within the block accept the
+					// replays, hence it is only enforced once the channel requires it
 					txID := chdr.TxId
+					if v.support.HasCapability(config.BlockTxIDUniquenessCapability) {
+						if txIDs[txID] {
+							logger.Error("Duplicate transaction found in block, ", txID, ", skipping")
+							txsfltr.SetFlag(tIdx, peer.TxValidationCode_DUPLICATE_TXID)
+							continue
+						}
+						txIDs[txID] = true
+					}
 					if _, err := v.support.Ledger().GetTransactionByID(txID); err == nil {
 						logger.Error("Duplicate transaction found, ", txID, ", skipping")
 						txsfltr.SetFlag(tIdx, peer.TxValidationCode_DUPLICATE_TXID)
 						continue
+					} else if err != blkstorage.ErrNotFoundInIndex {
+						err = fmt.Errorf("Could not check whether transaction %s is a duplicate: %s", txID, err)
+						logger.Critical(err)
+						return err
 					}
 
 					//the payload is used to get headers
 					logger.Debug("Validating transaction vscc tx validate")
-					if err = v.vscc.VSCCValidateTx(payload, d, env); err != nil {
+					if err = v.vscc.VSCCValidateTx(payload, d, env, ahead); err == sysccprovider.ErrStateNotSettled {
+						logger.Debugf("VSCC of transaction txId = %s read the state of the block before, which is not committed yet", txID)
+						return err
+					} else if err != nil {
 						txID := txID
 						logger.Errorf("VSCCValidateTx for transaction txId = %s returned error %s", txID, err)
-						txsfltr.SetFlag(tIdx, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+						if _, isVersionMismatch := err.(chaincodeVersionMismatchErr); isVersionMismatch {
+							txsfltr.SetFlag(tIdx, peer.TxValidationCode_EXPIRED_CHAINCODE)
+						} else {
+							txsfltr.SetFlag(tIdx, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+						}
 						continue
 					}
 
@@ -359,7 +428,7 @@ func (v *txValidator) getUpgradeTxInstance(chainID string, cdsBytes []byte) (*Ch
 	}, nil
 }
 
-func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []byte, env *common.Envelope) error {
+func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []byte, env *common.Envelope, ahead bool) error {
 	// get channel header
 	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
 	if err != nil {
@@ -395,6 +464,36 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 		return err
 	}
 
+	// get the read-write set to check the namespaces and the metadata
+	// the transaction writes
+	respPayload, err := utils.GetActionFromEnvelope(envBytes)
+	if err != nil {
+		logger.Errorf("Cannot extract the chaincode action for txid=%s, err %s", txid, err)
+		return err
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if respPayload != nil {
+		if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+			logger.Errorf("Cannot unmarshal the read-write set for txid=%s, err %s", txid, err)
+			return err
+		}
+	}
+	// the peers predating the check commit the chaincodes deployed through
+	// a chaincode to chaincode call to lscc, hence it is only enforced once
+	// the channel requires it
+	if v.support.HasCapability(config.LSCCWritesCapability) {
+		if err = validateLSCCWrites(hdrExt.ChaincodeId.Name, txRWSet); err != nil {
+			logger.Errorf("Invalid write to the namespace of lscc for txid=%s, err %s", txid, err)
+			return err
+		}
+	}
+	if !v.support.HasCapability(config.KeyMetadataCapability) {
+		if err = validateNoMetadataWrites(txRWSet); err != nil {
+			logger.Errorf("Invalid metadata write for txid=%s, err %s", txid, err)
+			return err
+		}
+	}
+
 	var vscc string
 	var policy []byte
 	if hdrExt.ChaincodeId.Name != "lscc" {
@@ -410,6 +509,15 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 		}
 		vscc = cd.Vscc
 		policy = cd.Policy
+
+		// the peers predating the check ignore the chaincode recorded in
+		// the action, hence it is only enforced once the channel requires it
+		if v.support.HasCapability(config.ChaincodeVersionCapability) {
+			if err = checkChaincodeVersion(hdrExt.ChaincodeId.Name, respPayload, cd); err != nil {
+				logger.Errorf("Chaincode version check failed for txid %s, due to %s", txid, err)
+				return err
+			}
+		}
 	} else {
 		// when we are validating LSCC, we use the default
 		// VSCC and a default policy that requires one signature
@@ -430,9 +538,20 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 	version := coreUtil.GetSysCCVersion()
 	cccid := v.ccprovider.GetCCContext(chainID, vscc, version, vscctxid, true, nil, nil)
 
+	// the values of the state the VSCC reads are not settled while the
+	// block before is committed
+	var unsettled *sysccprovider.UnsettledState
+	if ahead {
+		unsettled = &sysccprovider.UnsettledState{}
+		ctxt = sysccprovider.WithUnsettledState(ctxt, unsettled)
+	}
+
 	// invoke VSCC
 	logger.Debug("Invoking VSCC txid", txid, "chaindID", chainID)
 	res, _, err := v.ccprovider.ExecuteChaincode(ctxt, cccid, args)
+	if unsettled != nil && unsettled.ValuesRead() {
+		return sysccprovider.ErrStateNotSettled
+	}
 	if err != nil {
 		logger.Errorf("Invoke VSCC failed for transaction txid=%s, error %s", txid, err)
 		return err
@@ -445,6 +564,70 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 	return nil
 }
 
+// chaincodeVersionMismatchErr is returned when a transaction was endorsed
+// against another version of the chaincode than the committed one
+type chaincodeVersionMismatchErr string
+
+func (e chaincodeVersionMismatchErr) Error() string {
+	return string(e)
+}
+
+// checkChaincodeVersion checks that the chaincode recorded in the chaincode
+// action of a transaction, if any, is the committed definition cd of the
+// invoked chaincode, so that a transaction endorsed against a version that has
+// since been upgraded is not committed. Transactions endorsed by peers which
+// don't record the chaincode in the action are accepted as before
+func checkChaincodeVersion(ccName string, action *peer.ChaincodeAction, cd *ccprovider.ChaincodeData) error {
+	if action == nil || action.ChaincodeId == nil {
+		return nil
+	}
+	if action.ChaincodeId.Name != ccName {
+		return fmt.Errorf("chaincode action of chaincode %s for an invocation of chaincode %s", action.ChaincodeId.Name, ccName)
+	}
+	if action.ChaincodeId.Version != cd.Version {
+		return chaincodeVersionMismatchErr(fmt.Sprintf("chaincode %s was endorsed at version %s but version %s is committed", ccName, action.ChaincodeId.Version, cd.Version))
+	}
+	return nil
+}
+
+// validateLSCCWrites checks that a transaction invoking chaincode ccID
+// with read-write set txRWSet writes to the namespace of lscc only if it
+// invokes lscc. The namespace of lscc is written only by deploy and upgrade,
+// which are never legitimately invoked by an application chaincode. Whether
+// the other system chaincodes may be invoked depends on the configuration
+// of the endorsing peer, hence it is only enforced at endorsement
+func validateLSCCWrites(ccID string, txRWSet *rwsetutil.TxRwSet) error {
+	if ccID == "lscc" {
+		return nil
+	}
+
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != "lscc" || nsRWSet.KvRwSet == nil {
+			continue
+		}
+
+		if len(nsRWSet.KvRwSet.Writes) != 0 || len(nsRWSet.KvRwSet.MetadataWrites) != 0 {
+			return fmt.Errorf("chaincode %s attempted to write to the namespace of lscc", ccID)
+		}
+	}
+
+	return nil
+}
+
+// validateNoMetadataWrites checks that a transaction does not write the
+// metadata of keys, on the channels which do not require the KeyMetadata
+// capability: the peers predating it would commit the transaction without
+// the metadata writes, and their state would diverge
+func validateNoMetadataWrites(txRWSet *rwsetutil.TxRwSet) error {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.KvRwSet != nil && len(nsRWSet.KvRwSet.MetadataWrites) != 0 {
+			return fmt.Errorf("the transaction writes the metadata of keys of namespace %s, but the channel does not require the %s capability",
+				nsRWSet.NameSpace, config.KeyMetadataCapability)
+		}
+	}
+	return nil
+}
+
 func (v *vsccValidatorImpl) getCDataForCC(ccid string) (*ccprovider.ChaincodeData, error) {
 	l := v.support.Ledger()
 	if l == nil {
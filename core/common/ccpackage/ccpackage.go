@@ -176,9 +176,46 @@ func OwnerCreateSignedCCDepSpec(cds *peer.ChaincodeDeploymentSpec, instPolicy *c
 		return nil, fmt.Errorf("must provide an instantiation policy")
 	}
 
-	cdsbytes := utils.MarshalOrPanic(cds)
+	return ownerCreateSignedCCDepSpec(cds, utils.MarshalOrPanic(instPolicy), owner)
+}
+
+// OwnerCreateSignedCCDepSpecWithTemplate creates a package from a
+// ChaincodeDeploymentSpec whose instantiation policy is a template, such as
+// "MAJORITY Admins", resolved against the organizations of the channel config
+// when the chaincode is instantiated or upgraded, and optionally endorses it
+func OwnerCreateSignedCCDepSpecWithTemplate(cds *peer.ChaincodeDeploymentSpec, template *common.ImplicitMetaPolicy, owner msp.SigningIdentity) (*common.Envelope, error) {
+	if cds == nil {
+		return nil, fmt.Errorf("invalid chaincode deployment spec")
+	}
+
+	if template == nil || template.SubPolicy == "" {
+		return nil, fmt.Errorf("must provide an instantiation policy template")
+	}
 
-	instpolicybytes := utils.MarshalOrPanic(instPolicy)
+	instPolicy := &common.Policy{Type: int32(common.Policy_IMPLICIT_META), Policy: utils.MarshalOrPanic(template)}
+	return ownerCreateSignedCCDepSpec(cds, utils.MarshalOrPanic(instPolicy), owner)
+}
+
+// InstantiationPolicyTemplate returns the template of an instantiation policy
+// created by OwnerCreateSignedCCDepSpecWithTemplate, and false if the
+// instantiation policy is a SignaturePolicyEnvelope. The two can't be confused
+// since the version of a SignaturePolicyEnvelope, in the place of the type of
+// the Policy, is always 0
+func InstantiationPolicyTemplate(instPolicy []byte) (*common.ImplicitMetaPolicy, bool, error) {
+	policy := &common.Policy{}
+	if err := proto.Unmarshal(instPolicy, policy); err != nil || policy.Type != int32(common.Policy_IMPLICIT_META) {
+		return nil, false, nil
+	}
+
+	template := &common.ImplicitMetaPolicy{}
+	if err := proto.Unmarshal(policy.Policy, template); err != nil {
+		return nil, true, fmt.Errorf("invalid instantiation policy template, err %s", err)
+	}
+	return template, true, nil
+}
+
+func ownerCreateSignedCCDepSpec(cds *peer.ChaincodeDeploymentSpec, instpolicybytes []byte, owner msp.SigningIdentity) (*common.Envelope, error) {
+	cdsbytes := utils.MarshalOrPanic(cds)
 
 	var endorsements []*peer.Endorsement
 	//it is not mandatory (at this utils level) to have a signature
@@ -64,6 +64,55 @@ func TestOwnerCreateSignedCCDepSpec(t *testing.T) {
 	}
 }
 
+func TestOwnerCreateSignedCCDepSpecWithTemplate(t *testing.T) {
+	template := &common.ImplicitMetaPolicy{Rule: common.ImplicitMetaPolicy_MAJORITY, SubPolicy: "Admins"}
+	env, err := OwnerCreateSignedCCDepSpecWithTemplate(&peer.ChaincodeDeploymentSpec{CodePackage: []byte("codepackage")}, template, signer)
+	if err != nil || env == nil {
+		t.Fatalf("error owner creating package %s", err)
+		return
+	}
+
+	_, sdepspec, err := ExtractSignedCCDepSpec(env)
+	if err != nil {
+		t.Fatalf("error extracting package %s", err)
+		return
+	}
+	got, isTemplate, err := InstantiationPolicyTemplate(sdepspec.InstantiationPolicy)
+	if err != nil || !isTemplate || !proto.Equal(got, template) {
+		t.Fatalf("expected instantiation policy template %v, got %v (%t, %v)", template, got, isTemplate, err)
+		return
+	}
+
+	//the signature of the owner covers the template like any instantiation policy
+	env, err = SignExistingPackage(env, signer)
+	if err != nil || env == nil {
+		t.Fatalf("error signing existing package %s", err)
+		return
+	}
+
+	_, err = OwnerCreateSignedCCDepSpecWithTemplate(&peer.ChaincodeDeploymentSpec{CodePackage: []byte("codepackage")}, &common.ImplicitMetaPolicy{}, signer)
+	if err == nil {
+		t.Fatalf("expected error creating a package with an empty template")
+		return
+	}
+}
+
+func TestInstantiationPolicyTemplate(t *testing.T) {
+	mspid, _ := localmsp.GetIdentifier()
+	sigpolicy := createInstantiationPolicy(mspid, mspprotos.MSPRole_ADMIN)
+	_, isTemplate, err := InstantiationPolicyTemplate(utils.MarshalOrPanic(sigpolicy))
+	if err != nil || isTemplate {
+		t.Fatalf("a signature policy should not be taken for a template (%t, %v)", isTemplate, err)
+		return
+	}
+
+	_, isTemplate, err = InstantiationPolicyTemplate(utils.MarshalOrPanic(&common.Policy{Type: int32(common.Policy_IMPLICIT_META), Policy: []byte("garbage")}))
+	if err == nil || !isTemplate {
+		t.Fatalf("expected error decoding a bad template")
+		return
+	}
+}
+
 func TestAddSignature(t *testing.T) {
 	mspid, _ := localmsp.GetIdentifier()
 	sigpolicy := createInstantiationPolicy(mspid, mspprotos.MSPRole_ADMIN)
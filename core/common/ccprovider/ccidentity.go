@@ -0,0 +1,184 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ccprovider
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/factory"
+)
+
+// The identity of a chaincode at instantiation only depends on the code
+// package and on the name and version of the chaincode, whatever the format
+// of the package installed on the peer (raw or signed CDS). Peers that
+// installed the same code therefore record the same identity, and peers that
+// installed different code record different ones.
+//
+// Chaincodes instantiated before this identity was introduced recorded
+// package data in the legacy format, whose hashes and identity were
+// computed differently. That data carries no format version; it keeps
+// being validated with the legacy computation so that existing chaincodes
+// still run and historical deployments replay unchanged. As the endorsers
+// choose the format, VSCC rejects new deployments and upgrades in the
+// legacy format once the channel config requires the ChaincodeIdentity
+// capability.
+
+const (
+	// legacyIdentityVersion is the format of the package data recorded
+	// before the identity only depended on the code and metadata hashes
+	legacyIdentityVersion uint32 = iota
+
+	// currentIdentityVersion is the format of the package data recorded
+	// for new deployments
+	currentIdentityVersion
+)
+
+// hashCodePackage returns the hash of the code package of a chaincode
+func hashCodePackage(codePackage []byte) ([]byte, error) {
+	if err := factory.InitFactories(nil); err != nil {
+		return nil, fmt.Errorf("Internal error, BCCSP could not be initialized : %s", err)
+	}
+	return factory.GetDefault().Hash(codePackage, &bccsp.SHAOpts{})
+}
+
+// hashMetaData returns the hash of the name and version of a chaincode.
+// The name is NUL terminated so that different name/version pairs never
+// produce the same hash input
+func hashMetaData(name string, version string) ([]byte, error) {
+	if err := factory.InitFactories(nil); err != nil {
+		return nil, fmt.Errorf("Internal error, BCCSP could not be initialized : %s", err)
+	}
+	msg := make([]byte, 0, len(name)+len(version)+1)
+	msg = append(msg, name...)
+	msg = append(msg, 0)
+	msg = append(msg, version...)
+	return factory.GetDefault().Hash(msg, &bccsp.SHAOpts{})
+}
+
+// computeChaincodeIdentity returns the identity of a chaincode given the
+// hash of its code package and the hash of its name and version
+func computeChaincodeIdentity(codeHash []byte, metaDataHash []byte) ([]byte, error) {
+	if err := factory.InitFactories(nil); err != nil {
+		return nil, fmt.Errorf("Internal error, BCCSP could not be initialized : %s", err)
+	}
+	msg := make([]byte, 0, len(codeHash)+len(metaDataHash))
+	msg = append(msg, codeHash...)
+	msg = append(msg, metaDataHash...)
+	return factory.GetDefault().Hash(msg, &bccsp.SHAOpts{})
+}
+
+// legacyCodeHash returns the hash of the code package of a chaincode as
+// computed for package data in the legacy format, which appends the empty
+// digest to the code package
+func legacyCodeHash(codePackage []byte) ([]byte, error) {
+	if err := factory.InitFactories(nil); err != nil {
+		return nil, fmt.Errorf("Internal error, BCCSP could not be initialized : %s", err)
+	}
+	hash, err := factory.GetDefault().GetHash(&bccsp.SHAOpts{})
+	if err != nil {
+		return nil, err
+	}
+	return hash.Sum(codePackage), nil
+}
+
+// legacyMetaDataHash returns the hash of the name and version of a
+// chaincode as computed for package data in the legacy format
+func legacyMetaDataHash(name string, version string) ([]byte, error) {
+	if err := factory.InitFactories(nil); err != nil {
+		return nil, fmt.Errorf("Internal error, BCCSP could not be initialized : %s", err)
+	}
+	return factory.GetDefault().Hash([]byte(name+version), &bccsp.SHAOpts{})
+}
+
+// computeLegacyChaincodeIdentity returns the identity of a chaincode whose
+// package data is in the legacy format. The signature hash is only set for
+// signed packages
+func computeLegacyChaincodeIdentity(codeHash []byte, metaDataHash []byte, signatureHash []byte) ([]byte, error) {
+	if err := factory.InitFactories(nil); err != nil {
+		return nil, fmt.Errorf("Internal error, BCCSP could not be initialized : %s", err)
+	}
+	msg := make([]byte, 0, len(codeHash)+len(metaDataHash)+len(signatureHash))
+	msg = append(msg, codeHash...)
+	msg = append(msg, metaDataHash...)
+	msg = append(msg, signatureHash...)
+	return factory.GetDefault().Hash(msg, &bccsp.SHAOpts{})
+}
+
+// HasLegacyIdentity returns true if the package data recorded in the
+// ChaincodeData is in the legacy format, i.e. the chaincode was
+// instantiated before the current identity was introduced
+func HasLegacyIdentity(cd *ChaincodeData) bool {
+	data := &CDSData{}
+	if err := proto.Unmarshal(cd.Data, data); err != nil {
+		return false
+	}
+	return data.IdentityVersion == legacyIdentityVersion
+}
+
+// ValidateChaincodeIdentity checks that the identity of the instantiated
+// chaincode described by the ChaincodeData is the one derived from the
+// package data recorded with it, and that the package data matches the
+// name and version of the chaincode
+func ValidateChaincodeIdentity(cd *ChaincodeData) error {
+	if cd == nil {
+		return fmt.Errorf("nil chaincode data")
+	}
+
+	// SignedCDSData is a superset of CDSData: the signature hash is only
+	// set for signed packages
+	data := &SignedCDSData{}
+	if err := proto.Unmarshal(cd.Data, data); err != nil {
+		return fmt.Errorf("invalid package data for chaincode %s:%s: %s", cd.Name, cd.Version, err)
+	}
+	if len(data.CodeHash) == 0 {
+		return fmt.Errorf("missing code hash for chaincode %s:%s", cd.Name, cd.Version)
+	}
+
+	var metaDataHash, id []byte
+	var err error
+	switch data.IdentityVersion {
+	case legacyIdentityVersion:
+		metaDataHash, err = legacyMetaDataHash(cd.Name, cd.Version)
+	case currentIdentityVersion:
+		metaDataHash, err = hashMetaData(cd.Name, cd.Version)
+	default:
+		return fmt.Errorf("unknown package data format %d for chaincode %s:%s", data.IdentityVersion, cd.Name, cd.Version)
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(metaDataHash, data.MetaDataHash) {
+		return fmt.Errorf("metadata hash mismatch for chaincode %s:%s", cd.Name, cd.Version)
+	}
+
+	if data.IdentityVersion == legacyIdentityVersion {
+		id, err = computeLegacyChaincodeIdentity(data.CodeHash, data.MetaDataHash, data.SignatureHash)
+	} else {
+		id, err = computeChaincodeIdentity(data.CodeHash, data.MetaDataHash)
+	}
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(id, cd.Id) {
+		return fmt.Errorf("identity mismatch for chaincode %s:%s", cd.Name, cd.Version)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ccprovider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestChaincodeIdentity(t *testing.T) {
+	cds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "testcc", Version: "0"}, Input: &pb.ChaincodeInput{Args: [][]byte{[]byte("")}}}, CodePackage: []byte("code")}
+
+	_, _, cd, err := processCDS(cds, false)
+	if err != nil {
+		t.Fatalf("cannot create package %s", err)
+	}
+	if err = ValidateChaincodeIdentity(cd); err != nil {
+		t.Fatalf("identity of CDS package should be valid: %s", err)
+	}
+
+	// the same code in a signed package has the same identity
+	_, _, scd, err := processSignedCDS(cds, &common.SignaturePolicyEnvelope{Version: 1}, false)
+	if err != nil {
+		t.Fatalf("cannot create signed package %s", err)
+	}
+	if err = ValidateChaincodeIdentity(scd); err != nil {
+		t.Fatalf("identity of signed CDS package should be valid: %s", err)
+	}
+	if !bytes.Equal(cd.Id, scd.Id) {
+		t.Fatalf("the identity should not depend on the package format")
+	}
+
+	// identity is deterministic
+	_, _, cd2, err := processCDS(cds, false)
+	if err != nil {
+		t.Fatalf("cannot create package %s", err)
+	}
+	if !bytes.Equal(cd.Id, cd2.Id) {
+		t.Fatalf("the identity should be deterministic")
+	}
+
+	// different code, different identity
+	other := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: cds.ChaincodeSpec, CodePackage: []byte("othercode")}
+	_, _, ocd, err := processCDS(other, false)
+	if err != nil {
+		t.Fatalf("cannot create package %s", err)
+	}
+	if bytes.Equal(cd.Id, ocd.Id) {
+		t.Fatalf("different code packages should have different identities")
+	}
+
+	// name and version boundaries are part of the identity
+	ambiguous := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "testcc0", Version: ""}, Input: &pb.ChaincodeInput{Args: [][]byte{[]byte("")}}}, CodePackage: []byte("code")}
+	_, _, acd, err := processCDS(ambiguous, false)
+	if err != nil {
+		t.Fatalf("cannot create package %s", err)
+	}
+	if bytes.Equal(cd.Id, acd.Id) {
+		t.Fatalf("different name/version pairs should have different identities")
+	}
+
+	// tampered records are detected
+	tampered := *cd
+	tampered.Version = "1"
+	if err = ValidateChaincodeIdentity(&tampered); err == nil {
+		t.Fatalf("tampered version should have been detected")
+	}
+
+	tampered = *cd
+	tampered.Id = ocd.Id
+	if err = ValidateChaincodeIdentity(&tampered); err == nil {
+		t.Fatalf("tampered identity should have been detected")
+	}
+
+	if err = ValidateChaincodeIdentity(&ChaincodeData{Name: "testcc", Version: "0"}); err == nil {
+		t.Fatalf("missing package data should have been detected")
+	}
+}
+
+// legacyChaincodeData returns the ChaincodeData recorded for the
+// deployment spec by peers predating the current chaincode identity
+func legacyChaincodeData(t *testing.T, cds *pb.ChaincodeDeploymentSpec, signatureHash []byte) *ChaincodeData {
+	codeHash := sha256.New().Sum(cds.CodePackage)
+	metaDataHash := sha256.Sum256([]byte(cds.ChaincodeSpec.ChaincodeId.Name + cds.ChaincodeSpec.ChaincodeId.Version))
+
+	var data proto.Message = &CDSData{CodeHash: codeHash, MetaDataHash: metaDataHash[:]}
+	if signatureHash != nil {
+		data = &SignedCDSData{CodeHash: codeHash, MetaDataHash: metaDataHash[:], SignatureHash: signatureHash}
+	}
+	b, err := proto.Marshal(data)
+	if err != nil {
+		t.Fatalf("cannot marshal legacy data %s", err)
+	}
+
+	h := sha256.New()
+	h.Write(codeHash)
+	h.Write(metaDataHash[:])
+	h.Write(signatureHash)
+
+	return &ChaincodeData{Name: cds.ChaincodeSpec.ChaincodeId.Name, Version: cds.ChaincodeSpec.ChaincodeId.Version, Data: b, Id: h.Sum(nil)}
+}
+
+func TestLegacyChaincodeIdentity(t *testing.T) {
+	cds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: "testcc", Version: "0"}, Input: &pb.ChaincodeInput{Args: [][]byte{[]byte("")}}}, CodePackage: []byte("code")}
+
+	ccpack, _, cd, err := processCDS(cds, false)
+	if err != nil {
+		t.Fatalf("cannot create package %s", err)
+	}
+	if HasLegacyIdentity(cd) {
+		t.Fatalf("new packages should not record the legacy identity")
+	}
+
+	legacy := legacyChaincodeData(t, cds, nil)
+	if !HasLegacyIdentity(legacy) {
+		t.Fatalf("legacy data should have been detected")
+	}
+	if err = ValidateChaincodeIdentity(legacy); err != nil {
+		t.Fatalf("legacy identity of CDS package should be valid: %s", err)
+	}
+	if err = ccpack.ValidateCC(legacy); err != nil {
+		t.Fatalf("installed package should match chaincode instantiated with the legacy identity: %s", err)
+	}
+
+	sccpack, _, _, err := processSignedCDS(cds, &common.SignaturePolicyEnvelope{Version: 1}, false)
+	if err != nil {
+		t.Fatalf("cannot create signed package %s", err)
+	}
+	slegacy := legacyChaincodeData(t, cds, sccpack.data.SignatureHash)
+	if err = ValidateChaincodeIdentity(slegacy); err != nil {
+		t.Fatalf("legacy identity of signed CDS package should be valid: %s", err)
+	}
+	if err = sccpack.ValidateCC(slegacy); err != nil {
+		t.Fatalf("installed signed package should match chaincode instantiated with the legacy identity: %s", err)
+	}
+
+	// other code does not match the legacy data
+	other := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: cds.ChaincodeSpec, CodePackage: []byte("othercode")}
+	occpack, _, _, err := processCDS(other, false)
+	if err != nil {
+		t.Fatalf("cannot create package %s", err)
+	}
+	if err = occpack.ValidateCC(legacy); err == nil {
+		t.Fatalf("a different package should not match the legacy data")
+	}
+
+	tampered := *legacy
+	tampered.Id = cd.Id
+	if err = ValidateChaincodeIdentity(&tampered); err == nil {
+		t.Fatalf("tampered legacy identity should have been detected")
+	}
+}
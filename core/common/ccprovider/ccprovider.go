@@ -26,6 +26,7 @@ import (
 	"github.com/golang/protobuf/proto"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
@@ -203,6 +204,118 @@ func GetInstalledChaincodes() (*pb.ChaincodeQueryResponse, error) {
 	return cqr, nil
 }
 
+//InstalledChaincodeMetadata describes a chaincode package installed on the
+//peer. This needs to be serialized in lscc responses hence the protobuf format
+type InstalledChaincodeMetadata struct {
+	//Name of the chaincode
+	Name string `protobuf:"bytes,1,opt,name=name"`
+
+	//Version of the chaincode
+	Version string `protobuf:"bytes,2,opt,name=version"`
+
+	//Label identifies the package on the peer, <name>:<version>
+	Label string `protobuf:"bytes,3,opt,name=label"`
+
+	//Path of the chaincode source
+	Path string `protobuf:"bytes,4,opt,name=path"`
+
+	//Platform the chaincode is written for (GOLANG, JAVA, ...)
+	Platform string `protobuf:"bytes,5,opt,name=platform"`
+
+	//PackageType is the format of the package (CDS or SignedCDS)
+	PackageType string `protobuf:"bytes,6,opt,name=package_type"`
+
+	//Id is the fingerprint of the package, as recorded by lscc on instantiation
+	Id []byte `protobuf:"bytes,7,opt,name=id,proto3"`
+
+	//PackageHash is the SHA256 hash of the package bytes as installed
+	PackageHash []byte `protobuf:"bytes,8,opt,name=package_hash,proto3"`
+}
+
+//Reset resets
+func (md *InstalledChaincodeMetadata) Reset() { *md = InstalledChaincodeMetadata{} }
+
+//String convers to string
+func (md *InstalledChaincodeMetadata) String() string { return proto.CompactTextString(md) }
+
+//ProtoMessage just exists to make proto happy
+func (*InstalledChaincodeMetadata) ProtoMessage() {}
+
+//ChaincodeDefinition describes how the transactions of a chaincode
+//instantiated on a channel are endorsed and validated. This needs to be
+//serialized in lscc responses hence the protobuf format
+type ChaincodeDefinition struct {
+	//Name of the chaincode
+	Name string `protobuf:"bytes,1,opt,name=name"`
+
+	//Version of the chaincode
+	Version string `protobuf:"bytes,2,opt,name=version"`
+
+	//Escc endorses the proposals of the chaincode
+	Escc string `protobuf:"bytes,3,opt,name=escc"`
+
+	//Vscc validates the transactions of the chaincode
+	Vscc string `protobuf:"bytes,4,opt,name=vscc"`
+
+	//EndorsementPolicy is the marshaled SignaturePolicyEnvelope the
+	//endorsements of the transactions have to satisfy
+	EndorsementPolicy []byte `protobuf:"bytes,5,opt,name=endorsement_policy,proto3"`
+
+	//EndorsementPolicyExpression is the endorsement policy in the language
+	//of the policy parser, e.g. OR('Org1MSP.member', 'Org2MSP.member'),
+	//or empty if the policy cannot be represented in it
+	EndorsementPolicyExpression string `protobuf:"bytes,6,opt,name=endorsement_policy_expression"`
+}
+
+//Reset resets
+func (def *ChaincodeDefinition) Reset() { *def = ChaincodeDefinition{} }
+
+//String convers to string
+func (def *ChaincodeDefinition) String() string { return proto.CompactTextString(def) }
+
+//ProtoMessage just exists to make proto happy
+func (*ChaincodeDefinition) ProtoMessage() {}
+
+// GetInstalledChaincodeMetadata returns the metadata of the chaincode package
+// installed on the peer with the given name and version
+func GetInstalledChaincodeMetadata(ccname string, ccversion string) (*InstalledChaincodeMetadata, error) {
+	ccbytes, err := GetChaincodePackage(ccname, ccversion)
+	if err != nil {
+		return nil, err
+	}
+
+	ccpack, err := GetCCPackage(ccbytes)
+	if err != nil {
+		return nil, err
+	}
+
+	cds := ccpack.GetDepSpec()
+	if cds.ChaincodeSpec == nil || cds.ChaincodeSpec.ChaincodeId == nil {
+		return nil, fmt.Errorf("invalid chaincode package %s:%s", ccname, ccversion)
+	}
+	if cds.ChaincodeSpec.ChaincodeId.Name != ccname || cds.ChaincodeSpec.ChaincodeId.Version != ccversion {
+		// chaincode name/version in the chaincode file name has been modified
+		// by an external entity
+		return nil, fmt.Errorf("chaincode file's name/version has been modified on the filesystem: %s.%s", ccname, ccversion)
+	}
+
+	packageType := "CDS"
+	if _, ok := ccpack.(*SignedCDSPackage); ok {
+		packageType = "SignedCDS"
+	}
+
+	return &InstalledChaincodeMetadata{
+		Name:        ccname,
+		Version:     ccversion,
+		Label:       ccname + ":" + ccversion,
+		Path:        cds.ChaincodeSpec.ChaincodeId.Path,
+		Platform:    cds.ChaincodeSpec.Type.String(),
+		PackageType: packageType,
+		Id:          ccpack.GetId(),
+		PackageHash: util.ComputeSHA256(ccbytes),
+	}, nil
+}
+
 //CCContext pass this around instead of string of args
 type CCContext struct {
 	//ChainID chain id
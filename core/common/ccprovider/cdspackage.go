@@ -25,8 +25,6 @@ import (
 
 	"bytes"
 
-	"github.com/hyperledger/fabric/bccsp"
-	"github.com/hyperledger/fabric/bccsp/factory"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -41,6 +39,9 @@ type CDSData struct {
 
 	//MetaDataHash hash of Name and Version from ChaincodeDeploymentSpec
 	MetaDataHash []byte `protobuf:"bytes,2,opt,name=metadatahash,proto3"`
+
+	//IdentityVersion format of the hashes, unset for the legacy format
+	IdentityVersion uint32 `protobuf:"varint,4,opt,name=identityversion,proto3"`
 }
 
 //----implement functions needed from proto.Message for proto's mar/unmarshal functions
@@ -56,7 +57,7 @@ func (*CDSData) ProtoMessage() {}
 
 //Equals data equals other
 func (data *CDSData) Equals(other *CDSData) bool {
-	return other != nil && bytes.Equal(data.CodeHash, other.CodeHash) && bytes.Equal(data.MetaDataHash, other.MetaDataHash)
+	return other != nil && bytes.Equal(data.CodeHash, other.CodeHash) && bytes.Equal(data.MetaDataHash, other.MetaDataHash) && data.IdentityVersion == other.IdentityVersion
 }
 
 //--------- CDSPackage ------------
@@ -128,47 +129,30 @@ func (ccpack *CDSPackage) getCDSData(cds *pb.ChaincodeDeploymentSpec) ([]byte, [
 		panic("nil cds")
 	}
 
-	b, err := proto.Marshal(cds)
-	if err != nil {
+	var err error
+	cdsdata := &CDSData{IdentityVersion: currentIdentityVersion}
+
+	//code hash
+	if cdsdata.CodeHash, err = hashCodePackage(cds.CodePackage); err != nil {
 		return nil, nil, nil, err
 	}
 
-	if err = factory.InitFactories(nil); err != nil {
-		return nil, nil, nil, fmt.Errorf("Internal error, BCCSP could not be initialized : %s", err)
+	//metadata hash
+	if cdsdata.MetaDataHash, err = hashMetaData(cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version); err != nil {
+		return nil, nil, nil, err
 	}
 
-	//compute hashes now
-	hash, err := factory.GetDefault().GetHash(&bccsp.SHAOpts{})
+	b, err := proto.Marshal(cdsdata)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	cdsdata := &CDSData{}
-
-	//code hash
-	cdsdata.CodeHash = hash.Sum(cds.CodePackage)
-
-	hash.Reset()
-
-	//metadata hash
-	hash.Write([]byte(cds.ChaincodeSpec.ChaincodeId.Name))
-	hash.Write([]byte(cds.ChaincodeSpec.ChaincodeId.Version))
-
-	cdsdata.MetaDataHash = hash.Sum(nil)
-
-	b, err = proto.Marshal(cdsdata)
+	//compute the id
+	id, err := computeChaincodeIdentity(cdsdata.CodeHash, cdsdata.MetaDataHash)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	hash.Reset()
-
-	//compute the id
-	hash.Write(cdsdata.CodeHash)
-	hash.Write(cdsdata.MetaDataHash)
-
-	id := hash.Sum(nil)
-
 	return b, id, cdsdata, nil
 }
 
@@ -193,7 +177,19 @@ func (ccpack *CDSPackage) ValidateCC(ccdata *ChaincodeData) error {
 		return err
 	}
 
-	if !ccpack.data.Equals(otherdata) {
+	data := ccpack.data
+	if otherdata.IdentityVersion == legacyIdentityVersion {
+		//the chaincode was instantiated before the current identity
+		data = &CDSData{IdentityVersion: legacyIdentityVersion}
+		if data.CodeHash, err = legacyCodeHash(ccpack.depSpec.CodePackage); err != nil {
+			return err
+		}
+		if data.MetaDataHash, err = legacyMetaDataHash(ccpack.depSpec.ChaincodeSpec.ChaincodeId.Name, ccpack.depSpec.ChaincodeSpec.ChaincodeId.Version); err != nil {
+			return err
+		}
+	}
+
+	if !data.Equals(otherdata) {
 		return fmt.Errorf("data mismatch")
 	}
 
@@ -41,6 +41,9 @@ type SignedCDSData struct {
 	CodeHash      []byte `protobuf:"bytes,1,opt,name=hash"`
 	MetaDataHash  []byte `protobuf:"bytes,2,opt,name=metadatahash"`
 	SignatureHash []byte `protobuf:"bytes,3,opt,name=signaturehash"`
+
+	//IdentityVersion format of the hashes, unset for the legacy format
+	IdentityVersion uint32 `protobuf:"varint,4,opt,name=identityversion,proto3"`
 }
 
 //----implement functions needed from proto.Message for proto's mar/unmarshal functions
@@ -59,7 +62,8 @@ func (data *SignedCDSData) Equals(other *SignedCDSData) bool {
 	return other != nil &&
 		bytes.Equal(data.CodeHash, other.CodeHash) &&
 		bytes.Equal(data.MetaDataHash, other.MetaDataHash) &&
-		bytes.Equal(data.SignatureHash, other.SignatureHash)
+		bytes.Equal(data.SignatureHash, other.SignatureHash) &&
+		data.IdentityVersion == other.IdentityVersion
 }
 
 //-------- SignedCDSPackage ---------
@@ -147,36 +151,32 @@ func (ccpack *SignedCDSPackage) getCDSData(scds *pb.SignedChaincodeDeploymentSpe
 		return nil, nil, nil, err
 	}
 
-	if err = factory.InitFactories(nil); err != nil {
-		return nil, nil, nil, fmt.Errorf("Internal error, BCCSP could not be initialized : %s", err)
-	}
+	scdsdata := &SignedCDSData{IdentityVersion: currentIdentityVersion}
 
-	//get the hash object
-	hash, err := factory.GetDefault().GetHash(&bccsp.SHAOpts{})
-	if err != nil {
+	//get the code hash
+	if scdsdata.CodeHash, err = hashCodePackage(cds.CodePackage); err != nil {
 		return nil, nil, nil, err
 	}
 
-	scdsdata := &SignedCDSData{}
-
-	//get the code hash
-	scdsdata.CodeHash = hash.Sum(cds.CodePackage)
-
-	hash.Reset()
-
 	//get the metadata hash
-	hash.Write([]byte(cds.ChaincodeSpec.ChaincodeId.Name))
-	hash.Write([]byte(cds.ChaincodeSpec.ChaincodeId.Version))
-
-	scdsdata.MetaDataHash = hash.Sum(nil)
-
-	hash.Reset()
+	if scdsdata.MetaDataHash, err = hashMetaData(cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version); err != nil {
+		return nil, nil, nil, err
+	}
 
 	//get the signature hashes
 	if scds.InstantiationPolicy == nil {
 		return nil, nil, nil, fmt.Errorf("instantiation policy cannot be nil for chaincode (%s:%s)", cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version)
 	}
 
+	if err = factory.InitFactories(nil); err != nil {
+		return nil, nil, nil, fmt.Errorf("Internal error, BCCSP could not be initialized : %s", err)
+	}
+
+	hash, err := factory.GetDefault().GetHash(&bccsp.SHAOpts{})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	hash.Write(scds.InstantiationPolicy)
 	for _, o := range scds.OwnerEndorsements {
 		hash.Write(o.Endorser)
@@ -189,14 +189,12 @@ func (ccpack *SignedCDSPackage) getCDSData(scds *pb.SignedChaincodeDeploymentSpe
 		return nil, nil, nil, err
 	}
 
-	hash.Reset()
-
-	//compute the id
-	hash.Write(scdsdata.CodeHash)
-	hash.Write(scdsdata.MetaDataHash)
-	hash.Write(scdsdata.SignatureHash)
-
-	id := hash.Sum(nil)
+	//compute the id; the signatures are not part of it so that the
+	//identity does not depend on how the package was distributed
+	id, err := computeChaincodeIdentity(scdsdata.CodeHash, scdsdata.MetaDataHash)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	return b, id, scdsdata, nil
 }
@@ -226,7 +224,22 @@ func (ccpack *SignedCDSPackage) ValidateCC(ccdata *ChaincodeData) error {
 		return err
 	}
 
-	if !ccpack.data.Equals(otherdata) {
+	data := ccpack.data
+	if otherdata.IdentityVersion == legacyIdentityVersion {
+		//the chaincode was instantiated before the current identity; only
+		//the code and metadata hashes were computed differently
+		legacy := *ccpack.data
+		legacy.IdentityVersion = legacyIdentityVersion
+		if legacy.CodeHash, err = legacyCodeHash(ccpack.depSpec.CodePackage); err != nil {
+			return err
+		}
+		if legacy.MetaDataHash, err = legacyMetaDataHash(ccpack.depSpec.ChaincodeSpec.ChaincodeId.Name, ccpack.depSpec.ChaincodeSpec.ChaincodeId.Version); err != nil {
+			return err
+		}
+		data = &legacy
+	}
+
+	if !data.Equals(otherdata) {
 		return fmt.Errorf("data mismatch")
 	}
 
@@ -16,6 +16,10 @@ limitations under the License.
 
 package sysccprovider
 
+import (
+	"github.com/hyperledger/fabric/common/config"
+)
+
 // SystemChaincodeProvider provides an abstraction layer that is
 // used for different packages to interact with code in the
 // system chaincode package without importing it; more methods
@@ -27,6 +31,10 @@ type SystemChaincodeProvider interface {
 	// IsSysCCAndNotInvokableCC2CC returns true if the supplied chaincode
 	// is a system chaincode and is not invokable through a cc2cc invocation
 	IsSysCCAndNotInvokableCC2CC(name string) bool
+
+	// GetApplicationConfig returns the application config of the
+	// supplied channel, and false if the channel does not exist
+	GetApplicationConfig(cid string) (config.Application, bool)
 }
 
 var sccFactory SystemChaincodeProviderFactory
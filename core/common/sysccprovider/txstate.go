@@ -0,0 +1,205 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sysccprovider
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/ledger"
+	"golang.org/x/net/context"
+)
+
+// A system chaincode invoked with a tx simulator, e.g. ESCC by the endorser
+// or VSCC by the committer, reads the state through that simulator rather
+// than through a new query executor: the ledger does not commit a block
+// until the simulators open on it are done, and makes the new ones wait
+// meanwhile, so the invoker holding its simulator would never see a second
+// one opened while a block is committed.
+
+type txStateKey struct {
+	chainID string
+	txID    string
+}
+
+var txStates = struct {
+	sync.Mutex
+	qes map[txStateKey][]ledger.QueryExecutor
+}{qes: make(map[txStateKey][]ledger.QueryExecutor)}
+
+// RegisterTxQueryExecutor makes the query executor the system chaincode is
+// invoked with available to it by channel and transaction ID, until the
+// returned function is called. The values of the state cannot be read through
+// it when the context of the invocation carries an UnsettledState.
+func RegisterTxQueryExecutor(ctxt context.Context, chainID, txID string, qe ledger.QueryExecutor) func() {
+	if unsettled, ok := ctxt.Value(unsettledStateKey).(*UnsettledState); ok {
+		qe = &unsettledQueryExecutor{QueryExecutor: qe, state: unsettled}
+	}
+	key := txStateKey{chainID: chainID, txID: txID}
+	txStates.Lock()
+	txStates.qes[key] = append(txStates.qes[key], qe)
+	txStates.Unlock()
+
+	return func() {
+		txStates.Lock()
+		defer txStates.Unlock()
+		qes := txStates.qes[key]
+		for i := range qes {
+			if qes[i] == qe {
+				qes = append(qes[:i], qes[i+1:]...)
+				break
+			}
+		}
+		if len(qes) == 0 {
+			delete(txStates.qes, key)
+		} else {
+			txStates.qes[key] = qes
+		}
+	}
+}
+
+// GetTxQueryExecutor returns the query executor the system chaincode invoked
+// with the given transaction ID on the channel is invoked with; it remains
+// owned by the invoker, which releases it once the invocation is over
+func GetTxQueryExecutor(chainID, txID string) (ledger.QueryExecutor, error) {
+	txStates.Lock()
+	defer txStates.Unlock()
+	qes := txStates.qes[txStateKey{chainID: chainID, txID: txID}]
+	if len(qes) == 0 {
+		return nil, fmt.Errorf("no state available to transaction %s on channel %s", txID, chainID)
+	}
+	return qes[len(qes)-1], nil
+}
+
+// TxStateFetcher reads the state through the query executor registered for
+// a system chaincode invocation, as long as the invocation lasts
+type TxStateFetcher struct {
+	chainID string
+	txID    string
+}
+
+// NewTxStateFetcher returns a TxStateFetcher for the system chaincode invoked
+// with the given transaction ID on the channel
+func NewTxStateFetcher(chainID, txID string) (*TxStateFetcher, error) {
+	sf := &TxStateFetcher{chainID: chainID, txID: txID}
+	if _, err := sf.queryExecutor(); err != nil {
+		return nil, err
+	}
+	return sf, nil
+}
+
+func (sf *TxStateFetcher) queryExecutor() (ledger.QueryExecutor, error) {
+	return GetTxQueryExecutor(sf.chainID, sf.txID)
+}
+
+// GetState returns the value of the key in the namespace
+func (sf *TxStateFetcher) GetState(namespace string, key string) ([]byte, error) {
+	qe, err := sf.queryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	return qe.GetState(namespace, key)
+}
+
+// GetStateMultipleKeys returns the values of the keys in the namespace
+func (sf *TxStateFetcher) GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error) {
+	qe, err := sf.queryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	return qe.GetStateMultipleKeys(namespace, keys)
+}
+
+// GetStateRangeScanIterator returns an iterator over the key-values of the
+// namespace between startKey (included) and endKey (excluded)
+func (sf *TxStateFetcher) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.ResultsIterator, error) {
+	qe, err := sf.queryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	return qe.GetStateRangeScanIterator(namespace, startKey, endKey)
+}
+
+// GetStateMetadata returns the metadata of the key in the namespace
+func (sf *TxStateFetcher) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	qe, err := sf.queryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	return qe.GetStateMetadata(namespace, key)
+}
+
+// Done does not release the query executor, which belongs to the invoker
+func (sf *TxStateFetcher) Done() {
+}
+
+// ErrStateNotSettled is returned when a system chaincode reads the values of
+// the state while validating a block ahead of the commit of the block before
+var ErrStateNotSettled = errors.New("the state is not settled at the height of the block under validation")
+
+type contextKey string
+
+const unsettledStateKey contextKey = "unsettledstate"
+
+// UnsettledState records whether a system chaincode validating a transaction
+// of a block, while the block before it is committed, read the values of
+// the state. Only the metadata of the keys is settled then: the block before
+// may or may not have reached the state, but does not write metadata.
+type UnsettledState struct {
+	valuesRead int32
+}
+
+// WithUnsettledState returns a context invoking system chaincodes whose reads
+// of the values of the state fail with ErrStateNotSettled, recorded in state
+func WithUnsettledState(ctxt context.Context, state *UnsettledState) context.Context {
+	return context.WithValue(ctxt, unsettledStateKey, state)
+}
+
+// ValuesRead returns whether the values of the state were read
+func (s *UnsettledState) ValuesRead() bool {
+	return atomic.LoadInt32(&s.valuesRead) != 0
+}
+
+func (s *UnsettledState) readValues() error {
+	atomic.StoreInt32(&s.valuesRead, 1)
+	return ErrStateNotSettled
+}
+
+// unsettledQueryExecutor only reads the metadata of the keys
+type unsettledQueryExecutor struct {
+	ledger.QueryExecutor
+	state *UnsettledState
+}
+
+func (qe *unsettledQueryExecutor) GetState(namespace string, key string) ([]byte, error) {
+	return nil, qe.state.readValues()
+}
+
+func (qe *unsettledQueryExecutor) GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error) {
+	return nil, qe.state.readValues()
+}
+
+func (qe *unsettledQueryExecutor) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.ResultsIterator, error) {
+	return nil, qe.state.readValues()
+}
+
+func (qe *unsettledQueryExecutor) ExecuteQuery(namespace, query string) (commonledger.ResultsIterator, error) {
+	return nil, qe.state.readValues()
+}
@@ -67,13 +67,8 @@ func ValidateProposalMessage(signedProp *pb.SignedProposal) (*pb.Proposal, *comm
 	putilsLogger.Debugf("ValidateProposalMessage starts for signed proposal %p", signedProp)
 
 	// extract the Proposal message from signedProp
-	prop, err := utils.GetProposal(signedProp.ProposalBytes)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-
-	// 1) look at the ProposalHeader
-	hdr, err := utils.GetHeader(prop.Header)
+	// and 1) look at the ProposalHeader
+	prop, hdr, err := CheckSignedProposal(signedProp)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -319,6 +314,11 @@ func validateEndorserTransaction(data []byte, hdr *common.Header) error {
 			return err
 		}
 
+		// check for nil argument
+		if ccActionPayload.Action == nil {
+			return errors.New("Nil endorsed action")
+		}
+
 		// extract the proposal response payload
 		prp, err := utils.GetProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
 		if err != nil {
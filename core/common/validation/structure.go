@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+const (
+	// MaxMessageBytes is the maximum size of a serialized envelope or
+	// proposal. It is above the default absolute maximum size of a batch
+	// of the orderer, which further limits the size of the transactions
+	MaxMessageBytes = 100 * 1024 * 1024
+
+	// MaxHeaderBytes is the maximum size of a serialized channel header or
+	// signature header. Signature headers embed the certificate of their
+	// creator, which is far smaller
+	MaxHeaderBytes = 1024 * 1024
+)
+
+// CheckEnvelope checks the structure of an envelope before it is processed:
+// its size, the presence of a payload header, a channel header with a
+// known type and a valid epoch, and a well formed signature header when there
+// is one. It returns the payload and channel header it unmarshaled, so that
+// malformed envelopes are rejected here rather than panicking further down.
+func CheckEnvelope(env *common.Envelope) (*common.Payload, *common.ChannelHeader, error) {
+	if env == nil {
+		return nil, nil, errors.New("Nil envelope")
+	}
+	if len(env.Payload) > MaxMessageBytes {
+		return nil, nil, fmt.Errorf("Envelope payload of %d bytes exceeds the maximum of %d bytes", len(env.Payload), MaxMessageBytes)
+	}
+
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chdr, err := checkHeader(payload.Header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return payload, chdr, nil
+}
+
+// CheckSignedProposal checks the structure of a signed proposal before it is
+// processed: its size, the presence of a header, a channel header with a
+// known type and a valid epoch, and a well formed signature header when there
+// is one. It returns the proposal and header it unmarshaled.
+func CheckSignedProposal(signedProp *pb.SignedProposal) (*pb.Proposal, *common.Header, error) {
+	if signedProp == nil {
+		return nil, nil, errors.New("Nil signed proposal")
+	}
+	if len(signedProp.ProposalBytes) > MaxMessageBytes {
+		return nil, nil, fmt.Errorf("Proposal of %d bytes exceeds the maximum of %d bytes", len(signedProp.ProposalBytes), MaxMessageBytes)
+	}
+
+	prop, err := utils.GetProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err = checkHeader(hdr); err != nil {
+		return nil, nil, err
+	}
+
+	return prop, hdr, nil
+}
+
+// checkHeader checks the structure of a header, whatever the type of the
+// message it belongs to
+func checkHeader(hdr *common.Header) (*common.ChannelHeader, error) {
+	if hdr == nil {
+		return nil, errors.New("Nil header")
+	}
+	if len(hdr.ChannelHeader) > MaxHeaderBytes {
+		return nil, fmt.Errorf("Channel header of %d bytes exceeds the maximum of %d bytes", len(hdr.ChannelHeader), MaxHeaderBytes)
+	}
+	if len(hdr.SignatureHeader) > MaxHeaderBytes {
+		return nil, fmt.Errorf("Signature header of %d bytes exceeds the maximum of %d bytes", len(hdr.SignatureHeader), MaxHeaderBytes)
+	}
+
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := common.HeaderType_name[chdr.Type]; !ok {
+		return nil, fmt.Errorf("Unknown header type %d", chdr.Type)
+	}
+
+	// Currently we enforce that Epoch is 0, see validateChannelHeader
+	if chdr.Epoch != 0 {
+		return nil, fmt.Errorf("Invalid Epoch in ChannelHeader. It must be 0. It was [%d]", chdr.Epoch)
+	}
+
+	if len(hdr.SignatureHeader) != 0 {
+		if _, err = utils.GetSignatureHeader(hdr.SignatureHeader); err != nil {
+			return nil, err
+		}
+	}
+
+	return chdr, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+func envelopeWithHeader(hdr *common.Header) *common.Envelope {
+	return &common.Envelope{Payload: utils.MarshalOrPanic(&common.Payload{Header: hdr, Data: []byte("data")})}
+}
+
+func headerWith(chdr *common.ChannelHeader, shdr []byte) *common.Header {
+	return &common.Header{ChannelHeader: utils.MarshalOrPanic(chdr), SignatureHeader: shdr}
+}
+
+func TestCheckEnvelope(t *testing.T) {
+	chdr := &common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION), ChannelId: "mychannel"}
+	shdr := utils.MarshalOrPanic(&common.SignatureHeader{Creator: []byte("creator"), Nonce: []byte("nonce")})
+
+	payload, cHdr, err := CheckEnvelope(envelopeWithHeader(headerWith(chdr, shdr)))
+	if err != nil {
+		t.Fatalf("CheckEnvelope failed on a well formed envelope: %s", err)
+	}
+	if cHdr.ChannelId != "mychannel" || string(payload.Data) != "data" {
+		t.Fatalf("Unexpected payload %v and channel header %v", payload, cHdr)
+	}
+
+	for name, env := range map[string]*common.Envelope{
+		"nil envelope":        nil,
+		"garbage payload":     {Payload: []byte("garbage")},
+		"oversized payload":   {Payload: make([]byte, MaxMessageBytes+1)},
+		"nil header":          envelopeWithHeader(nil),
+		"garbage chdr":        envelopeWithHeader(&common.Header{ChannelHeader: []byte("garbage")}),
+		"oversized chdr":      envelopeWithHeader(&common.Header{ChannelHeader: make([]byte, MaxHeaderBytes+1)}),
+		"oversized shdr":      envelopeWithHeader(headerWith(chdr, make([]byte, MaxHeaderBytes+1))),
+		"garbage shdr":        envelopeWithHeader(headerWith(chdr, []byte("garbage"))),
+		"unknown header type": envelopeWithHeader(headerWith(&common.ChannelHeader{Type: 1000}, shdr)),
+		"non zero epoch":      envelopeWithHeader(headerWith(&common.ChannelHeader{Epoch: 1}, shdr)),
+	} {
+		if _, _, err := CheckEnvelope(env); err == nil {
+			t.Fatalf("CheckEnvelope should have failed on %s", name)
+		}
+	}
+}
+
+func TestCheckSignedProposal(t *testing.T) {
+	chdr := &common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION), ChannelId: "mychannel"}
+	prop := &pb.Proposal{Header: utils.MarshalOrPanic(headerWith(chdr, nil))}
+
+	if _, hdr, err := CheckSignedProposal(&pb.SignedProposal{ProposalBytes: utils.MarshalOrPanic(prop)}); err != nil || hdr == nil {
+		t.Fatalf("CheckSignedProposal failed on a well formed proposal: %v", err)
+	}
+
+	for name, signedProp := range map[string]*pb.SignedProposal{
+		"nil signed proposal": nil,
+		"garbage proposal":    {ProposalBytes: []byte("garbage")},
+		"oversized proposal":  {ProposalBytes: make([]byte, MaxMessageBytes+1)},
+		"garbage header":      {ProposalBytes: utils.MarshalOrPanic(&pb.Proposal{Header: []byte("garbage")})},
+		"non zero epoch":      {ProposalBytes: utils.MarshalOrPanic(&pb.Proposal{Header: utils.MarshalOrPanic(headerWith(&common.ChannelHeader{Epoch: 1}, nil))})},
+	} {
+		if _, _, err := CheckSignedProposal(signedProp); err == nil {
+			t.Fatalf("CheckSignedProposal should have failed on %s", name)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// CheckTimestamp checks that the timestamp of a channel header is within
+// skew of now, to reject stale or replayed messages and the ones of clients
+// with a misconfigured clock. A skew of 0 disables the check.
+func CheckTimestamp(chdr *common.ChannelHeader, now time.Time, skew time.Duration) error {
+	if skew <= 0 {
+		return nil
+	}
+	if chdr.Timestamp == nil {
+		return errors.New("Missing timestamp in ChannelHeader")
+	}
+
+	ts := time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos)).UTC()
+	switch {
+	case ts.Before(now.Add(-skew)):
+		return fmt.Errorf("Timestamp %s of transaction %s is %s in the past, beyond the tolerated clock skew of %s",
+			ts.Format(time.RFC3339), chdr.TxId, now.Sub(ts), skew)
+	case ts.After(now.Add(skew)):
+		return fmt.Errorf("Timestamp %s of transaction %s is %s in the future, beyond the tolerated clock skew of %s",
+			ts.Format(time.RFC3339), chdr.TxId, ts.Sub(now), skew)
+	}
+	return nil
+}
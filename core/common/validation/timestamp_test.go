@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTimestamp(t *testing.T) {
+	now := time.Now()
+	header := func(ts time.Time) *common.ChannelHeader {
+		return &common.ChannelHeader{TxId: "tx", Timestamp: &timestamp.Timestamp{Seconds: ts.Unix(), Nanos: int32(ts.Nanosecond())}}
+	}
+
+	assert.NoError(t, CheckTimestamp(header(now), now, time.Minute))
+	assert.NoError(t, CheckTimestamp(header(now.Add(-59*time.Second)), now, time.Minute))
+	assert.NoError(t, CheckTimestamp(header(now.Add(59*time.Second)), now, time.Minute))
+
+	err := CheckTimestamp(header(now.Add(-2*time.Minute)), now, time.Minute)
+	if assert.Error(t, err, "Should have rejected a stale timestamp") {
+		assert.Contains(t, err.Error(), "in the past")
+	}
+	err = CheckTimestamp(header(now.Add(2*time.Minute)), now, time.Minute)
+	if assert.Error(t, err, "Should have rejected a timestamp in the future") {
+		assert.Contains(t, err.Error(), "in the future")
+	}
+	assert.Error(t, CheckTimestamp(&common.ChannelHeader{}, now, time.Minute), "Should have rejected a missing timestamp")
+
+	assert.NoError(t, CheckTimestamp(header(now.Add(-time.Hour)), now, 0), "Should not check the timestamp without skew")
+	assert.NoError(t, CheckTimestamp(&common.ChannelHeader{}, now, 0))
+}
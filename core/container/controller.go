@@ -224,6 +224,13 @@ func (di DestroyImageReq) getCCID() ccintf.CCID {
 	return di.CCID
 }
 
+// ReapOrphans removes the leftovers of the docker chaincode containers of the
+// candidate chaincodes, keeping the images of the given chaincodes. It should be
+// invoked before chaincodes are launched
+func ReapOrphans(ctxt context.Context, candidates []ccintf.CCID, keep []ccintf.CCID) error {
+	return (&dockercontroller.DockerVM{}).ReapOrphans(ctxt, candidates, keep)
+}
+
 //VMCProcess should be used as follows
 //   . construct a context
 //   . construct req of the right type (e.g., CreateImageReq)
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"time"
 
@@ -69,6 +70,12 @@ func getDockerHostConfig() *docker.HostConfig {
 	if err != nil {
 		dockerLogger.Warningf("load docker HostConfig.LogConfig failed, error: %s", err.Error())
 	}
+	// UnmarshalKey ignores the environment, the logging driver and the values
+	// of its options set in the configuration can be overridden there
+	logConfig.Type = viper.GetString(dockerKey("LogConfig.Type"))
+	for option := range logConfig.Config {
+		logConfig.Config[option] = viper.GetString(dockerKey("LogConfig.Config." + option))
+	}
 	networkMode := viper.GetString(dockerKey("NetworkMode"))
 	if networkMode == "" {
 		networkMode = "host"
@@ -255,9 +262,8 @@ func (vm *DockerVM) Start(ctxt context.Context, ccid ccintf.CCID, args []string,
 			// ingestion of the IO, one log entry per line
 			is := bufio.NewReader(r)
 
-			// Acquire a custom logger for our chaincode, inheriting the level from the peer
-			containerLogger := flogging.MustGetLogger(containerID)
-			logging.SetLevel(logging.GetLevel("peer"), containerID)
+			writeLine, closeOutput := containerOutput(ccid, containerID)
+			defer closeOutput()
 
 			for {
 				// Loop forever dumping lines of text into the containerLogger
@@ -274,7 +280,7 @@ func (vm *DockerVM) Start(ctxt context.Context, ccid ccintf.CCID, args []string,
 					return
 				}
 
-				containerLogger.Info(line)
+				writeLine(line)
 			}
 		}()
 	}
@@ -290,6 +296,33 @@ func (vm *DockerVM) Start(ctxt context.Context, ccid ccintf.CCID, args []string,
 	return nil
 }
 
+// containerOutput returns the function writing a line of output of a chaincode
+// container, and the one to invoke once the container exited. The lines are
+// appended to the file configured by vm.docker.logFile, or logged by the peer
+// under the module ccs.<chaincode name>, which inherits the level of the peer.
+func containerOutput(ccid ccintf.CCID, containerID string) (func(line string), func()) {
+	if logFile := viper.GetString("vm.docker.logFile"); logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err == nil {
+			writeLine := func(line string) {
+				if _, err := fmt.Fprintf(f, "[%s] %s\n", containerID, strings.TrimRight(line, "\n")); err != nil {
+					dockerLogger.Errorf("Error writing the output of container %s to %s: %s", containerID, logFile, err)
+				}
+			}
+			return writeLine, func() { f.Close() }
+		}
+		dockerLogger.Errorf("Error opening %s, logging the output of container %s instead: %s", logFile, containerID, err)
+	}
+
+	module := "ccs." + ccid.GetName()
+	containerLogger := flogging.MustGetLogger(module)
+	logging.SetLevel(logging.GetLevel("peer"), module)
+	writeLine := func(line string) {
+		containerLogger.Info(strings.TrimRight(line, "\n"))
+	}
+	return writeLine, func() {}
+}
+
 //Stop stops a running chaincode
 func (vm *DockerVM) Stop(ctxt context.Context, ccid ccintf.CCID, timeout uint, dontkill bool, dontremove bool) error {
 	id, err := vm.GetVMName(ccid)
@@ -358,6 +391,105 @@ func (vm *DockerVM) Destroy(ctxt context.Context, ccid ccintf.CCID, force bool,
 	return err
 }
 
+// ReapOrphans removes the chaincode containers of the peer which are not
+// running, like the ones left behind when the peer crashed, and the images of
+// the chaincodes of the peer which are not among the chaincodes to keep. The
+// containers and images of the peer are recognized by their exact names, as
+// given by GetVMName to the candidate chaincodes, since the names of other
+// peers may start like them. Images still in use are left alone.
+func (vm *DockerVM) ReapOrphans(ctxt context.Context, candidates []ccintf.CCID, keep []ccintf.CCID) error {
+	names, err := vm.vmNames(candidates)
+	if err != nil {
+		return err
+	}
+	kept, err := vm.vmNames(keep)
+	if err != nil {
+		return err
+	}
+	for name := range kept {
+		names[name] = true
+	}
+	if len(names) == 0 {
+		dockerLogger.Debug("No chaincode the peer may have left containers or images of")
+		return nil
+	}
+
+	client, err := cutil.NewDockerClient()
+	if err != nil {
+		return fmt.Errorf("Error creating docker client: %s", err)
+	}
+	containers, err := client.ListContainers(docker.ListContainersOptions{All: true})
+	if err != nil {
+		return fmt.Errorf("Error listing containers: %s", err)
+	}
+	images, err := client.ListImages(docker.ListImagesOptions{})
+	if err != nil {
+		return fmt.Errorf("Error listing images: %s", err)
+	}
+
+	containerIDs, imageNames := orphans(names, kept, containers, images)
+	for _, id := range containerIDs {
+		if err := client.RemoveContainer(docker.RemoveContainerOptions{ID: id, Force: true}); err != nil {
+			dockerLogger.Warningf("Failed removing orphan container %s: %s", id, err)
+			continue
+		}
+		dockerLogger.Infof("Removed orphan container %s", id)
+	}
+	for _, name := range imageNames {
+		if err := client.RemoveImage(name); err != nil {
+			dockerLogger.Debugf("Failed removing orphan image %s: %s", name, err)
+			continue
+		}
+		dockerLogger.Infof("Removed orphan image %s", name)
+	}
+	return nil
+}
+
+// vmNames returns the set of the names GetVMName gives to the chaincodes
+func (vm *DockerVM) vmNames(ccids []ccintf.CCID) (map[string]bool, error) {
+	names := make(map[string]bool)
+	for _, ccid := range ccids {
+		name, err := vm.GetVMName(ccid)
+		if err != nil {
+			return nil, err
+		}
+		names[name] = true
+	}
+	return names, nil
+}
+
+// orphans returns the IDs of the containers and the names of the images to
+// remove among the listed ones: the containers of the peer which are not
+// running, and the images of the peer which are not kept
+func orphans(names map[string]bool, kept map[string]bool, containers []docker.APIContainers, images []docker.APIImages) ([]string, []string) {
+	var containerIDs []string
+	for _, c := range containers {
+		if c.State == "running" {
+			continue
+		}
+		for _, name := range c.Names {
+			if names[strings.TrimPrefix(name, "/")] {
+				containerIDs = append(containerIDs, c.ID)
+				break
+			}
+		}
+	}
+
+	var imageNames []string
+	for _, img := range images {
+		for _, tag := range img.RepoTags {
+			name := tag
+			if i := strings.LastIndex(tag, ":"); i >= 0 {
+				name = tag[:i]
+			}
+			if names[name] && !kept[name] {
+				imageNames = append(imageNames, tag)
+			}
+		}
+	}
+	return containerIDs, imageNames
+}
+
 //GetVMName generates the docker image from peer information given the hashcode. This is needed to
 //keep image name's unique in a single host, multi-peer environment (such as a development environment)
 func (vm *DockerVM) GetVMName(ccid ccintf.CCID) (string, error) {
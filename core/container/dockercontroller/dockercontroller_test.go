@@ -18,14 +18,18 @@ package dockercontroller
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/fsouza/go-dockerclient"
 	"github.com/spf13/viper"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/container/ccintf"
 	coreutil "github.com/hyperledger/fabric/core/testutil"
+	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
 func TestHostConfig(t *testing.T) {
@@ -54,3 +58,72 @@ func TestGetDockerHostConfig(t *testing.T) {
 	testutil.AssertEquals(t, hostConfig.Memory, int64(1024*1024*1024*2))
 	testutil.AssertEquals(t, hostConfig.CPUShares, int64(1024*1024*1024*2))
 }
+
+func TestOrphans(t *testing.T) {
+	vm := &DockerVM{}
+	ccid := ccintf.CCID{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}}, NetworkID: "dev", PeerID: "Peer0", Version: "1.0"}
+	kept, err := vm.GetVMName(ccid)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, kept, "dev-peer0-mycc-1.0")
+	names, err := vm.vmNames([]ccintf.CCID{
+		ccid,
+		{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}}, NetworkID: "dev", PeerID: "Peer0", Version: "0.9"},
+		{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "oldcc"}}, NetworkID: "dev", PeerID: "Peer0", Version: "1.0"},
+	})
+	testutil.AssertNoError(t, err, "")
+
+	containers := []docker.APIContainers{
+		{ID: "1", Names: []string{"/dev-peer0-mycc-1.0"}, State: "exited"},
+		{ID: "2", Names: []string{"/dev-peer0-mycc-0.9"}, State: "running"},
+		{ID: "3", Names: []string{"/dev-peer1-mycc-1.0"}, State: "exited"},
+		{ID: "4", Names: []string{"/dev-peer0-oldcc-1.0"}, State: "created"},
+		// the chaincode mycc of the peer peer0-org1
+		{ID: "5", Names: []string{"/dev-peer0-org1-mycc-1.0"}, State: "exited"},
+	}
+	images := []docker.APIImages{
+		{ID: "a", RepoTags: []string{"dev-peer0-mycc-1.0:latest"}},
+		{ID: "b", RepoTags: []string{"dev-peer0-oldcc-1.0:latest"}},
+		{ID: "c", RepoTags: []string{"dev-peer1-oldcc-1.0:latest"}},
+		{ID: "d", RepoTags: []string{"hyperledger/fabric-ccenv:latest"}},
+		{ID: "e", RepoTags: []string{"dev-peer0-org1-oldcc-1.0:latest"}},
+	}
+	containerIDs, imageNames := orphans(names, map[string]bool{kept: true}, containers, images)
+	testutil.AssertEquals(t, containerIDs, []string{"1", "4"})
+	testutil.AssertEquals(t, imageNames, []string{"dev-peer0-oldcc-1.0:latest"})
+}
+
+func TestGetDockerHostConfigFromEnv(t *testing.T) {
+	defer func() { hostConfig = nil }()
+	hostConfig = nil
+	os.Setenv("HYPERLEDGER_VM_DOCKER_HOSTCONFIG_LOGCONFIG_TYPE", "syslog")
+	os.Setenv("HYPERLEDGER_VM_DOCKER_HOSTCONFIG_LOGCONFIG_CONFIG_MAX-FILE", "3")
+	os.Setenv("HYPERLEDGER_VM_DOCKER_HOSTCONFIG_EXTRAHOSTS", "peer0:10.0.0.2 orderer0:10.0.0.3")
+	defer os.Unsetenv("HYPERLEDGER_VM_DOCKER_HOSTCONFIG_LOGCONFIG_TYPE")
+	defer os.Unsetenv("HYPERLEDGER_VM_DOCKER_HOSTCONFIG_LOGCONFIG_CONFIG_MAX-FILE")
+	defer os.Unsetenv("HYPERLEDGER_VM_DOCKER_HOSTCONFIG_EXTRAHOSTS")
+	coreutil.SetupTestConfig()
+	hostConfig := getDockerHostConfig()
+	testutil.AssertEquals(t, hostConfig.LogConfig.Type, "syslog")
+	testutil.AssertEquals(t, hostConfig.LogConfig.Config["max-size"], "50m")
+	testutil.AssertEquals(t, hostConfig.LogConfig.Config["max-file"], "3")
+	testutil.AssertEquals(t, hostConfig.ExtraHosts, []string{"peer0:10.0.0.2", "orderer0:10.0.0.3"})
+}
+
+func TestContainerOutputToFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ccoutput")
+	testutil.AssertNoError(t, err, "")
+	defer os.RemoveAll(dir)
+	logFile := filepath.Join(dir, "chaincodes.log")
+	viper.Set("vm.docker.logFile", logFile)
+	defer viper.Set("vm.docker.logFile", "")
+
+	ccid := ccintf.CCID{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}}, Version: "1.0"}
+	writeLine, closeOutput := containerOutput(ccid, "dev-peer0-mycc-1.0")
+	writeLine("hello\n")
+	writeLine("world\n")
+	closeOutput()
+
+	output, err := ioutil.ReadFile(logFile)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, string(output), "[dev-peer0-mycc-1.0] hello\n[dev-peer0-mycc-1.0] world\n")
+}
@@ -153,6 +153,11 @@ func (cp *connProducer) UpdateEndpoints(endpoints []string) {
 	panic("Not implemented")
 }
 
+// UpdatePreferredEndpoints sets the endpoints to be favored when connecting
+func (cp *connProducer) UpdatePreferredEndpoints(endpoints []string) {
+	panic("Not implemented")
+}
+
 func TestOrderingServiceConnFailure(t *testing.T) {
 	testOrderingServiceConnFailure(t, blockDelivererConsumerWithRecv)
 	testOrderingServiceConnFailure(t, blockDelivererConsumerWithSend)
@@ -40,6 +40,7 @@ func init() {
 
 var (
 	reConnectTotalTimeThreshold = time.Second * 60 * 5
+	reConnectBackoffThreshold   = time.Second * 30
 	connTimeout                 = time.Second * 3
 )
 
@@ -54,6 +55,15 @@ type DeliverService interface {
 	// to channel peers.
 	StopDeliverForChannel(chainID string) error
 
+	// UpdateEndpoints updates the ordering service endpoints blocks of the
+	// channel are delivered from, e.g. following a channel config update.
+	UpdateEndpoints(chainID string, endpoints []string) error
+
+	// UpdatePreferredEndpoints updates the ordering service endpoints of the
+	// channel to connect to before the others. When empty, the preferred
+	// endpoints of the delivery service configuration are used instead.
+	UpdatePreferredEndpoints(chainID string, endpoints []string) error
+
 	// Stop terminates delivery service and closes the connection
 	Stop()
 }
@@ -64,6 +74,9 @@ type DeliverService interface {
 type deliverServiceImpl struct {
 	conf           *Config
 	blockProviders map[string]blocksprovider.BlocksProvider
+	connProducers  map[string]comm.ConnectionProducer
+	chainEndpoints map[string][]string
+	chainPreferred map[string][]string
 	lock           sync.RWMutex
 	stopping       bool
 }
@@ -83,8 +96,13 @@ type Config struct {
 	// Gossip enables to enumerate peers in the channel, send a message to peers,
 	// and add a block to the gossip state transfer layer
 	Gossip blocksprovider.GossipServiceAdapter
-	// Endpoints specifies the endpoints of the ordering service
+	// Endpoints specifies the endpoints of the ordering service,
+	// used for channels whose endpoints haven't been updated
 	Endpoints []string
+	// PreferredEndpoints specifies the endpoints of the ordering service
+	// to connect to before the others, used for channels whose config
+	// doesn't declare endpoints for the peer's organization
+	PreferredEndpoints []string
 }
 
 // NewDeliverService construction function to create and initialize
@@ -95,6 +113,9 @@ func NewDeliverService(conf *Config) (DeliverService, error) {
 	ds := &deliverServiceImpl{
 		conf:           conf,
 		blockProviders: make(map[string]blocksprovider.BlocksProvider),
+		connProducers:  make(map[string]comm.ConnectionProducer),
+		chainEndpoints: make(map[string][]string),
+		chainPreferred: make(map[string][]string),
 	}
 	if err := ds.validateConfiguration(); err != nil {
 		return nil, err
@@ -159,6 +180,7 @@ func (d *deliverServiceImpl) StopDeliverForChannel(chainID string) error {
 	if client, exist := d.blockProviders[chainID]; exist {
 		client.Stop()
 		delete(d.blockProviders, chainID)
+		delete(d.connProducers, chainID)
 		logger.Debug("This peer will stop pass blocks from orderer service to other peers")
 	} else {
 		errMsg := fmt.Sprintf("Delivery service - no block provider for %s found, can't stop delivery", chainID)
@@ -168,6 +190,49 @@ func (d *deliverServiceImpl) StopDeliverForChannel(chainID string) error {
 	return nil
 }
 
+// UpdateEndpoints updates the ordering service endpoints of the channel.
+// The connection in use is kept, the new endpoints are used from the next
+// time the blocks provider of the channel reconnects.
+func (d *deliverServiceImpl) UpdateEndpoints(chainID string, endpoints []string) error {
+	if len(endpoints) == 0 {
+		return errors.New("No endpoints specified")
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	logger.Debugf("Updating ordering service endpoints of channel %s to %v", chainID, endpoints)
+	d.chainEndpoints[chainID] = endpoints
+	if connProd, exist := d.connProducers[chainID]; exist {
+		connProd.UpdateEndpoints(endpoints)
+	}
+	return nil
+}
+
+// UpdatePreferredEndpoints updates the ordering service endpoints of the
+// channel to connect to before the others.
+func (d *deliverServiceImpl) UpdatePreferredEndpoints(chainID string, endpoints []string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	logger.Debugf("Updating preferred ordering service endpoints of channel %s to %v", chainID, endpoints)
+	if len(endpoints) == 0 {
+		delete(d.chainPreferred, chainID)
+	} else {
+		d.chainPreferred[chainID] = endpoints
+	}
+	if connProd, exist := d.connProducers[chainID]; exist {
+		connProd.UpdatePreferredEndpoints(d.preferredEndpoints(chainID))
+	}
+	return nil
+}
+
+// preferredEndpoints returns the preferred endpoints of the channel,
+// falling back to the ones of the configuration
+func (d *deliverServiceImpl) preferredEndpoints(chainID string) []string {
+	if endpoints, exist := d.chainPreferred[chainID]; exist {
+		return endpoints
+	}
+	return d.conf.PreferredEndpoints
+}
+
 // Stop all service and release resources
 func (d *deliverServiceImpl) Stop() {
 	d.lock.Lock()
@@ -191,9 +256,19 @@ func (d *deliverServiceImpl) newClient(chainID string, ledgerInfoProvider blocks
 		if elapsedTime.Nanoseconds() > reConnectTotalTimeThreshold.Nanoseconds() {
 			return 0, false
 		}
-		return time.Duration(math.Pow(2, float64(attemptNum))) * time.Millisecond * 500, true
+		backoff := time.Duration(math.Pow(2, float64(attemptNum))) * time.Millisecond * 500
+		if backoff > reConnectBackoffThreshold {
+			backoff = reConnectBackoffThreshold
+		}
+		return backoff, true
+	}
+	endpoints, exist := d.chainEndpoints[chainID]
+	if !exist {
+		endpoints = d.conf.Endpoints
 	}
-	connProd := comm.NewConnectionProducer(d.conf.ConnFactory, d.conf.Endpoints)
+	connProd := comm.NewConnectionProducer(d.conf.ConnFactory, endpoints)
+	connProd.UpdatePreferredEndpoints(d.preferredEndpoints(chainID))
+	d.connProducers[chainID] = connProd
 	bClient := NewBroadcastClient(connProd, d.conf.ABCFactory, broadcastSetup, backoffPolicy)
 	requester.client = bClient
 	return bClient
@@ -287,6 +287,67 @@ func TestDeliverServiceShutdown(t *testing.T) {
 	time.Sleep(time.Second)
 }
 
+func TestDeliverServiceUpdateEndpoints(t *testing.T) {
+	defer ensureNoGoroutineLeak(t)()
+	// Scenario: Launch an ordering service node at an endpoint that is not part of the
+	// initial configuration of the delivery service, and update the endpoints of the channel
+	// before starting delivery. The client should pull blocks from the updated endpoint.
+	os := mocks.NewOrderer(5615, t)
+
+	time.Sleep(time.Second)
+	gossipServiceAdapter := &mocks.MockGossipServiceAdapter{GossipBlockDisseminations: make(chan uint64)}
+
+	service, err := NewDeliverService(&Config{
+		Endpoints:   []string{"localhost:5616"},
+		Gossip:      gossipServiceAdapter,
+		CryptoSvc:   &mockMCS{},
+		ABCFactory:  DefaultABCFactory,
+		ConnFactory: DefaultConnectionFactory,
+	})
+	assert.NoError(t, err)
+	assert.Error(t, service.UpdateEndpoints("TEST_CHAINID", []string{}))
+	assert.NoError(t, service.UpdateEndpoints("TEST_CHAINID", []string{"localhost:5615"}))
+
+	li := &mocks.MockLedgerInfo{Height: uint64(100)}
+	os.SetNextExpectedSeek(uint64(100))
+	err = service.StartDeliverForChannel("TEST_CHAINID", li)
+	assert.NoError(t, err, "can't start delivery")
+
+	go os.SendBlock(uint64(100))
+	assertBlockDissemination(100, gossipServiceAdapter.GossipBlockDisseminations, t)
+	// Updating the endpoints of a channel being delivered keeps the current connection
+	assert.NoError(t, service.UpdateEndpoints("TEST_CHAINID", []string{"localhost:5615", "localhost:5616"}))
+	atomic.StoreUint64(&li.Height, uint64(101))
+	go os.SendBlock(uint64(101))
+	assertBlockDissemination(101, gossipServiceAdapter.GossipBlockDisseminations, t)
+	service.Stop()
+	os.Shutdown()
+	time.Sleep(time.Second)
+}
+
+func TestDeliverServiceUpdatePreferredEndpoints(t *testing.T) {
+	// Scenario: The preferred endpoints of a channel are the ones of its config,
+	// and the ones of the delivery service configuration when it declares none.
+	service, err := NewDeliverService(&Config{
+		Endpoints:          []string{"localhost:5615", "localhost:5616"},
+		PreferredEndpoints: []string{"localhost:5615"},
+		Gossip:             &mocks.MockGossipServiceAdapter{GossipBlockDisseminations: make(chan uint64)},
+		CryptoSvc:          &mockMCS{},
+		ABCFactory:         DefaultABCFactory,
+		ConnFactory:        DefaultConnectionFactory,
+	})
+	assert.NoError(t, err)
+	ds := service.(*deliverServiceImpl)
+	assert.Equal(t, []string{"localhost:5615"}, ds.preferredEndpoints("TEST_CHAINID"))
+
+	assert.NoError(t, service.UpdatePreferredEndpoints("TEST_CHAINID", []string{"localhost:5616"}))
+	assert.Equal(t, []string{"localhost:5616"}, ds.preferredEndpoints("TEST_CHAINID"))
+	assert.Equal(t, []string{"localhost:5615"}, ds.preferredEndpoints("OTHER_CHAINID"))
+
+	assert.NoError(t, service.UpdatePreferredEndpoints("TEST_CHAINID", nil))
+	assert.Equal(t, []string{"localhost:5615"}, ds.preferredEndpoints("TEST_CHAINID"))
+}
+
 func TestDeliverServiceBadConfig(t *testing.T) {
 	// Empty endpoints
 	service, err := NewDeliverService(&Config{
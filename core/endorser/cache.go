@@ -0,0 +1,136 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endorser
+
+import (
+	"expvar"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/viper"
+)
+
+// cacheStats counts the hits and misses of the cache of the simulations of
+// query-only proposals, published on /debug/vars of the profiling server
+var cacheStats = expvar.NewMap("endorser_cache")
+
+// cachedSimulation is the outcome of the simulation of a query-only
+// proposal, along with the height of the ledger it was simulated at
+type cachedSimulation struct {
+	height  uint64
+	cd      *ccprovider.ChaincodeData
+	res     *pb.Response
+	simRes  []byte
+	ccevent *pb.ChaincodeEvent
+}
+
+// simulationCache keeps the simulations of query-only proposals, to answer
+// identical proposals without executing the chaincode again while the
+// ledger did not grow by more than maxStaleBlocks. The proposals answered
+// from the cache are still checked and endorsed as any other
+type simulationCache struct {
+	maxStaleBlocks uint64
+	size           int
+
+	lock    sync.Mutex
+	entries map[string]*cachedSimulation
+}
+
+// newSimulationCacheFromConfig returns the cache configured in
+// peer.endorser.cache, or nil when the cache is disabled
+func newSimulationCacheFromConfig() *simulationCache {
+	if !viper.GetBool("peer.endorser.cache.enabled") {
+		return nil
+	}
+	size := viper.GetInt("peer.endorser.cache.size")
+	if size <= 0 {
+		size = 1000
+	}
+	return newSimulationCache(uint64(viper.GetInt("peer.endorser.cache.maxStaleBlocks")), size)
+}
+
+func newSimulationCache(maxStaleBlocks uint64, size int) *simulationCache {
+	return &simulationCache{
+		maxStaleBlocks: maxStaleBlocks,
+		size:           size,
+		entries:        make(map[string]*cachedSimulation),
+	}
+}
+
+// simulationCacheKey identifies the proposals which simulate identically:
+// same chain, chaincode, input, transient data and creator
+func simulationCacheKey(chainID string, ccid *pb.ChaincodeID, prop *pb.Proposal, creator []byte) string {
+	return string(util.ComputeSHA256(util.ConcatenateBytes(
+		[]byte(chainID), []byte{0},
+		[]byte(ccid.Name), []byte{0},
+		[]byte(ccid.Version), []byte{0},
+		util.ComputeSHA256(prop.Payload),
+		creator)))
+}
+
+// get returns the simulation cached under the key, unless the ledger grew
+// by more than maxStaleBlocks since
+func (c *simulationCache) get(key string, height uint64) *cachedSimulation {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if ok && c.stale(entry, height) {
+		delete(c.entries, key)
+		ok = false
+	}
+	if !ok {
+		cacheStats.Add("misses", 1)
+		return nil
+	}
+	cacheStats.Add("hits", 1)
+	return entry
+}
+
+// put caches the simulation under the key, if it succeeded. When the cache
+// is full, the stale simulations are dropped, or an arbitrary one when
+// there is none
+func (c *simulationCache) put(key string, entry *cachedSimulation) {
+	if entry.res == nil || entry.res.Status >= shim.ERROR {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.size {
+		for k, e := range c.entries {
+			if c.stale(e, entry.height) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < c.size {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = entry
+}
+
+func (c *simulationCache) stale(entry *cachedSimulation, height uint64) bool {
+	return height < entry.height || height-entry.height > c.maxStaleBlocks
+}
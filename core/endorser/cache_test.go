@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endorser
+
+import (
+	"fmt"
+	"testing"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulationCache(t *testing.T) {
+	cache := newSimulationCache(1, 10)
+	ccid := &pb.ChaincodeID{Name: "mycc", Version: "0"}
+	prop := &pb.Proposal{Payload: []byte("query a")}
+	key := simulationCacheKey("testchainid", ccid, prop, []byte("alice"))
+
+	assert.NotEqual(t, key, simulationCacheKey("testchainid", ccid, prop, []byte("bob")), "Should key the simulations by creator")
+	assert.NotEqual(t, key, simulationCacheKey("testchainid", ccid, &pb.Proposal{Payload: []byte("query b")}, []byte("alice")), "Should key the simulations by input")
+	assert.NotEqual(t, key, simulationCacheKey("otherchainid", ccid, prop, []byte("alice")), "Should key the simulations by chain")
+	assert.NotEqual(t, key, simulationCacheKey("testchainid", &pb.ChaincodeID{Name: "mycc", Version: "1"}, prop, []byte("alice")), "Should key the simulations by chaincode version")
+
+	assert.Nil(t, cache.get(key, 5))
+	cache.put(key, &cachedSimulation{height: 5, res: &pb.Response{Status: 200, Payload: []byte("100")}})
+	if cached := cache.get(key, 6); assert.NotNil(t, cached, "Should have answered within maxStaleBlocks") {
+		assert.Equal(t, []byte("100"), cached.res.Payload)
+	}
+	assert.Nil(t, cache.get(key, 7), "Should have dropped the simulation once stale")
+	assert.Empty(t, cache.entries)
+
+	cache.put(key, &cachedSimulation{height: 7, res: &pb.Response{Status: 500}})
+	assert.Nil(t, cache.get(key, 7), "Should not have cached a failed simulation")
+}
+
+func TestSimulationCacheSize(t *testing.T) {
+	cache := newSimulationCache(0, 3)
+	for i := 0; i < 3; i++ {
+		cache.put(fmt.Sprintf("key%d", i), &cachedSimulation{height: uint64(i), res: &pb.Response{Status: 200}})
+	}
+	cache.put("key3", &cachedSimulation{height: 2, res: &pb.Response{Status: 200}})
+	assert.Len(t, cache.entries, 2, "Should have dropped the stale simulations")
+	assert.NotNil(t, cache.get("key2", 2))
+
+	cache.put("key4", &cachedSimulation{height: 2, res: &pb.Response{Status: 200}})
+	cache.put("key5", &cachedSimulation{height: 2, res: &pb.Response{Status: 200}})
+	assert.Len(t, cache.entries, 3, "Should not have exceeded the size of the cache")
+	assert.NotNil(t, cache.get("key5", 2))
+}
@@ -17,29 +17,36 @@ limitations under the License.
 package endorser
 
 import (
+	"bytes"
 	"fmt"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	grpcpeer "google.golang.org/grpc/peer"
 
 	"errors"
 
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/validation"
+	"github.com/hyperledger/fabric/core/endorser/interceptor"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/policy"
-	syscc "github.com/hyperledger/fabric/core/scc"
+	"github.com/hyperledger/fabric/core/quota"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
 )
 
 var endorserLogger = flogging.MustGetLogger("endorser")
@@ -49,17 +56,50 @@ var endorserLogger = flogging.MustGetLogger("endorser")
 
 // Endorser provides the Endorser service ProcessProposal
 type Endorser struct {
+	support       Support
 	policyChecker policy.PolicyChecker
+	interceptors  interceptor.Chain
+	endorsements  *quota.Limiter
+	cache         *simulationCache
+	timestampSkew time.Duration
+	// readOnly peers serve queries but never endorse transactions
+	readOnly bool
 }
 
 // NewEndorserServer creates and returns a new Endorser server instance.
 func NewEndorserServer() pb.EndorserServer {
-	e := new(Endorser)
-	e.policyChecker = policy.NewPolicyChecker(
+	return NewEndorserServerWithInterceptors(nil)
+}
+
+// NewEndorserServerWithInterceptors creates and returns a new Endorser server
+// instance which lets the passed interceptors check every proposal before it
+// is simulated.
+func NewEndorserServerWithInterceptors(interceptors interceptor.Chain) pb.EndorserServer {
+	policyChecker := policy.NewPolicyChecker(
 		peer.NewChannelPolicyManagerGetter(),
 		mgmt.GetLocalMSP(),
 		mgmt.NewLocalMSPPrincipalGetter(),
 	)
+	return NewEndorserServerWithSupport(NewSupport(), policyChecker, interceptors)
+}
+
+// NewEndorserServerWithSupport creates and returns a new Endorser server
+// instance which reaches the ledgers and the chaincodes through the support,
+// and checks the proposals against the policies with the policy checker, so
+// that it can be embedded in other assemblies than the peer.
+func NewEndorserServerWithSupport(support Support, policyChecker policy.PolicyChecker, interceptors interceptor.Chain) pb.EndorserServer {
+	e := &Endorser{
+		support:       support,
+		policyChecker: policyChecker,
+		interceptors:  interceptors,
+	}
+	e.endorsements = quota.NewLimiter("endorsements", quota.EndorsementLimit())
+	e.cache = newSimulationCacheFromConfig()
+	e.timestampSkew = viper.GetDuration("peer.endorser.timestampSkew")
+	e.readOnly = viper.GetBool("peer.endorser.readOnly")
+	if e.readOnly {
+		endorserLogger.Info("Read-only peer: serving queries, not endorsing transactions")
+	}
 
 	return e
 }
@@ -70,21 +110,59 @@ func (e *Endorser) checkACL(signedProp *pb.SignedProposal, chdr *common.ChannelH
 	return e.policyChecker.CheckPolicy(chdr.ChannelId, policies.ChannelApplicationWriters, signedProp)
 }
 
+// isLifecycleTx returns true if the proposal invokes lscc to
+// deploy or upgrade a chaincode
+func isLifecycleTx(prop *pb.Proposal, ccName string) bool {
+	if ccName != "lscc" {
+		return false
+	}
+	cis, err := putils.GetChaincodeInvocationSpec(prop)
+	if err != nil || cis.ChaincodeSpec == nil || cis.ChaincodeSpec.Input == nil || len(cis.ChaincodeSpec.Input.Args) == 0 {
+		return false
+	}
+	f := string(cis.ChaincodeSpec.Input.Args[0])
+	return f == "deploy" || f == "upgrade"
+}
+
+// isQuery returns true if the proposal cannot result in a transaction, i.e.
+// it is flagged query-only or dry-run, or it invokes a system chaincode other
+// than to deploy or upgrade a chaincode
+func (e *Endorser) isQuery(prop *pb.Proposal, hdrExt *pb.ChaincodeHeaderExtension) bool {
+	if hdrExt.QueryOnly || hdrExt.DryRun {
+		return true
+	}
+	return e.support.IsSysCC(hdrExt.ChaincodeId.Name) && !isLifecycleTx(prop, hdrExt.ChaincodeId.Name)
+}
+
 //TODO - check for escc and vscc
 func (*Endorser) checkEsccAndVscc(prop *pb.Proposal) error {
 	return nil
 }
 
-func (*Endorser) getTxSimulator(ledgername string) (ledger.TxSimulator, error) {
-	lgr := peer.GetLedger(ledgername)
+func (e *Endorser) getTxSimulator(ledgername string) (ledger.TxSimulator, error) {
+	lgr := e.support.GetLedger(ledgername)
 	if lgr == nil {
 		return nil, fmt.Errorf("chain does not exist(%s)", ledgername)
 	}
 	return lgr.NewTxSimulator()
 }
 
-func (*Endorser) getHistoryQueryExecutor(ledgername string) (ledger.HistoryQueryExecutor, error) {
-	lgr := peer.GetLedger(ledgername)
+// getQueryOnlyTxSimulator returns a simulator which reads the ledger through
+// a query executor, without recording the read and write sets of a transaction
+func (e *Endorser) getQueryOnlyTxSimulator(ledgername string) (ledger.TxSimulator, error) {
+	lgr := e.support.GetLedger(ledgername)
+	if lgr == nil {
+		return nil, fmt.Errorf("chain does not exist(%s)", ledgername)
+	}
+	qe, err := lgr.NewQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	return chaincode.NewReadOnlyTxSimulator(ledgername, qe), nil
+}
+
+func (e *Endorser) getHistoryQueryExecutor(ledgername string) (ledger.HistoryQueryExecutor, error) {
+	lgr := e.support.GetLedger(ledgername)
 	if lgr == nil {
 		return nil, fmt.Errorf("chain does not exist(%s)", ledgername)
 	}
@@ -102,11 +180,11 @@ func (e *Endorser) callChaincode(ctxt context.Context, chainID string, version s
 	}
 
 	//is this a system chaincode
-	scc := syscc.IsSysCC(cid.Name)
+	scc := e.support.IsSysCC(cid.Name)
 
 	cccid := ccprovider.NewCCContext(chainID, cid.Name, version, txid, scc, signedProp, prop)
 
-	res, ccevent, err = chaincode.ExecuteChaincode(ctxt, cccid, cis.ChaincodeSpec.Input.Args)
+	res, ccevent, err = e.support.ExecuteChaincode(ctxt, cccid, cis.ChaincodeSpec.Input.Args)
 
 	if err != nil {
 		return nil, nil, err
@@ -136,13 +214,13 @@ func (e *Endorser) callChaincode(ctxt context.Context, chainID string, version s
 		}
 
 		//this should not be a system chaincode
-		if syscc.IsSysCC(cds.ChaincodeSpec.ChaincodeId.Name) {
+		if e.support.IsSysCC(cds.ChaincodeSpec.ChaincodeId.Name) {
 			return nil, nil, fmt.Errorf("attempting to deploy a system chaincode %s/%s", cds.ChaincodeSpec.ChaincodeId.Name, chainID)
 		}
 
 		cccid = ccprovider.NewCCContext(chainID, cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version, txid, false, signedProp, prop)
 
-		_, _, err = chaincode.Execute(ctxt, cccid, cds)
+		err = e.support.DeployChaincode(ctxt, cccid, cds)
 		if err != nil {
 			return nil, nil, fmt.Errorf("%s", err)
 		}
@@ -171,7 +249,7 @@ func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid st
 
 	//default it to a system CC
 	version := util.GetSysCCVersion()
-	if !syscc.IsSysCC(cid.Name) {
+	if !e.support.IsSysCC(cid.Name) {
 		cd, err = e.getCDSFromLSCC(ctx, chainID, txid, signedProp, prop, cid.Name, txsim)
 		if err != nil {
 			return nil, nil, nil, nil, fmt.Errorf("failed to obtain cds for %s - %s", cid.Name, err)
@@ -203,7 +281,7 @@ func (e *Endorser) getCDSFromLSCC(ctx context.Context, chainID string, txid stri
 		ctxt = context.WithValue(ctx, chaincode.TXSimulatorKey, txsim)
 	}
 
-	return chaincode.GetChaincodeDataFromLSCC(ctxt, txid, signedProp, prop, chainID, chaincodeID)
+	return e.support.GetChaincodeData(ctxt, txid, signedProp, prop, chainID, chaincodeID)
 }
 
 //endorse the proposal by calling the ESCC
@@ -241,6 +319,11 @@ func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid str
 		return nil, fmt.Errorf("failed to marshal response bytes - %s", err)
 	}
 
+	ccidBytes, err := putils.Marshal(executedChaincodeID(ccid, cd))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal chaincode ID - %s", err)
+	}
+
 	// 3) call the ESCC we've identified
 	// arguments:
 	// args[0] - function name (not used now)
@@ -250,7 +333,8 @@ func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid str
 	// args[4] - binary blob of simulation results
 	// args[5] - serialized events
 	// args[6] - payloadVisibility
-	args := [][]byte{[]byte(""), proposal.Header, proposal.Payload, resBytes, simRes, eventBytes, visibility}
+	// args[7] - serialized ChaincodeID of the executed chaincode
+	args := [][]byte{[]byte(""), proposal.Header, proposal.Payload, resBytes, simRes, eventBytes, visibility, ccidBytes}
 	version := util.GetSysCCVersion()
 	ecccis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: escc}, Input: &pb.ChaincodeInput{Args: args}}}
 	res, _, err := e.callChaincode(ctx, chainID, version, txid, signedProp, proposal, ecccis, &pb.ChaincodeID{Name: escc}, txsim)
@@ -281,6 +365,32 @@ func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid str
 	return pResp, nil
 }
 
+// checkTLSBinding checks that the TLS certificate hash bound to the proposal,
+// if any, is the hash of the client certificate of the mutual TLS connection
+// the proposal was received on. Chaincodes can then rely on the hash returned
+// by shim.ChaincodeStubInterface.GetTLSCertHash
+func checkTLSBinding(ctx context.Context, chdr *common.ChannelHeader) error {
+	if len(chdr.TlsCertHash) == 0 {
+		return nil
+	}
+
+	p, ok := grpcpeer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return errors.New("proposal is bound to a TLS client certificate but was not received over TLS")
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return fmt.Errorf("proposal is bound to a TLS client certificate but was received over %s", p.AuthInfo.AuthType())
+	}
+	if len(tlsInfo.State.PeerCertificates) == 0 {
+		return errors.New("proposal is bound to a TLS client certificate but the client presented none")
+	}
+	if !bytes.Equal(util.ComputeSHA256(tlsInfo.State.PeerCertificates[0].Raw), chdr.TlsCertHash) {
+		return errors.New("proposal is bound to another TLS client certificate than the one of the connection")
+	}
+	return nil
+}
+
 // ProcessProposal process the Proposal
 func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
 	// at first, we check whether the message is valid
@@ -299,8 +409,18 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 	}
 
+	if err = checkTLSBinding(ctx, chdr); err != nil {
+		endorserLogger.Warningf("ProcessProposal error: TLS binding of proposal %s failed: %s", chdr.TxId, err)
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+	}
+
+	if err = validation.CheckTimestamp(chdr, time.Now(), e.timestampSkew); err != nil {
+		endorserLogger.Warningf("ProcessProposal error: rejecting proposal %s: %s", chdr.TxId, err)
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+	}
+
 	// block invocations to security-sensitive system chaincodes
-	if syscc.IsSysCCAndNotInvokable(hdrExt.ChaincodeId.Name) {
+	if e.support.IsSysCCAndNotInvokable(hdrExt.ChaincodeId.Name) {
 		endorserLogger.Errorf("ProcessProposal error: an attempt was made by %#v to invoke system chaincode %s",
 			shdr.Creator, hdrExt.ChaincodeId.Name)
 		err = fmt.Errorf("Chaincode %s cannot be invoked through a proposal", hdrExt.ChaincodeId.Name)
@@ -309,6 +429,18 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 
 	chainID := chdr.ChannelId
 
+	// read-only peers reject the proposals meant to be endorsed
+	if e.readOnly && chainID != "" && !e.isQuery(prop, hdrExt) {
+		err = fmt.Errorf("Peer is read-only and does not endorse proposals, flag proposal %s as query-only to query chaincode %s", chdr.TxId, hdrExt.ChaincodeId.Name)
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+	}
+
+	if hdrExt.DryRun {
+		if err = checkDryRun(chainID, prop, hdrExt); err != nil {
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+		}
+	}
+
 	// Check for uniqueness of prop.TxID with ledger
 	// Notice that ValidateProposalMessage has already verified
 	// that TxID is computed propertly
@@ -318,9 +450,14 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 	}
 
+	// trace the processing of the proposal, keyed by its txid
+	tr := tracing.New("endorser", txid, tracing.TxID(txid))
+	defer tr.Finish()
+	tr.LazyPrintf("channel %s, chaincode %s", chainID, hdrExt.ChaincodeId.Name)
+
 	if chainID != "" {
 		// here we handle uniqueness check and ACLs for proposals targeting a chain
-		lgr := peer.GetLedger(chainID)
+		lgr := e.support.GetLedger(chainID)
 		if lgr == nil {
 			return nil, errors.New(fmt.Sprintf("Failure while looking up the ledger %s", chainID))
 		}
@@ -328,9 +465,11 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 			return nil, fmt.Errorf("Duplicate transaction found [%s]. Creator [%x]. [%s]", txid, shdr.Creator, err)
 		}
 
-		// check ACL only for application chaincodes; ACLs
-		// for system chaincodes are checked elsewhere
-		if !syscc.IsSysCC(hdrExt.ChaincodeId.Name) {
+		// check ACL for application chaincodes and for the lscc
+		// invocations deploying or upgrading a chaincode, as they
+		// result in transactions; ACLs for the other system
+		// chaincode invocations are checked elsewhere
+		if !e.support.IsSysCC(hdrExt.ChaincodeId.Name) || isLifecycleTx(prop, hdrExt.ChaincodeId.Name) {
 			// check that the proposal complies with the channel's writers
 			if err = e.checkACL(signedProp, chdr, shdr, hdrExt); err != nil {
 				return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
@@ -343,12 +482,45 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		// MSP of the peer instead by the call to ValidateProposalMessage above
 	}
 
+	// let the interceptors of the deployment deny the proposal before it is simulated
+	if len(e.interceptors) > 0 {
+		ctx, err = e.interceptors.Intercept(ctx, &interceptor.Proposal{
+			SignedProposal:  signedProp,
+			Proposal:        prop,
+			ChannelHeader:   chdr,
+			SignatureHeader: shdr,
+			ChaincodeHeader: hdrExt,
+		})
+		if err != nil {
+			endorserLogger.Warningf("ProcessProposal error: proposal %s denied by an interceptor: %s", txid, err)
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+		}
+	}
+
+	// bound the proposals of each chain endorsed concurrently, so that a
+	// busy chain does not starve the others
+	if chainID != "" {
+		if err = e.endorsements.Acquire(chainID); err != nil {
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+		}
+		defer e.endorsements.Release(chainID)
+	}
+
 	// obtaining once the tx simulator for this proposal. This will be nil
 	// for chainless proposals
 	// Also obtain a history query executor for history queries, since tx simulator does not cover history
 	var txsim ledger.TxSimulator
 	var historyQueryExecutor ledger.HistoryQueryExecutor
-	if chainID != "" {
+	if chainID != "" && hdrExt.QueryOnly {
+		// query-only proposals are executed against a read-only view of the
+		// ledger, their reads are not recorded and their writes fail
+		endorserLogger.Debugf("Executing query-only proposal %s against a read-only view of the ledger", txid)
+		if txsim, err = e.getQueryOnlyTxSimulator(chainID); err != nil {
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+		}
+
+		defer txsim.Done()
+	} else if chainID != "" {
 		if txsim, err = e.getTxSimulator(chainID); err != nil {
 			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 		}
@@ -370,9 +542,37 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	//       to validate the supplied action before endorsing it
 
 	//1 -- simulate
-	cd, res, simulationResult, ccevent, err := e.simulateProposal(ctx, chainID, txid, signedProp, prop, hdrExt.ChaincodeId, txsim)
-	if err != nil {
-		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+	// query-only proposals identical to a recent one are answered with its
+	// simulation, if the cache is enabled
+	var cacheKey string
+	var height uint64
+	var cached *cachedSimulation
+	if e.cache != nil && chainID != "" && hdrExt.QueryOnly {
+		if height, err = e.ledgerHeight(chainID); err != nil {
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+		}
+		cacheKey = simulationCacheKey(chainID, hdrExt.ChaincodeId, prop, shdr.Creator)
+		cached = e.cache.get(cacheKey, height)
+	}
+
+	var cd *ccprovider.ChaincodeData
+	var res *pb.Response
+	var simulationResult []byte
+	var ccevent *pb.ChaincodeEvent
+	if cached != nil {
+		tr.LazyPrintf("answering with the simulation at height %d", cached.height)
+		cd, res, simulationResult, ccevent = cached.cd, cached.res, cached.simRes, cached.ccevent
+	} else {
+		tr.LazyPrintf("simulating")
+		cd, res, simulationResult, ccevent, err = e.simulateProposal(ctx, chainID, txid, signedProp, prop, hdrExt.ChaincodeId, txsim)
+		if err != nil {
+			tr.LazyPrintf("simulation failed: %s", err)
+			tr.SetError()
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+		}
+		if cacheKey != "" {
+			e.cache.put(cacheKey, &cachedSimulation{height: height, cd: cd, res: res, simRes: simulationResult, ccevent: ccevent})
+		}
 	}
 
 	//2 -- endorse and get a marshalled ProposalResponse message
@@ -380,11 +580,21 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 
 	//TODO till we implement global ESCC, CSCC for system chaincodes
 	//chainless proposals (such as CSCC) don't have to be endorsed
-	if chainID == "" {
+	//read-only peers answer the queries without signing their results, and
+	//query-only proposals are not endorsed since their results are not recorded
+	if hdrExt.DryRun {
+		tr.LazyPrintf("returning the dry-run results")
+		if pResp, err = dryRunResponse(prop, res, simulationResult, ccevent, hdrExt.PayloadVisibility, executedChaincodeID(hdrExt.ChaincodeId, cd)); err != nil {
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+		}
+	} else if chainID == "" || e.readOnly || hdrExt.QueryOnly {
 		pResp = &pb.ProposalResponse{Response: res}
 	} else {
+		tr.LazyPrintf("endorsing")
 		pResp, err = e.endorseProposal(ctx, chainID, txid, signedProp, prop, res, simulationResult, ccevent, hdrExt.PayloadVisibility, hdrExt.ChaincodeId, txsim, cd)
 		if err != nil {
+			tr.LazyPrintf("endorsement failed: %s", err)
+			tr.SetError()
 			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
 		}
 	}
@@ -394,9 +604,81 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	// chaincode invocation
 	pResp.Response.Payload = res.Payload
 
+	// let the client plan the endorsements the transaction needs
+	if chainID != "" {
+		if pResp.Interest, err = chaincodeInterest(hdrExt.ChaincodeId.Name, simulationResult, e.support.IsSysCC); err != nil {
+			endorserLogger.Warningf("Could not determine the chaincode interest of proposal %s: %s", txid, err)
+		}
+	}
+	tr.LazyPrintf("endorsed with status %d", res.Status)
+
 	return pResp, nil
 }
 
+// checkDryRun refuses the dry-run of a proposal which can't be simulated
+// without side effects or whose results would be meaningless
+func checkDryRun(chainID string, prop *pb.Proposal, hdrExt *pb.ChaincodeHeaderExtension) error {
+	switch {
+	case chainID == "":
+		return errors.New("Dry-run proposals must target a channel")
+	case hdrExt.QueryOnly:
+		return errors.New("Proposals cannot be both query-only and dry-run, query-only proposals are not simulated")
+	case isLifecycleTx(prop, hdrExt.ChaincodeId.Name):
+		return errors.New("Deploy and upgrade proposals cannot be dry-run, they launch the chaincode")
+	}
+	return nil
+}
+
+// executedChaincodeID returns the ID of the chaincode with the version that
+// was executed, recorded in the chaincode action so that the committer can
+// invalidate transactions endorsed against a version which has since been
+// upgraded
+func executedChaincodeID(ccid *pb.ChaincodeID, cd *ccprovider.ChaincodeData) *pb.ChaincodeID {
+	if cd != nil {
+		return &pb.ChaincodeID{Name: ccid.Name, Version: cd.Version}
+	}
+	return &pb.ChaincodeID{Name: ccid.Name, Version: util.GetSysCCVersion()}
+}
+
+// dryRunResponse returns the response of a dry-run proposal: its payload
+// carries the chaincode response, the results of the simulation and the
+// chaincode event as an endorsement would, but no endorsement is attached,
+// so that it can't be submitted as a transaction
+func dryRunResponse(prop *pb.Proposal, res *pb.Response, simRes []byte, event *pb.ChaincodeEvent, visibility []byte, ccid *pb.ChaincodeID) (*pb.ProposalResponse, error) {
+	hdr, err := putils.GetHeader(prop.Header)
+	if err != nil {
+		return nil, err
+	}
+	pHash, err := putils.GetProposalHash1(hdr, prop.Payload, visibility)
+	if err != nil {
+		return nil, fmt.Errorf("could not compute proposal hash - %s", err)
+	}
+	var eventBytes []byte
+	if event != nil {
+		if eventBytes, err = putils.GetBytesChaincodeEvent(event); err != nil {
+			return nil, fmt.Errorf("failed to marshal event bytes - %s", err)
+		}
+	}
+	prpBytes, err := putils.GetBytesProposalResponsePayload(pHash, res, simRes, eventBytes, ccid)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ProposalResponse{Version: 1, Response: res, Payload: prpBytes}, nil
+}
+
+// ledgerHeight returns the height of the ledger of the chain
+func (e *Endorser) ledgerHeight(chainID string) (uint64, error) {
+	lgr := e.support.GetLedger(chainID)
+	if lgr == nil {
+		return 0, fmt.Errorf("failure while looking up the ledger %s", chainID)
+	}
+	info, err := lgr.GetBlockchainInfo()
+	if err != nil {
+		return 0, err
+	}
+	return info.Height, nil
+}
+
 // Only exposed for testing purposes - commit the tx simulation so that
 // a deploy transaction is persisted and that chaincode can be invoked.
 // This makes the endorser test self-sufficient
@@ -406,7 +688,7 @@ func (e *Endorser) commitTxSimulation(proposal *pb.Proposal, chainID string, sig
 		return err
 	}
 
-	lgr := peer.GetLedger(chainID)
+	lgr := e.support.GetLedger(chainID)
 	if lgr == nil {
 		return fmt.Errorf("failure while looking up the ledger")
 	}
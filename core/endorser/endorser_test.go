@@ -27,15 +27,21 @@ import (
 	"path/filepath"
 
 	"errors"
+	"strings"
+
+	"crypto/tls"
+	"crypto/x509"
 
 	"github.com/golang/protobuf/proto"
 	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/peer"
 	syscc "github.com/hyperledger/fabric/core/scc"
 	"github.com/hyperledger/fabric/msp"
@@ -48,6 +54,7 @@ import (
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	grpcpeer "google.golang.org/grpc/peer"
 )
 
 var endorserServer pb.EndorserServer
@@ -624,6 +631,94 @@ func TestAdminACLFail(t *testing.T) {
 	chaincode.GetChain().Stop(ctxt, cccid, &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: chaincodeID}})
 }
 
+// TestDeployWritersACLFail makes sure that a deployment proposal
+// from a creator not satisfying the writers policy of the chain is
+// rejected before the deployment gets simulated
+func TestDeployWritersACLFail(t *testing.T) {
+	chainID := util.GetTestChainID()
+
+	rejectpolicy := &mockpolicies.Policy{
+		Err: errors.New("The creator of this proposal does not fulfil the writers policy of this chain"),
+	}
+	pm := peer.GetPolicyManager(chainID)
+	pm.(*mockpolicies.Manager).PolicyMap = map[string]policies.Policy{policies.ChannelApplicationWriters: rejectpolicy}
+	defer func() { pm.(*mockpolicies.Manager).PolicyMap = nil }()
+
+	url := "github.com/hyperledger/fabric/examples/chaincode/go/chaincode_example01"
+	chaincodeID := &pb.ChaincodeID{Path: url, Name: "ex01-writers-fail", Version: "0"}
+
+	defer deleteChaincodeOnDisk("ex01-writers-fail.0")
+
+	argsDeploy := util.ToChaincodeArgs("init", "a", "100", "b", "200")
+	spec := &pb.ChaincodeSpec{Type: 1, ChaincodeId: chaincodeID, Input: &pb.ChaincodeInput{Args: argsDeploy}}
+
+	_, _, err := deploy(endorserServer, chainID, spec, nil)
+	if err == nil {
+		t.Fatalf("Deploying chaincode should have failed!")
+	}
+	if !strings.Contains(err.Error(), "writers policy") {
+		t.Fatalf("Deployment should have been rejected by the writers policy, got [%s]", err)
+	}
+}
+
+func TestIsLifecycleTx(t *testing.T) {
+	creator, err := signer.Serialize()
+	if err != nil {
+		t.Fatalf("Failed serializing signer [%s]", err)
+	}
+
+	for _, tc := range []struct {
+		ccName   string
+		args     []string
+		expected bool
+	}{
+		{"lscc", []string{"deploy", "testchainid", "cds"}, true},
+		{"lscc", []string{"upgrade", "testchainid", "cds"}, true},
+		{"lscc", []string{"getid", "testchainid", "mycc"}, false},
+		{"qscc", []string{"deploy"}, false},
+		{"mycc", []string{"upgrade"}, false},
+	} {
+		cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: tc.ccName}, Input: &pb.ChaincodeInput{Args: util.ToChaincodeArgs(tc.args...)}}}
+		prop, _, err := getInvokeProposal(cis, util.GetTestChainID(), creator)
+		if err != nil {
+			t.Fatalf("Failed creating proposal [%s]", err)
+		}
+		if isLifecycleTx(prop, tc.ccName) != tc.expected {
+			t.Errorf("Expected isLifecycleTx to return %v for %s %v", tc.expected, tc.ccName, tc.args)
+		}
+	}
+}
+
+func TestIsQuery(t *testing.T) {
+	creator, err := signer.Serialize()
+	if err != nil {
+		t.Fatalf("Failed serializing signer [%s]", err)
+	}
+
+	for _, tc := range []struct {
+		ccName    string
+		args      []string
+		queryOnly bool
+		expected  bool
+	}{
+		{"mycc", []string{"invoke"}, false, false},
+		{"mycc", []string{"query"}, true, true},
+		{"qscc", []string{"GetChainInfo"}, false, true},
+		{"lscc", []string{"getid", "testchainid", "mycc"}, false, true},
+		{"lscc", []string{"deploy", "testchainid", "cds"}, false, false},
+	} {
+		cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: tc.ccName}, Input: &pb.ChaincodeInput{Args: util.ToChaincodeArgs(tc.args...)}}}
+		prop, _, err := getInvokeProposal(cis, util.GetTestChainID(), creator)
+		if err != nil {
+			t.Fatalf("Failed creating proposal [%s]", err)
+		}
+		hdrExt := &pb.ChaincodeHeaderExtension{ChaincodeId: &pb.ChaincodeID{Name: tc.ccName}, QueryOnly: tc.queryOnly}
+		if endorserServer.(*Endorser).isQuery(prop, hdrExt) != tc.expected {
+			t.Errorf("Expected isQuery to return %v for %s %v, query-only %v", tc.expected, tc.ccName, tc.args, tc.queryOnly)
+		}
+	}
+}
+
 // TestInvokeSccFail makes sure that invoking a system chaincode fails
 func TestInvokeSccFail(t *testing.T) {
 	chainID := util.GetTestChainID()
@@ -639,6 +734,167 @@ func TestInvokeSccFail(t *testing.T) {
 	}
 }
 
+// mockSupport serves the chaincodes of ccResponses without ledgers
+type mockSupport struct {
+	sysCCs      map[string]bool
+	ccResponses map[string]*pb.Response
+	executed    []string
+}
+
+func (s *mockSupport) IsSysCC(name string) bool {
+	return s.sysCCs[name]
+}
+
+func (s *mockSupport) IsSysCCAndNotInvokable(name string) bool {
+	return false
+}
+
+func (s *mockSupport) GetLedger(chainID string) ledger.PeerLedger {
+	return nil
+}
+
+func (s *mockSupport) GetChaincodeData(ctx context.Context, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, chainID string, chaincodeID string) (*ccprovider.ChaincodeData, error) {
+	return nil, fmt.Errorf("chaincode %s not found", chaincodeID)
+}
+
+func (s *mockSupport) ExecuteChaincode(ctx context.Context, cccid *ccprovider.CCContext, args [][]byte) (*pb.Response, *pb.ChaincodeEvent, error) {
+	s.executed = append(s.executed, cccid.Name)
+	res, ok := s.ccResponses[cccid.Name]
+	if !ok {
+		return nil, nil, fmt.Errorf("chaincode %s not found", cccid.Name)
+	}
+	return res, nil, nil
+}
+
+func (s *mockSupport) DeployChaincode(ctx context.Context, cccid *ccprovider.CCContext, cds *pb.ChaincodeDeploymentSpec) error {
+	return errors.New("not supported")
+}
+
+func TestEndorserWithSupport(t *testing.T) {
+	support := &mockSupport{
+		sysCCs:      map[string]bool{"cscc": true},
+		ccResponses: map[string]*pb.Response{"cscc": {Status: shim.OK, Payload: []byte("channels")}},
+	}
+	e := NewEndorserServerWithSupport(support, nil, nil)
+
+	creator, err := signer.Serialize()
+	if err != nil {
+		t.Fatalf("Failed serializing identity [%s]", err)
+	}
+	signedPropOf := func(chainID, ccName string) *pb.SignedProposal {
+		cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: ccName}, Input: &pb.ChaincodeInput{Args: util.ToChaincodeArgs("GetChannels")}}}
+		prop, _, err := getInvokeProposal(cis, chainID, creator)
+		if err != nil {
+			t.Fatalf("Failed creating proposal [%s]", err)
+		}
+		signedProp, err := getSignedProposal(prop, signer)
+		if err != nil {
+			t.Fatalf("Failed signing proposal [%s]", err)
+		}
+		return signedProp
+	}
+
+	// chainless proposals are executed by the chaincodes of the support
+	resp, err := e.ProcessProposal(context.Background(), signedPropOf("", "cscc"))
+	if err != nil {
+		t.Fatalf("Chainless proposal should have succeeded [%s]", err)
+	}
+	if string(resp.Response.Payload) != "channels" {
+		t.Fatalf("Expected the payload of the support, got %q", resp.Response.Payload)
+	}
+	if len(support.executed) != 1 || support.executed[0] != "cscc" {
+		t.Fatalf("Expected cscc to be executed through the support, got %v", support.executed)
+	}
+
+	// the ledgers are looked up through the support too
+	_, err = e.ProcessProposal(context.Background(), signedPropOf(util.GetTestChainID(), "mycc"))
+	if err == nil || !strings.Contains(err.Error(), "Failure while looking up the ledger") {
+		t.Fatalf("Expected the ledger lookup to fail, got %v", err)
+	}
+}
+
+func TestCheckDryRun(t *testing.T) {
+	creator, err := signer.Serialize()
+	if err != nil {
+		t.Fatalf("Failed serializing identity [%s]", err)
+	}
+	for _, tc := range []struct {
+		chainID   string
+		ccName    string
+		args      []string
+		queryOnly bool
+		valid     bool
+	}{
+		{util.GetTestChainID(), "mycc", []string{"invoke"}, false, true},
+		{"", "mycc", []string{"invoke"}, false, false},
+		{util.GetTestChainID(), "mycc", []string{"invoke"}, true, false},
+		{util.GetTestChainID(), "lscc", []string{"deploy", "testchainid", "cds"}, false, false},
+	} {
+		cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: tc.ccName}, Input: &pb.ChaincodeInput{Args: util.ToChaincodeArgs(tc.args...)}}}
+		prop, _, err := getInvokeProposal(cis, util.GetTestChainID(), creator)
+		if err != nil {
+			t.Fatalf("Failed creating proposal [%s]", err)
+		}
+		hdrExt := &pb.ChaincodeHeaderExtension{ChaincodeId: &pb.ChaincodeID{Name: tc.ccName}, QueryOnly: tc.queryOnly, DryRun: true}
+		if err = checkDryRun(tc.chainID, prop, hdrExt); (err == nil) != tc.valid {
+			t.Errorf("Expected the dry-run of %s %v on channel %q, query-only %v, to be valid %v, got %v", tc.ccName, tc.args, tc.chainID, tc.queryOnly, tc.valid, err)
+		}
+	}
+}
+
+func TestDryRunResponse(t *testing.T) {
+	creator, err := signer.Serialize()
+	if err != nil {
+		t.Fatalf("Failed serializing identity [%s]", err)
+	}
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: "mycc"}, Input: &pb.ChaincodeInput{Args: util.ToChaincodeArgs("invoke")}}}
+	prop, _, err := getInvokeProposal(cis, util.GetTestChainID(), creator)
+	if err != nil {
+		t.Fatalf("Failed creating proposal [%s]", err)
+	}
+
+	res := &pb.Response{Status: shim.OK, Payload: []byte("result")}
+	event := &pb.ChaincodeEvent{ChaincodeId: "mycc", EventName: "moved"}
+	ccid := executedChaincodeID(&pb.ChaincodeID{Name: "mycc"}, &ccprovider.ChaincodeData{Name: "mycc", Version: "1.0"})
+	pResp, err := dryRunResponse(prop, res, []byte("simulation results"), event, nil, ccid)
+	if err != nil {
+		t.Fatalf("Failed creating dry-run response [%s]", err)
+	}
+	if pResp.Endorsement != nil {
+		t.Fatalf("Dry-run responses must not be endorsed")
+	}
+	prp, err := pbutils.GetProposalResponsePayload(pResp.Payload)
+	if err != nil {
+		t.Fatalf("Failed unmarshaling proposal response payload [%s]", err)
+	}
+	action, err := pbutils.GetChaincodeAction(prp.Extension)
+	if err != nil {
+		t.Fatalf("Failed unmarshaling chaincode action [%s]", err)
+	}
+	if string(action.Results) != "simulation results" || !proto.Equal(action.Response, res) {
+		t.Fatalf("Unexpected dry-run results %q and response %v", action.Results, action.Response)
+	}
+	if !proto.Equal(action.ChaincodeId, &pb.ChaincodeID{Name: "mycc", Version: "1.0"}) {
+		t.Fatalf("Unexpected dry-run chaincode ID %v", action.ChaincodeId)
+	}
+	ccevent, err := pbutils.GetChaincodeEvents(action.Events)
+	if err != nil || ccevent.EventName != "moved" {
+		t.Fatalf("Unexpected dry-run event %v, err %v", ccevent, err)
+	}
+}
+
+func TestExecutedChaincodeID(t *testing.T) {
+	ccid := executedChaincodeID(&pb.ChaincodeID{Name: "mycc", Version: "0"}, &ccprovider.ChaincodeData{Name: "mycc", Version: "1.0"})
+	if !proto.Equal(ccid, &pb.ChaincodeID{Name: "mycc", Version: "1.0"}) {
+		t.Fatalf("Expected the version of the chaincode data, got %v", ccid)
+	}
+
+	ccid = executedChaincodeID(&pb.ChaincodeID{Name: "lscc"}, nil)
+	if !proto.Equal(ccid, &pb.ChaincodeID{Name: "lscc", Version: util.GetSysCCVersion()}) {
+		t.Fatalf("Expected the version of the system chaincodes, got %v", ccid)
+	}
+}
+
 func newTempDir() string {
 	tempDir, err := ioutil.TempDir("", "fabric-")
 	if err != nil {
@@ -647,6 +903,36 @@ func newTempDir() string {
 	return tempDir
 }
 
+func TestCheckTLSBinding(t *testing.T) {
+	cert := &x509.Certificate{Raw: []byte("client certificate")}
+	tlsCtx := grpcpeer.NewContext(context.Background(), &grpcpeer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+	})
+	noCertCtx := grpcpeer.NewContext(context.Background(), &grpcpeer.Peer{AuthInfo: credentials.TLSInfo{}})
+
+	// Unbound proposals are accepted on any connection
+	if err := checkTLSBinding(context.Background(), &common.ChannelHeader{}); err != nil {
+		t.Fatalf("Unbound proposal should have been accepted, got %s", err)
+	}
+
+	if err := checkTLSBinding(tlsCtx, &common.ChannelHeader{TlsCertHash: util.ComputeSHA256(cert.Raw)}); err != nil {
+		t.Fatalf("Proposal bound to the client certificate should have been accepted, got %s", err)
+	}
+
+	for _, c := range []struct {
+		ctx  context.Context
+		hash []byte
+	}{
+		{tlsCtx, util.ComputeSHA256([]byte("other certificate"))},
+		{noCertCtx, util.ComputeSHA256(cert.Raw)},
+		{context.Background(), util.ComputeSHA256(cert.Raw)},
+	} {
+		if err := checkTLSBinding(c.ctx, &common.ChannelHeader{TlsCertHash: c.hash}); err == nil {
+			t.Fatalf("Proposal bound to another certificate than the one of the connection should have been rejected")
+		}
+	}
+}
+
 func TestMain(m *testing.M) {
 	SetupTestConfig()
 
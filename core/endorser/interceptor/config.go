@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interceptor
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// PluginConfig is the configuration of an interceptor loaded as a plugin,
+// as found in the peer.endorser.interceptors section of core.yaml
+type PluginConfig struct {
+	Enabled bool   `mapstructure:"enabled" yaml:"enabled"`
+	Name    string `mapstructure:"name" yaml:"name"`
+	Path    string `mapstructure:"path" yaml:"path"`
+}
+
+// Load reads the interceptor plugins from the configuration and loads the
+// enabled ones, in the configured order. Unlike system chaincode plugins, an
+// interceptor failing to load is an error, as the peer would otherwise
+// endorse the proposals the interceptor is meant to deny
+func Load() (Chain, error) {
+	var pluginConfigs []*PluginConfig
+	if err := viper.UnmarshalKey("peer.endorser.interceptors", &pluginConfigs); err != nil {
+		return nil, fmt.Errorf("could not read the interceptor plugins configuration: %s", err)
+	}
+
+	var chain Chain
+	for _, conf := range pluginConfigs {
+		if conf == nil || conf.Name == "" || conf.Path == "" {
+			return nil, fmt.Errorf("interceptor plugin %v must have a name and a path", conf)
+		}
+
+		if !conf.Enabled {
+			logger.Infof("interceptor plugin %s(%s) disabled", conf.Name, conf.Path)
+			continue
+		}
+
+		interceptor, err := loadPlugin(conf.Path)
+		if err != nil {
+			return nil, fmt.Errorf("could not load interceptor plugin %s(%s): %s", conf.Name, conf.Path, err)
+		}
+		logger.Infof("loaded interceptor plugin %s(%s)", conf.Name, conf.Path)
+		chain = append(chain, interceptor)
+	}
+
+	return chain, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interceptor
+
+import (
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"golang.org/x/net/context"
+)
+
+var logger = flogging.MustGetLogger("endorser/interceptor")
+
+// Proposal is a proposal submitted to the endorser, along with its
+// unmarshalled headers. Interceptors must not modify it.
+type Proposal struct {
+	SignedProposal  *pb.SignedProposal
+	Proposal        *pb.Proposal
+	ChannelHeader   *common.ChannelHeader
+	SignatureHeader *common.SignatureHeader
+	ChaincodeHeader *pb.ChaincodeHeaderExtension
+}
+
+// Interceptor checks the proposals submitted to the endorser before they are
+// simulated, for instance to limit the rate of the proposals of a client
+type Interceptor interface {
+	// Intercept returns an error to deny the proposal. Otherwise it returns
+	// the context the proposal is simulated with, which may be derived from
+	// the given one, e.g. with a shorter deadline
+	Intercept(ctx context.Context, prop *Proposal) (context.Context, error)
+}
+
+// Chain is a sequence of interceptors, each one intercepting the proposal
+// with the context returned by the previous one
+type Chain []Interceptor
+
+// Intercept runs the interceptors of the chain in order, and stops at the
+// first one denying the proposal
+func (c Chain) Intercept(ctx context.Context, prop *Proposal) (context.Context, error) {
+	for _, interceptor := range c {
+		next, err := interceptor.Intercept(ctx, prop)
+		if err != nil {
+			return nil, err
+		}
+		if next != nil {
+			ctx = next
+		}
+	}
+	return ctx, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interceptor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+type key string
+
+type mockInterceptor struct {
+	name  string
+	deny  bool
+	calls *[]string
+}
+
+func (m *mockInterceptor) Intercept(ctx context.Context, prop *Proposal) (context.Context, error) {
+	*m.calls = append(*m.calls, m.name)
+	if m.deny {
+		return nil, errors.New(m.name + " denied the proposal")
+	}
+	if m.name == "unchanged" {
+		return nil, nil
+	}
+	return context.WithValue(ctx, key(m.name), ctx.Value(key("first"))), nil
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	chain := Chain{
+		&mockInterceptor{name: "first", calls: &calls},
+		&mockInterceptor{name: "unchanged", calls: &calls},
+		&mockInterceptor{name: "second", calls: &calls},
+	}
+	ctx, err := chain.Intercept(context.WithValue(context.Background(), key("first"), "value"), &Proposal{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "unchanged", "second"}, calls)
+	assert.Equal(t, "value", ctx.Value(key("second")), "Each interceptor should get the context returned by the previous one")
+
+	calls = nil
+	chain = Chain{
+		&mockInterceptor{name: "first", calls: &calls},
+		&mockInterceptor{name: "deny", deny: true, calls: &calls},
+		&mockInterceptor{name: "second", calls: &calls},
+	}
+	_, err = chain.Intercept(context.Background(), &Proposal{})
+	assert.EqualError(t, err, "deny denied the proposal")
+	assert.Equal(t, []string{"first", "deny"}, calls, "The interceptors after the one denying the proposal should not run")
+
+	ctx = context.Background()
+	result, err := Chain(nil).Intercept(ctx, &Proposal{})
+	assert.NoError(t, err)
+	assert.Equal(t, ctx, result)
+}
+
+func TestLoad(t *testing.T) {
+	defer viper.Set("peer.endorser.interceptors", nil)
+
+	viper.Set("peer.endorser.interceptors", nil)
+	chain, err := Load()
+	assert.NoError(t, err)
+	assert.Empty(t, chain)
+
+	viper.Set("peer.endorser.interceptors", []map[string]interface{}{
+		{"enabled": false, "name": "ratelimit", "path": "/opt/lib/ratelimit.so"},
+	})
+	chain, err = Load()
+	assert.NoError(t, err)
+	assert.Empty(t, chain, "Disabled interceptors should not be loaded")
+
+	viper.Set("peer.endorser.interceptors", []map[string]interface{}{
+		{"enabled": true, "path": "/opt/lib/ratelimit.so"},
+	})
+	_, err = Load()
+	assert.Error(t, err, "Interceptors without a name should be rejected")
+
+	viper.Set("peer.endorser.interceptors", []map[string]interface{}{
+		{"enabled": true, "name": "ratelimit", "path": "/nonexistent/ratelimit.so"},
+	})
+	_, err = Load()
+	assert.Error(t, err, "Interceptors failing to load should be an error")
+}
@@ -0,0 +1,54 @@
+// +build pluginsenabled,cgo
+// +build darwin linux
+
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interceptor
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// interceptorPluginFactory is the symbol that interceptor plugins must export
+const interceptorPluginFactory = "New"
+
+// loadPlugin opens the Go plugin at path and returns the interceptor built by
+// its exported New function, which must have the signature
+// func New() interceptor.Interceptor
+func loadPlugin(path string) (Interceptor, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := p.Lookup(interceptorPluginFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	factory, ok := sym.(func() Interceptor)
+	if !ok {
+		return nil, fmt.Errorf("symbol %s of plugin %s is not a func() interceptor.Interceptor", interceptorPluginFactory, path)
+	}
+
+	interceptor := factory()
+	if interceptor == nil {
+		return nil, fmt.Errorf("plugin %s returned a nil interceptor", path)
+	}
+	return interceptor, nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endorser
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// chaincodeInterest returns the invoked chaincode and the other chaincodes
+// whose namespaces the simulation results touch. The namespaces of system
+// chaincodes other than the invoked one are left out, as the endorser reads
+// them itself, e.g. to look up the chaincode definition in lscc.
+func chaincodeInterest(invoked string, simRes []byte, isSysCC func(string) bool) (*pb.ChaincodeInterest, error) {
+	invokedCall := &pb.ChaincodeCall{Name: invoked, ReadOnly: true}
+	interest := &pb.ChaincodeInterest{Chaincodes: []*pb.ChaincodeCall{invokedCall}}
+	if len(simRes) == 0 {
+		return interest, nil
+	}
+
+	txRWSet := &rwset.TxReadWriteSet{}
+	if err := proto.Unmarshal(simRes, txRWSet); err != nil {
+		return nil, err
+	}
+	for _, nsRWSet := range txRWSet.NsRwset {
+		kvRWSet := &kvrwset.KVRWSet{}
+		if err := proto.Unmarshal(nsRWSet.Rwset, kvRWSet); err != nil {
+			return nil, err
+		}
+		readOnly := len(kvRWSet.Writes) == 0 && len(kvRWSet.MetadataWrites) == 0
+
+		switch {
+		case nsRWSet.Namespace == invoked:
+			invokedCall.ReadOnly = readOnly
+		case isSysCC(nsRWSet.Namespace):
+		default:
+			interest.Chaincodes = append(interest.Chaincodes, &pb.ChaincodeCall{Name: nsRWSet.Namespace, ReadOnly: readOnly})
+		}
+	}
+	return interest, nil
+}
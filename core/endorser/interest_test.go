@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endorser
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaincodeInterest(t *testing.T) {
+	isSysCC := func(name string) bool { return name == "lscc" }
+
+	interest, err := chaincodeInterest("mycc", nil, isSysCC)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.ChaincodeCall{{Name: "mycc", ReadOnly: true}}, interest.Chaincodes)
+
+	builder := rwsetutil.NewRWSetBuilder()
+	builder.AddToReadSet("lscc", "mycc", version.NewHeight(1, 0))
+	builder.AddToReadSet("mycc", "a", version.NewHeight(1, 0))
+	builder.AddToReadSet("othercc", "b", version.NewHeight(1, 0))
+	builder.AddToWriteSet("writtencc", "c", []byte("value"))
+	simRes, err := builder.GetTxReadWriteSet().ToProtoBytes()
+	assert.NoError(t, err)
+
+	interest, err = chaincodeInterest("mycc", simRes, isSysCC)
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.ChaincodeCall{
+		{Name: "mycc", ReadOnly: true},
+		{Name: "othercc", ReadOnly: true},
+		{Name: "writtencc", ReadOnly: false},
+	}, interest.Chaincodes, "Should have listed the invoked chaincode first and left out lscc")
+
+	// the interest survives the marshaling of the proposal response
+	respBytes, err := proto.Marshal(&pb.ProposalResponse{Interest: interest})
+	assert.NoError(t, err)
+	resp := &pb.ProposalResponse{}
+	assert.NoError(t, proto.Unmarshal(respBytes, resp))
+	assert.True(t, proto.Equal(interest, resp.Interest))
+
+	_, err = chaincodeInterest("mycc", []byte("garbage"), isSysCC)
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endorser
+
+import (
+	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/peer"
+	syscc "github.com/hyperledger/fabric/core/scc"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"golang.org/x/net/context"
+)
+
+// Support gives the endorser access to the ledgers and the chaincodes of
+// the node it is assembled in. The peer uses the implementation returned by
+// NewSupport, tests and other node assemblies may provide their own
+type Support interface {
+	// IsSysCC returns true if the chaincode is a system chaincode
+	IsSysCC(name string) bool
+
+	// IsSysCCAndNotInvokable returns true if the chaincode is a system
+	// chaincode which cannot be invoked through a proposal
+	IsSysCCAndNotInvokable(name string) bool
+
+	// GetLedger returns the ledger of the channel, nil if there is none
+	GetLedger(chainID string) ledger.PeerLedger
+
+	// GetChaincodeData returns the definition of the chaincode instantiated
+	// on the channel, as recorded by the lscc
+	GetChaincodeData(ctx context.Context, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, chainID string, chaincodeID string) (*ccprovider.ChaincodeData, error)
+
+	// ExecuteChaincode invokes the chaincode with the args
+	ExecuteChaincode(ctx context.Context, cccid *ccprovider.CCContext, args [][]byte) (*pb.Response, *pb.ChaincodeEvent, error)
+
+	// DeployChaincode launches the chaincode of the deployment spec and
+	// runs its init, as part of the deploy or upgrade of the chaincode
+	DeployChaincode(ctx context.Context, cccid *ccprovider.CCContext, cds *pb.ChaincodeDeploymentSpec) error
+}
+
+// supportImpl implements Support over the ledgers of the peer, its
+// chaincode support and its system chaincodes
+type supportImpl struct{}
+
+// NewSupport returns the Support of the endorser of the peer
+func NewSupport() Support {
+	return &supportImpl{}
+}
+
+func (*supportImpl) IsSysCC(name string) bool {
+	return syscc.IsSysCC(name)
+}
+
+func (*supportImpl) IsSysCCAndNotInvokable(name string) bool {
+	return syscc.IsSysCCAndNotInvokable(name)
+}
+
+func (*supportImpl) GetLedger(chainID string) ledger.PeerLedger {
+	return peer.GetLedger(chainID)
+}
+
+func (*supportImpl) GetChaincodeData(ctx context.Context, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, chainID string, chaincodeID string) (*ccprovider.ChaincodeData, error) {
+	return chaincode.GetChaincodeDataFromLSCC(ctx, txid, signedProp, prop, chainID, chaincodeID)
+}
+
+func (*supportImpl) ExecuteChaincode(ctx context.Context, cccid *ccprovider.CCContext, args [][]byte) (*pb.Response, *pb.ChaincodeEvent, error) {
+	return chaincode.ExecuteChaincode(ctx, cccid, args)
+}
+
+func (*supportImpl) DeployChaincode(ctx context.Context, cccid *ccprovider.CCContext, cds *pb.ChaincodeDeploymentSpec) error {
+	_, _, err := chaincode.Execute(ctx, cccid, cds)
+	return err
+}
@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvledger
+
+import (
+	"sync"
+
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+)
+
+// blocksItr wraps the iterator of the block store so that a block is only
+// returned once the ledger has applied its updates to the state and history
+// databases. Consumers can hence query the state at the height of the block
+// they have just received.
+type blocksItr struct {
+	l                  *kvLedger
+	itr                commonledger.ResultsIterator
+	blockNumToRetrieve uint64
+	closeMarker        bool
+	closeMarkerLock    sync.Mutex
+}
+
+func newBlocksItr(l *kvLedger, itr commonledger.ResultsIterator, startBlockNum uint64) *blocksItr {
+	return &blocksItr{l: l, itr: itr, blockNumToRetrieve: startBlockNum}
+}
+
+func (itr *blocksItr) shouldClose() bool {
+	itr.closeMarkerLock.Lock()
+	defer itr.closeMarkerLock.Unlock()
+	return itr.closeMarker
+}
+
+// waitForCommit blocks till the block numbered blockNum is committed
+// and returns false if the iterator or the ledger got closed meanwhile
+func (itr *blocksItr) waitForCommit(blockNum uint64) bool {
+	itr.l.commitCond.L.Lock()
+	defer itr.l.commitCond.L.Unlock()
+	for itr.l.committedHeight <= blockNum {
+		if itr.l.closed || itr.shouldClose() {
+			return false
+		}
+		itr.l.commitCond.Wait()
+	}
+	return true
+}
+
+// Next returns the next committed block, blocking till it becomes available.
+// It returns nil once the iterator is closed
+func (itr *blocksItr) Next() (commonledger.QueryResult, error) {
+	if !itr.waitForCommit(itr.blockNumToRetrieve) {
+		return nil, nil
+	}
+	res, err := itr.itr.Next()
+	if err != nil || res == nil {
+		return res, err
+	}
+	itr.blockNumToRetrieve++
+	return res, nil
+}
+
+// Close releases any resources held by the iterator and unblocks a pending Next
+func (itr *blocksItr) Close() {
+	itr.closeMarkerLock.Lock()
+	itr.closeMarker = true
+	itr.closeMarkerLock.Unlock()
+
+	itr.l.commitCond.L.Lock()
+	itr.l.commitCond.Broadcast()
+	itr.l.commitCond.L.Unlock()
+
+	itr.itr.Close()
+}
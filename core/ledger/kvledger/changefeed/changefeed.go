@@ -0,0 +1,378 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package changefeed
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+var logger = flogging.MustGetLogger("changefeed")
+
+// tailChunkSize is the size of the chunks read backwards from the end of a
+// feed file when looking for its last record
+const tailChunkSize = 4096
+
+// BlockChanges is the record of the change feed for a committed block. The
+// feed of a ledger is a file with one JSON encoded BlockChanges per line, in
+// block order, with a record for every block, even without updates, so that
+// consumers can track the height they are in sync with
+type BlockChanges struct {
+	BlockNum uint64       `json:"blockNum"`
+	Updates  []*KeyChange `json:"updates"`
+}
+
+// KeyChange is a key updated or deleted by a valid transaction of a block
+type KeyChange struct {
+	TxNum     uint64 `json:"txNum"`
+	TxID      string `json:"txId"`
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Value     []byte `json:"value,omitempty"`
+	IsDelete  bool   `json:"isDelete,omitempty"`
+}
+
+// ChangeFeed appends the key updates of the blocks committed to a ledger to
+// its feed file. It is recovered like the history database: blocks committed
+// to the block store but missing from the feed are replayed when the ledger
+// is opened.
+//
+// Once the feed file reaches maxFileSize, it is renamed to <ledgerID>-<number
+// of its last block, on 20 digits>.json and a new feed file is started. Only
+// the maxFiles most recent of the renamed files are kept
+type ChangeFeed struct {
+	dir         string
+	ledgerID    string
+	maxFileSize int64
+	maxFiles    int
+	file        *os.File
+	size        int64
+	lock        sync.Mutex
+
+	// hasRecords tells whether lastBlockNum is the number of the last block in the feed
+	hasRecords   bool
+	lastBlockNum uint64
+
+	// failed is set when a record couldn't be written. The feed is then left
+	// as it is until it is opened again, and the missing blocks replayed
+	failed bool
+}
+
+// Open opens the feed of the ledger in directory dir, creating it if it does not
+// exist. A record left incomplete by a crash is dropped, and its block replayed.
+// The feed file is rotated when it reaches maxFileSize bytes, unless it is 0,
+// and maxFiles of the rotated files are kept, all of them if it is 0
+func Open(dir string, ledgerID string, maxFileSize int64, maxFiles int) (*ChangeFeed, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	feed := &ChangeFeed{dir: dir, ledgerID: ledgerID, maxFileSize: maxFileSize, maxFiles: maxFiles}
+	if err := feed.openFile(); err != nil {
+		return nil, err
+	}
+	if err := feed.loadLastRecord(); err != nil {
+		feed.file.Close()
+		return nil, err
+	}
+	return feed, nil
+}
+
+func (feed *ChangeFeed) openFile() error {
+	file, err := os.OpenFile(filepath.Join(feed.dir, feed.ledgerID+".json"), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	feed.file = file
+	return nil
+}
+
+// loadLastRecord truncates the feed after its last complete record, reads
+// the number of the block of that record and positions the file at its end.
+// When the feed file is empty, the last block is the one of the most recent
+// rotated file
+func (feed *ChangeFeed) loadLastRecord() error {
+	info, err := feed.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	// the feed ends with the last newline, anything after it is an incomplete record
+	end, err := feed.lastIndexOfNewline(info.Size())
+	if err != nil {
+		return err
+	}
+	if end+1 != info.Size() {
+		logger.Warningf("Channel [%s]: Dropping an incomplete change feed record", feed.ledgerID)
+		if err = feed.file.Truncate(end + 1); err != nil {
+			return err
+		}
+	}
+
+	if end >= 0 {
+		start, err := feed.lastIndexOfNewline(end)
+		if err != nil {
+			return err
+		}
+		line := make([]byte, end-start-1)
+		if _, err = feed.file.ReadAt(line, start+1); err != nil {
+			return err
+		}
+		record := &BlockChanges{}
+		if err = json.Unmarshal(line, record); err != nil {
+			return fmt.Errorf("corrupted change feed record for channel %s: %s", feed.ledgerID, err)
+		}
+		feed.hasRecords = true
+		feed.lastBlockNum = record.BlockNum
+	} else {
+		rotated, err := feed.rotatedFiles()
+		if err != nil {
+			return err
+		}
+		if len(rotated) > 0 {
+			feed.hasRecords = true
+			feed.lastBlockNum = rotated[len(rotated)-1]
+		}
+	}
+
+	feed.size = end + 1
+	_, err = feed.file.Seek(end+1, io.SeekStart)
+	return err
+}
+
+// rotatedFiles returns the numbers of the last blocks of the rotated files
+// of the feed, in increasing order
+func (feed *ChangeFeed) rotatedFiles() ([]uint64, error) {
+	names, err := filepath.Glob(filepath.Join(feed.dir, feed.ledgerID+"-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	blockNums := []uint64{}
+	for _, name := range names {
+		// the glob also matches the files of the ledgers whose ID starts with ledgerID-
+		suffix := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(name), feed.ledgerID+"-"), ".json")
+		if len(suffix) != 20 {
+			continue
+		}
+		blockNum, err := strconv.ParseUint(suffix, 10, 64)
+		if err != nil {
+			continue
+		}
+		blockNums = append(blockNums, blockNum)
+	}
+	// the names are sorted by Glob, hence the block numbers, on a fixed number of digits
+	return blockNums, nil
+}
+
+func (feed *ChangeFeed) rotatedFileName(lastBlockNum uint64) string {
+	return filepath.Join(feed.dir, fmt.Sprintf("%s-%020d.json", feed.ledgerID, lastBlockNum))
+}
+
+// rotate renames the feed file after the last block it holds, starts a new
+// one and removes the oldest rotated files beyond maxFiles
+func (feed *ChangeFeed) rotate() error {
+	if err := feed.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(feed.file.Name(), feed.rotatedFileName(feed.lastBlockNum)); err != nil {
+		return err
+	}
+	if err := feed.openFile(); err != nil {
+		return err
+	}
+	feed.size = 0
+	logger.Infof("Channel [%s]: Rotated the change feed after block [%d]", feed.ledgerID, feed.lastBlockNum)
+
+	if feed.maxFiles <= 0 {
+		return nil
+	}
+	rotated, err := feed.rotatedFiles()
+	if err != nil {
+		return err
+	}
+	for len(rotated) > feed.maxFiles {
+		if err = os.Remove(feed.rotatedFileName(rotated[0])); err != nil {
+			return err
+		}
+		rotated = rotated[1:]
+	}
+	return nil
+}
+
+// lastIndexOfNewline returns the offset of the last newline of the feed before
+// offset limit, or -1 if there is none
+func (feed *ChangeFeed) lastIndexOfNewline(limit int64) (int64, error) {
+	chunk := make([]byte, tailChunkSize)
+	for limit > 0 {
+		n := int64(tailChunkSize)
+		if limit < n {
+			n = limit
+		}
+		if _, err := feed.file.ReadAt(chunk[:n], limit-n); err != nil {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(chunk[:n], '\n'); i >= 0 {
+			return limit - n + int64(i), nil
+		}
+		limit -= n
+	}
+	return -1, nil
+}
+
+// Commit appends the record of the block to the feed. Blocks already in the
+// feed are ignored. If the record can't be written, an error is returned and
+// the following blocks are ignored until the feed is opened again
+func (feed *ChangeFeed) Commit(block *common.Block) error {
+	feed.lock.Lock()
+	defer feed.lock.Unlock()
+
+	if feed.failed {
+		return nil
+	}
+
+	blockNum := block.Header.Number
+	if feed.hasRecords && blockNum <= feed.lastBlockNum {
+		logger.Debugf("Channel [%s]: Block [%d] already in the change feed", feed.ledgerID, blockNum)
+		return nil
+	}
+
+	record, err := blockChanges(block)
+	if err != nil {
+		return err
+	}
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	recordBytes = append(recordBytes, '\n')
+	if _, err = feed.file.Write(recordBytes); err == nil {
+		err = feed.file.Sync()
+	}
+	if err != nil {
+		feed.failed = true
+		// an incomplete record is also dropped when the feed is opened again
+		feed.file.Truncate(feed.size)
+		return fmt.Errorf("failed writing block %d to the change feed of channel %s: %s", blockNum, feed.ledgerID, err)
+	}
+
+	feed.size += int64(len(recordBytes))
+	feed.hasRecords = true
+	feed.lastBlockNum = blockNum
+	logger.Debugf("Channel [%s]: Appended [%d] updates of block [%d] to the change feed", feed.ledgerID, len(record.Updates), blockNum)
+
+	if feed.maxFileSize > 0 && feed.size >= feed.maxFileSize {
+		if err = feed.rotate(); err != nil {
+			feed.failed = true
+			return fmt.Errorf("failed rotating the change feed of channel %s: %s", feed.ledgerID, err)
+		}
+	}
+	return nil
+}
+
+// ShouldRecover implements method in interface kvledger.Recoverer
+func (feed *ChangeFeed) ShouldRecover(lastAvailableBlock uint64) (bool, uint64, error) {
+	feed.lock.Lock()
+	defer feed.lock.Unlock()
+	if !feed.hasRecords {
+		return true, 0, nil
+	}
+	return feed.lastBlockNum != lastAvailableBlock, feed.lastBlockNum + 1, nil
+}
+
+// CommitLostBlock implements method in interface kvledger.Recoverer
+func (feed *ChangeFeed) CommitLostBlock(block *common.Block) error {
+	return feed.Commit(block)
+}
+
+// Close closes the feed file
+func (feed *ChangeFeed) Close() {
+	feed.lock.Lock()
+	defer feed.lock.Unlock()
+	feed.file.Close()
+}
+
+// blockChanges extracts the writes of the valid endorser transactions of the block
+func blockChanges(block *common.Block) (*BlockChanges, error) {
+	record := &BlockChanges{BlockNum: block.Header.Number, Updates: []*KeyChange{}}
+
+	var txsFilter util.TxValidationFlags
+	if len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txsFilter = util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	for txNum, envBytes := range block.Data.Data {
+		if len(txsFilter) > txNum && txsFilter.IsInvalid(txNum) {
+			continue
+		}
+
+		env, err := putils.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := putils.GetPayload(env)
+		if err != nil {
+			return nil, err
+		}
+		chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return nil, err
+		}
+		if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+			continue
+		}
+
+		respPayload, err := putils.GetActionFromEnvelope(envBytes)
+		if err != nil {
+			return nil, err
+		}
+		if respPayload == nil {
+			continue
+		}
+		txRWSet := &rwsetutil.TxRwSet{}
+		if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+			return nil, err
+		}
+
+		for _, nsRWSet := range txRWSet.NsRwSets {
+			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+				record.Updates = append(record.Updates, &KeyChange{
+					TxNum:     uint64(txNum),
+					TxID:      chdr.TxId,
+					Namespace: nsRWSet.NameSpace,
+					Key:       kvWrite.Key,
+					Value:     kvWrite.Value,
+					IsDelete:  kvWrite.IsDelete,
+				})
+			}
+		}
+	}
+
+	return record, nil
+}
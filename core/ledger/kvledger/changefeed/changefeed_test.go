@@ -0,0 +1,182 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package changefeed
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+)
+
+const testDir = "/tmp/fabric/ledgertests/kvledger/changefeed"
+
+func simulationResults(t *testing.T, ns string, writes map[string][]byte) []byte {
+	b := rwsetutil.NewRWSetBuilder()
+	for key, value := range writes {
+		b.AddToWriteSet(ns, key, value)
+	}
+	simRes, err := b.GetTxReadWriteSet().ToProtoBytes()
+	testutil.AssertNoError(t, err, "")
+	return simRes
+}
+
+func readRecords(t *testing.T, ledgerID string) []*BlockChanges {
+	return readFile(t, ledgerID+".json")
+}
+
+func readFile(t *testing.T, name string) []*BlockChanges {
+	f, err := os.Open(filepath.Join(testDir, name))
+	testutil.AssertNoError(t, err, "")
+	defer f.Close()
+	var records []*BlockChanges
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		record := &BlockChanges{}
+		testutil.AssertNoError(t, json.Unmarshal(scanner.Bytes(), record), "")
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestChangeFeed(t *testing.T) {
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+
+	feed, err := Open(testDir, "ledger1", 0, 0)
+	testutil.AssertNoError(t, err, "")
+	shouldRecover, from, err := feed.ShouldRecover(0)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, shouldRecover, true)
+	testutil.AssertEquals(t, from, uint64(0))
+
+	bg, gb := testutil.NewBlockGenerator(t, "ledger1", false)
+	testutil.AssertNoError(t, feed.Commit(gb), "")
+
+	// block 1 has a valid transaction updating and deleting keys, and an invalid one
+	block1 := bg.NextBlock([][]byte{
+		simulationResults(t, "ns1", map[string][]byte{"key1": []byte("value1"), "key2": nil}),
+		simulationResults(t, "ns1", map[string][]byte{"key3": []byte("value3")}),
+	})
+	txsFilter := util.NewTxValidationFlags(2)
+	txsFilter.SetFlag(1, peer.TxValidationCode_MVCC_READ_CONFLICT)
+	block1.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
+	testutil.AssertNoError(t, feed.Commit(block1), "")
+
+	// blocks already in the feed are ignored
+	testutil.AssertNoError(t, feed.Commit(block1), "")
+	shouldRecover, _, err = feed.ShouldRecover(1)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, shouldRecover, false)
+	feed.Close()
+
+	records := readRecords(t, "ledger1")
+	testutil.AssertEquals(t, len(records), 2)
+	testutil.AssertEquals(t, records[0].BlockNum, uint64(0))
+	testutil.AssertEquals(t, len(records[0].Updates), 0)
+	testutil.AssertEquals(t, records[1].BlockNum, uint64(1))
+	testutil.AssertEquals(t, len(records[1].Updates), 2)
+	updates := map[string]*KeyChange{}
+	for _, update := range records[1].Updates {
+		testutil.AssertEquals(t, update.Namespace, "ns1")
+		testutil.AssertEquals(t, update.TxNum, uint64(0))
+		testutil.AssertNotEquals(t, update.TxID, "")
+		updates[update.Key] = update
+	}
+	testutil.AssertEquals(t, updates["key1"].Value, []byte("value1"))
+	testutil.AssertEquals(t, updates["key1"].IsDelete, false)
+	testutil.AssertEquals(t, updates["key2"].IsDelete, true)
+
+	// an incomplete record is dropped on reopening, and its block recovered
+	f, err := os.OpenFile(filepath.Join(testDir, "ledger1.json"), os.O_WRONLY|os.O_APPEND, 0644)
+	testutil.AssertNoError(t, err, "")
+	_, err = f.Write([]byte(`{"blockNum":2,"upd`))
+	testutil.AssertNoError(t, err, "")
+	f.Close()
+
+	feed, err = Open(testDir, "ledger1", 0, 0)
+	testutil.AssertNoError(t, err, "")
+	shouldRecover, from, err = feed.ShouldRecover(2)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, shouldRecover, true)
+	testutil.AssertEquals(t, from, uint64(2))
+
+	block2 := bg.NextBlock([][]byte{simulationResults(t, "ns2", map[string][]byte{"key4": []byte("value4")})})
+	testutil.AssertNoError(t, feed.CommitLostBlock(block2), "")
+	feed.Close()
+
+	records = readRecords(t, "ledger1")
+	testutil.AssertEquals(t, len(records), 3)
+	testutil.AssertEquals(t, records[2].BlockNum, uint64(2))
+	testutil.AssertEquals(t, records[2].Updates[0].Namespace, "ns2")
+	testutil.AssertEquals(t, records[2].Updates[0].Key, "key4")
+}
+
+func TestChangeFeedRotation(t *testing.T) {
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+
+	// every record exceeds the size, so that each block gets its own file
+	feed, err := Open(testDir, "ledger1", 1, 2)
+	testutil.AssertNoError(t, err, "")
+	// the files of other ledgers are left alone
+	other, err := Open(testDir, "ledger1-2", 1, 2)
+	testutil.AssertNoError(t, err, "")
+	defer other.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "ledger1", false)
+	testutil.AssertNoError(t, feed.Commit(gb), "")
+	testutil.AssertNoError(t, other.Commit(gb), "")
+	for i := 1; i <= 3; i++ {
+		testutil.AssertNoError(t, feed.Commit(bg.NextBlock([][]byte{simulationResults(t, "ns1", map[string][]byte{"key1": []byte("value1")})})), "")
+	}
+	feed.Close()
+
+	// the two most recent rotated files are kept
+	names, err := filepath.Glob(filepath.Join(testDir, "ledger1-0*.json"))
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, len(names), 2)
+	records := readFile(t, "ledger1-00000000000000000003.json")
+	testutil.AssertEquals(t, len(records), 1)
+	testutil.AssertEquals(t, records[0].BlockNum, uint64(3))
+	testutil.AssertEquals(t, len(readRecords(t, "ledger1")), 0)
+	testutil.AssertEquals(t, len(readFile(t, "ledger1-2-00000000000000000000.json")), 1)
+
+	// the last block of an empty feed file is the one of the last rotated file
+	feed, err = Open(testDir, "ledger1", 1, 2)
+	testutil.AssertNoError(t, err, "")
+	shouldRecover, from, err := feed.ShouldRecover(4)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, shouldRecover, true)
+	testutil.AssertEquals(t, from, uint64(4))
+
+	// once a record fails to be written, the blocks are ignored until the feed is opened again
+	feed.file.Close()
+	testutil.AssertError(t, feed.Commit(bg.NextBlock([][]byte{})), "")
+	testutil.AssertNoError(t, feed.Commit(bg.NextBlock([][]byte{})), "")
+	shouldRecover, from, err = feed.ShouldRecover(5)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, shouldRecover, true)
+	testutil.AssertEquals(t, from, uint64(4))
+}
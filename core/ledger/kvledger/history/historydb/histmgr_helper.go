@@ -24,29 +24,29 @@ import (
 
 var compositeKeySep = []byte{0x00}
 
-//ConstructCompositeHistoryKey builds the History Key of namespace~key~blocknum~trannum
+//ConstructCompositeHistoryKey builds the History Key of namespace~len(key)key~blocknum~trannum
 // using an order preserving encoding so that history query results are ordered by height
 func ConstructCompositeHistoryKey(ns string, key string, blocknum uint64, trannum uint64) []byte {
 
 	var compositeKey []byte
 	compositeKey = append(compositeKey, []byte(ns)...)
 	compositeKey = append(compositeKey, compositeKeySep...)
+	compositeKey = append(compositeKey, util.EncodeOrderPreservingVarUint64(uint64(len(key)))...)
 	compositeKey = append(compositeKey, []byte(key)...)
-	compositeKey = append(compositeKey, compositeKeySep...)
 	compositeKey = append(compositeKey, util.EncodeOrderPreservingVarUint64(blocknum)...)
 	compositeKey = append(compositeKey, util.EncodeOrderPreservingVarUint64(trannum)...)
 
 	return compositeKey
 }
 
-//ConstructPartialCompositeHistoryKey builds a partial History Key namespace~key~
+//ConstructPartialCompositeHistoryKey builds a partial History Key namespace~len(key)key
 // for use in history key range queries
 func ConstructPartialCompositeHistoryKey(ns string, key string, endkey bool) []byte {
 	var compositeKey []byte
 	compositeKey = append(compositeKey, []byte(ns)...)
 	compositeKey = append(compositeKey, compositeKeySep...)
+	compositeKey = append(compositeKey, util.EncodeOrderPreservingVarUint64(uint64(len(key)))...)
 	compositeKey = append(compositeKey, []byte(key)...)
-	compositeKey = append(compositeKey, compositeKeySep...)
 	if endkey {
 		compositeKey = append(compositeKey, []byte{0xff}...)
 	}
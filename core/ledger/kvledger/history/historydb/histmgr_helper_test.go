@@ -17,9 +17,11 @@ limitations under the License.
 package historydb
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util"
 )
 
 var strKeySep = string(compositeKeySep)
@@ -34,15 +36,33 @@ func TestConstructPartialCompositeKey(t *testing.T) {
 	compositeStartKey := ConstructPartialCompositeHistoryKey("ns1", "key1", false)
 	compositeEndKey := ConstructPartialCompositeHistoryKey("ns1", "key1", true)
 
-	testutil.AssertEquals(t, compositeStartKey, []byte("ns1"+strKeySep+"key1"+strKeySep))
-	testutil.AssertEquals(t, compositeEndKey, []byte("ns1"+strKeySep+"key1"+strKeySep+string([]byte{0xff})))
+	keyLen := string(util.EncodeOrderPreservingVarUint64(4))
+	testutil.AssertEquals(t, compositeStartKey, []byte("ns1"+strKeySep+keyLen+"key1"))
+	testutil.AssertEquals(t, compositeEndKey, []byte("ns1"+strKeySep+keyLen+"key1"+string([]byte{0xff})))
 }
 
 func TestSplitCompositeKey(t *testing.T) {
-	compositeFullKey := []byte("ns1" + strKeySep + "key1" + strKeySep + "extra bytes to split")
 	compositePartialKey := ConstructPartialCompositeHistoryKey("ns1", "key1", false)
+	compositeFullKey := append(compositePartialKey, []byte("extra bytes to split")...)
 
 	_, extraBytes := SplitCompositeHistoryKey(compositeFullKey, compositePartialKey)
 	// second position should hold the extra bytes that were split off
 	testutil.AssertEquals(t, extraBytes, []byte("extra bytes to split"))
 }
+
+func TestCompositeKeyNoFalseMatches(t *testing.T) {
+	compositeStartKey := ConstructPartialCompositeHistoryKey("ns1", "key1", false)
+	compositeEndKey := ConstructPartialCompositeHistoryKey("ns1", "key1", true)
+
+	// the history of key1 is in the range of its partial keys
+	compositeKey := ConstructCompositeHistoryKey("ns1", "key1", 1, 1)
+	testutil.AssertEquals(t, bytes.Compare(compositeKey, compositeStartKey) >= 0 && bytes.Compare(compositeKey, compositeEndKey) < 0, true)
+
+	// the history of the keys starting with key1 and the separator, e.g. composite keys, is not
+	for _, key := range []string{"key1" + strKeySep + "suffix", "key1" + strKeySep, "key1suffix"} {
+		compositeKey = ConstructCompositeHistoryKey("ns1", key, 1, 1)
+		if bytes.Compare(compositeKey, compositeStartKey) >= 0 && bytes.Compare(compositeKey, compositeEndKey) < 0 {
+			t.Fatalf("the history of key %q should not match the history of key1", key)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package historydb
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// HistoryDBProviderFactory constructs a HistoryDBProvider. It is invoked once,
+// when the ledger provider is constructed, and is expected to read its own settings
+type HistoryDBProviderFactory func() (HistoryDBProvider, error)
+
+var providerFactories = make(map[string]HistoryDBProviderFactory)
+var providerFactoriesLock sync.RWMutex
+
+// RegisterHistoryDBProvider registers the factory of a history database under a name,
+// so that the history database can be selected with the setting ledger.history.historyDatabase.
+// Alternative history databases are expected to register themselves before the ledger
+// provider is constructed, and to build their keys with the helpers of this package
+func RegisterHistoryDBProvider(name string, factory HistoryDBProviderFactory) error {
+	if name == "" || factory == nil {
+		return fmt.Errorf("A history database needs a name and a factory to be registered")
+	}
+
+	providerFactoriesLock.Lock()
+	defer providerFactoriesLock.Unlock()
+	if _, exists := providerFactories[name]; exists {
+		return fmt.Errorf("History database %s is already registered", name)
+	}
+	providerFactories[name] = factory
+	return nil
+}
+
+// NewHistoryDBProvider constructs the HistoryDBProvider of the history database registered under name
+func NewHistoryDBProvider(name string) (HistoryDBProvider, error) {
+	providerFactoriesLock.RLock()
+	factory, exists := providerFactories[name]
+	providerFactoriesLock.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("Unknown history database %s, registered history databases are %v", name, RegisteredHistoryDBProviders())
+	}
+	return factory()
+}
+
+// RegisteredHistoryDBProviders returns the sorted names of the registered history databases
+func RegisteredHistoryDBProviders() []string {
+	providerFactoriesLock.RLock()
+	defer providerFactoriesLock.RUnlock()
+	names := []string{}
+	for name := range providerFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package historydb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+)
+
+type mockHistoryDBProvider struct {
+	HistoryDBProvider
+}
+
+func TestRegisterHistoryDBProvider(t *testing.T) {
+	provider := &mockHistoryDBProvider{}
+	err := RegisterHistoryDBProvider("mockhistorydb", func() (HistoryDBProvider, error) { return provider, nil })
+	testutil.AssertNoError(t, err, "")
+	err = RegisterHistoryDBProvider("faultyhistorydb", func() (HistoryDBProvider, error) { return nil, errors.New("cannot connect") })
+	testutil.AssertNoError(t, err, "")
+
+	historydbProvider, err := NewHistoryDBProvider("mockhistorydb")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertSame(t, historydbProvider, provider)
+
+	_, err = NewHistoryDBProvider("faultyhistorydb")
+	testutil.AssertError(t, err, "Construction errors should be returned")
+
+	_, err = NewHistoryDBProvider("unknownhistorydb")
+	testutil.AssertError(t, err, "Unknown history databases should not be constructed")
+
+	err = RegisterHistoryDBProvider("mockhistorydb", func() (HistoryDBProvider, error) { return provider, nil })
+	testutil.AssertError(t, err, "A history database should not be registered twice")
+	err = RegisterHistoryDBProvider("nilfactoryhistorydb", nil)
+	testutil.AssertError(t, err, "A history database should not be registered without factory")
+
+	testutil.AssertEquals(t, RegisteredHistoryDBProviders(), []string{"faultyhistorydb", "mockhistorydb"})
+}
@@ -19,11 +19,14 @@ package kvledger
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/changefeed"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
@@ -42,11 +45,22 @@ type kvLedger struct {
 	blockStore blkstorage.BlockStore
 	txtmgmt    txmgr.TxMgr
 	historyDB  historydb.HistoryDB
+	// changeFeed is nil unless the change feed is enabled
+	changeFeed *changefeed.ChangeFeed
+
+	// commitLock serializes the commits, and Close with them
+	commitLock sync.Mutex
+
+	// committedHeight is the number of blocks whose state and history updates
+	// have been fully written; blocks iterators wait on commitCond for it to grow
+	commitCond      *sync.Cond
+	committedHeight uint64
+	closed          bool
 }
 
 // NewKVLedger constructs new `KVLedger`
 func newKVLedger(ledgerID string, blockStore blkstorage.BlockStore,
-	versionedDB statedb.VersionedDB, historyDB historydb.HistoryDB) (*kvLedger, error) {
+	versionedDB statedb.VersionedDB, historyDB historydb.HistoryDB, changeFeed *changefeed.ChangeFeed) (*kvLedger, error) {
 
 	logger.Debugf("Creating KVLedger ledgerID=%s: ", ledgerID)
 
@@ -56,17 +70,24 @@ func newKVLedger(ledgerID string, blockStore blkstorage.BlockStore,
 
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying
 	// id store, blockstore, txmgr (state database), history database
-	l := &kvLedger{ledgerID, blockStore, txmgmt, historyDB}
+	l := &kvLedger{ledgerID: ledgerID, blockStore: blockStore, txtmgmt: txmgmt, historyDB: historyDB,
+		changeFeed: changeFeed, commitCond: sync.NewCond(&sync.Mutex{})}
 
-	//Recover both state DB and history DB if they are out of sync with block storage
+	//Recover the state DB, the history DB and the change feed if they are out of sync with block storage
 	if err := l.recoverDBs(); err != nil {
 		panic(fmt.Errorf(`Error during state DB recovery:%s`, err))
 	}
 
+	info, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	l.committedHeight = info.Height
+
 	return l, nil
 }
 
-//Recover the state database and history database (if exist)
+//Recover the state database, history database and change feed (if exist)
 //by recommitting last valid blocks
 func (l *kvLedger) recoverDBs() error {
 	logger.Debugf("Entering recoverDB()")
@@ -78,6 +99,9 @@ func (l *kvLedger) recoverDBs() error {
 	}
 	lastAvailableBlockNum := info.Height - 1
 	recoverables := []recoverable{l.txtmgmt, l.historyDB}
+	if l.changeFeed != nil {
+		recoverables = append(recoverables, l.changeFeed)
+	}
 	recoverers := []*recoverer{}
 	for _, recoverable := range recoverables {
 		recoverFlag, firstBlockNum, err := recoverable.ShouldRecover(lastAvailableBlockNum)
@@ -91,25 +115,27 @@ func (l *kvLedger) recoverDBs() error {
 	if len(recoverers) == 0 {
 		return nil
 	}
-	if len(recoverers) == 1 {
-		return l.recommitLostBlocks(recoverers[0].firstBlockNum, lastAvailableBlockNum, recoverers[0].recoverable)
-	}
 
-	// both dbs need to be recovered
-	if recoverers[0].firstBlockNum > recoverers[1].firstBlockNum {
-		// swap (put the lagger db at 0 index)
-		recoverers[0], recoverers[1] = recoverers[1], recoverers[0]
-	}
-	if recoverers[0].firstBlockNum != recoverers[1].firstBlockNum {
-		// bring the lagger db equal to the the other db
-		if err := l.recommitLostBlocks(recoverers[0].firstBlockNum, recoverers[1].firstBlockNum-1,
-			recoverers[0].recoverable); err != nil {
+	// put the most lagging db first, then bring the lagging dbs up to the next
+	// one, so that each block is read once and recommitted to all the dbs missing it
+	sort.Sort(recoverersByFirstBlock(recoverers))
+	for i := range recoverers {
+		lastBlockNum := lastAvailableBlockNum
+		if i < len(recoverers)-1 {
+			if recoverers[i].firstBlockNum == recoverers[i+1].firstBlockNum {
+				continue
+			}
+			lastBlockNum = recoverers[i+1].firstBlockNum - 1
+		}
+		lagging := make([]recoverable, i+1)
+		for j := range lagging {
+			lagging[j] = recoverers[j].recoverable
+		}
+		if err := l.recommitLostBlocks(recoverers[i].firstBlockNum, lastBlockNum, lagging...); err != nil {
 			return err
 		}
 	}
-	// get both the db upto block storage
-	return l.recommitLostBlocks(recoverers[1].firstBlockNum, lastAvailableBlockNum,
-		recoverers[0].recoverable, recoverers[1].recoverable)
+	return nil
 }
 
 //recommitLostBlocks retrieves blocks in specified range and commit the write set to either
@@ -161,11 +187,14 @@ func (l *kvLedger) GetBlockByNumber(blockNumber uint64) (*common.Block, error) {
 }
 
 // GetBlocksIterator returns an iterator that starts from `startBlockNumber`(inclusive).
-// The iterator is a blocking iterator i.e., it blocks till the next block gets available in the ledger
-// ResultsIterator contains type BlockHolder
+// The iterator is a blocking iterator i.e., it blocks till the next block gets committed to the ledger,
+// including its state and history updates. ResultsIterator contains type BlockHolder
 func (l *kvLedger) GetBlocksIterator(startBlockNumber uint64) (commonledger.ResultsIterator, error) {
-	return l.blockStore.RetrieveBlocks(startBlockNumber)
-
+	itr, err := l.blockStore.RetrieveBlocks(startBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return newBlocksItr(l, itr, startBlockNumber), nil
 }
 
 // GetBlockByHash returns a block given it's hash
@@ -207,11 +236,24 @@ func (l *kvLedger) NewHistoryQueryExecutor() (ledger.HistoryQueryExecutor, error
 	return l.historyDB.NewHistoryQueryExecutor(l.blockStore)
 }
 
+// SetCapabilityChecker sets the function telling whether the channel requires
+// a capability of its peers, which the validation of the next blocks depends on
+func (l *kvLedger) SetCapabilityChecker(hasCapability func(name string) bool) {
+	l.commitLock.Lock()
+	defer l.commitLock.Unlock()
+	l.txtmgmt.SetCapabilityChecker(hasCapability)
+}
+
 // Commit commits the valid block (returned in the method RemoveInvalidTransactionsAndPrepare) and related state changes
 func (l *kvLedger) Commit(block *common.Block) error {
 	var err error
 	blockNo := block.Header.Number
 
+	l.commitLock.Lock()
+	defer l.commitLock.Unlock()
+	if l.closed {
+		return ErrLedgerClosed
+	}
 	logger.Debugf("Channel [%s]: Validating block [%d]", l.ledgerID, blockNo)
 	err = l.txtmgmt.ValidateAndPrepare(block, true)
 	if err != nil {
@@ -237,11 +279,36 @@ func (l *kvLedger) Commit(block *common.Block) error {
 		}
 	}
 
+	if l.changeFeed != nil {
+		logger.Debugf("Channel [%s]: Appending block [%d] transactions to the change feed", l.ledgerID, blockNo)
+		// the change feed is not used by the peer, its missing blocks are
+		// appended when the ledger is opened again
+		if err := l.changeFeed.Commit(block); err != nil {
+			logger.Errorf("Channel [%s]: Error during commit to change feed, it is stopped until the peer restarts: %s", l.ledgerID, err)
+		}
+	}
+
+	l.commitCond.L.Lock()
+	l.committedHeight = blockNo + 1
+	l.commitCond.Broadcast()
+	l.commitCond.L.Unlock()
+
 	return nil
 }
 
-// Close closes `KVLedger`
+// Close closes `KVLedger`. It waits for the block being committed, if any, to
+// be written to all the databases, so that closing never leaves them half updated
 func (l *kvLedger) Close() {
+	l.commitLock.Lock()
+	defer l.commitLock.Unlock()
+
+	l.commitCond.L.Lock()
+	l.closed = true
+	l.commitCond.Broadcast()
+	l.commitCond.L.Unlock()
 	l.blockStore.Shutdown()
 	l.txtmgmt.Shutdown()
+	if l.changeFeed != nil {
+		l.changeFeed.Close()
+	}
 }
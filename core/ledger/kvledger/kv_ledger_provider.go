@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/changefeed"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb/historyleveldb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
@@ -44,11 +45,47 @@ var (
 	ErrNonExistingLedgerID = errors.New("LedgerID does not exist")
 	// ErrLedgerNotOpened is thrown by a CloseLedger call if a ledger with the given id has not been opened
 	ErrLedgerNotOpened = errors.New("Ledger is not opened yet")
+	// ErrLedgerClosed is thrown by a Commit call on a ledger which has been closed
+	ErrLedgerClosed = errors.New("Ledger is closed")
 
 	underConstructionLedgerKey = []byte("underConstructionLedgerKey")
 	ledgerKeyPrefix            = []byte("l")
+	ledgerKeyEnd               = []byte("m")
+	dataFormatKey              = []byte("f")
+
+	// currentDataFormat is the format of the ledger data written by this version of the peer.
+	// Format 2.0 introduced the length-prefixed keys of the history database
+	currentDataFormat = []byte("2.0")
+	// preVersioningDataFormat is the format of the ledger data written before the format was recorded
+	preVersioningDataFormat = []byte("1.0")
 )
 
+// register the state and history databases shipped with the peer
+func init() {
+	statedb.RegisterVersionedDBProvider("goleveldb", func() (statedb.VersionedDBProvider, error) {
+		return stateleveldb.NewVersionedDBProvider(), nil
+	})
+	statedb.RegisterVersionedDBProvider("CouchDB", func() (statedb.VersionedDBProvider, error) {
+		return statecouchdb.NewVersionedDBProvider()
+	})
+	historydb.RegisterHistoryDBProvider("goleveldb", func() (historydb.HistoryDBProvider, error) {
+		return historyleveldb.NewHistoryDBProvider(), nil
+	})
+}
+
+// ErrDataFormatMismatch is returned when the ledger data is in a format
+// other than the one of this version of the peer
+type ErrDataFormatMismatch struct {
+	Format         string
+	ExpectedFormat string
+}
+
+func (e *ErrDataFormatMismatch) Error() string {
+	return fmt.Sprintf("The ledger data is in format [%s] while this peer requires format [%s]. "+
+		"Stop the peer, back up the directory %s, then run 'peer node upgrade-dbs' before restarting the peer",
+		e.Format, e.ExpectedFormat, ledgerconfig.GetRootPath())
+}
+
 // Provider implements interface ledger.PeerLedgerProvider
 type Provider struct {
 	idStore            *idStore
@@ -66,6 +103,12 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 	// Initialize the ID store (inventory of chainIds/ledgerIds)
 	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
 
+	// Refuse to open ledger data written in a format other than the current one
+	if err := idStore.checkDataFormat(); err != nil {
+		idStore.close()
+		return nil, err
+	}
+
 	// Initialize the block storage
 	attrsToIndex := []blkstorage.IndexableAttr{
 		blkstorage.IndexableAttrBlockHash,
@@ -77,26 +120,24 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 	}
 	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
 	blockStoreProvider := fsblkstorage.NewProvider(
-		fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize()),
+		fsblkstorage.NewConfWithLedgerDirs(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize(), ledgerconfig.GetChannelBlockStorePath),
 		indexConfig)
 
 	// Initialize the versioned database (state database)
-	var vdbProvider statedb.VersionedDBProvider
-	if !ledgerconfig.IsCouchDBEnabled() {
-		logger.Debug("Constructing leveldb VersionedDBProvider")
-		vdbProvider = stateleveldb.NewVersionedDBProvider()
-	} else {
-		logger.Debug("Constructing CouchDB VersionedDBProvider")
-		var err error
-		vdbProvider, err = statecouchdb.NewVersionedDBProvider()
-		if err != nil {
-			return nil, err
-		}
+	stateDatabase := ledgerconfig.GetStateDatabase()
+	logger.Debugf("Constructing %s VersionedDBProvider", stateDatabase)
+	vdbProvider, err := statedb.NewVersionedDBProvider(stateDatabase)
+	if err != nil {
+		return nil, err
 	}
 
 	// Initialize the history database (index for history of values by key)
-	var historydbProvider historydb.HistoryDBProvider
-	historydbProvider = historyleveldb.NewHistoryDBProvider()
+	historyDatabase := ledgerconfig.GetHistoryDatabase()
+	logger.Debugf("Constructing %s HistoryDBProvider", historyDatabase)
+	historydbProvider, err := historydb.NewHistoryDBProvider(historyDatabase)
+	if err != nil {
+		return nil, err
+	}
 
 	logger.Info("ledger provider Initialized")
 	provider := &Provider{idStore, blockStoreProvider, vdbProvider, historydbProvider}
@@ -172,9 +213,18 @@ func (provider *Provider) openInternal(ledgerID string) (ledger.PeerLedger, erro
 		return nil, err
 	}
 
+	// Open the change feed of the chain/ledger if enabled
+	var changeFeed *changefeed.ChangeFeed
+	if ledgerconfig.IsChangeFeedEnabled() {
+		if changeFeed, err = changefeed.Open(ledgerconfig.GetChangeFeedPath(), ledgerID,
+			ledgerconfig.GetChangeFeedMaxFileSize(), ledgerconfig.GetChangeFeedMaxFiles()); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying data stores
-	// (id store, blockstore, state database, history database)
-	l, err := newKVLedger(ledgerID, blockStore, vDB, historyDB)
+	// (id store, blockstore, state database, history database, change feed)
+	l, err := newKVLedger(ledgerID, blockStore, vDB, historyDB, changeFeed)
 	if err != nil {
 		return nil, err
 	}
@@ -315,17 +365,48 @@ func (s *idStore) ledgerIDExists(ledgerID string) (bool, error) {
 
 func (s *idStore) getAllLedgerIds() ([]string, error) {
 	var ids []string
-	itr := s.db.GetIterator(nil, nil)
-	itr.First()
-	for itr.Valid() {
-		if bytes.Equal(itr.Key(), underConstructionLedgerKey) {
-			continue
-		}
+	itr := s.db.GetIterator(ledgerKeyPrefix, ledgerKeyEnd)
+	defer itr.Release()
+	for itr.Next() {
 		id := string(s.decodeLedgerID(itr.Key()))
 		ids = append(ids, id)
-		itr.Next()
 	}
-	return ids, nil
+	return ids, itr.Error()
+}
+
+// getDataFormat returns the format of the ledger data, the format before
+// the format was recorded if the store holds ledgers, or nil for a new store
+func (s *idStore) getDataFormat() ([]byte, error) {
+	format, err := s.db.Get(dataFormatKey)
+	if err != nil || format != nil {
+		return format, err
+	}
+	itr := s.db.GetIterator(nil, nil)
+	defer itr.Release()
+	if itr.Next() {
+		return preVersioningDataFormat, nil
+	}
+	return nil, itr.Error()
+}
+
+func (s *idStore) setDataFormat(format []byte) error {
+	return s.db.Put(dataFormatKey, format, true)
+}
+
+// checkDataFormat records the current format in a new store and returns
+// an error if the ledger data is in another format
+func (s *idStore) checkDataFormat() error {
+	format, err := s.getDataFormat()
+	if err != nil {
+		return err
+	}
+	if format == nil {
+		return s.setDataFormat(currentDataFormat)
+	}
+	if !bytes.Equal(format, currentDataFormat) {
+		return &ErrDataFormatMismatch{Format: string(format), ExpectedFormat: string(currentDataFormat)}
+	}
+	return nil
 }
 
 func (s *idStore) close() {
@@ -17,16 +17,23 @@ limitations under the License.
 package kvledger
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 
+	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/changefeed"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -102,6 +109,131 @@ func TestKVLedgerBlockStorage(t *testing.T) {
 
 }
 
+func TestKVLedgerBlocksIterator(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	defer ledger.Close()
+
+	itr, err := ledger.GetBlocksIterator(1)
+	testutil.AssertNoError(t, err, "")
+	defer itr.Close()
+
+	received := make(chan *common.Block)
+	go func() {
+		for {
+			res, err := itr.Next()
+			if err != nil || res == nil {
+				close(received)
+				return
+			}
+			received <- res.(commonledger.BlockHolder).GetBlock()
+		}
+	}()
+
+	for i := 1; i <= 3; i++ {
+		simulator, _ := ledger.NewTxSimulator()
+		simulator.SetState("ns1", "key1", []byte(fmt.Sprintf("value%d", i)))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		ledger.Commit(bg.NextBlock([][]byte{simRes}))
+
+		block := <-received
+		testutil.AssertEquals(t, block.Header.Number, uint64(i))
+
+		// the state of a received block is already committed
+		qe, _ := ledger.NewQueryExecutor()
+		value, _ := qe.GetState("ns1", "key1")
+		qe.Done()
+		testutil.AssertEquals(t, value, []byte(fmt.Sprintf("value%d", i)))
+	}
+
+	// closing the iterator unblocks the pending Next
+	itr.Close()
+	_, ok := <-received
+	testutil.AssertEquals(t, ok, false)
+}
+
+func TestKVLedgerCommitAfterClose(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	block1 := bg.NextBlock([][]byte{simRes})
+	testutil.AssertNoError(t, ledger.Commit(block1), "")
+	ledger.Close()
+
+	// a block committed once the ledger is closed is rejected, not written to closed databases
+	testutil.AssertEquals(t, ledger.Commit(bg.NextBlock([][]byte{simRes})), ErrLedgerClosed)
+
+	ledger, _ = provider.Open("testLedger")
+	defer ledger.Close()
+	bcInfo, _ := ledger.GetBlockchainInfo()
+	testutil.AssertEquals(t, bcInfo.Height, uint64(2))
+}
+
+func TestKVLedgerChangeFeed(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	defer viper.Set("ledger.changeFeed.enabled", false)
+
+	// commit blocks without change feed
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	for i := 1; i <= 2; i++ {
+		simulator, _ := ledger.NewTxSimulator()
+		simulator.SetState("ns1", "key1", []byte(fmt.Sprintf("value%d", i)))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		testutil.AssertNoError(t, ledger.Commit(bg.NextBlock([][]byte{simRes})), "")
+	}
+	ledger.Close()
+	provider.Close()
+
+	// enabling the change feed builds it from the first block
+	viper.Set("ledger.changeFeed.enabled", true)
+	provider, _ = NewProvider()
+	ledger, _ = provider.Open("testLedger")
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.DeleteState("ns1", "key1")
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	testutil.AssertNoError(t, ledger.Commit(bg.NextBlock([][]byte{simRes})), "")
+	ledger.Close()
+	provider.Close()
+
+	f, err := os.Open(filepath.Join(ledgerconfig.GetChangeFeedPath(), "testLedger.json"))
+	testutil.AssertNoError(t, err, "")
+	defer f.Close()
+	var records []*changefeed.BlockChanges
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		record := &changefeed.BlockChanges{}
+		testutil.AssertNoError(t, json.Unmarshal(scanner.Bytes(), record), "")
+		records = append(records, record)
+	}
+	testutil.AssertEquals(t, len(records), 4)
+	for i, record := range records {
+		testutil.AssertEquals(t, record.BlockNum, uint64(i))
+	}
+	testutil.AssertEquals(t, len(records[0].Updates), 0)
+	testutil.AssertEquals(t, records[2].Updates[0].Key, "key1")
+	testutil.AssertEquals(t, records[2].Updates[0].Value, []byte("value2"))
+	testutil.AssertEquals(t, records[3].Updates[0].IsDelete, true)
+}
+
 func TestKVLedgerDBRecovery(t *testing.T) {
 	ledgertestutil.SetupCoreYAMLConfig()
 	env := newTestEnv(t)
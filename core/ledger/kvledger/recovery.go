@@ -31,3 +31,10 @@ type recoverer struct {
 	firstBlockNum uint64
 	recoverable   recoverable
 }
+
+// recoverersByFirstBlock sorts recoverers by the block to start recovery from
+type recoverersByFirstBlock []*recoverer
+
+func (r recoverersByFirstBlock) Len() int           { return len(r) }
+func (r recoverersByFirstBlock) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r recoverersByFirstBlock) Less(i, j int) bool { return r[i].firstBlockNum < r[j].firstBlockNum }
@@ -28,6 +28,7 @@ var logger = flogging.MustGetLogger("rwset")
 type nsRWs struct {
 	readMap          map[string]*kvrwset.KVRead //for mvcc validation
 	writeMap         map[string]*kvrwset.KVWrite
+	metadataWriteMap map[string]*kvrwset.KVMetadataWrite
 	rangeQueriesMap  map[rangeQueryKey]*kvrwset.RangeQueryInfo //for phantom read validation
 	rangeQueriesKeys []rangeQueryKey
 }
@@ -35,6 +36,7 @@ type nsRWs struct {
 func newNsRWs() *nsRWs {
 	return &nsRWs{make(map[string]*kvrwset.KVRead),
 		make(map[string]*kvrwset.KVWrite),
+		make(map[string]*kvrwset.KVMetadataWrite),
 		make(map[rangeQueryKey]*kvrwset.RangeQueryInfo), nil}
 }
 
@@ -66,6 +68,13 @@ func (rws *RWSetBuilder) AddToWriteSet(ns string, key string, value []byte) {
 	nsRWs.writeMap[key] = newKVWrite(key, value)
 }
 
+// AddToMetadataWriteSet adds the metadata of a key to the metadata write-set.
+// The metadata replaces the whole metadata of the key, an empty one deletes it
+func (rws *RWSetBuilder) AddToMetadataWriteSet(ns string, key string, metadata map[string][]byte) {
+	nsRWs := rws.getOrCreateNsRW(ns)
+	nsRWs.metadataWriteMap[key] = NewKVMetadataWrite(key, metadata)
+}
+
 // AddToRangeQuerySet adds a range query info for performing phantom read validation
 func (rws *RWSetBuilder) AddToRangeQuerySet(ns string, rqi *kvrwset.RangeQueryInfo) {
 	nsRWs := rws.getOrCreateNsRW(ns)
@@ -99,13 +108,20 @@ func (rws *RWSetBuilder) GetTxReadWriteSet() *TxRwSet {
 			writes = append(writes, nsReadWriteMap.writeMap[key])
 		}
 
+		//add metadata write set
+		var metadataWrites []*kvrwset.KVMetadataWrite
+		sortedMetadataWriteKeys := util.GetSortedKeys(nsReadWriteMap.metadataWriteMap)
+		for _, key := range sortedMetadataWriteKeys {
+			metadataWrites = append(metadataWrites, nsReadWriteMap.metadataWriteMap[key])
+		}
+
 		//add range query info
 		var rangeQueriesInfo []*kvrwset.RangeQueryInfo
 		rangeQueriesMap := nsReadWriteMap.rangeQueriesMap
 		for _, key := range nsReadWriteMap.rangeQueriesKeys {
 			rangeQueriesInfo = append(rangeQueriesInfo, rangeQueriesMap[key])
 		}
-		kvRWs := &kvrwset.KVRWSet{Reads: reads, Writes: writes, RangeQueriesInfo: rangeQueriesInfo}
+		kvRWs := &kvrwset.KVRWSet{Reads: reads, Writes: writes, MetadataWrites: metadataWrites, RangeQueriesInfo: rangeQueriesInfo}
 		nsRWs := &NsRwSet{ns, kvRWs}
 		txRWSet.NsRwSets = append(txRWSet.NsRwSets, nsRWs)
 	}
@@ -67,3 +67,25 @@ func TestRWSetHolder(t *testing.T) {
 	t.Logf("Actual=%s\n Expected=%s", txRWSet, expectedTxRWSet)
 	testutil.AssertEquals(t, txRWSet, expectedTxRWSet)
 }
+
+func TestRWSetHolderMetadataWrites(t *testing.T) {
+	rwSetBuilder := NewRWSetBuilder()
+	rwSetBuilder.AddToMetadataWriteSet("ns1", "key2", map[string][]byte{"name2": []byte("value2"), "name1": []byte("value1")})
+	rwSetBuilder.AddToMetadataWriteSet("ns1", "key1", nil)
+
+	txRWSet := rwSetBuilder.GetTxReadWriteSet()
+
+	expectedTxRWSet := &TxRwSet{[]*NsRwSet{{"ns1", &kvrwset.KVRWSet{
+		MetadataWrites: []*kvrwset.KVMetadataWrite{
+			{Key: "key1"},
+			{Key: "key2", Entries: []*kvrwset.KVMetadataEntry{
+				{Name: "name1", Value: []byte("value1")},
+				{Name: "name2", Value: []byte("value2")}}}}}}}}
+	testutil.AssertEquals(t, txRWSet, expectedTxRWSet)
+
+	encodedMetadata, err := EncodeMetadata(txRWSet.NsRwSets[0].KvRwSet.MetadataWrites[1].Entries)
+	testutil.AssertNoError(t, err, "")
+	metadata, err := DecodeMetadata(encodedMetadata)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, metadata, map[string][]byte{"name1": []byte("value1"), "name2": []byte("value2")})
+}
@@ -19,6 +19,7 @@ package rwsetutil
 import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 )
@@ -101,3 +102,34 @@ func newProtoVersion(height *version.Height) *kvrwset.Version {
 func newKVWrite(key string, value []byte) *kvrwset.KVWrite {
 	return &kvrwset.KVWrite{Key: key, IsDelete: value == nil, Value: value}
 }
+
+// NewKVMetadataWrite helps constructing proto message kvrwset.KVMetadataWrite,
+// with the entries sorted by name so that all endorsers produce the same bytes
+func NewKVMetadataWrite(key string, metadata map[string][]byte) *kvrwset.KVMetadataWrite {
+	metadataWrite := &kvrwset.KVMetadataWrite{Key: key}
+	for _, name := range util.GetSortedKeys(metadata) {
+		metadataWrite.Entries = append(metadataWrite.Entries, &kvrwset.KVMetadataEntry{Name: name, Value: metadata[name]})
+	}
+	return metadataWrite
+}
+
+// EncodeMetadata serializes the entries of the metadata of a key, as stored in the state database
+func EncodeMetadata(entries []*kvrwset.KVMetadataEntry) ([]byte, error) {
+	return proto.Marshal(&kvrwset.KVMetadataWrite{Entries: entries})
+}
+
+// DecodeMetadata deserializes the metadata of a key stored in the state database
+func DecodeMetadata(encodedMetadata []byte) (map[string][]byte, error) {
+	if encodedMetadata == nil {
+		return nil, nil
+	}
+	metadataWrite := &kvrwset.KVMetadataWrite{}
+	if err := proto.Unmarshal(encodedMetadata, metadataWrite); err != nil {
+		return nil, err
+	}
+	metadata := make(map[string][]byte)
+	for _, entry := range metadataWrite.Entries {
+		metadata[entry.Name] = entry.Value
+	}
+	return metadata, nil
+}
@@ -41,18 +41,21 @@ func TestTxRWSetMarshalUnmarshal(t *testing.T) {
 			[]*kvrwset.KVRead{&kvrwset.KVRead{Key: "key1", Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
 			[]*kvrwset.RangeQueryInfo{rqi1},
 			[]*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key2", IsDelete: false, Value: []byte("value2")}},
+			[]*kvrwset.KVMetadataWrite{NewKVMetadataWrite("key2", map[string][]byte{"name": []byte("value")})},
 		}},
 
 		&NsRwSet{"ns2", &kvrwset.KVRWSet{
 			[]*kvrwset.KVRead{&kvrwset.KVRead{Key: "key3", Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
 			[]*kvrwset.RangeQueryInfo{rqi2},
 			[]*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key3", IsDelete: false, Value: []byte("value3")}},
+			nil,
 		}},
 
 		&NsRwSet{"ns3", &kvrwset.KVRWSet{
 			[]*kvrwset.KVRead{&kvrwset.KVRead{Key: "key4", Version: &kvrwset.Version{BlockNum: 1, TxNum: 1}}},
 			nil,
 			[]*kvrwset.KVWrite{&kvrwset.KVWrite{Key: "key4", IsDelete: false, Value: []byte("value4")}},
+			nil,
 		}},
 	}
 
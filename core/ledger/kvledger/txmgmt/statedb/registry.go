@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statedb
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// VersionedDBProviderFactory constructs a VersionedDBProvider. It is invoked once,
+// when the ledger provider is constructed, and is expected to read its own settings
+type VersionedDBProviderFactory func() (VersionedDBProvider, error)
+
+var providerFactories = make(map[string]VersionedDBProviderFactory)
+var providerFactoriesLock sync.RWMutex
+
+// RegisterVersionedDBProvider registers the factory of a state database under a name,
+// so that the state database can be selected with the setting ledger.state.stateDatabase.
+// Alternative state databases are expected to register themselves before the ledger
+// provider is constructed, typically in the init function of their package
+func RegisterVersionedDBProvider(name string, factory VersionedDBProviderFactory) error {
+	if name == "" || factory == nil {
+		return fmt.Errorf("A state database needs a name and a factory to be registered")
+	}
+
+	providerFactoriesLock.Lock()
+	defer providerFactoriesLock.Unlock()
+	if _, exists := providerFactories[name]; exists {
+		return fmt.Errorf("State database %s is already registered", name)
+	}
+	providerFactories[name] = factory
+	return nil
+}
+
+// NewVersionedDBProvider constructs the VersionedDBProvider of the state database registered under name
+func NewVersionedDBProvider(name string) (VersionedDBProvider, error) {
+	providerFactoriesLock.RLock()
+	factory, exists := providerFactories[name]
+	providerFactoriesLock.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("Unknown state database %s, registered state databases are %v", name, RegisteredVersionedDBProviders())
+	}
+	return factory()
+}
+
+// RegisteredVersionedDBProviders returns the sorted names of the registered state databases
+func RegisteredVersionedDBProviders() []string {
+	providerFactoriesLock.RLock()
+	defer providerFactoriesLock.RUnlock()
+	names := []string{}
+	for name := range providerFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statedb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+)
+
+type mockVersionedDBProvider struct {
+	VersionedDBProvider
+}
+
+func TestRegisterVersionedDBProvider(t *testing.T) {
+	provider := &mockVersionedDBProvider{}
+	err := RegisterVersionedDBProvider("mockdb", func() (VersionedDBProvider, error) { return provider, nil })
+	testutil.AssertNoError(t, err, "")
+	err = RegisterVersionedDBProvider("faultydb", func() (VersionedDBProvider, error) { return nil, errors.New("cannot connect") })
+	testutil.AssertNoError(t, err, "")
+
+	vdbProvider, err := NewVersionedDBProvider("mockdb")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertSame(t, vdbProvider, provider)
+
+	_, err = NewVersionedDBProvider("faultydb")
+	testutil.AssertError(t, err, "Construction errors should be returned")
+
+	_, err = NewVersionedDBProvider("unknowndb")
+	testutil.AssertError(t, err, "Unknown state databases should not be constructed")
+
+	err = RegisterVersionedDBProvider("mockdb", func() (VersionedDBProvider, error) { return provider, nil })
+	testutil.AssertError(t, err, "A state database should not be registered twice")
+	err = RegisterVersionedDBProvider("nilfactorydb", nil)
+	testutil.AssertError(t, err, "A state database should not be registered without factory")
+
+	testutil.AssertEquals(t, RegisteredVersionedDBProviders(), []string{"faultydb", "mockdb"})
+}
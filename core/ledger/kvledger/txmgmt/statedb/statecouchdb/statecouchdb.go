@@ -24,6 +24,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
@@ -55,8 +56,7 @@ type VersionedDBProvider struct {
 func NewVersionedDBProvider() (*VersionedDBProvider, error) {
 	logger.Debugf("constructing CouchDB VersionedDBProvider")
 	couchDBDef := couchdb.GetCouchDBDefinition()
-	couchInstance, err := couchdb.CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
-		couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout)
+	couchInstance, err := couchdb.CreateCouchInstanceFromDefinition(couchDBDef)
 	if err != nil {
 		return nil, err
 	}
@@ -87,19 +87,119 @@ func (provider *VersionedDBProvider) Close() {
 }
 
 // VersionedDB implements VersionedDB interface
+// The state of each namespace is kept in a database of its own, so that
+// indexes are scoped to a chaincode, while the database named after the
+// channel holds the metadata such as the savepoint.
+// The state written before keeps being read and written in the legacy
+// layout, the database of the channel, until peer node upgrade-dbs drops
+// it for the state to be rebuilt from the blocks, see DropLegacyLayoutDBs
 type VersionedDB struct {
-	db     *couchdb.CouchDatabase
-	dbName string
+	couchInstance *couchdb.CouchInstance
+	metadataDB    *couchdb.CouchDatabase
+	dbName        string
+	legacyLayout  bool
+	namespaceDBs  map[string]*couchdb.CouchDatabase
+	mux           sync.RWMutex
 }
 
 // newVersionedDB constructs an instance of VersionedDB
 func newVersionedDB(couchInstance *couchdb.CouchInstance, dbName string) (*VersionedDB, error) {
 	// CreateCouchDatabase creates a CouchDB database object, as well as the underlying database if it does not exist
-	db, err := couchdb.CreateCouchDatabase(*couchInstance, dbName)
+	metadataDB, err := couchdb.CreateCouchDatabase(*couchInstance, dbName)
 	if err != nil {
 		return nil, err
 	}
-	return &VersionedDB{db, dbName}, nil
+	legacyLayout, err := isLegacyLayout(metadataDB)
+	if err != nil {
+		return nil, err
+	}
+	if legacyLayout {
+		logger.Warningf("The state of [%s] is kept in the legacy layout of a single database, "+
+			"run peer node upgrade-dbs while the peer is stopped to keep it in a database per namespace", dbName)
+	}
+	return &VersionedDB{couchInstance: couchInstance, metadataDB: metadataDB, dbName: dbName,
+		legacyLayout: legacyLayout, namespaceDBs: make(map[string]*couchdb.CouchDatabase)}, nil
+}
+
+// isLegacyLayout returns true if the database of the channel holds the
+// state of the namespaces, as it did before each namespace got a database
+// of its own: it then holds documents besides the savepoint
+func isLegacyLayout(db *couchdb.CouchDatabase) (bool, error) {
+	dbInfo, _, err := db.GetDatabaseInfo()
+	if err != nil {
+		return false, err
+	}
+	docCount := dbInfo.DocCount
+	if docCount == 0 {
+		return false, nil
+	}
+	savepointDoc, _, err := db.ReadDoc(savepointDocID)
+	if err != nil {
+		return false, err
+	}
+	if savepointDoc != nil {
+		docCount--
+	}
+	return docCount > 0, nil
+}
+
+// DropLegacyLayoutDBs drops the databases of the named state databases
+// kept in the legacy layout, for their state to be rebuilt from the blocks
+// in a database per namespace when the peer restarts. It is invoked by
+// peer node upgrade-dbs, while the peer is stopped
+func DropLegacyLayoutDBs(dbNames []string) error {
+	couchInstance, err := couchdb.CreateCouchInstanceFromDefinition(couchdb.GetCouchDBDefinition())
+	if err != nil {
+		return err
+	}
+	for _, dbName := range dbNames {
+		db, err := couchdb.CreateCouchDatabase(*couchInstance, dbName)
+		if err != nil {
+			return err
+		}
+		legacyLayout, err := isLegacyLayout(db)
+		if err != nil {
+			return err
+		}
+		if !legacyLayout {
+			continue
+		}
+		logger.Infof("Dropping the state database of [%s] in the legacy layout, "+
+			"the state will be rebuilt from the blocks when the peer restarts", dbName)
+		if _, err = db.DropDatabase(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getNamespaceDBHandle gets the handle to the database of a namespace,
+// creating the database if it does not exist
+func (vdb *VersionedDB) getNamespaceDBHandle(namespace string) (*couchdb.CouchDatabase, error) {
+	if vdb.legacyLayout {
+		return vdb.metadataDB, nil
+	}
+
+	vdb.mux.RLock()
+	db := vdb.namespaceDBs[namespace]
+	vdb.mux.RUnlock()
+	if db != nil {
+		return db, nil
+	}
+
+	vdb.mux.Lock()
+	defer vdb.mux.Unlock()
+	db = vdb.namespaceDBs[namespace]
+	if db == nil {
+		var err error
+		namespaceDBName := couchdb.ConstructNamespaceDBName(vdb.dbName, namespace)
+		db, err = couchdb.CreateCouchDatabase(*vdb.couchInstance, namespaceDBName)
+		if err != nil {
+			return nil, err
+		}
+		vdb.namespaceDBs[namespace] = db
+	}
+	return db, nil
 }
 
 // Open implements method in VersionedDB interface
@@ -117,9 +217,14 @@ func (vdb *VersionedDB) Close() {
 func (vdb *VersionedDB) GetState(namespace string, key string) (*statedb.VersionedValue, error) {
 	logger.Debugf("GetState(). ns=%s, key=%s", namespace, key)
 
+	db, err := vdb.getNamespaceDBHandle(namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	compositeKey := constructCompositeKey(namespace, key)
 
-	couchDoc, _, err := vdb.db.ReadDoc(string(compositeKey))
+	couchDoc, _, err := db.ReadDoc(string(compositeKey))
 	if err != nil {
 		return nil, err
 	}
@@ -205,12 +310,17 @@ func (vdb *VersionedDB) GetStateRangeScanIterator(namespace string, startKey str
 	//Get the querylimit from core.yaml
 	queryLimit := ledgerconfig.GetQueryLimit()
 
+	db, err := vdb.getNamespaceDBHandle(namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	compositeStartKey := constructCompositeKey(namespace, startKey)
 	compositeEndKey := constructCompositeKey(namespace, endKey)
 	if endKey == "" {
 		compositeEndKey[len(compositeEndKey)-1] = lastKeyIndicator
 	}
-	queryResult, err := vdb.db.ReadDocRange(string(compositeStartKey), string(compositeEndKey), queryLimit, querySkip)
+	queryResult, err := db.ReadDocRange(string(compositeStartKey), string(compositeEndKey), queryLimit, querySkip)
 	if err != nil {
 		logger.Debugf("Error calling ReadDocRange(): %s\n", err.Error())
 		return nil, err
@@ -226,13 +336,18 @@ func (vdb *VersionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIt
 	//Get the querylimit from core.yaml
 	queryLimit := ledgerconfig.GetQueryLimit()
 
+	db, err := vdb.getNamespaceDBHandle(namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	queryString, err := ApplyQueryWrapper(namespace, query, queryLimit, 0)
 	if err != nil {
 		logger.Debugf("Error calling ApplyQueryWrapper(): %s\n", err.Error())
 		return nil, err
 	}
 
-	queryResult, err := vdb.db.QueryDocuments(queryString)
+	queryResult, err := db.QueryDocuments(queryString)
 	if err != nil {
 		logger.Debugf("Error calling QueryDocuments(): %s\n", err.Error())
 		return nil, err
@@ -246,6 +361,11 @@ func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 
 	namespaces := batch.GetUpdatedNamespaces()
 	for _, ns := range namespaces {
+		db, err := vdb.getNamespaceDBHandle(ns)
+		if err != nil {
+			logger.Errorf("Error during Commit(): %s\n", err.Error())
+			return err
+		}
 		updates := batch.GetUpdates(ns)
 		for k, vv := range updates {
 			compositeKey := constructCompositeKey(ns, k)
@@ -254,14 +374,14 @@ func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 			//convert nils to deletes
 			if vv.Value == nil {
 
-				vdb.db.DeleteDoc(string(compositeKey), "")
+				db.DeleteDoc(string(compositeKey), "")
 
 			} else {
 				couchDoc := &couchdb.CouchDoc{}
 
-				//Check to see if the value is a valid JSON
-				//If this is not a valid JSON, then store as an attachment
-				if couchdb.IsJSON(string(vv.Value)) {
+				//Check to see if the value is a valid JSON document
+				//If this is not a valid JSON document, then store as an attachment
+				if isJSONDocument(vv.Value) {
 					// Handle it as json
 					couchDoc.JSONValue = addVersionAndChainCodeID(vv.Value, ns, vv.Version)
 				} else { // if the data is not JSON, save as binary attachment in Couch
@@ -277,7 +397,7 @@ func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 				}
 
 				// SaveDoc using couchdb client and use attachment to persist the binary data
-				rev, err := vdb.db.SaveDoc(string(compositeKey), "", couchDoc)
+				rev, err := db.SaveDoc(string(compositeKey), "", couchDoc)
 				if err != nil {
 					logger.Errorf("Error during Commit(): %s\n", err.Error())
 					return err
@@ -287,6 +407,13 @@ func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 				}
 			}
 		}
+
+		// ensure full commit to flush the changes of the namespace to disk before the savepoint is recorded
+		dbResponse, err := db.EnsureFullCommit()
+		if err != nil || dbResponse.Ok != true {
+			logger.Errorf("Failed to perform full commit\n")
+			return errors.New("Failed to perform full commit")
+		}
 	}
 
 	// Record a savepoint at a given height
@@ -299,6 +426,38 @@ func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 	return nil
 }
 
+// reservedFields are the fields of JSON values the state database and CouchDB keep for document metadata
+var reservedFields = []string{"_id", "_rev", "~version"}
+
+// ValidateKeyValue implements method in VersionedDB interface
+// Keys are part of CouchDB document ids and must hence be valid UTF-8.
+// Values that are JSON documents must not use the reserved fields, any
+// other value is stored as an attachment
+func (vdb *VersionedDB) ValidateKeyValue(key string, value []byte) error {
+	if !utf8.ValidString(key) {
+		return fmt.Errorf("Invalid key [%x], keys must be valid UTF-8 strings", key)
+	}
+	if value == nil || !isJSONDocument(value) {
+		return nil
+	}
+	jsonMap := make(map[string]interface{})
+	if err := json.Unmarshal(value, &jsonMap); err != nil {
+		return err
+	}
+	for _, field := range reservedFields {
+		if _, exists := jsonMap[field]; exists {
+			return fmt.Errorf("Invalid JSON value for key [%s], field [%s] is reserved", key, field)
+		}
+	}
+	return nil
+}
+
+// isJSONDocument returns true iff the value is stored as a JSON document,
+// i.e. is a JSON object in UTF-8 as required by CouchDB
+func isJSONDocument(value []byte) bool {
+	return utf8.Valid(value) && couchdb.IsJSON(string(value))
+}
+
 //addVersionAndChainCodeID adds keys for version and chaincodeID to the JSON value
 func addVersionAndChainCodeID(value []byte, chaincodeID string, version *version.Height) []byte {
 
@@ -344,7 +503,7 @@ func (vdb *VersionedDB) recordSavepoint(height *version.Height) error {
 	var err error
 	var savepointDoc couchSavepointData
 	// ensure full commit to flush all changes until now to disk
-	dbResponse, err := vdb.db.EnsureFullCommit()
+	dbResponse, err := vdb.metadataDB.EnsureFullCommit()
 	if err != nil || dbResponse.Ok != true {
 		logger.Errorf("Failed to perform full commit\n")
 		return errors.New("Failed to perform full commit")
@@ -352,7 +511,7 @@ func (vdb *VersionedDB) recordSavepoint(height *version.Height) error {
 
 	// construct savepoint document
 	// UpdateSeq would be useful if we want to get all db changes since a logical savepoint
-	dbInfo, _, err := vdb.db.GetDatabaseInfo()
+	dbInfo, _, err := vdb.metadataDB.GetDatabaseInfo()
 	if err != nil {
 		logger.Errorf("Failed to get DB info %s\n", err.Error())
 		return err
@@ -368,14 +527,14 @@ func (vdb *VersionedDB) recordSavepoint(height *version.Height) error {
 	}
 
 	// SaveDoc using couchdb client and use JSON format
-	_, err = vdb.db.SaveDoc(savepointDocID, "", &couchdb.CouchDoc{JSONValue: savepointDocJSON, Attachments: nil})
+	_, err = vdb.metadataDB.SaveDoc(savepointDocID, "", &couchdb.CouchDoc{JSONValue: savepointDocJSON, Attachments: nil})
 	if err != nil {
 		logger.Errorf("Failed to save the savepoint to DB %s\n", err.Error())
 		return err
 	}
 
 	// ensure full commit to flush savepoint to disk
-	dbResponse, err = vdb.db.EnsureFullCommit()
+	dbResponse, err = vdb.metadataDB.EnsureFullCommit()
 	if err != nil || dbResponse.Ok != true {
 		logger.Errorf("Failed to perform full commit\n")
 		return errors.New("Failed to perform full commit")
@@ -387,7 +546,7 @@ func (vdb *VersionedDB) recordSavepoint(height *version.Height) error {
 func (vdb *VersionedDB) GetLatestSavePoint() (*version.Height, error) {
 
 	var err error
-	couchDoc, _, err := vdb.db.ReadDoc(savepointDocID)
+	couchDoc, _, err := vdb.metadataDB.ReadDoc(savepointDocID)
 	if err != nil {
 		logger.Errorf("Failed to read savepoint data %s\n", err.Error())
 		return nil, err
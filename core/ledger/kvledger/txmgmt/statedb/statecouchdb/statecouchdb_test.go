@@ -142,3 +142,90 @@ func TestQuery(t *testing.T) {
 
 	}
 }
+
+func TestNamespaceDBs(t *testing.T) {
+	if ledgerconfig.IsCouchDBEnabled() == true {
+
+		env := NewTestVDBEnv(t)
+		env.Cleanup("testnamespacedbs")
+		defer env.Cleanup("testnamespacedbs")
+
+		db, err := env.DBProvider.GetDBHandle("testnamespacedbs")
+		testutil.AssertNoError(t, err, "")
+		batch := statedb.NewUpdateBatch()
+		batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+		batch.Put("ns2", "key1", []byte("value2"), version.NewHeight(1, 2))
+		testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 2)), "")
+
+		// each namespace has a database of its own
+		vdb := db.(*VersionedDB)
+		testutil.AssertEquals(t, vdb.namespaceDBs["ns1"].DBName, "testnamespacedbs_ns1")
+		testutil.AssertEquals(t, vdb.namespaceDBs["ns2"].DBName, "testnamespacedbs_ns2")
+		doc, _, err := vdb.namespaceDBs["ns1"].ReadDoc(string(constructCompositeKey("ns2", "key1")))
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertNil(t, doc)
+
+		vv, err := db.GetState("ns2", "key1")
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, vv.Value, []byte("value2"))
+
+		savepoint, err := db.GetLatestSavePoint()
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, savepoint, version.NewHeight(1, 2))
+	}
+}
+
+func TestValidateKeyValue(t *testing.T) {
+	vdb := &VersionedDB{}
+	testutil.AssertNoError(t, vdb.ValidateKeyValue("key", []byte(`{"asset_name":"marble1","owner":"tom"}`)), "")
+	testutil.AssertNoError(t, vdb.ValidateKeyValue("key", []byte("binary value")), "")
+	testutil.AssertNoError(t, vdb.ValidateKeyValue("key", nil), "")
+
+	testutil.AssertError(t, vdb.ValidateKeyValue(string([]byte{0xff, 0xfe}), []byte("value")), "Keys should be valid UTF-8")
+	for _, field := range reservedFields {
+		value := []byte(`{"` + field + `":"x","owner":"tom"}`)
+		testutil.AssertError(t, vdb.ValidateKeyValue("key", value), "Reserved fields should be rejected")
+	}
+
+	// a JSON object that is not UTF-8 is stored as an attachment
+	notUTF8 := []byte("{\"owner\":\"t\xffm\"}")
+	testutil.AssertEquals(t, isJSONDocument(notUTF8), false)
+	testutil.AssertNoError(t, vdb.ValidateKeyValue("key", notUTF8), "")
+	testutil.AssertEquals(t, isJSONDocument([]byte(`{"owner":"tom"}`)), true)
+}
+
+func TestLegacyLayout(t *testing.T) {
+	if ledgerconfig.IsCouchDBEnabled() == true {
+
+		env := NewTestVDBEnv(t)
+		env.Cleanup("testlegacylayout")
+		defer env.Cleanup("testlegacylayout")
+
+		// the state written before each namespace got a database of its own
+		db, err := env.DBProvider.GetDBHandle("testlegacylayout")
+		testutil.AssertNoError(t, err, "")
+		vdb := db.(*VersionedDB)
+		testutil.AssertEquals(t, vdb.legacyLayout, false)
+		vdb.legacyLayout = true
+		batch := statedb.NewUpdateBatch()
+		batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+		testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 1)), "")
+
+		// it keeps being read from the database of the channel
+		vdb, err = newVersionedDB(vdb.couchInstance, "testlegacylayout")
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, vdb.legacyLayout, true)
+		vv, err := vdb.GetState("ns1", "key1")
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, vv.Value, []byte("value1"))
+
+		// until the upgrade drops it for the state to be rebuilt from the blocks
+		testutil.AssertNoError(t, DropLegacyLayoutDBs([]string{"testlegacylayout"}), "")
+		vdb, err = newVersionedDB(vdb.couchInstance, "testlegacylayout")
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, vdb.legacyLayout, false)
+		savepoint, err := vdb.GetLatestSavePoint()
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertNil(t, savepoint)
+	}
+}
@@ -54,6 +54,14 @@ func cleanupDB(dbName string) {
 	couchDBDef := couchdb.GetCouchDBDefinition()
 	couchInstance, _ := couchdb.CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
 		couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout)
+	//drop the databases of the namespaces of the test channel
+	dbNames, _ := couchInstance.ListDatabases()
+	for _, name := range dbNames {
+		if strings.HasPrefix(name, dbName+"_") {
+			namespaceDB := couchdb.CouchDatabase{CouchInstance: *couchInstance, DBName: name}
+			namespaceDB.DropDatabase()
+		}
+	}
 	db := couchdb.CouchDatabase{CouchInstance: *couchInstance, DBName: dbName}
 	//drop the test database
 	db.DropDatabase()
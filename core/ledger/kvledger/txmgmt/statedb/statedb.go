@@ -44,6 +44,9 @@ type VersionedDB interface {
 	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (ResultsIterator, error)
 	// ExecuteQuery executes the given query and returns an iterator that contains results of type *VersionedKV.
 	ExecuteQuery(namespace, query string) (ResultsIterator, error)
+	// ValidateKeyValue returns an error if the key or the value cannot be stored by the db.
+	// It is invoked at simulation time so that chaincodes get the error rather than the commit failing
+	ValidateKeyValue(key string, value []byte) error
 	// ApplyUpdates applies the batch to the underlying db.
 	// height is the height of the highest transaction in the Batch that
 	// a state db implementation is expected to ues as a save point
@@ -19,6 +19,7 @@ package stateleveldb
 import (
 	"bytes"
 	"errors"
+	"sync"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -37,6 +38,10 @@ var savePointKey = []byte{0x00}
 // VersionedDBProvider implements interface VersionedDBProvider
 type VersionedDBProvider struct {
 	dbProvider *leveldbhelper.Provider
+
+	// channelDBProviders are the dbs of the channels kept on their own volume
+	lock               sync.Mutex
+	channelDBProviders map[string]*leveldbhelper.Provider
 }
 
 // NewVersionedDBProvider instantiates VersionedDBProvider
@@ -44,17 +49,48 @@ func NewVersionedDBProvider() *VersionedDBProvider {
 	dbPath := ledgerconfig.GetStateLevelDBPath()
 	logger.Debugf("constructing VersionedDBProvider dbPath=%s", dbPath)
 	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
-	return &VersionedDBProvider{dbProvider}
+	return &VersionedDBProvider{dbProvider: dbProvider, channelDBProviders: make(map[string]*leveldbhelper.Provider)}
 }
 
-// GetDBHandle gets the handle to a named database
+// GetDBHandle gets the handle to a named database, in a db of its own if the
+// channel of the same name is kept on its own volume
 func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.VersionedDB, error) {
-	return newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
+	sharedHandle := provider.dbProvider.GetDBHandle(dbName)
+	dbPath := ledgerconfig.GetChannelStateLevelDBPath(dbName)
+	if dbPath == "" {
+		return newVersionedDB(sharedHandle, dbName), nil
+	}
+
+	// the state of a channel created before its folder was configured stays
+	// in the shared db until rebuilt
+	savePoint, err := sharedHandle.Get(savePointKey)
+	if err != nil {
+		return nil, err
+	}
+	if savePoint != nil {
+		logger.Warningf("State of channel [%s] is kept in the shared db instead of [%s] until rebuilt", dbName, dbPath)
+		return newVersionedDB(sharedHandle, dbName), nil
+	}
+
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	dbProvider, ok := provider.channelDBProviders[dbName]
+	if !ok {
+		logger.Debugf("constructing db of channel [%s] dbPath=%s", dbName, dbPath)
+		dbProvider = leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
+		provider.channelDBProviders[dbName] = dbProvider
+	}
+	return newVersionedDB(dbProvider.GetDBHandle(dbName), dbName), nil
 }
 
-// Close closes the underlying db
+// Close closes the underlying dbs
 func (provider *VersionedDBProvider) Close() {
 	provider.dbProvider.Close()
+	provider.lock.Lock()
+	defer provider.lock.Unlock()
+	for _, dbProvider := range provider.channelDBProviders {
+		dbProvider.Close()
+	}
 }
 
 // VersionedDB implements VersionedDB interface
@@ -149,6 +185,12 @@ func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 	return nil
 }
 
+// ValidateKeyValue implements method in VersionedDB interface
+// Any key and value can be stored in leveldb
+func (vdb *versionedDB) ValidateKeyValue(key string, value []byte) error {
+	return nil
+}
+
 // GetLatestSavePoint implements method in VersionedDB interface
 func (vdb *versionedDB) GetLatestSavePoint() (*version.Height, error) {
 	versionBytes, err := vdb.db.Get(savePointKey)
@@ -17,7 +17,9 @@ limitations under the License.
 package stateleveldb
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
@@ -80,3 +82,37 @@ func testCompositeKey(t *testing.T, dbName string, ns string, key string) {
 	testutil.AssertEquals(t, ns1, ns)
 	testutil.AssertEquals(t, key1, key)
 }
+
+func TestChannelDB(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+
+	channelPath, err := ioutil.TempDir("", "stateleveldb-")
+	testutil.AssertNoError(t, err, "")
+	defer os.RemoveAll(channelPath)
+
+	// the state of oldchannel is in the shared db before it is configured
+	db, err := env.DBProvider.GetDBHandle("oldchannel")
+	testutil.AssertNoError(t, err, "")
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key", []byte("value"), version.NewHeight(1, 1))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 1)), "")
+
+	viper.Set("ledger.channelPaths", map[string]string{"oldchannel": channelPath, "newchannel": channelPath})
+	defer viper.Set("ledger.channelPaths", nil)
+
+	db, err = env.DBProvider.GetDBHandle("oldchannel")
+	testutil.AssertNoError(t, err, "")
+	vv, err := db.GetState("ns", "key")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, vv.Value, []byte("value"))
+
+	db, err = env.DBProvider.GetDBHandle("newchannel")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 1)), "")
+	_, err = os.Stat(filepath.Join(channelPath, "stateLeveldb"))
+	testutil.AssertNoError(t, err, "")
+	vv, err = db.GetState("ns", "key")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, vv.Value, []byte("value"))
+}
@@ -33,3 +33,10 @@ func DecodeValue(encodedValue []byte) ([]byte, *version.Height) {
 	value := encodedValue[n:]
 	return value, version
 }
+
+// MetadataNamespace returns the namespace under which the metadata of the keys
+// of a namespace is stored. Chaincode names cannot contain '~', so it does not
+// collide with the namespace of a chaincode
+func MetadataNamespace(ns string) string {
+	return ns + "~metadata"
+}
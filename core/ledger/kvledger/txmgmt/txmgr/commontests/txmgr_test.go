@@ -201,6 +201,67 @@ func testTxValidation(t *testing.T, env testEnv) {
 	txMgrHelper.validateAndCommitRWSet(txRWSet6)
 }
 
+func TestTxStateMetadata(t *testing.T) {
+	for _, testEnv := range testEnvs {
+		t.Logf("Running test for TestEnv = %s", testEnv.getName())
+		testLedgerID := "testtxstatemetadata"
+		testEnv.init(t, testLedgerID)
+		testTxStateMetadata(t, testEnv)
+		testEnv.cleanup()
+	}
+}
+
+func testTxStateMetadata(t *testing.T, env testEnv) {
+	txMgr := env.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+	// simulate tx1 that writes a key along with its metadata
+	s1, _ := txMgr.NewTxSimulator()
+	s1.SetState("ns1", "key1", []byte("value1"))
+	s1.SetStateMetadata("ns1", "key1", map[string][]byte{"VP": []byte("policy1")})
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1)
+
+	// simulate tx2 that reads the metadata, and tx3 that replaces it before tx2 commits
+	s2, _ := txMgr.NewTxSimulator()
+	metadata, err := s2.GetStateMetadata("ns1", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, metadata, map[string][]byte{"VP": []byte("policy1")})
+	s2.SetState("ns1", "key2", []byte("value2"))
+	s2.Done()
+
+	s3, _ := txMgr.NewTxSimulator()
+	s3.SetStateMetadata("ns1", "key1", map[string][]byte{"VP": []byte("policy2")})
+	s3.Done()
+	txRWSet3, _ := s3.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet3)
+
+	// tx2 should be invalid as the metadata it read changed
+	txRWSet2, _ := s2.GetTxSimulationResults()
+	txMgrHelper.checkRWsetInvalid(txRWSet2)
+
+	// the value of the key is untouched by the metadata write
+	qe, _ := txMgr.NewQueryExecutor()
+	value, _ := qe.GetState("ns1", "key1")
+	testutil.AssertEquals(t, value, []byte("value1"))
+	metadata, _ = qe.GetStateMetadata("ns1", "key1")
+	testutil.AssertEquals(t, metadata, map[string][]byte{"VP": []byte("policy2")})
+	qe.Done()
+
+	// simulate tx4 that deletes the key, which deletes its metadata too
+	s4, _ := txMgr.NewTxSimulator()
+	s4.DeleteState("ns1", "key1")
+	s4.Done()
+	txRWSet4, _ := s4.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet4)
+
+	qe, _ = txMgr.NewQueryExecutor()
+	metadata, err = qe.GetStateMetadata("ns1", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, metadata)
+	qe.Done()
+}
+
 func TestTxPhantomValidation(t *testing.T) {
 	for _, testEnv := range testEnvs {
 		t.Logf("Running test for TestEnv = %s", testEnv.getName())
@@ -47,6 +47,20 @@ func (h *queryHelper) getState(ns string, key string) ([]byte, error) {
 	return val, nil
 }
 
+func (h *queryHelper) getStateMetadata(ns string, key string) (map[string][]byte, error) {
+	h.checkDone()
+	metadataNs := statedb.MetadataNamespace(ns)
+	versionedValue, err := h.txmgr.db.GetState(metadataNs, key)
+	if err != nil {
+		return nil, err
+	}
+	encodedMetadata, ver := decomposeVersionedValue(versionedValue)
+	if h.rwsetBuilder != nil {
+		h.rwsetBuilder.AddToReadSet(metadataNs, key, ver)
+	}
+	return rwsetutil.DecodeMetadata(encodedMetadata)
+}
+
 func (h *queryHelper) getStateMultipleKeys(namespace string, keys []string) ([][]byte, error) {
 	h.checkDone()
 	versionedValues, err := h.txmgr.db.GetStateMultipleKeys(namespace, keys)
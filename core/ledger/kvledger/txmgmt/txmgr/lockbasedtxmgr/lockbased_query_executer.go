@@ -57,6 +57,11 @@ func (q *lockBasedQueryExecutor) ExecuteQuery(namespace, query string) (ledger.R
 	return q.helper.executeQuery(namespace, query)
 }
 
+// GetStateMetadata implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	return q.helper.getStateMetadata(namespace, key)
+}
+
 // Done implements method in interface `ledger.QueryExecutor`
 func (q *lockBasedQueryExecutor) Done() {
 	logger.Debugf("Done with transaction simulation / query execution [%s]", q.id)
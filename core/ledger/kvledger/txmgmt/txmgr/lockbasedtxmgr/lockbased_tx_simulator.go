@@ -45,6 +45,9 @@ func (s *lockBasedTxSimulator) GetState(ns string, key string) ([]byte, error) {
 // SetState implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) SetState(ns string, key string, value []byte) error {
 	s.helper.checkDone()
+	if err := s.helper.txmgr.db.ValidateKeyValue(key, value); err != nil {
+		return err
+	}
 	s.rwsetBuilder.AddToWriteSet(ns, key, value)
 	return nil
 }
@@ -64,6 +67,13 @@ func (s *lockBasedTxSimulator) SetStateMultipleKeys(namespace string, kvs map[st
 	return nil
 }
 
+// SetStateMetadata implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) SetStateMetadata(namespace, key string, metadata map[string][]byte) error {
+	s.helper.checkDone()
+	s.rwsetBuilder.AddToMetadataWriteSet(namespace, key, metadata)
+	return nil
+}
+
 // GetTxSimulationResults implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) GetTxSimulationResults() ([]byte, error) {
 	logger.Debugf("Simulation completed, getting simulation results")
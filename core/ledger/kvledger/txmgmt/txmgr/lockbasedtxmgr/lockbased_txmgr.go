@@ -79,6 +79,11 @@ func (txmgr *LockBasedTxMgr) ValidateAndPrepare(block *common.Block, doMVCCValid
 	return err
 }
 
+// SetCapabilityChecker implements method in interface `txmgmt.TxMgr`
+func (txmgr *LockBasedTxMgr) SetCapabilityChecker(hasCapability func(name string) bool) {
+	txmgr.validator.SetCapabilityChecker(hasCapability)
+}
+
 // Shutdown implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Shutdown() {
 	txmgr.db.Close()
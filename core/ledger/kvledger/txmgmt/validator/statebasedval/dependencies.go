@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statebasedval
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+// invalidWrites records, by namespace, the keys written by the invalid
+// transactions of a block. The transactions of the block reading these keys
+// read the value they had before the block, which is still the committed
+// one, but they were simulated concurrently with the invalid transactions
+// and would have failed MVCC validation if those had been valid.
+type invalidWrites map[string]map[string]bool
+
+// addTx records the keys written by an invalid transaction. It returns an
+// error, recording nothing, if the read-write set of the transaction cannot be
+// extracted from its envelope: a malformed transaction writes nothing that
+// another one could have read, and failing the block over it would let any
+// client halt the channel
+func (w invalidWrites) addTx(envBytes []byte) error {
+	respPayload, err := putils.GetActionFromEnvelope(envBytes)
+	if err != nil {
+		return fmt.Errorf("cannot extract the chaincode action: %s", err)
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+		return fmt.Errorf("cannot unmarshal the read-write set: %s", err)
+	}
+	w.add(txRWSet)
+	return nil
+}
+
+// add records the keys written in the read-write set of an invalid transaction
+func (w invalidWrites) add(txRWSet *rwsetutil.TxRwSet) {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+			keys, ok := w[nsRWSet.NameSpace]
+			if !ok {
+				keys = make(map[string]bool)
+				w[nsRWSet.NameSpace] = keys
+			}
+			keys[kvWrite.Key] = true
+		}
+	}
+}
+
+// readBy returns whether the transaction read, or range queried, a key
+// written by an invalid transaction
+func (w invalidWrites) readBy(txRWSet *rwsetutil.TxRwSet) bool {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		keys := w[nsRWSet.NameSpace]
+		if len(keys) == 0 {
+			continue
+		}
+		for _, kvRead := range nsRWSet.KvRwSet.Reads {
+			if keys[kvRead.Key] {
+				return true
+			}
+		}
+		for _, rqi := range nsRWSet.KvRwSet.RangeQueriesInfo {
+			for key := range keys {
+				if key >= rqi.StartKey && (rqi.EndKey == "" || key < rqi.EndKey) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package statebasedval
 
 import (
+	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
@@ -34,19 +35,35 @@ var logger = flogging.MustGetLogger("statevalidator")
 // and preceding valid transactions with in the same block
 type Validator struct {
 	db statedb.VersionedDB
+	// hasCapability tells whether the channel requires a capability of its
+	// peers, nil until the ledger is attached to its channel
+	hasCapability func(name string) bool
 }
 
 // NewValidator constructs StateValidator
 func NewValidator(db statedb.VersionedDB) *Validator {
-	return &Validator{db}
+	return &Validator{db: db}
 }
 
-//validate endorser transaction
-func (v *Validator) validateEndorserTX(envBytes []byte, doMVCCValidation bool, updates *statedb.UpdateBatch) (*rwsetutil.TxRwSet, peer.TxValidationCode, error) {
+// SetCapabilityChecker implements method in Validator interface
+func (v *Validator) SetCapabilityChecker(hasCapability func(name string) bool) {
+	v.hasCapability = hasCapability
+}
+
+// checkDependencies returns whether the transactions reading the keys written
+// by the preceding invalid transactions of the block are marked invalid. It
+// depends on the channel config rather than on the peer, as the peers of the
+// channel have to agree on the validity of the transactions
+func (v *Validator) checkDependencies() bool {
+	return v.hasCapability != nil && v.hasCapability(config.DependencyInvalidationCapability)
+}
+
+//validate endorser transaction, returning what conflicted when MVCC validation invalidates it
+func (v *Validator) validateEndorserTX(envBytes []byte, doMVCCValidation bool, updates *statedb.UpdateBatch) (*rwsetutil.TxRwSet, *util.TxMVCCConflicts, peer.TxValidationCode, error) {
 	// extract actions from the envelope message
 	respPayload, err := putils.GetActionFromEnvelope(envBytes)
 	if err != nil {
-		return nil, peer.TxValidationCode_NIL_TXACTION, nil
+		return nil, nil, peer.TxValidationCode_NIL_TXACTION, nil
 	}
 
 	//preparation for extracting RWSet from transaction
@@ -56,21 +73,72 @@ func (v *Validator) validateEndorserTX(envBytes []byte, doMVCCValidation bool, u
 	// and then Unmarshal it into a TxReadWriteSet using custom unmarshalling
 
 	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
-		return nil, peer.TxValidationCode_INVALID_OTHER_REASON, nil
+		return nil, nil, peer.TxValidationCode_INVALID_OTHER_REASON, nil
 	}
 
 	txResult := peer.TxValidationCode_VALID
+	var txConflicts *util.TxMVCCConflicts
 
 	//mvccvalidation, may invalidate transaction
 	if doMVCCValidation {
 		if txResult, err = v.validateTx(txRWSet, updates); err != nil {
-			return nil, txResult, err
+			return nil, nil, txResult, err
 		} else if txResult != peer.TxValidationCode_VALID {
+			if txConflicts, err = v.collectConflicts(txRWSet, updates); err != nil {
+				return nil, nil, txResult, err
+			}
 			txRWSet = nil
 		}
 	}
 
-	return txRWSet, txResult, err
+	return txRWSet, txConflicts, txResult, err
+}
+
+// collectConflicts returns all that the transaction read and that changed
+// since, as a hint for clients to retry it. It is only invoked for the
+// transactions that fail MVCC validation so that valid ones stop at the
+// first check, but has to repeat checks to go beyond the first conflict
+func (v *Validator) collectConflicts(txRWSet *rwsetutil.TxRwSet, updates *statedb.UpdateBatch) (*util.TxMVCCConflicts, error) {
+	txConflicts := &util.TxMVCCConflicts{}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		ns := nsRWSet.NameSpace
+		for _, kvRead := range nsRWSet.KvRwSet.Reads {
+			var vv *statedb.VersionedValue
+			if updates.Exists(ns, kvRead.Key) {
+				vv = updates.Get(ns, kvRead.Key)
+			} else {
+				var err error
+				if vv, err = v.db.GetState(ns, kvRead.Key); err != nil {
+					return nil, err
+				}
+				if vv != nil && version.AreSame(vv.Version, rwsetutil.NewVersion(kvRead.Version)) {
+					continue
+				}
+				if vv == nil && kvRead.Version == nil {
+					continue
+				}
+			}
+			conflict := &util.KeyMVCCConflict{Namespace: ns, Key: kvRead.Key}
+			if vv == nil || vv.Value == nil {
+				conflict.Deleted = true
+			}
+			if vv != nil {
+				conflict.BlockNum, conflict.TxNum = vv.Version.BlockNum, vv.Version.TxNum
+			}
+			txConflicts.Keys = append(txConflicts.Keys, conflict)
+		}
+		for _, rqi := range nsRWSet.KvRwSet.RangeQueriesInfo {
+			valid, err := v.validateRangeQuery(ns, rqi, updates)
+			if err != nil {
+				return nil, err
+			}
+			if !valid {
+				txConflicts.Ranges = append(txConflicts.Ranges,
+					&util.RangeMVCCConflict{Namespace: ns, StartKey: rqi.StartKey, EndKey: rqi.EndKey})
+			}
+		}
+	}
+	return txConflicts, nil
 }
 
 // TODO validate configuration transaction
@@ -82,6 +150,7 @@ func (v *Validator) validateConfigTX(env *common.Envelope) (bool, error) {
 func (v *Validator) ValidateAndPrepareBatch(block *common.Block, doMVCCValidation bool) (*statedb.UpdateBatch, error) {
 	logger.Debugf("New block arrived for validation:%#v, doMVCCValidation=%t", block, doMVCCValidation)
 	updates := statedb.NewUpdateBatch()
+	conflicts := &util.MVCCConflicts{}
 	logger.Debugf("Validating a block with [%d] transactions", len(block.Data.Data))
 
 	// Committer validator has already set validation flags based on well formed tran checks
@@ -93,11 +162,23 @@ func (v *Validator) ValidateAndPrepareBatch(block *common.Block, doMVCCValidatio
 		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
 	}
 
+	// the recommitted blocks already carry the flags set at their validation
+	var invalid invalidWrites
+	if doMVCCValidation && v.checkDependencies() {
+		invalid = make(invalidWrites)
+	}
+
 	for txIndex, envBytes := range block.Data.Data {
 		if txsFilter.IsInvalid(txIndex) {
 			// Skiping invalid transaction
 			logger.Warningf("Block [%d] Transaction index [%d] marked as invalid by committer. Reason code [%d]",
 				block.Header.Number, txIndex, txsFilter.Flag(txIndex))
+			if invalid != nil {
+				if err := invalid.addTx(envBytes); err != nil {
+					logger.Warningf("Block [%d] Transaction index [%d] has no extractable writes, no transaction depends on it: %s",
+						block.Header.Number, txIndex, err)
+				}
+			}
 			continue
 		}
 
@@ -117,18 +198,36 @@ func (v *Validator) ValidateAndPrepareBatch(block *common.Block, doMVCCValidatio
 		}
 
 		if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
-			txRWSet, txResult, err := v.validateEndorserTX(envBytes, doMVCCValidation, updates)
-
+			txRWSet, txConflicts, txResult, err := v.validateEndorserTX(envBytes, doMVCCValidation, updates)
 			if err != nil {
 				return nil, err
 			}
+			if txConflicts != nil {
+				txConflicts.TxNum, txConflicts.TxId = uint64(txIndex), chdr.TxId
+				conflicts.TxConflicts = append(conflicts.TxConflicts, txConflicts)
+			}
+
+			if invalid != nil {
+				if txRWSet == nil {
+					if err := invalid.addTx(envBytes); err != nil {
+						logger.Warningf("Block [%d] Transaction index [%d] has no extractable writes, no transaction depends on it: %s",
+							block.Header.Number, txIndex, err)
+					}
+				} else if invalid.readBy(txRWSet) {
+					// what the transaction wrote depends on invalid ones in turn
+					invalid.add(txRWSet)
+					txRWSet, txResult = nil, peer.TxValidationCode_DEPENDENCY_ON_INVALID_TX
+				}
+			}
 
 			txsFilter.SetFlag(txIndex, txResult)
 
 			//txRWSet != nil => t is valid
 			if txRWSet != nil {
 				committingTxHeight := version.NewHeight(block.Header.Number, uint64(txIndex))
-				addWriteSetToBatch(txRWSet, committingTxHeight, updates)
+				if err := v.addWriteSetToBatch(txRWSet, committingTxHeight, updates); err != nil {
+					return nil, err
+				}
 				txsFilter.SetFlag(txIndex, peer.TxValidationCode_VALID)
 			}
 		} else if common.HeaderType(chdr.Type) == common.HeaderType_CONFIG {
@@ -153,20 +252,66 @@ func (v *Validator) ValidateAndPrepareBatch(block *common.Block, doMVCCValidatio
 
 	}
 	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
+	// the conflicts the block may carry from an earlier validation are stale,
+	// except on recommit, where the flags they explain are kept as well
+	if doMVCCValidation {
+		if err := util.SetMVCCConflicts(block, conflicts); err != nil {
+			return nil, err
+		}
+	}
 	return updates, nil
 }
 
-func addWriteSetToBatch(txRWSet *rwsetutil.TxRwSet, txHeight *version.Height, batch *statedb.UpdateBatch) {
+func (v *Validator) addWriteSetToBatch(txRWSet *rwsetutil.TxRwSet, txHeight *version.Height, batch *statedb.UpdateBatch) error {
 	for _, nsRWSet := range txRWSet.NsRwSets {
 		ns := nsRWSet.NameSpace
 		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
 			if kvWrite.IsDelete {
 				batch.Delete(ns, kvWrite.Key, txHeight)
+				// the metadata of a key goes away with the key
+				if err := v.deleteMetadata(ns, kvWrite.Key, txHeight, batch); err != nil {
+					return err
+				}
 			} else {
 				batch.Put(ns, kvWrite.Key, kvWrite.Value, txHeight)
 			}
 		}
+		for _, metadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+			if len(metadataWrite.Entries) == 0 {
+				if err := v.deleteMetadata(ns, metadataWrite.Key, txHeight, batch); err != nil {
+					return err
+				}
+				continue
+			}
+			encodedMetadata, err := rwsetutil.EncodeMetadata(metadataWrite.Entries)
+			if err != nil {
+				logger.Panicf("Error encoding the metadata of key %s: %s", metadataWrite.Key, err)
+			}
+			batch.Put(statedb.MetadataNamespace(ns), metadataWrite.Key, encodedMetadata, txHeight)
+		}
+	}
+	return nil
+}
+
+// deleteMetadata deletes the metadata of a key only if the key has some,
+// either committed or written earlier in the block, so that the keys without
+// metadata never get entries in the metadata namespace
+func (v *Validator) deleteMetadata(ns string, key string, txHeight *version.Height, batch *statedb.UpdateBatch) error {
+	metadataNs := statedb.MetadataNamespace(ns)
+	if batch.Exists(metadataNs, key) {
+		if batch.Get(metadataNs, key).Value != nil {
+			batch.Delete(metadataNs, key, txHeight)
+		}
+		return nil
+	}
+	committed, err := v.db.GetState(metadataNs, key)
+	if err != nil {
+		return err
+	}
+	if committed != nil {
+		batch.Delete(metadataNs, key, txHeight)
 	}
+	return nil
 }
 
 func (v *Validator) validateTx(txRWSet *rwsetutil.TxRwSet, updates *statedb.UpdateBatch) (peer.TxValidationCode, error) {
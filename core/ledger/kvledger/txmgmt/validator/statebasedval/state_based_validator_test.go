@@ -20,6 +20,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
@@ -28,6 +29,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/spf13/viper"
 )
 
@@ -210,7 +212,59 @@ func TestPhantomHashBasedValidation(t *testing.T) {
 	checkValidation(t, validator, []*rwsetutil.TxRwSet{rwsetBuilder2.GetTxReadWriteSet()}, []int{0})
 }
 
-func checkValidation(t *testing.T, validator *Validator, rwsets []*rwsetutil.TxRwSet, invalidTxIndexes []int) {
+func TestMVCCConflictsInBlock(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(0, 0))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(0, 1))
+	batch.Put("ns1", "key3", []byte("value3"), version.NewHeight(0, 2))
+	db.ApplyUpdates(batch, version.NewHeight(0, 2))
+
+	rwsetBuilder0 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder0.AddToReadSet("ns1", "key1", version.NewHeight(0, 0))
+	rwsetBuilder0.AddToWriteSet("ns1", "key1", []byte("value1_new"))
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToReadSet("ns1", "key1", version.NewHeight(0, 0))
+	rwsetBuilder1.AddToReadSet("ns1", "key2", version.NewHeight(0, 0))
+	rwsetBuilder1.AddToReadSet("ns1", "key3", version.NewHeight(0, 2))
+	rwsetBuilder1.AddToReadSet("ns1", "key4", version.NewHeight(0, 3))
+	rqi := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: true}
+	rqi.SetRawReads([]*kvrwset.KVRead{rwsetutil.NewKVRead("key2", version.NewHeight(0, 0))})
+	rwsetBuilder1.AddToRangeQuerySet("ns1", rqi)
+
+	block := constructTestBlock(t, []*rwsetutil.TxRwSet{rwsetBuilder0.GetTxReadWriteSet(), rwsetBuilder1.GetTxReadWriteSet()})
+	_, err = NewValidator(db).ValidateAndPrepareBatch(block, true)
+	testutil.AssertNoError(t, err, "")
+
+	conflicts, err := util.GetMVCCConflicts(block)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNotNil(t, conflicts)
+	testutil.AssertEquals(t, len(conflicts.TxConflicts), 1)
+	txConflicts := conflicts.TxConflicts[0]
+	testutil.AssertEquals(t, txConflicts.TxNum, uint64(1))
+	testutil.AssertEquals(t, txConflicts.Keys, []*util.KeyMVCCConflict{
+		{Namespace: "ns1", Key: "key1", BlockNum: 1, TxNum: 0},
+		{Namespace: "ns1", Key: "key2", BlockNum: 0, TxNum: 1},
+		{Namespace: "ns1", Key: "key4", Deleted: true}})
+	testutil.AssertEquals(t, txConflicts.Ranges, []*util.RangeMVCCConflict{{Namespace: "ns1", StartKey: "key2", EndKey: "key4"}})
+
+	// no conflicts are recorded for blocks without MVCC invalidated transactions,
+	// and the ones they carry from an earlier validation are cleared
+	block = constructTestBlock(t, []*rwsetutil.TxRwSet{rwsetBuilder0.GetTxReadWriteSet()})
+	testutil.AssertNoError(t, util.SetMVCCConflicts(block, conflicts), "")
+	_, err = NewValidator(db).ValidateAndPrepareBatch(block, true)
+	testutil.AssertNoError(t, err, "")
+	conflicts, err = util.GetMVCCConflicts(block)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, conflicts)
+}
+
+func constructTestBlock(t *testing.T, rwsets []*rwsetutil.TxRwSet) *common.Block {
 	simulationResults := [][]byte{}
 	for _, txRWS := range rwsets {
 		sr, err := txRWS.ToProtoBytes()
@@ -219,6 +273,11 @@ func checkValidation(t *testing.T, validator *Validator, rwsets []*rwsetutil.TxR
 	}
 	block := testutil.ConstructBlock(t, 1, []byte("dummyPreviousHash"), simulationResults, false)
 	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = util.NewTxValidationFlags(len(block.Data.Data))
+	return block
+}
+
+func checkValidation(t *testing.T, validator *Validator, rwsets []*rwsetutil.TxRwSet, invalidTxIndexes []int) {
+	block := constructTestBlock(t, rwsets)
 	_, err := validator.ValidateAndPrepareBatch(block, true)
 	txsFltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
 	invalidTxs := make([]int, 0)
@@ -245,3 +304,87 @@ func buildTestHashResults(t *testing.T, maxDegree int, kvReads []*kvrwset.KVRead
 	testutil.AssertNotNil(t, h)
 	return h
 }
+
+func TestDeleteMetadata(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+
+	metadata, err := rwsetutil.EncodeMetadata([]*kvrwset.KVMetadataEntry{{Name: "VALIDATION_PARAMETER", Value: []byte("policy")}})
+	testutil.AssertNoError(t, err, "")
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	batch.Put(statedb.MetadataNamespace("ns1"), "key1", metadata, version.NewHeight(1, 0))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 1))
+	db.ApplyUpdates(batch, version.NewHeight(1, 1))
+
+	// tx0 deletes key1, which has metadata, and key2, which has none, and tx1
+	// clears the metadata of key2
+	rwsetBuilder0 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder0.AddToWriteSet("ns1", "key1", nil)
+	rwsetBuilder0.AddToWriteSet("ns1", "key2", nil)
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToMetadataWriteSet("ns1", "key2", nil)
+
+	block := constructTestBlock(t, []*rwsetutil.TxRwSet{rwsetBuilder0.GetTxReadWriteSet(), rwsetBuilder1.GetTxReadWriteSet()})
+	updates, err := NewValidator(db).ValidateAndPrepareBatch(block, true)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, updates.Exists(statedb.MetadataNamespace("ns1"), "key1"), true)
+	testutil.AssertNil(t, updates.Get(statedb.MetadataNamespace("ns1"), "key1").Value)
+	testutil.AssertEquals(t, updates.Exists(statedb.MetadataNamespace("ns1"), "key2"), false)
+}
+
+func TestDependencyInvalidation(t *testing.T) {
+	testDBEnv := stateleveldb.NewTestVDBEnv(t)
+	defer testDBEnv.Cleanup()
+
+	db, err := testDBEnv.DBProvider.GetDBHandle("TestDB")
+	testutil.AssertNoError(t, err, "")
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key3", []byte("value3"), version.NewHeight(1, 2))
+	db.ApplyUpdates(batch, version.NewHeight(1, 2))
+
+	// tx0 fails MVCC validation, tx1 reads key2 which tx0 wrote, tx2 reads
+	// key3 which tx1 wrote, tx3 range queries over key2 and tx4 is independent
+	rwsetBuilder0 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder0.AddToReadSet("ns1", "key1", version.NewHeight(0, 0))
+	rwsetBuilder0.AddToWriteSet("ns1", "key2", []byte("value2_new"))
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToReadSet("ns1", "key2", version.NewHeight(1, 1))
+	rwsetBuilder1.AddToWriteSet("ns1", "key3", []byte("value3_new"))
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder2.AddToReadSet("ns1", "key3", version.NewHeight(1, 2))
+	rwsetBuilder3 := rwsetutil.NewRWSetBuilder()
+	rqi := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key3", ItrExhausted: true}
+	rqi.SetRawReads([]*kvrwset.KVRead{rwsetutil.NewKVRead("key2", version.NewHeight(1, 1))})
+	rwsetBuilder3.AddToRangeQuerySet("ns1", rqi)
+	rwsetBuilder4 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder4.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	rwsetBuilder4.AddToWriteSet("ns1", "key1", []byte("value1_new"))
+	rwsets := []*rwsetutil.TxRwSet{rwsetBuilder0.GetTxReadWriteSet(), rwsetBuilder1.GetTxReadWriteSet(),
+		rwsetBuilder2.GetTxReadWriteSet(), rwsetBuilder3.GetTxReadWriteSet(), rwsetBuilder4.GetTxReadWriteSet()}
+
+	// without the capability, tx0 is invalid and tx2 fails MVCC validation over the key3 tx1 wrote
+	validator := NewValidator(db)
+	checkValidation(t, validator, rwsets, []int{0, 2})
+	validator.SetCapabilityChecker(func(name string) bool { return false })
+	checkValidation(t, validator, rwsets, []int{0, 2})
+
+	validator.SetCapabilityChecker(func(name string) bool { return name == config.DependencyInvalidationCapability })
+	block := constructTestBlock(t, rwsets)
+	updates, err := validator.ValidateAndPrepareBatch(block, true)
+	testutil.AssertNoError(t, err, "")
+	txsFltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	testutil.AssertEquals(t, txsFltr.Flag(0), peer.TxValidationCode_MVCC_READ_CONFLICT)
+	for _, txIndex := range []int{1, 2, 3} {
+		testutil.AssertEquals(t, txsFltr.Flag(txIndex), peer.TxValidationCode_DEPENDENCY_ON_INVALID_TX)
+	}
+	testutil.AssertEquals(t, txsFltr.Flag(4), peer.TxValidationCode_VALID)
+	testutil.AssertNil(t, updates.Get("ns1", "key3"))
+	testutil.AssertEquals(t, updates.Get("ns1", "key1").Value, []byte("value1_new"))
+}
@@ -24,4 +24,7 @@ import (
 // Validator validates a rwset
 type Validator interface {
 	ValidateAndPrepareBatch(block *common.Block, doMVCCValidation bool) (*statedb.UpdateBatch, error)
+	// SetCapabilityChecker sets the function telling whether the channel requires
+	// a capability of its peers, which the validation of the next blocks depends on
+	SetCapabilityChecker(hasCapability func(name string) bool)
 }
@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvledger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+)
+
+// UpgradeDBs migrates the ledger data to the format of this version of the peer.
+// It must be invoked while the peer is stopped. The databases that are derived from
+// the blocks are dropped when their format changed and get rebuilt from the blocks
+// when the peer restarts, as part of the recovery of the ledgers
+func UpgradeDBs() error {
+	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	defer idStore.close()
+
+	if err := upgradeDataFormat(idStore); err != nil {
+		return err
+	}
+
+	// the state kept in CouchDB moved from a database per channel to a
+	// database per namespace, independently of the format of the ledger data
+	if ledgerconfig.IsCouchDBEnabled() {
+		ledgerIDs, err := idStore.getAllLedgerIds()
+		if err != nil {
+			return err
+		}
+		if err = statecouchdb.DropLegacyLayoutDBs(ledgerIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upgradeDataFormat migrates the ledger data recorded in the format
+// preceding the current one
+func upgradeDataFormat(idStore *idStore) error {
+	format, err := idStore.getDataFormat()
+	if err != nil {
+		return err
+	}
+	if format == nil || bytes.Equal(format, currentDataFormat) {
+		logger.Infof("Ledger data is in format [%s], no upgrade is needed", currentDataFormat)
+		return idStore.checkDataFormat()
+	}
+
+	if !bytes.Equal(format, preVersioningDataFormat) {
+		return fmt.Errorf("Cannot upgrade ledger data from format [%s] to format [%s], "+
+			"the ledger data was probably written by a more recent version of the peer", format, currentDataFormat)
+	}
+
+	logger.Infof("Upgrading ledger data from format [%s] to format [%s]", format, currentDataFormat)
+	// the keys of the history database are length-prefixed since format 2.0
+	if err := dropHistoryDB(); err != nil {
+		return err
+	}
+
+	if err := idStore.setDataFormat(currentDataFormat); err != nil {
+		return err
+	}
+	logger.Infof("Ledger data upgraded to format [%s]", currentDataFormat)
+	return nil
+}
+
+// dropHistoryDB drops the history database, to be rebuilt from the blocks
+func dropHistoryDB() error {
+	historyDatabase := ledgerconfig.GetHistoryDatabase()
+	if historyDatabase != "goleveldb" {
+		logger.Warningf("History database %s is not dropped, drop it to have it rebuilt from the blocks in format [%s]", historyDatabase, currentDataFormat)
+		return nil
+	}
+	historyDBPath := ledgerconfig.GetHistoryLevelDBPath()
+	logger.Infof("Dropping history database at %s, it will be rebuilt from the blocks when the peer restarts", historyDBPath)
+	return os.RemoveAll(historyDBPath)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvledger
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
+	"github.com/spf13/viper"
+)
+
+func TestUpgradeDBs(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+	defer ledgertestutil.ResetConfigToDefaultValues()
+
+	provider, err := NewProvider()
+	testutil.AssertNoError(t, err, "")
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	simulator, _ := ledger.NewTxSimulator()
+	simulator.SetState("ns1", "key1", []byte("value1"))
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	testutil.AssertNoError(t, ledger.Commit(bg.NextBlock([][]byte{simRes})), "")
+	ledger.Close()
+	provider.Close()
+
+	// a peer without recorded data format has data in the format preceding versioning
+	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	testutil.AssertNoError(t, idStore.db.Delete(dataFormatKey, true), "")
+	idStore.close()
+
+	_, err = NewProvider()
+	testutil.AssertError(t, err, "The ledger provider should refuse data in a previous format")
+	_, ok := err.(*ErrDataFormatMismatch)
+	testutil.AssertEquals(t, ok, true)
+
+	testutil.AssertNoError(t, UpgradeDBs(), "")
+	_, err = os.Stat(ledgerconfig.GetHistoryLevelDBPath())
+	testutil.AssertEquals(t, os.IsNotExist(err), true)
+
+	// the history is rebuilt from the blocks
+	provider, err = NewProvider()
+	testutil.AssertNoError(t, err, "")
+	ledger, err = provider.Open("testLedger")
+	testutil.AssertNoError(t, err, "")
+	qhistory, err := ledger.NewHistoryQueryExecutor()
+	testutil.AssertNoError(t, err, "")
+	itr, err := qhistory.GetHistoryForKey("ns1", "key1")
+	testutil.AssertNoError(t, err, "")
+	res, err := itr.Next()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNotNil(t, res)
+	itr.Close()
+	ledger.Close()
+	provider.Close()
+
+	// upgrading data in the current format is a no-op
+	testutil.AssertNoError(t, UpgradeDBs(), "")
+}
+
+func TestUpgradeDBsUnknownFormat(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+
+	provider, err := NewProvider()
+	testutil.AssertNoError(t, err, "")
+	provider.Close()
+
+	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	testutil.AssertNoError(t, idStore.setDataFormat([]byte("99.0")), "")
+	idStore.close()
+
+	_, err = NewProvider()
+	testutil.AssertError(t, err, "The ledger provider should refuse data in an unknown format")
+	testutil.AssertError(t, UpgradeDBs(), "Data in an unknown format should not be upgraded")
+}
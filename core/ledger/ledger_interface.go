@@ -65,6 +65,10 @@ type PeerLedger interface {
 	NewHistoryQueryExecutor() (HistoryQueryExecutor, error)
 	//Prune prunes the blocks/transactions that satisfy the given policy
 	Prune(policy commonledger.PrunePolicy) error
+	// SetCapabilityChecker sets the function telling whether the channel config requires a capability
+	// of its peers, which changes how the transactions of the blocks committed afterwards are validated.
+	// Until it is set, the ledger validates the blocks as if no capability was required
+	SetCapabilityChecker(hasCapability func(name string) bool)
 }
 
 // ValidatedLedger represents the 'final ledger' after filtering out invalid transactions from PeerLedger.
@@ -94,6 +98,10 @@ type QueryExecutor interface {
 	// For a chaincode, the namespace corresponds to the chaincodeId
 	// The returned ResultsIterator contains results of type *KV which is defined in protos/ledger/queryresult.
 	ExecuteQuery(namespace, query string) (commonledger.ResultsIterator, error)
+	// GetStateMetadata returns the metadata of the given namespace and key, e.g. the validation parameters
+	// of key-level endorsement policies, or nil if the key has no metadata. When simulating a transaction,
+	// the read is recorded in the read set, so that a concurrent change of the metadata invalidates it
+	GetStateMetadata(namespace, key string) (map[string][]byte, error)
 	// Done releases resources occupied by the QueryExecutor
 	Done()
 }
@@ -115,6 +123,9 @@ type TxSimulator interface {
 	DeleteState(namespace string, key string) error
 	// SetMultipleKeys sets the values for multiple keys in a single call
 	SetStateMultipleKeys(namespace string, kvs map[string][]byte) error
+	// SetStateMetadata replaces the whole metadata of the given namespace and key, an empty metadata deletes it.
+	// Deleting a key also deletes its metadata
+	SetStateMetadata(namespace, key string, metadata map[string][]byte) error
 	// ExecuteUpdate for supporting rich data model (see comments on QueryExecutor above)
 	ExecuteUpdate(query string) error
 	// GetTxSimulationResults encapsulates the results of the transaction simulation.
@@ -0,0 +1,282 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledgeraudit
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+var logger = flogging.MustGetLogger("ledgeraudit")
+
+// The checks an Issue can be found by
+const (
+	// CheckHashChain verifies that the previous hash of each block is the hash of the header of the prior block
+	CheckHashChain = "hashChain"
+	// CheckDataHash verifies that the data hash of each block is the hash of its data
+	CheckDataHash = "dataHash"
+	// CheckSignatures verifies that the signatures of each block satisfy the
+	// BlockValidation policy of the config in effect at its height
+	CheckSignatures = "signatures"
+	// CheckConfig verifies that the config blocks can be parsed
+	CheckConfig = "config"
+	// CheckTxIndex verifies that the transactions indexed by ID are those of the blocks
+	CheckTxIndex = "txIndex"
+)
+
+// Report is the outcome of the verification of the blocks of a channel
+type Report struct {
+	ChainID        string  `json:"channel"`
+	Height         uint64  `json:"height"`
+	VerifiedBlocks uint64  `json:"verifiedBlocks"`
+	Transactions   uint64  `json:"transactions"`
+	Issues         []Issue `json:"issues"`
+}
+
+// Issue describes an integrity problem found in a block
+type Issue struct {
+	Block  uint64 `json:"block"`
+	Check  string `json:"check"`
+	Detail string `json:"detail"`
+}
+
+// OK returns true when no issue was found
+func (r *Report) OK() bool {
+	return len(r.Issues) == 0
+}
+
+func (r *Report) addIssue(blockNum uint64, check string, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, Issue{Block: blockNum, Check: check, Detail: fmt.Sprintf(format, args...)})
+}
+
+// VerifyLedger opens the block store of a channel under the file system path
+// of the peer and verifies its blocks. The peer must be stopped, the block
+// store is opened directly.
+func VerifyLedger(chainID string) (*Report, error) {
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: []blkstorage.IndexableAttr{
+		blkstorage.IndexableAttrBlockNum,
+		blkstorage.IndexableAttrTxID,
+		blkstorage.IndexableAttrBlockTxID,
+		blkstorage.IndexableAttrTxValidationCode,
+	}}
+	provider := fsblkstorage.NewProvider(
+		fsblkstorage.NewConfWithLedgerDirs(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize(), ledgerconfig.GetChannelBlockStorePath),
+		indexConfig)
+	defer provider.Close()
+
+	exists, err := provider.Exists(chainID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("no ledger for channel %s under %s", chainID, ledgerconfig.GetBlockStorePath())
+	}
+	store, err := provider.OpenBlockStore(chainID)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Shutdown()
+
+	return VerifyBlockStore(chainID, store)
+}
+
+// VerifyBlockStore walks the blocks of a channel from the genesis block,
+// verifying the chaining of their headers, their signatures against the
+// config in effect at their height, and the consistency of the index of
+// their transactions. The genesis block is the root of trust, its
+// signatures are not verified. Identities are validated at the time of
+// the verification, the signatures of certificates expired since are
+// reported as not satisfying the policy.
+func VerifyBlockStore(chainID string, store blkstorage.BlockStore) (*Report, error) {
+	info, err := store.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{ChainID: chainID, Height: info.Height}
+
+	var prevHash []byte
+	var blockValidation policies.Policy
+	for num := uint64(0); num < info.Height; num++ {
+		block, err := store.RetrieveBlockByNumber(num)
+		if err != nil {
+			report.addIssue(num, CheckHashChain, "block can't be read: %s", err)
+			return report, nil
+		}
+		logger.Debugf("Verifying block %d of channel %s", num, chainID)
+
+		if num > 0 && !bytes.Equal(block.Header.PreviousHash, prevHash) {
+			report.addIssue(num, CheckHashChain, "previous hash %x doesn't match the hash %x of block %d", block.Header.PreviousHash, prevHash, num-1)
+		}
+		if !bytes.Equal(block.Data.Hash(), block.Header.DataHash) {
+			report.addIssue(num, CheckDataHash, "data hash %x doesn't match the hash of the data %x", block.Header.DataHash, block.Data.Hash())
+		}
+		if num > 0 {
+			if blockValidation == nil {
+				report.addIssue(num, CheckSignatures, "no block validation policy to verify the signatures against")
+			} else if err := blockValidation.Evaluate(blockSignatureSet(block)); err != nil {
+				report.addIssue(num, CheckSignatures, "signatures don't satisfy the block validation policy: %s", err)
+			}
+		}
+		report.Transactions += verifyTxIndex(report, store, block)
+
+		if isConfigBlock(block) {
+			// The next blocks are signed according to the new config
+			policy, err := blockValidationPolicy(block)
+			if err != nil {
+				report.addIssue(num, CheckConfig, "config can't be parsed: %s", err)
+			} else {
+				blockValidation = policy
+			}
+		}
+
+		prevHash = block.Header.Hash()
+		report.VerifiedBlocks++
+	}
+
+	if info.Height > 0 && !bytes.Equal(info.CurrentBlockHash, prevHash) {
+		report.addIssue(info.Height-1, CheckHashChain, "hash %x of the last block doesn't match the hash %x recorded by the block store", prevHash, info.CurrentBlockHash)
+	}
+	return report, nil
+}
+
+// blockSignatureSet returns the signatures of a block as SignedData,
+// counting a single signature per creator like the committers
+func blockSignatureSet(block *common.Block) []*common.SignedData {
+	metadata, err := utils.GetMetadataFromBlock(block, common.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return nil
+	}
+	var signatureSet []*common.SignedData
+	creators := make(map[string]struct{})
+	for _, metadataSignature := range metadata.Signatures {
+		shdr, err := utils.GetSignatureHeader(metadataSignature.SignatureHeader)
+		if err != nil {
+			continue
+		}
+		if _, signed := creators[string(shdr.Creator)]; signed {
+			continue
+		}
+		creators[string(shdr.Creator)] = struct{}{}
+		signatureSet = append(signatureSet, &common.SignedData{
+			Identity:  shdr.Creator,
+			Data:      util.ConcatenateBytes(metadata.Value, metadataSignature.SignatureHeader, block.Header.Bytes()),
+			Signature: metadataSignature.Signature,
+		})
+	}
+	return signatureSet
+}
+
+// verifyTxIndex checks that the transactions of the block are those the
+// index returns for their IDs, with the same validation code, and returns
+// the number of transactions of the block
+func verifyTxIndex(report *Report, store blkstorage.BlockStore, block *common.Block) uint64 {
+	num := block.Header.Number
+	txsFilter := ledgerUtil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for txIndex, envBytes := range block.Data.Data {
+		txID := txIDOf(envBytes)
+		if txID == "" {
+			continue
+		}
+		if txIndex < len(txsFilter) && txsFilter.IsSetTo(txIndex, peer.TxValidationCode_DUPLICATE_TXID) {
+			// The index holds the original transaction
+			continue
+		}
+
+		indexed, err := store.RetrieveTxByID(txID)
+		if err != nil {
+			report.addIssue(num, CheckTxIndex, "transaction %s can't be retrieved by ID: %s", txID, err)
+			continue
+		}
+		if indexedBytes, err := proto.Marshal(indexed); err != nil || !bytes.Equal(indexedBytes, envBytes) {
+			report.addIssue(num, CheckTxIndex, "transaction %s retrieved by ID differs from transaction %d of the block", txID, txIndex)
+		}
+		if txBlock, err := store.RetrieveBlockByTxID(txID); err != nil || txBlock.Header.Number != num {
+			report.addIssue(num, CheckTxIndex, "transaction %s isn't indexed in its block", txID)
+		}
+		if txIndex < len(txsFilter) {
+			if code, err := store.RetrieveTxValidationCodeByTxID(txID); err != nil || code != txsFilter.Flag(txIndex) {
+				report.addIssue(num, CheckTxIndex, "validation code of transaction %s in the index doesn't match %s in the block", txID, txsFilter.Flag(txIndex))
+			}
+		}
+	}
+	return uint64(len(block.Data.Data))
+}
+
+func txIDOf(envBytes []byte) string {
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return ""
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil || payload.Header == nil {
+		return ""
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return ""
+	}
+	return chdr.TxId
+}
+
+func isConfigBlock(block *common.Block) bool {
+	if block.Data == nil || len(block.Data.Data) != 1 {
+		return false
+	}
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return false
+	}
+	payload, err := utils.ExtractPayload(env)
+	if err != nil || payload.Header == nil {
+		return false
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return false
+	}
+	return chdr.Type == int32(common.HeaderType_CONFIG)
+}
+
+// blockValidationPolicy returns the BlockValidation policy of the config of a config block
+func blockValidationPolicy(block *common.Block) (policies.Policy, error) {
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := configtx.NewManagerImpl(env, configtx.NewInitializer(), nil)
+	if err != nil {
+		return nil, err
+	}
+	policy, ok := manager.PolicyManager().GetPolicy(policies.BlockValidation)
+	if !ok {
+		return nil, fmt.Errorf("no %s policy", policies.BlockValidation)
+	}
+	return policy, nil
+}
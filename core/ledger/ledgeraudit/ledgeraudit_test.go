@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledgeraudit
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPath = "/tmp/fabric/ledgertests/ledgeraudit"
+
+func TestMain(m *testing.M) {
+	viper.Set("peer.fileSystemPath", testPath)
+	os.Exit(m.Run())
+}
+
+func writeBlockStore(t *testing.T, chainID string, modify func(blocks []*common.Block)) {
+	os.RemoveAll(testPath)
+	provider := fsblkstorage.NewProvider(
+		fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize()),
+		&blkstorage.IndexConfig{AttrsToIndex: []blkstorage.IndexableAttr{
+			blkstorage.IndexableAttrBlockNum,
+			blkstorage.IndexableAttrTxID,
+			blkstorage.IndexableAttrBlockTxID,
+			blkstorage.IndexableAttrTxValidationCode,
+		}})
+	defer provider.Close()
+	store, err := provider.OpenBlockStore(chainID)
+	assert.NoError(t, err)
+	defer store.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, chainID, false)
+	blocks := append([]*common.Block{gb}, bg.NextTestBlocks(3)...)
+	if modify != nil {
+		modify(blocks)
+	}
+	for _, block := range blocks {
+		assert.NoError(t, store.AddBlock(block))
+	}
+}
+
+func TestVerifyLedger(t *testing.T) {
+	defer os.RemoveAll(testPath)
+
+	writeBlockStore(t, "testchain", nil)
+	report, err := VerifyLedger("testchain")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), report.Height)
+	assert.Equal(t, uint64(4), report.VerifiedBlocks)
+	assert.Equal(t, uint64(31), report.Transactions)
+	for _, issue := range report.Issues {
+		assert.Equal(t, CheckSignatures, issue.Check, "Should only have found unsigned blocks, found %v", issue)
+	}
+
+	_, err = VerifyLedger("otherchain")
+	assert.Error(t, err, "Should have failed to verify a channel without ledger")
+}
+
+func TestVerifyLedgerTampered(t *testing.T) {
+	defer os.RemoveAll(testPath)
+
+	writeBlockStore(t, "testchain", func(blocks []*common.Block) {
+		blocks[2].Data.Data[0] = blocks[1].Data.Data[0]
+	})
+	report, err := VerifyLedger("testchain")
+	assert.NoError(t, err)
+	assert.False(t, report.OK())
+
+	issues := make(map[string][]uint64)
+	for _, issue := range report.Issues {
+		issues[issue.Check] = append(issues[issue.Check], issue.Block)
+	}
+	assert.Equal(t, []uint64{2}, issues[CheckDataHash], "Should have found the data of block 2 altered")
+	assert.Contains(t, issues[CheckTxIndex], uint64(1), "Should have found the transaction of block 1 indexed in block 2")
+	assert.Empty(t, issues[CheckHashChain], "Should not have found the headers altered")
+}
@@ -18,6 +18,7 @@ package ledgerconfig
 
 import (
 	"path/filepath"
+	"strings"
 
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/spf13/viper"
@@ -32,6 +33,24 @@ func IsCouchDBEnabled() bool {
 	return false
 }
 
+// GetStateDatabase returns the name of the state database, goleveldb by default
+func GetStateDatabase() string {
+	stateDatabase := viper.GetString("ledger.state.stateDatabase")
+	if stateDatabase == "" {
+		return "goleveldb"
+	}
+	return stateDatabase
+}
+
+// GetHistoryDatabase returns the name of the history database, goleveldb by default
+func GetHistoryDatabase() string {
+	historyDatabase := viper.GetString("ledger.history.historyDatabase")
+	if historyDatabase == "" {
+		return "goleveldb"
+	}
+	return historyDatabase
+}
+
 // GetRootPath returns the filesystem path.
 // All ledger related contents are expected to be stored under this path
 func GetRootPath() string {
@@ -54,11 +73,51 @@ func GetHistoryLevelDBPath() string {
 	return filepath.Join(GetRootPath(), "historyLeveldb")
 }
 
+// GetChangeFeedPath returns the filesystem path that is used to maintain the change feeds
+func GetChangeFeedPath() string {
+	return filepath.Join(GetRootPath(), "changeFeed")
+}
+
 // GetBlockStorePath returns the filesystem path that is used for the chain block stores
 func GetBlockStorePath() string {
 	return filepath.Join(GetRootPath(), "chains")
 }
 
+// GetChannelRootPath returns the directory holding the block storage and the
+// state database of a channel, as configured in ledger.channelPaths or by
+// ledger.channelPathTemplate, or an empty string if the channel is kept in the
+// shared stores under peer.fileSystemPath
+func GetChannelRootPath(chainID string) string {
+	// viper lower-cases the keys of the map, as are the channel names
+	if path := viper.GetStringMapString("ledger.channelPaths")[strings.ToLower(chainID)]; path != "" {
+		return path
+	}
+	if template := viper.GetString("ledger.channelPathTemplate"); template != "" {
+		return strings.Replace(template, "{channel}", chainID, -1)
+	}
+	return ""
+}
+
+// GetChannelBlockStorePath returns the filesystem path of the block store of
+// a channel kept on its own volume, empty if the channel is not
+func GetChannelBlockStorePath(chainID string) string {
+	root := GetChannelRootPath(chainID)
+	if root == "" {
+		return ""
+	}
+	return filepath.Join(root, "chains")
+}
+
+// GetChannelStateLevelDBPath returns the filesystem path of the state level db
+// of a channel kept on its own volume, empty if the channel is not
+func GetChannelStateLevelDBPath(chainID string) string {
+	root := GetChannelRootPath(chainID)
+	if root == "" {
+		return ""
+	}
+	return filepath.Join(root, "stateLeveldb")
+}
+
 // GetMaxBlockfileSize returns maximum size of the block file
 func GetMaxBlockfileSize() int {
 	return 64 * 1024 * 1024
@@ -79,6 +138,50 @@ func IsHistoryDBEnabled() bool {
 	return viper.GetBool("ledger.history.enableHistoryDatabase")
 }
 
+// IsChangeFeedEnabled exposes the changeFeed.enabled variable
+func IsChangeFeedEnabled() bool {
+	return viper.GetBool("ledger.changeFeed.enabled")
+}
+
+// GetChangeFeedMaxFileSize returns the size in bytes at which the change feed
+// files are rotated, 0 if they are never rotated
+func GetChangeFeedMaxFileSize() int64 {
+	return int64(viper.GetInt("ledger.changeFeed.maxFileSize"))
+}
+
+// GetChangeFeedMaxFiles returns the number of rotated change feed files kept
+// per channel, 0 if all of them are kept
+func GetChangeFeedMaxFiles() int {
+	return viper.GetInt("ledger.changeFeed.maxFiles")
+}
+
+// IsCommitPipelineEnabled exposes the commitPipeline variable
+func IsCommitPipelineEnabled() bool {
+	return viper.GetBool("ledger.commit.pipeline")
+}
+
+// GetMaxBlockBytes returns the largest size in bytes of the blocks the peer
+// commits, 0 if the size of the blocks isn't limited
+func GetMaxBlockBytes() int {
+	return viper.GetInt("ledger.commit.maxBlockBytes")
+}
+
+// GetMaxBlockTransactions returns the largest number of transactions of the
+// blocks the peer commits, 0 if the number of transactions isn't limited
+func GetMaxBlockTransactions() int {
+	return viper.GetInt("ledger.commit.maxBlockTransactions")
+}
+
+// IsHashChainVerificationEnabled returns whether the committer checks that
+// the previous hash of each block matches the hash of the header of the
+// prior block, true unless disabled
+func IsHashChainVerificationEnabled() bool {
+	if !viper.IsSet("ledger.commit.verifyHashChain") {
+		return true
+	}
+	return viper.GetBool("ledger.commit.verifyHashChain")
+}
+
 // IsQueryReadsHashingEnabled enables or disables computing of hash
 // of range query results for phantom item validation
 func IsQueryReadsHashingEnabled() bool {
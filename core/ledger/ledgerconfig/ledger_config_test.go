@@ -46,6 +46,23 @@ func TestIsCouchDBEnabled(t *testing.T) {
 	testutil.AssertEquals(t, updatedValue, true) //test config returns true
 }
 
+func TestGetStateDatabase(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.state.stateDatabase", "")
+	testutil.AssertEquals(t, GetStateDatabase(), "goleveldb") //test goleveldb is the default
+	viper.Set("ledger.state.stateDatabase", "CouchDB")
+	testutil.AssertEquals(t, GetStateDatabase(), "CouchDB")
+}
+
+func TestGetHistoryDatabase(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	testutil.AssertEquals(t, GetHistoryDatabase(), "goleveldb") //test default config is goleveldb
+	viper.Set("ledger.history.historyDatabase", "")
+	testutil.AssertEquals(t, GetHistoryDatabase(), "goleveldb")
+}
+
 func TestIsHistoryDBEnabledDefault(t *testing.T) {
 	setUpCoreYAMLConfig()
 	defaultValue := IsHistoryDBEnabled()
@@ -72,3 +89,19 @@ func setUpCoreYAMLConfig() {
 	//call a helper method to load the core.yaml
 	ledgertestutil.SetupCoreYAMLConfig()
 }
+
+func TestGetChannelRootPath(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	testutil.AssertEquals(t, GetChannelRootPath("mychannel"), "")
+	testutil.AssertEquals(t, GetChannelBlockStorePath("mychannel"), "")
+	testutil.AssertEquals(t, GetChannelStateLevelDBPath("mychannel"), "")
+
+	viper.Set("ledger.channelPathTemplate", "/mnt/channels/{channel}")
+	viper.Set("ledger.channelPaths", map[string]string{"bigchannel": "/mnt/vol1"})
+	defer viper.Set("ledger.channelPathTemplate", "")
+	defer viper.Set("ledger.channelPaths", nil)
+	testutil.AssertEquals(t, GetChannelRootPath("bigchannel"), "/mnt/vol1")
+	testutil.AssertEquals(t, GetChannelBlockStorePath("bigchannel"), "/mnt/vol1/chains")
+	testutil.AssertEquals(t, GetChannelStateLevelDBPath("mychannel"), "/mnt/channels/mychannel/stateLeveldb")
+}
@@ -121,6 +121,17 @@ func GetLedgerIDs() ([]string, error) {
 	return ledgerProvider.List()
 }
 
+// UpgradeDBs migrates the ledger data to the format of this version of the peer.
+// It cannot be invoked once ledger mgmt is initialized, since the ledger data should not be in use
+func UpgradeDBs() error {
+	lock.Lock()
+	defer lock.Unlock()
+	if initialized {
+		return errors.New("ledger data cannot be upgraded while ledger mgmt is initialized")
+	}
+	return kvledger.UpgradeDBs()
+}
+
 // Close closes all the opened ledgers and any resources held for ledger management
 func Close() {
 	logger.Infof("Closing ledger mgmt")
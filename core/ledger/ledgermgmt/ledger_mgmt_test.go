@@ -70,3 +70,9 @@ func TestLedgerMgmt(t *testing.T) {
 func constructTestLedgerID(i int) string {
 	return fmt.Sprintf("ledger_%06d", i)
 }
+
+func TestUpgradeDBsWhileInitialized(t *testing.T) {
+	InitializeTestEnv()
+	defer CleanupTestEnv()
+	testutil.AssertError(t, UpgradeDBs(), "Ledger data should not be upgraded while in use")
+}
@@ -19,6 +19,7 @@ package couchdb
 import (
 	"time"
 
+	"github.com/hyperledger/fabric/core/config"
 	"github.com/spf13/viper"
 )
 
@@ -30,6 +31,16 @@ type CouchDBDef struct {
 	MaxRetries          int
 	MaxRetriesOnStartup int
 	RequestTimeout      time.Duration
+	MaxConnections      int
+	TLS                 CouchDBTLSDef
+}
+
+// CouchDBTLSDef contains the TLS parameters for the connection to CouchDB
+type CouchDBTLSDef struct {
+	Enabled        bool
+	RootCertFile   string
+	ClientCertFile string
+	ClientKeyFile  string
 }
 
 //GetCouchDBDefinition exposes the useCouchDB variable
@@ -41,6 +52,15 @@ func GetCouchDBDefinition() *CouchDBDef {
 	maxRetries := viper.GetInt("ledger.state.couchDBConfig.maxRetries")
 	maxRetriesOnStartup := viper.GetInt("ledger.state.couchDBConfig.maxRetriesOnStartup")
 	requestTimeout := viper.GetDuration("ledger.state.couchDBConfig.requestTimeout")
+	maxConnections := viper.GetInt("ledger.state.couchDBConfig.maxConnections")
+
+	tlsDef := CouchDBTLSDef{Enabled: viper.GetBool("ledger.state.couchDBConfig.tls.enabled")}
+	if tlsDef.Enabled {
+		tlsDef.RootCertFile = config.GetPath("ledger.state.couchDBConfig.tls.rootCertFile")
+		tlsDef.ClientCertFile = config.GetPath("ledger.state.couchDBConfig.tls.clientCertFile")
+		tlsDef.ClientKeyFile = config.GetPath("ledger.state.couchDBConfig.tls.clientKeyFile")
+	}
 
-	return &CouchDBDef{couchDBAddress, username, password, maxRetries, maxRetriesOnStartup, requestTimeout}
+	return &CouchDBDef{couchDBAddress, username, password, maxRetries, maxRetriesOnStartup, requestTimeout,
+		maxConnections, tlsDef}
 }
@@ -31,4 +31,6 @@ func TestGetCouchDBDefinition(t *testing.T) {
 	testutil.AssertEquals(t, couchDBDef.MaxRetries, 3)
 	testutil.AssertEquals(t, couchDBDef.MaxRetriesOnStartup, 10)
 	testutil.AssertEquals(t, couchDBDef.RequestTimeout, time.Second*35)
+	testutil.AssertEquals(t, couchDBDef.MaxConnections, 100)
+	testutil.AssertEquals(t, couchDBDef.TLS.Enabled, false)
 }
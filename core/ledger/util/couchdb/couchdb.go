@@ -131,12 +131,14 @@ type CouchConnectionDef struct {
 	MaxRetries          int
 	MaxRetriesOnStartup int
 	RequestTimeout      time.Duration
+	MaxConnections      int
 }
 
 //CouchInstance represents a CouchDB instance
 type CouchInstance struct {
-	conf   CouchConnectionDef //connection configuration
-	client *http.Client       // a client to connect to this instance
+	conf         CouchConnectionDef //connection configuration
+	client       *http.Client       // a client to connect to this instance
+	requestSlots chan struct{}      // bounds the concurrent requests, nil if unbounded
 }
 
 //CouchDatabase represents a database within a CouchDB instance
@@ -237,10 +239,37 @@ func CreateConnectionDefinition(couchDBAddress, username, password string, maxRe
 
 	//return an object containing the connection information
 	return &CouchConnectionDef{finalURL.String(), username, password, maxRetries,
-		maxRetriesOnStartup, requestTimeout}, nil
+		maxRetriesOnStartup, requestTimeout, 0}, nil
 
 }
 
+//ListDatabases method provides function to list the names of the databases of the instance
+func (couchInstance *CouchInstance) ListDatabases() ([]string, error) {
+
+	logger.Debugf("Entering ListDatabases()")
+	defer logger.Debugf("Exiting ListDatabases()")
+
+	connectURL, err := url.Parse(couchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err.Error())
+		return nil, err
+	}
+	connectURL.Path = "/_all_dbs"
+
+	resp, _, err := couchInstance.handleRequest(http.MethodGet, connectURL.String(), nil, "", "", couchInstance.conf.MaxRetries)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+
+	var dbNames []string
+	if err = json.NewDecoder(resp.Body).Decode(&dbNames); err != nil {
+		return nil, err
+	}
+
+	return dbNames, nil
+}
+
 //CreateDatabaseIfNotExist method provides function to create database
 func (dbclient *CouchDatabase) CreateDatabaseIfNotExist() (*DBOperationResponse, error) {
 
@@ -1230,8 +1259,10 @@ func (couchInstance *CouchInstance) handleRequest(method, connectURL string, dat
 			logger.Debugf("HTTP Request: %s", bytes.Replace(dump, []byte{0x0d, 0x0a}, []byte{0x20, 0x7c, 0x20}, -1))
 		}
 
-		//Execute http request
+		//Execute http request, waiting for a free connection if their number is bounded
+		couchInstance.acquireRequestSlot()
 		resp, errResp = couchInstance.client.Do(req)
+		couchInstance.releaseRequestSlot()
 
 		//if an error is not detected then drop out of the retry
 		if errResp == nil && resp != nil && resp.StatusCode < 500 {
@@ -1311,6 +1342,18 @@ func (couchInstance *CouchInstance) handleRequest(method, connectURL string, dat
 	return resp, couchDBReturn, nil
 }
 
+func (couchInstance *CouchInstance) acquireRequestSlot() {
+	if couchInstance.requestSlots != nil {
+		couchInstance.requestSlots <- struct{}{}
+	}
+}
+
+func (couchInstance *CouchInstance) releaseRequestSlot() {
+	if couchInstance.requestSlots != nil {
+		<-couchInstance.requestSlots
+	}
+}
+
 //IsJSON tests a string to determine if a valid JSON
 func IsJSON(s string) bool {
 	var js map[string]interface{}
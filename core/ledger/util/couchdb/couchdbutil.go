@@ -17,7 +17,12 @@ limitations under the License.
 package couchdb
 
 import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"regexp"
 	"strconv"
@@ -28,16 +33,31 @@ import (
 var validNamePattern = `^[a-z][a-z0-9_$(),+/-]+`
 var maxLength = 249
 
+//lengths the channel and namespace parts of a database name are truncated to
+//when the name has to be suffixed by a hash, see ConstructNamespaceDBName
+var chainNameAllowedLength = 50
+var namespaceNameAllowedLength = 100
+
 //CreateCouchInstance creates a CouchDB instance
 func CreateCouchInstance(couchDBConnectURL, id, pw string, maxRetries,
 	maxRetriesOnStartup int, connectionTimeout time.Duration) (*CouchInstance, error) {
 
-	couchConf, err := CreateConnectionDefinition(couchDBConnectURL,
-		id, pw, maxRetries, maxRetriesOnStartup, connectionTimeout)
+	return CreateCouchInstanceFromDefinition(&CouchDBDef{URL: couchDBConnectURL, Username: id, Password: pw,
+		MaxRetries: maxRetries, MaxRetriesOnStartup: maxRetriesOnStartup, RequestTimeout: connectionTimeout})
+}
+
+//CreateCouchInstanceFromDefinition creates a CouchDB instance, honoring the bound
+//on the number of connections and the TLS settings of the definition
+func CreateCouchInstanceFromDefinition(couchDBDef *CouchDBDef) (*CouchInstance, error) {
+
+	couchConf, err := CreateConnectionDefinition(couchDBDef.URL,
+		couchDBDef.Username, couchDBDef.Password, couchDBDef.MaxRetries,
+		couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout)
 	if err != nil {
 		logger.Errorf("Error during CouchDB CreateConnectionDefinition(): %s\n", err.Error())
 		return nil, err
 	}
+	couchConf.MaxConnections = couchDBDef.MaxConnections
 
 	// Create the http client once
 	// Clients and Transports are safe for concurrent use by multiple goroutines
@@ -46,10 +66,26 @@ func CreateCouchInstance(couchDBConnectURL, id, pw string, maxRetries,
 
 	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
 	transport.DisableCompression = false
+	if couchConf.MaxConnections > 0 {
+		// keep the connections open between requests instead of the default of 2
+		transport.MaxIdleConnsPerHost = couchConf.MaxConnections
+	}
+	if couchDBDef.TLS.Enabled {
+		tlsConfig, err := createTLSConfig(&couchDBDef.TLS)
+		if err != nil {
+			logger.Errorf("Error during CouchDB TLS configuration: %s\n", err.Error())
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+		couchConf.URL = strings.Replace(couchConf.URL, "http://", "https://", 1)
+	}
 	client.Transport = transport
 
 	//Create the CouchDB instance
 	couchInstance := &CouchInstance{conf: *couchConf, client: client}
+	if couchConf.MaxConnections > 0 {
+		couchInstance.requestSlots = make(chan struct{}, couchConf.MaxConnections)
+	}
 
 	connectInfo, retVal, verifyErr := couchInstance.VerifyCouchConfig()
 	if verifyErr != nil {
@@ -70,6 +106,36 @@ func CreateCouchInstance(couchDBConnectURL, id, pw string, maxRetries,
 	return couchInstance, nil
 }
 
+//createTLSConfig returns the TLS configuration to connect to CouchDB, trusting the
+//root certificates of the definition if any and authenticating with its client certificate if any
+func createTLSConfig(tlsDef *CouchDBTLSDef) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+
+	if tlsDef.RootCertFile != "" {
+		rootCert, err := ioutil.ReadFile(tlsDef.RootCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading CouchDB root certificate file: %s", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(rootCert) {
+			return nil, fmt.Errorf("No PEM certificate found in CouchDB root certificate file %s", tlsDef.RootCertFile)
+		}
+	}
+
+	if tlsDef.ClientCertFile != "" || tlsDef.ClientKeyFile != "" {
+		if tlsDef.ClientCertFile == "" || tlsDef.ClientKeyFile == "" {
+			return nil, fmt.Errorf("Both a client certificate and a client key are required for CouchDB certificate authentication")
+		}
+		clientCert, err := tls.LoadX509KeyPair(tlsDef.ClientCertFile, tlsDef.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error loading CouchDB client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return tlsConfig, nil
+}
+
 //checkCouchDBVersion verifies CouchDB is at least 2.0.0
 func checkCouchDBVersion(version string) error {
 
@@ -106,6 +172,48 @@ func CreateCouchDatabase(couchInstance CouchInstance, dbName string) (*CouchData
 	return &couchDBDatabase, nil
 }
 
+//ConstructNamespaceDBName returns the name of the database holding the state of
+//a namespace of a channel. Each chaincode, and each collection of a chaincode as
+//they have a namespace of their own, gets a database named chainName_namespace.
+//Upper-case letters are escaped with a '$', e.g. chaincode "myCC" gets "mychannel_my$c$c".
+//When the result would not be a legal CouchDB database name, because of its
+//characters or its length, the illegal characters are replaced, the channel and
+//namespace parts are truncated, and the name is made unique by a suffix with the
+//hash of the original names.
+func ConstructNamespaceDBName(chainName, namespace string) string {
+	rawName := chainName + "_" + namespace
+	dbName := escapeUpperCase(chainName) + "_" + escapeUpperCase(namespace)
+
+	validName := regexp.MustCompile(validNamePattern + "$")
+	if len(dbName) <= maxLength && validName.MatchString(dbName) {
+		return dbName
+	}
+
+	//Replace any characters not allowed in CouchDB with an "_"
+	replaceString := regexp.MustCompile(`[^a-z0-9_$(),+/-]`)
+	legalChainName := replaceString.ReplaceAllString(escapeUpperCase(chainName), "_")
+	legalNamespace := replaceString.ReplaceAllString(escapeUpperCase(namespace), "_")
+	if !regexp.MustCompile("^[a-z]").MatchString(legalChainName) {
+		legalChainName = "db_" + legalChainName
+	}
+	if len(legalChainName) > chainNameAllowedLength {
+		legalChainName = legalChainName[:chainNameAllowedLength]
+	}
+	if len(legalNamespace) > namespaceNameAllowedLength {
+		legalNamespace = legalNamespace[:namespaceNameAllowedLength]
+	}
+
+	hash := sha256.Sum256([]byte(rawName))
+	return legalChainName + "_" + legalNamespace + "(" + hex.EncodeToString(hash[:]) + ")"
+}
+
+//escapeUpperCase replaces each upper-case letter by a '$' followed by its lower-case
+func escapeUpperCase(name string) string {
+	return regexp.MustCompile("[A-Z]").ReplaceAllStringFunc(name, func(upper string) string {
+		return "$" + strings.ToLower(upper)
+	})
+}
+
 //CreateSystemDatabasesIfNotExist - creates the system databases if they do not exist
 func CreateSystemDatabasesIfNotExist(couchInstance CouchInstance) error {
 
@@ -18,6 +18,8 @@ package couchdb
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
@@ -116,3 +118,55 @@ func TestDatabaseMapping(t *testing.T) {
 	testutil.AssertError(t, err, fmt.Sprintf("Error should have been thrown for an invalid name"))
 
 }
+
+func TestCreateTLSConfig(t *testing.T) {
+	certsDir := "../../../comm/testdata/certs/"
+
+	tlsConfig, err := createTLSConfig(&CouchDBTLSDef{Enabled: true})
+	testutil.AssertNoError(t, err, "TLS without root or client certificates should use the system roots")
+	testutil.AssertNil(t, tlsConfig.RootCAs)
+
+	tlsConfig, err = createTLSConfig(&CouchDBTLSDef{Enabled: true, RootCertFile: certsDir + "Org1-cert.pem",
+		ClientCertFile: certsDir + "Org1-client1-cert.pem", ClientKeyFile: certsDir + "Org1-client1-key.pem"})
+	testutil.AssertNoError(t, err, "Error when trying to create the TLS configuration")
+	testutil.AssertNotNil(t, tlsConfig.RootCAs)
+	testutil.AssertEquals(t, len(tlsConfig.Certificates), 1)
+
+	_, err = createTLSConfig(&CouchDBTLSDef{Enabled: true, RootCertFile: certsDir + "Org1-client1-key.pem"})
+	testutil.AssertError(t, err, "Did not receive error for a root certificate file without certificate")
+
+	_, err = createTLSConfig(&CouchDBTLSDef{Enabled: true, ClientCertFile: certsDir + "Org1-client1-cert.pem"})
+	testutil.AssertError(t, err, "Did not receive error for a client certificate without key")
+}
+
+func TestConstructNamespaceDBName(t *testing.T) {
+	validName := regexp.MustCompile(validNamePattern + "$")
+
+	testutil.AssertEquals(t, ConstructNamespaceDBName("mychannel", "mycc"), "mychannel_mycc")
+	testutil.AssertEquals(t, ConstructNamespaceDBName("mychannel", "myCC"), "mychannel_my$c$c")
+	testutil.AssertEquals(t, ConstructNamespaceDBName("mychannel", "mycc$$mycollection"), "mychannel_mycc$$mycollection")
+
+	// names differing only by case or by illegal characters get different databases
+	testutil.AssertNotEquals(t, ConstructNamespaceDBName("mychannel", "mycc"), ConstructNamespaceDBName("mychannel", "myCc"))
+	dottedName := ConstructNamespaceDBName("my.channel", "mycc")
+	testutil.AssertEquals(t, validName.MatchString(dottedName), true)
+	testutil.AssertNotEquals(t, dottedName, ConstructNamespaceDBName("my_channel", "mycc"))
+	testutil.AssertEquals(t, dottedName, ConstructNamespaceDBName("my.channel", "mycc"))
+
+	// long names are truncated and made unique by a hash
+	longChainName := strings.Repeat("c", 100)
+	longNamespace := strings.Repeat("n", 300)
+	longName := ConstructNamespaceDBName(longChainName, longNamespace)
+	testutil.AssertEquals(t, validName.MatchString(longName), true)
+	if len(longName) > maxLength {
+		t.Fatalf("Database name %s is longer than %d", longName, maxLength)
+	}
+	testutil.AssertNotEquals(t, longName, ConstructNamespaceDBName(longChainName, longNamespace+"n"))
+
+	// the names produced are not altered by the validation of database names
+	for _, name := range []string{dottedName, longName, ConstructNamespaceDBName("mychannel", "myCC")} {
+		validatedName, err := mapAndValidateDatabaseName(name)
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, validatedName, name)
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// MVCCConflicts lists, for the transactions of a block invalidated by MVCC validation,
+// what they read that had changed by the time they were validated, so that clients
+// can retry them after re-reading only what conflicted
+type MVCCConflicts struct {
+	//TxConflicts of the invalidated transactions, in block order
+	TxConflicts []*TxMVCCConflicts `protobuf:"bytes,1,rep,name=tx_conflicts"`
+}
+
+// TxMVCCConflicts is what a transaction read that had changed by the time it was validated
+type TxMVCCConflicts struct {
+	//TxNum is the index of the transaction in the block
+	TxNum uint64 `protobuf:"varint,1,opt,name=tx_num"`
+
+	//TxId of the transaction
+	TxId string `protobuf:"bytes,2,opt,name=tx_id"`
+
+	//Keys whose version differs from the one read
+	Keys []*KeyMVCCConflict `protobuf:"bytes,3,rep,name=keys"`
+
+	//Ranges whose results differ from the ones read
+	Ranges []*RangeMVCCConflict `protobuf:"bytes,4,rep,name=ranges"`
+}
+
+// KeyMVCCConflict is a key whose version differs from the one read by the transaction
+type KeyMVCCConflict struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace"`
+	Key       string `protobuf:"bytes,2,opt,name=key"`
+
+	//BlockNum and TxNum locate the transaction that wrote the version of the key the
+	//transaction was validated against, either committed or preceding it in the block
+	BlockNum uint64 `protobuf:"varint,3,opt,name=block_num"`
+	TxNum    uint64 `protobuf:"varint,4,opt,name=tx_num"`
+
+	//Deleted is set when the key does not exist anymore, BlockNum and TxNum being unknown
+	Deleted bool `protobuf:"varint,5,opt,name=deleted"`
+}
+
+// RangeMVCCConflict is a range query whose results changed since the transaction was simulated
+type RangeMVCCConflict struct {
+	Namespace string `protobuf:"bytes,1,opt,name=namespace"`
+	StartKey  string `protobuf:"bytes,2,opt,name=start_key"`
+	EndKey    string `protobuf:"bytes,3,opt,name=end_key"`
+}
+
+//implement functions needed from proto.Message for proto's mar/unmarshal functions
+
+//Reset resets
+func (c *MVCCConflicts) Reset() { *c = MVCCConflicts{} }
+
+//String convers to string
+func (c *MVCCConflicts) String() string { return proto.CompactTextString(c) }
+
+//ProtoMessage just exists to make proto happy
+func (*MVCCConflicts) ProtoMessage() {}
+
+//Reset resets
+func (c *TxMVCCConflicts) Reset() { *c = TxMVCCConflicts{} }
+
+//String convers to string
+func (c *TxMVCCConflicts) String() string { return proto.CompactTextString(c) }
+
+//ProtoMessage just exists to make proto happy
+func (*TxMVCCConflicts) ProtoMessage() {}
+
+//Reset resets
+func (c *KeyMVCCConflict) Reset() { *c = KeyMVCCConflict{} }
+
+//String convers to string
+func (c *KeyMVCCConflict) String() string { return proto.CompactTextString(c) }
+
+//ProtoMessage just exists to make proto happy
+func (*KeyMVCCConflict) ProtoMessage() {}
+
+//Reset resets
+func (c *RangeMVCCConflict) Reset() { *c = RangeMVCCConflict{} }
+
+//String convers to string
+func (c *RangeMVCCConflict) String() string { return proto.CompactTextString(c) }
+
+//ProtoMessage just exists to make proto happy
+func (*RangeMVCCConflict) ProtoMessage() {}
+
+// SetMVCCConflicts records the MVCC conflicts of the transactions of the block in its
+// metadata, overwriting the ones it may carry, or clears them if there are none
+func SetMVCCConflicts(block *common.Block, conflicts *MVCCConflicts) error {
+	if conflicts == nil || len(conflicts.TxConflicts) == 0 {
+		if len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_MVCC_CONFLICTS) {
+			block.Metadata.Metadata[common.BlockMetadataIndex_MVCC_CONFLICTS] = []byte{}
+		}
+		return nil
+	}
+	b, err := proto.Marshal(conflicts)
+	if err != nil {
+		return err
+	}
+	for len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_MVCC_CONFLICTS) {
+		block.Metadata.Metadata = append(block.Metadata.Metadata, []byte{})
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_MVCC_CONFLICTS] = b
+	return nil
+}
+
+// GetMVCCConflicts returns the MVCC conflicts of the transactions of the block
+// recorded in its metadata, or nil if none of its transactions had conflicts
+func GetMVCCConflicts(block *common.Block) (*MVCCConflicts, error) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_MVCC_CONFLICTS) ||
+		len(block.Metadata.Metadata[common.BlockMetadataIndex_MVCC_CONFLICTS]) == 0 {
+		return nil, nil
+	}
+	conflicts := &MVCCConflicts{}
+	if err := proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_MVCC_CONFLICTS], conflicts); err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
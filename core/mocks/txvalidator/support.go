@@ -25,9 +25,10 @@ import (
 )
 
 type Support struct {
-	LedgerVal     ledger.PeerLedger
-	MSPManagerVal msp.MSPManager
-	ApplyVal      error
+	LedgerVal       ledger.PeerLedger
+	MSPManagerVal   msp.MSPManager
+	ApplyVal        error
+	CapabilitiesVal map[string]bool
 }
 
 // Ledger returns LedgerVal
@@ -52,3 +53,8 @@ func (ms *Support) PolicyManager() policies.Manager {
 func (cs *Support) GetMSPIDs(cid string) []string {
 	return []string{"DEFAULT"}
 }
+
+// HasCapability returns whether the capability is in CapabilitiesVal
+func (ms *Support) HasCapability(name string) bool {
+	return ms.CapabilitiesVal[name]
+}
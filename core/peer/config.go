@@ -33,8 +33,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"path/filepath"
 
 	"github.com/spf13/viper"
+	"google.golang.org/grpc"
 
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/config"
@@ -170,6 +172,29 @@ func SecurityEnabled() bool {
 	return securityEnabled
 }
 
+// GetServerInterceptors returns the interceptors enabled in the
+// peer.interceptors section of the configuration, in the order they run:
+// request logging, per-identity rate limiting, then payload size enforcement
+func GetServerInterceptors() ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor) {
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+	if viper.GetBool("peer.interceptors.logging.enabled") {
+		unary = append(unary, comm.LoggingUnaryServerInterceptor)
+		stream = append(stream, comm.LoggingStreamServerInterceptor)
+	}
+	if viper.GetBool("peer.interceptors.rateLimit.enabled") {
+		rl := comm.NewRateLimiter(viper.GetFloat64("peer.interceptors.rateLimit.rate"), viper.GetInt("peer.interceptors.rateLimit.burst"))
+		unary = append(unary, rl.UnaryServerInterceptor())
+		stream = append(stream, rl.StreamServerInterceptor())
+	}
+	if viper.GetBool("peer.interceptors.payloadSize.enabled") {
+		maxBytes := viper.GetInt("peer.interceptors.payloadSize.maxBytes")
+		unary = append(unary, comm.PayloadSizeUnaryServerInterceptor(maxBytes))
+		stream = append(stream, comm.PayloadSizeStreamServerInterceptor(maxBytes))
+	}
+	return unary, stream
+}
+
 // GetSecureConfig returns the secure server configuration for the peer
 func GetSecureConfig() (comm.SecureServerConfig, error) {
 	secureConfig := comm.SecureServerConfig{
@@ -193,6 +218,17 @@ func GetSecureConfig() (comm.SecureServerConfig, error) {
 			}
 			secureConfig.ServerRootCAs = [][]byte{rootCert}
 		}
+		// check for client authentication
+		secureConfig.RequireClientCert = viper.GetBool("peer.tls.clientAuthRequired")
+		if secureConfig.RequireClientCert {
+			for _, file := range viper.GetStringSlice("peer.tls.clientRootCAs.files") {
+				clientRoot, err := ioutil.ReadFile(config.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), file))
+				if err != nil {
+					return secureConfig, fmt.Errorf("Error loading TLS client root certificate (%s)", err)
+				}
+				secureConfig.ClientRootCAs = append(secureConfig.ClientRootCAs, clientRoot)
+			}
+		}
 		return secureConfig, nil
 	}
 	return secureConfig, nil
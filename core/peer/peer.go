@@ -36,6 +36,7 @@ import (
 	"github.com/hyperledger/fabric/core/committer"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/service"
@@ -70,6 +71,41 @@ func (cs *chainSupport) GetMSPIDs(cid string) []string {
 	return GetMSPIDs(cid)
 }
 
+// HasCapability returns true if the current application config of the
+// channel requires the capability of its peers
+func (cs *chainSupport) HasCapability(name string) bool {
+	ac := cs.ApplicationConfig()
+	return ac != nil && ac.HasCapability(name)
+}
+
+// OrdererAddresses returns the orderer addresses of the channel config
+// this peer should connect to
+func (cs *chainSupport) OrdererAddresses() []string {
+	return ordererAddresses(cs.Manager)
+}
+
+// PreferredOrdererAddresses returns the orderer addresses the organization of
+// this peer declares in the channel config, if any
+func (cs *chainSupport) PreferredOrdererAddresses() []string {
+	mspID, err := mspmgmt.GetLocalMSP().GetIdentifier()
+	if err != nil {
+		peerLogger.Warningf("Could not obtain the MSP ID of this peer, no orderer addresses are preferred: %s", err)
+		return nil
+	}
+	return config.OrdererEndpointsOfOrg(mspID, cs.OrdererConfig())
+}
+
+// ordererAddresses resolves the orderer addresses of the channel, favoring
+// the orderer endpoints declared by the organization of this peer
+func ordererAddresses(cm configtxapi.Manager) []string {
+	mspID, err := mspmgmt.GetLocalMSP().GetIdentifier()
+	if err != nil {
+		peerLogger.Warningf("Could not obtain the MSP ID of this peer, using the orderer addresses of the channel: %s", err)
+		return cm.ChannelConfig().OrdererAddresses()
+	}
+	return config.OrdererAddressesOfOrg(mspID, cm.ChannelConfig(), cm.OrdererConfig())
+}
+
 // chain is a local struct to manage objects in a chain
 type chain struct {
 	cs        *chainSupport
@@ -223,13 +259,21 @@ func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block) error {
 		Application: configtxManager.ApplicationConfig(), // TODO, refactor as this is accessible through Manager
 		ledger:      ledger,
 	}
-
-	c := committer.NewLedgerCommitter(ledger, txvalidator.NewTxValidator(cs))
-	ordererAddresses := configtxManager.ChannelConfig().OrdererAddresses()
-	if len(ordererAddresses) == 0 {
+	// the ledger validates the blocks according to the capabilities of the
+	// current config of the channel, as the committer does
+	ledger.SetCapabilityChecker(cs.HasCapability)
+
+	var c *committer.LedgerCommitter
+	if ledgerconfig.IsCommitPipelineEnabled() {
+		c = committer.NewPipelinedLedgerCommitter(ledger, txvalidator.NewTxValidator(cs))
+	} else {
+		c = committer.NewLedgerCommitter(ledger, txvalidator.NewTxValidator(cs))
+	}
+	endpoints := ordererAddresses(configtxManager)
+	if len(endpoints) == 0 {
 		return errors.New("No orderering service endpoint provided in configuration block")
 	}
-	service.GetGossipService().InitializeChannel(cs.ChainID(), c, ordererAddresses)
+	service.GetGossipService().InitializeChannel(cs.ChainID(), c, endpoints)
 
 	chains.Lock()
 	defer chains.Unlock()
@@ -318,6 +362,17 @@ func GetPolicyManager(cid string) policies.Manager {
 	return nil
 }
 
+// GetApplicationConfig returns the application config of the chain with chain ID.
+// Note that this call returns false if chain cid has not been created.
+func GetApplicationConfig(cid string) (config.Application, bool) {
+	chains.RLock()
+	defer chains.RUnlock()
+	if c, ok := chains.list[cid]; ok && c.cs.Application != nil {
+		return c.cs.Application, true
+	}
+	return nil, false
+}
+
 // GetCurrConfigBlock returns the cached config block of the specified chain.
 // Note that this call returns nil if chain cid has not been created.
 func GetCurrConfigBlock(cid string) *common.Block {
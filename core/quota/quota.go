@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"expvar"
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/spf13/viper"
+)
+
+var logger = flogging.MustGetLogger("quota")
+
+// rejections counts the requests denied because their channel exceeded a
+// quota, keyed by the resource and the channel, published on /debug/vars
+// of the profiling server
+var rejections = expvar.NewMap("peer_quota_rejections")
+
+// ExceededError is returned when a channel already uses all of its quota of a resource
+type ExceededError struct {
+	Resource string
+	ChainID  string
+	Limit    int
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("channel %s reached its quota of %d concurrent %s", e.ChainID, e.Limit, e.Resource)
+}
+
+// Limiter bounds the number of concurrent uses of a resource by each
+// channel, so that a channel using the resource heavily does not starve the
+// other channels of the peer
+type Limiter struct {
+	resource string
+	limit    int
+
+	lock  sync.Mutex
+	inUse map[string]int
+}
+
+// NewLimiter creates a Limiter allowing each channel limit concurrent uses
+// of the resource, a limit of 0 or less does not bound the uses
+func NewLimiter(resource string, limit int) *Limiter {
+	return &Limiter{resource: resource, limit: limit, inUse: make(map[string]int)}
+}
+
+// Acquire takes one use of the resource by the channel, or returns an
+// ExceededError without waiting when the channel has no quota left. Each
+// successful Acquire must be followed by a Release.
+func (l *Limiter) Acquire(chainID string) error {
+	if l == nil || l.limit <= 0 {
+		return nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.inUse[chainID] >= l.limit {
+		rejections.Add(l.resource+"/"+chainID, 1)
+		logger.Warningf("Channel %s reached its quota of %d concurrent %s", chainID, l.limit, l.resource)
+		return &ExceededError{Resource: l.resource, ChainID: chainID, Limit: l.limit}
+	}
+	l.inUse[chainID]++
+	return nil
+}
+
+// Release returns one use of the resource to the quota of the channel
+func (l *Limiter) Release(chainID string) {
+	if l == nil || l.limit <= 0 {
+		return
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.inUse[chainID] <= 1 {
+		delete(l.inUse, chainID)
+		return
+	}
+	l.inUse[chainID]--
+}
+
+// InUse returns the number of uses of the resource the channel holds
+func (l *Limiter) InUse(chainID string) int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.inUse[chainID]
+}
+
+// EndorsementLimit returns the number of proposals of a channel the peer
+// endorses concurrently, as configured in peer.quotas.endorsements
+func EndorsementLimit() int {
+	return viper.GetInt("peer.quotas.endorsements")
+}
+
+// EventSubscriberLimit returns the number of event streams which may
+// register interests for a channel, as configured in peer.quotas.eventSubscribers
+func EventSubscriberLimit() int {
+	return viper.GetInt("peer.quotas.eventSubscribers")
+}
@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter("endorsements", 2)
+
+	assert.NoError(t, l.Acquire("a"))
+	assert.NoError(t, l.Acquire("a"))
+	err := l.Acquire("a")
+	if assert.IsType(t, &ExceededError{}, err) {
+		assert.Equal(t, "channel a reached its quota of 2 concurrent endorsements", err.Error())
+	}
+	assert.NoError(t, l.Acquire("b"), "Should not have limited another channel")
+	assert.Equal(t, "1", rejections.Get("endorsements/a").String())
+
+	l.Release("a")
+	assert.NoError(t, l.Acquire("a"), "Should have acquired the released use")
+	l.Release("a")
+	l.Release("a")
+	l.Release("a")
+	assert.Equal(t, 0, l.InUse("a"))
+	assert.Equal(t, 1, l.InUse("b"))
+}
+
+func TestLimiterUnlimited(t *testing.T) {
+	for _, l := range []*Limiter{NewLimiter("endorsements", 0), nil} {
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, l.Acquire("a"))
+		}
+		l.Release("a")
+	}
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/hyperledger/fabric/msp/mgmt"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
 )
 
 // PeerConfiger implements the configuration handler for the peer. For every
@@ -71,6 +72,14 @@ func (e *PeerConfiger) Init(stub shim.ChaincodeStubInterface) pb.Response {
 	return shim.Success(nil)
 }
 
+// joinChainPolicy returns the local MSP policy a JoinChain request must satisfy
+func joinChainPolicy() string {
+	if policy := viper.GetString("peer.adminService.accessControl.policies.JoinChain"); policy != "" {
+		return policy
+	}
+	return mgmt.Admins
+}
+
 // Invoke is called for the following:
 // # to process joining a chain (called by app as a transaction proposal)
 // # to get the current configuration block (called by app)
@@ -105,8 +114,8 @@ func (e *PeerConfiger) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 
 	switch fname {
 	case JoinChain:
-		// 2. check local MSP Admins policy
-		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+		// 2. check the configured local MSP policy, Admins by default
+		if err = e.policyChecker.CheckPolicyNoChannel(joinChainPolicy(), sp); err != nil {
 			cid, e := utils.GetChainIDFromBlockBytes(args[1])
 			errorString := fmt.Sprintf("\"JoinChain\" request failed authorization check "+
 				"for channel [%s]: [%s]", cid, err)
@@ -60,6 +60,16 @@ func (ds *mockDeliveryClient) StopDeliverForChannel(chainID string) error {
 	return nil
 }
 
+// UpdateEndpoints updates the ordering service endpoints of the channel
+func (ds *mockDeliveryClient) UpdateEndpoints(chainID string, endpoints []string) error {
+	return nil
+}
+
+// UpdatePreferredEndpoints updates the preferred ordering service endpoints of the channel
+func (ds *mockDeliveryClient) UpdatePreferredEndpoints(chainID string, endpoints []string) error {
+	return nil
+}
+
 // Stop terminates delivery service and closes the connection
 func (*mockDeliveryClient) Stop() {
 
@@ -19,6 +19,8 @@ package escc
 import (
 	"fmt"
 
+	"github.com/golang/protobuf/proto"
+
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -48,7 +50,7 @@ func (e *EndorserOneValidSignature) Init(stub shim.ChaincodeStubInterface) pb.Re
 // policy specification to be coded as a transaction of the chaincode and Client
 // could select which policy to use for endorsement using parameter
 // @return a marshalled proposal response
-// Note that Peer calls this function with 4 mandatory arguments (and 2 optional ones):
+// Note that Peer calls this function with 4 mandatory arguments (and 3 optional ones):
 // args[0] - function name (not used now)
 // args[1] - serialized Header object
 // args[2] - serialized ChaincodeProposalPayload object
@@ -66,8 +68,8 @@ func (e *EndorserOneValidSignature) Invoke(stub shim.ChaincodeStubInterface) pb.
 	args := stub.GetArgs()
 	if len(args) < 5 {
 		return shim.Error(fmt.Sprintf("Incorrect number of arguments (expected a minimum of 5, provided %d)", len(args)))
-	} else if len(args) > 7 {
-		return shim.Error(fmt.Sprintf("Incorrect number of arguments (expected a maximum of 7, provided %d)", len(args)))
+	} else if len(args) > 8 {
+		return shim.Error(fmt.Sprintf("Incorrect number of arguments (expected a maximum of 8, provided %d)", len(args)))
 	}
 
 	logger.Debugf("ESCC starts: %d args", len(args))
@@ -132,6 +134,17 @@ func (e *EndorserOneValidSignature) Invoke(stub shim.ChaincodeStubInterface) pb.
 		visibility = args[6]
 	}
 
+	// Handle the chaincode ID (it's an optional argument), recorded in the
+	// chaincode action so that the committer can check that the transaction
+	// was endorsed against the committed version of the chaincode
+	var ccid *pb.ChaincodeID
+	if len(args) > 7 && len(args[7]) > 0 {
+		ccid = &pb.ChaincodeID{}
+		if err = proto.Unmarshal(args[7], ccid); err != nil {
+			return shim.Error(fmt.Sprintf("Failed to unmarshal the chaincode ID: %s", err))
+		}
+	}
+
 	// obtain the default signing identity for this peer; it will be used to sign this proposal response
 	localMsp := mspmgmt.GetLocalMSP()
 	if localMsp == nil {
@@ -144,7 +157,7 @@ func (e *EndorserOneValidSignature) Invoke(stub shim.ChaincodeStubInterface) pb.
 	}
 
 	// obtain a proposal response
-	presp, err := utils.CreateProposalResponse(hdr, payl, response, results, events, visibility, signingEndorser)
+	presp, err := utils.CreateProposalResponse(hdr, payl, response, results, events, ccid, visibility, signingEndorser)
 	if err != nil {
 		return shim.Error(err.Error())
 	}
@@ -22,6 +22,8 @@ import (
 	"os"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/validation"
@@ -197,6 +199,36 @@ func TestInvoke(t *testing.T) {
 		t.Fatalf("%s", err)
 		return
 	}
+
+	// success test 4: invocation with mandatory args + events, visibility and chaincode ID
+	ccid := &pb.ChaincodeID{Name: "foo", Version: "1.0"}
+	args = [][]byte{[]byte(""), proposal.Header, proposal.Payload, successRes, simRes, events, nil, putils.MarshalOrPanic(ccid)}
+	res = stub.MockInvoke("1", args)
+	if res.Status != shim.OK {
+		t.Fail()
+		t.Fatalf("escc invoke failed with: %s", res.Message)
+		return
+	}
+
+	err = validateProposalResponse(res.Payload, proposal, []byte{}, successResponse, simRes, events)
+	if err != nil {
+		t.Fail()
+		t.Fatalf("%s", err)
+		return
+	}
+	pResp, _ := putils.GetProposalResponse(res.Payload)
+	prp, _ := putils.GetProposalResponsePayload(pResp.Payload)
+	cact, _ := putils.GetChaincodeAction(prp.Extension)
+	if !proto.Equal(cact.ChaincodeId, ccid) {
+		t.Fatalf("expected chaincode ID %v in the chaincode action, got %v", ccid, cact.ChaincodeId)
+	}
+
+	// failure test: invalid chaincode ID
+	args = [][]byte{[]byte(""), proposal.Header, proposal.Payload, successRes, simRes, events, nil, []byte("garbage")}
+	res = stub.MockInvoke("1", args)
+	if res.Status == shim.OK {
+		t.Fatalf("escc invoke should have failed with an invalid chaincode ID")
+	}
 }
 
 func validateProposalResponse(prBytes []byte, proposal *pb.Proposal, visibility []byte, response *pb.Response, simRes []byte, events []byte) error {
@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package escc
+
+import (
+	"fmt"
+
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+// StateFetcher gives endorsement system chaincodes read-only access to the
+// committed state of the channel, e.g. to consult per-key ownership records
+// before deciding whether to sign. It reads through the tx simulator the
+// proposal was simulated with, so the state is the one the simulation saw.
+// The state changes of the proposal being endorsed are not visible through
+// it: they are the simulation results received by the ESCC.
+type StateFetcher interface {
+	// GetState returns the committed value of the key in the namespace of a chaincode
+	GetState(namespace string, key string) ([]byte, error)
+
+	// GetStateMultipleKeys returns the committed values of the keys in the namespace of a chaincode
+	GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error)
+
+	// GetStateRangeScanIterator returns an iterator over the committed key-values
+	// of the namespace of a chaincode between startKey (included) and endKey (excluded)
+	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.ResultsIterator, error)
+
+	// Done releases the resources held by the StateFetcher
+	Done()
+}
+
+// NewStateFetcher returns a StateFetcher over the committed state of the
+// channel of the proposal being endorsed, given its serialized Header as
+// received by ESCCs in args[1]. It is only usable while the ESCC is invoked
+// for the proposal. Done must be called once the StateFetcher is no longer
+// needed.
+func NewStateFetcher(hdr []byte) (StateFetcher, error) {
+	header, err := putils.GetHeader(hdr)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal Header: %s", err)
+	}
+
+	chdr, err := putils.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal ChannelHeader: %s", err)
+	}
+
+	if chdr.ChannelId == "" {
+		return nil, fmt.Errorf("No state to fetch for a proposal without channel")
+	}
+
+	sf, err := sysccprovider.NewTxStateFetcher(chdr.ChannelId, chdr.TxId)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get the state of channel %s: %s", chdr.ChannelId, err)
+	}
+
+	return sf, nil
+}
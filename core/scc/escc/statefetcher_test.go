@@ -0,0 +1,109 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package escc
+
+import (
+	"errors"
+	"testing"
+
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/protos/common"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"golang.org/x/net/context"
+)
+
+type mockQueryExecutor struct {
+	state map[string]map[string][]byte
+	done  bool
+}
+
+func (qe *mockQueryExecutor) GetState(namespace string, key string) ([]byte, error) {
+	return qe.state[namespace][key], nil
+}
+
+func (qe *mockQueryExecutor) GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error) {
+	var values [][]byte
+	for _, key := range keys {
+		values = append(values, qe.state[namespace][key])
+	}
+	return values, nil
+}
+
+func (qe *mockQueryExecutor) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.ResultsIterator, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (qe *mockQueryExecutor) ExecuteQuery(namespace, query string) (commonledger.ResultsIterator, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (qe *mockQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (qe *mockQueryExecutor) Done() {
+	qe.done = true
+}
+
+func headerFor(chainID string) []byte {
+	chdr := &common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION), ChannelId: chainID, TxId: "txid"}
+	return putils.MarshalOrPanic(&common.Header{ChannelHeader: putils.MarshalOrPanic(chdr)})
+}
+
+func TestStateFetcher(t *testing.T) {
+	qe := &mockQueryExecutor{state: map[string]map[string][]byte{"mycc": {"key": []byte("owner")}}}
+	release := sysccprovider.RegisterTxQueryExecutor(context.Background(), "mychannel", "txid", qe)
+
+	sf, err := NewStateFetcher(headerFor("mychannel"))
+	if err != nil {
+		t.Fatalf("NewStateFetcher failed: %s", err)
+	}
+
+	v, err := sf.GetState("mycc", "key")
+	if err != nil || string(v) != "owner" {
+		t.Fatalf("Unexpected state %s (err %v)", v, err)
+	}
+
+	vs, err := sf.GetStateMultipleKeys("mycc", []string{"key", "missing"})
+	if err != nil || len(vs) != 2 || string(vs[0]) != "owner" || vs[1] != nil {
+		t.Fatalf("Unexpected states %v (err %v)", vs, err)
+	}
+
+	sf.Done()
+	if qe.done {
+		t.Fatalf("Done should not have released the tx simulator of the endorser")
+	}
+
+	// The tx simulator is no longer available once the ESCC invocation is over
+	release()
+	if _, err = sf.GetState("mycc", "key"); err == nil {
+		t.Fatalf("GetState should have failed once the invocation is over")
+	}
+
+	if _, err = NewStateFetcher(headerFor("otherchannel")); err == nil {
+		t.Fatalf("NewStateFetcher should have failed for an unknown channel")
+	}
+
+	if _, err = NewStateFetcher(headerFor("")); err == nil {
+		t.Fatalf("NewStateFetcher should have failed for a proposal without channel")
+	}
+
+	if _, err = NewStateFetcher([]byte("garbage")); err == nil {
+		t.Fatalf("NewStateFetcher should have failed for an invalid header")
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package scc
 
 import (
+	"sync"
+
 	//import system chain codes here
 	"github.com/hyperledger/fabric/core/scc/cscc"
 	"github.com/hyperledger/fabric/core/scc/escc"
@@ -69,9 +71,17 @@ var systemChaincodes = []*SystemChaincode{
 	},
 }
 
+// loadPluginsOnce guards the loading of the system chaincode plugins, which
+// are added to systemChaincodes the first time RegisterSysCCs is called
+var loadPluginsOnce sync.Once
+
 //RegisterSysCCs is the hook for system chaincodes where system chaincodes are registered with the fabric
 //note the chaincode must still be deployed and launched like a user chaincode will be
 func RegisterSysCCs() {
+	loadPluginsOnce.Do(func() {
+		systemChaincodes = append(systemChaincodes, loadSysCCs()...)
+	})
+
 	for _, sysCC := range systemChaincodes {
 		RegisterSysCC(sysCC)
 	}
@@ -107,11 +117,12 @@ func IsSysCC(name string) bool {
 
 // IsSysCCAndNotInvokable returns true if the chaincode
 // is a system chaincode and *CANNOT* be invoked through
-// a proposal to this peer
+// a proposal to this peer, either because it is not
+// invokable externally or because it is disabled
 func IsSysCCAndNotInvokable(name string) bool {
 	for _, sysCC := range systemChaincodes {
 		if sysCC.Name == name {
-			return !sysCC.InvokableExternal
+			return !sysCC.InvokableExternal || !sysCC.Enabled || !isWhitelisted(sysCC)
 		}
 	}
 	return false
@@ -119,11 +130,12 @@ func IsSysCCAndNotInvokable(name string) bool {
 
 // IsSysCCAndNotInvokableCC2CC returns true if the chaincode
 // is a system chaincode and *CANNOT* be invoked through
-// a cc2cc invocation
+// a cc2cc invocation, either because it is not invokable
+// by other chaincodes or because it is disabled
 func IsSysCCAndNotInvokableCC2CC(name string) bool {
 	for _, sysCC := range systemChaincodes {
 		if sysCC.Name == name {
-			return !sysCC.InvokableCC2CC
+			return !sysCC.InvokableCC2CC || !sysCC.Enabled || !isWhitelisted(sysCC)
 		}
 	}
 	return false
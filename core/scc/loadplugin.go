@@ -0,0 +1,57 @@
+// +build pluginsenabled,cgo
+// +build darwin linux
+
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scc
+
+import (
+	"fmt"
+	"plugin"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+)
+
+// sysCCPluginFactory is the symbol that system chaincode plugins must export
+const sysCCPluginFactory = "New"
+
+// loadPlugin opens the Go plugin at path and returns the chaincode built by
+// its exported New function, which must have the signature
+// func New() shim.Chaincode
+func loadPlugin(path string) (shim.Chaincode, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := p.Lookup(sysCCPluginFactory)
+	if err != nil {
+		return nil, err
+	}
+
+	factory, ok := sym.(func() shim.Chaincode)
+	if !ok {
+		return nil, fmt.Errorf("symbol %s of plugin %s is not a func() shim.Chaincode", sysCCPluginFactory, path)
+	}
+
+	chaincode := factory()
+	if chaincode == nil {
+		return nil, fmt.Errorf("plugin %s returned a nil chaincode", path)
+	}
+
+	return chaincode, nil
+}
@@ -0,0 +1,32 @@
+// +build !pluginsenabled !cgo !darwin,!linux
+
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scc
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+)
+
+// loadPlugin fails as the peer was built without plugin support. Loading
+// Go plugins requires go 1.8 or later, cgo, linux or darwin and the
+// pluginsenabled build tag
+func loadPlugin(path string) (shim.Chaincode, error) {
+	return nil, fmt.Errorf("cannot load %s: the peer was built without plugin support (pluginsenabled build tag)", path)
+}
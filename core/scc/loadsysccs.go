@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scc
+
+import (
+	"github.com/spf13/viper"
+)
+
+// PluginConfig is the configuration of a system chaincode loaded as a plugin,
+// as found in the chaincode.systemPlugins section of core.yaml
+type PluginConfig struct {
+	Enabled           bool   `mapstructure:"enabled" yaml:"enabled"`
+	Name              string `mapstructure:"name" yaml:"name"`
+	Path              string `mapstructure:"path" yaml:"path"`
+	InvokableExternal bool   `mapstructure:"invokableExternal" yaml:"invokableExternal"`
+	InvokableCC2CC    bool   `mapstructure:"invokableCC2CC" yaml:"invokableCC2CC"`
+}
+
+// loadSysCCs reads the system chaincode plugins from the configuration and
+// loads them. Plugins that fail to load, or whose name clashes with another
+// system chaincode, are skipped
+func loadSysCCs() []*SystemChaincode {
+	var pluginConfigs []*PluginConfig
+	if err := viper.UnmarshalKey("chaincode.systemPlugins", &pluginConfigs); err != nil {
+		sysccLogger.Errorf("could not read the system chaincode plugins configuration: %s", err)
+		return nil
+	}
+
+	var sysccs []*SystemChaincode
+	for _, conf := range pluginConfigs {
+		if conf == nil || conf.Name == "" || conf.Path == "" {
+			sysccLogger.Errorf("system chaincode plugin %v must have a name and a path", conf)
+			continue
+		}
+
+		if IsSysCC(conf.Name) || containsSysCC(sysccs, conf.Name) {
+			sysccLogger.Errorf("system chaincode plugin %s clashes with an existing system chaincode", conf.Name)
+			continue
+		}
+
+		if !conf.Enabled {
+			sysccLogger.Infof("system chaincode plugin %s(%s) disabled", conf.Name, conf.Path)
+			continue
+		}
+
+		chaincode, err := loadPlugin(conf.Path)
+		if err != nil {
+			sysccLogger.Errorf("could not load system chaincode plugin %s(%s): %s", conf.Name, conf.Path, err)
+			continue
+		}
+
+		sysccs = append(sysccs, &SystemChaincode{
+			Enabled:           conf.Enabled,
+			Name:              conf.Name,
+			Path:              conf.Path,
+			InitArgs:          [][]byte{[]byte("")},
+			Chaincode:         chaincode,
+			InvokableExternal: conf.InvokableExternal,
+			InvokableCC2CC:    conf.InvokableCC2CC,
+		})
+	}
+
+	return sysccs
+}
+
+func containsSysCC(sysccs []*SystemChaincode, name string) bool {
+	for _, sysCC := range sysccs {
+		if sysCC.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+const pluginsConfig = `
+chaincode:
+  system:
+    lscc: enable
+    escc: disable
+  systemPlugins:
+    - enabled: true
+      name: lscc
+      path: /nonexistent/lscc.so
+    - enabled: false
+      name: disabledscc
+      path: /nonexistent/disabledscc.so
+    - enabled: true
+      name: refdatascc
+      path: /nonexistent/refdatascc.so
+      invokableExternal: true
+`
+
+func TestLoadSysCCs(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigType("yaml")
+	if err := viper.ReadConfig(bytes.NewBufferString(pluginsConfig)); err != nil {
+		t.Fatalf("cannot read config: %s", err)
+	}
+
+	var pluginConfigs []*PluginConfig
+	if err := viper.UnmarshalKey("chaincode.systemPlugins", &pluginConfigs); err != nil {
+		t.Fatalf("cannot unmarshal plugins config: %s", err)
+	}
+	if len(pluginConfigs) != 3 {
+		t.Fatalf("expected 3 plugin configs, got %d", len(pluginConfigs))
+	}
+	refdata := pluginConfigs[2]
+	if !refdata.Enabled || refdata.Name != "refdatascc" || refdata.Path != "/nonexistent/refdatascc.so" || !refdata.InvokableExternal || refdata.InvokableCC2CC {
+		t.Fatalf("unexpected plugin config %v", refdata)
+	}
+
+	// the lscc plugin clashes with the built-in lscc, the disabled
+	// plugin is skipped and the last one does not exist
+	if sysccs := loadSysCCs(); len(sysccs) != 0 {
+		t.Fatalf("no plugin should have been loaded, got %d", len(sysccs))
+	}
+}
+
+func TestDisabledSysCCNotInvokable(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("chaincode.system", map[string]string{"lscc": "enable", "qscc": "disable"})
+
+	if IsSysCCAndNotInvokable("lscc") || IsSysCCAndNotInvokableCC2CC("lscc") {
+		t.Fatalf("lscc is enabled and should be invokable")
+	}
+	if !IsSysCCAndNotInvokable("qscc") || !IsSysCCAndNotInvokableCC2CC("qscc") {
+		t.Fatalf("qscc is disabled and should not be invokable")
+	}
+	if !IsSysCCAndNotInvokable("cscc") {
+		t.Fatalf("cscc is not whitelisted and should not be invokable")
+	}
+	if !IsSysCCAndNotInvokable("escc") {
+		t.Fatalf("escc is not invokable externally")
+	}
+	if IsSysCCAndNotInvokable("mycc") {
+		t.Fatalf("mycc is not a system chaincode")
+	}
+}
@@ -19,12 +19,14 @@ package lscc
 import (
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccpackage"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/peer"
@@ -70,16 +72,41 @@ const (
 	//GETCCDATA get ChaincodeData
 	GETCCDATA = "getccdata"
 
+	//GETCCDEFINITION get the ChaincodeDefinition, with the endorsement policy
+	GETCCDEFINITION = "getchaincodedefinition"
+
 	//GETCHAINCODES gets the instantiated chaincodes on a channel
 	GETCHAINCODES = "getchaincodes"
 
 	//GETINSTALLEDCHAINCODES gets the installed chaincodes on a peer
 	GETINSTALLEDCHAINCODES = "getinstalledchaincodes"
 
-	allowedCharsChaincodeName = "[A-Za-z0-9_-]+"
+	//GETINSTALLEDPACKAGE gets the package of a chaincode installed on a peer
+	GETINSTALLEDPACKAGE = "getinstalledpackage"
+
+	//GETINSTALLEDMETADATA gets the metadata of a chaincode installed on a peer
+	GETINSTALLEDMETADATA = "getinstalledmetadata"
+
+	allowedCharsChaincodeName = "[A-Za-z0-9]+([-_][A-Za-z0-9]+)*"
 	allowedCharsVersion       = "[A-Za-z0-9_.-]+"
+
+	// allowedCharsLegacyChaincodeName is the syntax of the names of the
+	// chaincodes installed or instantiated before the one above applied
+	allowedCharsLegacyChaincodeName = "[A-Za-z0-9_-]+"
+
+	// maxChaincodeNameLength and maxChaincodeVersionLength bound the names
+	// and versions of chaincodes, from which the state database names and
+	// the container names are derived
+	maxChaincodeNameLength    = 64
+	maxChaincodeVersionLength = 64
 )
 
+// reservedChaincodeNamePrefixes are the prefixes of the names user chaincodes
+// cannot take, compared regardless of case: the namespaces of the chaincodes
+// would collide with the ones of the system chaincodes, also in the state
+// databases whose names are case-insensitive
+var reservedChaincodeNamePrefixes = []string{"lscc", "qscc", "cscc", "escc", "vscc"}
+
 //---------- the LSCC -----------------
 
 // LifeCycleSysCC implements chaincode lifecycle and policies aroud it
@@ -170,7 +197,21 @@ func (f InvalidChainNameErr) Error() string {
 type InvalidChaincodeNameErr string
 
 func (f InvalidChaincodeNameErr) Error() string {
-	return fmt.Sprintf("invalid chaincode name '%s'. Names can only consist of alphanumerics, '_', and '-'", string(f))
+	return fmt.Sprintf("invalid chaincode name '%s'. Names can only consist of alphanumerics, '_', and '-', and must start and end with an alphanumeric", string(f))
+}
+
+//ChaincodeNameTooLongErr chaincode name too long error
+type ChaincodeNameTooLongErr string
+
+func (f ChaincodeNameTooLongErr) Error() string {
+	return fmt.Sprintf("chaincode name '%s' exceeds the maximum length of %d characters", string(f), maxChaincodeNameLength)
+}
+
+//ReservedChaincodeNameErr chaincode name reserved for system chaincodes error
+type ReservedChaincodeNameErr string
+
+func (f ReservedChaincodeNameErr) Error() string {
+	return fmt.Sprintf("chaincode name '%s' is reserved for system chaincodes", string(f))
 }
 
 //EmptyChaincodeNameErr trying to upgrade to same version of Chaincode
@@ -187,6 +228,13 @@ func (f InvalidVersionErr) Error() string {
 	return fmt.Sprintf("invalid chaincode version '%s'. Versions can only consist of alphanumerics, '_',  '-', and '.'", string(f))
 }
 
+//VersionTooLongErr chaincode version too long error
+type VersionTooLongErr string
+
+func (f VersionTooLongErr) Error() string {
+	return fmt.Sprintf("chaincode version '%s' exceeds the maximum length of %d characters", string(f), maxChaincodeVersionLength)
+}
+
 //ChaincodeMismatchErr chaincode name from two places don't match
 type ChaincodeMismatchErr string
 
@@ -319,6 +367,37 @@ func (lscc *LifeCycleSysCC) getCCCode(ccname string, cdbytes []byte) (*ccprovide
 	return cd, depspec, depspecbytes, nil
 }
 
+// getChaincodeDefinition returns the ChaincodeDefinition of an instantiated
+// chaincode, so that applications can discover which organizations have to
+// endorse its transactions
+func (lscc *LifeCycleSysCC) getChaincodeDefinition(ccname string, cdbytes []byte) pb.Response {
+	cd, err := lscc.getChaincodeData(ccname, cdbytes)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	def := &ccprovider.ChaincodeDefinition{
+		Name:              cd.Name,
+		Version:           cd.Version,
+		Escc:              cd.Escc,
+		Vscc:              cd.Vscc,
+		EndorsementPolicy: cd.Policy,
+	}
+
+	env := &common.SignaturePolicyEnvelope{}
+	if err = proto.Unmarshal(cd.Policy, env); err != nil {
+		logger.Warningf("Invalid endorsement policy for chaincode %s: %s", ccname, err)
+	} else if def.EndorsementPolicyExpression, err = cauthdsl.PolicyString(env); err != nil {
+		logger.Debugf("Endorsement policy of chaincode %s cannot be represented as an expression: %s", ccname, err)
+	}
+
+	defbytes, err := proto.Marshal(def)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success(defbytes)
+}
+
 // getChaincodes returns all chaincodes instantiated on this LSCC's channel
 func (lscc *LifeCycleSysCC) getChaincodes(stub shim.ChaincodeStubInterface) pb.Response {
 	// get all rows from LSCC
@@ -388,6 +467,33 @@ func (lscc *LifeCycleSysCC) getInstalledChaincodes() pb.Response {
 	return shim.Success(cqrbytes)
 }
 
+// getInstalledPackage returns the bytes of the chaincode package as
+// installed on the peer
+func (lscc *LifeCycleSysCC) getInstalledPackage(ccname string, version string) pb.Response {
+	ccbytes, err := ccprovider.GetChaincodePackage(ccname, version)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed retrieving installed chaincode %s:%s - %s", ccname, version, err))
+	}
+
+	return shim.Success(ccbytes)
+}
+
+// getInstalledMetadata returns the metadata of the chaincode package
+// installed on the peer
+func (lscc *LifeCycleSysCC) getInstalledMetadata(ccname string, version string) pb.Response {
+	md, err := ccprovider.GetInstalledChaincodeMetadata(ccname, version)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed retrieving installed chaincode %s:%s - %s", ccname, version, err))
+	}
+
+	mdbytes, err := proto.Marshal(md)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(mdbytes)
+}
+
 //do access control
 func (lscc *LifeCycleSysCC) acl(stub shim.ChaincodeStubInterface, chainname string, cds *pb.ChaincodeDeploymentSpec) error {
 	return nil
@@ -403,16 +509,32 @@ func (lscc *LifeCycleSysCC) isValidChainName(chainname string) bool {
 }
 
 // isValidChaincodeName checks the validity of chaincode name. Chaincode names
-// should never be blank and should only consist of alphanumerics, '_', and '-'
+// should never be blank, should only consist of alphanumerics, '_', and '-',
+// starting and ending with an alphanumeric, and should not be the name of a
+// system chaincode or start with a prefix reserved for system chaincodes
 func (lscc *LifeCycleSysCC) isValidChaincodeName(chaincodeName string) error {
 	if chaincodeName == "" {
 		return EmptyChaincodeNameErr("")
 	}
 
+	if len(chaincodeName) > maxChaincodeNameLength {
+		return ChaincodeNameTooLongErr(chaincodeName)
+	}
+
 	if !isValidCCNameOrVersion(chaincodeName, allowedCharsChaincodeName) {
 		return InvalidChaincodeNameErr(chaincodeName)
 	}
 
+	lowerName := strings.ToLower(chaincodeName)
+	for _, prefix := range reservedChaincodeNamePrefixes {
+		if strings.HasPrefix(lowerName, prefix) {
+			return ReservedChaincodeNameErr(chaincodeName)
+		}
+	}
+	if lscc.sccprovider != nil && lscc.sccprovider.IsSysCC(chaincodeName) {
+		return ReservedChaincodeNameErr(chaincodeName)
+	}
+
 	return nil
 }
 
@@ -424,6 +546,40 @@ func (lscc *LifeCycleSysCC) isValidChaincodeVersion(chaincodeName string, versio
 		return EmptyVersionErr(chaincodeName)
 	}
 
+	if len(version) > maxChaincodeVersionLength {
+		return VersionTooLongErr(version)
+	}
+
+	if !isValidCCNameOrVersion(version, allowedCharsVersion) {
+		return InvalidVersionErr(version)
+	}
+
+	return nil
+}
+
+// isValidLegacyChaincodeName checks the validity of the name of a chaincode
+// that may have been installed or instantiated before the stricter syntax
+// applied to the names of new chaincodes. Such names should never be blank
+// and should only consist of alphanumerics, '_', and '-'
+func (lscc *LifeCycleSysCC) isValidLegacyChaincodeName(chaincodeName string) error {
+	if chaincodeName == "" {
+		return EmptyChaincodeNameErr("")
+	}
+
+	if !isValidCCNameOrVersion(chaincodeName, allowedCharsLegacyChaincodeName) {
+		return InvalidChaincodeNameErr(chaincodeName)
+	}
+
+	return nil
+}
+
+// isValidLegacyChaincodeVersion checks the validity of the version of a
+// chaincode that may have been installed before the versions were bounded
+func (lscc *LifeCycleSysCC) isValidLegacyChaincodeVersion(chaincodeName string, version string) error {
+	if version == "" {
+		return EmptyVersionErr(chaincodeName)
+	}
+
 	if !isValidCCNameOrVersion(version, allowedCharsVersion) {
 		return InvalidVersionErr(version)
 	}
@@ -455,11 +611,13 @@ func (lscc *LifeCycleSysCC) executeInstall(stub shim.ChaincodeStubInterface, ccb
 		return fmt.Errorf("nil deployment spec from from the CC package")
 	}
 
-	if err = lscc.isValidChaincodeName(cds.ChaincodeSpec.ChaincodeId.Name); err != nil {
+	// the package may be the upgrade of a chaincode instantiated before the
+	// stricter names applied: those are only enforced on deploy
+	if err = lscc.isValidLegacyChaincodeName(cds.ChaincodeSpec.ChaincodeId.Name); err != nil {
 		return err
 	}
 
-	if err = lscc.isValidChaincodeVersion(cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version); err != nil {
+	if err = lscc.isValidLegacyChaincodeVersion(cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version); err != nil {
 		return err
 	}
 
@@ -487,13 +645,7 @@ func (lscc *LifeCycleSysCC) getInstantiationPolicy(stub shim.ChaincodeStubInterf
 
 // checkInstantiationPolicy evaluates an instantiation policy against a signed proposal
 func (lscc *LifeCycleSysCC) checkInstantiationPolicy(stub shim.ChaincodeStubInterface, chainName string, instantiationPolicy []byte) error {
-	// create a policy object from the policy bytes
-	mgr := mspmgmt.GetManagerForChain(chainName)
-	if mgr == nil {
-		return fmt.Errorf("Error checking chaincode instantiation policy: MSP manager for chain %s not found", chainName)
-	}
-	npp := cauthdsl.NewPolicyProvider(mgr)
-	instPol, _, err := npp.NewPolicy(instantiationPolicy)
+	instPol, err := lscc.newInstantiationPolicy(chainName, instantiationPolicy)
 	if err != nil {
 		return err
 	}
@@ -528,6 +680,39 @@ func (lscc *LifeCycleSysCC) checkInstantiationPolicy(stub shim.ChaincodeStubInte
 	return nil
 }
 
+// newInstantiationPolicy creates a policy object from the instantiation policy
+// bytes. A template such as "MAJORITY Admins" is resolved against the
+// application organizations of the current channel config, so that orgs
+// added after the chaincode was packaged take part in its upgrades
+func (lscc *LifeCycleSysCC) newInstantiationPolicy(chainName string, instantiationPolicy []byte) (policies.Policy, error) {
+	template, isTemplate, err := ccpackage.InstantiationPolicyTemplate(instantiationPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if isTemplate {
+		pm := peer.GetPolicyManager(chainName)
+		if pm == nil {
+			return nil, fmt.Errorf("Error checking chaincode instantiation policy: policy manager for chain %s not found", chainName)
+		}
+		appManager, ok := pm.Manager([]string{policies.ApplicationPrefix})
+		if !ok {
+			return nil, fmt.Errorf("Error checking chaincode instantiation policy: chain %s has no application policies", chainName)
+		}
+		return policies.NewImplicitMetaPolicyOf(appManager, template)
+	}
+
+	mgr := mspmgmt.GetManagerForChain(chainName)
+	if mgr == nil {
+		return nil, fmt.Errorf("Error checking chaincode instantiation policy: MSP manager for chain %s not found", chainName)
+	}
+	npp := cauthdsl.NewPolicyProvider(mgr)
+	instPol, _, err := npp.NewPolicy(instantiationPolicy)
+	if err != nil {
+		return nil, err
+	}
+	return instPol, nil
+}
+
 // executeDeploy implements the "instantiate" Invoke transaction
 func (lscc *LifeCycleSysCC) executeDeploy(stub shim.ChaincodeStubInterface, chainname string, depSpec []byte, policy []byte, escc []byte, vscc []byte) (*ccprovider.ChaincodeData, error) {
 	cds, err := utils.GetChaincodeDeploymentSpec(depSpec)
@@ -596,8 +781,10 @@ func (lscc *LifeCycleSysCC) executeUpgrade(stub shim.ChaincodeStubInterface, cha
 		return nil, err
 	}
 
+	// the chaincode has to exist on the channel, possibly since before the
+	// stricter names applied to new chaincodes
 	chaincodeName := cds.ChaincodeSpec.ChaincodeId.Name
-	if err = lscc.isValidChaincodeName(chaincodeName); err != nil {
+	if err = lscc.isValidLegacyChaincodeName(chaincodeName); err != nil {
 		return nil, err
 	}
 
@@ -816,7 +1003,7 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			return shim.Error(err.Error())
 		}
 		return shim.Success(cdbytes)
-	case GETCCINFO, GETDEPSPEC, GETCCDATA:
+	case GETCCINFO, GETDEPSPEC, GETCCDATA, GETCCDEFINITION:
 		if len(args) != 3 {
 			return shim.Error(InvalidArgsLenErr(len(args)).Error())
 		}
@@ -846,6 +1033,8 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 			return shim.Success([]byte(cd.Name))
 		case GETCCDATA:
 			return shim.Success(cdbytes)
+		case GETCCDEFINITION:
+			return lscc.getChaincodeDefinition(ccname, cdbytes)
 		default:
 			_, _, depspecbytes, err := lscc.getCCCode(ccname, cdbytes)
 			if err != nil {
@@ -875,6 +1064,31 @@ func (lscc *LifeCycleSysCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response
 		}
 
 		return lscc.getInstalledChaincodes()
+	case GETINSTALLEDPACKAGE, GETINSTALLEDMETADATA:
+		if len(args) != 3 {
+			return shim.Error(InvalidArgsLenErr(len(args)).Error())
+		}
+
+		ccname := string(args[1])
+		version := string(args[2])
+
+		// 2. check local MSP Admins policy
+		if err = lscc.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+			return shim.Error(fmt.Sprintf("Authorization for %s on %s:%s has been denied with error %s", function, ccname, version, err))
+		}
+
+		// the name and version make up the path of the package on the file system
+		if err = lscc.isValidLegacyChaincodeName(ccname); err != nil {
+			return shim.Error(err.Error())
+		}
+		if err = lscc.isValidLegacyChaincodeVersion(ccname, version); err != nil {
+			return shim.Error(err.Error())
+		}
+
+		if function == GETINSTALLEDPACKAGE {
+			return lscc.getInstalledPackage(ccname, version)
+		}
+		return lscc.getInstalledMetadata(ccname, version)
 	}
 
 	return shim.Error(InvalidFunctionErr(function).Error())
@@ -24,6 +24,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccpackage"
@@ -60,13 +61,17 @@ type mocksccProviderImpl struct {
 }
 
 func (c *mocksccProviderImpl) IsSysCC(name string) bool {
-	return true
+	return name == "lscc" || name == "escc" || name == "vscc" || name == "qscc" || name == "cscc" || name == "mysyscc"
 }
 
 func (c *mocksccProviderImpl) IsSysCCAndNotInvokableCC2CC(name string) bool {
 	return false
 }
 
+func (c *mocksccProviderImpl) GetApplicationConfig(cid string) (config.Application, bool) {
+	return nil, false
+}
+
 func register(stub *shim.MockStub, ccname string) error {
 	args := [][]byte{[]byte("register"), []byte(ccname)}
 	if res := stub.MockInvoke("1", args); res.Status != shim.OK {
@@ -111,6 +116,12 @@ func TestInstall(t *testing.T) {
 	testInstall(t, "example02.go", "0", path, InvalidChaincodeNameErr("example02.go").Error(), "Alice")
 	testInstall(t, "", "0", path, EmptyChaincodeNameErr("").Error(), "Alice")
 	testInstall(t, "example02", "1{}0", path, InvalidVersionErr("1{}0").Error(), "Alice")
+	// the packages of chaincodes named before the stricter names applied can
+	// still be installed, to upgrade them
+	testInstall(t, "-example02", "0", path, "", "Alice")
+	testInstall(t, "example__02", "0", path, "", "Alice")
+	testInstall(t, strings.Repeat("a", 65), "0", path, "", "Alice")
+	testInstall(t, "example02", strings.Repeat("1", 65), path, "", "Alice")
 	testInstall(t, "example02", "0", path, "Authorization for INSTALL on", "Bob")
 }
 
@@ -124,7 +135,7 @@ func testInstall(t *testing.T, ccname string, version string, path string, expec
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policy.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policy.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policy.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policy.MockChannelPolicyManager{MockPolicy: &policy.MockPolicy{Deserializer: identityDeserializer}},
@@ -263,6 +274,13 @@ func TestDeploy(t *testing.T) {
 	testDeploy(t, "example02", "0", path, false, true, EmptyVersionErr("example02").Error())
 	testDeploy(t, "example02.go", "0", path, false, false, InvalidChaincodeNameErr("example02.go").Error())
 	testDeploy(t, "example02", "1{}0", path, false, false, InvalidVersionErr("1{}0").Error())
+	testDeploy(t, "-example02", "0", path, false, false, InvalidChaincodeNameErr("-example02").Error())
+	testDeploy(t, "example__02", "0", path, false, false, InvalidChaincodeNameErr("example__02").Error())
+	testDeploy(t, strings.Repeat("a", 65), "0", path, false, false, ChaincodeNameTooLongErr(strings.Repeat("a", 65)).Error())
+	testDeploy(t, "example02", strings.Repeat("1", 65), path, false, false, VersionTooLongErr(strings.Repeat("1", 65)).Error())
+	testDeploy(t, "lscc", "0", path, false, false, ReservedChaincodeNameErr("lscc").Error())
+	testDeploy(t, "QSCC-2", "0", path, false, false, ReservedChaincodeNameErr("QSCC-2").Error())
+	testDeploy(t, "mysyscc", "0", path, false, false, ReservedChaincodeNameErr("mysyscc").Error())
 	testDeploy(t, "example02", "0", path, true, true, EmptyChaincodeNameErr("").Error())
 }
 
@@ -276,7 +294,7 @@ func testDeploy(t *testing.T, ccname string, version string, path string, forceB
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policy.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policy.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policy.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policy.MockChannelPolicyManager{MockPolicy: &policy.MockPolicy{Deserializer: identityDeserializer}},
@@ -402,7 +420,7 @@ func TestMultipleDeploy(t *testing.T) {
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policy.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policy.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policy.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policy.MockChannelPolicyManager{MockPolicy: &policy.MockPolicy{Deserializer: identityDeserializer}},
@@ -487,7 +505,7 @@ func TestRetryFailedDeploy(t *testing.T) {
 		t.FailNow()
 	}
 	// Init the policy checker
-	identityDeserializer := &policy.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policy.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policy.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policy.MockChannelPolicyManager{MockPolicy: &policy.MockPolicy{Deserializer: identityDeserializer}},
@@ -550,7 +568,7 @@ func TestTamperChaincode(t *testing.T) {
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policy.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policy.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policy.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policy.MockChannelPolicyManager{MockPolicy: &policy.MockPolicy{Deserializer: identityDeserializer}},
@@ -653,7 +671,7 @@ func testIPolDeploy(t *testing.T, iPol string, successExpected bool) {
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policy.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policy.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policy.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			chainid: &policy.MockChannelPolicyManager{MockPolicy: &policy.MockPolicy{Deserializer: identityDeserializer}},
@@ -811,6 +829,69 @@ func testUpgrade(t *testing.T, ccname string, version string, newccname string,
 	}
 }
 
+// TestUpgradeLegacyChaincodeName tests that a chaincode instantiated before
+// the stricter names applied to new chaincodes can still be upgraded
+func TestUpgradeLegacyChaincodeName(t *testing.T) {
+	path := "github.com/hyperledger/fabric/examples/chaincode/go/chaincode_example02"
+
+	scc := new(LifeCycleSysCC)
+	stub := shim.NewMockStub("lscc", scc)
+
+	if res := stub.MockInit("1", nil); res.Status != shim.OK {
+		t.Fatalf("Init failed: %s", string(res.Message))
+	}
+
+	cds, err := constructDeploymentSpec("example__02", path, "0", [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")}, true)
+	if err != nil {
+		t.FailNow()
+	}
+	defer os.Remove(lscctestpath + "/example__02.0")
+	var b []byte
+	if b, err = proto.Marshal(cds); err != nil || b == nil {
+		t.Fatalf("Marshal DeploymentSpec failed")
+	}
+
+	args := [][]byte{[]byte(DEPLOY), []byte("test"), b}
+	if res := stub.MockInvoke("1", args); res.Message != InvalidChaincodeNameErr("example__02").Error() {
+		t.Fatalf("Deploy of a legacy chaincode name should fail, got: %s", res.Message)
+	}
+
+	// write the entry of the chaincode as instantiated under the legacy names
+	cdbytes, err := proto.Marshal(&ccprovider.ChaincodeData{Name: "example__02", Version: "0", Escc: "escc", Vscc: "vscc"})
+	if err != nil {
+		t.Fatalf("Marshal ChaincodeData failed")
+	}
+	stub.MockTransactionStart("2")
+	if err = stub.PutState("example__02", cdbytes); err != nil {
+		t.Fatalf("PutState failed: %s", err)
+	}
+	stub.MockTransactionEnd("2")
+
+	newCds, err := constructDeploymentSpec("example__02", path, "1", [][]byte{[]byte("init"), []byte("a"), []byte("100"), []byte("b"), []byte("200")}, true)
+	if err != nil {
+		t.FailNow()
+	}
+	defer os.Remove(lscctestpath + "/example__02.1")
+	var newb []byte
+	if newb, err = proto.Marshal(newCds); err != nil || newb == nil {
+		t.Fatalf("Marshal DeploymentSpec failed")
+	}
+
+	args = [][]byte{[]byte(UPGRADE), []byte("test"), newb}
+	res := stub.MockInvoke("3", args)
+	if res.Status != shim.OK {
+		t.Fatalf("Upgrade chaincode error: %s", res.Message)
+	}
+
+	cd := &ccprovider.ChaincodeData{}
+	if err = proto.Unmarshal(res.Payload, cd); err != nil {
+		t.Fatalf("Upgrade chaincode could not unmarshal response")
+	}
+	if cd.Version != "1" {
+		t.Fatalf("Upgrade chaincode version error, expected 1, got %s", cd.Version)
+	}
+}
+
 //TestIPolUpgrade tests chaincode deploy with an instantiation policy
 func TestIPolUpgrade(t *testing.T) {
 	// default policy, this should succeed
@@ -828,7 +909,7 @@ func testIPolUpgrade(t *testing.T, iPol string, successExpected bool) {
 		t.FailNow()
 	}
 	// Init the policy checker
-	identityDeserializer := &policy.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policy.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policy.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			chainid: &policy.MockChannelPolicyManager{MockPolicy: &policy.MockPolicy{Deserializer: identityDeserializer}},
@@ -950,7 +1031,7 @@ func TestGetAPIsWithoutInstall(t *testing.T) {
 		t.FailNow()
 	}
 	// Init the policy checker
-	identityDeserializer := &policy.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policy.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policy.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policy.MockChannelPolicyManager{MockPolicy: &policy.MockPolicy{Deserializer: identityDeserializer}},
@@ -1050,7 +1131,7 @@ func TestGetInstalledChaincodesAccessRights(t *testing.T) {
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policy.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policy.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policy.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policy.MockChannelPolicyManager{MockPolicy: &policy.MockPolicy{Deserializer: identityDeserializer}},
@@ -1082,6 +1163,93 @@ func TestGetInstalledChaincodesAccessRights(t *testing.T) {
 	}
 }
 
+// TestGetInstalledPackage verifies that admins can retrieve the package
+// and the metadata of an installed chaincode
+func TestGetInstalledPackage(t *testing.T) {
+	scc := new(LifeCycleSysCC)
+	stub := shim.NewMockStub("lscc", scc)
+
+	if res := stub.MockInit("1", nil); res.Status != shim.OK {
+		fmt.Println("Init failed", string(res.Message))
+		t.FailNow()
+	}
+
+	// Init the policy checker
+	identityDeserializer := &policy.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
+	policyManagerGetter := &policy.MockChannelPolicyManagerGetter{
+		Managers: map[string]policies.Manager{
+			"test": &policy.MockChannelPolicyManager{MockPolicy: &policy.MockPolicy{Deserializer: identityDeserializer}},
+		},
+	}
+	scc.policyChecker = policy.NewPolicyChecker(
+		policyManagerGetter,
+		identityDeserializer,
+		&policy.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	)
+
+	path := "github.com/hyperledger/fabric/examples/chaincode/go/chaincode_example02"
+	cds, err := constructDeploymentSpec("example02", path, "0", [][]byte{[]byte("init")}, true)
+	if err != nil {
+		t.FailNow()
+	}
+	defer os.Remove(lscctestpath + "/example02.0")
+
+	installed, err := ccprovider.GetChaincodePackage("example02", "0")
+	if err != nil {
+		t.FailNow()
+	}
+
+	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
+	identityDeserializer.Msg = sProp.ProposalBytes
+	sProp.Signature = sProp.ProposalBytes
+
+	args := [][]byte{[]byte(GETINSTALLEDPACKAGE), []byte("example02"), []byte("0")}
+	res := stub.MockInvokeWithSignedProposal("1", args, sProp)
+	if res.Status != shim.OK {
+		t.Fatalf("Getting the installed package failed: %s", res.Message)
+	}
+	if !bytes.Equal(res.Payload, installed) {
+		t.Fatalf("Installed package mismatch")
+	}
+
+	args = [][]byte{[]byte(GETINSTALLEDMETADATA), []byte("example02"), []byte("0")}
+	res = stub.MockInvokeWithSignedProposal("1", args, sProp)
+	if res.Status != shim.OK {
+		t.Fatalf("Getting the installed metadata failed: %s", res.Message)
+	}
+	md := &ccprovider.InstalledChaincodeMetadata{}
+	if err = proto.Unmarshal(res.Payload, md); err != nil {
+		t.FailNow()
+	}
+	if md.Name != "example02" || md.Version != "0" || md.Label != "example02:0" || md.Path != path || md.Platform != cds.ChaincodeSpec.Type.String() || md.PackageType != "CDS" {
+		t.Fatalf("Unexpected metadata %s", md)
+	}
+	if !bytes.Equal(md.PackageHash, util.ComputeSHA256(installed)) || len(md.Id) == 0 {
+		t.Fatalf("Unexpected hashes in metadata %s", md)
+	}
+
+	// not installed
+	args = [][]byte{[]byte(GETINSTALLEDMETADATA), []byte("example02"), []byte("1")}
+	if res = stub.MockInvokeWithSignedProposal("1", args, sProp); res.Status == shim.OK {
+		t.Fatalf("Getting the metadata of a chaincode that is not installed should have failed")
+	}
+
+	// invalid version
+	args = [][]byte{[]byte(GETINSTALLEDPACKAGE), []byte("example02"), []byte("../0")}
+	if res = stub.MockInvokeWithSignedProposal("1", args, sProp); res.Status == shim.OK {
+		t.Fatalf("Getting a package with an invalid version should have failed")
+	}
+
+	// not an admin
+	sProp, _ = utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Bob"), []byte("msg1"))
+	identityDeserializer.Msg = sProp.ProposalBytes
+	sProp.Signature = sProp.ProposalBytes
+	args = [][]byte{[]byte(GETINSTALLEDPACKAGE), []byte("example02"), []byte("0")}
+	if res = stub.MockInvokeWithSignedProposal("1", args, sProp); res.Status == shim.OK {
+		t.Fatalf("Getting the installed package should have been denied")
+	}
+}
+
 // TestGetChaincodesAccessRights verifies that only authorized parties can call
 // the GETCHAINCODES function
 func TestGetChaincodesAccessRights(t *testing.T) {
@@ -1094,7 +1262,7 @@ func TestGetChaincodesAccessRights(t *testing.T) {
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policy.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policy.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policy.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policy.MockChannelPolicyManager{MockPolicy: &policy.MockPolicy{Deserializer: identityDeserializer}},
@@ -1138,7 +1306,7 @@ func TestGetCCAccessRights(t *testing.T) {
 	}
 
 	// Init the policy checker
-	identityDeserializer := &policy.MockIdentityDeserializer{[]byte("Alice"), []byte("msg1")}
+	identityDeserializer := &policy.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
 	policyManagerGetter := &policy.MockChannelPolicyManagerGetter{
 		Managers: map[string]policies.Manager{
 			"test": &policy.MockChannelPolicyManager{MockPolicy: &policy.MockPolicy{Deserializer: identityDeserializer}},
@@ -1209,6 +1377,43 @@ func TestGetCCAccessRights(t *testing.T) {
 		t.FailNow()
 	}
 
+	// GETCCDEFINITION
+	// Should pass
+	args = [][]byte{[]byte(GETCCDEFINITION), []byte("test"), []byte(cds.ChaincodeSpec.ChaincodeId.Name)}
+	sProp, _ = utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
+	identityDeserializer.Msg = sProp.ProposalBytes
+	sProp.Signature = sProp.ProposalBytes
+	res = stub.MockInvokeWithSignedProposal("1", args, sProp)
+	if res.Status != shim.OK {
+		t.Logf("This should pass [%s]", res.Message)
+		t.FailNow()
+	}
+	def := &ccprovider.ChaincodeDefinition{}
+	if err = proto.Unmarshal(res.Payload, def); err != nil {
+		t.Fatalf("Unmarshalling ChaincodeDefinition failed: %s", err)
+	}
+	if def.Name != "example02" || def.Version != "0" || def.Escc != "escc" || def.Vscc != "vscc" {
+		t.Fatalf("Unexpected chaincode definition %s", def)
+	}
+	env := &common.SignaturePolicyEnvelope{}
+	if err = proto.Unmarshal(def.EndorsementPolicy, env); err != nil {
+		t.Fatalf("Unmarshalling the endorsement policy failed: %s", err)
+	}
+	expectedPolicy, err := cauthdsl.PolicyString(env)
+	if err != nil || def.EndorsementPolicyExpression != expectedPolicy {
+		t.Fatalf("Unexpected endorsement policy expression %s, expected %s", def.EndorsementPolicyExpression, expectedPolicy)
+	}
+
+	// Should fail
+	sProp, _ = utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Bob"), []byte("msg1"))
+	identityDeserializer.Msg = sProp.ProposalBytes
+	sProp.Signature = sProp.ProposalBytes
+	res = stub.MockInvokeWithSignedProposal("1", args, sProp)
+	if res.Status == shim.OK {
+		t.Logf("This should fail [%s]", res.Message)
+		t.FailNow()
+	}
+
 	// GETCCDATA
 	// Should pass
 	args = [][]byte{[]byte(GETCCDATA), []byte("test"), []byte(cds.ChaincodeSpec.ChaincodeId.Name)}
@@ -1232,6 +1437,33 @@ func TestGetCCAccessRights(t *testing.T) {
 	}
 }
 
+func TestNewInstantiationPolicy(t *testing.T) {
+	scc := new(LifeCycleSysCC)
+
+	sigPolicy := utils.MarshalOrPanic(cauthdsl.SignedByMspAdmin(mspid))
+	if instPol, err := scc.newInstantiationPolicy(chainid, sigPolicy); err != nil || instPol == nil {
+		t.Fatalf("expected a signature instantiation policy, got %s", err)
+	}
+
+	if _, err := scc.newInstantiationPolicy(chainid, []byte("garbage")); err == nil {
+		t.Fatalf("expected an error with a bad instantiation policy")
+	}
+
+	// a template can't be resolved without the policy manager of the channel
+	template := &common.ImplicitMetaPolicy{Rule: common.ImplicitMetaPolicy_MAJORITY, SubPolicy: "Admins"}
+	env, err := ccpackage.OwnerCreateSignedCCDepSpecWithTemplate(&pb.ChaincodeDeploymentSpec{CodePackage: []byte("codepackage")}, template, nil)
+	if err != nil {
+		t.Fatalf("error creating package %s", err)
+	}
+	_, sdepspec, err := ccpackage.ExtractSignedCCDepSpec(env)
+	if err != nil {
+		t.Fatalf("error extracting package %s", err)
+	}
+	if _, err = scc.newInstantiationPolicy(chainid, sdepspec.InstantiationPolicy); err == nil {
+		t.Fatalf("expected an error resolving a template without the channel policy manager")
+	}
+}
+
 var id msp.SigningIdentity
 var sid []byte
 var mspid string
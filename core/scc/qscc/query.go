@@ -37,7 +37,7 @@ import (
 // - GetChainInfo returns BlockchainInfo
 // - GetBlockByNumber returns a block
 // - GetBlockByHash returns a block
-// - GetTransactionByID returns a transaction
+// - GetTransactionByID returns a transaction and its validation code
 type LedgerQuerier struct {
 	policyChecker policy.PolicyChecker
 }
@@ -75,7 +75,10 @@ func (e *LedgerQuerier) Init(stub shim.ChaincodeStubInterface) pb.Response {
 // # GetChainInfo: Return a BlockchainInfo object marshalled in bytes
 // # GetBlockByNumber: Return the block specified by block number in args[2]
 // # GetBlockByHash: Return the block specified by block hash in args[2]
-// # GetTransactionByID: Return the transaction specified by ID in args[2]
+// # GetTransactionByID: Return a ProcessedTransaction object marshalled in bytes,
+// holding the transaction specified by ID in args[2] along with the code its
+// validation by the committing peer resulted in: only transactions with the
+// VALID code took effect, the state changes of the other ones were discarded
 func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
 
@@ -24,10 +24,15 @@ import (
 
 	"strings"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/policy"
+	"github.com/hyperledger/fabric/protos/common"
 	peer2 "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
@@ -88,6 +93,47 @@ func TestQueryGetTransactionByID(t *testing.T) {
 	}
 }
 
+func TestQueryGetTransactionByIDValidationCode(t *testing.T) {
+	viper.Set("peer.fileSystemPath", "/var/hyperledger/test8/")
+	defer os.RemoveAll("/var/hyperledger/test8/")
+	peer.MockInitialize()
+	peer.MockCreateChain("mytestchainid8")
+
+	// commit a block with a valid and an invalid transaction
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToWriteSet("mycc", "key", []byte("value"))
+	simRes, err := rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+	assert.NoError(t, err)
+	block := testutil.ConstructBlock(t, 1, []byte("previousHash"), [][]byte{simRes, simRes}, false)
+	ledgerUtil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]).SetFlag(1, peer2.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+	assert.NoError(t, peer.GetLedger("mytestchainid8").Commit(block))
+
+	e := new(LedgerQuerier)
+	stub := shim.NewMockStub("LedgerQuerier", e)
+	if res := stub.MockInit("1", nil); res.Status != shim.OK {
+		t.Fatalf("qscc init failed with err: %s", res.Message)
+	}
+
+	for i, expected := range []peer2.TxValidationCode{peer2.TxValidationCode_VALID, peer2.TxValidationCode_ENDORSEMENT_POLICY_FAILURE} {
+		env, err := utils.GetEnvelopeFromBlock(block.Data.Data[i])
+		assert.NoError(t, err)
+		payl, err := utils.GetPayload(env)
+		assert.NoError(t, err)
+		chdr, err := utils.UnmarshalChannelHeader(payl.Header.ChannelHeader)
+		assert.NoError(t, err)
+
+		args := [][]byte{[]byte(GetTransactionByID), []byte("mytestchainid8"), []byte(chdr.TxId)}
+		res := stub.MockInvoke("2", args)
+		if res.Status != shim.OK {
+			t.Fatalf("qscc getTransactionByID failed with err: %s", res.Message)
+		}
+		processedTran := &peer2.ProcessedTransaction{}
+		assert.NoError(t, proto.Unmarshal(res.Payload, processedTran))
+		assert.Equal(t, int32(expected), processedTran.ValidationCode, "Unexpected validation code for transaction %d", i)
+		assert.Equal(t, block.Data.Data[i], utils.MarshalOrPanic(processedTran.TransactionEnvelope))
+	}
+}
+
 func TestQueryWithWrongParameters(t *testing.T) {
 	viper.Set("peer.fileSystemPath", "/var/hyperledger/test4/")
 	defer os.RemoveAll("/var/hyperledger/test4/")
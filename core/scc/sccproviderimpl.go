@@ -17,7 +17,9 @@ limitations under the License.
 package scc
 
 import (
+	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/peer"
 )
 
 // sccProviderFactory implements the sysccprovider.SystemChaincodeProviderFactory
@@ -50,3 +52,9 @@ func (c *sccProviderImpl) IsSysCC(name string) bool {
 func (c *sccProviderImpl) IsSysCCAndNotInvokableCC2CC(name string) bool {
 	return IsSysCCAndNotInvokableCC2CC(name)
 }
+
+// GetApplicationConfig returns the application config of the
+// specified channel, and false if the channel does not exist
+func (c *sccProviderImpl) GetApplicationConfig(cid string) (config.Application, bool) {
+	return peer.GetApplicationConfig(cid)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vscc
+
+import (
+	"fmt"
+
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// StateFetcher gives validation system chaincodes read-only access to the
+// committed state of the channel, e.g. to read key-level validation
+// parameters, at the height of the block under validation: the state reflects
+// all the previous blocks, and none of the transactions of the block, not even
+// the ones preceding the transaction being validated. It reads through the tx
+// simulator the VSCC is invoked with. With ledger.commit.pipeline enabled, a
+// block may be validated while the previous one is written, provided the
+// previous one writes no metadata of keys: reading the values of the state
+// then fails, and the block is validated again once the previous one is
+// committed.
+type StateFetcher interface {
+	// GetState returns the committed value of the key in the namespace of a chaincode
+	GetState(namespace string, key string) ([]byte, error)
+
+	// GetStateMultipleKeys returns the committed values of the keys in the namespace of a chaincode
+	GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error)
+
+	// GetStateRangeScanIterator returns an iterator over the committed key-values
+	// of the namespace of a chaincode between startKey (included) and endKey (excluded)
+	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.ResultsIterator, error)
+
+	// GetStateMetadata returns the committed metadata of the key in the namespace of a chaincode,
+	// e.g. its key-level validation parameters, or nil if the key has no metadata
+	GetStateMetadata(namespace, key string) (map[string][]byte, error)
+
+	// Done releases the resources held by the StateFetcher
+	Done()
+}
+
+// NewStateFetcher returns a StateFetcher over the committed state of the
+// channel of the transaction being validated, given the stub of the VSCC
+// invocation and the serialized Envelope received by VSCCs in args[1]. It is
+// only usable during the invocation. Done must be called once the
+// StateFetcher is no longer needed.
+func NewStateFetcher(stub shim.ChaincodeStubInterface, envBytes []byte) (StateFetcher, error) {
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, fmt.Errorf("GetEnvelope failed, err %s", err)
+	}
+
+	payl, err := utils.GetPayload(env)
+	if err != nil {
+		return nil, fmt.Errorf("GetPayload failed, err %s", err)
+	}
+	if payl.Header == nil {
+		return nil, fmt.Errorf("missing payload header")
+	}
+
+	chdr, err := utils.UnmarshalChannelHeader(payl.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	if chdr.ChannelId == "" {
+		return nil, fmt.Errorf("transaction header does not contain a chain ID")
+	}
+
+	sf, err := sysccprovider.NewTxStateFetcher(chdr.ChannelId, stub.GetTxID())
+	if err != nil {
+		return nil, fmt.Errorf("Could not retrieve the state of channel %s, error %s", chdr.ChannelId, err)
+	}
+
+	return sf, nil
+}
@@ -0,0 +1,164 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vscc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/config"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"golang.org/x/net/context"
+)
+
+type mockQueryExecutor struct {
+	state    map[string]map[string][]byte
+	metadata map[string]map[string]map[string][]byte
+	done     bool
+}
+
+func (qe *mockQueryExecutor) GetState(namespace string, key string) ([]byte, error) {
+	return qe.state[namespace][key], nil
+}
+
+func (qe *mockQueryExecutor) GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error) {
+	var values [][]byte
+	for _, key := range keys {
+		values = append(values, qe.state[namespace][key])
+	}
+	return values, nil
+}
+
+func (qe *mockQueryExecutor) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.ResultsIterator, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (qe *mockQueryExecutor) ExecuteQuery(namespace, query string) (commonledger.ResultsIterator, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (qe *mockQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	return qe.metadata[namespace][key], nil
+}
+
+func (qe *mockQueryExecutor) Done() {
+	qe.done = true
+}
+
+type mockSccProviderFactory struct {
+	apps map[string]config.Application
+}
+
+func (f *mockSccProviderFactory) NewSystemChaincodeProvider() sysccprovider.SystemChaincodeProvider {
+	return f
+}
+
+func (f *mockSccProviderFactory) IsSysCC(name string) bool {
+	return true
+}
+
+func (f *mockSccProviderFactory) IsSysCCAndNotInvokableCC2CC(name string) bool {
+	return false
+}
+
+func (f *mockSccProviderFactory) GetApplicationConfig(cid string) (config.Application, bool) {
+	ac, ok := f.apps[cid]
+	return ac, ok
+}
+
+func envelopeFor(chainID string) []byte {
+	chdr := &common.ChannelHeader{Type: int32(common.HeaderType_ENDORSER_TRANSACTION), ChannelId: chainID, TxId: "txid"}
+	payl := &common.Payload{Header: &common.Header{ChannelHeader: utils.MarshalOrPanic(chdr)}}
+	return utils.MarshalOrPanic(&common.Envelope{Payload: utils.MarshalOrPanic(payl)})
+}
+
+func TestStateFetcher(t *testing.T) {
+	qe := &mockQueryExecutor{
+		state:    map[string]map[string][]byte{"mycc": {"key": []byte("value")}},
+		metadata: map[string]map[string]map[string][]byte{"mycc": {"key": {"VALIDATION_PARAMETER": []byte("policy")}}},
+	}
+	stub := shim.NewMockStub("vscc", nil)
+	stub.TxID = "vscctxid"
+	release := sysccprovider.RegisterTxQueryExecutor(context.Background(), "mychannel", "vscctxid", qe)
+
+	sf, err := NewStateFetcher(stub, envelopeFor("mychannel"))
+	if err != nil {
+		t.Fatalf("NewStateFetcher failed: %s", err)
+	}
+
+	v, err := sf.GetState("mycc", "key")
+	if err != nil || string(v) != "value" {
+		t.Fatalf("Unexpected state %s (err %v)", v, err)
+	}
+
+	sf.Done()
+	if qe.done {
+		t.Fatalf("Done should not have released the tx simulator of the validator")
+	}
+	release()
+
+	if _, err = NewStateFetcher(stub, envelopeFor("otherchannel")); err == nil {
+		t.Fatalf("NewStateFetcher should have failed for an unknown channel")
+	}
+
+	if _, err = NewStateFetcher(stub, envelopeFor("")); err == nil {
+		t.Fatalf("NewStateFetcher should have failed for a transaction without channel")
+	}
+
+	if _, err = NewStateFetcher(stub, []byte("garbage")); err == nil {
+		t.Fatalf("NewStateFetcher should have failed for an invalid envelope")
+	}
+}
+
+func TestStateFetcherUnsettledState(t *testing.T) {
+	qe := &mockQueryExecutor{
+		state:    map[string]map[string][]byte{"mycc": {"key": []byte("value")}},
+		metadata: map[string]map[string]map[string][]byte{"mycc": {"key": {"VALIDATION_PARAMETER": []byte("policy")}}},
+	}
+	stub := shim.NewMockStub("vscc", nil)
+	stub.TxID = "vscctxid"
+	unsettled := &sysccprovider.UnsettledState{}
+	ctxt := sysccprovider.WithUnsettledState(context.Background(), unsettled)
+	defer sysccprovider.RegisterTxQueryExecutor(ctxt, "mychannel", "vscctxid", qe)()
+
+	sf, err := NewStateFetcher(stub, envelopeFor("mychannel"))
+	if err != nil {
+		t.Fatalf("NewStateFetcher failed: %s", err)
+	}
+	defer sf.Done()
+
+	// The metadata of the keys is settled while the previous block is committed
+	md, err := sf.GetStateMetadata("mycc", "key")
+	if err != nil || string(md["VALIDATION_PARAMETER"]) != "policy" {
+		t.Fatalf("Unexpected metadata %v (err %v)", md, err)
+	}
+	if unsettled.ValuesRead() {
+		t.Fatalf("Reading metadata should not have been recorded as reading values")
+	}
+
+	// The values are not
+	if _, err = sf.GetState("mycc", "key"); err != sysccprovider.ErrStateNotSettled {
+		t.Fatalf("Expected ErrStateNotSettled, got %v", err)
+	}
+	if !unsettled.ValuesRead() {
+		t.Fatalf("Reading values should have been recorded")
+	}
+}
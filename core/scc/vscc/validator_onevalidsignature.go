@@ -21,9 +21,15 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/scc/lscc"
+	"github.com/hyperledger/fabric/msp"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -86,12 +92,26 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 		logger.Errorf("VSCC error: GetPayload failed, err %s", err)
 		return shim.Error(err.Error())
 	}
+	if payl.Header == nil {
+		logger.Errorf("VSCC error: nil payload header")
+		return shim.Error("Nil payload header")
+	}
 
 	chdr, err := utils.UnmarshalChannelHeader(payl.Header.ChannelHeader)
 	if err != nil {
 		return shim.Error(err.Error())
 	}
 
+	// the payload signed by the creator must be canonically encoded, once the
+	// channel requires it of its peers, as the older ones don't check it
+	canonical := hasCapability(chdr.ChannelId, config.CanonicalEncodingCapability)
+	if canonical {
+		if err = utils.CheckCanonical(payl, env.Payload); err != nil {
+			logger.Errorf("VSCC error: CheckCanonical failed, err %s", err)
+			return shim.Error(err.Error())
+		}
+	}
+
 	// get the policy
 	mgr := mspmgmt.GetManagerForChain(chdr.ChannelId)
 	pProvider := cauthdsl.NewPolicyProvider(mgr)
@@ -121,9 +141,27 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 			logger.Errorf("VSCC error: GetChaincodeActionPayload failed, err %s", err)
 			return shim.Error(err.Error())
 		}
+		if cap.Action == nil {
+			logger.Errorf("VSCC error: nil endorsed action")
+			return shim.Error("Nil endorsed action")
+		}
 
 		// this is the first part of the signed message
 		prespBytes := cap.Action.ProposalResponsePayload
+
+		// ...which must be canonically encoded too
+		presp, err := utils.GetProposalResponsePayload(prespBytes)
+		if err != nil {
+			logger.Errorf("VSCC error: GetProposalResponsePayload failed, err %s", err)
+			return shim.Error(err.Error())
+		}
+		if canonical {
+			if err = utils.CheckCanonical(presp, prespBytes); err != nil {
+				logger.Errorf("VSCC error: CheckCanonical failed, err %s", err)
+				return shim.Error(err.Error())
+			}
+		}
+
 		// build the signature set for the evaluation
 		signatureSet := make([]*common.SignedData, len(cap.Action.Endorsements))
 
@@ -139,21 +177,22 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 			}
 		}
 
-		// evaluate the signature set against the policy
-		err = policy.Evaluate(signatureSet)
-		if err != nil {
-			return shim.Error(fmt.Sprintf("VSCC error: policy evaluation failed, err %s", err))
-		}
-
 		hdrExt, err := utils.GetChaincodeHeaderExtension(payl.Header)
 		if err != nil {
 			logger.Errorf("VSCC error: GetChaincodeHeaderExtension failed, err %s", err)
 			return shim.Error(err.Error())
 		}
 
+		// evaluate the signature set against the policy, and against the
+		// key-level endorsement policies of the keys written by the transaction
+		err = vscc.evaluatePolicies(stub, args[1], hdrExt.ChaincodeId.Name, presp, signatureSet, policy, pProvider)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("VSCC error: policy evaluation failed, err %s", err))
+		}
+
 		// do some extra validation that is specific to lscc
 		if hdrExt.ChaincodeId.Name == "lscc" {
-			err = vscc.ValidateLSCCInvocation(cap)
+			err = vscc.ValidateLSCCInvocation(chdr.ChannelId, cap, mgr)
 			if err != nil {
 				logger.Errorf("VSCC error: ValidateLSCCInvocation failed, err %s", err)
 				return shim.Error(err.Error())
@@ -166,7 +205,104 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 	return shim.Success(nil)
 }
 
-func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(cap *pb.ChaincodeActionPayload) error {
+// evaluatePolicies evaluates the signature set of an action against the
+// key-level endorsement policies, committed before the block under
+// validation, of the keys of the chaincode namespace whose value or metadata
+// the action writes. The endorsement policy of the chaincode applies instead
+// when the action writes a key without key-level endorsement policy, or no key.
+func (vscc *ValidatorOneValidSignature) evaluatePolicies(stub shim.ChaincodeStubInterface, envBytes []byte, namespace string, presp *pb.ProposalResponsePayload,
+	signatureSet []*common.SignedData, ccPolicy policies.Policy, pProvider policies.Provider) error {
+	// lscc does not set key-level endorsement policies
+	if namespace == "lscc" {
+		return ccPolicy.Evaluate(signatureSet)
+	}
+
+	keys, err := getWrittenKeys(presp, namespace)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return ccPolicy.Evaluate(signatureSet)
+	}
+
+	sf, err := NewStateFetcher(stub, envBytes)
+	if err != nil {
+		return fmt.Errorf("NewStateFetcher failed, err %s", err)
+	}
+	defer sf.Done()
+
+	chaincodePolicyRequired := false
+	satisfied := make(map[string]bool)
+	for _, key := range keys {
+		metadata, err := sf.GetStateMetadata(namespace, key)
+		if err != nil {
+			return fmt.Errorf("could not get the metadata of key %s, err %s", key, err)
+		}
+		ep := metadata[shim.ValidationParameterKey]
+		if len(ep) == 0 {
+			chaincodePolicyRequired = true
+			continue
+		}
+		if satisfied[string(ep)] {
+			continue
+		}
+
+		keyPolicy, _, err := pProvider.NewPolicy(ep)
+		if err != nil {
+			return fmt.Errorf("invalid key-level endorsement policy for key %s, err %s", key, err)
+		}
+		if err = keyPolicy.Evaluate(signatureSet); err != nil {
+			return fmt.Errorf("key-level endorsement policy of key %s not satisfied, err %s", key, err)
+		}
+		satisfied[string(ep)] = true
+	}
+
+	if chaincodePolicyRequired {
+		return ccPolicy.Evaluate(signatureSet)
+	}
+	logger.Debugf("VSCC info: all the keys written in namespace %s satisfied their key-level endorsement policy", namespace)
+	return nil
+}
+
+// getWrittenKeys returns the keys of the namespace whose value or metadata
+// is written in the results of the proposal response
+func getWrittenKeys(presp *pb.ProposalResponsePayload, namespace string) ([]string, error) {
+	cact, err := utils.GetChaincodeAction(presp.Extension)
+	if err != nil {
+		return nil, fmt.Errorf("GetChaincodeAction failed, err %s", err)
+	}
+
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err = txRWSet.FromProtoBytes(cact.Results); err != nil {
+		return nil, fmt.Errorf("txRWSet.FromProtoBytes failed, err %s", err)
+	}
+
+	var keys []string
+	written := make(map[string]bool)
+	for _, ns := range txRWSet.NsRwSets {
+		if ns.NameSpace != namespace {
+			continue
+		}
+		for _, write := range ns.KvRwSet.Writes {
+			if !written[write.Key] {
+				written[write.Key] = true
+				keys = append(keys, write.Key)
+			}
+		}
+		for _, write := range ns.KvRwSet.MetadataWrites {
+			if !written[write.Key] {
+				written[write.Key] = true
+				keys = append(keys, write.Key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// ValidateLSCCInvocation performs the validation specific to lscc
+// invocations. Deployments and upgrades must record the identity of the
+// chaincode package that every endorser has installed
+func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(chainID string, cap *pb.ChaincodeActionPayload, deserializer msp.IdentityDeserializer) error {
 	cpp, err := utils.GetChaincodeProposalPayload(cap.ChaincodeProposalPayload)
 	if err != nil {
 		logger.Errorf("VSCC error: GetChaincodeProposalPayload failed, err %s", err)
@@ -192,18 +328,120 @@ func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(cap *pb.Chaincode
 	lsccArgs := cis.ChaincodeSpec.Input.Args[1:]
 
 	switch lsccFunc {
-	case lscc.DEPLOY:
-	case lscc.UPGRADE:
+	case lscc.DEPLOY, lscc.UPGRADE:
 		logger.Infof("VSCC info: validating invocation of lscc function %s on arguments %#v", lsccFunc, lsccArgs)
 
-		// TODO: two more crs are expected to fill this gap, as explained in FAB-3155
-		// 1) check that the invocation complies with the InstantiationPolicy
-		// 2) check that the read/write set is appropriate
+		if len(lsccArgs) < 2 {
+			return fmt.Errorf("VSCC error: wrong number of arguments for invocation lscc(%s): expected at least 2, received %d", lsccFunc, len(lsccArgs))
+		}
+
+		cds, err := utils.GetChaincodeDeploymentSpec(lsccArgs[1])
+		if err != nil {
+			return fmt.Errorf("VSCC error: GetChaincodeDeploymentSpec failed, err %s", err)
+		}
+		if cds.ChaincodeSpec == nil || cds.ChaincodeSpec.ChaincodeId == nil {
+			return fmt.Errorf("VSCC error: invalid chaincode deployment spec")
+		}
+
+		cd, err := getWrittenChaincodeData(cap.Action.ProposalResponsePayload, cds.ChaincodeSpec.ChaincodeId.Name)
+		if err != nil {
+			return err
+		}
+		if cd.Name != cds.ChaincodeSpec.ChaincodeId.Name || cd.Version != cds.ChaincodeSpec.ChaincodeId.Version {
+			return fmt.Errorf("VSCC error: chaincode %s:%s recorded by lscc does not match the invocation for %s:%s",
+				cd.Name, cd.Version, cds.ChaincodeSpec.ChaincodeId.Name, cds.ChaincodeSpec.ChaincodeId.Version)
+		}
+		if err = ccprovider.ValidateChaincodeIdentity(cd); err != nil {
+			return fmt.Errorf("VSCC error: invalid chaincode identity, err %s", err)
+		}
+
+		// the endorsers choose the format of the package data, so the legacy
+		// one, recorded by the peers predating the current identity, is only
+		// accepted until the channel config requires every peer to record
+		// the current one
+		if ccprovider.HasLegacyIdentity(cd) {
+			if hasCapability(chainID, config.ChaincodeIdentityCapability) {
+				return fmt.Errorf("VSCC error: chaincode %s:%s records its package data in the legacy format, which channel %s no longer accepts",
+					cd.Name, cd.Version, chainID)
+			}
+			logger.Infof("VSCC info: chaincode %s:%s uses the legacy package identity", cd.Name, cd.Version)
+			return nil
+		}
+
+		// the identity of the package is part of the endorsed results: an
+		// endorser that installed a different package endorsed different
+		// results, so its signature does not verify
+		if err = validateAllEndorsements(cap, deserializer); err != nil {
+			return fmt.Errorf("VSCC error: endorsers disagree on the chaincode package of %s:%s, err %s", cd.Name, cd.Version, err)
+		}
+
+		// TODO: check that the invocation complies with the InstantiationPolicy,
+		// as explained in FAB-3155
 
 		return nil
 	default:
 		return fmt.Errorf("VSCC error: committing an invocation of function %s of lscc is invalid", lsccFunc)
 	}
+}
+
+// hasCapability returns true if the config of the channel requires the capability of its peers
+func hasCapability(chainID string, capability string) bool {
+	ac, ok := sysccprovider.GetSystemChaincodeProvider().GetApplicationConfig(chainID)
+	return ok && ac.HasCapability(capability)
+}
+
+// getWrittenChaincodeData returns the ChaincodeData of the chaincode
+// written by lscc in the results of the proposal response
+func getWrittenChaincodeData(prespBytes []byte, ccname string) (*ccprovider.ChaincodeData, error) {
+	presp, err := utils.GetProposalResponsePayload(prespBytes)
+	if err != nil {
+		return nil, fmt.Errorf("VSCC error: GetProposalResponsePayload failed, err %s", err)
+	}
+
+	cact, err := utils.GetChaincodeAction(presp.Extension)
+	if err != nil {
+		return nil, fmt.Errorf("VSCC error: GetChaincodeAction failed, err %s", err)
+	}
+
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err = txRWSet.FromProtoBytes(cact.Results); err != nil {
+		return nil, fmt.Errorf("VSCC error: txRWSet.FromProtoBytes failed, err %s", err)
+	}
 
+	for _, ns := range txRWSet.NsRwSets {
+		if ns.NameSpace != "lscc" {
+			continue
+		}
+		for _, write := range ns.KvRwSet.Writes {
+			if write.Key != ccname {
+				continue
+			}
+			cd := &ccprovider.ChaincodeData{}
+			if err = proto.Unmarshal(write.Value, cd); err != nil {
+				return nil, fmt.Errorf("VSCC error: unmarshalling ChaincodeData failed, err %s", err)
+			}
+			return cd, nil
+		}
+	}
+
+	return nil, fmt.Errorf("VSCC error: no chaincode data written for chaincode %s", ccname)
+}
+
+// validateAllEndorsements checks that every endorsement of the action
+// is a valid signature over its proposal response
+func validateAllEndorsements(cap *pb.ChaincodeActionPayload, deserializer msp.IdentityDeserializer) error {
+	prespBytes := cap.Action.ProposalResponsePayload
+	for _, endorsement := range cap.Action.Endorsements {
+		endorser, err := deserializer.DeserializeIdentity(endorsement.Endorser)
+		if err != nil {
+			return fmt.Errorf("failed deserializing endorser, err %s", err)
+		}
+		data := make([]byte, 0, len(prespBytes)+len(endorsement.Endorser))
+		data = append(data, prespBytes...)
+		data = append(data, endorsement.Endorser...)
+		if err = endorser.Verify(data, endorsement.Signature); err != nil {
+			return fmt.Errorf("invalid endorsement by %s, err %s", endorser.GetIdentifier().Mspid, err)
+		}
+	}
 	return nil
 }
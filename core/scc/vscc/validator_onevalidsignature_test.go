@@ -16,20 +16,30 @@ limitations under the License.
 package vscc
 
 import (
+	"crypto/sha256"
+	"errors"
 	"testing"
 
 	"fmt"
 	"os"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/config"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/msp"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
+	"golang.org/x/net/context"
 )
 
 func createTx() (*common.Envelope, error) {
@@ -40,7 +50,12 @@ func createTx() (*common.Envelope, error) {
 		return nil, err
 	}
 
-	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &peer.Response{Status: 200}, []byte("res"), nil, nil, id)
+	res, err := rwsetutil.NewRWSetBuilder().GetTxReadWriteSet().ToProtoBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, &peer.Response{Status: 200}, res, nil, nil, nil, id)
 	if err != nil {
 		return nil, err
 	}
@@ -125,6 +140,220 @@ func TestInvoke(t *testing.T) {
 	}
 }
 
+func TestGetWrittenChaincodeData(t *testing.T) {
+	cd := &ccprovider.ChaincodeData{Name: "mycc", Version: "1", Id: []byte("id")}
+	cdbytes, err := proto.Marshal(cd)
+	if err != nil {
+		t.Fatalf("Marshal failed, err %s", err)
+	}
+
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToWriteSet("lscc", "othercc", []byte("garbage"))
+	rwsetBuilder.AddToWriteSet("lscc", "mycc", cdbytes)
+	results, err := rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+	if err != nil {
+		t.Fatalf("ToProtoBytes failed, err %s", err)
+	}
+
+	prespBytes, err := utils.GetBytesProposalResponsePayload([]byte("hash"), &peer.Response{Status: 200}, results, nil, nil)
+	if err != nil {
+		t.Fatalf("GetBytesProposalResponsePayload failed, err %s", err)
+	}
+
+	written, err := getWrittenChaincodeData(prespBytes, "mycc")
+	if err != nil {
+		t.Fatalf("getWrittenChaincodeData failed, err %s", err)
+	}
+	if written.Name != cd.Name || written.Version != cd.Version || string(written.Id) != string(cd.Id) {
+		t.Fatalf("Unexpected chaincode data %s", written)
+	}
+
+	if _, err = getWrittenChaincodeData(prespBytes, "missingcc"); err == nil {
+		t.Fatalf("getWrittenChaincodeData should have failed for a chaincode that was not written")
+	}
+}
+
+// mockPolicyProvider returns the policies of its map, by marshaled policy
+type mockPolicyProvider struct {
+	policies map[string]policies.Policy
+}
+
+func (pp *mockPolicyProvider) NewPolicy(data []byte) (policies.Policy, proto.Message, error) {
+	policy, ok := pp.policies[string(data)]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown policy %s", data)
+	}
+	return policy, nil, nil
+}
+
+func TestKeyLevelPolicies(t *testing.T) {
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToWriteSet("mycc", "asset1", []byte("value"))
+	rwsetBuilder.AddToMetadataWriteSet("mycc", "asset2", map[string][]byte{shim.ValidationParameterKey: []byte("org3")})
+	rwsetBuilder.AddToWriteSet("othercc", "asset3", []byte("value"))
+	results, err := rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+	if err != nil {
+		t.Fatalf("ToProtoBytes failed, err %s", err)
+	}
+	prespBytes, err := utils.GetBytesProposalResponsePayload([]byte("hash"), &peer.Response{Status: 200}, results, nil, nil)
+	if err != nil {
+		t.Fatalf("GetBytesProposalResponsePayload failed, err %s", err)
+	}
+	presp, err := utils.GetProposalResponsePayload(prespBytes)
+	if err != nil {
+		t.Fatalf("GetProposalResponsePayload failed, err %s", err)
+	}
+
+	keys, err := getWrittenKeys(presp, "mycc")
+	if err != nil || len(keys) != 2 || keys[0] != "asset1" || keys[1] != "asset2" {
+		t.Fatalf("Unexpected written keys %v (err %v)", keys, err)
+	}
+
+	qe := &mockQueryExecutor{metadata: map[string]map[string]map[string][]byte{"mycc": {}}}
+	stub := shim.NewMockStub("vscc", nil)
+	stub.TxID = "vscctxid"
+	defer sysccprovider.RegisterTxQueryExecutor(context.Background(), "mychannel", "vscctxid", qe)()
+	envBytes := envelopeFor("mychannel")
+
+	pProvider := &mockPolicyProvider{policies: map[string]policies.Policy{
+		"org1": &mockpolicies.Policy{},
+		"org2": &mockpolicies.Policy{Err: errors.New("not endorsed by org2")},
+	}}
+	satisfied := &mockpolicies.Policy{}
+	unsatisfied := &mockpolicies.Policy{Err: errors.New("not endorsed by the chaincode endorsers")}
+	v := new(ValidatorOneValidSignature)
+
+	// no key-level endorsement policy: the chaincode policy applies
+	if err = v.evaluatePolicies(stub, envBytes, "mycc", presp, nil, satisfied, pProvider); err != nil {
+		t.Fatalf("evaluatePolicies failed, err %s", err)
+	}
+	if err = v.evaluatePolicies(stub, envBytes, "mycc", presp, nil, unsatisfied, pProvider); err == nil {
+		t.Fatalf("evaluatePolicies should have failed without key-level endorsement policies")
+	}
+
+	// a key with a key-level endorsement policy, the other without
+	qe.metadata["mycc"]["asset1"] = map[string][]byte{shim.ValidationParameterKey: []byte("org1")}
+	if err = v.evaluatePolicies(stub, envBytes, "mycc", presp, nil, unsatisfied, pProvider); err == nil {
+		t.Fatalf("evaluatePolicies should have failed for a key without key-level endorsement policy")
+	}
+
+	// all the keys have a key-level endorsement policy: the chaincode policy does not apply
+	qe.metadata["mycc"]["asset2"] = map[string][]byte{shim.ValidationParameterKey: []byte("org1")}
+	if err = v.evaluatePolicies(stub, envBytes, "mycc", presp, nil, unsatisfied, pProvider); err != nil {
+		t.Fatalf("evaluatePolicies failed, err %s", err)
+	}
+
+	qe.metadata["mycc"]["asset2"] = map[string][]byte{shim.ValidationParameterKey: []byte("org2")}
+	if err = v.evaluatePolicies(stub, envBytes, "mycc", presp, nil, satisfied, pProvider); err == nil {
+		t.Fatalf("evaluatePolicies should have failed for an unsatisfied key-level endorsement policy")
+	}
+
+	qe.metadata["mycc"]["asset2"] = map[string][]byte{shim.ValidationParameterKey: []byte("garbage")}
+	if err = v.evaluatePolicies(stub, envBytes, "mycc", presp, nil, satisfied, pProvider); err == nil {
+		t.Fatalf("evaluatePolicies should have failed for an invalid key-level endorsement policy")
+	}
+
+	if qe.done {
+		t.Fatalf("evaluatePolicies should not have released the tx simulator of the validator")
+	}
+}
+
+// mockApplication is the application config of a channel requiring its capabilities
+type mockApplication struct {
+	capabilities map[string]bool
+}
+
+func (ac *mockApplication) Organizations() map[string]config.ApplicationOrg {
+	return nil
+}
+
+func (ac *mockApplication) HasCapability(name string) bool {
+	return ac.capabilities[name]
+}
+
+func TestLegacyIdentityCapability(t *testing.T) {
+	// the package data recorded by the peers predating the current chaincode identity
+	codeHash := sha256.New().Sum([]byte("code"))
+	metaDataHash := sha256.Sum256([]byte("mycc" + "1"))
+	data, err := proto.Marshal(&ccprovider.CDSData{CodeHash: codeHash, MetaDataHash: metaDataHash[:]})
+	if err != nil {
+		t.Fatalf("Marshal failed, err %s", err)
+	}
+	h := sha256.New()
+	h.Write(codeHash)
+	h.Write(metaDataHash[:])
+	cdbytes, err := proto.Marshal(&ccprovider.ChaincodeData{Name: "mycc", Version: "1", Data: data, Id: h.Sum(nil)})
+	if err != nil {
+		t.Fatalf("Marshal failed, err %s", err)
+	}
+
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToWriteSet("lscc", "mycc", cdbytes)
+	results, err := rwsetBuilder.GetTxReadWriteSet().ToProtoBytes()
+	if err != nil {
+		t.Fatalf("ToProtoBytes failed, err %s", err)
+	}
+	prespBytes, err := utils.GetBytesProposalResponsePayload([]byte("hash"), &peer.Response{Status: 200}, results, nil, nil)
+	if err != nil {
+		t.Fatalf("GetBytesProposalResponsePayload failed, err %s", err)
+	}
+
+	cds := &peer.ChaincodeDeploymentSpec{ChaincodeSpec: &peer.ChaincodeSpec{Type: 1, ChaincodeId: &peer.ChaincodeID{Name: "mycc", Version: "1"}}}
+	cis := &peer.ChaincodeInvocationSpec{ChaincodeSpec: &peer.ChaincodeSpec{Input: &peer.ChaincodeInput{Args: [][]byte{[]byte("deploy"), []byte("mychannel"), utils.MarshalOrPanic(cds)}}}}
+	cap := &peer.ChaincodeActionPayload{
+		ChaincodeProposalPayload: utils.MarshalOrPanic(&peer.ChaincodeProposalPayload{Input: utils.MarshalOrPanic(cis)}),
+		Action:                   &peer.ChaincodeEndorsedAction{ProposalResponsePayload: prespBytes},
+	}
+
+	apps := map[string]config.Application{"mychannel": &mockApplication{}}
+	sysccprovider.RegisterSystemChaincodeProviderFactory(&mockSccProviderFactory{apps: apps})
+	v := new(ValidatorOneValidSignature)
+
+	if err = v.ValidateLSCCInvocation("mychannel", cap, nil); err != nil {
+		t.Fatalf("ValidateLSCCInvocation should have accepted the legacy package data, err %s", err)
+	}
+
+	apps["mychannel"] = &mockApplication{capabilities: map[string]bool{config.ChaincodeIdentityCapability: true}}
+	if err = v.ValidateLSCCInvocation("mychannel", cap, nil); err == nil {
+		t.Fatalf("ValidateLSCCInvocation should have rejected the legacy package data once the channel requires the current one")
+	}
+}
+
+func TestCanonicalEncodingCapability(t *testing.T) {
+	tx, err := createTx()
+	if err != nil {
+		t.Fatalf("createTx returned err %s", err)
+	}
+	// an empty header field leaves the payload unchanged once unmarshaled,
+	// but is not part of its canonical encoding
+	tx.Payload = append(tx.Payload, 0x0a, 0x00)
+	if tx.Signature, err = id.Sign(tx.Payload); err != nil {
+		t.Fatalf("Sign returned err %s", err)
+	}
+	envBytes, err := utils.GetBytesEnvelope(tx)
+	if err != nil {
+		t.Fatalf("GetBytesEnvelope returned err %s", err)
+	}
+	policy, err := getSignedByMSPMemberPolicy(mspid)
+	if err != nil {
+		t.Fatalf("failed getting policy, err %s", err)
+	}
+
+	apps := map[string]config.Application{chainId: &mockApplication{}}
+	sysccprovider.RegisterSystemChaincodeProviderFactory(&mockSccProviderFactory{apps: apps})
+	stub := shim.NewMockStub("validatoronevalidsignature", new(ValidatorOneValidSignature))
+	args := [][]byte{[]byte("dv"), envBytes, policy}
+
+	if res := stub.MockInvoke("1", args); res.Status != shim.OK {
+		t.Fatalf("vscc invoke should have accepted the non-canonical payload, err %s", res.Message)
+	}
+
+	apps[chainId] = &mockApplication{capabilities: map[string]bool{config.CanonicalEncodingCapability: true}}
+	if res := stub.MockInvoke("1", args); res.Status == shim.OK {
+		t.Fatalf("vscc invoke should have rejected the non-canonical payload once the channel requires canonical encoding")
+	}
+}
+
 var id msp.SigningIdentity
 var sid []byte
 var mspid string
@@ -170,5 +399,8 @@ func TestMain(m *testing.M) {
 		os.Exit(-1)
 	}
 
+	// channels without application config require no capabilities
+	sysccprovider.RegisterSystemChaincodeProviderFactory(&mockSccProviderFactory{})
+
 	os.Exit(m.Run())
 }
@@ -116,7 +116,7 @@ func createTestBlock(t *testing.T) *common.Block {
 	if err != nil {
 		t.Fatalf("Failure while marshalling the ProposalResponsePayload")
 	}
-	ccaPayload.Action.ProposalResponsePayload, err = utils.GetBytesProposalResponsePayload(pHashBytes, pResponse, results, eventBytes)
+	ccaPayload.Action.ProposalResponsePayload, err = utils.GetBytesProposalResponsePayload(pHashBytes, pResponse, results, eventBytes, nil)
 	if err != nil {
 		t.Fatalf("Failure while marshalling the ProposalResponsePayload")
 	}
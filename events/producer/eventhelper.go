@@ -30,6 +30,8 @@ func SendProducerBlockEvent(block *common.Block) error {
 	defer logger.Debugf("Exit")
 	bevent := &common.Block{}
 	bevent.Header = block.Header
+	// the metadata carries the validation codes of the transactions along with,
+	// for those invalidated by MVCC validation, what conflicted (see util.GetMVCCConflicts)
 	bevent.Metadata = block.Metadata
 	bevent.Data = &common.BlockData{}
 	var channelId string
@@ -76,7 +78,7 @@ func SendProducerBlockEvent(block *common.Block) error {
 					// Dropping the read write set may cause issues for security and
 					// we will need to revist when event security is addressed
 					caPayload.Results = nil
-					chaincodeActionPayload.Action.ProposalResponsePayload, err = utils.GetBytesProposalResponsePayload(propRespPayload.ProposalHash, caPayload.Response, caPayload.Results, caPayload.Events)
+					chaincodeActionPayload.Action.ProposalResponsePayload, err = utils.GetBytesProposalResponsePayload(propRespPayload.ProposalHash, caPayload.Response, caPayload.Results, caPayload.Events, caPayload.ChaincodeId)
 					if err != nil {
 						return fmt.Errorf("error marshalling tx proposal payload for block event: %s", err)
 					}
@@ -21,6 +21,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hyperledger/fabric/core/quota"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -203,6 +204,14 @@ type eventProcessor struct {
 	//if 0, if buffer full, will block and guarantee the event will be sent out
 	//if > 0, if buffer full, blocks till timeout
 	timeout int
+
+	//bounds the number of handlers with interests for each chain
+	subscribers *quota.Limiter
+
+	//number of events queued for each consumer, and how long sending one
+	//of them may take, before the consumer is evicted
+	sendBuffer  int
+	sendTimeout time.Duration
 }
 
 //global eventProcessor singleton created by initializeEvents. Openchain producers
@@ -236,12 +245,13 @@ func (ep *eventProcessor) start() {
 }
 
 //initialize and start
-func initializeEvents(bufferSize uint, tout int) {
+func initializeEvents(bufferSize uint, tout int, sendBuffer int, sendTimeout time.Duration) {
 	if gEventProcessor != nil {
 		panic("should not be called twice")
 	}
 
-	gEventProcessor = &eventProcessor{eventConsumers: make(map[pb.EventType]handlerList), eventChannel: make(chan *pb.Event, bufferSize), timeout: tout}
+	gEventProcessor = &eventProcessor{eventConsumers: make(map[pb.EventType]handlerList), eventChannel: make(chan *pb.Event, bufferSize), timeout: tout,
+		subscribers: quota.NewLimiter("event subscribers", quota.EventSubscriberLimit()), sendBuffer: sendBuffer, sendTimeout: sendTimeout}
 
 	addInternalEventTypes()
 
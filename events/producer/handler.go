@@ -19,8 +19,12 @@ package producer
 import (
 	"fmt"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 
 	"github.com/hyperledger/fabric/msp/mgmt"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -29,23 +33,100 @@ import (
 type handler struct {
 	ChatStream       pb.Events_ChatServer
 	interestedEvents map[string]*pb.Interest
+	// number of registered interests for each chain, the handler counts
+	// as one subscriber of a chain while it has interests for it
+	chainInterests map[string]int
+
+	// events waiting to be sent to the consumer, by a single goroutine
+	sendQueue chan *pb.Event
+	// how long sending an event to the consumer may take
+	sendTimeout time.Duration
+	// closed when the consumer is evicted, evictErr is the status the
+	// stream ends with
+	evicted   chan struct{}
+	evictOnce sync.Once
+	evictErr  error
+	// closed when the handler stops
+	done     chan struct{}
+	stopOnce sync.Once
 }
 
 func newEventHandler(stream pb.Events_ChatServer) (*handler, error) {
+	sendBuffer, sendTimeout := defaultSendBuffer, defaultTimeout
+	if gEventProcessor != nil && gEventProcessor.sendBuffer > 0 {
+		sendBuffer, sendTimeout = gEventProcessor.sendBuffer, gEventProcessor.sendTimeout
+	}
 	d := &handler{
-		ChatStream: stream,
+		ChatStream:  stream,
+		sendQueue:   make(chan *pb.Event, sendBuffer),
+		sendTimeout: sendTimeout,
+		evicted:     make(chan struct{}),
+		done:        make(chan struct{}),
 	}
 	d.interestedEvents = make(map[string]*pb.Interest)
+	d.chainInterests = make(map[string]int)
 	return d, nil
 }
 
+// Start starts sending the queued events to the consumer
+func (d *handler) Start() {
+	go d.sendLoop()
+}
+
 // Stop stops this handler
 func (d *handler) Stop() error {
+	d.stopOnce.Do(func() { close(d.done) })
 	d.deregisterAll()
 	d.interestedEvents = nil
 	return nil
 }
 
+// sendLoop sends the queued events to the consumer until the handler stops,
+// the consumer is evicted if sending one of them takes longer than the send
+// timeout, which ends its stream and so unblocks the send
+func (d *handler) sendLoop() {
+	for {
+		select {
+		case msg := <-d.sendQueue:
+			timer := time.AfterFunc(d.sendTimeout, func() {
+				d.evict(evictedSendTimeout, fmt.Sprintf("did not receive an event within %s", d.sendTimeout))
+			})
+			err := d.ChatStream.Send(msg)
+			timer.Stop()
+			if err != nil {
+				logger.Errorf("Error sending message through ChatStream: %s", err)
+				return
+			}
+		case <-d.evicted:
+			return
+		case <-d.done:
+			return
+		}
+	}
+}
+
+// evict disconnects the consumer, which does not keep up with the events,
+// with a RESOURCE_EXHAUSTED status
+func (d *handler) evict(reason, detail string) {
+	d.evictOnce.Do(func() {
+		logger.Warningf("Evicting slow event consumer: %s", detail)
+		evictions.Add(reason, 1)
+		d.evictErr = grpc.Errorf(codes.ResourceExhausted, "slow event consumer evicted: %s", detail)
+		close(d.evicted)
+	})
+}
+
+// Evicted returns a channel closed when the consumer is evicted
+func (d *handler) Evicted() <-chan struct{} {
+	return d.evicted
+}
+
+// EvictionError returns the status the stream of an evicted consumer ends with
+func (d *handler) EvictionError() error {
+	<-d.evicted
+	return d.evictErr
+}
+
 func getInterestKey(interest pb.Interest) string {
 	var key string
 	switch interest.EventType {
@@ -66,22 +147,44 @@ func (d *handler) register(iMsg []*pb.Interest) error {
 	// Could consider passing interest array to registerHandler
 	// and only lock once for entire array here
 	for _, v := range iMsg {
+		if d.chainInterests[v.ChainID] == 0 {
+			if err := gEventProcessor.subscribers.Acquire(v.ChainID); err != nil {
+				return err
+			}
+		}
 		if err := registerHandler(v, d); err != nil {
 			logger.Errorf("could not register %s: %s", v, err)
+			if d.chainInterests[v.ChainID] == 0 {
+				gEventProcessor.subscribers.Release(v.ChainID)
+			}
 			continue
 		}
 		d.interestedEvents[getInterestKey(*v)] = v
+		d.chainInterests[v.ChainID]++
 	}
 
 	return nil
 }
 
+// forgetInterest releases the subscription of the handler to the chain of
+// the interest once it has no interest left for the chain
+func (d *handler) forgetInterest(ie *pb.Interest) {
+	d.chainInterests[ie.ChainID]--
+	if d.chainInterests[ie.ChainID] <= 0 {
+		delete(d.chainInterests, ie.ChainID)
+		gEventProcessor.subscribers.Release(ie.ChainID)
+	}
+}
+
 func (d *handler) deregister(iMsg []*pb.Interest) error {
 	for _, v := range iMsg {
 		if err := deRegisterHandler(v, d); err != nil {
 			logger.Errorf("could not deregister %s", v)
 			continue
 		}
+		if registered, ok := d.interestedEvents[getInterestKey(*v)]; ok {
+			d.forgetInterest(registered)
+		}
 		delete(d.interestedEvents, getInterestKey(*v))
 	}
 	return nil
@@ -93,6 +196,7 @@ func (d *handler) deregisterAll() {
 			logger.Errorf("could not deregister %s", v)
 			continue
 		}
+		d.forgetInterest(v)
 		delete(d.interestedEvents, k)
 	}
 }
@@ -120,20 +224,30 @@ func (d *handler) HandleMessage(msg *pb.SignedEvent) error {
 		return fmt.Errorf("invalide type from client %T", evt.Event)
 	}
 	//TODO return supported events.. for now just return the received msg
-	if err := d.ChatStream.Send(evt); err != nil {
+	if err := d.SendMessage(evt); err != nil {
 		return fmt.Errorf("error sending response to %v:  %s", msg, err)
 	}
 
 	return nil
 }
 
-// SendMessage sends a message to the remote PEER through the stream
+// SendMessage queues a message to send to the remote PEER through the stream.
+// The consumer is evicted if its send buffer is full, rather than blocking the
+// delivery of the events to the other consumers.
 func (d *handler) SendMessage(msg *pb.Event) error {
-	err := d.ChatStream.Send(msg)
-	if err != nil {
-		return fmt.Errorf("error Sending message through ChatStream: %s", err)
+	select {
+	case <-d.evicted:
+		return fmt.Errorf("error Sending message through ChatStream: consumer evicted")
+	default:
+	}
+
+	select {
+	case d.sendQueue <- msg:
+		return nil
+	default:
+		d.evict(evictedBufferFull, fmt.Sprintf("%d events are waiting to be sent", cap(d.sendQueue)))
+		return fmt.Errorf("error Sending message through ChatStream: send buffer full")
 	}
-	return nil
 }
 
 // Validates event messages by validating the Creator and verifying
@@ -0,0 +1,34 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package producer
+
+import "expvar"
+
+const (
+	// evictedBufferFull is the reason of the eviction of a consumer whose
+	// send buffer is full of events it did not receive yet
+	evictedBufferFull = "buffer_full"
+	// evictedSendTimeout is the reason of the eviction of a consumer which
+	// did not receive an event within the send timeout
+	evictedSendTimeout = "send_timeout"
+)
+
+// evictions counts the event consumers disconnected because they did not
+// keep up with the events, by reason, e.g. {"buffer_full": 2,
+// "send_timeout": 1}. It is published with the other expvar variables on
+// /debug/vars of the profiling server of the peer
+var evictions = expvar.NewMap("events_consumer_evictions")
@@ -25,7 +25,12 @@ import (
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
-const defaultTimeout = time.Second * 3
+const (
+	// defaultTimeout is the default time sending an event to a consumer may take
+	defaultTimeout = time.Second * 3
+	// defaultSendBuffer is the default number of events queued for a consumer
+	defaultSendBuffer = 100
+)
 
 var logger = flogging.MustGetLogger("eventhub_producer")
 
@@ -38,11 +43,24 @@ var globalEventsServer *EventsServer
 
 // NewEventsServer returns a EventsServer
 func NewEventsServer(bufferSize uint, timeout int) *EventsServer {
+	return NewEventsServerWithFlowControl(bufferSize, timeout, defaultSendBuffer, defaultTimeout)
+}
+
+// NewEventsServerWithFlowControl returns a EventsServer which queues at most
+// sendBuffer events for each consumer, and evicts the consumers whose queue is
+// full or which do not receive an event within sendTimeout
+func NewEventsServerWithFlowControl(bufferSize uint, timeout int, sendBuffer int, sendTimeout time.Duration) *EventsServer {
 	if globalEventsServer != nil {
 		panic("Cannot create multiple event hub servers")
 	}
+	if sendBuffer <= 0 {
+		sendBuffer = defaultSendBuffer
+	}
+	if sendTimeout <= 0 {
+		sendTimeout = defaultTimeout
+	}
 	globalEventsServer = new(EventsServer)
-	initializeEvents(bufferSize, timeout)
+	initializeEvents(bufferSize, timeout, sendBuffer, sendTimeout)
 	//initializeCCEventProcessor(bufferSize, timeout)
 	return globalEventsServer
 }
@@ -53,19 +71,47 @@ func (p *EventsServer) Chat(stream pb.Events_ChatServer) error {
 	if err != nil {
 		return fmt.Errorf("error creating handler during handleChat initiation: %s", err)
 	}
+	handler.Start()
 	defer handler.Stop()
+
+	// receive in a separate goroutine, to end the stream as soon as the
+	// consumer is evicted
+	type received struct {
+		msg *pb.SignedEvent
+		err error
+	}
+	recvChan := make(chan received)
+	go func() {
+		for {
+			in, err := stream.Recv()
+			select {
+			case recvChan <- received{msg: in, err: err}:
+			case <-handler.Evicted():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
 	for {
-		in, err := stream.Recv()
-		if err == io.EOF {
+		var in received
+		select {
+		case in = <-recvChan:
+		case <-handler.Evicted():
+			return handler.EvictionError()
+		}
+		if in.err == io.EOF {
 			logger.Debug("Received EOF, ending Chat")
 			return nil
 		}
-		if err != nil {
-			e := fmt.Errorf("error during Chat, stopping handler: %s", err)
+		if in.err != nil {
+			e := fmt.Errorf("error during Chat, stopping handler: %s", in.err)
 			logger.Error(e.Error())
 			return e
 		}
-		err = handler.HandleMessage(in)
+		err = handler.HandleMessage(in.msg)
 		if err != nil {
 			logger.Errorf("Error handling message: %s", err)
 			return err
@@ -17,18 +17,25 @@ limitations under the License.
 package producer
 
 import (
+	"expvar"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/quota"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
+	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 func createEvent() (*peer.Event, error) {
@@ -117,6 +124,137 @@ func TestSignedEvent(t *testing.T) {
 	}
 }
 
+func TestSubscriberQuota(t *testing.T) {
+	defer func() { gEventProcessor = nil }()
+	gEventProcessor = &eventProcessor{
+		eventConsumers: make(map[peer.EventType]handlerList),
+		subscribers:    quota.NewLimiter("event subscribers", 1),
+	}
+	addInternalEventTypes()
+
+	blockInterest := &peer.Interest{EventType: peer.EventType_BLOCK, ChainID: "a"}
+	rejectionInterest := &peer.Interest{EventType: peer.EventType_REJECTION, ChainID: "a"}
+
+	h1, _ := newEventHandler(nil)
+	assert.NoError(t, h1.register([]*peer.Interest{blockInterest, rejectionInterest}), "Should have counted the handler once for its interests")
+	h2, _ := newEventHandler(nil)
+	assert.Error(t, h2.register([]*peer.Interest{blockInterest}), "Should have denied a second subscriber of the chain")
+	assert.NoError(t, h2.register([]*peer.Interest{{EventType: peer.EventType_BLOCK, ChainID: "b"}}), "Should not have limited another chain")
+
+	h1.deregister([]*peer.Interest{blockInterest})
+	assert.Error(t, h2.register([]*peer.Interest{rejectionInterest}), "Should still count the handler with an interest left")
+	h1.Stop()
+	assert.NoError(t, h2.register([]*peer.Interest{rejectionInterest}), "Should have released the subscription of the stopped handler")
+	assert.Equal(t, 1, gEventProcessor.subscribers.InUse("a"))
+}
+
+// mockChatStream is a Chat stream whose sends block until unblock is closed
+type mockChatStream struct {
+	grpc.ServerStream
+	unblock chan struct{}
+	sent    chan *peer.Event
+}
+
+func newMockChatStream(blocking bool) *mockChatStream {
+	s := &mockChatStream{unblock: make(chan struct{}), sent: make(chan *peer.Event, 10)}
+	if !blocking {
+		close(s.unblock)
+	}
+	return s
+}
+
+func (s *mockChatStream) Send(evt *peer.Event) error {
+	<-s.unblock
+	s.sent <- evt
+	return nil
+}
+
+func (s *mockChatStream) Recv() (*peer.SignedEvent, error) {
+	<-s.unblock
+	return nil, io.EOF
+}
+
+func evictionCount(reason string) int64 {
+	if v, ok := evictions.Get(reason).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func blockEvent() *peer.Event {
+	return &peer.Event{Event: &peer.Event_Block{Block: &common.Block{}}}
+}
+
+func TestEvictSlowConsumerBufferFull(t *testing.T) {
+	defer func() { gEventProcessor = nil }()
+	gEventProcessor = &eventProcessor{
+		eventConsumers: make(map[peer.EventType]handlerList),
+		sendBuffer:     1,
+		sendTimeout:    time.Hour,
+	}
+	addInternalEventTypes()
+	evicted := evictionCount(evictedBufferFull)
+
+	slowStream, fastStream := newMockChatStream(true), newMockChatStream(false)
+	defer close(slowStream.unblock)
+	slow, _ := newEventHandler(slowStream)
+	fast, _ := newEventHandler(fastStream)
+	fast.Start()
+	defer fast.Stop()
+	interest := []*peer.Interest{{EventType: peer.EventType_BLOCK}}
+	assert.NoError(t, slow.register(interest))
+	assert.NoError(t, fast.register(interest))
+
+	hl := gEventProcessor.eventConsumers[peer.EventType_BLOCK]
+	for i := 0; i < 2; i++ {
+		hl.foreach(blockEvent(), func(h *handler) { h.SendMessage(blockEvent()) })
+		select {
+		case <-fastStream.sent:
+		case <-time.After(time.Second):
+			t.Fatalf("Should have delivered the events to the other consumer")
+		}
+	}
+
+	select {
+	case <-slow.Evicted():
+	default:
+		t.Fatalf("Should have evicted the consumer whose send buffer is full")
+	}
+	assert.Equal(t, codes.ResourceExhausted, grpc.Code(slow.EvictionError()))
+	assert.Equal(t, evicted+1, evictionCount(evictedBufferFull))
+	assert.Error(t, slow.SendMessage(blockEvent()), "Should not queue events for an evicted consumer")
+	select {
+	case <-fast.Evicted():
+		t.Fatalf("Should not have evicted the consumer which keeps up")
+	default:
+	}
+}
+
+func TestEvictSlowConsumerSendTimeout(t *testing.T) {
+	defer func() { gEventProcessor = nil }()
+	gEventProcessor = &eventProcessor{
+		eventConsumers: make(map[peer.EventType]handlerList),
+		sendBuffer:     10,
+		sendTimeout:    10 * time.Millisecond,
+	}
+	evicted := evictionCount(evictedSendTimeout)
+
+	stream := newMockChatStream(true)
+	defer close(stream.unblock)
+	h, _ := newEventHandler(stream)
+	h.Start()
+	defer h.Stop()
+
+	assert.NoError(t, h.SendMessage(blockEvent()))
+	select {
+	case <-h.Evicted():
+	case <-time.After(time.Second):
+		t.Fatalf("Should have evicted the consumer which does not receive the event")
+	}
+	assert.Equal(t, codes.ResourceExhausted, grpc.Code(h.EvictionError()))
+	assert.Equal(t, evicted+1, evictionCount(evictedSendTimeout))
+}
+
 var signer msp.SigningIdentity
 var signerSerialized []byte
 
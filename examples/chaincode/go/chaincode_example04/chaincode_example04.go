@@ -134,7 +134,7 @@ func (t *SimpleChaincode) query(stub shim.ChaincodeStubInterface, args []string)
 		invokeArgs := util.ToChaincodeArgs(f, queryKey)
 		response := stub.InvokeChaincode(chainCodeToCall, invokeArgs, channel)
 		if response.Status != shim.OK {
-			errStr := fmt.Sprintf("Failed to invoke chaincode. Got error: %s", err.Error())
+			errStr := fmt.Sprintf("Failed to invoke chaincode. Got error: %s", string(response.Payload))
 			fmt.Printf(errStr)
 			return shim.Error(errStr)
 		}
@@ -22,7 +22,6 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/events/consumer"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -154,7 +153,11 @@ func main() {
 			fmt.Println("")
 			fmt.Println("Received block")
 			fmt.Println("--------------")
-			txsFltr := util.TxValidationFlags(b.Block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+			codes, err := utils.GetTxValidationCodesFromBlock(b.Block)
+			if err != nil {
+				fmt.Printf("Error extracting the validation codes: %s\n", err)
+				return
+			}
 			for i, r := range b.Block.Data.Data {
 				tx, _ := getTxPayload(r)
 				if tx != nil {
@@ -163,7 +166,7 @@ func main() {
 						fmt.Print("Error extracting channel header\n")
 						return
 					}
-					if txsFltr.IsInvalid(i) {
+					if codes[i] != pb.TxValidationCode_VALID {
 						fmt.Println("")
 						fmt.Println("")
 						fmt.Printf("Received invalid transaction from channel %s\n", chdr.ChannelId)
@@ -31,6 +31,10 @@ type Comm interface {
 	// GetPKIid returns this instance's PKI id
 	GetPKIid() common.PKIidType
 
+	// GetTLSCertHash returns the hash of this instance's TLS certificate,
+	// or nil if TLS isn't used
+	GetTLSCertHash() []byte
+
 	// Send sends a message to remote peers
 	Send(msg *proto.SignedGossipMessage, peers ...*RemotePeer)
 
@@ -381,6 +381,10 @@ func (c *commImpl) GetPKIid() common.PKIidType {
 	return c.PKIID
 }
 
+func (c *commImpl) GetTLSCertHash() []byte {
+	return c.selfCertHash
+}
+
 func extractRemoteAddress(stream stream) string {
 	var remoteAddress string
 	p, ok := peer.FromContext(stream.Context())
@@ -464,6 +468,7 @@ func (c *commImpl) authenticateRemotePeer(stream stream) (*proto.ConnectionInfo,
 			Signature:  m.Signature,
 			SignedData: m.Payload,
 		}
+		connInfo.TLSCertHash = remoteCertHash
 	}
 
 	// TLS enabled but not detected on other side, and we're not configured to skip handshake verification
@@ -299,6 +299,15 @@ func TestGetConnectionInfo(t *testing.T) {
 	}
 }
 
+func TestMsgType(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "DataMsg", msgType(createGossipMsg()))
+	assert.Equal(t, "AliveMsg", msgType((&proto.GossipMessage{
+		Content: &proto.GossipMessage_AliveMsg{AliveMsg: &proto.AliveMessage{}},
+	}).NoopSign()))
+	assert.Equal(t, "Unknown", msgType(&proto.SignedGossipMessage{GossipMessage: &proto.GossipMessage{}}))
+}
+
 func TestCloseConn(t *testing.T) {
 	t.Parallel()
 	comm1, _ := newCommInstance(1611, naiveSec)
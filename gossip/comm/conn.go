@@ -254,6 +254,7 @@ func (conn *connection) send(msg *proto.SignedGossipMessage, onErr func(error))
 	defer conn.Unlock()
 
 	if len(conn.outBuff) == util.GetIntOrDefault("peer.gossip.sendBuffSize", defSendBuffSize) {
+		sendOverflows.Add(1)
 		go onErr(errSendOverflow)
 		return
 	}
@@ -264,6 +265,7 @@ func (conn *connection) send(msg *proto.SignedGossipMessage, onErr func(error))
 	}
 
 	conn.outBuff <- m
+	sentMsgs.Add(msgType(msg), 1)
 }
 
 func (conn *connection) serviceConnection() error {
@@ -289,6 +291,7 @@ func (conn *connection) serviceConnection() error {
 		case err := <-errChan:
 			return err
 		case msg := <-msgChan:
+			receivedMsgs.Add(msgType(msg), 1)
 			conn.handler(msg)
 		}
 	}
@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package comm
+
+import (
+	"expvar"
+	"reflect"
+	"strings"
+
+	proto "github.com/hyperledger/fabric/protos/gossip"
+)
+
+// sentMsgs and receivedMsgs count the gossip messages sent to and received
+// from remote peers by message type, e.g. {"AliveMsg": 12, "DataMsg": 3}.
+// sendOverflows counts the messages dropped because the send buffer of the
+// connection to the remote peer was full. They are published with the other
+// expvar variables on /debug/vars of the profiling server of the peer
+var (
+	sentMsgs      = expvar.NewMap("gossip_comm_sent_messages")
+	receivedMsgs  = expvar.NewMap("gossip_comm_received_messages")
+	sendOverflows = expvar.NewInt("gossip_comm_send_buffer_overflows")
+)
+
+// msgType returns the name of the type of content of a gossip message
+func msgType(m *proto.SignedGossipMessage) string {
+	if m == nil || m.GossipMessage == nil || m.Content == nil {
+		return "Unknown"
+	}
+	return strings.TrimPrefix(reflect.TypeOf(m.Content).Elem().Name(), "GossipMessage_")
+}
@@ -141,6 +141,11 @@ func (mock *commMock) GetPKIid() common.PKIidType {
 	return common.PKIidType(mock.id)
 }
 
+// GetTLSCertHash returns nil, the mock doesn't use TLS
+func (mock *commMock) GetTLSCertHash() []byte {
+	return nil
+}
+
 // Send sends a message to remote peers
 func (mock *commMock) Send(msg *proto.SignedGossipMessage, peers ...*comm.RemotePeer) {
 	for _, peer := range peers {
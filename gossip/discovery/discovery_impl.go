@@ -131,6 +131,7 @@ func NewDiscoveryService(bootstrapPeers []string, self NetworkMember, comm CommS
 		delete(d.id2Member, string(id))
 		delete(d.deadLastTS, string(id))
 		delete(d.aliveLastTS, string(id))
+		d.recordMembership()
 	}
 
 	d.msgStore = msgstore.NewMessageStoreExpirable(policy, trigger, aliveMsgTTL, externalLock, externalUnlock, callback)
@@ -570,6 +571,8 @@ func (d *gossipDiscoveryImpl) resurrectMember(am *proto.SignedGossipMessage, t p
 	delete(d.deadLastTS, string(pkiID))
 	d.deadMembership.Remove(common.PKIidType(pkiID))
 	d.aliveMembership.Put(common.PKIidType(pkiID), &proto.SignedGossipMessage{GossipMessage: am.GossipMessage, Envelope: am.Envelope})
+	resurrectedMembers.Add(1)
+	d.recordMembership()
 }
 
 func (d *gossipDiscoveryImpl) periodicalReconnectToDead() {
@@ -677,7 +680,9 @@ func (d *gossipDiscoveryImpl) expireDeadMembers(dead []common.PKIidType) {
 			d.deadMembership.Put(pkiID, am)
 			d.aliveMembership.Remove(pkiID)
 		}
+		expiredMembers.Add(1)
 	}
+	d.recordMembership()
 
 	d.lock.Unlock()
 
@@ -845,6 +850,7 @@ func (d *gossipDiscoveryImpl) learnNewMembers(aliveMembers []*proto.SignedGossip
 		d.deadMembership.Put(dm.GetAliveMsg().Membership.PkiId, &proto.SignedGossipMessage{GossipMessage: dm.GossipMessage, Envelope: dm.Envelope})
 		d.logger.Debugf("Learned about a new dead member: %v", dm)
 	}
+	d.recordMembership()
 
 	// update the member in any case
 	for _, a := range [][]*proto.SignedGossipMessage{aliveMembers, deadMembers} {
@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import "expvar"
+
+// membership describes the view of the membership of the peer: the number of
+// alive and dead members it currently knows, and the number of times members
+// were declared dead and were resurrected, which grow fast when the membership
+// flaps. It is published with the other expvar variables on /debug/vars of the
+// profiling server of the peer
+var membership = expvar.NewMap("gossip_membership")
+
+var (
+	aliveMembers       = new(expvar.Int)
+	deadMembers        = new(expvar.Int)
+	expiredMembers     = new(expvar.Int)
+	resurrectedMembers = new(expvar.Int)
+)
+
+func init() {
+	membership.Set("alive", aliveMembers)
+	membership.Set("dead", deadMembers)
+	membership.Set("expired", expiredMembers)
+	membership.Set("resurrected", resurrectedMembers)
+}
+
+// recordMembership updates the number of alive and dead members,
+// it must be called with the lock of the discovery held
+func (d *gossipDiscoveryImpl) recordMembership() {
+	aliveMembers.Set(int64(len(d.aliveLastTS)))
+	deadMembers.Set(int64(len(d.deadLastTS)))
+}
@@ -25,6 +25,9 @@ import (
 
 type emitBatchCallback func([]interface{})
 
+// iterationsPolicy returns the number of times a message is forwarded
+type iterationsPolicy func(interface{}) int
+
 //batchingEmitter is used for the gossip push/forwarding phase.
 // Messages are added into the batchingEmitter, and they are forwarded periodically T times in batches and then discarded.
 // If the batchingEmitter's stored message count reaches a certain capacity, that also triggers a message dispatch
@@ -45,18 +48,31 @@ type batchingEmitter interface {
 // latency: the maximum delay that each message can be stored without being forwarded
 // cb: a callback that is called in order for the forwarding to take place
 func newBatchingEmitter(iterations, burstSize int, latency time.Duration, cb emitBatchCallback) batchingEmitter {
+	return newBatchingEmitterWithPolicy(iterations, nil, burstSize, latency, cb)
+}
+
+// newBatchingEmitterWithPolicy is like newBatchingEmitter, but the number of
+// times each message is forwarded is decided by iterationsOf.
+// Setting iterations to 0 still disables forwarding altogether.
+func newBatchingEmitterWithPolicy(iterations int, iterationsOf iterationsPolicy, burstSize int, latency time.Duration, cb emitBatchCallback) batchingEmitter {
 	if iterations < 0 {
 		panic(fmt.Errorf("Got a negative iterations number"))
 	}
+	if iterationsOf == nil {
+		iterationsOf = func(interface{}) int {
+			return iterations
+		}
+	}
 
 	p := &batchingEmitterImpl{
-		cb:         cb,
-		delay:      latency,
-		iterations: iterations,
-		burstSize:  burstSize,
-		lock:       &sync.Mutex{},
-		buff:       make([]*batchedMessage, 0),
-		stopFlag:   int32(0),
+		cb:           cb,
+		delay:        latency,
+		iterations:   iterations,
+		iterationsOf: iterationsOf,
+		burstSize:    burstSize,
+		lock:         &sync.Mutex{},
+		buff:         make([]*batchedMessage, 0),
+		stopFlag:     int32(0),
 	}
 
 	if iterations != 0 {
@@ -109,13 +125,14 @@ func (p *batchingEmitterImpl) toDie() bool {
 }
 
 type batchingEmitterImpl struct {
-	iterations int
-	burstSize  int
-	delay      time.Duration
-	cb         emitBatchCallback
-	lock       *sync.Mutex
-	buff       []*batchedMessage
-	stopFlag   int32
+	iterations   int
+	iterationsOf iterationsPolicy
+	burstSize    int
+	delay        time.Duration
+	cb           emitBatchCallback
+	lock         *sync.Mutex
+	buff         []*batchedMessage
+	stopFlag     int32
 }
 
 type batchedMessage struct {
@@ -137,10 +154,14 @@ func (p *batchingEmitterImpl) Add(message interface{}) {
 	if p.iterations == 0 {
 		return
 	}
+	iterations := p.iterationsOf(message)
+	if iterations <= 0 {
+		return
+	}
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
-	p.buff = append(p.buff, &batchedMessage{data: message, iterationsLeft: p.iterations})
+	p.buff = append(p.buff, &batchedMessage{data: message, iterationsLeft: iterations})
 
 	if len(p.buff) >= p.burstSize {
 		p.emit()
@@ -71,6 +71,37 @@ func TestBatchingEmitterExpiration(t *testing.T) {
 	assert.Equal(t, 0, emitter.Size())
 }
 
+func TestBatchingEmitterIterationsPolicy(t *testing.T) {
+	// In this test we make sure each message is forwarded the amount of times its policy dictates
+	var lock sync.Mutex
+	counters := make(map[int]int)
+	cb := func(a []interface{}) {
+		lock.Lock()
+		defer lock.Unlock()
+		for _, m := range a {
+			counters[m.(int)]++
+		}
+	}
+	policy := func(m interface{}) int {
+		return m.(int)
+	}
+
+	emitter := newBatchingEmitterWithPolicy(1, policy, 10, time.Duration(10)*time.Millisecond, cb)
+	defer emitter.Stop()
+
+	emitter.Add(0)
+	emitter.Add(2)
+	emitter.Add(5)
+	time.Sleep(time.Duration(500) * time.Millisecond)
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, 0, counters[0])
+	assert.Equal(t, 2, counters[2])
+	assert.Equal(t, 5, counters[5])
+	assert.Equal(t, 0, emitter.Size())
+}
+
 func TestBatchingEmitterCounter(t *testing.T) {
 	// In this test we count the number of times each message is forwarded, with relation to the time passed
 	counters := make(map[int]int)
@@ -130,14 +130,15 @@ type gossipAdapterImpl struct {
 }
 
 func (ga *gossipAdapterImpl) GetConf() channel.Config {
+	blockPullInterval := ga.conf.blockPropagation().PullInterval
 	return channel.Config{
 		ID:                          ga.conf.ID,
 		MaxBlockCountToStore:        ga.conf.MaxBlockCountToStore,
 		PublishStateInfoInterval:    ga.conf.PublishStateInfoInterval,
-		PullInterval:                ga.conf.PullInterval,
+		PullInterval:                blockPullInterval,
 		PullPeerNum:                 ga.conf.PullPeerNum,
-		RequestStateInfoInterval:    ga.conf.RequestStateInfoInterval,
-		BlockExpirationInterval:     ga.conf.PullInterval * 100,
+		RequestStateInfoInterval:    ga.conf.stateInfoPropagation().PullInterval,
+		BlockExpirationInterval:     blockPullInterval * 100,
 		StateInfoExpirationInterval: ga.conf.PublishStateInfoInterval * 100,
 	}
 }
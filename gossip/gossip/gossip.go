@@ -95,4 +95,65 @@ type Config struct {
 
 	InternalEndpoint string // Endpoint we publish to peers in our organization
 	ExternalEndpoint string // Peer publishes this endpoint instead of SelfEndpoint to foreign organizations
+
+	BlockPropagation      PropagationConfig // Dissemination of blocks
+	StateInfoPropagation  PropagationConfig // Dissemination of state info messages, pulled every RequestStateInfoInterval by default
+	LeadershipPropagation PropagationConfig // Dissemination of leadership messages, pushed to all peers of the channel by default and never pulled
+	IdentityPropagation   PropagationConfig // Dissemination of peer identities, which are only pulled
+}
+
+// PropagationConfig overrides the dissemination parameters of a class of messages.
+// Parameters left to zero fall back to the ones of Config
+type PropagationConfig struct {
+	PropagateIterations int           // Number of times a message of the class is pushed to remote peers
+	PropagatePeerNum    int           // Number of peers selected to push messages of the class to
+	PullInterval        time.Duration // Determines frequency of pull phases of the class
+}
+
+// resolve fills the parameters left unset in pc with the global ones
+func (c *Config) resolve(pc PropagationConfig, pullInterval time.Duration) PropagationConfig {
+	if pc.PropagateIterations == 0 {
+		pc.PropagateIterations = c.PropagateIterations
+	}
+	if pc.PropagatePeerNum == 0 {
+		pc.PropagatePeerNum = c.PropagatePeerNum
+	}
+	if pc.PullInterval == 0 {
+		pc.PullInterval = pullInterval
+	}
+	return pc
+}
+
+func (c *Config) blockPropagation() PropagationConfig {
+	return c.resolve(c.BlockPropagation, c.PullInterval)
+}
+
+func (c *Config) stateInfoPropagation() PropagationConfig {
+	return c.resolve(c.StateInfoPropagation, c.RequestStateInfoInterval)
+}
+
+// leadershipPropagation leaves PropagatePeerNum to zero if unset, since
+// leadership messages are sent to all peers of the channel by default
+func (c *Config) leadershipPropagation() PropagationConfig {
+	pc := c.resolve(c.LeadershipPropagation, 0)
+	pc.PropagatePeerNum = c.LeadershipPropagation.PropagatePeerNum
+	return pc
+}
+
+func (c *Config) identityPropagation() PropagationConfig {
+	return c.resolve(c.IdentityPropagation, c.PullInterval)
+}
+
+// propagationOf returns the dissemination parameters of the class of the message
+func (c *Config) propagationOf(msg *proto.SignedGossipMessage) PropagationConfig {
+	switch {
+	case msg.IsDataMsg():
+		return c.blockPropagation()
+	case msg.IsStateInfoMsg():
+		return c.stateInfoPropagation()
+	case msg.IsLeadershipMsg():
+		return c.leadershipPropagation()
+	default:
+		return c.resolve(PropagationConfig{}, c.PullInterval)
+	}
 }
@@ -21,7 +21,9 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"reflect"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -90,6 +92,13 @@ func NewGossipService(conf *Config, s *grpc.Server, secAdvisor api.SecurityAdvis
 		return nil
 	}
 
+	if conf.ExternalEndpoint != "" && !isValidEndpoint(conf.ExternalEndpoint) {
+		// Better not to be known outside of the organization than to
+		// publish an endpoint other organizations cannot use
+		lgr.Errorf("External endpoint %s isn't formatted as 'host:port', it will not be published", conf.ExternalEndpoint)
+		conf.ExternalEndpoint = ""
+	}
+
 	stateInfoExpirationInterval := conf.PublishStateInfoInterval * 100
 
 	g := &gossipServiceImpl{
@@ -112,7 +121,7 @@ func NewGossipService(conf *Config, s *grpc.Server, secAdvisor api.SecurityAdvis
 	}
 
 	g.chanState = newChannelState(g)
-	g.emitter = newBatchingEmitter(conf.PropagateIterations,
+	g.emitter = newBatchingEmitterWithPolicy(conf.PropagateIterations, g.propagateIterationsOf,
 		conf.MaxPropagationBurstSize, conf.MaxPropagationBurstLatency,
 		g.sendGossipBatch)
 
@@ -247,6 +256,7 @@ func (g *gossipServiceImpl) syncDiscovery() {
 	defer g.logger.Debug("Exiting discovery sync loop")
 	for !g.toDie() {
 		g.disc.InitiateSync(g.conf.PullPeerNum)
+		g.recordChannelPeers()
 		time.Sleep(g.conf.PullInterval)
 	}
 }
@@ -333,6 +343,10 @@ func (g *gossipServiceImpl) handleMessage(m proto.ReceivedMessage) {
 	}
 
 	if selectOnlyDiscoveryMessages(m) {
+		if msg.IsAliveMsg() && !validateAliveMsgTLSCertHash(msg, m.GetConnectionInfo()) {
+			g.logger.Warning("Got AliveMessage from", m.GetConnectionInfo().ID, "with a TLS certificate hash that doesn't match the connection")
+			return
+		}
 		// It's a membership request, check its self information
 		// matches the sender
 		if m.GetGossipMessage().GetMemReq() != nil {
@@ -349,6 +363,10 @@ func (g *gossipServiceImpl) handleMessage(m proto.ReceivedMessage) {
 				g.logger.Warning("Got membership request with selfInfo that doesn't match the handshake")
 				return
 			}
+			if !validateAliveMsgTLSCertHash(sMsg, m.GetConnectionInfo()) {
+				g.logger.Warning("Got membership request with selfInfo that doesn't match the TLS certificate of the connection")
+				return
+			}
 		}
 		g.forwardDiscoveryMsg(m)
 	}
@@ -358,6 +376,21 @@ func (g *gossipServiceImpl) handleMessage(m proto.ReceivedMessage) {
 	}
 }
 
+// validateAliveMsgTLSCertHash checks that an AliveMessage sent by the peer
+// it is about carries the hash of the TLS certificate of the connection it
+// was received from. AliveMessages about other peers are relayed, and can't
+// be checked against the connection.
+func validateAliveMsgTLSCertHash(m *proto.SignedGossipMessage, connInfo *proto.ConnectionInfo) bool {
+	am := m.GetAliveMsg()
+	if connInfo == nil || connInfo.TLSCertHash == nil || am.Membership == nil {
+		return true
+	}
+	if !bytes.Equal(am.Membership.PkiId, connInfo.ID) {
+		return true
+	}
+	return bytes.Equal(am.TlsCertHash, connInfo.TLSCertHash)
+}
+
 func (g *gossipServiceImpl) forwardDiscoveryMsg(msg proto.ReceivedMessage) {
 	defer func() { // can be closed while shutting down
 		recover()
@@ -407,6 +440,16 @@ func (g *gossipServiceImpl) validateMsg(msg proto.ReceivedMessage) bool {
 	return true
 }
 
+// propagateIterationsOf returns the number of times a message is pushed
+// to remote peers, according to the class of the message
+func (g *gossipServiceImpl) propagateIterationsOf(msg interface{}) int {
+	sMsg, isSignedMsg := msg.(*proto.SignedGossipMessage)
+	if !isSignedMsg {
+		return g.conf.PropagateIterations
+	}
+	return g.conf.propagationOf(sMsg).PropagateIterations
+}
+
 func (g *gossipServiceImpl) sendGossipBatch(a []interface{}) {
 	msgs2Gossip := make([]*proto.SignedGossipMessage, len(a))
 	for i, e := range a {
@@ -461,13 +504,18 @@ func (g *gossipServiceImpl) gossipBatch(msgs []*proto.SignedGossipMessage) {
 
 	// Gossip blocks
 	blocks, msgs = partitionMessages(isABlock, msgs)
-	g.gossipInChan(blocks, func(gc channel.GossipChannel) filter.RoutingFilter {
+	g.gossipInChan(blocks, g.conf.blockPropagation().PropagatePeerNum, func(gc channel.GossipChannel) filter.RoutingFilter {
 		return filter.CombineRoutingFilters(gc.EligibleForChannel, gc.IsMemberInChan, g.isInMyorg)
 	})
 
 	// Gossip Leadership messages
 	leadershipMsgs, msgs = partitionMessages(isLeadershipMsg, msgs)
-	g.gossipInChan(leadershipMsgs, func(gc channel.GossipChannel) filter.RoutingFilter {
+	leadershipPeerNum := g.conf.leadershipPropagation().PropagatePeerNum
+	if leadershipPeerNum == 0 {
+		// Select all peers that pass routing factory - e.g. all peers in channel and org
+		leadershipPeerNum = len(g.disc.GetMembership())
+	}
+	g.gossipInChan(leadershipMsgs, leadershipPeerNum, func(gc channel.GossipChannel) filter.RoutingFilter {
 		return filter.CombineRoutingFilters(gc.EligibleForChannel, gc.IsMemberInChan, g.isInMyorg)
 	})
 
@@ -480,7 +528,7 @@ func (g *gossipServiceImpl) gossipBatch(msgs []*proto.SignedGossipMessage) {
 			peerSelector = gc.IsMemberInChan
 		}
 
-		peers2Send := filter.SelectPeers(g.conf.PropagatePeerNum, g.disc.GetMembership(), peerSelector)
+		peers2Send := filter.SelectPeers(g.conf.stateInfoPropagation().PropagatePeerNum, g.disc.GetMembership(), peerSelector)
 		g.comm.Send(stateInfMsg, peers2Send...)
 	}
 
@@ -520,8 +568,8 @@ func (g *gossipServiceImpl) sendAndFilterSecrets(msg *proto.SignedGossipMessage,
 	}
 }
 
-// gossipInChan gossips a given GossipMessage slice according to a channel's routing policy.
-func (g *gossipServiceImpl) gossipInChan(messages []*proto.SignedGossipMessage, chanRoutingFactory channelRoutingFilterFactory) {
+// gossipInChan gossips a given GossipMessage slice to peerNum peers selected according to a channel's routing policy.
+func (g *gossipServiceImpl) gossipInChan(messages []*proto.SignedGossipMessage, peerNum int, chanRoutingFactory channelRoutingFilterFactory) {
 	if len(messages) == 0 {
 		return
 	}
@@ -543,17 +591,10 @@ func (g *gossipServiceImpl) gossipInChan(messages []*proto.SignedGossipMessage,
 			continue
 		}
 		// Select the peers to send the messages to
-		// For leadership messages we will select all peers that pass routing factory - e.g. all peers in channel and org
-		membership := g.disc.GetMembership()
-		allPeersInCh := filter.SelectPeers(len(membership), membership, chanRoutingFactory(gc))
-		peers2Send := filter.SelectPeers(g.conf.PropagatePeerNum, membership, chanRoutingFactory(gc))
+		peers2Send := filter.SelectPeers(peerNum, g.disc.GetMembership(), chanRoutingFactory(gc))
 		// Send the messages to the remote peers
 		for _, msg := range messagesOfChannel {
-			if msg.IsLeadershipMsg() {
-				g.comm.Send(msg, allPeersInCh...)
-			} else {
-				g.comm.Send(msg, peers2Send...)
-			}
+			g.comm.Send(msg, peers2Send...)
 		}
 	}
 }
@@ -872,6 +913,11 @@ func (sa *discoverySecurityAdapter) SignMessage(m *proto.GossipMessage, internal
 	if m.IsAliveMsg() && time.Now().Before(sa.includeIdentityPeriod) {
 		m.GetAliveMsg().Identity = sa.identity
 	}
+	// Bind our TLS certificate to the AliveMessage, so peers
+	// we're connected to can check it matches the connection
+	if m.IsAliveMsg() {
+		m.GetAliveMsg().TlsCertHash = sa.c.GetTLSCertHash()
+	}
 	sMsg := &proto.SignedGossipMessage{
 		GossipMessage: m,
 	}
@@ -910,7 +956,7 @@ func (g *gossipServiceImpl) createCertStorePuller() pull.Mediator {
 		Channel:           []byte(""),
 		ID:                g.conf.InternalEndpoint,
 		PeerCountToSelect: g.conf.PullPeerNum,
-		PullInterval:      g.conf.PullInterval,
+		PullInterval:      g.conf.identityPropagation().PullInterval,
 		Tag:               proto.GossipMessage_EMPTY,
 	}
 	pkiIDFromMsg := func(msg *proto.SignedGossipMessage) string {
@@ -1010,6 +1056,16 @@ func (g *gossipServiceImpl) hasExternalEndpoint(PKIID common.PKIidType) bool {
 	return false
 }
 
+// isValidEndpoint returns whether the endpoint is of the form host:port
+func isValidEndpoint(endpoint string) bool {
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil || host == "" {
+		return false
+	}
+	portNum, err := strconv.ParseUint(port, 10, 16)
+	return err == nil && portNum != 0
+}
+
 func (g *gossipServiceImpl) isInMyorg(member discovery.NetworkMember) bool {
 	if member.PKIid == nil {
 		return false
@@ -786,6 +786,32 @@ func TestMembershipRequestSpoofing(t *testing.T) {
 	}
 }
 
+func TestAliveMsgTLSCertHash(t *testing.T) {
+	t.Parallel()
+	aliveMsg := func(pkiID string, certHash []byte) *proto.SignedGossipMessage {
+		return &proto.SignedGossipMessage{
+			GossipMessage: &proto.GossipMessage{
+				Content: &proto.GossipMessage_AliveMsg{
+					AliveMsg: &proto.AliveMessage{
+						Membership:  &proto.Member{PkiId: common.PKIidType(pkiID)},
+						TlsCertHash: certHash,
+					},
+				},
+			},
+		}
+	}
+	connInfo := &proto.ConnectionInfo{ID: common.PKIidType("p1"), TLSCertHash: []byte("hash1")}
+
+	// An AliveMessage of the remote peer itself must carry the TLS certificate hash of the connection
+	assert.True(t, validateAliveMsgTLSCertHash(aliveMsg("p1", []byte("hash1")), connInfo))
+	assert.False(t, validateAliveMsgTLSCertHash(aliveMsg("p1", []byte("hash2")), connInfo))
+	assert.False(t, validateAliveMsgTLSCertHash(aliveMsg("p1", nil), connInfo))
+	// AliveMessages relayed about other peers can't be checked against the connection
+	assert.True(t, validateAliveMsgTLSCertHash(aliveMsg("p2", []byte("hash2")), connInfo))
+	// Without TLS there's nothing to check against
+	assert.True(t, validateAliveMsgTLSCertHash(aliveMsg("p1", nil), &proto.ConnectionInfo{ID: common.PKIidType("p1")}))
+}
+
 func TestDataLeakage(t *testing.T) {
 	t.Parallel()
 	portPrefix := 1610
@@ -1038,6 +1064,28 @@ func TestRevocation(t *testing.T) {
 	stopPeers(peers)
 }
 
+func TestPropagationConfig(t *testing.T) {
+	conf := &Config{
+		PropagateIterations:      1,
+		PropagatePeerNum:         3,
+		PullInterval:             4 * time.Second,
+		RequestStateInfoInterval: 2 * time.Second,
+		BlockPropagation:         PropagationConfig{PropagatePeerNum: 10},
+		IdentityPropagation:      PropagationConfig{PullInterval: time.Minute},
+	}
+
+	assert.Equal(t, PropagationConfig{PropagateIterations: 1, PropagatePeerNum: 10, PullInterval: 4 * time.Second}, conf.blockPropagation())
+	assert.Equal(t, PropagationConfig{PropagateIterations: 1, PropagatePeerNum: 3, PullInterval: 2 * time.Second}, conf.stateInfoPropagation())
+	assert.Equal(t, 0, conf.leadershipPropagation().PropagatePeerNum)
+	assert.Equal(t, time.Minute, conf.identityPropagation().PullInterval)
+
+	conf.LeadershipPropagation = PropagationConfig{PropagateIterations: 3, PropagatePeerNum: 5}
+	assert.Equal(t, PropagationConfig{PropagateIterations: 3, PropagatePeerNum: 5}, conf.leadershipPropagation())
+
+	dataMsg := &proto.SignedGossipMessage{GossipMessage: createDataMsg(1, []byte{}, "", common.ChainID("A"))}
+	assert.Equal(t, 10, conf.propagationOf(dataMsg).PropagatePeerNum)
+}
+
 func TestEndedGoroutines(t *testing.T) {
 	t.Parallel()
 	testWG.Wait()
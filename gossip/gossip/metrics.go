@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gossip
+
+import (
+	"expvar"
+
+	"github.com/hyperledger/fabric/gossip/common"
+)
+
+// channelPeers counts the alive peers known in each channel the peer joined,
+// e.g. {"mychannel": 4}. It is refreshed on every discovery sync and published
+// with the other expvar variables on /debug/vars of the profiling server of
+// the peer
+var channelPeers = expvar.NewMap("gossip_channel_peers")
+
+// recordChannelPeers updates the number of alive peers known in each channel
+func (g *gossipServiceImpl) recordChannelPeers() {
+	g.chanState.RLock()
+	chainIDs := make([]string, 0, len(g.chanState.channels))
+	for chainID := range g.chanState.channels {
+		chainIDs = append(chainIDs, chainID)
+	}
+	g.chanState.RUnlock()
+
+	for _, chainID := range chainIDs {
+		count := new(expvar.Int)
+		count.Set(int64(len(g.PeersOfChannel(common.ChainID(chainID)))))
+		channelPeers.Set(chainID, count)
+	}
+}
@@ -247,6 +247,25 @@ func TestMultipleOrgEndpointLeakage(t *testing.T) {
 	}
 }
 
+func TestInvalidExternalEndpoint(t *testing.T) {
+	t.Parallel()
+	// Scenario: a peer configured with an external endpoint that isn't of the form host:port
+	// must not publish it to peers of other organizations.
+	for _, endpoint := range []string{"localhost:7051", "10.0.0.1:7051", "[::1]:7051"} {
+		assert.True(t, isValidEndpoint(endpoint), endpoint)
+	}
+	for _, endpoint := range []string{"localhost", ":7051", "localhost:", "localhost:0", "localhost:port", "localhost:70510"} {
+		assert.False(t, isValidEndpoint(endpoint), endpoint)
+	}
+
+	portPrefix := 13610
+	cs := &configurableCryptoService{m: make(map[string]api.OrgIdentityType)}
+	cs.putInOrg(portPrefix, "A")
+	p := newGossipInstanceWithExternalEndpoint(portPrefix, 0, cs, "localhost")
+	defer p.Stop()
+	assert.Empty(t, p.(*gossipServiceImpl).selfNetworkMember().Endpoint)
+}
+
 func TestConfidentiality(t *testing.T) {
 	t.Parallel()
 	// Scenario: create 4 organizations: {A, B, C, D}, each with 3 peers.
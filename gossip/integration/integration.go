@@ -66,6 +66,21 @@ func newConfig(selfEndpoint string, externalEndpoint string, bootPeers ...string
 		PublishStateInfoInterval:   util.GetDurationOrDefault("peer.gossip.publishStateInfoInterval", 4*time.Second),
 		SkipBlockVerification:      viper.GetBool("peer.gossip.skipBlockVerification"),
 		TLSServerCert:              cert,
+		BlockPropagation:           propagationConfig("block"),
+		StateInfoPropagation:       propagationConfig("stateInfo"),
+		LeadershipPropagation:      propagationConfig("leadership"),
+		IdentityPropagation:        propagationConfig("identity"),
+	}
+}
+
+// propagationConfig reads the dissemination parameters of a class of messages,
+// the unset ones are left to zero so that the global ones apply
+func propagationConfig(class string) gossip.PropagationConfig {
+	prefix := "peer.gossip.propagation." + class + "."
+	return gossip.PropagationConfig{
+		PropagateIterations: viper.GetInt(prefix + "propagateIterations"),
+		PropagatePeerNum:    viper.GetInt(prefix + "propagatePeerNum"),
+		PullInterval:        viper.GetDuration(prefix + "pullInterval"),
 	}
 }
 
@@ -34,6 +34,12 @@ type Config interface {
 
 	// Sequence should return the sequence number of the current configuration
 	Sequence() uint64
+
+	// OrdererAddresses returns the list of valid orderer addresses to connect to to invoke Broadcast/Deliver
+	OrdererAddresses() []string
+
+	// PreferredOrdererAddresses returns the orderer addresses declared by the organization of this peer, if any
+	PreferredOrdererAddresses() []string
 }
 
 // ConfigProcessor receives config updates
@@ -43,8 +49,10 @@ type ConfigProcessor interface {
 }
 
 type configStore struct {
-	anchorPeers []*peer.AnchorPeer
-	orgMap      map[string]config.ApplicationOrg
+	anchorPeers      []*peer.AnchorPeer
+	orgMap           map[string]config.ApplicationOrg
+	ordererAddresses []string
+	preferred        []string
 }
 
 type configEventReceiver interface {
@@ -69,8 +77,10 @@ func newConfigEventer(receiver configEventReceiver) *configEventer {
 func (ce *configEventer) ProcessConfigUpdate(config Config) {
 	logger.Debugf("Processing new config for channel %s", config.ChainID())
 
-	if ce.lastConfig != nil && reflect.DeepEqual(ce.lastConfig.orgMap, config.Organizations()) {
-		logger.Debugf("Ignoring new config for channel %s because it contained no anchor peer updates", config.ChainID())
+	if ce.lastConfig != nil && reflect.DeepEqual(ce.lastConfig.orgMap, config.Organizations()) &&
+		reflect.DeepEqual(ce.lastConfig.ordererAddresses, config.OrdererAddresses()) &&
+		reflect.DeepEqual(ce.lastConfig.preferred, config.PreferredOrdererAddresses()) {
+		logger.Debugf("Ignoring new config for channel %s because it contained no anchor peer or orderer address updates", config.ChainID())
 		return
 	}
 
@@ -80,8 +90,10 @@ func (ce *configEventer) ProcessConfigUpdate(config Config) {
 	}
 
 	newConfig := &configStore{
-		orgMap:      config.Organizations(),
-		anchorPeers: newAnchorPeers,
+		orgMap:           config.Organizations(),
+		anchorPeers:      newAnchorPeers,
+		ordererAddresses: config.OrdererAddresses(),
+		preferred:        config.PreferredOrdererAddresses(),
 	}
 	ce.lastConfig = newConfig
 
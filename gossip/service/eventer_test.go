@@ -22,6 +22,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/gossip/util"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
 	"github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -41,19 +42,27 @@ func (ao applicationOrgs) MSPID() string {
 	panic("Unimplimented")
 }
 
+func (ao applicationOrgs) MSPConfig() *mspprotos.MSPConfig {
+	panic("Unimplimented")
+}
+
 func (ao applicationOrgs) Name() string {
 	panic("Unimplimented")
 }
 
 type mockReceiver struct {
-	orgs     map[string]config.ApplicationOrg
-	sequence uint64
+	orgs             map[string]config.ApplicationOrg
+	sequence         uint64
+	ordererAddresses []string
+	preferred        []string
 }
 
 func (mr *mockReceiver) configUpdated(config Config) {
-	logger.Debugf("[TEST] Setting config to %d %v", config.Sequence(), config.Organizations())
+	logger.Debugf("[TEST] Setting config to %d %v %v", config.Sequence(), config.Organizations(), config.OrdererAddresses())
 	mr.orgs = config.Organizations()
 	mr.sequence = config.Sequence()
+	mr.ordererAddresses = config.OrdererAddresses()
+	mr.preferred = config.PreferredOrdererAddresses()
 }
 
 type mockConfig mockReceiver
@@ -66,10 +75,22 @@ func (mc *mockConfig) Organizations() map[string]config.ApplicationOrg {
 	return mc.orgs
 }
 
+func (mc *mockConfig) HasCapability(name string) bool {
+	return false
+}
+
 func (mc *mockConfig) ChainID() string {
 	return testChainID
 }
 
+func (mc *mockConfig) OrdererAddresses() []string {
+	return mc.ordererAddresses
+}
+
+func (mc *mockConfig) PreferredOrdererAddresses() []string {
+	return mc.preferred
+}
+
 const testOrgID = "testID"
 
 func TestInitialUpdate(t *testing.T) {
@@ -156,6 +177,66 @@ func TestSecondSameUpdate(t *testing.T) {
 	}
 }
 
+func TestOrdererAddressesUpdate(t *testing.T) {
+	mc := &mockConfig{
+		sequence: 7,
+		orgs: map[string]config.ApplicationOrg{
+			testOrgID: applicationOrgs([]*peer.AnchorPeer{
+				&peer.AnchorPeer{
+					Port: 9,
+				},
+			}),
+		},
+		ordererAddresses: []string{"orderer1:7050"},
+	}
+
+	mr := &mockReceiver{}
+
+	ce := newConfigEventer(mr)
+	ce.ProcessConfigUpdate(mc)
+	mc.sequence = 8
+	mc.ordererAddresses = []string{"orderer1:7050", "orderer2:7050"}
+	ce.ProcessConfigUpdate(mc)
+
+	if mr.sequence != 8 {
+		t.Errorf("Should have updated sequence when orderer addresses changed")
+	}
+
+	if !reflect.DeepEqual(mr.ordererAddresses, mc.ordererAddresses) {
+		t.Errorf("Should have updated orderer addresses")
+	}
+}
+
+func TestPreferredOrdererAddressesUpdate(t *testing.T) {
+	mc := &mockConfig{
+		sequence: 7,
+		orgs: map[string]config.ApplicationOrg{
+			testOrgID: applicationOrgs([]*peer.AnchorPeer{
+				&peer.AnchorPeer{
+					Port: 9,
+				},
+			}),
+		},
+		ordererAddresses: []string{"orderer1:7050", "orderer2:7050"},
+	}
+
+	mr := &mockReceiver{}
+
+	ce := newConfigEventer(mr)
+	ce.ProcessConfigUpdate(mc)
+	mc.sequence = 8
+	mc.preferred = []string{"orderer2:7050"}
+	ce.ProcessConfigUpdate(mc)
+
+	if mr.sequence != 8 {
+		t.Errorf("Should have updated sequence when preferred orderer addresses changed")
+	}
+
+	if !reflect.DeepEqual(mr.preferred, mc.preferred) {
+		t.Errorf("Should have updated preferred orderer addresses")
+	}
+}
+
 func TestUpdatedSeqOnly(t *testing.T) {
 	mc := &mockConfig{
 		sequence: 7,
@@ -17,6 +17,7 @@ limitations under the License.
 package service
 
 import (
+	"fmt"
 	"sync"
 
 	peerComm "github.com/hyperledger/fabric/core/comm"
@@ -71,11 +72,12 @@ type deliveryFactoryImpl struct {
 // Returns an instance of delivery client
 func (*deliveryFactoryImpl) Service(g GossipService, endpoints []string, mcs api.MessageCryptoService) (deliverclient.DeliverService, error) {
 	return deliverclient.NewDeliverService(&deliverclient.Config{
-		CryptoSvc:   mcs,
-		Gossip:      g,
-		Endpoints:   endpoints,
-		ConnFactory: deliverclient.DefaultConnectionFactory,
-		ABCFactory:  deliverclient.DefaultABCFactory,
+		CryptoSvc:          mcs,
+		Gossip:             g,
+		Endpoints:          endpoints,
+		PreferredEndpoints: viper.GetStringSlice("peer.deliveryclient.preferredEndpoints"),
+		ConnFactory:        deliverclient.DefaultConnectionFactory,
+		ABCFactory:         deliverclient.DefaultABCFactory,
 	})
 }
 
@@ -85,6 +87,7 @@ type gossipServiceImpl struct {
 	leaderElection  map[string]election.LeaderElectionService
 	deliveryService deliverclient.DeliverService
 	deliveryFactory DeliveryServiceFactory
+	preferred       map[string][]string
 	lock            sync.RWMutex
 	idMapper        identity.Mapper
 	mcs             api.MessageCryptoService
@@ -157,6 +160,7 @@ func InitGossipServiceCustomDeliveryFactory(peerIdentity []byte, endpoint string
 			chains:          make(map[string]state.GossipStateProvider),
 			leaderElection:  make(map[string]election.LeaderElectionService),
 			deliveryFactory: factory,
+			preferred:       make(map[string][]string),
 			idMapper:        idMapper,
 			peerIdentity:    peerIdentity,
 			secAdv:          secAdv,
@@ -192,6 +196,13 @@ func (g *gossipServiceImpl) InitializeChannel(chainID string, committer committe
 	// Delivery service might be nil only if it was not able to get connected
 	// to the ordering service
 	if g.deliveryService != nil {
+		if err := g.deliveryService.UpdateEndpoints(chainID, endpoints); err != nil {
+			logger.Warning("Cannot set ordering service endpoints of chain", chainID, "due to", err)
+		}
+		if err := g.deliveryService.UpdatePreferredEndpoints(chainID, g.preferred[chainID]); err != nil {
+			logger.Warning("Cannot set preferred ordering service endpoints of chain", chainID, "due to", err)
+		}
+
 		// Parameters:
 		//              - peer.gossip.useLeaderElection
 		//              - peer.gossip.orgLeader
@@ -242,6 +253,28 @@ func (g *gossipServiceImpl) configUpdated(config Config) {
 	// Initialize new state provider for given committer
 	logger.Debug("Creating state provider for chainID", config.ChainID())
 	g.JoinChan(jcm, gossipCommon.ChainID(config.ChainID()))
+
+	g.updateOrdererEndpoints(config.ChainID(), config.OrdererAddresses(), config.PreferredOrdererAddresses())
+}
+
+// updateOrdererEndpoints forwards the orderer addresses of the channel config to
+// the delivery service. When the delivery service isn't created yet, the addresses
+// are passed at channel initialization instead.
+func (g *gossipServiceImpl) updateOrdererEndpoints(chainID string, endpoints []string, preferred []string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.preferred[chainID] = preferred
+	if g.deliveryService == nil {
+		return
+	}
+	if len(endpoints) > 0 {
+		if err := g.deliveryService.UpdateEndpoints(chainID, endpoints); err != nil {
+			logger.Warning("Cannot update ordering service endpoints of chain", chainID, "due to", err)
+		}
+	}
+	if err := g.deliveryService.UpdatePreferredEndpoints(chainID, preferred); err != nil {
+		logger.Warning("Cannot update preferred ordering service endpoints of chain", chainID, "due to", err)
+	}
 }
 
 // GetBlock returns block for given chain
@@ -254,14 +287,25 @@ func (g *gossipServiceImpl) GetBlock(chainID string, index uint64) *common.Block
 // AddPayload appends message payload to for given chain
 func (g *gossipServiceImpl) AddPayload(chainID string, payload *proto.Payload) error {
 	g.lock.RLock()
-	defer g.lock.RUnlock()
-	return g.chains[chainID].AddPayload(payload)
+	stateProvider, exists := g.chains[chainID]
+	g.lock.RUnlock()
+	if !exists {
+		return fmt.Errorf("No state provider for channel %s", chainID)
+	}
+	// Not holding the lock: adding the payload blocks while the buffer of
+	// the channel is full
+	return stateProvider.AddPayload(payload)
 }
 
-// Stop stops the gossip component
+// Stop stops the gossip component. The blocks received from the ordering
+// service before it stopped are committed before the chains stop
 func (g *gossipServiceImpl) Stop() {
 	g.lock.Lock()
 	defer g.lock.Unlock()
+	if g.deliveryService != nil {
+		g.deliveryService.Stop()
+	}
+
 	for _, ch := range g.chains {
 		logger.Info("Stopping chain", ch)
 		ch.Stop()
@@ -272,9 +316,6 @@ func (g *gossipServiceImpl) Stop() {
 		electionService.Stop()
 	}
 	g.gossipSvc.Stop()
-	if g.deliveryService != nil {
-		g.deliveryService.Stop()
-	}
 }
 
 func (g *gossipServiceImpl) newLeaderElectionComponent(chainID string, callback func(bool)) election.LeaderElectionService {
@@ -38,6 +38,7 @@ import (
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
 	"github.com/hyperledger/fabric/peer/gossip/mcs"
 	"github.com/hyperledger/fabric/protos/common"
+	proto "github.com/hyperledger/fabric/protos/gossip"
 	"github.com/op/go-logging"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -292,6 +293,14 @@ func (ds *mockDeliverService) StopDeliverForChannel(chainID string) error {
 	return nil
 }
 
+func (ds *mockDeliverService) UpdateEndpoints(chainID string, endpoints []string) error {
+	return nil
+}
+
+func (ds *mockDeliverService) UpdatePreferredEndpoints(chainID string, endpoints []string) error {
+	return nil
+}
+
 func (ds *mockDeliverService) Stop() {
 }
 
@@ -617,6 +626,7 @@ func newGossipInstance(portPrefix int, id int, maxMsgCount int, boot ...int) Gos
 		chains:          make(map[string]state.GossipStateProvider),
 		leaderElection:  make(map[string]election.LeaderElectionService),
 		deliveryFactory: &deliveryFactoryImpl{},
+		preferred:       make(map[string][]string),
 		idMapper:        idMapper,
 		peerIdentity:    api.PeerIdentityType(conf.InternalEndpoint),
 	}
@@ -689,3 +699,50 @@ func (*naiveCryptoService) Verify(peerIdentity api.PeerIdentityType, signature,
 }
 
 var orgInChannelA = api.OrgIdentityType("ORG1")
+
+// blockedStateProvider blocks the payloads added until released
+type blockedStateProvider struct {
+	adding  chan struct{}
+	release chan struct{}
+}
+
+func (sp *blockedStateProvider) GetBlock(index uint64) *common.Block {
+	return nil
+}
+
+func (sp *blockedStateProvider) AddPayload(payload *proto.Payload) error {
+	close(sp.adding)
+	<-sp.release
+	return nil
+}
+
+func (sp *blockedStateProvider) Stop() {
+}
+
+func TestAddPayloadDoesNotHoldLock(t *testing.T) {
+	sp := &blockedStateProvider{adding: make(chan struct{}), release: make(chan struct{})}
+	g := &gossipServiceImpl{chains: map[string]state.GossipStateProvider{"A": sp}}
+
+	added := make(chan error)
+	go func() {
+		added <- g.AddPayload("A", &proto.Payload{SeqNum: 1})
+	}()
+	<-sp.adding
+
+	// A payload waiting for room in the buffer doesn't stall the writers of the lock
+	locked := make(chan struct{})
+	go func() {
+		g.lock.Lock()
+		g.lock.Unlock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(time.Second * 5):
+		assert.Fail(t, "The lock is held while the payload is added")
+	}
+	close(sp.release)
+	assert.NoError(t, <-added)
+
+	assert.Error(t, g.AddPayload("B", &proto.Payload{SeqNum: 1}))
+}
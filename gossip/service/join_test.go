@@ -27,6 +27,7 @@ import (
 	"github.com/hyperledger/fabric/gossip/discovery"
 	"github.com/hyperledger/fabric/gossip/util"
 	proto "github.com/hyperledger/fabric/protos/gossip"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -99,6 +100,10 @@ func (ao *appOrgMock) MSPID() string {
 	return ao.id
 }
 
+func (*appOrgMock) MSPConfig() *mspprotos.MSPConfig {
+	panic("implement me")
+}
+
 func (*appOrgMock) AnchorPeers() []*peer.AnchorPeer {
 	return []*peer.AnchorPeer{{Host: "1.2.3.4", Port: 5611}}
 }
@@ -116,10 +121,22 @@ func (*configMock) Organizations() map[string]config.ApplicationOrg {
 	}
 }
 
+func (*configMock) HasCapability(name string) bool {
+	return false
+}
+
 func (*configMock) Sequence() uint64 {
 	return 0
 }
 
+func (*configMock) OrdererAddresses() []string {
+	return []string{"localhost:7050"}
+}
+
+func (*configMock) PreferredOrdererAddresses() []string {
+	return nil
+}
+
 func TestJoinChannelConfig(t *testing.T) {
 	// Scenarios: The channel we're joining has a single org - Org0
 	// but our org ID is actually Org0MSP in the negative path
@@ -143,7 +160,7 @@ func TestJoinChannelConfig(t *testing.T) {
 	g2SvcMock.On("JoinChan", mock.Anything).Run(func(_ mock.Arguments) {
 		succChan <- struct{}{}
 	})
-	g2 := &gossipServiceImpl{secAdv: &secAdvMock{}, peerIdentity: api.PeerIdentityType("Org0"), gossipSvc: g2SvcMock}
+	g2 := &gossipServiceImpl{secAdv: &secAdvMock{}, peerIdentity: api.PeerIdentityType("Org0"), gossipSvc: g2SvcMock, preferred: make(map[string][]string)}
 	g2.configUpdated(&configMock{})
 	select {
 	case <-time.After(time.Second):
@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"expvar"
+	"sync"
+)
+
+// payloadsBuffers describes the usage of the payloads buffer of each channel,
+// e.g. {"mychannel": {"size": 3, "capacity": 200, "dropped": 0, "throttled": 0}}.
+// It is refreshed on every anti entropy round and published with the other
+// expvar variables on /debug/vars of the profiling server of the peer
+var payloadsBuffers = expvar.NewMap("gossip_state_payloads_buffer")
+
+// payloadsBuffersLock serializes the creation of the variables of a channel
+var payloadsBuffersLock sync.Mutex
+
+// recordBufferStats publishes the usage of the payloads buffer of a channel
+func recordBufferStats(chainID string, stats PayloadsBufferStats) {
+	payloadsBuffersLock.Lock()
+	defer payloadsBuffersLock.Unlock()
+
+	vars, ok := payloadsBuffers.Get(chainID).(*expvar.Map)
+	if !ok {
+		vars = new(expvar.Map).Init()
+		payloadsBuffers.Set(chainID, vars)
+	}
+	for name, value := range map[string]int64{
+		"size":      int64(stats.Size),
+		"capacity":  int64(stats.Capacity),
+		"dropped":   int64(stats.Dropped),
+		"throttled": int64(stats.Throttled),
+	} {
+		v := new(expvar.Int)
+		v.Set(value)
+		vars.Set(name, v)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/hyperledger/fabric/gossip/util"
 	proto "github.com/hyperledger/fabric/protos/gossip"
@@ -32,9 +33,15 @@ import (
 // sequence numbers. It also will provide the capability
 // to signal whenever expected block has arrived.
 type PayloadsBuffer interface {
-	// Adds new block into the buffer
+	// Adds new block into the buffer. If the buffer is full, the payload
+	// furthest from the next expected sequence number is shed.
 	Push(payload *proto.Payload) error
 
+	// Adds new block into the buffer, waiting for room to free up
+	// if the buffer is full. Used to slow down the block sources that
+	// cannot afford to lose blocks.
+	BlockingPush(payload *proto.Payload) error
+
 	// Returns next expected sequence number
 	Next() uint64
 
@@ -48,9 +55,30 @@ type PayloadsBuffer interface {
 	// number equal to the next expected value.
 	Ready() chan struct{}
 
+	// Stats returns counters describing the buffer usage
+	Stats() PayloadsBufferStats
+
+	// Drops the buffered payloads and sets the next expected
+	// sequence number
+	Reset(next uint64)
+
 	Close()
 }
 
+// PayloadsBufferStats summarizes the usage of a payloads buffer
+type PayloadsBufferStats struct {
+	// Number of payloads currently buffered
+	Size int
+	// Maximum number of payloads the buffer holds
+	Capacity int
+	// Number of payloads shed because the buffer was full
+	Dropped uint64
+	// Number of pushes delayed because the buffer was full
+	Throttled uint64
+	// Total time pushes have been delayed for
+	ThrottledTime time.Duration
+}
+
 // PayloadsBufferImpl structure to implement PayloadsBuffer interface
 // store inner state of available payloads and sequence numbers
 type PayloadsBufferImpl struct {
@@ -58,21 +86,46 @@ type PayloadsBufferImpl struct {
 
 	buf map[uint64]*proto.Payload
 
+	capacity int
+
 	readyChan chan struct{}
 
+	closeChan chan struct{}
+
 	mutex sync.RWMutex
 
+	// Signaled whenever payloads are removed from the buffer
+	spaceCond *sync.Cond
+
+	closed bool
+
+	stats PayloadsBufferStats
+
 	logger *logging.Logger
 }
 
 // NewPayloadsBuffer is factory function to create new payloads buffer
+// holding up to defPayloadBufferSize payloads
 func NewPayloadsBuffer(next uint64) PayloadsBuffer {
-	return &PayloadsBufferImpl{
+	return NewPayloadsBufferWithCapacity(next, defPayloadBufferSize)
+}
+
+// NewPayloadsBufferWithCapacity creates new payloads buffer holding
+// up to capacity payloads
+func NewPayloadsBufferWithCapacity(next uint64, capacity int) PayloadsBuffer {
+	if capacity <= 0 {
+		capacity = defPayloadBufferSize
+	}
+	b := &PayloadsBufferImpl{
 		buf:       make(map[uint64]*proto.Payload),
+		capacity:  capacity,
 		readyChan: make(chan struct{}, 0),
+		closeChan: make(chan struct{}),
 		next:      next,
 		logger:    util.GetLogger(util.LoggingStateModule, ""),
 	}
+	b.spaceCond = sync.NewCond(&b.mutex)
+	return b
 }
 
 // Ready function returns the channel which indicates whenever expected
@@ -84,28 +137,102 @@ func (b *PayloadsBufferImpl) Ready() chan struct{} {
 
 // Push new payload into the buffer structure in case new arrived payload
 // sequence number is below the expected next block number payload will be
-// thrown away and error will be returned.
+// thrown away and error will be returned. If the buffer is full, either the
+// new payload or the buffered payload with the highest sequence number is
+// dropped, whichever is further from the next expected block.
 func (b *PayloadsBufferImpl) Push(payload *proto.Payload) error {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
-	seqNum := payload.SeqNum
+	if err := b.checkNotProcessed(payload); err != nil {
+		return err
+	}
+
+	if b.isFull() && !b.shed(payload.SeqNum) {
+		b.stats.Dropped++
+		return fmt.Errorf("Payload with sequence number = %d dropped, buffer is full", payload.SeqNum)
+	}
+
+	b.add(payload)
+	return nil
+}
+
+// BlockingPush new payload into the buffer structure, waiting for room to
+// free up if the buffer is full. The payload carrying the next expected
+// sequence number never waits, otherwise the buffer would never drain.
+func (b *PayloadsBufferImpl) BlockingPush(payload *proto.Payload) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.isFull() && payload.SeqNum != b.next && !b.closed {
+		start := time.Now()
+		b.stats.Throttled++
+		b.logger.Debugf("Buffer is full, delaying payload with sequence number = %d", payload.SeqNum)
+		for b.isFull() && payload.SeqNum != b.next && !b.closed {
+			b.spaceCond.Wait()
+		}
+		b.stats.ThrottledTime += time.Since(start)
+	}
+
+	if b.closed {
+		return fmt.Errorf("Payload with sequence number = %d dropped, buffer is closed", payload.SeqNum)
+	}
+	if err := b.checkNotProcessed(payload); err != nil {
+		return err
+	}
+
+	if b.isFull() {
+		// Only the next expected payload gets here, make room for it
+		b.shed(payload.SeqNum)
+	}
 
-	if seqNum < b.next || b.buf[seqNum] != nil {
+	b.add(payload)
+	return nil
+}
+
+func (b *PayloadsBufferImpl) checkNotProcessed(payload *proto.Payload) error {
+	if payload.SeqNum < b.next || b.buf[payload.SeqNum] != nil {
 		return fmt.Errorf("Payload with sequence number = %s has been already processed",
 			strconv.FormatUint(payload.SeqNum, 10))
 	}
+	return nil
+}
+
+func (b *PayloadsBufferImpl) isFull() bool {
+	return len(b.buf) >= b.capacity
+}
+
+// shed evicts the buffered payload with the highest sequence number if it
+// is greater than seqNum, and reports whether room was made for seqNum
+func (b *PayloadsBufferImpl) shed(seqNum uint64) bool {
+	highest := seqNum
+	for n := range b.buf {
+		if n > highest {
+			highest = n
+		}
+	}
+	if highest == seqNum {
+		return false
+	}
+	delete(b.buf, highest)
+	b.stats.Dropped++
+	b.logger.Debugf("Buffer is full, dropped payload with sequence number = %d", highest)
+	return true
+}
 
-	b.buf[seqNum] = payload
+func (b *PayloadsBufferImpl) add(payload *proto.Payload) {
+	b.buf[payload.SeqNum] = payload
 
 	// Send notification that next sequence has arrived
-	if seqNum == b.next {
+	if payload.SeqNum == b.next {
 		// Do not block execution of current routine
 		go func() {
-			b.readyChan <- struct{}{}
+			select {
+			case b.readyChan <- struct{}{}:
+			case <-b.closeChan:
+			}
 		}()
 	}
-	return nil
 }
 
 // Next function provides the number of the next expected block
@@ -127,6 +254,8 @@ func (b *PayloadsBufferImpl) Pop() *proto.Payload {
 		delete(b.buf, b.Next())
 		// Increment next expect block index
 		atomic.AddUint64(&b.next, 1)
+		// Wake up pushes waiting for room
+		b.spaceCond.Broadcast()
 	}
 	return result
 }
@@ -138,7 +267,36 @@ func (b *PayloadsBufferImpl) Size() int {
 	return len(b.buf)
 }
 
-// Close cleanups resources and channels in maintained
+// Stats returns counters describing the buffer usage
+func (b *PayloadsBufferImpl) Stats() PayloadsBufferStats {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	stats := b.stats
+	stats.Size = len(b.buf)
+	stats.Capacity = b.capacity
+	return stats
+}
+
+// Reset drops the buffered payloads, e.g. after a payload which doesn't
+// extend the ledger was found, and expects the given sequence number next
+func (b *PayloadsBufferImpl) Reset(next uint64) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.buf = make(map[uint64]*proto.Payload)
+	atomic.StoreUint64(&b.next, next)
+	// Wake up pushes waiting for room
+	b.spaceCond.Broadcast()
+}
+
+// Close cleanups resources and channels in maintained and releases
+// the pushes waiting for room
 func (b *PayloadsBufferImpl) Close() {
-	close(b.readyChan)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	close(b.closeChan)
+	b.spaceCond.Broadcast()
 }
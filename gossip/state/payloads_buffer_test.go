@@ -18,6 +18,7 @@ package state
 
 import (
 	"crypto/rand"
+	"expvar"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -184,3 +185,127 @@ func TestPayloadsBufferImpl_ConcurrentPush(t *testing.T) {
 	// Buffer size has to be only one
 	assert.Equal(t, 1, buffer.Size())
 }
+
+func TestPayloadsBufferImpl_PushShedding(t *testing.T) {
+	buffer := NewPayloadsBufferWithCapacity(1, 3)
+
+	for _, seqNum := range []uint64{2, 3, 5} {
+		payload, err := randomPayloadWithSeqNum(seqNum)
+		assert.NoError(t, err)
+		assert.NoError(t, buffer.Push(payload))
+	}
+
+	// Buffer is full and the payload is further than all buffered ones
+	payload, err := randomPayloadWithSeqNum(6)
+	assert.NoError(t, err)
+	assert.Error(t, buffer.Push(payload))
+
+	// Payload closer to the next expected one replaces the furthest one
+	payload, err = randomPayloadWithSeqNum(4)
+	assert.NoError(t, err)
+	assert.NoError(t, buffer.Push(payload))
+
+	// Next expected payload is always accepted
+	payload, err = randomPayloadWithSeqNum(1)
+	assert.NoError(t, err)
+	assert.NoError(t, buffer.Push(payload))
+
+	stats := buffer.Stats()
+	assert.Equal(t, 3, stats.Size)
+	assert.Equal(t, 3, stats.Capacity)
+	assert.Equal(t, uint64(3), stats.Dropped)
+
+	<-buffer.Ready()
+	for _, seqNum := range []uint64{1, 2, 3} {
+		payload := buffer.Pop()
+		assert.NotNil(t, payload)
+		assert.Equal(t, seqNum, payload.SeqNum)
+	}
+	assert.Nil(t, buffer.Pop())
+}
+
+func TestPayloadsBufferImpl_BlockingPush(t *testing.T) {
+	buffer := NewPayloadsBufferWithCapacity(1, 2)
+
+	for _, seqNum := range []uint64{1, 2} {
+		payload, err := randomPayloadWithSeqNum(seqNum)
+		assert.NoError(t, err)
+		assert.NoError(t, buffer.BlockingPush(payload))
+	}
+	<-buffer.Ready()
+
+	pushed := make(chan error, 1)
+	payload, err := randomPayloadWithSeqNum(3)
+	assert.NoError(t, err)
+	go func() {
+		pushed <- buffer.BlockingPush(payload)
+	}()
+
+	select {
+	case <-pushed:
+		assert.Fail(t, "Push should wait for room in the buffer")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.Equal(t, uint64(1), buffer.Pop().SeqNum)
+	select {
+	case err := <-pushed:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "Push should have completed once room was made")
+	}
+	assert.Equal(t, 2, buffer.Size())
+	assert.Equal(t, uint64(1), buffer.Stats().Throttled)
+
+	// Waiting pushes are released when the buffer is closed
+	payload, err = randomPayloadWithSeqNum(4)
+	assert.NoError(t, err)
+	go func() {
+		pushed <- buffer.BlockingPush(payload)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	buffer.Close()
+	select {
+	case err := <-pushed:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "Push should have been released by Close")
+	}
+}
+
+func TestPayloadsBufferImpl_Reset(t *testing.T) {
+	buffer := NewPayloadsBuffer(5)
+	for seqNum := uint64(5); seqNum < 8; seqNum++ {
+		payload, err := randomPayloadWithSeqNum(seqNum)
+		if err != nil {
+			t.Fatal("Wasn't able to generate random payload for test")
+		}
+		assert.NoError(t, buffer.Push(payload))
+	}
+	assert.NotNil(t, buffer.Pop())
+	assert.Equal(t, uint64(6), buffer.Next())
+
+	// Going back to a block already popped drops the buffered payloads
+	buffer.Reset(5)
+	assert.Equal(t, uint64(5), buffer.Next())
+	assert.Equal(t, 0, buffer.Size())
+
+	payload, _ := randomPayloadWithSeqNum(5)
+	assert.NoError(t, buffer.Push(payload), "Should accept the payloads from the new next sequence number")
+	assert.Equal(t, uint64(5), buffer.Pop().SeqNum)
+}
+
+func TestRecordBufferStats(t *testing.T) {
+	recordBufferStats("testchannel", PayloadsBufferStats{Size: 3, Capacity: 10, Dropped: 2, Throttled: 1})
+	vars, ok := payloadsBuffers.Get("testchannel").(*expvar.Map)
+	if !assert.True(t, ok, "Should have published the buffer usage of the channel") {
+		return
+	}
+	assert.Equal(t, "3", vars.Get("size").String())
+	assert.Equal(t, "10", vars.Get("capacity").String())
+	assert.Equal(t, "2", vars.Get("dropped").String())
+	assert.Equal(t, "1", vars.Get("throttled").String())
+
+	recordBufferStats("testchannel", PayloadsBufferStats{Size: 0, Capacity: 10, Dropped: 2, Throttled: 1})
+	assert.Equal(t, "0", vars.Get("size").String())
+}
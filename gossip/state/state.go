@@ -42,6 +42,8 @@ type GossipStateProvider interface {
 	// Retrieve block with sequence number equal to index
 	GetBlock(index uint64) *common.Block
 
+	// AddPayload adds new payload into state, blocking while
+	// the payloads buffer is full
 	AddPayload(payload *proto.Payload) error
 
 	// Stop terminates state transfer object
@@ -55,6 +57,8 @@ const (
 
 	defChannelBufferSize     = 100
 	defAntiEntropyMaxRetries = 3
+
+	defPayloadBufferSize = 200
 )
 
 // GossipAdapter defines gossip/communication required interface for state provider
@@ -110,6 +114,10 @@ type GossipStateProviderImpl struct {
 
 	stopCh chan struct{}
 
+	// Signaled to start an anti entropy round right away, after a block
+	// which doesn't extend the chain of the ledger was refused
+	resyncCh chan struct{}
+
 	done sync.WaitGroup
 
 	once sync.Once
@@ -185,7 +193,8 @@ func NewGossipStateProvider(chainID string, g GossipAdapter, committer committer
 		commChan: commChan,
 
 		// Create a queue for payload received
-		payloads: NewPayloadsBuffer(height),
+		payloads: NewPayloadsBufferWithCapacity(height,
+			util.GetIntOrDefault("peer.gossip.state.payloadBufferSize", defPayloadBufferSize)),
 
 		committer: committer,
 
@@ -195,6 +204,8 @@ func NewGossipStateProvider(chainID string, g GossipAdapter, committer committer
 
 		stopCh: make(chan struct{}, 1),
 
+		resyncCh: make(chan struct{}, 1),
+
 		stateTransferActive: 0,
 
 		once: sync.Once{},
@@ -372,7 +383,7 @@ func (s *GossipStateProviderImpl) handleStateResponse(msg proto.ReceivedMessage)
 		}
 		err := s.payloads.Push(payload)
 		if err != nil {
-			logger.Warningf("Payload with sequence number %d was received earlier", payload.SeqNum)
+			logger.Warningf("Payload with sequence number %d not buffered, due to %s", payload.SeqNum, err)
 		}
 	}
 	return max, nil
@@ -383,6 +394,8 @@ func (s *GossipStateProviderImpl) Stop() {
 	// Make sure stop won't be executed twice
 	// and stop channel won't be used again
 	s.once.Do(func() {
+		// Release the block sources waiting for room in the buffer
+		s.payloads.Close()
 		s.stopCh <- struct{}{}
 		// Make sure all go-routines has finished
 		s.done.Wait()
@@ -421,16 +434,22 @@ func (s *GossipStateProviderImpl) deliverPayloads() {
 		// Wait for notification that next seq has arrived
 		case <-s.payloads.Ready():
 			logger.Debugf("Ready to transfer payloads to the ledger, next sequence number is = [%d]", s.payloads.Next())
-			// Collect all subsequent payloads
-			for payload := s.payloads.Pop(); payload != nil; payload = s.payloads.Pop() {
-				rawblock := &common.Block{}
-				if err := pb.Unmarshal(payload.Data, rawblock); err != nil {
-					logger.Errorf("Error getting block with seqNum = %d due to (%s)...dropping block", payload.SeqNum, err)
-					continue
+			// Collect all subsequent payloads, announcing each block to the
+			// committer before committing the previous one
+			var next *common.Block
+			for rawblock := s.popBlock(); rawblock != nil; rawblock = next {
+				next = s.popBlock()
+				if prevalidator, isPrevalidator := s.committer.(committer.Prevalidator); isPrevalidator && next != nil {
+					prevalidator.Prevalidate(next)
 				}
 
-				logger.Debug("New block with sequence number ", payload.SeqNum, " transactions num ", len(rawblock.Data.Data))
-				s.commitBlock(rawblock)
+				logger.Debug("New block with sequence number ", rawblock.Header.Number, " transactions num ", len(rawblock.Data.Data))
+				if err := s.commitBlock(rawblock); err != nil {
+					if _, isMismatch := err.(*committer.BlockMismatchError); isMismatch {
+						s.resync()
+						break
+					}
+				}
 			}
 		case <-s.stopCh:
 			s.stopCh <- struct{}{}
@@ -440,31 +459,91 @@ func (s *GossipStateProviderImpl) deliverPayloads() {
 	}
 }
 
+// popBlock returns the next block of the payloads buffer, dropping the
+// payloads which can't be decoded, or nil if the next one didn't arrive yet
+func (s *GossipStateProviderImpl) popBlock() *common.Block {
+	for payload := s.payloads.Pop(); payload != nil; payload = s.payloads.Pop() {
+		rawblock := &common.Block{}
+		if err := pb.Unmarshal(payload.Data, rawblock); err != nil {
+			logger.Errorf("Error getting block with seqNum = %d due to (%s)...dropping block", payload.SeqNum, err)
+			continue
+		}
+		return rawblock
+	}
+	return nil
+}
+
 func (s *GossipStateProviderImpl) antiEntropy() {
 	defer s.done.Done()
 	defer logger.Debug("State Provider stopped, stopping anti entropy procedure.")
 
+	var lastStats PayloadsBufferStats
 	for {
 		select {
 		case <-s.stopCh:
 			s.stopCh <- struct{}{}
 			return
 		case <-time.After(defAntiEntropyInterval):
-			current, err := s.committer.LedgerHeight()
-			if err != nil {
-				// Unable to read from ledger continue to the next round
-				logger.Error("Cannot obtain ledger height, due to", err)
-				continue
-			}
-			max := s.maxAvailableLedgerHeight()
+		case <-s.resyncCh:
+			logger.Warningf("Channel [%s]: resynchronizing the ledger with the other peers", s.chainID)
+		}
 
-			if current == max {
-				continue
-			}
+		current, err := s.committer.LedgerHeight()
+		if err != nil {
+			// Unable to read from ledger continue to the next round
+			logger.Error("Cannot obtain ledger height, due to", err)
+			continue
+		}
+		lastStats = s.reportBufferStats(lastStats)
+
+		max := s.maxAvailableLedgerHeight()
+
+		if current == max {
+			continue
+		}
 
-			s.requestBlocksInRange(uint64(current), uint64(max))
+		// Do not request more blocks than the buffer can hold,
+		// they would be dropped until the committer catches up
+		if capacity := uint64(lastStats.Capacity); max > current+capacity {
+			max = current + capacity
 		}
+
+		s.requestBlocksInRange(uint64(current), uint64(max))
+	}
+}
+
+// resync drops the buffered payloads after a block which doesn't extend the
+// chain of the ledger was refused, so that they are fetched again from the
+// ledger height, and triggers an anti entropy round to get them from the
+// other peers
+func (s *GossipStateProviderImpl) resync() {
+	height, err := s.committer.LedgerHeight()
+	if err != nil {
+		logger.Error("Cannot obtain ledger height, due to", err)
+		return
+	}
+	logger.Warningf("Channel [%s]: dropping the buffered blocks, expecting block %d next", s.chainID, height)
+	s.payloads.Reset(height)
+
+	select {
+	case s.resyncCh <- struct{}{}:
+	default:
+	}
+}
+
+// reportBufferStats logs the payloads buffer usage, warning whenever
+// payloads got dropped or delayed since the previous report
+func (s *GossipStateProviderImpl) reportBufferStats(prev PayloadsBufferStats) PayloadsBufferStats {
+	stats := s.payloads.Stats()
+	recordBufferStats(s.chainID, stats)
+	logger.Debugf("Channel [%s]: payloads buffer holds %d/%d blocks, dropped %d, throttled %d for %s",
+		s.chainID, stats.Size, stats.Capacity, stats.Dropped, stats.Throttled, stats.ThrottledTime)
+	if stats.Dropped > prev.Dropped || stats.Throttled > prev.Throttled {
+		logger.Warningf("Channel [%s]: committer is falling behind, payloads buffer holds %d/%d blocks, "+
+			"%d blocks dropped and %d blocks delayed for %s since last report", s.chainID, stats.Size, stats.Capacity,
+			stats.Dropped-prev.Dropped, stats.Throttled-prev.Throttled, stats.ThrottledTime-prev.ThrottledTime)
 	}
+	return stats
 }
 
 // Iterate over all available peers and check advertised meta state to
@@ -610,7 +689,9 @@ func (s *GossipStateProviderImpl) GetBlock(index uint64) *common.Block {
 func (s *GossipStateProviderImpl) AddPayload(payload *proto.Payload) error {
 
 	logger.Debug("Adding new payload into the buffer, seqNum = ", payload.SeqNum)
-	return s.payloads.Push(payload)
+	// Blocks until there is room in the buffer, slowing down the
+	// delivery of blocks when the committer falls behind
+	return s.payloads.BlockingPush(payload)
 }
 
 func (s *GossipStateProviderImpl) commitBlock(block *common.Block) error {
@@ -191,13 +191,30 @@ func newGossipInstance(config *gossip.Config, mcs api.MessageCryptoService) goss
 	return gossip.NewGossipServiceWithServer(config, &orgCryptoService{}, mcs, idMapper, []byte(config.InternalEndpoint))
 }
 
+// genesisDataHash replaces the data hash of the genesis blocks of the peers,
+// which create their ledgers from different genesis blocks, so that the
+// same blocks extend the chains of all of them
+var genesisDataHash = []byte("genesis")
+
 // Create new instance of KVLedger to be used for testing
 func newCommitter(id int) committer.Committer {
 	cb, _ := test.MakeGenesisBlock(strconv.Itoa(id))
+	cb.Header.DataHash = genesisDataHash
 	ledger, _ := ledgermgmt.CreateLedger(cb)
 	return committer.NewLedgerCommitter(ledger, &validator.MockValidator{})
 }
 
+// newBlocks creates the blocks 1 to count extending the chains of the peers
+func newBlocks(count int) []*pcomm.Block {
+	previousHash := (&pcomm.BlockHeader{DataHash: genesisDataHash}).Hash()
+	blocks := make([]*pcomm.Block, count)
+	for i := range blocks {
+		blocks[i] = pcomm.NewBlock(uint64(i+1), previousHash)
+		previousHash = blocks[i].Header.Hash()
+	}
+	return blocks
+}
+
 // Constructing pseudo peer node, simulating only gossip and state transfer part
 func newPeerNode(config *gossip.Config, committer committer.Committer, acceptor peerIdentityAcceptor) *peerNode {
 	cs := &cryptoServiceMock{acceptor: acceptor}
@@ -254,10 +271,9 @@ func TestAccessControl(t *testing.T) {
 
 	msgCount := 5
 
-	for i := 1; i <= msgCount; i++ {
-		rawblock := pcomm.NewBlock(uint64(i), []byte{})
+	for _, rawblock := range newBlocks(msgCount) {
 		if b, err := pb.Marshal(rawblock); err == nil {
-			payload := &proto.Payload{uint64(i), "", b}
+			payload := &proto.Payload{rawblock.Header.Number, "", b}
 			bootstrapSet[0].s.AddPayload(payload)
 		} else {
 			t.Fail()
@@ -410,10 +426,9 @@ func TestNewGossipStateProvider_SendingManyMessages(t *testing.T) {
 
 	msgCount := 10
 
-	for i := 1; i <= msgCount; i++ {
-		rawblock := pcomm.NewBlock(uint64(i), []byte{})
+	for _, rawblock := range newBlocks(msgCount) {
 		if b, err := pb.Marshal(rawblock); err == nil {
-			payload := &proto.Payload{uint64(i), "", b}
+			payload := &proto.Payload{rawblock.Header.Number, "", b}
 			bootstrapSet[0].s.AddPayload(payload)
 		} else {
 			t.Fail()
@@ -542,10 +557,9 @@ func TestNewGossipStateProvider_BatchingOfStateRequest(t *testing.T) {
 	msgCount := defAntiEntropyBatchSize + 5
 	expectedMessagesCnt := 2
 
-	for i := 1; i <= msgCount; i++ {
-		rawblock := pcomm.NewBlock(uint64(i), []byte{})
+	for _, rawblock := range newBlocks(msgCount) {
 		if b, err := pb.Marshal(rawblock); err == nil {
-			payload := &proto.Payload{uint64(i), "", b}
+			payload := &proto.Payload{rawblock.Header.Number, "", b}
 			bootPeer.s.AddPayload(payload)
 		} else {
 			t.Fail()
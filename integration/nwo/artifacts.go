@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nwo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/hyperledger/fabric/common/configtx"
+	genesisconfig "github.com/hyperledger/fabric/common/configtx/tool/localconfig"
+	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
+	"github.com/hyperledger/fabric/common/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// ordererConfig returns the orderer section of the profiles of the network,
+// with the orderer organization only for the genesis block
+func (n *Network) ordererConfig(withOrgs bool) *genesisconfig.Orderer {
+	orderer := &genesisconfig.Orderer{
+		OrdererType:  provisional.ConsensusTypeSolo,
+		Addresses:    []string{n.OrdererAddress()},
+		BatchTimeout: time.Second,
+		BatchSize: genesisconfig.BatchSize{
+			MaxMessageCount:   10,
+			AbsoluteMaxBytes:  100000000,
+			PreferredMaxBytes: 512 * 1024,
+		},
+	}
+	if withOrgs {
+		orderer.Organizations = []*genesisconfig.Organization{n.orgConfig(&n.Config.OrdererOrg, false)}
+	}
+	return orderer
+}
+
+// orgConfig returns the profile organization of org, with the first peer
+// of the org as anchor peer if requested
+func (n *Network) orgConfig(org *Organization, withAnchorPeer bool) *genesisconfig.Organization {
+	orgConfig := &genesisconfig.Organization{
+		Name:   org.Name,
+		ID:     org.MSPID,
+		MSPDir: n.OrgMSPDir(org),
+	}
+	if peers := n.PeersOf(org.Name); withAnchorPeer && len(peers) > 0 {
+		orgConfig.AnchorPeers = []*genesisconfig.AnchorPeer{{Host: n.Config.Host, Port: peers[0].Port}}
+	}
+	return orgConfig
+}
+
+func (n *Network) peerOrgConfigs() []*genesisconfig.Organization {
+	var orgs []*genesisconfig.Organization
+	for i := range n.Config.PeerOrgs {
+		orgs = append(orgs, n.orgConfig(&n.Config.PeerOrgs[i], true))
+	}
+	return orgs
+}
+
+// genesisProfile returns the profile of the orderer system channel, whose
+// consortium is made of the peer organizations
+func (n *Network) genesisProfile() *genesisconfig.Profile {
+	return &genesisconfig.Profile{
+		Orderer:     n.ordererConfig(true),
+		Application: &genesisconfig.Application{Organizations: n.peerOrgConfigs()},
+		Consortiums: map[string]*genesisconfig.Consortium{
+			n.Config.Consortium: {
+				Organizations:         n.peerOrgConfigs(),
+				ChannelCreationPolicy: cb.ImplicitMetaPolicy_ANY.String(),
+			},
+		},
+	}
+}
+
+// channelProfile returns the profile of the channels of the consortium
+func (n *Network) channelProfile() *genesisconfig.Profile {
+	return &genesisconfig.Profile{
+		Consortium:  n.Config.Consortium,
+		Orderer:     n.ordererConfig(false),
+		Application: &genesisconfig.Application{Organizations: n.peerOrgConfigs()},
+	}
+}
+
+func (n *Network) writeGenesisBlock() error {
+	block := provisional.New(n.genesisProfile()).GenesisBlockForChannel(n.SystemChannel())
+	return ioutil.WriteFile(n.GenesisBlockPath(), utils.MarshalOrPanic(block), 0644)
+}
+
+// ChannelCreationTx returns the creation transaction of the channel, signed
+// by the admins of all the peer organizations so that it satisfies any
+// channel creation policy of the consortium.
+func (n *Network) ChannelCreationTx(channel string) (*cb.Envelope, error) {
+	if len(n.Config.PeerOrgs) == 0 {
+		return nil, fmt.Errorf("No peer organization to create channel %s", channel)
+	}
+	template := provisional.New(n.channelProfile()).ChannelTemplate()
+	configUpdateEnv, err := configtx.NewChainCreationTemplate(provisional.AcceptAllPolicyKey, template).Envelope(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	var admins []*signer
+	for i := range n.Config.PeerOrgs {
+		org := &n.Config.PeerOrgs[i]
+		admin, err := newSigner(n.AdminMSPDir(org), org.MSPID)
+		if err != nil {
+			return nil, err
+		}
+		admins = append(admins, admin)
+
+		sigHeader, err := admin.NewSignatureHeader()
+		if err != nil {
+			return nil, err
+		}
+		configSig := &cb.ConfigSignature{SignatureHeader: utils.MarshalOrPanic(sigHeader)}
+		configSig.Signature, err = admin.Sign(util.ConcatenateBytes(configSig.SignatureHeader, configUpdateEnv.ConfigUpdate))
+		if err != nil {
+			return nil, err
+		}
+		configUpdateEnv.Signatures = append(configUpdateEnv.Signatures, configSig)
+	}
+
+	return utils.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, channel, admins[0], configUpdateEnv, 0, 0)
+}
+
+func (n *Network) writeChannelCreationTx(channel string) error {
+	env, err := n.ChannelCreationTx(channel)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(n.ChannelTxPath(channel), utils.MarshalOrPanic(env), 0644)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nwo
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Chaincode is a chaincode deployed on the channels of a network.
+type Chaincode struct {
+	Name    string
+	Version string
+	// Path is the import path of the chaincode in the GOPATH.
+	Path string
+	// Ctor is the JSON of the args of the init of the chaincode, e.g.
+	// {"Args":["init","a","100"]}.
+	Ctor string
+	// Policy is the endorsement policy of the chaincode, if not the
+	// default one.
+	Policy string
+}
+
+// PeerCommand runs the peer CLI against the peer as the admin of its
+// organization, from the root dir of the network, and returns its output.
+func (n *Network) PeerCommand(p *Peer, args ...string) (string, error) {
+	org := p.Organization
+	cmd := exec.Command(n.Components.Peer, args...)
+	cmd.Dir = n.RootDir
+	cmd.Env = append(os.Environ(),
+		"FABRIC_CFG_PATH="+n.Components.ConfigDir,
+		"CORE_PEER_ADDRESS="+n.PeerAddress(p),
+		"CORE_PEER_MSPCONFIGPATH="+n.AdminMSPDir(org),
+		"CORE_PEER_LOCALMSPID="+org.MSPID,
+	)
+
+	logger.Debugf("Running peer %s against %s", strings.Join(args, " "), p.Name)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("Error running peer %s against %s: %s\n%s", strings.Join(args, " "), p.Name, err, output)
+	}
+	return string(output), nil
+}
+
+// CreateChannel submits the creation transaction of the channel to the
+// orderer through the first peer, which writes the genesis block of the
+// channel to ChannelBlockPath.
+func (n *Network) CreateChannel(channel string) error {
+	if len(n.Peers) == 0 {
+		return fmt.Errorf("No peer to create channel %s", channel)
+	}
+	_, err := n.PeerCommand(n.Peers[0], "channel", "create", "-c", channel, "-f", n.ChannelTxPath(channel), "-o", n.OrdererAddress())
+	return err
+}
+
+// JoinChannel joins the peers to the created channel.
+func (n *Network) JoinChannel(channel string, peers ...*Peer) error {
+	for _, p := range peers {
+		if _, err := n.PeerCommand(p, "channel", "join", "-b", n.ChannelBlockPath(channel)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateAndJoinChannel creates the channel and joins all the peers to it.
+func (n *Network) CreateAndJoinChannel(channel string) error {
+	if err := n.CreateChannel(channel); err != nil {
+		return err
+	}
+	return n.JoinChannel(channel, n.Peers...)
+}
+
+// InstallChaincode installs the chaincode on the peers.
+func (n *Network) InstallChaincode(cc Chaincode, peers ...*Peer) error {
+	for _, p := range peers {
+		if _, err := n.PeerCommand(p, "chaincode", "install", "-n", cc.Name, "-v", cc.Version, "-p", cc.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InstantiateChaincode instantiates the installed chaincode on the channel
+// through the peer.
+func (n *Network) InstantiateChaincode(channel string, cc Chaincode, p *Peer) error {
+	args := []string{"chaincode", "instantiate", "-o", n.OrdererAddress(), "-C", channel, "-n", cc.Name, "-v", cc.Version, "-c", cc.Ctor}
+	if cc.Policy != "" {
+		args = append(args, "-P", cc.Policy)
+	}
+	_, err := n.PeerCommand(p, args...)
+	return err
+}
+
+// DeployChaincode installs the chaincode on all the peers and instantiates
+// it on the channel through the first one.
+func (n *Network) DeployChaincode(channel string, cc Chaincode) error {
+	if len(n.Peers) == 0 {
+		return fmt.Errorf("No peer to deploy chaincode %s", cc.Name)
+	}
+	if err := n.InstallChaincode(cc, n.Peers...); err != nil {
+		return err
+	}
+	return n.InstantiateChaincode(channel, cc, n.Peers[0])
+}
+
+// QueryChaincode queries the chaincode on the channel through the peer and
+// returns the output of the CLI.
+func (n *Network) QueryChaincode(channel, name, ctor string, p *Peer) (string, error) {
+	return n.PeerCommand(p, "chaincode", "query", "-C", channel, "-n", name, "-c", ctor)
+}
+
+// InvokeChaincode invokes the chaincode on the channel through the peer and
+// submits the transaction to the orderer.
+func (n *Network) InvokeChaincode(channel, name, ctor string, p *Peer) (string, error) {
+	return n.PeerCommand(p, "chaincode", "invoke", "-o", n.OrdererAddress(), "-C", channel, "-n", name, "-c", ctor)
+}
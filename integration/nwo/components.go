@@ -0,0 +1,107 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nwo
+
+import (
+	"fmt"
+	"go/build"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	fabricPackage       = "github.com/hyperledger/fabric"
+	peerPackage         = "github.com/hyperledger/fabric/peer"
+	ordererPackage      = "github.com/hyperledger/fabric/orderer"
+	sampleConfigPackage = "github.com/hyperledger/fabric/sampleconfig"
+	metadataPackage     = "github.com/hyperledger/fabric/common/metadata"
+)
+
+// BuildVersion is the version the components are built with, the system
+// chaincodes of the peer can't be deployed without one.
+var BuildVersion = "1.0.0-nwo"
+
+// Components are the binaries, and the directory of the core.yaml and
+// orderer.yaml they are configured from, the nodes of a network run.
+type Components struct {
+	Peer      string
+	Orderer   string
+	ConfigDir string
+}
+
+// BuildComponents builds the peer and the orderer of the GOPATH in
+// outputDir, and configures them from the sampleconfig of the GOPATH.
+func BuildComponents(outputDir string) (*Components, error) {
+	configDir, err := packageDir(sampleConfigPackage)
+	if err != nil {
+		return nil, err
+	}
+	ldflags, err := buildLDFlags()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Components{
+		Peer:      filepath.Join(outputDir, "peer"),
+		Orderer:   filepath.Join(outputDir, "orderer"),
+		ConfigDir: configDir,
+	}
+	for binary, pkg := range map[string]string{c.Peer: peerPackage, c.Orderer: ordererPackage} {
+		logger.Infof("Building %s", pkg)
+		output, err := exec.Command("go", "build", "-ldflags", ldflags, "-o", binary, pkg).CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("Error building %s: %s\n%s", pkg, err, output)
+		}
+	}
+	return c, nil
+}
+
+func packageDir(pkg string) (string, error) {
+	p, err := build.Import(pkg, "", build.FindOnly)
+	if err != nil {
+		return "", fmt.Errorf("Error locating %s: %s", pkg, err)
+	}
+	return p.Dir, nil
+}
+
+// buildLDFlags sets the metadata the Makefile sets, the base image release
+// of the chaincode containers is read from the .baseimage-release of the repo
+func buildLDFlags() (string, error) {
+	fabricDir, err := packageDir(fabricPackage)
+	if err != nil {
+		return "", err
+	}
+	baseVersion, err := ioutil.ReadFile(filepath.Join(fabricDir, ".baseimage-release"))
+	if err != nil {
+		return "", err
+	}
+
+	metadata := []string{
+		"Version=" + BuildVersion,
+		"BaseVersion=" + strings.TrimSpace(string(baseVersion)),
+		"BaseDockerLabel=org.hyperledger.fabric",
+		"DockerNamespace=hyperledger",
+		"BaseDockerNamespace=hyperledger",
+	}
+	var flags []string
+	for _, m := range metadata {
+		flags = append(flags, "-X "+metadataPackage+"."+m)
+	}
+	return strings.Join(flags, " "), nil
+}
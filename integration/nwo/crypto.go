@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nwo
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/common/tools/cryptogen/ca"
+	cryptomsp "github.com/hyperledger/fabric/common/tools/cryptogen/msp"
+)
+
+func adminUserName(org *Organization) string {
+	return "Admin@" + org.Domain
+}
+
+// generateCrypto lays out the crypto material of the organizations the way
+// cryptogen does: a CA and a verifying MSP per organization, and a local
+// MSP per node and user, whose admincerts hold the cert of the org admin.
+func (n *Network) generateCrypto() error {
+	var ordererNames []string
+	if n.Orderer != nil {
+		ordererNames = []string{n.Orderer.Name}
+	}
+	if err := n.generateOrg(&n.Config.OrdererOrg, "orderers", ordererNames); err != nil {
+		return err
+	}
+
+	for i := range n.Config.PeerOrgs {
+		org := &n.Config.PeerOrgs[i]
+		var peerNames []string
+		for _, p := range n.PeersOf(org.Name) {
+			peerNames = append(peerNames, p.Name)
+		}
+		if err := n.generateOrg(org, "peers", peerNames); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *Network) generateOrg(org *Organization, nodesDirName string, nodeNames []string) error {
+	orgDir := n.orgDir(org)
+	rootCA, err := ca.NewCA(filepath.Join(orgDir, "ca"), org.Domain)
+	if err != nil {
+		return fmt.Errorf("Error generating CA for org %s: %s", org.Name, err)
+	}
+	mspDir := n.OrgMSPDir(org)
+	if err = cryptomsp.GenerateVerifyingMSP(mspDir, rootCA); err != nil {
+		return fmt.Errorf("Error generating MSP for org %s: %s", org.Name, err)
+	}
+
+	users := []string{adminUserName(org)}
+	for i := 1; i <= org.Users; i++ {
+		users = append(users, fmt.Sprintf("User%d@%s", i, org.Domain))
+	}
+
+	var localMSPDirs []string
+	for _, name := range nodeNames {
+		localMSPDirs = append(localMSPDirs, filepath.Join(orgDir, nodesDirName, name))
+	}
+	for _, user := range users {
+		localMSPDirs = append(localMSPDirs, n.UserMSPDir(org, user))
+	}
+	names := append(append([]string{}, nodeNames...), users...)
+	for i, dir := range localMSPDirs {
+		if err = cryptomsp.GenerateLocalMSP(dir, names[i], rootCA); err != nil {
+			return fmt.Errorf("Error generating local MSP for %s: %s", names[i], err)
+		}
+	}
+
+	adminCert := filepath.Join(n.AdminMSPDir(org), "signcerts", adminUserName(org)+"-cert.pem")
+	for _, dir := range append(localMSPDirs, mspDir) {
+		if err = copyAdminCert(adminCert, filepath.Join(dir, "admincerts")); err != nil {
+			return fmt.Errorf("Error copying admin cert of org %s: %s", org.Name, err)
+		}
+	}
+	return nil
+}
+
+// copyAdminCert replaces the content of the admincerts dir by the admin cert
+func copyAdminCert(adminCert, adminCertsDir string) error {
+	raw, err := ioutil.ReadFile(adminCert)
+	if err != nil {
+		return err
+	}
+	if err = os.RemoveAll(adminCertsDir); err != nil {
+		return err
+	}
+	if err = os.MkdirAll(adminCertsDir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(adminCertsDir, filepath.Base(adminCert)), raw, 0644)
+}
@@ -0,0 +1,253 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nwo orchestrates Fabric networks for the integration tests: it
+// generates the crypto material and the channel artifacts of a network,
+// runs its orderer and peers as local processes, and drives them through the
+// peer CLI to create channels and deploy chaincodes.
+package nwo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
+	"github.com/hyperledger/fabric/common/flogging"
+)
+
+var logger = flogging.MustGetLogger("integration/nwo")
+
+const (
+	// DefaultBasePort is the first port allocated to the nodes of a network
+	// if the config doesn't set one.
+	DefaultBasePort = 27050
+
+	// DefaultHost is the host the nodes of a network advertise if the config
+	// doesn't set one.
+	DefaultHost = "127.0.0.1"
+)
+
+// Config describes the network to orchestrate.
+type Config struct {
+	// OrdererOrg is the organization of the solo orderer.
+	OrdererOrg Organization
+	// PeerOrgs are the organizations of the peers, all members of the
+	// consortium.
+	PeerOrgs []Organization
+	// Consortium is the name of the consortium of the peer organizations.
+	Consortium string
+	// Channels are the channels the peer organizations may create.
+	Channels []string
+	// Host is the host the nodes advertise to each other and to the
+	// chaincodes. Chaincode containers can't reach the loopback address of
+	// the host, set an address reachable from docker to run chaincodes.
+	Host string
+	// BasePort is the first of the consecutive ports allocated to the nodes.
+	BasePort int
+}
+
+// Organization describes an organization of the network.
+type Organization struct {
+	// Name is the name of the organization in the channel config.
+	Name string
+	// MSPID is the MSP ID of the organization.
+	MSPID string
+	// Domain is the domain of the organization, its nodes and users are
+	// named after it.
+	Domain string
+	// Peers is the number of peers of the organization. It must be 0 for
+	// the orderer organization.
+	Peers int
+	// Users is the number of users of the organization besides its admin.
+	Users int
+}
+
+// BasicSolo returns the config of a network of a solo orderer and two
+// organizations of one peer each, which may create the channel testchannel.
+func BasicSolo() Config {
+	return Config{
+		OrdererOrg: Organization{Name: "OrdererOrg", MSPID: "OrdererMSP", Domain: "example.com"},
+		PeerOrgs: []Organization{
+			{Name: "Org1", MSPID: "Org1MSP", Domain: "org1.example.com", Peers: 1, Users: 1},
+			{Name: "Org2", MSPID: "Org2MSP", Domain: "org2.example.com", Peers: 1, Users: 1},
+		},
+		Consortium: "SampleConsortium",
+		Channels:   []string{"testchannel"},
+	}
+}
+
+// Orderer is the solo orderer of a network.
+type Orderer struct {
+	Name string
+	Port int
+}
+
+// Peer is a peer of a network.
+type Peer struct {
+	Name         string
+	Organization *Organization
+	Port         int
+	EventsPort   int
+}
+
+// Network is a Fabric network generated and run from a directory.
+type Network struct {
+	RootDir    string
+	Config     Config
+	Components *Components
+
+	Orderer *Orderer
+	Peers   []*Peer
+
+	processes []*Process
+}
+
+// New returns the network of the config, whose artifacts and ledgers are
+// kept under rootDir and whose nodes are run from the binaries of components.
+func New(c Config, rootDir string, components *Components) *Network {
+	if c.Host == "" {
+		c.Host = DefaultHost
+	}
+	if c.BasePort == 0 {
+		c.BasePort = DefaultBasePort
+	}
+
+	n := &Network{
+		RootDir:    rootDir,
+		Config:     c,
+		Components: components,
+	}
+
+	port := c.BasePort
+	n.Orderer = &Orderer{Name: "orderer." + c.OrdererOrg.Domain, Port: port}
+	port++
+	for i := range n.Config.PeerOrgs {
+		org := &n.Config.PeerOrgs[i]
+		for j := 0; j < org.Peers; j++ {
+			n.Peers = append(n.Peers, &Peer{
+				Name:         fmt.Sprintf("peer%d.%s", j, org.Domain),
+				Organization: org,
+				Port:         port,
+				EventsPort:   port + 1,
+			})
+			port += 2
+		}
+	}
+
+	return n
+}
+
+// Generate generates the crypto material of the organizations, the genesis
+// block of the orderer and the creation transactions of the channels.
+func (n *Network) Generate() error {
+	if err := os.MkdirAll(n.RootDir, 0755); err != nil {
+		return err
+	}
+	if err := n.generateCrypto(); err != nil {
+		return fmt.Errorf("Error generating crypto material: %s", err)
+	}
+	if err := n.writeGenesisBlock(); err != nil {
+		return fmt.Errorf("Error generating genesis block: %s", err)
+	}
+	for _, channel := range n.Config.Channels {
+		if err := n.writeChannelCreationTx(channel); err != nil {
+			return fmt.Errorf("Error generating creation transaction of channel %s: %s", channel, err)
+		}
+	}
+	return nil
+}
+
+// SystemChannel returns the ID of the orderer system channel.
+func (n *Network) SystemChannel() string {
+	return provisional.TestChainID
+}
+
+// OrdererAddress returns the address the orderer is reached at.
+func (n *Network) OrdererAddress() string {
+	return fmt.Sprintf("%s:%d", n.Config.Host, n.Orderer.Port)
+}
+
+// PeerAddress returns the address the peer is reached at.
+func (n *Network) PeerAddress(p *Peer) string {
+	return fmt.Sprintf("%s:%d", n.Config.Host, p.Port)
+}
+
+// PeersOf returns the peers of the organization of the given name.
+func (n *Network) PeersOf(orgName string) []*Peer {
+	var peers []*Peer
+	for _, p := range n.Peers {
+		if p.Organization.Name == orgName {
+			peers = append(peers, p)
+		}
+	}
+	return peers
+}
+
+// GenesisBlockPath returns the path of the genesis block of the orderer.
+func (n *Network) GenesisBlockPath() string {
+	return filepath.Join(n.RootDir, n.SystemChannel()+".block")
+}
+
+// ChannelTxPath returns the path of the creation transaction of the channel.
+func (n *Network) ChannelTxPath(channel string) string {
+	return filepath.Join(n.RootDir, channel+".tx")
+}
+
+// ChannelBlockPath returns the path of the genesis block of the channel,
+// written once the channel is created.
+func (n *Network) ChannelBlockPath(channel string) string {
+	return filepath.Join(n.RootDir, channel+".block")
+}
+
+// OrgMSPDir returns the directory of the verifying MSP of the organization.
+func (n *Network) OrgMSPDir(org *Organization) string {
+	return filepath.Join(n.orgDir(org), "msp")
+}
+
+// OrdererMSPDir returns the directory of the local MSP of the orderer.
+func (n *Network) OrdererMSPDir() string {
+	return filepath.Join(n.orgDir(&n.Config.OrdererOrg), "orderers", n.Orderer.Name)
+}
+
+// PeerMSPDir returns the directory of the local MSP of the peer.
+func (n *Network) PeerMSPDir(p *Peer) string {
+	return filepath.Join(n.orgDir(p.Organization), "peers", p.Name)
+}
+
+// AdminMSPDir returns the directory of the local MSP of the admin of the
+// organization.
+func (n *Network) AdminMSPDir(org *Organization) string {
+	return n.UserMSPDir(org, adminUserName(org))
+}
+
+// UserMSPDir returns the directory of the local MSP of the user of the
+// organization, as named by cryptogen, e.g. User1@org1.example.com.
+func (n *Network) UserMSPDir(org *Organization, user string) string {
+	return filepath.Join(n.orgDir(org), "users", user)
+}
+
+func (n *Network) orgDir(org *Organization) string {
+	kind := "peerOrganizations"
+	if org == &n.Config.OrdererOrg {
+		kind = "ordererOrganizations"
+	}
+	return filepath.Join(n.RootDir, "crypto", kind, org.Domain)
+}
+
+func (n *Network) nodeDir(name string) string {
+	return filepath.Join(n.RootDir, "nodes", name)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nwo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/configtx"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAllocatesPorts(t *testing.T) {
+	n := New(BasicSolo(), "/tmp/nwo", nil)
+
+	assert.Equal(t, DefaultBasePort, n.Orderer.Port)
+	assert.Equal(t, "127.0.0.1:27050", n.OrdererAddress())
+	if assert.Len(t, n.Peers, 2) {
+		assert.Equal(t, "peer0.org1.example.com", n.Peers[0].Name)
+		assert.Equal(t, 27051, n.Peers[0].Port)
+		assert.Equal(t, 27052, n.Peers[0].EventsPort)
+		assert.Equal(t, "peer0.org2.example.com", n.Peers[1].Name)
+		assert.Equal(t, "127.0.0.1:27053", n.PeerAddress(n.Peers[1]))
+	}
+	assert.Equal(t, []*Peer{n.Peers[1]}, n.PeersOf("Org2"))
+}
+
+func TestGenerate(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "nwo")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	n := New(BasicSolo(), rootDir, nil)
+	assert.NoError(t, n.Generate())
+
+	for _, dir := range []string{n.OrdererMSPDir(), n.PeerMSPDir(n.Peers[0]), n.AdminMSPDir(n.Peers[1].Organization)} {
+		admincerts, err := ioutil.ReadDir(filepath.Join(dir, "admincerts"))
+		assert.NoError(t, err)
+		assert.Len(t, admincerts, 1, "admin certs of %s", dir)
+	}
+	_, err = os.Stat(n.UserMSPDir(&n.Config.PeerOrgs[0], "User1@org1.example.com"))
+	assert.NoError(t, err)
+
+	raw, err := ioutil.ReadFile(n.GenesisBlockPath())
+	assert.NoError(t, err)
+	block, err := utils.GetBlockFromBlockBytes(raw)
+	assert.NoError(t, err)
+	chainID, err := utils.GetChainIDFromBlock(block)
+	assert.NoError(t, err)
+	assert.Equal(t, n.SystemChannel(), chainID)
+
+	raw, err = ioutil.ReadFile(n.ChannelTxPath("testchannel"))
+	assert.NoError(t, err)
+	env, err := utils.UnmarshalEnvelope(raw)
+	assert.NoError(t, err)
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	assert.NoError(t, err)
+	ch, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(cb.HeaderType_CONFIG_UPDATE), ch.Type)
+	assert.Equal(t, "testchannel", ch.ChannelId)
+	configUpdateEnv, err := configtx.UnmarshalConfigUpdateEnvelope(payload.Data)
+	assert.NoError(t, err)
+	assert.Len(t, configUpdateEnv.Signatures, 2, "the admins of both orgs should sign")
+}
+
+// TestCreateChannel runs a network and creates and joins its channel, it
+// builds the binaries and is only run if FABRIC_NWO_TEST is set.
+func TestCreateChannel(t *testing.T) {
+	if os.Getenv("FABRIC_NWO_TEST") == "" {
+		t.Skip("FABRIC_NWO_TEST not set")
+	}
+
+	rootDir, err := ioutil.TempDir("", "nwo")
+	assert.NoError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	components, err := BuildComponents(filepath.Join(rootDir, "bin"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	n := New(BasicSolo(), rootDir, components)
+	assert.NoError(t, n.Generate())
+	if !assert.NoError(t, n.Start()) {
+		return
+	}
+	defer n.Stop()
+
+	assert.NoError(t, n.CreateAndJoinChannel("testchannel"))
+	output, err := n.PeerCommand(n.Peers[1], "channel", "list")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "testchannel")
+}
@@ -0,0 +1,189 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nwo
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+var (
+	// StartTimeout is how long a node is given to listen once started.
+	StartTimeout = 30 * time.Second
+
+	// StopTimeout is how long a node is given to exit once signaled before
+	// it is killed.
+	StopTimeout = 10 * time.Second
+)
+
+// Process is a node of the network run as a local process, whose output
+// is written to the log file of the node.
+type Process struct {
+	Name    string
+	LogFile string
+
+	cmd    *exec.Cmd
+	log    *os.File
+	exited chan struct{}
+	err    error
+}
+
+// startProcess runs the binary with the environment overrides, and waits
+// for it to listen on address
+func (n *Network) startProcess(name, binary, address string, env []string, args ...string) (*Process, error) {
+	if err := os.MkdirAll(n.nodeDir(name), 0755); err != nil {
+		return nil, err
+	}
+	logFile := filepath.Join(n.RootDir, name+".log")
+	log, err := os.Create(logFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = n.nodeDir(name)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = log
+	cmd.Stderr = log
+	if err = cmd.Start(); err != nil {
+		log.Close()
+		return nil, fmt.Errorf("Error starting %s: %s", name, err)
+	}
+
+	p := &Process{Name: name, LogFile: logFile, cmd: cmd, log: log, exited: make(chan struct{})}
+	go func() {
+		p.err = cmd.Wait()
+		log.Close()
+		close(p.exited)
+	}()
+	n.processes = append(n.processes, p)
+
+	logger.Infof("Started %s, pid %d, logging to %s", name, cmd.Process.Pid, logFile)
+	if err = p.waitForListener(address); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Process) waitForListener(address string) error {
+	deadline := time.Now().Add(StartTimeout)
+	for {
+		select {
+		case <-p.exited:
+			return fmt.Errorf("%s exited before listening on %s: %v, see %s", p.Name, address, p.err, p.LogFile)
+		default:
+		}
+
+		conn, err := net.DialTimeout("tcp", address, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s not listening on %s after %s, see %s", p.Name, address, StartTimeout, p.LogFile)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// Stop signals the process to terminate, and kills it if it hasn't exited
+// after StopTimeout.
+func (p *Process) Stop() {
+	select {
+	case <-p.exited:
+		return
+	default:
+	}
+
+	p.cmd.Process.Signal(syscall.SIGTERM)
+	select {
+	case <-p.exited:
+	case <-time.After(StopTimeout):
+		logger.Warningf("%s didn't exit after %s, killing it", p.Name, StopTimeout)
+		p.cmd.Process.Kill()
+		<-p.exited
+	}
+}
+
+// Exited returns a channel closed once the process exited.
+func (p *Process) Exited() <-chan struct{} {
+	return p.exited
+}
+
+// StartOrderer starts the orderer from the genesis block of the network.
+func (n *Network) StartOrderer() (*Process, error) {
+	env := []string{
+		"FABRIC_CFG_PATH=" + n.Components.ConfigDir,
+		"ORDERER_GENERAL_LISTENADDRESS=0.0.0.0",
+		fmt.Sprintf("ORDERER_GENERAL_LISTENPORT=%d", n.Orderer.Port),
+		"ORDERER_GENERAL_GENESISMETHOD=file",
+		"ORDERER_GENERAL_GENESISFILE=" + n.GenesisBlockPath(),
+		"ORDERER_GENERAL_LOCALMSPDIR=" + n.OrdererMSPDir(),
+		"ORDERER_GENERAL_LOCALMSPID=" + n.Config.OrdererOrg.MSPID,
+		"ORDERER_GENERAL_LEDGERTYPE=file",
+		"ORDERER_FILELEDGER_LOCATION=" + filepath.Join(n.nodeDir(n.Orderer.Name), "ledger"),
+	}
+	return n.startProcess(n.Orderer.Name, n.Components.Orderer, n.OrdererAddress(), env)
+}
+
+// StartPeer starts the peer, as the leader of its organization.
+func (n *Network) StartPeer(p *Peer) (*Process, error) {
+	env := []string{
+		"FABRIC_CFG_PATH=" + n.Components.ConfigDir,
+		"CORE_PEER_ID=" + p.Name,
+		"CORE_PEER_ADDRESS=" + n.PeerAddress(p),
+		fmt.Sprintf("CORE_PEER_LISTENADDRESS=0.0.0.0:%d", p.Port),
+		fmt.Sprintf("CORE_PEER_EVENTS_ADDRESS=0.0.0.0:%d", p.EventsPort),
+		"CORE_PEER_GOSSIP_BOOTSTRAP=" + n.PeerAddress(p),
+		"CORE_PEER_GOSSIP_EXTERNALENDPOINT=" + n.PeerAddress(p),
+		"CORE_PEER_GOSSIP_USELEADERELECTION=false",
+		"CORE_PEER_GOSSIP_ORGLEADER=true",
+		"CORE_PEER_MSPCONFIGPATH=" + n.PeerMSPDir(p),
+		"CORE_PEER_LOCALMSPID=" + p.Organization.MSPID,
+		"CORE_PEER_FILESYSTEMPATH=" + filepath.Join(n.nodeDir(p.Name), "production"),
+	}
+	return n.startProcess(p.Name, n.Components.Peer, n.PeerAddress(p), env, "node", "start")
+}
+
+// Start starts the orderer and then all the peers of the network. The
+// nodes already started are stopped if one fails to start.
+func (n *Network) Start() error {
+	if _, err := n.StartOrderer(); err != nil {
+		n.Stop()
+		return err
+	}
+	for _, p := range n.Peers {
+		if _, err := n.StartPeer(p); err != nil {
+			n.Stop()
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop stops all the processes of the network, the last started first.
+func (n *Network) Stop() {
+	for i := len(n.processes) - 1; i >= 0; i-- {
+		n.processes[i].Stop()
+	}
+	n.processes = nil
+}
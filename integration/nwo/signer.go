@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nwo
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/utils"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	putils "github.com/hyperledger/fabric/protos/utils"
+)
+
+// signer signs with the key of a local MSP generated by the network, it
+// implements crypto.LocalSigner without going through the global MSP and
+// BCCSP of the process, so that several identities can sign.
+type signer struct {
+	creator []byte
+	key     *ecdsa.PrivateKey
+}
+
+// newSigner returns the signer of the identity of the local MSP in mspDir
+func newSigner(mspDir, mspID string) (*signer, error) {
+	cert, err := readSingleFile(filepath.Join(mspDir, "signcerts"))
+	if err != nil {
+		return nil, err
+	}
+	rawKey, err := readSingleFile(filepath.Join(mspDir, "keystore"))
+	if err != nil {
+		return nil, err
+	}
+	key, err := utils.PEMtoPrivateKey(rawKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("Key of %s is not an ECDSA key", mspDir)
+	}
+	creator, err := proto.Marshal(&mspprotos.SerializedIdentity{Mspid: mspID, IdBytes: cert})
+	if err != nil {
+		return nil, err
+	}
+	return &signer{creator: creator, key: ecdsaKey}, nil
+}
+
+func readSingleFile(dir string) ([]byte, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) != 1 {
+		return nil, fmt.Errorf("Expected a single file in %s, found %d", dir, len(files))
+	}
+	return ioutil.ReadFile(filepath.Join(dir, files[0].Name()))
+}
+
+// NewSignatureHeader creates a SignatureHeader with the identity of the
+// signer and a fresh nonce
+func (s *signer) NewSignatureHeader() (*cb.SignatureHeader, error) {
+	nonce, err := putils.CreateNonce()
+	if err != nil {
+		return nil, err
+	}
+	return &cb.SignatureHeader{Creator: s.creator, Nonce: nonce}, nil
+}
+
+// Sign signs the SHA-256 digest of the message the way the bccsp does,
+// with a low-S ECDSA signature
+func (s *signer) Sign(message []byte) ([]byte, error) {
+	digest := sha256.Sum256(message)
+	r, sig, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	halfOrder := new(big.Int).Rsh(s.key.Params().N, 1)
+	if sig.Cmp(halfOrder) == 1 {
+		sig.Sub(s.key.Params().N, sig)
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, sig})
+}
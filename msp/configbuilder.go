@@ -99,11 +99,18 @@ func SetupBCCSPKeystoreConfig(bccspConfig *factory.FactoryOpts, keystoreDir stri
 			bccspConfig.SwOpts = factory.DefaultOpts.SwOpts
 		}
 
+		// The Vault keystore is not backed by the local MSP folder
+		if bccspConfig.SwOpts.KeyStoreType == factory.VaultKeyStoreType {
+			return
+		}
+
 		// Only override the KeyStorePath if it was left empty
-		if bccspConfig.SwOpts.FileKeystore == nil ||
-			bccspConfig.SwOpts.FileKeystore.KeyStorePath == "" {
+		if bccspConfig.SwOpts.FileKeystore == nil {
 			bccspConfig.SwOpts.Ephemeral = false
 			bccspConfig.SwOpts.FileKeystore = &factory.FileKeystoreOpts{KeyStorePath: keystoreDir}
+		} else if bccspConfig.SwOpts.FileKeystore.KeyStorePath == "" {
+			bccspConfig.SwOpts.Ephemeral = false
+			bccspConfig.SwOpts.FileKeystore.KeyStorePath = keystoreDir
 		}
 	}
 }
@@ -0,0 +1,219 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enrollment
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric/bccsp"
+)
+
+// requestTimeout bounds the requests to the Fabric CA server
+var requestTimeout = 30 * time.Second
+
+// enrollmentRequest is the body of the enroll and reenroll requests of the
+// Fabric CA REST API
+type enrollmentRequest struct {
+	CertificateRequest string              `json:"certificate_request"`
+	Hosts              []string            `json:"hosts,omitempty"`
+	Profile            string              `json:"profile,omitempty"`
+	Label              string              `json:"label,omitempty"`
+	CAName             string              `json:"caname,omitempty"`
+	AttrReqs           []*attributeRequest `json:"attr_reqs,omitempty"`
+}
+
+type attributeRequest struct {
+	Name     string `json:"name"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// response is the envelope of the responses of the Fabric CA REST API
+type response struct {
+	Success bool            `json:"success"`
+	Result  json.RawMessage `json:"result"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// enrollmentResponse is the result of the enroll and reenroll requests, the
+// certificates are base64 encoded PEM
+type enrollmentResponse struct {
+	Cert       string `json:"Cert"`
+	ServerInfo struct {
+		CAName  string `json:"CAName"`
+		CAChain string `json:"CAChain"`
+	} `json:"ServerInfo"`
+}
+
+// enrollmentResult holds the PEM encoded certificates of an enrollment
+type enrollmentResult struct {
+	CertPEM    []byte
+	CAChainPEM []byte
+	// Cert is the DER encoded enrollment certificate
+	Cert []byte
+}
+
+// client sends the requests of the enrollment to the Fabric CA server
+type client struct {
+	conf       *Config
+	httpClient *http.Client
+}
+
+func newClient(conf *Config) (*client, error) {
+	if conf.URL == "" {
+		return nil, fmt.Errorf("The URL of the Fabric CA server must be set to enroll")
+	}
+
+	transport := &http.Transport{}
+	if strings.HasPrefix(conf.URL, "https://") && len(conf.TLS.CertFiles) > 0 {
+		roots := x509.NewCertPool()
+		for _, file := range conf.TLS.CertFiles {
+			raw, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("Failed reading the TLS root certificate of the Fabric CA server: %s", err)
+			}
+			if !roots.AppendCertsFromPEM(raw) {
+				return nil, fmt.Errorf("No certificate in the TLS root certificate file %s", file)
+			}
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: roots}
+	}
+	return &client{
+		conf:       conf,
+		httpClient: &http.Client{Transport: transport, Timeout: requestTimeout},
+	}, nil
+}
+
+// enroll enrolls with the enrollment ID and secret
+func (c *client) enroll(req *enrollmentRequest) (*enrollmentResult, error) {
+	if c.conf.EnrollmentID == "" || c.conf.Secret == "" {
+		return nil, fmt.Errorf("The enrollment ID and secret must be set to enroll")
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := c.newRequest("enroll", body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.SetBasicAuth(c.conf.EnrollmentID, string(c.conf.Secret))
+	return c.send(httpReq)
+}
+
+// reenroll obtains a new certificate authenticating with a current one,
+// whose key signs the token of the request
+func (c *client) reenroll(req *enrollmentRequest, csp bccsp.BCCSP, cert []byte, key bccsp.Key) (*enrollmentResult, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	token, err := authToken(csp, cert, key, body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := c.newRequest("reenroll", body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", token)
+	return c.send(httpReq)
+}
+
+func (c *client) newRequest(endpoint string, body []byte) (*http.Request, error) {
+	url := strings.TrimSuffix(c.conf.URL, "/") + "/api/v1/" + endpoint
+	httpReq, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Fabric CA server URL %s: %s", c.conf.URL, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	return httpReq, nil
+}
+
+func (c *client) send(httpReq *http.Request) (*enrollmentResult, error) {
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("Failed sending the request to the Fabric CA server: %s", err)
+	}
+	defer httpResp.Body.Close()
+	raw, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading the response of the Fabric CA server: %s", err)
+	}
+
+	resp := &response{}
+	if err := json.Unmarshal(raw, resp); err != nil {
+		return nil, fmt.Errorf("Invalid response of the Fabric CA server, status %s: %s", httpResp.Status, err)
+	}
+	if !resp.Success {
+		var messages []string
+		for _, e := range resp.Errors {
+			messages = append(messages, fmt.Sprintf("%d: %s", e.Code, e.Message))
+		}
+		return nil, fmt.Errorf("The Fabric CA server refused the %s request, status %s: %s",
+			httpReq.URL.Path, httpResp.Status, strings.Join(messages, "; "))
+	}
+
+	result := &enrollmentResponse{}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return nil, fmt.Errorf("Invalid enrollment response of the Fabric CA server: %s", err)
+	}
+	certPEM, err := base64.StdEncoding.DecodeString(result.Cert)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid certificate in the enrollment response: %s", err)
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, fmt.Errorf("No PEM content in the certificate of the enrollment response")
+	}
+	chainPEM, err := base64.StdEncoding.DecodeString(result.ServerInfo.CAChain)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid CA chain in the enrollment response: %s", err)
+	}
+	return &enrollmentResult{CertPEM: certPEM, CAChainPEM: chainPEM, Cert: block.Bytes}, nil
+}
+
+// authToken returns the token authenticating a request of the Fabric CA
+// REST API with a certificate: the base64 encoded PEM certificate and the
+// signature of the base64 encoded body and certificate joined with a dot
+func authToken(csp bccsp.BCCSP, cert []byte, key bccsp.Key, body []byte) (string, error) {
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})
+	b64Cert := base64.StdEncoding.EncodeToString(certPEM)
+	payload := base64.StdEncoding.EncodeToString(body) + "." + b64Cert
+
+	digest, err := csp.Hash([]byte(payload), &bccsp.SHAOpts{})
+	if err != nil {
+		return "", fmt.Errorf("Failed hashing the token: %s", err)
+	}
+	signature, err := csp.Sign(key, digest, nil)
+	if err != nil {
+		return "", fmt.Errorf("Failed signing the token: %s", err)
+	}
+	return b64Cert + "." + base64.StdEncoding.EncodeToString(signature), nil
+}
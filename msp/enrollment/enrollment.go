@@ -0,0 +1,398 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enrollment
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/bccsp/signer"
+	"github.com/hyperledger/fabric/common/flogging"
+	cf "github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/msp"
+)
+
+var logger = flogging.MustGetLogger("msp/enrollment")
+
+// defaultTLSProfile is the signing profile of the Fabric CA issuing TLS certificates
+const defaultTLSProfile = "tls"
+
+// The files the enrollment writes into the MSP directory
+const (
+	signcertFile  = "signcerts/cert.pem"
+	cacertFile    = "cacerts/ca-cert-%d.pem"
+	intercaFile   = "intermediatecerts/intermediate-cert-%d.pem"
+	admincertFile = "admincerts/admin-cert-%d.pem"
+)
+
+// Secret is the enrollment secret of an identity, it is not printed
+type Secret string
+
+// String redacts the secret, e.g. when the configuration is logged
+func (s Secret) String() string {
+	return "****"
+}
+
+// Config configures the enrollment of a node with a Fabric CA at startup
+type Config struct {
+	// Enabled enrolls the node when its MSP directory holds no signing certificate yet
+	Enabled bool
+	// URL of the Fabric CA server, e.g. https://ca.org1.example.com:7054
+	URL string
+	// CAName selects the CA of a server hosting several of them
+	CAName string
+	// EnrollmentID and Secret of the identity registered for the node
+	EnrollmentID string
+	Secret       Secret
+	// Reenroll renews the enrollment certificate with a new key at every
+	// startup once the node is enrolled, authenticating with the current one
+	Reenroll bool
+	// Profile is the signing profile of the enrollment certificate
+	Profile string
+	// Label selects the HSM key the CA signs with, when it uses several
+	Label string
+	// CSR describes the subject of the enrollment certificate
+	CSR CSR
+	// Attributes requested in the enrollment certificate
+	Attributes []Attribute
+	// AdminCerts are the files of the admin certificates of the local MSP
+	AdminCerts []string
+	// TLS configures the connections to the Fabric CA server
+	TLS TLS
+	// TLSProfile configures the enrollment of the TLS certificate of the node
+	TLSProfile TLSProfile
+}
+
+// CSR describes the subject of a certificate
+type CSR struct {
+	// CN defaults to the enrollment ID
+	CN    string
+	Hosts []string
+	Names []Name
+}
+
+// Name is a part of the distinguished name of a certificate subject
+type Name struct {
+	C  string
+	ST string
+	L  string
+	O  string
+	OU string
+}
+
+// Attribute is the request of an attribute of the identity in the certificate
+type Attribute struct {
+	Name string
+	// Optional attributes are left out when the identity does not have them,
+	// instead of failing the enrollment
+	Optional bool
+}
+
+// TLS configures the connections to the Fabric CA server
+type TLS struct {
+	// CertFiles are the root certificates the TLS certificate of the server
+	// is verified against, the system ones are used if empty
+	CertFiles []string
+}
+
+// TLSProfile configures the enrollment of the TLS certificate of the node
+type TLSProfile struct {
+	Enabled bool
+	// Profile is the signing profile of the TLS certificate, "tls" if empty
+	Profile string
+	// Hosts are the host names and IP addresses of the TLS certificate, the
+	// ones of the CSR are used if empty
+	Hosts []string
+}
+
+// TranslatePaths makes the relative paths of the configuration relative to
+// the given directory, the one of the configuration file
+func (c *Config) TranslatePaths(base string) {
+	for i := range c.TLS.CertFiles {
+		cf.TranslatePathInPlace(base, &c.TLS.CertFiles[i])
+	}
+	for i := range c.AdminCerts {
+		cf.TranslatePathInPlace(base, &c.AdminCerts[i])
+	}
+}
+
+// Enroll enrolls the node with the configured Fabric CA, unless it is
+// already enrolled and not configured to re-enroll. The key of the
+// enrollment certificate is generated and stored by the BCCSP with the
+// keystore of the local MSP, the certificates are written into the MSP
+// directory. When the TLS profile is enabled, the TLS certificate and key of
+// the node are written to tlsCertFile and tlsKeyFile.
+func Enroll(conf *Config, mspDir string, bccspConfig *factory.FactoryOpts, tlsCertFile, tlsKeyFile string) error {
+	if conf == nil || !conf.Enabled {
+		return nil
+	}
+
+	msp.SetupBCCSPKeystoreConfig(bccspConfig, filepath.Join(mspDir, "keystore"))
+	if err := factory.InitFactories(bccspConfig); err != nil {
+		return fmt.Errorf("Could not initialize BCCSP Factories [%s]", err)
+	}
+
+	return enroll(conf, factory.GetDefault(), mspDir, tlsCertFile, tlsKeyFile)
+}
+
+func enroll(conf *Config, csp bccsp.BCCSP, mspDir, tlsCertFile, tlsKeyFile string) error {
+	client, err := newClient(conf)
+	if err != nil {
+		return err
+	}
+
+	current, currentKey, err := currentEnrollment(csp, mspDir)
+	if err != nil {
+		return err
+	}
+	if current != nil && !conf.Reenroll {
+		logger.Infof("Already enrolled as %s, not enrolling with %s", current.Subject.CommonName, conf.URL)
+		return nil
+	}
+
+	// The enrollment certificate
+	key, err := csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	if err != nil {
+		return fmt.Errorf("Failed generating the enrollment key: %s", err)
+	}
+	keySigner := &signer.CryptoSigner{}
+	if err := keySigner.Init(csp, key); err != nil {
+		return fmt.Errorf("Failed initializing the signer of the enrollment key: %s", err)
+	}
+	csr, err := newCSR(conf, conf.CSR.Hosts, keySigner)
+	if err != nil {
+		return err
+	}
+	req := &enrollmentRequest{
+		CertificateRequest: string(csr),
+		Hosts:              conf.CSR.Hosts,
+		Profile:            conf.Profile,
+		Label:              conf.Label,
+		CAName:             conf.CAName,
+	}
+	for _, attribute := range conf.Attributes {
+		req.AttrReqs = append(req.AttrReqs, &attributeRequest{Name: attribute.Name, Optional: attribute.Optional})
+	}
+
+	var result *enrollmentResult
+	if current != nil {
+		logger.Infof("Re-enrolling %s with %s", current.Subject.CommonName, conf.URL)
+		result, err = client.reenroll(req, csp, current.Raw, currentKey)
+	} else {
+		logger.Infof("Enrolling %s with %s", conf.EnrollmentID, conf.URL)
+		result, err = client.enroll(req)
+	}
+	if err != nil {
+		return err
+	}
+	if err := writeEnrollment(mspDir, result); err != nil {
+		return err
+	}
+	if err := writeAdminCerts(mspDir, conf.AdminCerts); err != nil {
+		return err
+	}
+
+	if !conf.TLSProfile.Enabled {
+		return nil
+	}
+	return enrollTLS(conf, client, csp, result, key, tlsCertFile, tlsKeyFile)
+}
+
+// enrollTLS obtains the TLS certificate of the node, authenticating with
+// its new enrollment certificate so that no enrollment of the identity is
+// consumed. The TLS key is generated in software since it is read from a file.
+func enrollTLS(conf *Config, client *client, csp bccsp.BCCSP, enrollment *enrollmentResult, enrollmentKey bccsp.Key, certFile, keyFile string) error {
+	tlsKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("Failed generating the TLS key: %s", err)
+	}
+	hosts := conf.TLSProfile.Hosts
+	if len(hosts) == 0 {
+		hosts = conf.CSR.Hosts
+	}
+	csr, err := newCSR(conf, hosts, tlsKey)
+	if err != nil {
+		return err
+	}
+	profile := conf.TLSProfile.Profile
+	if profile == "" {
+		profile = defaultTLSProfile
+	}
+	req := &enrollmentRequest{
+		CertificateRequest: string(csr),
+		Hosts:              hosts,
+		Profile:            profile,
+		Label:              conf.Label,
+		CAName:             conf.CAName,
+	}
+
+	result, err := client.reenroll(req, csp, enrollment.Cert, enrollmentKey)
+	if err != nil {
+		return fmt.Errorf("Failed obtaining the TLS certificate: %s", err)
+	}
+	der, err := x509.MarshalECPrivateKey(tlsKey)
+	if err != nil {
+		return fmt.Errorf("Failed marshalling the TLS key: %s", err)
+	}
+	if err := writeFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		return err
+	}
+	return writeFile(certFile, result.CertPEM, 0644)
+}
+
+// currentEnrollment returns the signing certificate of the MSP directory and
+// its key, if the node is enrolled
+func currentEnrollment(csp bccsp.BCCSP, mspDir string) (*x509.Certificate, bccsp.Key, error) {
+	raw, err := ioutil.ReadFile(filepath.Join(mspDir, signcertFile))
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed reading the enrollment certificate: %s", err)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, nil, fmt.Errorf("No PEM content in the enrollment certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed parsing the enrollment certificate: %s", err)
+	}
+
+	publicKey, err := csp.KeyImport(cert, &bccsp.X509PublicKeyImportOpts{Temporary: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed importing the public key of the enrollment certificate: %s", err)
+	}
+	key, err := csp.GetKey(publicKey.SKI())
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed getting the key of the enrollment certificate: %s", err)
+	}
+	return cert, key, nil
+}
+
+// newCSR creates the PEM encoded certificate request of the key of signer
+func newCSR(conf *Config, hosts []string, keySigner crypto.Signer) ([]byte, error) {
+	subject := pkix.Name{CommonName: conf.CSR.CN}
+	if subject.CommonName == "" {
+		subject.CommonName = conf.EnrollmentID
+	}
+	for _, name := range conf.CSR.Names {
+		appendNonEmpty(&subject.Country, name.C)
+		appendNonEmpty(&subject.Province, name.ST)
+		appendNonEmpty(&subject.Locality, name.L)
+		appendNonEmpty(&subject.Organization, name.O)
+		appendNonEmpty(&subject.OrganizationalUnit, name.OU)
+	}
+
+	template := &x509.CertificateRequest{Subject: subject}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, keySigner)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating the certificate request: %s", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), nil
+}
+
+func appendNonEmpty(values *[]string, value string) {
+	if value != "" {
+		*values = append(*values, value)
+	}
+}
+
+// writeEnrollment writes the enrollment certificate and the certificates of
+// the chain of the CA into the MSP directory
+func writeEnrollment(mspDir string, result *enrollmentResult) error {
+	if err := writeFile(filepath.Join(mspDir, signcertFile), result.CertPEM, 0644); err != nil {
+		return err
+	}
+
+	roots, intermediates := 0, 0
+	for rest := result.CAChainPEM; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("Failed parsing the certificate chain of the CA: %s", err)
+		}
+
+		certPEM := pem.EncodeToMemory(block)
+		if isSelfSigned(cert) {
+			err = writeFile(filepath.Join(mspDir, fmt.Sprintf(cacertFile, roots)), certPEM, 0644)
+			roots++
+		} else {
+			err = writeFile(filepath.Join(mspDir, fmt.Sprintf(intercaFile, intermediates)), certPEM, 0644)
+			intermediates++
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if roots == 0 {
+		return fmt.Errorf("The certificate chain of the CA has no root certificate")
+	}
+	return nil
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return cert.CheckSignatureFrom(cert) == nil
+}
+
+// writeAdminCerts copies the admin certificates into the MSP directory
+func writeAdminCerts(mspDir string, files []string) error {
+	for i, file := range files {
+		raw, err := ioutil.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("Failed reading admin certificate: %s", err)
+		}
+		if err := writeFile(filepath.Join(mspDir, fmt.Sprintf(admincertFile, i)), raw, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(file string, content []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("Failed creating the directory of %s: %s", file, err)
+	}
+	if err := ioutil.WriteFile(file, content, perm); err != nil {
+		return fmt.Errorf("Failed writing %s: %s", file, err)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enrollment
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCA is a Fabric CA server issuing the certificates of the enroll and
+// reenroll requests
+type mockCA struct {
+	t        *testing.T
+	key      *ecdsa.PrivateKey
+	cert     *x509.Certificate
+	certPEM  []byte
+	requests []string
+	profiles []string
+}
+
+func newMockCA(t *testing.T) *mockCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return &mockCA{t: t, key: key, cert: cert, certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (ca *mockCA) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	endpoint := strings.TrimPrefix(r.URL.Path, "/api/v1/")
+	ca.requests = append(ca.requests, endpoint)
+
+	var err error
+	switch endpoint {
+	case "enroll":
+		if id, secret, ok := r.BasicAuth(); !ok || id != "peer0" || secret != "peer0pw" {
+			err = fmt.Errorf("invalid credentials")
+		}
+	case "reenroll":
+		err = ca.checkToken(r.Header.Get("Authorization"), body)
+	default:
+		err = fmt.Errorf("unknown endpoint")
+	}
+
+	req := &enrollmentRequest{}
+	if err == nil {
+		err = json.Unmarshal(body, req)
+	}
+	var cert []byte
+	if err == nil {
+		ca.profiles = append(ca.profiles, req.Profile)
+		cert, err = ca.issue(req.CertificateRequest)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprintf(w, `{"success":false,"result":null,"errors":[{"code":20,"message":%q}]}`, err.Error())
+		return
+	}
+
+	result := &enrollmentResponse{Cert: base64.StdEncoding.EncodeToString(cert)}
+	result.ServerInfo.CAChain = base64.StdEncoding.EncodeToString(ca.certPEM)
+	raw, _ := json.Marshal(result)
+	fmt.Fprintf(w, `{"success":true,"result":%s,"errors":[],"messages":[]}`, raw)
+}
+
+func (ca *mockCA) checkToken(token string, body []byte) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid token")
+	}
+	certPEM, _ := base64.StdEncoding.DecodeString(parts[0])
+	signature, _ := base64.StdEncoding.DecodeString(parts[1])
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return fmt.Errorf("invalid token certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return err
+	}
+	if err := cert.CheckSignatureFrom(ca.cert); err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(base64.StdEncoding.EncodeToString(body) + "." + parts[0]))
+	if !ecdsa.VerifyASN1(cert.PublicKey.(*ecdsa.PublicKey), digest[:], signature) {
+		return fmt.Errorf("invalid token signature")
+	}
+	return nil
+}
+
+func (ca *mockCA) issue(csrPEM string) ([]byte, error) {
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil {
+		return nil, fmt.Errorf("invalid certificate request")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      csr.Subject,
+		DNSNames:     csr.DNSNames,
+		IPAddresses:  csr.IPAddresses,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+func readCert(t *testing.T, file string) *x509.Certificate {
+	raw, err := ioutil.ReadFile(file)
+	assert.NoError(t, err)
+	block, _ := pem.Decode(raw)
+	if !assert.NotNil(t, block, "No PEM content in %s", file) {
+		t.FailNow()
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	assert.NoError(t, err)
+	return cert
+}
+
+func TestEnroll(t *testing.T) {
+	dir, err := ioutil.TempDir("", "enrollment")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	mspDir := filepath.Join(dir, "msp")
+	tlsCert, tlsKey := filepath.Join(dir, "tls", "server.crt"), filepath.Join(dir, "tls", "server.key")
+	csp, err := sw.NewDefaultSecurityLevel(filepath.Join(mspDir, "keystore"))
+	assert.NoError(t, err)
+
+	ca := newMockCA(t)
+	server := httptest.NewServer(ca)
+	defer server.Close()
+
+	adminCert := filepath.Join(dir, "admin.pem")
+	assert.NoError(t, ioutil.WriteFile(adminCert, ca.certPEM, 0644))
+	conf := &Config{
+		Enabled:      true,
+		URL:          server.URL,
+		EnrollmentID: "peer0",
+		Secret:       "peer0pw",
+		CSR:          CSR{Hosts: []string{"peer0.org1.example.com"}, Names: []Name{{O: "Org1", OU: "peer"}}},
+		AdminCerts:   []string{adminCert},
+		TLSProfile:   TLSProfile{Enabled: true, Hosts: []string{"peer0.org1.example.com", "127.0.0.1"}},
+	}
+
+	assert.NoError(t, enroll(conf, csp, mspDir, tlsCert, tlsKey))
+	assert.Equal(t, []string{"enroll", "reenroll"}, ca.requests, "The TLS certificate should have been obtained with the enrollment certificate")
+	assert.Equal(t, []string{"", defaultTLSProfile}, ca.profiles)
+
+	cert := readCert(t, filepath.Join(mspDir, signcertFile))
+	assert.Equal(t, "peer0", cert.Subject.CommonName)
+	assert.Equal(t, []string{"Org1"}, cert.Subject.Organization)
+	assert.NoError(t, cert.CheckSignatureFrom(readCert(t, filepath.Join(mspDir, fmt.Sprintf(cacertFile, 0)))))
+	readCert(t, filepath.Join(mspDir, fmt.Sprintf(admincertFile, 0)))
+	_, key, err := currentEnrollment(csp, mspDir)
+	assert.NoError(t, err)
+	assert.True(t, key.Private(), "The key of the enrollment certificate should be in the keystore")
+
+	tlsCertificate := readCert(t, tlsCert)
+	assert.Equal(t, []string{"peer0.org1.example.com"}, tlsCertificate.DNSNames)
+	assert.Len(t, tlsCertificate.IPAddresses, 1)
+	info, err := os.Stat(tlsKey)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "The TLS key should only be readable by the node")
+	}
+
+	// Already enrolled
+	ca.requests = nil
+	assert.NoError(t, enroll(conf, csp, mspDir, tlsCert, tlsKey))
+	assert.Empty(t, ca.requests, "Should not have enrolled again")
+
+	// Re-enrolling renews the certificate with the current one
+	conf.Reenroll = true
+	conf.TLSProfile.Enabled = false
+	conf.Secret = ""
+	assert.NoError(t, enroll(conf, csp, mspDir, tlsCert, tlsKey))
+	assert.Equal(t, []string{"reenroll"}, ca.requests)
+	renewed := readCert(t, filepath.Join(mspDir, signcertFile))
+	assert.NotEqual(t, cert.SerialNumber, renewed.SerialNumber)
+	assert.NotEqual(t, cert.PublicKey, renewed.PublicKey, "Should have renewed the certificate with a new key")
+}
+
+func TestEnrollRefused(t *testing.T) {
+	dir, err := ioutil.TempDir("", "enrollment")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	csp, err := sw.NewDefaultSecurityLevel(filepath.Join(dir, "keystore"))
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(newMockCA(t))
+	defer server.Close()
+
+	conf := &Config{Enabled: true, URL: server.URL, EnrollmentID: "peer0", Secret: "wrong"}
+	err = enroll(conf, csp, dir, "", "")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid credentials")
+	}
+	_, err = os.Stat(filepath.Join(dir, signcertFile))
+	assert.True(t, os.IsNotExist(err), "Should not have written an enrollment certificate")
+
+	conf.Secret = ""
+	assert.Error(t, enroll(conf, csp, dir, "", ""), "Should not enroll without secret")
+	assert.NoError(t, Enroll(&Config{}, dir, nil, "", ""), "Should not enroll when disabled")
+}
+
+func TestSecretRedacted(t *testing.T) {
+	conf := Config{EnrollmentID: "peer0", Secret: "peer0pw"}
+	assert.NotContains(t, fmt.Sprintf("%+v", conf), "peer0pw")
+}
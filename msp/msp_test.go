@@ -473,6 +473,73 @@ func TestIdentityPolicyPrincipalFails(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestAnonymityPolicyPrincipal(t *testing.T) {
+	id, err := localMsp.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+
+	principal := &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ANONYMITY,
+		Principal:               mustMarshal(t, &msp.MSPIdentityAnonymity{AnonymityType: msp.MSPIdentityAnonymity_NOMINAL})}
+
+	err = id.SatisfiesPrincipal(principal)
+	assert.NoError(t, err)
+
+	principal = &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ANONYMITY,
+		Principal:               mustMarshal(t, &msp.MSPIdentityAnonymity{AnonymityType: msp.MSPIdentityAnonymity_ANONYMOUS})}
+
+	err = id.SatisfiesPrincipal(principal)
+	assert.Error(t, err)
+}
+
+func mustMarshal(t *testing.T, msg proto.Message) []byte {
+	bytes, err := proto.Marshal(msg)
+	assert.NoError(t, err)
+	return bytes
+}
+
+func TestCombinedPolicyPrincipal(t *testing.T) {
+	id, err := localMsp.GetDefaultSigningIdentity()
+	assert.NoError(t, err)
+
+	cid, err := localMsp.(*bccspmsp).getCertificationChainIdentifier(id.GetPublicVersion())
+	assert.NoError(t, err)
+
+	member := &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ROLE,
+		Principal:               mustMarshal(t, &msp.MSPRole{Role: msp.MSPRole_MEMBER, MspIdentifier: "DEFAULT"})}
+	ouPrincipal := func(ou string) *msp.MSPPrincipal {
+		return &msp.MSPPrincipal{
+			PrincipalClassification: msp.MSPPrincipal_ORGANIZATION_UNIT,
+			Principal: mustMarshal(t, &msp.OrganizationUnit{
+				OrganizationalUnitIdentifier: ou,
+				MspIdentifier:                "DEFAULT",
+				CertifiersIdentifier:         cid,
+			})}
+	}
+	combined := func(principals ...*msp.MSPPrincipal) *msp.MSPPrincipal {
+		return &msp.MSPPrincipal{
+			PrincipalClassification: msp.MSPPrincipal_COMBINED,
+			Principal:               mustMarshal(t, &msp.CombinedPrincipal{Principals: principals})}
+	}
+
+	// a member of the COP organization unit
+	err = id.SatisfiesPrincipal(combined(member, ouPrincipal("COP")))
+	assert.NoError(t, err)
+
+	// a member of another organization unit
+	err = id.SatisfiesPrincipal(combined(member, ouPrincipal("COP2")))
+	assert.Error(t, err)
+
+	// nested combinations
+	err = id.SatisfiesPrincipal(combined(combined(member), ouPrincipal("COP")))
+	assert.NoError(t, err)
+
+	// empty combinations are satisfied by no identity
+	err = id.SatisfiesPrincipal(combined())
+	assert.Error(t, err)
+}
+
 var conf *msp.MSPConfig
 var localMsp MSP
 var mspMgr MSPManager
@@ -662,6 +662,45 @@ func (msp *bccspmsp) SatisfiesPrincipal(id Identity, principal *m.MSPPrincipal)
 
 		// if we are here, no match was found, return an error
 		return errors.New("The identities do not match")
+	case m.MSPPrincipal_ANONYMITY:
+		// Principal contains the anonymity type
+		anonymity := &m.MSPIdentityAnonymity{}
+		err := proto.Unmarshal(principal.Principal, anonymity)
+		if err != nil {
+			return fmt.Errorf("Could not unmarshal MSPIdentityAnonymity from principal, err %s", err)
+		}
+
+		// the identities of this MSP are X.509 certificates,
+		// which always carry the enrollment of their owner
+		switch anonymity.AnonymityType {
+		case m.MSPIdentityAnonymity_NOMINAL:
+			return nil
+		case m.MSPIdentityAnonymity_ANONYMOUS:
+			return errors.New("Principal is anonymous, but X.509 MSP does not support anonymous identities")
+		default:
+			return fmt.Errorf("Invalid MSP identity anonymity type %d", int32(anonymity.AnonymityType))
+		}
+	case m.MSPPrincipal_COMBINED:
+		// Principal contains the principals to combine
+		combined := &m.CombinedPrincipal{}
+		err := proto.Unmarshal(principal.Principal, combined)
+		if err != nil {
+			return fmt.Errorf("Could not unmarshal CombinedPrincipal from principal, err %s", err)
+		}
+
+		// an empty combination would be satisfied by any
+		// identity, including the ones of other MSPs
+		if len(combined.Principals) == 0 {
+			return errors.New("No principals in CombinedPrincipal")
+		}
+
+		// the identity has to satisfy all the principals
+		for i, p := range combined.Principals {
+			if err := msp.SatisfiesPrincipal(id, p); err != nil {
+				return fmt.Errorf("The identity does not satisfy principal %d of the combination: %s", i, err)
+			}
+		}
+		return nil
 	default:
 		return fmt.Errorf("Invalid principal type %d", int32(principal.PrincipalClassification))
 	}
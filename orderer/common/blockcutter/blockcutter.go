@@ -47,7 +47,8 @@ type Receiver interface {
 	//   - After adding the current message to the pending batch, the message count has reached BatchSize.MaxMessageCount.
 	Ordered(msg *cb.Envelope) ([][]*cb.Envelope, [][]filter.Committer, bool)
 
-	// Cut returns the current batch and starts a new one
+	// Cut returns the current batch and starts a new one, it is invoked by
+	// the consenters once the batch timeout of the pending batch expired
 	Cut() ([]*cb.Envelope, []filter.Committer)
 }
 
@@ -57,13 +58,15 @@ type receiver struct {
 	pendingBatch          []*cb.Envelope
 	pendingBatchSizeBytes uint32
 	pendingCommitters     []filter.Committer
+	metrics               *channelMetrics
 }
 
-// NewReceiverImpl creates a Receiver implementation based on the given configtxorderer manager and filters
-func NewReceiverImpl(sharedConfigManager config.Orderer, filters *filter.RuleSet) Receiver {
+// NewReceiverImpl creates a Receiver implementation for the given chain based on the given configtxorderer manager and filters
+func NewReceiverImpl(chainID string, sharedConfigManager config.Orderer, filters *filter.RuleSet) Receiver {
 	return &receiver{
 		sharedConfigManager: sharedConfigManager,
 		filters:             filters,
+		metrics:             newChannelMetrics(chainID),
 	}
 }
 
@@ -96,10 +99,12 @@ func (r *receiver) Ordered(msg *cb.Envelope) ([][]*cb.Envelope, [][]filter.Commi
 
 	if committer.Isolated() || messageSizeBytes > r.sharedConfigManager.BatchSize().PreferredMaxBytes {
 
+		reason := cutIsolated
 		if committer.Isolated() {
 			logger.Debugf("Found message which requested to be isolated, cutting into its own batch")
 		} else {
 			logger.Debugf("The current message, with %v bytes, is larger than the preferred batch size of %v bytes and will be isolated.", messageSizeBytes, r.sharedConfigManager.BatchSize().PreferredMaxBytes)
+			reason = cutOversized
 		}
 
 		messageBatches := [][]*cb.Envelope{}
@@ -107,12 +112,13 @@ func (r *receiver) Ordered(msg *cb.Envelope) ([][]*cb.Envelope, [][]filter.Commi
 
 		// cut pending batch, if it has any messages
 		if len(r.pendingBatch) > 0 {
-			messageBatch, committerBatch := r.Cut()
+			messageBatch, committerBatch := r.cut(reason)
 			messageBatches = append(messageBatches, messageBatch)
 			committerBatches = append(committerBatches, committerBatch)
 		}
 
 		// create new batch with single message
+		r.recordBatch(reason, 1, messageSizeBytes)
 		messageBatches = append(messageBatches, []*cb.Envelope{msg})
 		committerBatches = append(committerBatches, []filter.Committer{committer})
 
@@ -127,7 +133,7 @@ func (r *receiver) Ordered(msg *cb.Envelope) ([][]*cb.Envelope, [][]filter.Commi
 	if messageWillOverflowBatchSizeBytes {
 		logger.Debugf("The current message, with %v bytes, will overflow the pending batch of %v bytes.", messageSizeBytes, r.pendingBatchSizeBytes)
 		logger.Debugf("Pending batch would overflow if current message is added, cutting batch now.")
-		messageBatch, committerBatch := r.Cut()
+		messageBatch, committerBatch := r.cut(cutBytes)
 		messageBatches = append(messageBatches, messageBatch)
 		committerBatches = append(committerBatches, committerBatch)
 	}
@@ -136,10 +142,11 @@ func (r *receiver) Ordered(msg *cb.Envelope) ([][]*cb.Envelope, [][]filter.Commi
 	r.pendingBatch = append(r.pendingBatch, msg)
 	r.pendingBatchSizeBytes += messageSizeBytes
 	r.pendingCommitters = append(r.pendingCommitters, committer)
+	r.metrics.recordPending(len(r.pendingBatch), r.pendingBatchSizeBytes)
 
 	if uint32(len(r.pendingBatch)) >= r.sharedConfigManager.BatchSize().MaxMessageCount {
 		logger.Debugf("Batch size met, cutting batch")
-		messageBatch, committerBatch := r.Cut()
+		messageBatch, committerBatch := r.cut(cutSize)
 		messageBatches = append(messageBatches, messageBatch)
 		committerBatches = append(committerBatches, committerBatch)
 	}
@@ -155,6 +162,16 @@ func (r *receiver) Ordered(msg *cb.Envelope) ([][]*cb.Envelope, [][]filter.Commi
 
 // Cut returns the current batch and starts a new one
 func (r *receiver) Cut() ([]*cb.Envelope, []filter.Committer) {
+	return r.cut(cutTimeout)
+}
+
+// cut returns the current batch and starts a new one, accounting for the
+// batch in the metrics of the channel under the given reason
+func (r *receiver) cut(reason string) ([]*cb.Envelope, []filter.Committer) {
+	if len(r.pendingBatch) > 0 {
+		r.recordBatch(reason, len(r.pendingBatch), r.pendingBatchSizeBytes)
+		r.metrics.recordPending(0, 0)
+	}
 	batch := r.pendingBatch
 	r.pendingBatch = nil
 	committers := r.pendingCommitters
@@ -163,6 +180,12 @@ func (r *receiver) Cut() ([]*cb.Envelope, []filter.Committer) {
 	return batch, committers
 }
 
+// recordBatch accounts for a batch against the batch size of the channel
+func (r *receiver) recordBatch(reason string, messages int, sizeBytes uint32) {
+	batchSize := r.sharedConfigManager.BatchSize()
+	r.metrics.recordBatch(reason, messages, sizeBytes, batchSize.MaxMessageCount, batchSize.PreferredMaxBytes)
+}
+
 func messageSizeBytes(message *cb.Envelope) uint32 {
 	return uint32(len(message.Payload) + len(message.Signature))
 }
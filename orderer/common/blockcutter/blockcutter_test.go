@@ -18,6 +18,8 @@ package blockcutter
 
 import (
 	"bytes"
+	"expvar"
+	"fmt"
 	"testing"
 
 	mockconfigtxorderer "github.com/hyperledger/fabric/common/mocks/configvalues/channel/orderer"
@@ -72,6 +74,8 @@ func getFilters() *filter.RuleSet {
 	})
 }
 
+const testChainID = "foo"
+
 var badTx = &cb.Envelope{Payload: []byte("BAD")}
 var goodTx = &cb.Envelope{Payload: []byte("GOOD")}
 var goodTxLarge = &cb.Envelope{Payload: []byte("GOOD"), Signature: make([]byte, 1000)}
@@ -83,7 +87,7 @@ func TestNormalBatch(t *testing.T) {
 	maxMessageCount := uint32(2)
 	absoluteMaxBytes := uint32(1000)
 	preferredMaxBytes := uint32(100)
-	r := NewReceiverImpl(&mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: absoluteMaxBytes, PreferredMaxBytes: preferredMaxBytes}}, filters)
+	r := NewReceiverImpl(testChainID, &mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: absoluteMaxBytes, PreferredMaxBytes: preferredMaxBytes}}, filters)
 
 	batches, committers, ok := r.Ordered(goodTx)
 
@@ -112,7 +116,7 @@ func TestBadMessageInBatch(t *testing.T) {
 	maxMessageCount := uint32(2)
 	absoluteMaxBytes := uint32(1000)
 	preferredMaxBytes := uint32(100)
-	r := NewReceiverImpl(&mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: absoluteMaxBytes, PreferredMaxBytes: preferredMaxBytes}}, filters)
+	r := NewReceiverImpl(testChainID, &mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: absoluteMaxBytes, PreferredMaxBytes: preferredMaxBytes}}, filters)
 
 	batches, committers, ok := r.Ordered(badTx)
 
@@ -150,7 +154,7 @@ func TestUnmatchedMessageInBatch(t *testing.T) {
 	maxMessageCount := uint32(2)
 	absoluteMaxBytes := uint32(1000)
 	preferredMaxBytes := uint32(100)
-	r := NewReceiverImpl(&mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: absoluteMaxBytes, PreferredMaxBytes: preferredMaxBytes}}, filters)
+	r := NewReceiverImpl(testChainID, &mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: absoluteMaxBytes, PreferredMaxBytes: preferredMaxBytes}}, filters)
 
 	batches, committers, ok := r.Ordered(unmatchedTx)
 
@@ -188,7 +192,7 @@ func TestIsolatedEmptyBatch(t *testing.T) {
 	maxMessageCount := uint32(2)
 	absoluteMaxBytes := uint32(1000)
 	preferredMaxBytes := uint32(100)
-	r := NewReceiverImpl(&mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: absoluteMaxBytes, PreferredMaxBytes: preferredMaxBytes}}, filters)
+	r := NewReceiverImpl(testChainID, &mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: absoluteMaxBytes, PreferredMaxBytes: preferredMaxBytes}}, filters)
 
 	batches, committers, ok := r.Ordered(isolatedTx)
 
@@ -214,7 +218,7 @@ func TestIsolatedPartialBatch(t *testing.T) {
 	maxMessageCount := uint32(2)
 	absoluteMaxBytes := uint32(1000)
 	preferredMaxBytes := uint32(100)
-	r := NewReceiverImpl(&mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: absoluteMaxBytes, PreferredMaxBytes: preferredMaxBytes}}, filters)
+	r := NewReceiverImpl(testChainID, &mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: absoluteMaxBytes, PreferredMaxBytes: preferredMaxBytes}}, filters)
 
 	batches, committers, ok := r.Ordered(goodTx)
 
@@ -264,7 +268,7 @@ func TestBatchSizePreferredMaxBytesOverflow(t *testing.T) {
 	// set message count > 9
 	maxMessageCount := uint32(20)
 
-	r := NewReceiverImpl(&mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: preferredMaxBytes * 2, PreferredMaxBytes: preferredMaxBytes}}, filters)
+	r := NewReceiverImpl(testChainID, &mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: preferredMaxBytes * 2, PreferredMaxBytes: preferredMaxBytes}}, filters)
 
 	// enqueue 9 messages
 	for i := 0; i < 9; i++ {
@@ -319,7 +323,7 @@ func TestBatchSizePreferredMaxBytesOverflowNoPending(t *testing.T) {
 	// set message count > 1
 	maxMessageCount := uint32(20)
 
-	r := NewReceiverImpl(&mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: preferredMaxBytes * 3, PreferredMaxBytes: preferredMaxBytes}}, filters)
+	r := NewReceiverImpl(testChainID, &mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: maxMessageCount, AbsoluteMaxBytes: preferredMaxBytes * 3, PreferredMaxBytes: preferredMaxBytes}}, filters)
 
 	// submit large message
 	batches, committers, ok := r.Ordered(goodTxLarge)
@@ -340,3 +344,42 @@ func TestBatchSizePreferredMaxBytesOverflowNoPending(t *testing.T) {
 	}
 
 }
+
+func TestMetrics(t *testing.T) {
+	chainID := "metrics"
+	goodTxBytes := messageSizeBytes(goodTx)
+	r := NewReceiverImpl(chainID, &mockconfigtxorderer.SharedConfig{BatchSizeVal: &ab.BatchSize{MaxMessageCount: 2, AbsoluteMaxBytes: 1000, PreferredMaxBytes: 100}}, getFilters())
+
+	r.Ordered(goodTx)
+	pending := pendingBatch.Get(chainID).(*expvar.Map)
+	if pending.Get("messages").String() != "1" || pending.Get("bytes").String() != fmt.Sprint(goodTxBytes) {
+		t.Fatalf("Should have published the pending message, got %s", pending)
+	}
+
+	r.Ordered(goodTx)
+	r.Ordered(goodTx)
+	r.Cut()
+	r.Ordered(isolatedTx)
+	r.Ordered(goodTxLarge)
+	// Nothing pending, not accounted for
+	r.Cut()
+
+	reasons := cutReasons.Get(chainID).(*expvar.Map)
+	for reason, expected := range map[string]string{cutSize: "1", cutTimeout: "1", cutIsolated: "1", cutOversized: "1"} {
+		if reasons.Get(reason) == nil || reasons.Get(reason).String() != expected {
+			t.Errorf("Expected %s batches cut for reason %s, got %v", expected, reason, reasons.Get(reason))
+		}
+	}
+
+	fill := batchFill.Get(chainID).(*expvar.Map)
+	if fill.Get("batches").String() != "4" || fill.Get("messages").String() != "5" {
+		t.Errorf("Should have accounted for 4 batches of 5 messages, got %s", fill)
+	}
+	// 2/2 for the full batch, 1/2 for the timeout and the two isolated batches
+	if fill.Get("message_fill").String() != "2.5" {
+		t.Errorf("Unexpected message fill ratio sum %s", fill.Get("message_fill"))
+	}
+	if pending.Get("messages").String() != "0" || pending.Get("bytes").String() != "0" {
+		t.Errorf("Should have published the empty pending batch, got %s", pending)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blockcutter
+
+import (
+	"expvar"
+	"sync"
+)
+
+// The reasons for which a batch is cut
+const (
+	// cutSize is the pending batch reaching BatchSize.MaxMessageCount
+	cutSize = "size"
+	// cutBytes is the next message overflowing BatchSize.PreferredMaxBytes
+	cutBytes = "bytes"
+	// cutOversized is a message larger than BatchSize.PreferredMaxBytes,
+	// which is cut into its own batch
+	cutOversized = "oversized"
+	// cutIsolated is a message which requested to be isolated, like a config
+	// transaction, and is cut into its own batch
+	cutIsolated = "isolated"
+	// cutTimeout is the consenter cutting the pending batch, once its batch
+	// timeout expired
+	cutTimeout = "timeout"
+)
+
+// cutReasons counts by channel the batches cut for each reason, e.g.
+// {"mychannel": {"size": 12, "timeout": 40, "isolated": 1}}. It is published
+// with the other expvar variables on /debug/vars of the profiling server of
+// the orderer
+var cutReasons = expvar.NewMap("blockcutter_cut_reasons")
+
+// batchFill accumulates by channel the number of batches cut, the messages
+// and bytes they hold, and the sums of their fill ratios relative to
+// BatchSize.MaxMessageCount and BatchSize.PreferredMaxBytes, e.g.
+// {"mychannel": {"batches": 4, "messages": 120, "bytes": 52000,
+// "message_fill": 1.2, "byte_fill": 0.4}}. The average fill ratio is the
+// sum divided by the number of batches
+var batchFill = expvar.NewMap("blockcutter_batch_fill")
+
+// pendingBatch holds by channel the number of messages and bytes waiting in
+// the pending batch, the backlog of the channel which is not cut yet
+var pendingBatch = expvar.NewMap("blockcutter_pending_batch")
+
+// metricsLock serializes the creation of the metrics of a channel
+var metricsLock sync.Mutex
+
+// channelMetrics are the metrics of the batches of a channel
+type channelMetrics struct {
+	cutReasons      *expvar.Map
+	batchFill       *expvar.Map
+	pendingMessages *expvar.Int
+	pendingBytes    *expvar.Int
+}
+
+// newChannelMetrics returns the metrics of the batches of a channel, the
+// counters are shared by the receivers of the same channel
+func newChannelMetrics(chainID string) *channelMetrics {
+	metricsLock.Lock()
+	defer metricsLock.Unlock()
+
+	m := &channelMetrics{
+		cutReasons: channelMap(cutReasons, chainID),
+		batchFill:  channelMap(batchFill, chainID),
+	}
+	pending := channelMap(pendingBatch, chainID)
+	if m.pendingMessages, _ = pending.Get("messages").(*expvar.Int); m.pendingMessages == nil {
+		m.pendingMessages = new(expvar.Int)
+		pending.Set("messages", m.pendingMessages)
+	}
+	if m.pendingBytes, _ = pending.Get("bytes").(*expvar.Int); m.pendingBytes == nil {
+		m.pendingBytes = new(expvar.Int)
+		pending.Set("bytes", m.pendingBytes)
+	}
+	return m
+}
+
+// channelMap returns the sub-map of a channel in a map, metricsLock must be held
+func channelMap(metric *expvar.Map, chainID string) *expvar.Map {
+	if counters, ok := metric.Get(chainID).(*expvar.Map); ok {
+		return counters
+	}
+	counters := new(expvar.Map).Init()
+	metric.Set(chainID, counters)
+	return counters
+}
+
+// recordBatch accounts for a batch cut for the given reason
+func (m *channelMetrics) recordBatch(reason string, messages int, sizeBytes uint32, maxMessageCount uint32, preferredMaxBytes uint32) {
+	m.cutReasons.Add(reason, 1)
+	m.batchFill.Add("batches", 1)
+	m.batchFill.Add("messages", int64(messages))
+	m.batchFill.Add("bytes", int64(sizeBytes))
+	if maxMessageCount > 0 {
+		m.batchFill.AddFloat("message_fill", float64(messages)/float64(maxMessageCount))
+	}
+	if preferredMaxBytes > 0 {
+		m.batchFill.AddFloat("byte_fill", float64(sizeBytes)/float64(preferredMaxBytes))
+	}
+}
+
+// recordPending sets the backlog of the pending batch
+func (m *channelMetrics) recordPending(messages int, sizeBytes uint32) {
+	m.pendingMessages.Set(int64(messages))
+	m.pendingBytes.Set(int64(sizeBytes))
+}
@@ -17,12 +17,16 @@ limitations under the License.
 package broadcast
 
 import (
+	"github.com/hyperledger/fabric/common/tracing"
+	"github.com/hyperledger/fabric/core/common/validation"
 	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/common/sizefilter"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/op/go-logging"
 
 	"io"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -59,16 +63,32 @@ type Support interface {
 
 	// Filters returns the set of broadcast filters for this chain
 	Filters() *filter.RuleSet
+
+	// AbsoluteMaxBytes returns the maximum size of a message accepted for this chain, as currently configured
+	AbsoluteMaxBytes() uint32
+
+	// ConsensusState returns the current state of the consensus for this chain, in maintenance only
+	// config transactions are accepted
+	ConsensusState() ab.ConsensusType_State
 }
 
 type handlerImpl struct {
-	sm SupportManager
+	sm            SupportManager
+	timestampSkew time.Duration
 }
 
 // NewHandlerImpl constructs a new implementation of the Handler interface
 func NewHandlerImpl(sm SupportManager) Handler {
+	return NewHandlerImplWithTimestampSkew(sm, 0)
+}
+
+// NewHandlerImplWithTimestampSkew constructs a new implementation of the
+// Handler interface which rejects the messages whose timestamp differs from
+// the local time by more than timestampSkew, 0 disabling the check
+func NewHandlerImplWithTimestampSkew(sm SupportManager, timestampSkew time.Duration) Handler {
 	return &handlerImpl{
-		sm: sm,
+		sm:            sm,
+		timestampSkew: timestampSkew,
 	}
 }
 
@@ -84,91 +104,122 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 			return err
 		}
 
-		payload := &cb.Payload{}
-		err = proto.Unmarshal(msg.Payload, payload)
-		if err != nil {
-			if logger.IsEnabledFor(logging.WARNING) {
-				logger.Warningf("Received malformed message, dropping connection: %s", err)
-			}
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST})
+		resp := bh.process(msg)
+		if resp.Status != cb.Status_SUCCESS {
+			return srv.Send(resp)
 		}
 
-		if payload.Header == nil {
-			logger.Warningf("Received malformed message, with missing header, dropping connection")
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST})
-		}
+		err = srv.Send(resp)
 
-		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
 		if err != nil {
-			if logger.IsEnabledFor(logging.WARNING) {
-				logger.Warningf("Received malformed message (bad channel header), dropping connection: %s", err)
-			}
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST})
+			return err
 		}
+	}
+}
 
-		if chdr.Type == int32(cb.HeaderType_CONFIG_UPDATE) {
-			logger.Debugf("Preprocessing CONFIG_UPDATE")
-			msg, err = bh.sm.Process(msg)
-			if err != nil {
-				if logger.IsEnabledFor(logging.WARNING) {
-					logger.Warningf("Rejecting CONFIG_UPDATE because: %s", err)
-				}
-				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST})
-			}
-
-			err = proto.Unmarshal(msg.Payload, payload)
-			if err != nil || payload.Header == nil {
-				logger.Criticalf("Generated bad transaction after CONFIG_UPDATE processing")
-				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_INTERNAL_SERVER_ERROR})
-			}
+// process enqueues a broadcast message on its chain, and returns the response
+// to send back to the client
+func (bh *handlerImpl) process(msg *cb.Envelope) (resp *ab.BroadcastResponse) {
+	payload, chdr, err := validation.CheckEnvelope(msg)
+	if err != nil {
+		if logger.IsEnabledFor(logging.WARNING) {
+			logger.Warningf("Received malformed message, dropping connection: %s", err)
+		}
+		return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST}
+	}
 
-			chdr, err = utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
-			if err != nil {
-				logger.Criticalf("Generated bad transaction after CONFIG_UPDATE processing (bad channel header): %s", err)
-				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_INTERNAL_SERVER_ERROR})
-			}
+	// trace the path of the message through the orderer, keyed by its txid
+	tr := tracing.New("broadcast", chdr.TxId, tracing.TxID(chdr.TxId))
+	defer func() {
+		tr.LazyPrintf("responded with status %s", resp.Status)
+		if resp.Status != cb.Status_SUCCESS {
+			tr.SetError()
+		}
+		tr.Finish()
+	}()
+	tr.LazyPrintf("channel %s, type %s", chdr.ChannelId, cb.HeaderType(chdr.Type))
 
-			if chdr.ChannelId == "" {
-				logger.Criticalf("Generated bad transaction after CONFIG_UPDATE processing (empty channel ID)")
-				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_INTERNAL_SERVER_ERROR})
-			}
+	if err = validation.CheckTimestamp(chdr, time.Now(), bh.timestampSkew); err != nil {
+		if logger.IsEnabledFor(logging.WARNING) {
+			logger.Warningf("Rejecting broadcast message for channel %s: %s", chdr.ChannelId, err)
 		}
+		return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()}
+	}
 
-		support, ok := bh.sm.GetChain(chdr.ChannelId)
-		if !ok {
+	if chdr.Type == int32(cb.HeaderType_CONFIG_UPDATE) {
+		logger.Debugf("Preprocessing CONFIG_UPDATE")
+		msg, err = bh.sm.Process(msg)
+		if err != nil {
 			if logger.IsEnabledFor(logging.WARNING) {
-				logger.Warningf("Rejecting broadcast because channel %s was not found", chdr.ChannelId)
+				logger.Warningf("Rejecting CONFIG_UPDATE because: %s", err)
 			}
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_NOT_FOUND})
+			return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST}
 		}
 
-		if logger.IsEnabledFor(logging.DEBUG) {
-			logger.Debugf("Broadcast is filtering message of type %s for channel %s", cb.HeaderType_name[chdr.Type], chdr.ChannelId)
+		err = proto.Unmarshal(msg.Payload, payload)
+		if err != nil || payload.Header == nil {
+			logger.Criticalf("Generated bad transaction after CONFIG_UPDATE processing")
+			return &ab.BroadcastResponse{Status: cb.Status_INTERNAL_SERVER_ERROR}
 		}
 
-		// Normal transaction for existing chain
-		_, filterErr := support.Filters().Apply(msg)
+		chdr, err = utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			logger.Criticalf("Generated bad transaction after CONFIG_UPDATE processing (bad channel header): %s", err)
+			return &ab.BroadcastResponse{Status: cb.Status_INTERNAL_SERVER_ERROR}
+		}
 
-		if filterErr != nil {
-			if logger.IsEnabledFor(logging.WARNING) {
-				logger.Warningf("Rejecting broadcast message because of filter error: %s", filterErr)
-			}
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST})
+		if chdr.ChannelId == "" {
+			logger.Criticalf("Generated bad transaction after CONFIG_UPDATE processing (empty channel ID)")
+			return &ab.BroadcastResponse{Status: cb.Status_INTERNAL_SERVER_ERROR}
 		}
+		tr.LazyPrintf("processed config update into %s for channel %s", chdr.TxId, chdr.ChannelId)
+	}
+
+	support, ok := bh.sm.GetChain(chdr.ChannelId)
+	if !ok {
+		if logger.IsEnabledFor(logging.WARNING) {
+			logger.Warningf("Rejecting broadcast because channel %s was not found", chdr.ChannelId)
+		}
+		return &ab.BroadcastResponse{Status: cb.Status_NOT_FOUND}
+	}
 
-		if !support.Enqueue(msg) {
-			logger.Infof("Consenter instructed us to shut down")
-			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE})
+	if logger.IsEnabledFor(logging.DEBUG) {
+		logger.Debugf("Broadcast is filtering message of type %s for channel %s", cb.HeaderType_name[chdr.Type], chdr.ChannelId)
+	}
+
+	if support.ConsensusState() == ab.ConsensusType_STATE_MAINTENANCE && chdr.Type != int32(cb.HeaderType_CONFIG) {
+		if logger.IsEnabledFor(logging.WARNING) {
+			logger.Warningf("Rejecting broadcast message of type %s because channel %s is in maintenance", cb.HeaderType_name[chdr.Type], chdr.ChannelId)
 		}
+		return &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "channel is in maintenance, only config transactions are accepted"}
+	}
 
-		if logger.IsEnabledFor(logging.DEBUG) {
-			logger.Debugf("Broadcast has successfully enqueued message of type %d for chain %s", chdr.Type, chdr.ChannelId)
+	if err = sizefilter.CheckMaxBytes(msg, support.AbsoluteMaxBytes()); err != nil {
+		if logger.IsEnabledFor(logging.WARNING) {
+			logger.Warningf("Rejecting broadcast message for channel %s: %s", chdr.ChannelId, err)
 		}
+		return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()}
+	}
 
-		err = srv.Send(&ab.BroadcastResponse{Status: cb.Status_SUCCESS})
+	// Normal transaction for existing chain
+	_, filterErr := support.Filters().Apply(msg)
 
-		if err != nil {
-			return err
+	if filterErr != nil {
+		if logger.IsEnabledFor(logging.WARNING) {
+			logger.Warningf("Rejecting broadcast message because of filter error: %s", filterErr)
 		}
+		return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST}
 	}
+	tr.LazyPrintf("filtered")
+
+	if !support.Enqueue(msg) {
+		logger.Infof("Consenter instructed us to shut down")
+		return &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE}
+	}
+
+	if logger.IsEnabledFor(logging.DEBUG) {
+		logger.Debugf("Broadcast has successfully enqueued message of type %d for chain %s", chdr.Type, chdr.ChannelId)
+	}
+
+	return &ab.BroadcastResponse{Status: cb.Status_SUCCESS}
 }
@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/orderer/common/filter"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -83,6 +84,8 @@ func (mm *mockSupportManager) Process(configTx *cb.Envelope) (*cb.Envelope, erro
 type mockSupport struct {
 	filters       *filter.RuleSet
 	rejectEnqueue bool
+	maxBytes      uint32
+	state         ab.ConsensusType_State
 }
 
 func (ms *mockSupport) Filters() *filter.RuleSet {
@@ -94,6 +97,14 @@ func (ms *mockSupport) Enqueue(env *cb.Envelope) bool {
 	return !ms.rejectEnqueue
 }
 
+func (ms *mockSupport) AbsoluteMaxBytes() uint32 {
+	return ms.maxBytes
+}
+
+func (ms *mockSupport) ConsensusState() ab.ConsensusType_State {
+	return ms.state
+}
+
 func makeConfigMessage(chainID string) *cb.Envelope {
 	payload := &cb.Payload{
 		Data: utils.MarshalOrPanic(&cb.ConfigEnvelope{}),
@@ -132,7 +143,8 @@ func getMockSupportManager() (*mockSupportManager, *mockSupport) {
 		chains: make(map[string]*mockSupport),
 	}
 	mSysChain := &mockSupport{
-		filters: filters,
+		filters:  filters,
+		maxBytes: 1024 * 1024,
 	}
 	mm.chains[string(systemChain)] = mSysChain
 	return mm, mSysChain
@@ -195,6 +207,102 @@ func TestEmptyEnvelope(t *testing.T) {
 	}
 }
 
+func TestMalformedChannelHeader(t *testing.T) {
+	mm, _ := getMockSupportManager()
+	bh := NewHandlerImpl(mm)
+	m := newMockB()
+	defer close(m.recvChan)
+	done := make(chan struct{})
+	go func() {
+		bh.Handle(m)
+		close(done)
+	}()
+
+	m.recvChan <- &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{
+		Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{ChannelId: systemChain, Epoch: 1})},
+	})}
+	reply := <-m.sendChan
+	if reply.Status != cb.Status_BAD_REQUEST {
+		t.Fatalf("Should have rejected the message with an invalid epoch")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Should have terminated the stream")
+	}
+}
+
+func TestMessageTooLarge(t *testing.T) {
+	mm, mSysChain := getMockSupportManager()
+	bh := NewHandlerImpl(mm)
+	m := newMockB()
+	defer close(m.recvChan)
+	done := make(chan struct{})
+	go func() {
+		bh.Handle(m)
+		close(done)
+	}()
+
+	mSysChain.maxBytes = 100
+	m.recvChan <- makeMessage(systemChain, make([]byte, 100))
+	reply := <-m.sendChan
+	assert.Equal(t, cb.Status_BAD_REQUEST, reply.Status, "Should have rejected the oversized message")
+	assert.Contains(t, reply.Info, "maximum allowed 100 bytes", "Should have reported the maximum message size")
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Should have terminated the stream")
+	}
+}
+
+func TestTimestampSkew(t *testing.T) {
+	mm, _ := getMockSupportManager()
+	bh := NewHandlerImplWithTimestampSkew(mm, time.Minute)
+	m := newMockB()
+	defer close(m.recvChan)
+	go bh.Handle(m)
+
+	makeTimestampedMessage := func(ts time.Time) *cb.Envelope {
+		return &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+			ChannelId: systemChain,
+			Timestamp: &timestamp.Timestamp{Seconds: ts.Unix()},
+		})}})}
+	}
+
+	m.recvChan <- makeTimestampedMessage(time.Now())
+	reply := <-m.sendChan
+	assert.Equal(t, cb.Status_SUCCESS, reply.Status, "Should have accepted a message with a current timestamp")
+
+	m.recvChan <- makeTimestampedMessage(time.Now().Add(-time.Hour))
+	reply = <-m.sendChan
+	assert.Equal(t, cb.Status_BAD_REQUEST, reply.Status, "Should have rejected a stale message")
+	assert.Contains(t, reply.Info, "clock skew", "Should have reported the tolerated clock skew")
+}
+
+func TestMaintenance(t *testing.T) {
+	mm, mSysChain := getMockSupportManager()
+	mSysChain.state = ab.ConsensusType_STATE_MAINTENANCE
+	mm.ProcessVal = &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+		ChannelId: systemChain,
+		Type:      int32(cb.HeaderType_CONFIG),
+	})}})}
+	bh := NewHandlerImpl(mm)
+	m := newMockB()
+	defer close(m.recvChan)
+	go bh.Handle(m)
+
+	m.recvChan <- makeConfigMessage(systemChain)
+	reply := <-m.sendChan
+	assert.Equal(t, cb.Status_SUCCESS, reply.Status, "Should have allowed a config transaction during maintenance")
+
+	m.recvChan <- makeMessage(systemChain, []byte("Some bytes"))
+	reply = <-m.sendChan
+	assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, reply.Status, "Should have rejected a normal transaction during maintenance")
+	assert.Contains(t, reply.Info, "maintenance", "Should have reported the maintenance")
+}
+
 func TestBadChannelId(t *testing.T) {
 	mm, _ := getMockSupportManager()
 	bh := NewHandlerImpl(mm)
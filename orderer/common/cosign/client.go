@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cosign
+
+import (
+	"sync"
+	"time"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// Client requests the signatures of other ordering nodes over the blocks
+// produced by this ordering node
+type Client struct {
+	dialOpts    []grpc.DialOption
+	timeout     time.Duration
+	maxAttempts int
+
+	lock  sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// NewClient creates a Client which connects to the ordering nodes with the
+// given dial options, waits at most timeout for them to sign a block, and
+// requests their signatures over a block at most maxAttempts times
+func NewClient(dialOpts []grpc.DialOption, timeout time.Duration, maxAttempts int) *Client {
+	return &Client{
+		dialOpts:    dialOpts,
+		timeout:     timeout,
+		maxAttempts: maxAttempts,
+		conns:       make(map[string]*grpc.ClientConn),
+	}
+}
+
+// MaxAttempts returns how many times the signatures over a block are requested
+func (c *Client) MaxAttempts() int {
+	return c.maxAttempts
+}
+
+// Cosign requests the signatures of the ordering nodes at the given addresses
+// over the header of a block of a chain, and returns the ones it obtained
+func (c *Client) Cosign(chainID string, addresses []string, header *cb.BlockHeader) []*cb.MetadataSignature {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	req := &ab.CosignRequest{ChannelId: chainID, Header: header}
+	results := make(chan *cb.MetadataSignature, len(addresses))
+	for _, address := range addresses {
+		go func(address string) {
+			results <- c.cosign(ctx, address, req)
+		}(address)
+	}
+
+	var signatures []*cb.MetadataSignature
+	for range addresses {
+		if signature := <-results; signature != nil {
+			signatures = append(signatures, signature)
+		}
+	}
+	return signatures
+}
+
+func (c *Client) cosign(ctx context.Context, address string, req *ab.CosignRequest) *cb.MetadataSignature {
+	conn, err := c.connection(address)
+	if err != nil {
+		logger.Warningf("[channel: %s] Could not connect to ordering node %s: %s", req.ChannelId, address, err)
+		return nil
+	}
+
+	resp, err := ab.NewBlockCosignerClient(conn).Cosign(ctx, req, grpc.FailFast(false))
+	if err != nil {
+		logger.Warningf("[channel: %s] Could not get the signature of ordering node %s over block %d: %s", req.ChannelId, address, req.Header.Number, err)
+		return nil
+	}
+	if resp.Status != cb.Status_SUCCESS || resp.Signature == nil {
+		logger.Warningf("[channel: %s] Ordering node %s did not sign block %d: %s %s", req.ChannelId, address, req.Header.Number, resp.Status, resp.Info)
+		return nil
+	}
+	return resp.Signature
+}
+
+// connection returns the connection to the ordering node at address, the
+// connections are kept open to be reused for the next blocks
+func (c *Client) connection(address string) (*grpc.ClientConn, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if conn, ok := c.conns[address]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.Dial(address, c.dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	c.conns[address] = conn
+	return conn, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cosign
+
+import (
+	"errors"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+
+	"github.com/op/go-logging"
+	"golang.org/x/net/context"
+)
+
+var logger = logging.MustGetLogger("orderer/common/cosign")
+
+// ErrHeaderMismatch is returned when an ordering node is asked to sign the
+// header of a block which differs from the one it produced
+var ErrHeaderMismatch = errors.New("the block header differs from the one of the block produced by this ordering node")
+
+// SupportManager provides a way for the Handler to look up the Support for a chain
+type SupportManager interface {
+	GetChain(chainID string) (Support, bool)
+}
+
+// Support provides the backing resources needed to co-sign the blocks of a chain
+type Support interface {
+	// SignBlockHeader waits until this ordering node produces the block with the number of the given
+	// header, or until done is closed, and returns its signature over the header if it is the one of
+	// the block it produced
+	SignBlockHeader(header *cb.BlockHeader, done <-chan struct{}) (*cb.MetadataSignature, error)
+}
+
+type handlerImpl struct {
+	sm SupportManager
+}
+
+// NewHandlerImpl creates an implementation of the ab.BlockCosignerServer interface
+func NewHandlerImpl(sm SupportManager) ab.BlockCosignerServer {
+	return &handlerImpl{
+		sm: sm,
+	}
+}
+
+// Cosign returns the signature of this ordering node over the header of a block it produced
+func (h *handlerImpl) Cosign(ctx context.Context, req *ab.CosignRequest) (*ab.CosignResponse, error) {
+	if req.Header == nil {
+		return &ab.CosignResponse{Status: cb.Status_BAD_REQUEST, Info: "missing block header"}, nil
+	}
+
+	support, ok := h.sm.GetChain(req.ChannelId)
+	if !ok {
+		return &ab.CosignResponse{Status: cb.Status_NOT_FOUND, Info: "channel not found"}, nil
+	}
+
+	signature, err := support.SignBlockHeader(req.Header, ctx.Done())
+	if err != nil {
+		if logger.IsEnabledFor(logging.WARNING) {
+			logger.Warningf("[channel: %s] Not signing block %d: %s", req.ChannelId, req.Header.Number, err)
+		}
+		if err == ErrHeaderMismatch {
+			return &ab.CosignResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()}, nil
+		}
+		return &ab.CosignResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()}, nil
+	}
+
+	logger.Debugf("[channel: %s] Signed block %d on behalf of another ordering node", req.ChannelId, req.Header.Number)
+	return &ab.CosignResponse{Status: cb.Status_SUCCESS, Signature: signature}, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cosign
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+var systemChain = "systemChain"
+
+type mockSupportManager struct {
+	chains map[string]*mockSupport
+}
+
+func (mm *mockSupportManager) GetChain(chainID string) (Support, bool) {
+	cs, ok := mm.chains[chainID]
+	return cs, ok
+}
+
+type mockSupport struct {
+	produced *cb.BlockHeader
+}
+
+func (ms *mockSupport) SignBlockHeader(header *cb.BlockHeader, done <-chan struct{}) (*cb.MetadataSignature, error) {
+	if ms.produced == nil || ms.produced.Number != header.Number {
+		<-done
+		return nil, fmt.Errorf("gave up waiting to produce block %d", header.Number)
+	}
+	if string(ms.produced.DataHash) != string(header.DataHash) {
+		return nil, ErrHeaderMismatch
+	}
+	return &cb.MetadataSignature{Signature: header.Bytes()}, nil
+}
+
+func newHandler(produced *cb.BlockHeader) ab.BlockCosignerServer {
+	return NewHandlerImpl(&mockSupportManager{
+		chains: map[string]*mockSupport{systemChain: &mockSupport{produced: produced}},
+	})
+}
+
+func TestCosign(t *testing.T) {
+	header := &cb.BlockHeader{Number: 1, DataHash: []byte("data")}
+	resp, err := newHandler(header).Cosign(context.Background(), &ab.CosignRequest{ChannelId: systemChain, Header: header})
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_SUCCESS, resp.Status)
+	assert.Equal(t, header.Bytes(), resp.Signature.Signature)
+}
+
+func TestCosignMissingHeader(t *testing.T) {
+	resp, err := newHandler(nil).Cosign(context.Background(), &ab.CosignRequest{ChannelId: systemChain})
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_BAD_REQUEST, resp.Status)
+}
+
+func TestCosignChainNotFound(t *testing.T) {
+	resp, err := newHandler(nil).Cosign(context.Background(), &ab.CosignRequest{ChannelId: "foo", Header: &cb.BlockHeader{}})
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_NOT_FOUND, resp.Status)
+}
+
+func TestCosignHeaderMismatch(t *testing.T) {
+	produced := &cb.BlockHeader{Number: 1, DataHash: []byte("data")}
+	header := &cb.BlockHeader{Number: 1, DataHash: []byte("other data")}
+	resp, err := newHandler(produced).Cosign(context.Background(), &ab.CosignRequest{ChannelId: systemChain, Header: header})
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_BAD_REQUEST, resp.Status)
+	assert.Nil(t, resp.Signature)
+}
+
+func TestCosignTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	resp, err := newHandler(nil).Cosign(ctx, &ab.CosignRequest{ChannelId: systemChain, Header: &cb.BlockHeader{Number: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, resp.Status)
+}
@@ -48,6 +48,10 @@ type Support interface {
 
 	// Reader returns the chain Reader for the chain
 	Reader() ledger.Reader
+
+	// ConsensusState returns the current state of the consensus for this chain, in maintenance only
+	// the orderers are delivered the blocks
+	ConsensusState() ab.ConsensusType_State
 }
 
 type deliverServer struct {
@@ -103,11 +107,16 @@ func (ds *deliverServer) Handle(srv ab.AtomicBroadcast_DeliverServer) error {
 			return sendStatusReply(srv, cb.Status_NOT_FOUND)
 		}
 
-		sf := sigfilter.New(policies.ChannelReaders, chain.PolicyManager())
+		policy := policies.ChannelReaders
+		if chain.ConsensusState() == ab.ConsensusType_STATE_MAINTENANCE {
+			policy = policies.ChannelOrdererReaders
+		}
+
+		sf := sigfilter.New(policy, chain.PolicyManager())
 		result, _ := sf.Apply(envelope)
 		if result != filter.Forward {
 			if logger.IsEnabledFor(logging.WARNING) {
-				logger.Warningf("Received unauthorized deliver request for channel %s", chdr.ChannelId)
+				logger.Warningf("Received unauthorized deliver request for channel %s against policy %s", chdr.ChannelId, policy)
 			}
 			return sendStatusReply(srv, cb.Status_FORBIDDEN)
 		}
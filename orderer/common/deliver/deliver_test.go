@@ -79,8 +79,9 @@ func (mm *mockSupportManager) GetChain(chainID string) (Support, bool) {
 }
 
 type mockSupport struct {
-	ledger        ledger.ReadWriter
-	policyManager *mockpolicies.Manager
+	ledger         ledger.ReadWriter
+	policyManager  *mockpolicies.Manager
+	consensusState ab.ConsensusType_State
 }
 
 func (mcs *mockSupport) PolicyManager() policies.Manager {
@@ -91,6 +92,10 @@ func (mcs *mockSupport) Reader() ledger.Reader {
 	return mcs.ledger
 }
 
+func (mcs *mockSupport) ConsensusState() ab.ConsensusType_State {
+	return mcs.consensusState
+}
+
 func NewRAMLedger() ledger.ReadWriter {
 	rlf := ramledger.New(ledgerSize + 1)
 	rl, _ := rlf.GetOrCreate(provisional.TestChainID)
@@ -263,6 +268,37 @@ func TestUnauthorizedSeek(t *testing.T) {
 	}
 }
 
+func TestMaintenanceSeek(t *testing.T) {
+	mm := newMockMultichainManager()
+	mm.chains[systemChainID].policyManager.PolicyMap = map[string]policies.Policy{
+		policies.ChannelOrdererReaders: &mockpolicies.Policy{Err: fmt.Errorf("Not an orderer")},
+	}
+
+	for _, state := range []ab.ConsensusType_State{ab.ConsensusType_STATE_NORMAL, ab.ConsensusType_STATE_MAINTENANCE} {
+		mm.chains[systemChainID].consensusState = state
+
+		m := newMockD()
+		ds := NewHandlerImpl(mm)
+
+		go ds.Handle(m)
+
+		m.recvChan <- makeSeek(systemChainID, &ab.SeekInfo{Start: seekSpecified(uint64(0)), Stop: seekSpecified(uint64(0)), Behavior: ab.SeekInfo_BLOCK_UNTIL_READY})
+
+		select {
+		case deliverReply := <-m.sendChan:
+			if state == ab.ConsensusType_STATE_MAINTENANCE && deliverReply.GetStatus() != cb.Status_FORBIDDEN {
+				t.Fatalf("Expected the request of a client that is not an orderer to be forbidden in maintenance")
+			}
+			if state == ab.ConsensusType_STATE_NORMAL && deliverReply.GetBlock() == nil {
+				t.Fatalf("Expected the genesis block to be delivered, got status %v", deliverReply.GetStatus())
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for the deliver reply")
+		}
+		close(m.recvChan)
+	}
+}
+
 func TestBadSeek(t *testing.T) {
 	mm := newMockMultichainManager()
 	for i := 1; i < ledgerSize; i++ {
@@ -17,6 +17,8 @@ limitations under the License.
 package sizefilter
 
 import (
+	"fmt"
+
 	"github.com/hyperledger/fabric/orderer/common/filter"
 	ab "github.com/hyperledger/fabric/protos/common"
 	logging "github.com/op/go-logging"
@@ -34,13 +36,35 @@ type maxBytesRule struct {
 }
 
 func (r *maxBytesRule) Apply(message *ab.Envelope) (filter.Action, filter.Committer) {
-	if size := messageByteSize(message); size > r.maxBytes {
-		logger.Warningf("%d byte message payload exceeds maximum allowed %d bytes", size, r.maxBytes)
+	if err := CheckMaxBytes(message, r.maxBytes); err != nil {
+		logger.Warning(err)
 		return filter.Reject, nil
 	}
 	return filter.Forward, nil
 }
 
+// MaxBytesExceededError is returned by CheckMaxBytes for messages larger than
+// the maximum allowed size
+type MaxBytesExceededError struct {
+	// Size is the size of the message, in bytes
+	Size uint32
+	// MaxBytes is the maximum allowed size, in bytes
+	MaxBytes uint32
+}
+
+func (e *MaxBytesExceededError) Error() string {
+	return fmt.Sprintf("%d byte message payload exceeds maximum allowed %d bytes", e.Size, e.MaxBytes)
+}
+
+// CheckMaxBytes returns a *MaxBytesExceededError if the message is larger
+// than maxBytes, and nil otherwise
+func CheckMaxBytes(message *ab.Envelope, maxBytes uint32) error {
+	if size := messageByteSize(message); size > maxBytes {
+		return &MaxBytesExceededError{Size: size, MaxBytes: maxBytes}
+	}
+	return nil
+}
+
 func messageByteSize(message *ab.Envelope) uint32 {
 	return uint32(len(message.Payload) + len(message.Signature))
 }
@@ -49,6 +49,24 @@ func TestMaxBytesRule(t *testing.T) {
 	})
 }
 
+func TestCheckMaxBytes(t *testing.T) {
+	dataSize := uint32(100)
+	maxBytes := calcMessageBytesForPayloadDataSize(dataSize)
+
+	if err := CheckMaxBytes(makeMessage(make([]byte, dataSize)), maxBytes); err != nil {
+		t.Fatalf("Should have accepted: %s", err)
+	}
+
+	err := CheckMaxBytes(makeMessage(make([]byte, dataSize+1)), maxBytes)
+	mbErr, ok := err.(*MaxBytesExceededError)
+	if !ok {
+		t.Fatalf("Should have rejected with a MaxBytesExceededError, got %v", err)
+	}
+	if mbErr.MaxBytes != maxBytes || mbErr.Size != maxBytes+1 {
+		t.Fatalf("Unexpected sizes in error: %s", mbErr)
+	}
+}
+
 func calcMessageBytesForPayloadDataSize(dataSize uint32) uint32 {
 	return messageByteSize(makeMessage(make([]byte, dataSize)))
 }
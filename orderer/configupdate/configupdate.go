@@ -25,7 +25,11 @@ import (
 
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/orderer/common/filter"
+	"github.com/hyperledger/fabric/orderer/common/sigfilter"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
 
 	"github.com/op/go-logging"
@@ -49,6 +53,13 @@ type SupportManager interface {
 type Support interface {
 	// ProposeConfigUpdate applies a CONFIG_UPDATE to an existing config to produce a *cb.ConfigEnvelope
 	ProposeConfigUpdate(env *cb.Envelope) (*cb.ConfigEnvelope, error)
+
+	// PolicyManager returns the current policy manager as specified by the chain configuration
+	PolicyManager() policies.Manager
+
+	// ConsensusState returns the current state of the consensus, in maintenance only the orderers
+	// may update the config
+	ConsensusState() ab.ConsensusType_State
 }
 
 type Processor struct {
@@ -107,6 +118,13 @@ func (p *Processor) Process(envConfigUpdate *cb.Envelope) (*cb.Envelope, error)
 }
 
 func (p *Processor) existingChannelConfig(envConfigUpdate *cb.Envelope, channelID string, support Support) (*cb.Envelope, error) {
+	if support.ConsensusState() == ab.ConsensusType_STATE_MAINTENANCE {
+		sf := sigfilter.New(policies.ChannelOrdererWriters, support.PolicyManager())
+		if result, _ := sf.Apply(envConfigUpdate); result != filter.Forward {
+			return nil, fmt.Errorf("Failing to process config update because channel %s is in maintenance and only the orderers may update its config", channelID)
+		}
+	}
+
 	configEnvelope, err := support.ProposeConfigUpdate(envConfigUpdate)
 	if err != nil {
 		return nil, err
@@ -144,6 +162,10 @@ func createInitialConfig(envConfigUpdate *cb.Envelope) (*cb.ConfigEnvelope, erro
 }
 
 func (p *Processor) newChannelConfig(channelID string, envConfigUpdate *cb.Envelope) (*cb.Envelope, error) {
+	if support, ok := p.manager.GetChain(p.systemChannelID); ok && support.ConsensusState() == ab.ConsensusType_STATE_MAINTENANCE {
+		return nil, fmt.Errorf("Failing to create channel %s because the system channel is in maintenance", channelID)
+	}
+
 	initialConfig, err := createInitialConfig(envConfigUpdate)
 	if err != nil {
 		return nil, err
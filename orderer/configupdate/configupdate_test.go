@@ -22,7 +22,10 @@ import (
 
 	"github.com/hyperledger/fabric/common/configtx"
 	mockcrypto "github.com/hyperledger/fabric/common/mocks/crypto"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
 
 	"github.com/op/go-logging"
@@ -35,6 +38,8 @@ func init() {
 
 type mockSupport struct {
 	ProposeConfigUpdateVal *cb.ConfigEnvelope
+	PolicyManagerVal       *mockpolicies.Manager
+	ConsensusStateVal      ab.ConsensusType_State
 }
 
 func (ms *mockSupport) ProposeConfigUpdate(env *cb.Envelope) (*cb.ConfigEnvelope, error) {
@@ -45,11 +50,23 @@ func (ms *mockSupport) ProposeConfigUpdate(env *cb.Envelope) (*cb.ConfigEnvelope
 	return ms.ProposeConfigUpdateVal, err
 }
 
+func (ms *mockSupport) PolicyManager() policies.Manager {
+	return ms.PolicyManagerVal
+}
+
+func (ms *mockSupport) ConsensusState() ab.ConsensusType_State {
+	return ms.ConsensusStateVal
+}
+
 type mockSupportManager struct {
-	GetChainVal *mockSupport
+	GetChainVal    *mockSupport
+	SystemChainVal *mockSupport
 }
 
 func (msm *mockSupportManager) GetChain(chainID string) (Support, bool) {
+	if chainID == systemChannelID {
+		return msm.SystemChainVal, msm.SystemChainVal != nil
+	}
 	return msm.GetChainVal, msm.GetChainVal != nil
 }
 
@@ -155,3 +172,28 @@ func TestNewChannel(t *testing.T) {
 
 	assert.Equal(t, int32(cb.HeaderType_ORDERER_TRANSACTION), chdr.Type, "Wrong wrapper tx type")
 }
+
+func TestMaintenance(t *testing.T) {
+	msm, p := newTestInstance()
+
+	testUpdate := testConfigUpdate()
+
+	dummyResult := &cb.ConfigEnvelope{LastUpdate: &cb.Envelope{Payload: []byte("DUMMY")}}
+
+	msm.GetChainVal = &mockSupport{
+		ProposeConfigUpdateVal: dummyResult,
+		PolicyManagerVal:       &mockpolicies.Manager{Policy: &mockpolicies.Policy{Err: fmt.Errorf("Not an orderer")}},
+		ConsensusStateVal:      ab.ConsensusType_STATE_MAINTENANCE,
+	}
+	_, err := p.Process(testUpdate)
+	assert.Error(t, err, "Config update not signed by the orderers in maintenance")
+
+	msm.GetChainVal.PolicyManagerVal.Policy.Err = nil
+	_, err = p.Process(testUpdate)
+	assert.NoError(t, err, "Config update signed by the orderers in maintenance")
+
+	msm.GetChainVal = nil
+	msm.SystemChainVal = &mockSupport{ConsensusStateVal: ab.ConsensusType_STATE_MAINTENANCE}
+	_, err = p.Process(testUpdate)
+	assert.Error(t, err, "Channel creation while the system channel is in maintenance")
+}
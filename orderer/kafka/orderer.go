@@ -29,24 +29,24 @@ import (
 )
 
 // New creates a Kafka-backed consenter. Called by orderer's main.go.
-func New(kv sarama.KafkaVersion, ro config.Retry, tls config.TLS) multichain.Consenter {
-	return newConsenter(kv, ro, tls, bfValue, pfValue, cfValue)
+func New(kv sarama.KafkaVersion, ro config.Retry, tls config.TLS, sasl config.SASL) multichain.Consenter {
+	return newConsenter(kv, ro, tls, sasl, bfValue, pfValue, cfValue)
 }
 
 // New calls here because we need to pass additional arguments to
 // the constructor and New() should only read from the config file.
-func newConsenter(kv sarama.KafkaVersion, ro config.Retry, tls config.TLS, bf bfType, pf pfType, cf cfType) multichain.Consenter {
-	return &consenterImpl{kv, ro, tls, bf, pf, cf}
+func newConsenter(kv sarama.KafkaVersion, ro config.Retry, tls config.TLS, sasl config.SASL, bf bfType, pf pfType, cf cfType) multichain.Consenter {
+	return &consenterImpl{kv, ro, tls, sasl, bf, pf, cf}
 }
 
 // bfType defines the signature of the broker constructor.
 type bfType func([]string, ChainPartition) (Broker, error)
 
 // pfType defines the signature of the producer constructor.
-type pfType func([]string, sarama.KafkaVersion, config.Retry, config.TLS) Producer
+type pfType func([]string, sarama.KafkaVersion, config.Retry, config.TLS, config.SASL) Producer
 
 // cfType defines the signature of the consumer constructor.
-type cfType func([]string, sarama.KafkaVersion, config.TLS, ChainPartition, int64) (Consumer, error)
+type cfType func([]string, sarama.KafkaVersion, config.TLS, config.SASL, ChainPartition, int64) (Consumer, error)
 
 // bfValue holds the value for the broker constructor that's used in the non-test case.
 var bfValue = func(brokers []string, cp ChainPartition) (Broker, error) {
@@ -54,13 +54,13 @@ var bfValue = func(brokers []string, cp ChainPartition) (Broker, error) {
 }
 
 // pfValue holds the value for the producer constructor that's used in the non-test case.
-var pfValue = func(brokers []string, kafkaVersion sarama.KafkaVersion, retryOptions config.Retry, tls config.TLS) Producer {
-	return newProducer(brokers, kafkaVersion, retryOptions, tls)
+var pfValue = func(brokers []string, kafkaVersion sarama.KafkaVersion, retryOptions config.Retry, tls config.TLS, sasl config.SASL) Producer {
+	return newProducer(brokers, kafkaVersion, retryOptions, tls, sasl)
 }
 
 // cfValue holds the value for the consumer constructor that's used in the non-test case.
-var cfValue = func(brokers []string, kafkaVersion sarama.KafkaVersion, tls config.TLS, cp ChainPartition, offset int64) (Consumer, error) {
-	return newConsumer(brokers, kafkaVersion, tls, cp, offset)
+var cfValue = func(brokers []string, kafkaVersion sarama.KafkaVersion, tls config.TLS, sasl config.SASL, cp ChainPartition, offset int64) (Consumer, error) {
+	return newConsumer(brokers, kafkaVersion, tls, sasl, cp, offset)
 }
 
 // consenterImpl holds the implementation of type that satisfies the
@@ -68,12 +68,13 @@ var cfValue = func(brokers []string, kafkaVersion sarama.KafkaVersion, tls confi
 // is needed because that is what the HandleChain contract requires.
 // The latter is needed for testing.
 type consenterImpl struct {
-	kv  sarama.KafkaVersion
-	ro  config.Retry
-	tls config.TLS
-	bf  bfType
-	pf  pfType
-	cf  cfType
+	kv   sarama.KafkaVersion
+	ro   config.Retry
+	tls  config.TLS
+	sasl config.SASL
+	bf   bfType
+	pf   pfType
+	cf   cfType
 }
 
 // HandleChain creates/returns a reference to a Chain for the given set of support resources.
@@ -115,7 +116,7 @@ func newChain(consenter testableConsenter, support multichain.ConsenterSupport,
 		batchTimeout:        support.SharedConfig().BatchTimeout(),
 		lastOffsetPersisted: lastOffsetPersisted,
 		lastCutBlock:        lastCutBlock,
-		producer:            consenter.prodFunc()(support.SharedConfig().KafkaBrokers(), consenter.kafkaVersion(), consenter.retryOptions(), consenter.tlsConfig()),
+		producer:            consenter.prodFunc()(support.SharedConfig().KafkaBrokers(), consenter.kafkaVersion(), consenter.retryOptions(), consenter.tlsConfig(), consenter.saslConfig()),
 		halted:              false, // Redundant as the default value for booleans is false but added for readability
 		exitChan:            make(chan struct{}),
 		haltedChan:          make(chan struct{}),
@@ -129,6 +130,7 @@ type testableConsenter interface {
 	kafkaVersion() sarama.KafkaVersion
 	retryOptions() config.Retry
 	tlsConfig() config.TLS
+	saslConfig() config.SASL
 	brokFunc() bfType
 	prodFunc() pfType
 	consFunc() cfType
@@ -137,6 +139,7 @@ type testableConsenter interface {
 func (co *consenterImpl) kafkaVersion() sarama.KafkaVersion { return co.kv }
 func (co *consenterImpl) retryOptions() config.Retry        { return co.ro }
 func (co *consenterImpl) tlsConfig() config.TLS             { return co.tls }
+func (co *consenterImpl) saslConfig() config.SASL           { return co.sasl }
 func (co *consenterImpl) brokFunc() bfType                  { return co.bf }
 func (co *consenterImpl) prodFunc() pfType                  { return co.pf }
 func (co *consenterImpl) consFunc() cfType                  { return co.cf }
@@ -177,7 +180,7 @@ func (ch *chainImpl) Start() {
 	logger.Debugf("[channel: %s] CONNECT message posted successfully", ch.support.ChainID())
 
 	// 2. Set up the listener/consumer for this partition.
-	consumer, err := ch.consenter.consFunc()(ch.support.SharedConfig().KafkaBrokers(), ch.consenter.kafkaVersion(), ch.consenter.tlsConfig(), ch.partition, ch.lastOffsetPersisted+1)
+	consumer, err := ch.consenter.consFunc()(ch.support.SharedConfig().KafkaBrokers(), ch.consenter.kafkaVersion(), ch.consenter.tlsConfig(), ch.consenter.saslConfig(), ch.partition, ch.lastOffsetPersisted+1)
 	if err != nil {
 		logger.Criticalf("[channel: %s] Cannot retrieve requested offset from Kafka cluster: %s", ch.support.ChainID(), err)
 		close(ch.exitChan)
@@ -26,7 +26,7 @@ import (
 	ab "github.com/hyperledger/fabric/protos/orderer"
 )
 
-func newBrokerConfig(kafkaVersion sarama.KafkaVersion, chosenStaticPartition int32, tlsConfig config.TLS) *sarama.Config {
+func newBrokerConfig(kafkaVersion sarama.KafkaVersion, chosenStaticPartition int32, tlsConfig config.TLS, saslConfig config.SASL) *sarama.Config {
 	brokerConfig := sarama.NewConfig()
 
 	brokerConfig.Consumer.Return.Errors = true
@@ -53,6 +53,14 @@ func newBrokerConfig(kafkaVersion sarama.KafkaVersion, chosenStaticPartition int
 		}
 	}
 
+	// Only SASL/PLAIN is supported by sarama, the mechanism is checked when
+	// the configuration is loaded
+	brokerConfig.Net.SASL.Enable = saslConfig.Enabled
+	if brokerConfig.Net.SASL.Enable {
+		brokerConfig.Net.SASL.User = saslConfig.User
+		brokerConfig.Net.SASL.Password = saslConfig.Password
+	}
+
 	// Set equivalent of Kafka producer config max.request.bytes to the default
 	// value of a Kafka broker's socket.request.max.bytes property (100 MiB).
 	brokerConfig.Producer.MaxMessageBytes = int(sarama.MaxRequestSize)
@@ -39,7 +39,7 @@ func TestProducerConfigMessageMaxBytes(t *testing.T) {
 	})
 
 	mockTLS := config.TLS{Enabled: false}
-	config := newBrokerConfig(testConf.Kafka.Version, rawPartition, mockTLS)
+	config := newBrokerConfig(testConf.Kafka.Version, rawPartition, mockTLS, config.SASL{})
 	producer, err := sarama.NewSyncProducer([]string{broker.Addr()}, config)
 	if err != nil {
 		t.Fatal(err)
@@ -90,7 +90,7 @@ func TestNewBrokerConfig(t *testing.T) {
 		"ProduceRequest": sarama.NewMockProduceResponse(t),
 	})
 
-	config := newBrokerConfig(testConf.Kafka.Version, differentPartition, config.TLS{Enabled: false})
+	config := newBrokerConfig(testConf.Kafka.Version, differentPartition, config.TLS{Enabled: false}, config.SASL{})
 	producer, err := sarama.NewSyncProducer([]string{broker.Addr()}, config)
 	if err != nil {
 		t.Fatal("Failed to create producer:", err)
@@ -125,7 +125,7 @@ func TestTLSConfigEnabled(t *testing.T) {
 		PrivateKey:  privateKey,
 		Certificate: publicKey,
 		RootCAs:     []string{caPublicKey},
-	})
+	}, config.SASL{})
 
 	assert.True(t, config.Net.TLS.Enable)
 	assert.NotNil(t, config.Net.TLS.Config)
@@ -150,13 +150,39 @@ func TestTLSConfigDisabled(t *testing.T) {
 		PrivateKey:  privateKey,
 		Certificate: publicKey,
 		RootCAs:     []string{caPublicKey},
-	})
+	}, config.SASL{})
 
 	assert.False(t, config.Net.TLS.Enable)
 	assert.Zero(t, config.Net.TLS.Config)
 
 }
 
+func TestSASLConfig(t *testing.T) {
+	t.Run("Enabled", func(t *testing.T) {
+		config := newBrokerConfig(testConf.Kafka.Version, 0, config.TLS{Enabled: false}, config.SASL{
+			Enabled:   true,
+			Mechanism: "PLAIN",
+			User:      "user",
+			Password:  "secret",
+		})
+
+		assert.True(t, config.Net.SASL.Enable)
+		assert.Equal(t, "user", config.Net.SASL.User)
+		assert.Equal(t, "secret", config.Net.SASL.Password)
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		config := newBrokerConfig(testConf.Kafka.Version, 0, config.TLS{Enabled: false}, config.SASL{
+			Enabled:  false,
+			User:     "user",
+			Password: "secret",
+		})
+
+		assert.False(t, config.Net.SASL.Enable)
+		assert.Empty(t, config.Net.SASL.User)
+		assert.Empty(t, config.Net.SASL.Password)
+	})
+}
+
 func TestTLSConfigBadCert(t *testing.T) {
 	publicKey, privateKey, err := util.GenerateMockPublicPrivateKeyPairPEM(false)
 	if err != nil {
@@ -174,7 +200,7 @@ func TestTLSConfigBadCert(t *testing.T) {
 				PrivateKey:  privateKey,
 				Certificate: "TRASH",
 				RootCAs:     []string{caPublicKey},
-			})
+			}, config.SASL{})
 		})
 	})
 	t.Run("BadPublicKey", func(t *testing.T) {
@@ -184,7 +210,7 @@ func TestTLSConfigBadCert(t *testing.T) {
 				PrivateKey:  "TRASH",
 				Certificate: publicKey,
 				RootCAs:     []string{caPublicKey},
-			})
+			}, config.SASL{})
 		})
 	})
 	t.Run("BadRootCAs", func(t *testing.T) {
@@ -194,7 +220,7 @@ func TestTLSConfigBadCert(t *testing.T) {
 				PrivateKey:  privateKey,
 				Certificate: publicKey,
 				RootCAs:     []string{"TRASH"},
-			})
+			}, config.SASL{})
 		})
 	})
 }
@@ -46,7 +46,9 @@ func (flf *fileLedgerFactory) GetOrCreate(chainID string) (ledger.ReadWriter, er
 	if err != nil {
 		return nil, err
 	}
-	ledger = &fileLedger{blockStore: blockStore, signal: make(chan struct{})}
+	fl := &fileLedger{blockStore: blockStore, signal: make(chan struct{})}
+	fl.oldest = fl.findOldestBlockNumber()
+	ledger = fl
 	flf.ledgers[key] = ledger
 	return ledger, nil
 }
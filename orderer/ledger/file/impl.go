@@ -17,6 +17,8 @@ limitations under the License.
 package fileledger
 
 import (
+	"fmt"
+
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	ledger "github.com/hyperledger/fabric/orderer/ledger"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -34,6 +36,7 @@ func init() {
 
 type fileLedger struct {
 	blockStore blkstorage.BlockStore
+	oldest     uint64
 	signal     chan struct{}
 }
 
@@ -72,7 +75,7 @@ func (i *fileLedgerIterator) ReadyChan() <-chan struct{} {
 func (fl *fileLedger) Iterator(startPosition *ab.SeekPosition) (ledger.Iterator, uint64) {
 	switch start := startPosition.Type.(type) {
 	case *ab.SeekPosition_Oldest:
-		return &fileLedgerIterator{ledger: fl, blockNumber: 0}, 0
+		return &fileLedgerIterator{ledger: fl, blockNumber: fl.oldest}, fl.oldest
 	case *ab.SeekPosition_Newest:
 		info, err := fl.blockStore.GetBlockchainInfo()
 		if err != nil {
@@ -82,7 +85,7 @@ func (fl *fileLedger) Iterator(startPosition *ab.SeekPosition) (ledger.Iterator,
 		return &fileLedgerIterator{ledger: fl, blockNumber: newestBlockNumber}, newestBlockNumber
 	case *ab.SeekPosition_Specified:
 		height := fl.Height()
-		if start.Specified.Number > height {
+		if start.Specified.Number > height || start.Specified.Number < fl.oldest {
 			return &ledger.NotFoundErrorIterator{}, 0
 		}
 		return &fileLedgerIterator{ledger: fl, blockNumber: start.Specified.Number}, start.Specified.Number
@@ -91,6 +94,27 @@ func (fl *fileLedger) Iterator(startPosition *ab.SeekPosition) (ledger.Iterator,
 	return &ledger.NotFoundErrorIterator{}, 0
 }
 
+// findOldestBlockNumber returns the number of the first block on the ledger,
+// which is not the genesis block if the ledger was bootstrapped from a later
+// block. As the blocks of the ledger are contiguous, it is found by bisection
+// when the ledger is opened, and kept in oldest from then on.
+func (fl *fileLedger) findOldestBlockNumber() uint64 {
+	height := fl.Height()
+	if height == 0 {
+		return 0
+	}
+	low, high := uint64(0), height-1
+	for low < high {
+		mid := low + (high-low)/2
+		if _, err := fl.blockStore.RetrieveBlockByNumber(mid); err == nil {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return low
+}
+
 // Height returns the number of blocks on the ledger
 func (fl *fileLedger) Height() uint64 {
 	info, err := fl.blockStore.GetBlockchainInfo()
@@ -102,10 +126,29 @@ func (fl *fileLedger) Height() uint64 {
 
 // Append a new block to the ledger
 func (fl *fileLedger) Append(block *cb.Block) error {
-	err := fl.blockStore.AddBlock(block)
+	var err error
+	if fl.Height() == 0 && block.Header.Number != 0 {
+		// The first block of an empty ledger may be a later block than the
+		// genesis block, to bootstrap the ledger from it
+		err = fl.bootstrap(block)
+	} else {
+		err = fl.blockStore.AddBlock(block)
+	}
 	if err == nil {
 		close(fl.signal)
 		fl.signal = make(chan struct{})
 	}
 	return err
 }
+
+func (fl *fileLedger) bootstrap(block *cb.Block) error {
+	blockStore, ok := fl.blockStore.(blkstorage.BootstrappableBlockStore)
+	if !ok {
+		return fmt.Errorf("Block number should have been 0 but was %d", block.Header.Number)
+	}
+	if err := blockStore.BootstrapBlock(block); err != nil {
+		return err
+	}
+	fl.oldest = block.Header.Number
+	return nil
+}
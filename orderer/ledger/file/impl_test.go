@@ -235,3 +235,52 @@ func TestBlockedRetrieval(t *testing.T) {
 		t.Fatalf("Expected to successfully retrieve the second block")
 	}
 }
+
+func TestBootstrapFromLaterBlock(t *testing.T) {
+	name, err := ioutil.TempDir("", "hyperledger_fabric")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	tev := &testEnv{location: name, t: t, flf: New(name)}
+	defer tev.tearDown()
+	l, err := tev.flf.GetOrCreate(provisional.TestChainID)
+	if err != nil {
+		t.Fatalf("Error creating chain: %s", err)
+	}
+	fl := l.(*fileLedger)
+
+	if err = fl.Append(cb.NewBlock(5, []byte("previous hash"))); err != nil {
+		t.Fatalf("Should have bootstrapped the ledger from block 5: %s", err)
+	}
+	if err = fl.Append(ledger.CreateNextBlock(fl, []*cb.Envelope{&cb.Envelope{Payload: []byte("My Data")}})); err != nil {
+		t.Fatalf("Error appending block: %s", err)
+	}
+	if fl.Height() != 7 {
+		t.Fatalf("Block height should be 7, got %d", fl.Height())
+	}
+
+	it, num := fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{}})
+	if num != 5 {
+		t.Fatalf("Expected the oldest block to be 5, but got %d", num)
+	}
+	block, status := it.Next()
+	if status != cb.Status_SUCCESS || block.Header.Number != 5 {
+		t.Fatalf("Expected to successfully retrieve block 5")
+	}
+
+	it, _ = fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 3}}})
+	if _, status = it.Next(); status != cb.Status_NOT_FOUND {
+		t.Fatalf("Expected block 3 not to be found, got status %v", status)
+	}
+
+	// Reopen the ledger, the oldest block is found again
+	tev.flf.Close()
+	tev.flf = New(name)
+	l, err = tev.flf.GetOrCreate(provisional.TestChainID)
+	if err != nil {
+		t.Fatalf("Error reopening chain: %s", err)
+	}
+	if oldest := l.(*fileLedger).oldest; oldest != 5 {
+		t.Fatalf("Expected the oldest block to be 5 after reopening, but got %d", oldest)
+	}
+}
@@ -70,14 +70,16 @@ func newChain(directory string) ledger.ReadWriter {
 	return jl
 }
 
-// initializeBlockHeight verifies that all blocks exist between 0 and the block
-// height, and populates the lastHash
+// initializeBlockHeight verifies that all blocks exist between the oldest block,
+// which is the genesis block unless the chain was bootstrapped from a later
+// block, and the block height, and populates the lastHash
 func (jl *jsonLedger) initializeBlockHeight() {
 	infos, err := ioutil.ReadDir(jl.directory)
 	if err != nil {
 		logger.Panic(err)
 	}
 	nextNumber := uint64(0)
+	first := true
 	for _, info := range infos {
 		if info.IsDir() {
 			continue
@@ -87,6 +89,11 @@ func (jl *jsonLedger) initializeBlockHeight() {
 		if err != nil {
 			continue
 		}
+		if first {
+			jl.oldest = number
+			nextNumber = number
+			first = false
+		}
 		if number != nextNumber {
 			logger.Panicf("Missing block %d in the chain", nextNumber)
 		}
@@ -51,6 +51,7 @@ type cursor struct {
 type jsonLedger struct {
 	directory      string
 	fqFormatString string
+	oldest         uint64
 	height         uint64
 	signal         chan struct{}
 	lastHash       []byte
@@ -105,12 +106,12 @@ func (cu *cursor) ReadyChan() <-chan struct{} {
 func (jl *jsonLedger) Iterator(startPosition *ab.SeekPosition) (ledger.Iterator, uint64) {
 	switch start := startPosition.Type.(type) {
 	case *ab.SeekPosition_Oldest:
-		return &cursor{jl: jl, blockNumber: 0}, 0
+		return &cursor{jl: jl, blockNumber: jl.oldest}, jl.oldest
 	case *ab.SeekPosition_Newest:
 		high := jl.height - 1
 		return &cursor{jl: jl, blockNumber: high}, high
 	case *ab.SeekPosition_Specified:
-		if start.Specified.Number > jl.height {
+		if start.Specified.Number > jl.height || start.Specified.Number < jl.oldest {
 			return &ledger.NotFoundErrorIterator{}, 0
 		}
 		return &cursor{jl: jl, blockNumber: start.Specified.Number}, start.Specified.Number
@@ -127,17 +128,23 @@ func (jl *jsonLedger) Height() uint64 {
 
 // Append appends a new block to the ledger
 func (jl *jsonLedger) Append(block *cb.Block) error {
-	if block.Header.Number != jl.height {
-		return fmt.Errorf("Block number should have been %d but was %d", jl.height, block.Header.Number)
-	}
+	// The first block of an empty ledger may be a later block than the
+	// genesis block, to bootstrap the ledger from it
+	if jl.height == 0 {
+		jl.oldest = block.Header.Number
+	} else {
+		if block.Header.Number != jl.height {
+			return fmt.Errorf("Block number should have been %d but was %d", jl.height, block.Header.Number)
+		}
 
-	if !bytes.Equal(block.Header.PreviousHash, jl.lastHash) {
-		return fmt.Errorf("Block should have had previous hash of %x but was %x", jl.lastHash, block.Header.PreviousHash)
+		if !bytes.Equal(block.Header.PreviousHash, jl.lastHash) {
+			return fmt.Errorf("Block should have had previous hash of %x but was %x", jl.lastHash, block.Header.PreviousHash)
+		}
 	}
 
 	jl.writeBlock(block)
 	jl.lastHash = block.Header.Hash()
-	jl.height++
+	jl.height = block.Header.Number + 1
 	close(jl.signal)
 	jl.signal = make(chan struct{})
 	return nil
@@ -133,6 +133,15 @@ func (rl *ramLedger) Height() uint64 {
 
 // Append appends a new block to the ledger
 func (rl *ramLedger) Append(block *cb.Block) error {
+	if rl.newest.block.Header.Number == ^uint64(0) && block.Header.Number != 0 {
+		// The ledger is bootstrapped from a later block than the genesis block,
+		// the pre-genesis block is dropped so that this block becomes the oldest
+		rl.appendBlock(block)
+		rl.oldest = rl.newest
+		rl.size = 1
+		return nil
+	}
+
 	if block.Header.Number != rl.newest.block.Header.Number+1 {
 		return fmt.Errorf("Block number should have been %d but was %d",
 			rl.newest.block.Header.Number+1, block.Header.Number)
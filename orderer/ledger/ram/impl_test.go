@@ -21,6 +21,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 
 	logging "github.com/op/go-logging"
 )
@@ -106,3 +107,38 @@ func TestTruncationSafety(t *testing.T) {
 		t.Fatalf("The iterator should have found %d new blocks but found %d", newBlocks, count)
 	}
 }
+
+// TestBootstrapFromLaterBlock checks that a ledger bootstrapped from a later block than the genesis block
+// starts at this block
+func TestBootstrapFromLaterBlock(t *testing.T) {
+	chain, err := New(3).GetOrCreate(provisional.TestChainID)
+	if err != nil {
+		panic(err)
+	}
+	rl := chain.(*ramLedger)
+
+	bootstrapBlock := cb.NewBlock(5, []byte("previous hash"))
+	if err = rl.Append(bootstrapBlock); err != nil {
+		t.Fatalf("Should have bootstrapped the ledger from block 5: %s", err)
+	}
+	if err = rl.Append(cb.NewBlock(6, bootstrapBlock.Header.Hash())); err != nil {
+		t.Fatalf("Error appending block: %s", err)
+	}
+	if rl.Height() != 7 {
+		t.Fatalf("Block height should be 7, got %d", rl.Height())
+	}
+
+	it, num := rl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{}})
+	if num != 5 {
+		t.Fatalf("Expected the oldest block to be 5, but got %d", num)
+	}
+	block, status := it.Next()
+	if status != cb.Status_SUCCESS || block.Header.Number != 5 {
+		t.Fatalf("Expected to successfully retrieve block 5")
+	}
+
+	it, _ = rl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: 3}}})
+	if _, status = it.Next(); status != cb.Status_NOT_FOUND {
+		t.Fatalf("Expected block 3 not to be found, got status %v", status)
+	}
+}
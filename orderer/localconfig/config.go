@@ -32,6 +32,7 @@ import (
 	"path/filepath"
 
 	bccsp "github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/msp/enrollment"
 )
 
 const (
@@ -66,10 +67,14 @@ type General struct {
 	GenesisProfile string
 	GenesisFile    string
 	Profile        Profile
+	Tracing        Tracing
+	Cosign         Cosign
+	TimestampSkew  time.Duration
 	LogLevel       string
 	LocalMSPDir    string
 	LocalMSPID     string
 	BCCSP          *bccsp.FactoryOpts
+	Enrollment     enrollment.Config
 }
 
 // TLS contains config for TLS connections.
@@ -82,6 +87,13 @@ type TLS struct {
 	ClientRootCAs     []string
 }
 
+// Cosign contains config for the collection of the signatures of the other
+// ordering nodes over blocks, when the BlockValidation policy requires them.
+type Cosign struct {
+	Timeout     time.Duration
+	MaxAttempts int
+}
+
 // Genesis is a deprecated structure which was used to put
 // values into the genesis block, but this is now handled elsewhere.
 // SBFT did not reference these values via the genesis block however
@@ -98,6 +110,11 @@ type Profile struct {
 	Address string
 }
 
+// Tracing contains configuration for the export of the transaction traces.
+type Tracing struct {
+	Endpoint string
+}
+
 // RAMLedger contains configuration for the RAM ledger.
 type RAMLedger struct {
 	HistorySize uint
@@ -115,6 +132,15 @@ type Kafka struct {
 	Verbose bool
 	Version sarama.KafkaVersion // TODO Move this to global config
 	TLS     TLS
+	SASL    SASL
+}
+
+// SASL contains config for SASL authentication to the Kafka brokers.
+type SASL struct {
+	Enabled   bool
+	Mechanism string
+	User      string
+	Password  string
 }
 
 // SbftLocal contains configuration for the SBFT peer/replica.
@@ -165,6 +191,10 @@ var defaults = TopLevel{
 			Enabled: false,
 			Address: "0.0.0.0:6060",
 		},
+		Cosign: Cosign{
+			Timeout:     5 * time.Second,
+			MaxAttempts: 10,
+		},
 		LogLevel:    "INFO",
 		LocalMSPDir: "msp",
 		LocalMSPID:  "DEFAULT",
@@ -187,6 +217,10 @@ var defaults = TopLevel{
 		TLS: TLS{
 			Enabled: false,
 		},
+		SASL: SASL{
+			Enabled:   false,
+			Mechanism: "PLAIN",
+		},
 	},
 	Genesis: Genesis{
 		SbftShared: SbftShared{
@@ -225,12 +259,27 @@ func (c *TopLevel) initDefaults() {
 			c.General.GenesisFile = defaults.General.GenesisFile
 		case c.General.GenesisProfile == "":
 			c.General.GenesisProfile = defaults.General.GenesisProfile
+		case c.General.Cosign.Timeout == 0:
+			logger.Infof("General.Cosign.Timeout unset, setting to %s", defaults.General.Cosign.Timeout)
+			c.General.Cosign.Timeout = defaults.General.Cosign.Timeout
+		case c.General.Cosign.MaxAttempts == 0:
+			logger.Infof("General.Cosign.MaxAttempts unset, setting to %d", defaults.General.Cosign.MaxAttempts)
+			c.General.Cosign.MaxAttempts = defaults.General.Cosign.MaxAttempts
 		case c.Kafka.TLS.Enabled && c.Kafka.TLS.Certificate == "":
 			logger.Panicf("General.Kafka.TLS.Certificate must be set if General.Kafka.TLS.Enabled is set to true.")
 		case c.Kafka.TLS.Enabled && c.Kafka.TLS.PrivateKey == "":
 			logger.Panicf("General.Kafka.TLS.PrivateKey must be set if General.Kafka.TLS.Enabled is set to true.")
 		case c.Kafka.TLS.Enabled && c.Kafka.TLS.RootCAs == nil:
 			logger.Panicf("General.Kafka.TLS.CertificatePool must be set if General.Kafka.TLS.Enabled is set to true.")
+		case c.Kafka.SASL.Enabled && c.Kafka.SASL.Mechanism == "":
+			logger.Infof("Kafka.SASL.Mechanism unset, setting to %s", defaults.Kafka.SASL.Mechanism)
+			c.Kafka.SASL.Mechanism = defaults.Kafka.SASL.Mechanism
+		case c.Kafka.SASL.Enabled && c.Kafka.SASL.Mechanism != "PLAIN":
+			logger.Panicf("Kafka.SASL.Mechanism %s is not supported, the Kafka client only supports PLAIN.", c.Kafka.SASL.Mechanism)
+		case c.Kafka.SASL.Enabled && c.Kafka.SASL.User == "":
+			logger.Panicf("Kafka.SASL.User must be set if Kafka.SASL.Enabled is set to true.")
+		case c.Kafka.SASL.Enabled && c.Kafka.SASL.Password == "":
+			logger.Panicf("Kafka.SASL.Password must be set if Kafka.SASL.Enabled is set to true.")
 		case c.General.Profile.Enabled && (c.General.Profile.Address == ""):
 			logger.Infof("Profiling enabled and General.Profile.Address unset, setting to %s", defaults.General.Profile.Address)
 			c.General.Profile.Address = defaults.General.Profile.Address
@@ -282,6 +331,7 @@ func (c *TopLevel) completeInitialization(configDir string) {
 
 	cf.TranslatePathInPlace(configDir, &c.General.GenesisFile)
 	cf.TranslatePathInPlace(configDir, &c.General.LocalMSPDir)
+	c.General.Enrollment.TranslatePaths(configDir)
 }
 
 // Load parses the orderer.yaml file and environment, producing a struct suitable for config use
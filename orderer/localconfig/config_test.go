@@ -107,3 +107,28 @@ func TestKafkaTLSConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestKafkaSASLConfig(t *testing.T) {
+	testCases := []struct {
+		name        string
+		sasl        SASL
+		shouldPanic bool
+	}{
+		{"Disabled", SASL{Enabled: false}, false},
+		{"EnabledPlain", SASL{Enabled: true, Mechanism: "PLAIN", User: "user", Password: "secret"}, false},
+		{"EnabledDefaultMechanism", SASL{Enabled: true, User: "user", Password: "secret"}, false},
+		{"EnabledUnsupportedMechanism", SASL{Enabled: true, Mechanism: "SCRAM-SHA-256", User: "user", Password: "secret"}, true},
+		{"EnabledNoUser", SASL{Enabled: true, Mechanism: "PLAIN", Password: "secret"}, true},
+		{"EnabledNoPassword", SASL{Enabled: true, Mechanism: "PLAIN", User: "user"}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uconf := &TopLevel{Kafka: Kafka{SASL: tc.sasl}}
+			if tc.shouldPanic {
+				assert.Panics(t, func() { uconf.completeInitialization(DummyPath) }, "should panic")
+			} else {
+				assert.NotPanics(t, func() { uconf.completeInitialization(DummyPath) }, "should not panic")
+			}
+		})
+	}
+}
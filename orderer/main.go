@@ -30,6 +30,7 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
+	"github.com/hyperledger/fabric/orderer/common/cosign"
 	"github.com/hyperledger/fabric/orderer/kafka"
 	"github.com/hyperledger/fabric/orderer/localconfig"
 	"github.com/hyperledger/fabric/orderer/multichain"
@@ -41,6 +42,8 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/common/tracing"
+	"github.com/hyperledger/fabric/msp/enrollment"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	logging "github.com/op/go-logging"
 )
@@ -65,6 +68,15 @@ func main() {
 		}()
 	}
 
+	tracing.Init("orderer", conf.General.Tracing.Endpoint)
+
+	// Enroll with the Fabric CA, if configured, before loading the TLS and MSP material
+	err := enrollment.Enroll(&conf.General.Enrollment, conf.General.LocalMSPDir, conf.General.BCCSP,
+		conf.General.TLS.Certificate, conf.General.TLS.PrivateKey)
+	if err != nil {
+		logger.Fatal("Failed to enroll with the Fabric CA:", err)
+	}
+
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", conf.General.ListenAddress, conf.General.ListenPort))
 	if err != nil {
 		logger.Error("Failed to listen:", err)
@@ -144,6 +156,16 @@ func main() {
 			logger.Panic("Unknown genesis method:", conf.General.GenesisMethod)
 		}
 
+		// A later config block may be provided instead of the genesis block to
+		// recover the system chain from its current config
+		if err = checkBootstrapBlock(genesisBlock); err != nil {
+			logger.Error("Cannot bootstrap the system chain:", err)
+			return
+		}
+		if genesisBlock.Header.Number > 0 {
+			logger.Infof("Bootstrapping the system chain from config block %d", genesisBlock.Header.Number)
+		}
+
 		chainID, err := utils.GetChainIDFromBlock(genesisBlock)
 		if err != nil {
 			logger.Error("Failed to parse chain ID from genesis block:", err)
@@ -166,19 +188,27 @@ func main() {
 
 	consenters := make(map[string]multichain.Consenter)
 	consenters["solo"] = solo.New()
-	consenters["kafka"] = kafka.New(conf.Kafka.Version, conf.Kafka.Retry, conf.Kafka.TLS)
+	consenters["kafka"] = kafka.New(conf.Kafka.Version, conf.Kafka.Retry, conf.Kafka.TLS, conf.Kafka.SASL)
 	consenters["sbft"] = sbft.New(makeSbftConsensusConfig(conf), makeSbftStackConfig(conf))
 
 	signer := localmsp.NewSigner()
 
-	manager := multichain.NewManagerImpl(lf, consenters, signer)
+	cosignOpts, err := cosignDialOptions(secureConfig)
+	if err != nil {
+		logger.Error("Failed to create the options to connect to the other ordering nodes:", err)
+		return
+	}
+
+	manager := multichain.NewManagerImpl(lf, consenters, signer, cosign.NewClient(cosignOpts, conf.General.Cosign.Timeout, conf.General.Cosign.MaxAttempts))
 
 	server := NewServer(
 		manager,
 		signer,
+		conf.General.TimestampSkew,
 	)
 
 	ab.RegisterAtomicBroadcastServer(grpcServer.Server(), server)
+	ab.RegisterBlockCosignerServer(grpcServer.Server(), cosign.NewHandlerImpl(cosignSupport{Manager: manager}))
 	logger.Info("Beginning to serve requests")
 	grpcServer.Start()
 }
@@ -17,18 +17,25 @@ limitations under the License.
 package multichain
 
 import (
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/broadcast"
 	"github.com/hyperledger/fabric/orderer/common/configtxfilter"
+	"github.com/hyperledger/fabric/orderer/common/cosign"
 	"github.com/hyperledger/fabric/orderer/common/filter"
 	"github.com/hyperledger/fabric/orderer/common/sigfilter"
 	"github.com/hyperledger/fabric/orderer/common/sizefilter"
 	"github.com/hyperledger/fabric/orderer/ledger"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
 )
 
@@ -86,6 +93,7 @@ type ChainSupport interface {
 
 	broadcast.Support
 	ConsenterSupport
+	cosign.Support
 
 	// ProposeConfigUpdate applies a CONFIG_UPDATE to an existing config to produce a *cb.ConfigEnvelope
 	ProposeConfigUpdate(env *cb.Envelope) (*cb.ConfigEnvelope, error)
@@ -99,6 +107,11 @@ type chainSupport struct {
 	signer        crypto.LocalSigner
 	lastConfig    uint64
 	lastConfigSeq uint64
+
+	cosigner       Cosigner
+	cosignLock     sync.Mutex
+	producedHeader *cb.BlockHeader
+	producedChan   chan struct{}
 }
 
 func newChainSupport(
@@ -106,9 +119,10 @@ func newChainSupport(
 	ledgerResources *ledgerResources,
 	consenters map[string]Consenter,
 	signer crypto.LocalSigner,
+	cosigner Cosigner,
 ) *chainSupport {
 
-	cutter := blockcutter.NewReceiverImpl(ledgerResources.SharedConfig(), filters)
+	cutter := blockcutter.NewReceiverImpl(ledgerResources.ChainID(), ledgerResources.SharedConfig(), filters)
 	consenterType := ledgerResources.SharedConfig().ConsensusType()
 	consenter, ok := consenters[consenterType]
 	if !ok {
@@ -120,6 +134,7 @@ func newChainSupport(
 		cutter:          cutter,
 		filters:         filters,
 		signer:          signer,
+		cosigner:        cosigner,
 	}
 
 	var err error
@@ -181,6 +196,14 @@ func (cs *chainSupport) Filters() *filter.RuleSet {
 	return cs.filters
 }
 
+func (cs *chainSupport) AbsoluteMaxBytes() uint32 {
+	return cs.SharedConfig().BatchSize().AbsoluteMaxBytes
+}
+
+func (cs *chainSupport) ConsensusState() ab.ConsensusType_State {
+	return cs.SharedConfig().ConsensusState()
+}
+
 func (cs *chainSupport) BlockCutter() blockcutter.Receiver {
 	return cs.cutter
 }
@@ -190,7 +213,11 @@ func (cs *chainSupport) Reader() ledger.Reader {
 }
 
 func (cs *chainSupport) Enqueue(env *cb.Envelope) bool {
-	return cs.chain.Enqueue(env)
+	if !cs.chain.Enqueue(env) {
+		return false
+	}
+	enqueuedMessages.Add(cs.ChainID(), 1)
+	return true
 }
 
 func (cs *chainSupport) CreateNextBlock(messages []*cb.Envelope) *cb.Block {
@@ -201,24 +228,27 @@ func (cs *chainSupport) addBlockSignature(block *cb.Block) {
 	logger.Debugf("%+v", cs)
 	logger.Debugf("%+v", cs.signer)
 
-	blockSignature := &cb.MetadataSignature{
-		SignatureHeader: utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(cs.signer)),
-	}
-
 	// Note, this value is intentionally nil, as this metadata is only about the signature, there is no additional metadata
 	// information required beyond the fact that the metadata item is signed.
 	blockSignatureValue := []byte(nil)
 
-	blockSignature.Signature = utils.SignOrPanic(cs.signer, util.ConcatenateBytes(blockSignatureValue, blockSignature.SignatureHeader, block.Header.Bytes()))
-
 	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&cb.Metadata{
 		Value: blockSignatureValue,
 		Signatures: []*cb.MetadataSignature{
-			blockSignature,
+			cs.blockHeaderSignature(block.Header),
 		},
 	})
 }
 
+// blockHeaderSignature signs a block header, the signature covers the nil value of the signatures metadata
+func (cs *chainSupport) blockHeaderSignature(header *cb.BlockHeader) *cb.MetadataSignature {
+	blockSignature := &cb.MetadataSignature{
+		SignatureHeader: utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(cs.signer)),
+	}
+	blockSignature.Signature = utils.SignOrPanic(cs.signer, util.ConcatenateBytes(nil, blockSignature.SignatureHeader, header.Bytes()))
+	return blockSignature
+}
+
 func (cs *chainSupport) addLastConfigSignature(block *cb.Block) {
 	configSeq := cs.Sequence()
 	if configSeq > cs.lastConfigSeq {
@@ -243,6 +273,12 @@ func (cs *chainSupport) addLastConfigSignature(block *cb.Block) {
 }
 
 func (cs *chainSupport) WriteBlock(block *cb.Block, committers []filter.Committer, encodedMetadataValue []byte) *cb.Block {
+	txIDs := tracing.BlockTxIDs(block)
+	tr := tracing.New("orderer.block", fmt.Sprintf("[%s] block %d", cs.ChainID(), block.Header.Number), txIDs)
+	defer tr.Finish()
+	tr.LazyPrintf("cut with transactions %v", txIDs)
+	start := time.Now()
+
 	for _, committer := range committers {
 		committer.Commit()
 	}
@@ -251,12 +287,15 @@ func (cs *chainSupport) WriteBlock(block *cb.Block, committers []filter.Committe
 		block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{Value: encodedMetadataValue})
 	}
 	cs.addBlockSignature(block)
+	cs.cosignBlock(block)
 	cs.addLastConfigSignature(block)
+	tr.LazyPrintf("signed")
 
 	err := cs.ledger.Append(block)
 	if err != nil {
 		logger.Panicf("[channel: %s] Could not append block: %s", cs.ChainID(), err)
 	}
+	recordBlockCommit(cs.ChainID(), time.Since(start))
 	return block
 }
 
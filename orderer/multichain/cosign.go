@@ -0,0 +1,197 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multichain
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/orderer/common/cosign"
+	"github.com/hyperledger/fabric/orderer/ledger"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// cosignRetryInterval is how long a chain waits before requesting the
+// signatures of the other ordering nodes over a block again, when the ones it
+// collected do not satisfy the BlockValidation policy yet
+var cosignRetryInterval = time.Second
+
+// Cosigner obtains the signatures of other ordering nodes over the blocks of a chain
+type Cosigner interface {
+	// Cosign requests the signatures of the ordering nodes at the given addresses over the
+	// header of a block of the chain, and returns the ones it obtained
+	Cosign(chainID string, addresses []string, header *cb.BlockHeader) []*cb.MetadataSignature
+
+	// MaxAttempts returns how many times the signatures over a block are requested, before the
+	// block is written with the signatures obtained so far
+	MaxAttempts() int
+}
+
+// cosignBlock makes the header of the block available to the other ordering
+// nodes, which sign it once they produced the same block, and collects their
+// signatures until the block satisfies the BlockValidation policy of the
+// chain. The block is not written, and so not delivered, before, unless the
+// policy is still not satisfied after the maximum number of attempts: the
+// block is then written as is, so that the chain does not stall, and the
+// committers reject it from this ordering node.
+func (cs *chainSupport) cosignBlock(block *cb.Block) {
+	cs.setProducedHeader(block.Header)
+
+	if cs.cosigner == nil {
+		return
+	}
+	policy, ok := cs.PolicyManager().GetPolicy(policies.BlockValidation)
+	if !ok {
+		logger.Debugf("[channel: %s] No block validation policy, not collecting the signatures of other ordering nodes", cs.ChainID())
+		return
+	}
+
+	metadata, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		logger.Panicf("[channel: %s] Could not extract the signatures of block %d: %s", cs.ChainID(), block.Header.Number, err)
+	}
+	headerBytes := block.Header.Bytes()
+
+	for attempt := 0; policy.Evaluate(blockSignedData(metadata, headerBytes)) != nil; attempt++ {
+		if attempt >= cs.cosigner.MaxAttempts() {
+			logger.Errorf("[channel: %s] The %d signatures of block %d do not satisfy the block validation policy after %d attempts, writing it as is",
+				cs.ChainID(), len(metadata.Signatures), block.Header.Number, attempt)
+			break
+		}
+		if attempt > 0 {
+			logger.Warningf("[channel: %s] The %d signatures of block %d do not satisfy the block validation policy yet, requesting them again",
+				cs.ChainID(), len(metadata.Signatures), block.Header.Number)
+			time.Sleep(cosignRetryInterval)
+		}
+
+		for _, signature := range cs.cosigner.Cosign(cs.ChainID(), cs.ordererEndpoints(), block.Header) {
+			if err := cs.checkCosignature(metadata, signature, headerBytes); err != nil {
+				logger.Warningf("[channel: %s] Discarding signature of block %d: %s", cs.ChainID(), block.Header.Number, err)
+				continue
+			}
+			metadata.Signatures = append(metadata.Signatures, signature)
+		}
+	}
+
+	logger.Debugf("[channel: %s] Block %d carries %d signatures", cs.ChainID(), block.Header.Number, len(metadata.Signatures))
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(metadata)
+}
+
+// checkCosignature checks that the signature over a block is valid and comes
+// from an ordering node which did not sign the block yet
+func (cs *chainSupport) checkCosignature(metadata *cb.Metadata, signature *cb.MetadataSignature, headerBytes []byte) error {
+	shdr, err := utils.GetSignatureHeader(signature.SignatureHeader)
+	if err != nil {
+		return err
+	}
+	for _, existing := range metadata.Signatures {
+		existingShdr, err := utils.GetSignatureHeader(existing.SignatureHeader)
+		if err == nil && bytes.Equal(existingShdr.Creator, shdr.Creator) {
+			return fmt.Errorf("the creator already signed the block")
+		}
+	}
+
+	identity, err := cs.MSPManager().DeserializeIdentity(shdr.Creator)
+	if err != nil {
+		return fmt.Errorf("invalid creator: %s", err)
+	}
+	return identity.Verify(util.ConcatenateBytes(metadata.Value, signature.SignatureHeader, headerBytes), signature.Signature)
+}
+
+// ordererEndpoints returns the addresses of the ordering nodes of the chain
+func (cs *chainSupport) ordererEndpoints() []string {
+	var endpoints []string
+	for _, org := range cs.SharedConfig().Organizations() {
+		endpoints = append(endpoints, org.Endpoints()...)
+	}
+	if len(endpoints) == 0 {
+		return cs.ChannelConfig().OrdererAddresses()
+	}
+	return endpoints
+}
+
+// blockSignedData returns the signatures of a block as SignedData, like the
+// committers evaluate them against the BlockValidation policy
+func blockSignedData(metadata *cb.Metadata, headerBytes []byte) []*cb.SignedData {
+	var signedData []*cb.SignedData
+	for _, signature := range metadata.Signatures {
+		shdr, err := utils.GetSignatureHeader(signature.SignatureHeader)
+		if err != nil {
+			continue
+		}
+		signedData = append(signedData, &cb.SignedData{
+			Identity:  shdr.Creator,
+			Data:      util.ConcatenateBytes(metadata.Value, signature.SignatureHeader, headerBytes),
+			Signature: signature.Signature,
+		})
+	}
+	return signedData
+}
+
+// setProducedHeader records the header of the block the chain produced last,
+// and wakes up the requests waiting for it to sign it
+func (cs *chainSupport) setProducedHeader(header *cb.BlockHeader) {
+	cs.cosignLock.Lock()
+	defer cs.cosignLock.Unlock()
+
+	cs.producedHeader = header
+	if cs.producedChan != nil {
+		close(cs.producedChan)
+	}
+	cs.producedChan = make(chan struct{})
+}
+
+// SignBlockHeader signs the header of a block on behalf of another ordering
+// node, once this ordering node produced the same block
+func (cs *chainSupport) SignBlockHeader(header *cb.BlockHeader, done <-chan struct{}) (*cb.MetadataSignature, error) {
+	for {
+		cs.cosignLock.Lock()
+		if cs.producedChan == nil {
+			cs.producedChan = make(chan struct{})
+		}
+		produced, producedChan := cs.producedHeader, cs.producedChan
+		cs.cosignLock.Unlock()
+
+		var own *cb.BlockHeader
+		switch {
+		case produced != nil && produced.Number == header.Number:
+			own = produced
+		case header.Number < cs.Reader().Height():
+			block := ledger.GetBlock(cs.Reader(), header.Number)
+			if block == nil {
+				return nil, fmt.Errorf("block %d is no longer available", header.Number)
+			}
+			own = block.Header
+		default:
+			select {
+			case <-producedChan:
+				continue
+			case <-done:
+				return nil, fmt.Errorf("gave up waiting to produce block %d", header.Number)
+			}
+		}
+
+		if !bytes.Equal(own.Bytes(), header.Bytes()) {
+			return nil, cosign.ErrHeaderMismatch
+		}
+		return cs.blockHeaderSignature(header), nil
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multichain
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto"
+	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
+	mockconfigvalueschannel "github.com/hyperledger/fabric/common/mocks/configvalues/channel"
+	mockconfigvaluesorderer "github.com/hyperledger/fabric/common/mocks/configvalues/channel/orderer"
+	mockcrypto "github.com/hyperledger/fabric/common/mocks/crypto"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/orderer/common/cosign"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// signatureCountPolicy is satisfied by n signatures
+type signatureCountPolicy struct {
+	n int
+}
+
+func (p *signatureCountPolicy) Evaluate(signatureSet []*cb.SignedData) error {
+	if len(signatureSet) < p.n {
+		return fmt.Errorf("Expected %d signatures, got %d", p.n, len(signatureSet))
+	}
+	return nil
+}
+
+// mockIdentity verifies the signatures of the mock signers, which are the signed messages
+type mockIdentity struct {
+	msp.Identity
+}
+
+func (id *mockIdentity) Verify(msg []byte, sig []byte) error {
+	if !bytes.Equal(msg, sig) {
+		return fmt.Errorf("Invalid signature")
+	}
+	return nil
+}
+
+type mockMSPManager struct {
+	msp.MSPManager
+}
+
+func (mm *mockMSPManager) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	return &mockIdentity{}, nil
+}
+
+// badSigner produces invalid signatures
+type badSigner struct {
+	*mockcrypto.LocalSigner
+}
+
+func (bs *badSigner) Sign(msg []byte) ([]byte, error) {
+	return []byte("bad signature"), nil
+}
+
+// mockCosigner returns the signatures of its signers, starting with the given round
+type mockCosigner struct {
+	signers     []crypto.LocalSigner
+	fromRound   int
+	rounds      int
+	addresses   []string
+	maxAttempts int
+}
+
+func (mc *mockCosigner) Cosign(chainID string, addresses []string, header *cb.BlockHeader) []*cb.MetadataSignature {
+	mc.rounds++
+	mc.addresses = addresses
+	if mc.rounds < mc.fromRound {
+		return nil
+	}
+	var signatures []*cb.MetadataSignature
+	for _, signer := range mc.signers {
+		signatures = append(signatures, (&chainSupport{signer: signer}).blockHeaderSignature(header))
+	}
+	return signatures
+}
+
+func (mc *mockCosigner) MaxAttempts() int {
+	if mc.maxAttempts == 0 {
+		return 10
+	}
+	return mc.maxAttempts
+}
+
+func newCosignChainSupport(policy policies.Policy, cosigner Cosigner) (*chainSupport, *mockLedgerReadWriter) {
+	ml := &mockLedgerReadWriter{}
+	cm := &mockconfigtx.Manager{
+		Initializer: mockconfigtx.Initializer{
+			Resources: mockconfigtx.Resources{
+				PolicyManagerVal: &mockpolicies.Manager{PolicyMap: map[string]policies.Policy{policies.BlockValidation: policy}},
+				MSPManagerVal:    &mockMSPManager{},
+				OrdererConfigVal: &mockconfigvaluesorderer.SharedConfig{},
+				ChannelConfigVal: &mockconfigvalueschannel.SharedConfig{OrdererAddressesVal: []string{"orderer0:7050", "orderer1:7050"}},
+			},
+		},
+	}
+	cs := &chainSupport{
+		ledgerResources: &ledgerResources{configResources: &configResources{Manager: cm}, ledger: ml},
+		signer:          mockCrypto(),
+		cosigner:        cosigner,
+	}
+	return cs, ml
+}
+
+func blockSignatures(t *testing.T, metadata [][]byte) []*cb.MetadataSignature {
+	md := &cb.Metadata{}
+	assert.NoError(t, proto.Unmarshal(metadata[cb.BlockMetadataIndex_SIGNATURES], md))
+	return md.Signatures
+}
+
+func mustSignatureHeader(t *testing.T, signature *cb.MetadataSignature) *cb.SignatureHeader {
+	shdr, err := utils.GetSignatureHeader(signature.SignatureHeader)
+	assert.NoError(t, err)
+	return shdr
+}
+
+func TestCosignBlock(t *testing.T) {
+	cosigner := &mockCosigner{
+		signers: []crypto.LocalSigner{
+			&mockcrypto.LocalSigner{Identity: []byte("Other")},
+			// Already signed the block
+			mockCrypto(),
+			&badSigner{&mockcrypto.LocalSigner{Identity: []byte("Bad")}},
+		},
+	}
+	cs, ml := newCosignChainSupport(&signatureCountPolicy{n: 2}, cosigner)
+	cs.WriteBlock(cs.CreateNextBlock([]*cb.Envelope{makeNormalTx("foo", 0)}), nil, nil)
+
+	assert.Equal(t, 1, cosigner.rounds, "Should have requested the signatures once")
+	assert.Equal(t, []string{"orderer0:7050", "orderer1:7050"}, cosigner.addresses, "Should have fallen back to the orderer addresses of the channel")
+	signatures := blockSignatures(t, ml.metadata)
+	if assert.Len(t, signatures, 2, "Should have kept the signatures of the ordering node and the other valid one") {
+		assert.Equal(t, []byte("Other"), mustSignatureHeader(t, signatures[1]).Creator)
+	}
+}
+
+func TestCosignBlockRetry(t *testing.T) {
+	defer func(interval time.Duration) { cosignRetryInterval = interval }(cosignRetryInterval)
+	cosignRetryInterval = time.Millisecond
+
+	cosigner := &mockCosigner{
+		signers:   []crypto.LocalSigner{&mockcrypto.LocalSigner{Identity: []byte("Other")}},
+		fromRound: 3,
+	}
+	cs, ml := newCosignChainSupport(&signatureCountPolicy{n: 2}, cosigner)
+	cs.WriteBlock(cs.CreateNextBlock([]*cb.Envelope{makeNormalTx("foo", 0)}), nil, nil)
+
+	assert.Equal(t, 3, cosigner.rounds, "Should have requested the signatures until the policy was satisfied")
+	assert.Len(t, blockSignatures(t, ml.metadata), 2)
+
+	cosigner = &mockCosigner{
+		signers:     []crypto.LocalSigner{&mockcrypto.LocalSigner{Identity: []byte("Other")}},
+		fromRound:   5,
+		maxAttempts: 2,
+	}
+	cs, ml = newCosignChainSupport(&signatureCountPolicy{n: 2}, cosigner)
+	cs.WriteBlock(cs.CreateNextBlock([]*cb.Envelope{makeNormalTx("foo", 0)}), nil, nil)
+
+	assert.Equal(t, 2, cosigner.rounds, "Should have given up requesting the signatures after the maximum number of attempts")
+	assert.Len(t, blockSignatures(t, ml.metadata), 1, "Should have written the block with its own signature")
+}
+
+func TestCosignBlockNotRequired(t *testing.T) {
+	cosigner := &mockCosigner{}
+	cs, ml := newCosignChainSupport(&signatureCountPolicy{n: 1}, cosigner)
+	cs.WriteBlock(cs.CreateNextBlock([]*cb.Envelope{makeNormalTx("foo", 0)}), nil, nil)
+
+	assert.Equal(t, 0, cosigner.rounds, "Should not have requested signatures when the own one satisfies the policy")
+	assert.Len(t, blockSignatures(t, ml.metadata), 1)
+
+	cs, ml = newCosignChainSupport(&signatureCountPolicy{n: 2}, nil)
+	cs.WriteBlock(cs.CreateNextBlock([]*cb.Envelope{makeNormalTx("foo", 0)}), nil, nil)
+	assert.Len(t, blockSignatures(t, ml.metadata), 1, "Should have written the block with its own signature without cosigner")
+}
+
+func TestSignBlockHeader(t *testing.T) {
+	rl := NewRAMLedger(10)
+	cs := &chainSupport{
+		ledgerResources: &ledgerResources{configResources: &configResources{Manager: &mockconfigtx.Manager{}}, ledger: rl},
+		signer:          mockCrypto(),
+	}
+
+	block1 := cs.CreateNextBlock([]*cb.Envelope{makeNormalTx("foo", 0)})
+	cs.WriteBlock(block1, nil, nil)
+
+	// The genesis block, read from the ledger
+	signature, err := cs.SignBlockHeader(genesisBlock.Header, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, util.ConcatenateBytes(nil, signature.SignatureHeader, genesisBlock.Header.Bytes()), signature.Signature)
+
+	// The last produced block
+	_, err = cs.SignBlockHeader(block1.Header, nil)
+	assert.NoError(t, err)
+
+	// A different block with the same number
+	otherHeader := *block1.Header
+	otherHeader.DataHash = []byte("other data")
+	_, err = cs.SignBlockHeader(&otherHeader, nil)
+	assert.Equal(t, cosign.ErrHeaderMismatch, err)
+
+	// A block which is not produced yet
+	block2 := cs.CreateNextBlock([]*cb.Envelope{makeNormalTx("bar", 0)})
+	result := make(chan error)
+	go func() {
+		_, err := cs.SignBlockHeader(block2.Header, nil)
+		result <- err
+	}()
+	select {
+	case <-result:
+		t.Fatalf("Should not have signed a block which was not produced yet")
+	case <-time.After(10 * time.Millisecond):
+	}
+	cs.WriteBlock(block2, nil, nil)
+	select {
+	case err = <-result:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatalf("Should have signed the block once produced")
+	}
+
+	// Giving up
+	done := make(chan struct{})
+	close(done)
+	_, err = cs.SignBlockHeader(&cb.BlockHeader{Number: 5}, done)
+	assert.Error(t, err)
+}
@@ -57,6 +57,7 @@ type multiLedger struct {
 	consenters      map[string]Consenter
 	ledgerFactory   ledger.Factory
 	signer          crypto.LocalSigner
+	cosigner        Cosigner
 	systemChannelID string
 }
 
@@ -74,13 +75,15 @@ func getConfigTx(reader ledger.Reader) *cb.Envelope {
 	return utils.ExtractEnvelopeOrPanic(configBlock, 0)
 }
 
-// NewManagerImpl produces an instance of a Manager
-func NewManagerImpl(ledgerFactory ledger.Factory, consenters map[string]Consenter, signer crypto.LocalSigner) Manager {
+// NewManagerImpl produces an instance of a Manager, the cosigner, which may be nil, collects the signatures
+// of the other ordering nodes the BlockValidation policy of a chain requires on its blocks
+func NewManagerImpl(ledgerFactory ledger.Factory, consenters map[string]Consenter, signer crypto.LocalSigner, cosigner Cosigner) Manager {
 	ml := &multiLedger{
 		chains:        make(map[string]*chainSupport),
 		ledgerFactory: ledgerFactory,
 		consenters:    consenters,
 		signer:        signer,
+		cosigner:      cosigner,
 	}
 
 	existingChains := ledgerFactory.ChainIDs()
@@ -103,7 +106,8 @@ func NewManagerImpl(ledgerFactory ledger.Factory, consenters map[string]Consente
 			chain := newChainSupport(createSystemChainFilters(ml, ledgerResources),
 				ledgerResources,
 				consenters,
-				signer)
+				signer,
+				cosigner)
 			logger.Infof("Starting with system channel %s and orderer type %s", chainID, chain.SharedConfig().ConsensusType())
 			ml.chains[string(chainID)] = chain
 			ml.systemChannelID = chainID
@@ -114,7 +118,8 @@ func NewManagerImpl(ledgerFactory ledger.Factory, consenters map[string]Consente
 			chain := newChainSupport(createStandardFilters(ledgerResources),
 				ledgerResources,
 				consenters,
-				signer)
+				signer,
+				cosigner)
 			ml.chains[string(chainID)] = chain
 			chain.start()
 		}
@@ -168,7 +173,7 @@ func (ml *multiLedger) newChain(configtx *cb.Envelope) {
 		newChains[key] = value
 	}
 
-	cs := newChainSupport(createStandardFilters(ledgerResources), ledgerResources, ml.consenters, ml.signer)
+	cs := newChainSupport(createStandardFilters(ledgerResources), ledgerResources, ml.consenters, ml.signer, ml.cosigner)
 	chainID := ledgerResources.ChainID()
 
 	logger.Infof("Created and starting new chain %s", chainID)
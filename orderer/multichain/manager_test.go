@@ -141,7 +141,7 @@ func TestNoSystemChain(t *testing.T) {
 	consenters := make(map[string]Consenter)
 	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
 
-	NewManagerImpl(lf, consenters, mockCrypto())
+	NewManagerImpl(lf, consenters, mockCrypto(), nil)
 }
 
 // This test essentially brings the entire system up and is ultimately what main.go will replicate
@@ -151,7 +151,7 @@ func TestManagerImpl(t *testing.T) {
 	consenters := make(map[string]Consenter)
 	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
 
-	manager := NewManagerImpl(lf, consenters, mockCrypto())
+	manager := NewManagerImpl(lf, consenters, mockCrypto(), nil)
 
 	_, ok := manager.GetChain("Fake")
 	if ok {
@@ -198,7 +198,7 @@ func TestSignatureFilter(t *testing.T) {
 	consenters := make(map[string]Consenter)
 	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
 
-	manager := NewManagerImpl(lf, consenters, mockCryptoRejector())
+	manager := NewManagerImpl(lf, consenters, mockCryptoRejector(), nil)
 
 	cs, ok := manager.GetChain(provisional.TestChainID)
 
@@ -236,7 +236,7 @@ func TestNewChain(t *testing.T) {
 	consenters := make(map[string]Consenter)
 	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
 
-	manager := NewManagerImpl(lf, consenters, mockCrypto())
+	manager := NewManagerImpl(lf, consenters, mockCrypto(), nil)
 
 	newChainID := "TestNewChain"
 
@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multichain
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+// enqueuedMessages counts by channel the broadcast messages enqueued to the
+// consenters, e.g. {"mychannel": 1500}, the enqueue rate is the difference
+// between two reads. It is published with the other expvar variables on
+// /debug/vars of the profiling server of the orderer
+var enqueuedMessages = expvar.NewMap("broadcast_enqueued_messages")
+
+// blockCommits accumulates by channel the number of blocks written and the
+// time spent signing them, collecting their cosignatures and appending them
+// to the ledger, e.g. {"mychannel": {"blocks": 50, "seconds": 0.8}}
+var blockCommits = expvar.NewMap("consenter_block_commits")
+
+// blockCommitsLock serializes the creation of the counters of a channel
+var blockCommitsLock sync.Mutex
+
+// channelBlockCommits returns the counters of the blocks written by a channel
+func channelBlockCommits(chainID string) *expvar.Map {
+	blockCommitsLock.Lock()
+	defer blockCommitsLock.Unlock()
+
+	if counters, ok := blockCommits.Get(chainID).(*expvar.Map); ok {
+		return counters
+	}
+	counters := new(expvar.Map).Init()
+	blockCommits.Set(chainID, counters)
+	return counters
+}
+
+// recordBlockCommit accounts for a block written by a channel
+func recordBlockCommit(chainID string, elapsed time.Duration) {
+	counters := channelBlockCommits(chainID)
+	counters.Add("blocks", 1)
+	counters.AddFloat("seconds", elapsed.Seconds())
+}
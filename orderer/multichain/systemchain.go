@@ -18,13 +18,16 @@ package multichain
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/configtx"
 	configtxapi "github.com/hyperledger/fabric/common/configtx/api"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/orderer/common/filter"
 	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
 
@@ -40,6 +43,7 @@ type chainCreator interface {
 type limitedSupport interface {
 	PolicyManager() policies.Manager
 	SharedConfig() config.Orderer
+	ChannelConfig() config.Channel
 }
 
 type systemChainCommitter struct {
@@ -139,6 +143,11 @@ func (scf *systemChainFilter) authorize(configEnvelope *cb.ConfigEnvelope) error
 		return fmt.Errorf("Failing to validate channel creation because WriteSet is nil")
 	}
 
+	// Once the system channel defines consortiums, channels may only be created by them
+	if consortiums := scf.support.ChannelConfig().Consortiums(); len(consortiums) > 0 {
+		return scf.authorizeConsortium(configMsg.WriteSet, configUpdateEnv, consortiums)
+	}
+
 	ordererGroup, ok := configMsg.WriteSet.Groups[config.OrdererGroupKey]
 	if !ok {
 		return fmt.Errorf("Rejecting channel creation because it is missing orderer group")
@@ -185,6 +194,109 @@ func (scf *systemChainFilter) authorize(configEnvelope *cb.ConfigEnvelope) error
 	return nil
 }
 
+// authorizeConsortium checks that the channel names one of the consortiums of
+// the system channel, that its creation was signed as the channel creation
+// policy of the consortium requires, and that its orgs are members of the
+// consortium with the same MSP definitions
+func (scf *systemChainFilter) authorizeConsortium(writeSet *cb.ConfigGroup, configUpdateEnv *cb.ConfigUpdateEnvelope, consortiums map[string]config.Consortium) error {
+	consortiumValue, ok := writeSet.Values[config.ConsortiumKey]
+	if !ok {
+		return fmt.Errorf("Rejecting channel creation because it does not name the consortium it is created by")
+	}
+
+	consortiumMsg := &cb.Consortium{}
+	if err := proto.Unmarshal(consortiumValue.Value, consortiumMsg); err != nil {
+		return fmt.Errorf("Failing to validate channel creation because the consortium could not be unmarshaled: %s", err)
+	}
+
+	consortium, ok := consortiums[consortiumMsg.Name]
+	if !ok {
+		return fmt.Errorf("Rejecting channel creation because consortium %s does not exist", consortiumMsg.Name)
+	}
+
+	if _, ok := writeSet.Groups[config.ConsortiumsGroupKey]; ok {
+		return fmt.Errorf("Rejecting channel creation because only the system channel may define consortiums")
+	}
+
+	policyName := policies.PathSeparator + strings.Join([]string{policies.ChannelPrefix, config.ConsortiumsGroupKey, consortiumMsg.Name, config.ChannelCreationPolicyKey}, policies.PathSeparator)
+	policy, ok := scf.support.PolicyManager().GetPolicy(policyName)
+	if !ok {
+		return fmt.Errorf("Failed to validate channel creation because consortium %s has no channel creation policy", consortiumMsg.Name)
+	}
+
+	signedData, err := configUpdateEnv.AsSignedData()
+	if err != nil {
+		return fmt.Errorf("Failed to validate channel creation because config envelope could not be converted to signed data: %s", err)
+	}
+
+	if err := policy.Evaluate(signedData); err != nil {
+		return fmt.Errorf("Failed to validate channel creation, did not satisfy the channel creation policy of consortium %s: %s", consortiumMsg.Name, err)
+	}
+
+	applicationGroup, ok := writeSet.Groups[config.ApplicationGroupKey]
+	if !ok {
+		return fmt.Errorf("Rejecting channel creation because it is missing application group")
+	}
+
+	members := consortium.Organizations()
+	for orgName, orgGroup := range applicationGroup.Groups {
+		member, ok := members[orgName]
+		if !ok {
+			return fmt.Errorf("Rejecting channel creation because org %s is not a member of consortium %s", orgName, consortiumMsg.Name)
+		}
+
+		fabricConfig, err := orgFabricMSPConfig(orgGroup)
+		if err != nil {
+			return fmt.Errorf("Failing to validate channel creation because of the MSP of org %s: %s", orgName, err)
+		}
+		if fabricConfig.Name != member.MSPID() {
+			return fmt.Errorf("Rejecting channel creation because org %s has MSP ID %s, but %s in consortium %s", orgName, fabricConfig.Name, member.MSPID(), consortiumMsg.Name)
+		}
+
+		// the MSP ID alone does not identify the org: the channel must trust
+		// the same root and intermediate CAs and admins as the consortium
+		memberConfig, err := fabricMSPConfig(member.MSPConfig())
+		if err != nil {
+			return fmt.Errorf("Failing to validate channel creation because of the MSP of org %s in consortium %s: %s", orgName, consortiumMsg.Name, err)
+		}
+		if !proto.Equal(fabricConfig, memberConfig) {
+			return fmt.Errorf("Rejecting channel creation because the MSP definition of org %s differs from the one in consortium %s", orgName, consortiumMsg.Name)
+		}
+	}
+
+	return nil
+}
+
+// orgFabricMSPConfig returns the fabric MSP config of an org group
+func orgFabricMSPConfig(orgGroup *cb.ConfigGroup) (*mspprotos.FabricMSPConfig, error) {
+	mspValue, ok := orgGroup.Values[config.MSPKey]
+	if !ok {
+		return nil, fmt.Errorf("no MSP config")
+	}
+
+	mspConfig := &mspprotos.MSPConfig{}
+	if err := proto.Unmarshal(mspValue.Value, mspConfig); err != nil {
+		return nil, err
+	}
+	return fabricMSPConfig(mspConfig)
+}
+
+// fabricMSPConfig returns the fabric MSP config within an MSP config
+func fabricMSPConfig(mspConfig *mspprotos.MSPConfig) (*mspprotos.FabricMSPConfig, error) {
+	if mspConfig == nil {
+		return nil, fmt.Errorf("no MSP config")
+	}
+	if mspConfig.Type != int32(msp.FABRIC) {
+		return nil, fmt.Errorf("unsupported MSP type %d", mspConfig.Type)
+	}
+
+	fabricConfig := &mspprotos.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+		return nil, err
+	}
+	return fabricConfig, nil
+}
+
 func (scf *systemChainFilter) inspect(configManager configtxapi.Resources) error {
 	// XXX decide what it is that we will require to be the same in the new config, and what will be allowed to be different
 	// Are all keys allowed? etc.
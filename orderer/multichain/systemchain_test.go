@@ -17,30 +17,39 @@ limitations under the License.
 package multichain
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/config"
 	"github.com/hyperledger/fabric/common/configtx"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
+	mockconfigvalueschannel "github.com/hyperledger/fabric/common/mocks/configvalues/channel"
 	mockconfigvaluesorderer "github.com/hyperledger/fabric/common/mocks/configvalues/channel/orderer"
 	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
 	"github.com/hyperledger/fabric/common/policies"
+	cf "github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/orderer/common/filter"
 	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/utils"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockSupport struct {
 	mpm *mockpolicies.Manager
 	msc *mockconfigvaluesorderer.SharedConfig
+	mcc *mockconfigvalueschannel.SharedConfig
 }
 
 func newMockSupport(chainID string) *mockSupport {
 	return &mockSupport{
 		mpm: &mockpolicies.Manager{},
 		msc: &mockconfigvaluesorderer.SharedConfig{},
+		mcc: &mockconfigvalueschannel.SharedConfig{},
 	}
 }
 
@@ -52,6 +61,10 @@ func (ms *mockSupport) SharedConfig() config.Orderer {
 	return ms.msc
 }
 
+func (ms *mockSupport) ChannelConfig() config.Channel {
+	return ms.mcc
+}
+
 type mockChainCreator struct {
 	newChains []*cb.Envelope
 	ms        *mockSupport
@@ -157,3 +170,127 @@ func TestNumChainsExceeded(t *testing.T) {
 
 	assert.EqualValues(t, filter.Reject, action, "Transaction had created too many channels")
 }
+
+const sampleConsortium = "SampleConsortium"
+
+var channelCreationPolicyName = "/Channel/Consortiums/" + sampleConsortium + "/ChannelCreationPolicy"
+
+// sampleMSPConfig returns the MSP config of the DEFAULT org of the test templates
+func sampleMSPConfig(t *testing.T) *mspprotos.MSPConfig {
+	mspDir, err := cf.GetDevMspDir()
+	if err != nil {
+		t.Fatalf("Error getting the dev MSP dir: %s", err)
+	}
+	mspConfig, err := msp.GetLocalMspConfig(mspDir, nil, "DEFAULT")
+	if err != nil {
+		t.Fatalf("Error loading the sample MSP config: %s", err)
+	}
+	return mspConfig
+}
+
+// newConsortiumChainCreator returns a chain creator whose system channel
+// defines a consortium with the DEFAULT org of the test templates
+func newConsortiumChainCreator(t *testing.T, creationPolicy *mockpolicies.Policy) *mockChainCreator {
+	mcc := newMockChainCreator()
+	mcc.ms.mcc.ConsortiumsVal = map[string]config.Consortium{
+		sampleConsortium: &mockconfigvalueschannel.Consortium{
+			OrganizationsVal: map[string]config.Org{
+				"DEFAULT": &mockconfigvalueschannel.Org{NameVal: "DEFAULT", MSPIDVal: "DEFAULT", MSPConfigVal: sampleMSPConfig(t)},
+			},
+		},
+	}
+	mcc.ms.mpm.PolicyMap = map[string]policies.Policy{channelCreationPolicyName: creationPolicy}
+	return mcc
+}
+
+func applyConsortiumProposal(t *testing.T, mcc *mockChainCreator, templates ...configtx.Template) filter.Action {
+	newChainID := "NewChainID"
+
+	configEnv, err := configtx.NewCompositeTemplate(templates...).Envelope(newChainID)
+	if err != nil {
+		t.Fatalf("Error constructing configtx")
+	}
+	ingressTx := makeConfigTxFromConfigUpdateEnvelope(newChainID, configEnv)
+
+	sysFilter := newSystemChainFilter(mcc.ms, mcc)
+	action, committer := sysFilter.Apply(wrapConfigTx(ingressTx))
+	if action == filter.Accept {
+		committer.Commit()
+	}
+	return action
+}
+
+func TestConsortiumProposal(t *testing.T) {
+	mcc := newConsortiumChainCreator(t, &mockpolicies.Policy{})
+	action := applyConsortiumProposal(t, mcc, configtxtest.CompositeTemplate(), configtx.NewSimpleTemplate(config.TemplateConsortium(sampleConsortium)))
+	assert.EqualValues(t, filter.Accept, action, "Did not accept valid consortium transaction")
+	assert.Len(t, mcc.newChains, 1, "Proposal should have created 1 new chain")
+}
+
+func TestConsortiumProposalWithoutConsortium(t *testing.T) {
+	mcc := newConsortiumChainCreator(t, &mockpolicies.Policy{})
+	mcc.ms.msc.ChainCreationPolicyNamesVal = []string{provisional.AcceptAllPolicyKey}
+	mcc.ms.mpm.Policy = &mockpolicies.Policy{}
+
+	configEnv, err := configtx.NewChainCreationTemplate(provisional.AcceptAllPolicyKey, configtxtest.CompositeTemplate()).Envelope("NewChainID")
+	if err != nil {
+		t.Fatalf("Error constructing configtx")
+	}
+	ingressTx := makeConfigTxFromConfigUpdateEnvelope("NewChainID", configEnv)
+	action, _ := newSystemChainFilter(mcc.ms, mcc).Apply(wrapConfigTx(ingressTx))
+	assert.EqualValues(t, filter.Reject, action, "Should have rejected a creation by chain creation policy once consortiums are defined")
+}
+
+func TestConsortiumProposalWithUnknownConsortium(t *testing.T) {
+	mcc := newConsortiumChainCreator(t, &mockpolicies.Policy{})
+	action := applyConsortiumProposal(t, mcc, configtxtest.CompositeTemplate(), configtx.NewSimpleTemplate(config.TemplateConsortium("OtherConsortium")))
+	assert.EqualValues(t, filter.Reject, action, "Should have rejected a creation by an unknown consortium")
+}
+
+func TestConsortiumProposalWithUnsatisfiedPolicy(t *testing.T) {
+	mcc := newConsortiumChainCreator(t, &mockpolicies.Policy{Err: fmt.Errorf("Not enough signatures")})
+	action := applyConsortiumProposal(t, mcc, configtxtest.CompositeTemplate(), configtx.NewSimpleTemplate(config.TemplateConsortium(sampleConsortium)))
+	assert.EqualValues(t, filter.Reject, action, "Should have rejected a creation not satisfying the channel creation policy")
+}
+
+func TestConsortiumProposalWithNonMember(t *testing.T) {
+	mcc := newConsortiumChainCreator(t, &mockpolicies.Policy{})
+	mcc.ms.mcc.ConsortiumsVal[sampleConsortium] = &mockconfigvalueschannel.Consortium{
+		OrganizationsVal: map[string]config.Org{
+			"OtherOrg": &mockconfigvalueschannel.Org{NameVal: "OtherOrg", MSPIDVal: "OtherMSP"},
+		},
+	}
+	action := applyConsortiumProposal(t, mcc, configtxtest.CompositeTemplate(), configtx.NewSimpleTemplate(config.TemplateConsortium(sampleConsortium)))
+	assert.EqualValues(t, filter.Reject, action, "Should have rejected a channel with an org which is not a member of the consortium")
+}
+
+func TestConsortiumProposalWithOtherMSP(t *testing.T) {
+	mcc := newConsortiumChainCreator(t, &mockpolicies.Policy{})
+	mcc.ms.mcc.ConsortiumsVal[sampleConsortium] = &mockconfigvalueschannel.Consortium{
+		OrganizationsVal: map[string]config.Org{
+			"DEFAULT": &mockconfigvalueschannel.Org{NameVal: "DEFAULT", MSPIDVal: "OtherMSP"},
+		},
+	}
+	action := applyConsortiumProposal(t, mcc, configtxtest.CompositeTemplate(), configtx.NewSimpleTemplate(config.TemplateConsortium(sampleConsortium)))
+	assert.EqualValues(t, filter.Reject, action, "Should have rejected a channel with an org whose MSP differs from the one of the consortium")
+}
+
+func TestConsortiumProposalWithOtherMSPDefinition(t *testing.T) {
+	mspConfig := sampleMSPConfig(t)
+	fabricConfig := &mspprotos.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+		t.Fatalf("Error unmarshaling the sample MSP config: %s", err)
+	}
+	// same MSP ID, but admins the consortium does not know about
+	fabricConfig.Admins = append(fabricConfig.Admins, fabricConfig.RootCerts...)
+	mspConfig.Config = utils.MarshalOrPanic(fabricConfig)
+
+	mcc := newConsortiumChainCreator(t, &mockpolicies.Policy{})
+	mcc.ms.mcc.ConsortiumsVal[sampleConsortium] = &mockconfigvalueschannel.Consortium{
+		OrganizationsVal: map[string]config.Org{
+			"DEFAULT": &mockconfigvalueschannel.Org{NameVal: "DEFAULT", MSPIDVal: "DEFAULT", MSPConfigVal: mspConfig},
+		},
+	}
+	action := applyConsortiumProposal(t, mcc, configtxtest.CompositeTemplate(), configtx.NewSimpleTemplate(config.TemplateConsortium(sampleConsortium)))
+	assert.EqualValues(t, filter.Reject, action, "Should have rejected a channel with an org whose MSP definition differs from the one of the consortium")
+}
@@ -131,9 +131,9 @@ func TestSbftPeer(t *testing.T) {
 		panic(fmt.Errorf("Failed initializing crypto [%s]", err))
 	}
 	signer := localmsp.NewSigner()
-	manager := multichain.NewManagerImpl(lf, consenters, signer)
+	manager := multichain.NewManagerImpl(lf, consenters, signer, nil)
 
-	server := NewServer(manager, signer)
+	server := NewServer(manager, signer, 0)
 	grpcServer := grpc.NewServer()
 	grpcAddr := fmt.Sprintf("%s:%d", conf.General.ListenAddress, conf.General.ListenPort)
 	lis, err := net.Listen("tcp", grpcAddr)
@@ -17,8 +17,11 @@ limitations under the License.
 package main
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/orderer/common/broadcast"
+	"github.com/hyperledger/fabric/orderer/common/cosign"
 	"github.com/hyperledger/fabric/orderer/common/deliver"
 	"github.com/hyperledger/fabric/orderer/configupdate"
 	"github.com/hyperledger/fabric/orderer/multichain"
@@ -50,21 +53,31 @@ func (bs deliverSupport) GetChain(chainID string) (deliver.Support, bool) {
 	return bs.Manager.GetChain(chainID)
 }
 
+type cosignSupport struct {
+	multichain.Manager
+}
+
+func (cs cosignSupport) GetChain(chainID string) (cosign.Support, bool) {
+	return cs.Manager.GetChain(chainID)
+}
+
 type server struct {
 	bh broadcast.Handler
 	dh deliver.Handler
 }
 
-// NewServer creates an ab.AtomicBroadcastServer based on the broadcast target and ledger Reader
-func NewServer(ml multichain.Manager, signer crypto.LocalSigner) ab.AtomicBroadcastServer {
+// NewServer creates an ab.AtomicBroadcastServer based on the broadcast target and ledger Reader,
+// which rejects the broadcast messages whose timestamp differs from the local time by more than
+// timestampSkew
+func NewServer(ml multichain.Manager, signer crypto.LocalSigner, timestampSkew time.Duration) ab.AtomicBroadcastServer {
 	logger.Infof("Starting orderer")
 
 	s := &server{
 		dh: deliver.NewHandlerImpl(deliverSupport{Manager: ml}),
-		bh: broadcast.NewHandlerImpl(broadcastSupport{
+		bh: broadcast.NewHandlerImplWithTimestampSkew(broadcastSupport{
 			Manager:               ml,
 			ConfigUpdateProcessor: configupdate.New(ml.SystemChannelID(), configUpdateSupport{Manager: ml}, signer),
-		}),
+		}, timestampSkew),
 	}
 	return s
 }
@@ -17,11 +17,15 @@ limitations under the License.
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
+	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/orderer/ledger"
 	fileledger "github.com/hyperledger/fabric/orderer/ledger/file"
 	jsonledger "github.com/hyperledger/fabric/orderer/ledger/json"
@@ -31,6 +35,11 @@ import (
 	"github.com/hyperledger/fabric/orderer/sbft/backend"
 	sbftcrypto "github.com/hyperledger/fabric/orderer/sbft/crypto"
 	"github.com/hyperledger/fabric/orderer/sbft/simplebft"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func createLedgerFactory(conf *config.TopLevel) (ledger.Factory, string) {
@@ -88,6 +97,70 @@ func createSubDir(parentDirPath string, subDir string) (string, bool) {
 	return subDirPath, created
 }
 
+// checkBootstrapBlock checks that a block the system chain is bootstrapped from
+// is either the genesis block, or a later config block. The chain is recovered
+// from the latter, which must hold the current config of the chain.
+func checkBootstrapBlock(block *cb.Block) error {
+	if block.Header == nil {
+		return fmt.Errorf("Bootstrap block has no header")
+	}
+	if block.Header.Number == 0 {
+		return nil
+	}
+
+	lastConfig, err := utils.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		return fmt.Errorf("Bootstrap block %d has no last config: %s", block.Header.Number, err)
+	}
+	if lastConfig != block.Header.Number {
+		return fmt.Errorf("Bootstrap block %d is not the last config block, the last config block is %d", block.Header.Number, lastConfig)
+	}
+
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return fmt.Errorf("Bootstrap block %d has no config transaction: %s", block.Header.Number, err)
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return fmt.Errorf("Bootstrap block %d has no config transaction: %s", block.Header.Number, err)
+	}
+	if payload.Header == nil {
+		return fmt.Errorf("Bootstrap block %d has no config transaction: missing header", block.Header.Number)
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return fmt.Errorf("Bootstrap block %d has no config transaction: %s", block.Header.Number, err)
+	}
+	if chdr.Type != int32(cb.HeaderType_CONFIG) {
+		return fmt.Errorf("Bootstrap block %d is not a config block, its transaction is of type %d", block.Header.Number, chdr.Type)
+	}
+	return nil
+}
+
+// cosignDialOptions returns the options to connect to the other ordering nodes
+// to co-sign blocks, the orderer presents its server certificate as client
+// certificate and trusts the root CAs of its server
+func cosignDialOptions(secureConfig comm.SecureServerConfig) ([]grpc.DialOption, error) {
+	if !secureConfig.UseTLS {
+		return []grpc.DialOption{grpc.WithInsecure()}, nil
+	}
+
+	cert, err := tls.X509KeyPair(secureConfig.ServerCertificate, secureConfig.ServerKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server certificate: %s", err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if len(secureConfig.ServerRootCAs) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		for _, root := range secureConfig.ServerRootCAs {
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(root) {
+				return nil, fmt.Errorf("invalid server root CA")
+			}
+		}
+	}
+	return []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}, nil
+}
+
 // XXX The functions below need to be moved to the SBFT package ASAP
 
 func makeSbftConsensusConfig(conf *config.TopLevel) *sbft.ConsensusConfig {
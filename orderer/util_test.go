@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	config "github.com/hyperledger/fabric/orderer/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
 func TestCreateLedgerFactory(t *testing.T) {
@@ -104,3 +106,45 @@ func TestCreateSubDir(t *testing.T) {
 		})
 	}
 }
+
+func makeBootstrapBlock(number uint64, lastConfig uint64, headerType cb.HeaderType) *cb.Block {
+	block := cb.NewBlock(number, []byte("previous hash"))
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(&cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{Type: int32(headerType), ChannelId: "testchain"}),
+			},
+		}),
+	})}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{
+		Value: utils.MarshalOrPanic(&cb.LastConfig{Index: lastConfig}),
+	})
+	return block
+}
+
+func TestCheckBootstrapBlock(t *testing.T) {
+	testCases := []struct {
+		name        string
+		block       *cb.Block
+		expectError bool
+	}{
+		{"GenesisBlock", cb.NewBlock(0, nil), false},
+		{"LaterConfigBlock", makeBootstrapBlock(5, 5, cb.HeaderType_CONFIG), false},
+		{"NotLastConfigBlock", makeBootstrapBlock(5, 3, cb.HeaderType_CONFIG), true},
+		{"NotConfigBlock", makeBootstrapBlock(5, 5, cb.HeaderType_ENDORSER_TRANSACTION), true},
+		{"NoLastConfig", cb.NewBlock(5, []byte("previous hash")), true},
+		{"NoHeader", &cb.Block{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkBootstrapBlock(tc.block)
+			if tc.expectError && err == nil {
+				t.Fatal("Should have rejected the bootstrap block")
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("Should have accepted the bootstrap block: %s", err)
+			}
+		})
+	}
+}
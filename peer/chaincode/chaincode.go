@@ -56,9 +56,13 @@ func AddFlags(cmd *cobra.Command) {
 		fmt.Sprint("The name of the endorsement system chaincode to be used for this chaincode"))
 	flags.StringVarP(&vscc, "vscc", "V", common.UndefinedParamValue,
 		fmt.Sprint("The name of the verification system chaincode to be used for this chaincode"))
-	flags.StringVarP(&orderingEndpoint, "orderer", "o", "", "Ordering service endpoint")
+	flags.StringVarP(&orderingEndpoint, "orderer", "o", "", "Ordering service endpoint. If not set, it is resolved from the config of the chain held by the peer")
 	flags.BoolVarP(&tls, "tls", "", false, "Use TLS when communicating with the orderer endpoint")
 	flags.StringVarP(&caFile, "cafile", "", "", "Path to file containing PEM-encoded trusted certificate(s) for the ordering endpoint")
+	flags.StringSliceVarP(&peerAddresses, "peerAddresses", "", nil, "Addresses of the peers to collect the endorsements of invokes and queries from. If not set, only the local peer endorses")
+	flags.StringVarP(&connectionProfile, "connectionProfile", "", "", "Connection profile to resolve the endorsing peers of invokes and queries, and the ordering endpoint, of the chain from, if --peerAddresses or --orderer are not set")
+	flags.StringSliceVarP(&targets, "targets", "", nil, "Organizations, peers or org/peer of the connection profile to collect the endorsements from. If not set, all the peers of the chain in the profile endorse")
+	flags.StringSliceVarP(&tlsRootCertFiles, "tlsRootCertFiles", "", nil, "Paths to the TLS root certificates of the peers of --peerAddresses, in the same order, if TLS is enabled. If not set, the configured one is used for all of them")
 }
 
 // Cmd returns the cobra command for Chaincode
@@ -72,29 +76,40 @@ func Cmd(cf *ChaincodeCmdFactory) *cobra.Command {
 	chaincodeCmd.AddCommand(packageCmd(cf, nil))
 	chaincodeCmd.AddCommand(installCmd(cf))
 	chaincodeCmd.AddCommand(signpackageCmd(cf))
+	chaincodeCmd.AddCommand(listCmd(cf))
 
 	return chaincodeCmd
 }
 
 // Chaincode-related variables.
 var (
-	chaincodeLang     string
-	chaincodeCtorJSON string
-	chaincodePath     string
-	chaincodeName     string
-	chaincodeUsr      string
-	chaincodeQueryRaw bool
-	chaincodeQueryHex bool
-	customIDGenAlg    string
-	chainID           string
-	chaincodeVersion  string
-	policy            string
-	escc              string
-	vscc              string
-	policyMarhsalled  []byte
-	orderingEndpoint  string
-	tls               bool
-	caFile            string
+	chaincodeLang            string
+	chaincodeCtorJSON        string
+	chaincodePath            string
+	chaincodeName            string
+	chaincodeUsr             string
+	chaincodeQueryRaw        bool
+	chaincodeQueryHex        bool
+	chaincodeQueryOutput     string
+	chaincodeQueryOutputFile string
+	chaincodeQueryOnly       bool
+	customIDGenAlg           string
+	chainID                  string
+	chaincodeVersion         string
+	policy                   string
+	escc                     string
+	vscc                     string
+	policyMarhsalled         []byte
+	orderingEndpoint         string
+	tls                      bool
+	caFile                   string
+	peerAddresses            []string
+	tlsRootCertFiles         []string
+	connectionProfile        string
+	targets                  []string
+	listInstalled            bool
+	listInstantiated         bool
+	listOutput               string
 )
 
 var chaincodeCmd = &cobra.Command{
@@ -17,19 +17,32 @@ limitations under the License.
 package chaincode
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/scc/lscc"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/peer/common"
 	pcommon "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	mspproto "github.com/hyperledger/fabric/protos/msp"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	putils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/cobra"
@@ -98,7 +111,7 @@ func chaincodeInvokeOrQuery(cmd *cobra.Command, args []string, invoke bool, cf *
 		return err
 	}
 
-	proposalResp, err := ChaincodeInvokeOrQuery(spec, chainID, invoke, cf.Signer, cf.EndorserClient, cf.BroadcastClient)
+	proposalResp, err := ChaincodeInvokeOrQuery(spec, chainID, invoke, cf.Signer, cf.EndorserClients, cf.BroadcastClient)
 	if err != nil {
 		return err
 	}
@@ -110,20 +123,93 @@ func chaincodeInvokeOrQuery(cmd *cobra.Command, args []string, invoke bool, cf *
 			return fmt.Errorf("Error query %s by endorsing: %s", chainFuncName, err)
 		}
 
-		if chaincodeQueryRaw {
-			if chaincodeQueryHex {
-				err = errors.New("Options --raw (-r) and --hex (-x) are not compatible")
-				return
-			}
-			fmt.Print("Query Result (Raw): ")
-			os.Stdout.Write(proposalResp.Response.Payload)
-		} else {
-			if chaincodeQueryHex {
-				fmt.Printf("Query Result: %x\n", proposalResp.Response.Payload)
-			} else {
-				fmt.Printf("Query Result: %s\n", string(proposalResp.Response.Payload))
-			}
+		var format string
+		if format, err = getQueryOutputFormat(); err != nil {
+			return err
 		}
+		err = writeQueryResult(os.Stdout, proposalResp.Response.Payload, format, chaincodeQueryOutputFile)
+	}
+	return err
+}
+
+// Output formats of the query value
+const (
+	queryOutputString = "string"
+	queryOutputRaw    = "raw"
+	queryOutputHex    = "hex"
+	queryOutputJSON   = "json"
+)
+
+// getQueryOutputFormat returns the format of the query value requested with
+// --output, or with the older --raw and --hex options
+func getQueryOutputFormat() (string, error) {
+	if chaincodeQueryRaw && chaincodeQueryHex {
+		return "", errors.New("Options --raw (-r) and --hex (-x) are not compatible")
+	}
+
+	format := queryOutputString
+	if chaincodeQueryRaw {
+		format = queryOutputRaw
+	} else if chaincodeQueryHex {
+		format = queryOutputHex
+	}
+
+	if chaincodeQueryOutput == "" {
+		return format, nil
+	}
+	if (chaincodeQueryRaw || chaincodeQueryHex) && chaincodeQueryOutput != format {
+		return "", fmt.Errorf("Option --output (-O) %s is not compatible with --raw (-r) or --hex (-x)", chaincodeQueryOutput)
+	}
+	switch chaincodeQueryOutput {
+	case queryOutputString, queryOutputRaw, queryOutputHex, queryOutputJSON:
+		return chaincodeQueryOutput, nil
+	}
+	return "", fmt.Errorf("Unknown output format %s, expected one of %s, %s, %s or %s", chaincodeQueryOutput, queryOutputString, queryOutputRaw, queryOutputHex, queryOutputJSON)
+}
+
+// formatQueryResult formats the query value according to the output format
+func formatQueryResult(payload []byte, format string) ([]byte, error) {
+	switch format {
+	case queryOutputString, queryOutputRaw:
+		return payload, nil
+	case queryOutputHex:
+		return []byte(hex.EncodeToString(payload)), nil
+	case queryOutputJSON:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, payload, "", "  "); err != nil {
+			return nil, fmt.Errorf("Query result is not valid JSON: %s", err)
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("Unknown output format %s", format)
+}
+
+// writeQueryResult writes the formatted query value to outputFile if set, as
+// is, or to w otherwise. Raw values are written to w without trailing newline
+func writeQueryResult(w io.Writer, payload []byte, format string, outputFile string) error {
+	result, err := formatQueryResult(payload, format)
+	if err != nil {
+		return err
+	}
+
+	if outputFile != "" {
+		if err = ioutil.WriteFile(outputFile, result, 0644); err != nil {
+			return fmt.Errorf("Error writing query result to %s: %s", outputFile, err)
+		}
+		logger.Infof("Query result written to %s", outputFile)
+		return nil
+	}
+
+	switch format {
+	case queryOutputRaw:
+		_, err = fmt.Fprint(w, "Query Result (Raw): ")
+		if err == nil {
+			_, err = w.Write(result)
+		}
+	case queryOutputJSON:
+		_, err = fmt.Fprintf(w, "Query Result:\n%s\n", result)
+	default:
+		_, err = fmt.Fprintf(w, "Query Result: %s\n", result)
 	}
 	return err
 }
@@ -209,20 +295,31 @@ func checkChaincodeCmdParams(cmd *cobra.Command) error {
 
 // ChaincodeCmdFactory holds the clients used by ChaincodeCmd
 type ChaincodeCmdFactory struct {
-	EndorserClient  pb.EndorserClient
+	// EndorserClient is the client of the first of the EndorserClients
+	EndorserClient pb.EndorserClient
+	// EndorserClients are the clients of the peers of --peerAddresses, or
+	// of the local peer alone if none is given. Invokes and queries collect
+	// the endorsements of all of them
+	EndorserClients []pb.EndorserClient
 	Signer          msp.SigningIdentity
 	BroadcastClient common.BroadcastClient
 }
 
 // InitCmdFactory init the ChaincodeCmdFactory with default clients
 func InitCmdFactory(isEndorserRequired, isOrdererRequired bool) (*ChaincodeCmdFactory, error) {
-	var err error
+	err := applyConnectionProfile(isEndorserRequired)
+	if err != nil {
+		return nil, err
+	}
+
 	var endorserClient pb.EndorserClient
+	var endorserClients []pb.EndorserClient
 	if isEndorserRequired {
-		endorserClient, err = common.GetEndorserClient()
+		endorserClients, err = getEndorserClients()
 		if err != nil {
-			return nil, fmt.Errorf("Error getting endorser client %s: %s", chainFuncName, err)
+			return nil, err
 		}
+		endorserClient = endorserClients[0]
 	}
 
 	signer, err := common.GetDefaultSigner()
@@ -232,6 +329,18 @@ func InitCmdFactory(isEndorserRequired, isOrdererRequired bool) (*ChaincodeCmdFa
 
 	var broadcastClient common.BroadcastClient
 	if isOrdererRequired {
+		if len(orderingEndpoint) == 0 {
+			endpoints, err := common.GetOrdererEndpointOfChain(chainID, signer, endorserClient)
+			if err != nil {
+				return nil, fmt.Errorf("Error getting chain(%s) orderer endpoint: %s", chainID, err)
+			}
+			if len(endpoints) == 0 {
+				return nil, fmt.Errorf("Error no orderer endpoint got for %s", chainID)
+			}
+			orderingEndpoint = endpoints[0]
+			logger.Infof("Retrieved orderer endpoint %s of chain %s", orderingEndpoint, chainID)
+		}
+
 		broadcastClient, err = common.GetBroadcastClient(orderingEndpoint, tls, caFile)
 
 		if err != nil {
@@ -240,12 +349,98 @@ func InitCmdFactory(isEndorserRequired, isOrdererRequired bool) (*ChaincodeCmdFa
 	}
 	return &ChaincodeCmdFactory{
 		EndorserClient:  endorserClient,
+		EndorserClients: endorserClients,
 		Signer:          signer,
 		BroadcastClient: broadcastClient,
 	}, nil
 }
 
-// ChaincodeInvokeOrQuery invokes or queries the chaincode. If successful, the
+// applyConnectionProfile sets the endorsing peers, if isEndorserRequired, and
+// the ordering endpoint of the chain from the connection profile if one is
+// given, unless --peerAddresses and --orderer are set
+func applyConnectionProfile(isEndorserRequired bool) error {
+	if connectionProfile == "" {
+		if len(targets) != 0 {
+			return errors.New("Option --targets requires --connectionProfile")
+		}
+		return nil
+	}
+
+	profile, err := common.LoadConnectionProfile(connectionProfile)
+	if err != nil {
+		return err
+	}
+
+	if isEndorserRequired && len(peerAddresses) == 0 {
+		if len(tlsRootCertFiles) != 0 {
+			return errors.New("Option --tlsRootCertFiles requires --peerAddresses")
+		}
+		peers, err := profile.ChannelPeers(chainID, targets)
+		if err != nil {
+			return err
+		}
+		for _, peer := range peers {
+			peerAddresses = append(peerAddresses, peer.URL)
+			tlsRootCertFiles = append(tlsRootCertFiles, peer.TLSCACerts.Path)
+			logger.Infof("Using peer %s at %s of the connection profile", peer.Name, peer.URL)
+		}
+	}
+
+	if orderingEndpoint == "" {
+		orderer, err := profile.ChannelOrderer(chainID)
+		if err != nil {
+			return err
+		}
+		orderingEndpoint = orderer.URL
+		if orderer.TLSEnabled() {
+			tls = true
+			caFile = orderer.TLSCACerts.Path
+		}
+		logger.Infof("Using orderer %s at %s of the connection profile", orderer.Name, orderingEndpoint)
+	}
+	return nil
+}
+
+// getEndorserClients returns the clients of the peers of --peerAddresses, with
+// the TLS root certificates of --tlsRootCertFiles, or of the local peer
+func getEndorserClients() ([]pb.EndorserClient, error) {
+	if len(peerAddresses) == 0 {
+		if len(tlsRootCertFiles) != 0 {
+			return nil, errors.New("Option --tlsRootCertFiles requires --peerAddresses")
+		}
+		endorserClient, err := common.GetEndorserClient()
+		if err != nil {
+			return nil, fmt.Errorf("Error getting endorser client %s: %s", chainFuncName, err)
+		}
+		return []pb.EndorserClient{endorserClient}, nil
+	}
+
+	if len(tlsRootCertFiles) != 0 && len(tlsRootCertFiles) != len(peerAddresses) {
+		return nil, fmt.Errorf("Got %d --tlsRootCertFiles for %d --peerAddresses, expected one per peer", len(tlsRootCertFiles), len(peerAddresses))
+	}
+
+	var endorserClients []pb.EndorserClient
+	for i, address := range peerAddresses {
+		var tlsRootCertFile string
+		if len(tlsRootCertFiles) != 0 {
+			tlsRootCertFile = tlsRootCertFiles[i]
+		}
+		endorserClient, err := common.GetEndorserClientWithAddress(address, tlsRootCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error getting endorser client of peer %s: %s", address, err)
+		}
+		endorserClients = append(endorserClients, endorserClient)
+	}
+	return endorserClients, nil
+}
+
+// ChaincodeInvokeOrQuery invokes or queries the chaincode, collecting the
+// endorsements of all the endorserClients. Their responses must be successful
+// and carry the same results, and when there are several endorsers of an
+// invoke, their organizations must satisfy the endorsement policy of the
+// chaincode. The results are only then submitted for ordering with all the
+// endorsements, e.g. to satisfy an endorsement policy requiring the
+// endorsements of several organizations. If successful, the
 // INVOKE form prints the ProposalResponse to STDOUT, and the QUERY form prints
 // the query result on STDOUT. A command-line flag (-r, --raw) determines
 // whether the query result is output as raw bytes, or as a printable string.
@@ -254,7 +449,7 @@ func InitCmdFactory(isEndorserRequired, isOrdererRequired bool) (*ChaincodeCmdFa
 //
 // NOTE - Query will likely go away as all interactions with the endorser are
 // Proposal and ProposalResponses
-func ChaincodeInvokeOrQuery(spec *pb.ChaincodeSpec, cID string, invoke bool, signer msp.SigningIdentity, endorserClient pb.EndorserClient, bc common.BroadcastClient) (*pb.ProposalResponse, error) {
+func ChaincodeInvokeOrQuery(spec *pb.ChaincodeSpec, cID string, invoke bool, signer msp.SigningIdentity, endorserClients []pb.EndorserClient, bc common.BroadcastClient) (*pb.ProposalResponse, error) {
 	// Build the ChaincodeInvocationSpec message
 	invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
 	if customIDGenAlg != common.UndefinedParamValue {
@@ -277,22 +472,48 @@ func ChaincodeInvokeOrQuery(spec *pb.ChaincodeSpec, cID string, invoke bool, sig
 		return nil, fmt.Errorf("Error creating proposal  %s: %s", funcName, err)
 	}
 
+	// queries hinted as query-only are executed against a read-only view of the
+	// ledger, which fails the writes of chaincodes writing even on queries
+	if !invoke && chaincodeQueryOnly {
+		if err = putils.SetProposalQueryOnly(prop); err != nil {
+			return nil, fmt.Errorf("Error creating proposal  %s: %s", funcName, err)
+		}
+	}
+
+	// bind the proposal to the TLS client certificate presented to the peers
+	var tlsCertHash []byte
+	if tlsCertHash, err = comm.GetClientCertHash(); err != nil {
+		return nil, fmt.Errorf("Error getting TLS client certificate hash: %s", err)
+	}
+	if tlsCertHash != nil {
+		if err = putils.SetProposalTLSCertHash(prop, tlsCertHash); err != nil {
+			return nil, fmt.Errorf("Error binding proposal %s to TLS client certificate: %s", funcName, err)
+		}
+	}
+
 	var signedProp *pb.SignedProposal
 	signedProp, err = putils.GetSignedProposal(prop, signer)
 	if err != nil {
 		return nil, fmt.Errorf("Error creating signed proposal  %s: %s", funcName, err)
 	}
 
-	var proposalResp *pb.ProposalResponse
-	proposalResp, err = endorserClient.ProcessProposal(context.Background(), signedProp)
+	var proposalResps []*pb.ProposalResponse
+	proposalResps, err = collectEndorsements(signedProp, endorserClients)
 	if err != nil {
 		return nil, fmt.Errorf("Error endorsing %s: %s", funcName, err)
 	}
+	proposalResp := proposalResps[0]
+
+	if invoke && len(proposalResps) > 1 {
+		if err = checkEndorsementPolicy(cID, spec.ChaincodeId.Name, signer, endorserClients[0], proposalResps); err != nil {
+			return proposalResp, fmt.Errorf("Error endorsing %s: %s", funcName, err)
+		}
+	}
 
 	if invoke {
 		if proposalResp != nil {
 			// assemble a signed transaction (it's an Envelope message)
-			env, err := putils.CreateSignedTx(prop, signer, proposalResp)
+			env, err := putils.CreateSignedTx(prop, signer, proposalResps...)
 			if err != nil {
 				return proposalResp, fmt.Errorf("Could not assemble transaction, err %s", err)
 			}
@@ -306,3 +527,218 @@ func ChaincodeInvokeOrQuery(spec *pb.ChaincodeSpec, cID string, invoke bool, sig
 
 	return proposalResp, nil
 }
+
+// collectEndorsements sends the signed proposal to all the endorserClients and
+// returns their responses, after checking that all of them are successful and
+// carry the same results. The responses of a single endorser are returned as
+// is, as they always were, leaving their checks to the caller
+func collectEndorsements(signedProp *pb.SignedProposal, endorserClients []pb.EndorserClient) ([]*pb.ProposalResponse, error) {
+	if len(endorserClients) == 0 {
+		return nil, errors.New("No endorser to send the proposal to")
+	}
+
+	proposalResps := make([]*pb.ProposalResponse, len(endorserClients))
+	errs := make([]error, len(endorserClients))
+	var wg sync.WaitGroup
+	for i, endorserClient := range endorserClients {
+		wg.Add(1)
+		go func(i int, endorserClient pb.EndorserClient) {
+			defer wg.Done()
+			proposalResps[i], errs[i] = endorserClient.ProcessProposal(context.Background(), signedProp)
+		}(i, endorserClient)
+	}
+	wg.Wait()
+
+	if len(endorserClients) == 1 {
+		return proposalResps, errs[0]
+	}
+
+	for i, proposalResp := range proposalResps {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("endorser %d failed: %s", i, errs[i])
+		}
+		if proposalResp == nil || proposalResp.Response == nil {
+			return nil, fmt.Errorf("endorser %d returned no response", i)
+		}
+		if proposalResp.Response.Status != shim.OK {
+			return nil, fmt.Errorf("endorser %d returned status %d, message %s", i, proposalResp.Response.Status, proposalResp.Response.Message)
+		}
+		if i > 0 && !bytes.Equal(proposalResp.Payload, proposalResps[0].Payload) {
+			return nil, fmt.Errorf("the results of endorser %d differ from the ones of endorser 0 in %s", i, payloadMismatch(proposalResps[0].Payload, proposalResp.Payload))
+		}
+	}
+	return proposalResps, nil
+}
+
+// payloadMismatch describes the fields in which two proposal response payloads
+// differ, so that the endorser which diverged can be diagnosed before an
+// unverifiable transaction is submitted
+func payloadMismatch(payload0, payload1 []byte) string {
+	prp0, err0 := putils.GetProposalResponsePayload(payload0)
+	prp1, err1 := putils.GetProposalResponsePayload(payload1)
+	if err0 != nil || err1 != nil {
+		return "the encoding of the payload"
+	}
+
+	var fields []string
+	if !bytes.Equal(prp0.ProposalHash, prp1.ProposalHash) {
+		fields = append(fields, "the proposal hash")
+	}
+
+	action0, err0 := putils.GetChaincodeAction(prp0.Extension)
+	action1, err1 := putils.GetChaincodeAction(prp1.Extension)
+	if err0 != nil || err1 != nil {
+		return strings.Join(append(fields, "the encoding of the chaincode action"), ", ")
+	}
+	if !proto.Equal(action0.ChaincodeId, action1.ChaincodeId) {
+		fields = append(fields, fmt.Sprintf("the chaincode name or version (%s vs %s)", action0.ChaincodeId, action1.ChaincodeId))
+	}
+	if !proto.Equal(action0.Response, action1.Response) {
+		fields = append(fields, "the chaincode response")
+	}
+	if !bytes.Equal(action0.Results, action1.Results) {
+		fields = append(fields, "the read-write set"+rwsetMismatch(action0.Results, action1.Results))
+	}
+	if !bytes.Equal(action0.Events, action1.Events) {
+		fields = append(fields, "the chaincode event")
+	}
+
+	if len(fields) == 0 {
+		return "the encoding of the payload"
+	}
+	return strings.Join(fields, ", ")
+}
+
+// rwsetMismatch lists the namespaces whose read-write sets differ, if the
+// read-write sets can be decoded
+func rwsetMismatch(results0, results1 []byte) string {
+	rwset0, rwset1 := &rwset.TxReadWriteSet{}, &rwset.TxReadWriteSet{}
+	if proto.Unmarshal(results0, rwset0) != nil || proto.Unmarshal(results1, rwset1) != nil {
+		return ""
+	}
+
+	nsRwsets := map[string][]byte{}
+	for _, nsRwset := range rwset0.NsRwset {
+		nsRwsets[nsRwset.Namespace] = nsRwset.Rwset
+	}
+	var namespaces []string
+	for _, nsRwset := range rwset1.NsRwset {
+		other, exists := nsRwsets[nsRwset.Namespace]
+		if !exists || !bytes.Equal(other, nsRwset.Rwset) {
+			namespaces = append(namespaces, nsRwset.Namespace)
+		}
+		delete(nsRwsets, nsRwset.Namespace)
+	}
+	for ns := range nsRwsets {
+		namespaces = append(namespaces, ns)
+	}
+	if len(namespaces) == 0 {
+		return ""
+	}
+	sort.Strings(namespaces)
+	return fmt.Sprintf(" of namespaces %s", strings.Join(namespaces, ", "))
+}
+
+// checkEndorsementPolicy checks that the endorsers of the proposalResps satisfy
+// the endorsement policy of chaincode ccName on channel cID, as returned by lscc
+// on the peer of endorserClient, so that a transaction bound to be invalidated
+// is not submitted. Only the identities of the endorsers are matched against
+// the principals of the policy, their roles and signatures being left to the
+// committing peers, and chaincodes validated by other vscc than the default
+// one are not checked
+func checkEndorsementPolicy(cID, ccName string, signer msp.SigningIdentity, endorserClient pb.EndorserClient, proposalResps []*pb.ProposalResponse) error {
+	cf := &ChaincodeCmdFactory{EndorserClient: endorserClient, Signer: signer}
+	cdbytes, err := queryLSCC(cf, cID, []byte(lscc.GETCCDATA), []byte(cID), []byte(ccName))
+	if err != nil {
+		return fmt.Errorf("could not get the endorsement policy of chaincode %s: %s", ccName, err)
+	}
+	cd := &ccprovider.ChaincodeData{}
+	if err = proto.Unmarshal(cdbytes, cd); err != nil {
+		return fmt.Errorf("could not unmarshal the definition of chaincode %s: %s", ccName, err)
+	}
+	if cd.Vscc != "vscc" {
+		logger.Debugf("Chaincode %s is validated by %s, not checking its endorsement policy", ccName, cd.Vscc)
+		return nil
+	}
+	policy := &pcommon.SignaturePolicyEnvelope{}
+	if err = proto.Unmarshal(cd.Policy, policy); err != nil {
+		return fmt.Errorf("could not unmarshal the endorsement policy of chaincode %s: %s", ccName, err)
+	}
+
+	endorsers := make([]*mspproto.SerializedIdentity, len(proposalResps))
+	mspIDs := make([]string, len(proposalResps))
+	for i, proposalResp := range proposalResps {
+		if proposalResp.Endorsement == nil {
+			return fmt.Errorf("endorser %d returned no endorsement", i)
+		}
+		endorsers[i] = &mspproto.SerializedIdentity{}
+		if err = proto.Unmarshal(proposalResp.Endorsement.Endorser, endorsers[i]); err != nil {
+			return fmt.Errorf("could not unmarshal the identity of endorser %d: %s", i, err)
+		}
+		mspIDs[i] = endorsers[i].Mspid
+	}
+
+	if !policySatisfiedBy(policy.Policy, policy.Identities, endorsers, make([]bool, len(endorsers))) {
+		return fmt.Errorf("the endorsements of peers of MSPs %s do not satisfy the endorsement policy of chaincode %s", strings.Join(mspIDs, ", "), ccName)
+	}
+	return nil
+}
+
+// policySatisfiedBy evaluates the signature policy like cauthdsl, each of the
+// endorsers not used yet satisfying the principals it matches
+func policySatisfiedBy(policy *pcommon.SignaturePolicy, principals []*mspproto.MSPPrincipal, endorsers []*mspproto.SerializedIdentity, used []bool) bool {
+	switch t := policy.Type.(type) {
+	case *pcommon.SignaturePolicy_NOutOf_:
+		satisfied := int32(0)
+		_used := make([]bool, len(used))
+		for _, policy := range t.NOutOf.Policies {
+			copy(_used, used)
+			if policySatisfiedBy(policy, principals, endorsers, _used) {
+				satisfied++
+				copy(used, _used)
+			}
+		}
+		return satisfied >= t.NOutOf.N
+	case *pcommon.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || t.SignedBy >= int32(len(principals)) {
+			return false
+		}
+		for i, endorser := range endorsers {
+			if !used[i] && principalMatchedBy(principals[t.SignedBy], endorser) {
+				used[i] = true
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// principalMatchedBy tells whether the endorser may satisfy the principal,
+// i.e. whether it belongs to the MSP of the principal, or is the identity of
+// the principal. The principals of other classifications are assumed satisfied
+func principalMatchedBy(principal *mspproto.MSPPrincipal, endorser *mspproto.SerializedIdentity) bool {
+	switch principal.PrincipalClassification {
+	case mspproto.MSPPrincipal_ROLE:
+		role := &mspproto.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return false
+		}
+		return role.MspIdentifier == endorser.Mspid
+	case mspproto.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mspproto.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+			return false
+		}
+		return ou.MspIdentifier == endorser.Mspid
+	case mspproto.MSPPrincipal_IDENTITY:
+		identity := &mspproto.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, identity); err != nil {
+			return false
+		}
+		return identity.Mspid == endorser.Mspid && bytes.Equal(identity.IdBytes, endorser.IdBytes)
+	default:
+		return true
+	}
+}
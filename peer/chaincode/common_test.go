@@ -17,10 +17,22 @@
 package chaincode
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	mspproto "github.com/hyperledger/fabric/protos/msp"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 )
@@ -123,3 +135,204 @@ func TestCheckInvalidJSON(t *testing.T) {
 		return
 	}
 }
+
+func TestGetQueryOutputFormat(t *testing.T) {
+	defer func() {
+		chaincodeQueryRaw, chaincodeQueryHex, chaincodeQueryOutput = false, false, ""
+	}()
+
+	for _, c := range []struct {
+		raw, hex bool
+		output   string
+		expected string
+	}{
+		{false, false, "", queryOutputString},
+		{true, false, "", queryOutputRaw},
+		{false, true, "", queryOutputHex},
+		{false, false, queryOutputJSON, queryOutputJSON},
+		{true, false, queryOutputRaw, queryOutputRaw},
+	} {
+		chaincodeQueryRaw, chaincodeQueryHex, chaincodeQueryOutput = c.raw, c.hex, c.output
+		format, err := getQueryOutputFormat()
+		require.NoError(t, err)
+		require.Equal(t, c.expected, format)
+	}
+
+	for _, c := range []struct {
+		raw, hex bool
+		output   string
+	}{
+		{true, true, ""},
+		{true, false, queryOutputJSON},
+		{false, true, queryOutputRaw},
+		{false, false, "yaml"},
+	} {
+		chaincodeQueryRaw, chaincodeQueryHex, chaincodeQueryOutput = c.raw, c.hex, c.output
+		_, err := getQueryOutputFormat()
+		require.Error(t, err)
+	}
+}
+
+func TestWriteQueryResult(t *testing.T) {
+	binary := []byte{0x00, 0xff, '\n'}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeQueryResult(&buf, binary, queryOutputRaw, ""))
+	require.Equal(t, append([]byte("Query Result (Raw): "), binary...), buf.Bytes())
+
+	buf.Reset()
+	require.NoError(t, writeQueryResult(&buf, binary, queryOutputHex, ""))
+	require.Equal(t, "Query Result: 00ff0a\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeQueryResult(&buf, []byte(`{"a":[1,2]}`), queryOutputJSON, ""))
+	require.Equal(t, "Query Result:\n{\n  \"a\": [\n    1,\n    2\n  ]\n}\n", buf.String())
+
+	require.Error(t, writeQueryResult(&buf, binary, queryOutputJSON, ""))
+
+	dir, err := ioutil.TempDir("", "query")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "result")
+
+	buf.Reset()
+	require.NoError(t, writeQueryResult(&buf, binary, queryOutputRaw, file))
+	require.Empty(t, buf.Bytes())
+	written, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, binary, written)
+}
+
+func TestCollectEndorsements(t *testing.T) {
+	response := func(status int32, payload string) *pb.ProposalResponse {
+		return &pb.ProposalResponse{Response: &pb.Response{Status: status}, Payload: []byte(payload)}
+	}
+	signedProp := &pb.SignedProposal{}
+
+	resps, err := collectEndorsements(signedProp, []pb.EndorserClient{
+		common.GetMockEndorserClient(response(200, "results"), nil),
+		common.GetMockEndorserClient(response(200, "results"), nil),
+	})
+	require.NoError(t, err)
+	require.Len(t, resps, 2)
+
+	// A single endorser is left to the caller, as before
+	resps, err = collectEndorsements(signedProp, []pb.EndorserClient{common.GetMockEndorserClient(response(500, ""), nil)})
+	require.NoError(t, err)
+	require.Equal(t, int32(500), resps[0].Response.Status)
+
+	for _, second := range []pb.EndorserClient{
+		common.GetMockEndorserClient(response(200, "other results"), nil),
+		common.GetMockEndorserClient(response(500, "results"), nil),
+		common.GetMockEndorserClient(nil, nil),
+		common.GetMockEndorserClient(nil, errors.New("unreachable")),
+	} {
+		_, err = collectEndorsements(signedProp, []pb.EndorserClient{common.GetMockEndorserClient(response(200, "results"), nil), second})
+		require.Error(t, err)
+	}
+
+	_, err = collectEndorsements(signedProp, nil)
+	require.Error(t, err)
+}
+
+func TestCheckEndorsementPolicy(t *testing.T) {
+	InitMSP()
+	signer, err := common.GetDefaultSigner()
+	require.NoError(t, err)
+
+	endorsement := func(mspID string) *pb.ProposalResponse {
+		endorser, err := proto.Marshal(&mspproto.SerializedIdentity{Mspid: mspID, IdBytes: []byte(mspID + " peer")})
+		require.NoError(t, err)
+		return &pb.ProposalResponse{Endorsement: &pb.Endorsement{Endorser: endorser}}
+	}
+	lsccClient := func(vscc, policy string) pb.EndorserClient {
+		envelope, err := cauthdsl.FromString(policy)
+		require.NoError(t, err)
+		cd := &ccprovider.ChaincodeData{Name: "mycc", Version: "1.0", Vscc: vscc, Policy: putils.MarshalOrPanic(envelope)}
+		return common.GetMockEndorserClient(&pb.ProposalResponse{Response: &pb.Response{Status: 200, Payload: putils.MarshalOrPanic(cd)}}, nil)
+	}
+	org1, org2 := endorsement("Org1MSP"), endorsement("Org2MSP")
+	and := "AND('Org1MSP.member', 'Org2MSP.member')"
+
+	require.NoError(t, checkEndorsementPolicy("mychannel", "mycc", signer, lsccClient("vscc", and), []*pb.ProposalResponse{org1, org2}))
+	require.NoError(t, checkEndorsementPolicy("mychannel", "mycc", signer, lsccClient("vscc", "OR('Org1MSP.member', 'Org2MSP.member')"), []*pb.ProposalResponse{org1, org1}))
+
+	// an endorsement satisfies a single principal
+	err = checkEndorsementPolicy("mychannel", "mycc", signer, lsccClient("vscc", and), []*pb.ProposalResponse{org1, org1})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Org1MSP, Org1MSP")
+	require.Error(t, checkEndorsementPolicy("mychannel", "mycc", signer, lsccClient("vscc", "AND('Org1MSP.member', 'Org1MSP.member', 'Org2MSP.member')"), []*pb.ProposalResponse{org1, org2}))
+
+	// the policies of other vscc are not checked
+	require.NoError(t, checkEndorsementPolicy("mychannel", "mycc", signer, lsccClient("myvscc", and), []*pb.ProposalResponse{org1, org1}))
+
+	// nor submitted without the policy
+	failing := common.GetMockEndorserClient(&pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "not found"}}, nil)
+	require.Error(t, checkEndorsementPolicy("mychannel", "mycc", signer, failing, []*pb.ProposalResponse{org1, org2}))
+}
+
+func TestPayloadMismatch(t *testing.T) {
+	rwsetBytes := func(namespaces ...string) []byte {
+		txRWSet := &rwset.TxReadWriteSet{}
+		for _, ns := range namespaces {
+			txRWSet.NsRwset = append(txRWSet.NsRwset, &rwset.NsReadWriteSet{Namespace: ns, Rwset: []byte(ns + " writes")})
+		}
+		b, err := proto.Marshal(txRWSet)
+		require.NoError(t, err)
+		return b
+	}
+	payload := func(action *pb.ChaincodeAction) []byte {
+		actionBytes, err := proto.Marshal(action)
+		require.NoError(t, err)
+		b, err := proto.Marshal(&pb.ProposalResponsePayload{ProposalHash: []byte("hash"), Extension: actionBytes})
+		require.NoError(t, err)
+		return b
+	}
+	action := func() *pb.ChaincodeAction {
+		return &pb.ChaincodeAction{
+			Results:     rwsetBytes("mycc", "othercc"),
+			Events:      []byte("event"),
+			Response:    &pb.Response{Status: 200, Payload: []byte("90")},
+			ChaincodeId: &pb.ChaincodeID{Name: "mycc", Version: "1.0"},
+		}
+	}
+
+	base := payload(action())
+	require.Equal(t, "the encoding of the payload", payloadMismatch(base, base))
+	require.Equal(t, "the encoding of the payload", payloadMismatch(base, []byte("garbage")))
+
+	diverging := action()
+	diverging.ChaincodeId.Version = "2.0"
+	require.Contains(t, payloadMismatch(base, payload(diverging)), "the chaincode name or version")
+
+	diverging = action()
+	diverging.Response.Payload = []byte("80")
+	require.Equal(t, "the chaincode response", payloadMismatch(base, payload(diverging)))
+
+	diverging = action()
+	diverging.Events = []byte("other event")
+	require.Equal(t, "the chaincode event", payloadMismatch(base, payload(diverging)))
+
+	diverging = action()
+	diverging.Results = rwsetBytes("mycc", "thirdcc")
+	require.Equal(t, "the read-write set of namespaces othercc, thirdcc", payloadMismatch(base, payload(diverging)))
+
+	diverging = action()
+	diverging.Results = []byte("garbage")
+	diverging.Events = nil
+	require.Equal(t, "the read-write set, the chaincode event", payloadMismatch(base, payload(diverging)))
+}
+
+func TestGetEndorserClientsFlags(t *testing.T) {
+	defer func() {
+		peerAddresses, tlsRootCertFiles = nil, nil
+	}()
+
+	tlsRootCertFiles = []string{"ca.pem"}
+	_, err := getEndorserClients()
+	require.Error(t, err)
+
+	peerAddresses = []string{"peer0:7051", "peer1:7051"}
+	_, err = getEndorserClients()
+	require.Error(t, err)
+}
@@ -0,0 +1,237 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/scc/lscc"
+	pcommon "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+const (
+	listOutputJSON = "json"
+	listOutputText = "text"
+)
+
+// chaincodeList is the output of list, with the hashes hex encoded
+type chaincodeList struct {
+	// Channel the chaincodes are instantiated on, empty for the installed ones
+	Channel    string            `json:"channel,omitempty"`
+	Chaincodes []*chaincodeEntry `json:"chaincodes"`
+}
+
+type chaincodeEntry struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	// Id and PackageHash identify the installed packages
+	Id          string `json:"id,omitempty"`
+	PackageHash string `json:"packageHash,omitempty"`
+	// Input, Escc and Vscc describe the instantiated chaincodes
+	Input string `json:"input,omitempty"`
+	Escc  string `json:"escc,omitempty"`
+	Vscc  string `json:"vscc,omitempty"`
+}
+
+var chaincodeListCmd *cobra.Command
+
+// listCmd returns the cobra command for listing the chaincodes installed on
+// the peer or instantiated on a channel
+func listCmd(cf *ChaincodeCmdFactory) *cobra.Command {
+	chaincodeListCmd = &cobra.Command{
+		Use:   "list",
+		Short: fmt.Sprintf("Get the %ss installed on the peer or instantiated on a channel.", chainFuncName),
+		Long:  fmt.Sprintf("Get the %ss installed on the peer with --installed, with the fingerprints of their packages, or instantiated on the channel of -C with --instantiated.", chainFuncName),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listChaincodes(cf)
+		},
+	}
+
+	chaincodeListCmd.Flags().BoolVarP(&listInstalled, "installed", "", false,
+		"Get the chaincodes installed on the peer")
+	chaincodeListCmd.Flags().BoolVarP(&listInstantiated, "instantiated", "", false,
+		"Get the chaincodes instantiated on the channel of -C")
+	chaincodeListCmd.Flags().StringVarP(&listOutput, "output", "O", listOutputText,
+		fmt.Sprintf("Format of the list, %s or %s", listOutputText, listOutputJSON))
+
+	return chaincodeListCmd
+}
+
+func listChaincodes(cf *ChaincodeCmdFactory) error {
+	if listInstalled == listInstantiated {
+		return errors.New("Must supply one of --installed or --instantiated")
+	}
+	if listOutput != listOutputJSON && listOutput != listOutputText {
+		return fmt.Errorf("Invalid output format %s, expected %s or %s", listOutput, listOutputText, listOutputJSON)
+	}
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(true, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	var list *chaincodeList
+	if listInstalled {
+		list, err = getInstalledChaincodes(cf)
+	} else {
+		list, err = getInstantiatedChaincodes(cf, chainID)
+	}
+	if err != nil {
+		return err
+	}
+
+	return printChaincodeList(os.Stdout, list, listOutput)
+}
+
+// getInstalledChaincodes returns the chaincodes installed on the peer, along
+// with the id and hash of their packages
+func getInstalledChaincodes(cf *ChaincodeCmdFactory) (*chaincodeList, error) {
+	payload, err := queryLSCC(cf, "", []byte(lscc.GETINSTALLEDCHAINCODES))
+	if err != nil {
+		return nil, err
+	}
+	cqr := &pb.ChaincodeQueryResponse{}
+	if err = proto.Unmarshal(payload, cqr); err != nil {
+		return nil, fmt.Errorf("Cannot read installed chaincodes response, %s", err)
+	}
+
+	list := &chaincodeList{Chaincodes: []*chaincodeEntry{}}
+	for _, ccInfo := range cqr.Chaincodes {
+		entry := &chaincodeEntry{Name: ccInfo.Name, Version: ccInfo.Version, Path: ccInfo.Path}
+
+		payload, err = queryLSCC(cf, "", []byte(lscc.GETINSTALLEDMETADATA), []byte(ccInfo.Name), []byte(ccInfo.Version))
+		if err != nil {
+			return nil, err
+		}
+		md := &ccprovider.InstalledChaincodeMetadata{}
+		if err = proto.Unmarshal(payload, md); err != nil {
+			return nil, fmt.Errorf("Cannot read metadata response of %s:%s, %s", ccInfo.Name, ccInfo.Version, err)
+		}
+		entry.Id, entry.PackageHash = hex.EncodeToString(md.Id), hex.EncodeToString(md.PackageHash)
+
+		list.Chaincodes = append(list.Chaincodes, entry)
+	}
+	return list, nil
+}
+
+// getInstantiatedChaincodes returns the chaincodes instantiated on a channel
+func getInstantiatedChaincodes(cf *ChaincodeCmdFactory, chainID string) (*chaincodeList, error) {
+	payload, err := queryLSCC(cf, chainID, []byte(lscc.GETCHAINCODES))
+	if err != nil {
+		return nil, err
+	}
+	cqr := &pb.ChaincodeQueryResponse{}
+	if err = proto.Unmarshal(payload, cqr); err != nil {
+		return nil, fmt.Errorf("Cannot read instantiated chaincodes response, %s", err)
+	}
+
+	list := &chaincodeList{Channel: chainID, Chaincodes: []*chaincodeEntry{}}
+	for _, ccInfo := range cqr.Chaincodes {
+		list.Chaincodes = append(list.Chaincodes, &chaincodeEntry{
+			Name:    ccInfo.Name,
+			Version: ccInfo.Version,
+			Path:    ccInfo.Path,
+			Input:   ccInfo.Input,
+			Escc:    ccInfo.Escc,
+			Vscc:    ccInfo.Vscc,
+		})
+	}
+	return list, nil
+}
+
+// queryLSCC invokes lscc with the given arguments on the peer, on the given
+// channel or on none if empty, and returns the payload of its response
+func queryLSCC(cf *ChaincodeCmdFactory, chainID string, args ...[]byte) ([]byte, error) {
+	invocation := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: "lscc"},
+			Input:       &pb.ChaincodeInput{Args: args},
+		},
+	}
+
+	creator, err := cf.Signer.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("Error serializing identity for %s: %s", cf.Signer.GetIdentifier(), err)
+	}
+
+	prop, _, err := utils.CreateProposalFromCIS(pcommon.HeaderType_ENDORSER_TRANSACTION, chainID, invocation, creator)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot create proposal, due to %s", err)
+	}
+
+	signedProp, err := utils.GetSignedProposal(prop, cf.Signer)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot create signed proposal, due to %s", err)
+	}
+
+	proposalResp, err := cf.EndorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed sending proposal, got %s", err)
+	}
+
+	if proposalResp.Response == nil {
+		return nil, errors.New("Received bad response, no response")
+	}
+	if proposalResp.Response.Status != 200 {
+		return nil, fmt.Errorf("Received bad response, status %d: %s", proposalResp.Response.Status, proposalResp.Response.Message)
+	}
+	return proposalResp.Response.Payload, nil
+}
+
+// printChaincodeList writes the chaincodes in the given format
+func printChaincodeList(w io.Writer, list *chaincodeList, format string) error {
+	if format == listOutputJSON {
+		out, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", out)
+		return err
+	}
+
+	if list.Channel != "" {
+		fmt.Fprintf(w, "Chaincodes instantiated on channel %s:\n", list.Channel)
+	} else {
+		fmt.Fprintln(w, "Chaincodes installed on the peer:")
+	}
+	for _, entry := range list.Chaincodes {
+		if list.Channel != "" {
+			fmt.Fprintf(w, "Name: %s, Version: %s, Path: %s, Input: %s, Escc: %s, Vscc: %s\n",
+				entry.Name, entry.Version, entry.Path, entry.Input, entry.Escc, entry.Vscc)
+		} else {
+			fmt.Fprintf(w, "Name: %s, Version: %s, Path: %s, Id: %s, Package hash: %s\n",
+				entry.Name, entry.Version, entry.Path, entry.Id, entry.PackageHash)
+		}
+	}
+	return nil
+}
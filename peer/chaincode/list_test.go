@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/scc/lscc"
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// lsccEndorserClient answers the lscc queries with the payloads of their functions
+type lsccEndorserClient struct {
+	payloads map[string]proto.Message
+	channels []string
+}
+
+func (c *lsccEndorserClient) ProcessProposal(ctx context.Context, in *pb.SignedProposal, opts ...grpc.CallOption) (*pb.ProposalResponse, error) {
+	prop, err := utils.GetProposal(in.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
+	hdr, err := utils.GetHeader(prop.Header)
+	if err != nil {
+		return nil, err
+	}
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	c.channels = append(c.channels, chdr.ChannelId)
+	cis, err := utils.GetChaincodeInvocationSpec(prop)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, ok := c.payloads[string(cis.ChaincodeSpec.Input.Args[0])]
+	if !ok {
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "unexpected function"}}, nil
+	}
+	return &pb.ProposalResponse{Response: &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(payload)}}, nil
+}
+
+func TestListInstalled(t *testing.T) {
+	InitMSP()
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err)
+
+	client := &lsccEndorserClient{payloads: map[string]proto.Message{
+		lscc.GETINSTALLEDCHAINCODES: &pb.ChaincodeQueryResponse{Chaincodes: []*pb.ChaincodeInfo{{Name: "mycc", Version: "1.0", Path: "github.com/mycc"}}},
+		lscc.GETINSTALLEDMETADATA:   &ccprovider.InstalledChaincodeMetadata{Name: "mycc", Version: "1.0", Id: []byte{1, 2}, PackageHash: []byte{3, 4}},
+	}}
+	cf := &ChaincodeCmdFactory{EndorserClient: client, Signer: signer}
+
+	list, err := getInstalledChaincodes(cf)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", ""}, client.channels, "Installed chaincodes should be queried on no channel")
+	assert.Equal(t, []*chaincodeEntry{{Name: "mycc", Version: "1.0", Path: "github.com/mycc", Id: "0102", PackageHash: "0304"}}, list.Chaincodes)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, printChaincodeList(buf, list, listOutputJSON))
+	decoded := &chaincodeList{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+	assert.Equal(t, list, decoded)
+
+	buf.Reset()
+	assert.NoError(t, printChaincodeList(buf, list, listOutputText))
+	assert.Contains(t, buf.String(), "Name: mycc, Version: 1.0, Path: github.com/mycc, Id: 0102, Package hash: 0304")
+
+	// a failed metadata query fails the listing
+	delete(client.payloads, lscc.GETINSTALLEDMETADATA)
+	_, err = getInstalledChaincodes(cf)
+	assert.Error(t, err)
+}
+
+func TestListInstantiated(t *testing.T) {
+	InitMSP()
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err)
+
+	client := &lsccEndorserClient{payloads: map[string]proto.Message{
+		lscc.GETCHAINCODES: &pb.ChaincodeQueryResponse{Chaincodes: []*pb.ChaincodeInfo{{Name: "mycc", Version: "1.0", Escc: "escc", Vscc: "vscc"}}},
+	}}
+	cf := &ChaincodeCmdFactory{EndorserClient: client, Signer: signer}
+
+	list, err := getInstantiatedChaincodes(cf, "mychannel")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mychannel"}, client.channels)
+	assert.Equal(t, "mychannel", list.Channel)
+	assert.Equal(t, []*chaincodeEntry{{Name: "mycc", Version: "1.0", Escc: "escc", Vscc: "vscc"}}, list.Chaincodes)
+}
+
+func TestListFlags(t *testing.T) {
+	InitMSP()
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err)
+	cf := &ChaincodeCmdFactory{EndorserClient: &lsccEndorserClient{}, Signer: signer}
+
+	cmd := listCmd(cf)
+	AddFlags(cmd)
+
+	cmd.SetArgs([]string{})
+	assert.Error(t, cmd.Execute(), "Exactly one of --installed or --instantiated should be required")
+
+	cmd.SetArgs([]string{"--installed", "--instantiated"})
+	assert.Error(t, cmd.Execute(), "Exactly one of --installed or --instantiated should be required")
+
+	cmd.SetArgs([]string{"--installed", "--instantiated=false", "-O", "yaml"})
+	assert.Error(t, cmd.Execute(), "Unknown output formats should be rejected")
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/common/ccpackage"
 	"github.com/hyperledger/fabric/msp"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
@@ -68,7 +69,7 @@ func packageCmd(cf *ChaincodeCmdFactory, cdsFact ccDepSpecFactory) *cobra.Comman
 
 	chaincodePackageCmd.Flags().BoolVarP(&createSignedCCDepSpec, "cc-package", "s", false, "create CC deployment spec for owner endorsements instead of raw CC deployment spec")
 	chaincodePackageCmd.Flags().BoolVarP(&signCCDepSpec, "sign", "S", false, "if creating CC deployment spec package for owner endorsements, also sign it with local MSP")
-	chaincodePackageCmd.Flags().StringVarP(&instantiationPolicy, "instantiate-policy", "i", "", "instatiation policy for the chaincode")
+	chaincodePackageCmd.Flags().StringVarP(&instantiationPolicy, "instantiate-policy", "i", "", "instatiation policy for the chaincode, either a signature policy or a template of the channel config such as \"MAJORITY Admins\"")
 
 	return chaincodePackageCmd
 }
@@ -118,15 +119,24 @@ func getChaincodeInstallPackage(cds *pb.ChaincodeDeploymentSpec, cf *ChaincodeCm
 		ip = "AND('" + mspid + ".admin')"
 	}
 
-	sp, err := getInstantiationPolicy(ip)
-	if err != nil {
-		return nil, err
-	}
+	//a template is resolved against the orgs of the channel config when the
+	//chaincode is instantiated, instead of naming the MSPs in the package
+	if template, err := policies.ImplicitMetaFromString(ip); err == nil {
+		objToWrite, err = ccpackage.OwnerCreateSignedCCDepSpecWithTemplate(cds, template, owner)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		sp, err := getInstantiationPolicy(ip)
+		if err != nil {
+			return nil, err
+		}
 
-	//we get the Envelope of type CHAINCODE_PACKAGE
-	objToWrite, err = ccpackage.OwnerCreateSignedCCDepSpec(cds, sp, owner)
-	if err != nil {
-		return nil, err
+		//we get the Envelope of type CHAINCODE_PACKAGE
+		objToWrite, err = ccpackage.OwnerCreateSignedCCDepSpec(cds, sp, owner)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	//convert the proto object to bytes
@@ -24,6 +24,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 
+	"github.com/hyperledger/fabric/core/common/ccpackage"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/peer/common"
 	pcommon "github.com/hyperledger/fabric/protos/common"
@@ -202,3 +203,38 @@ func TestInvalidPolicy(t *testing.T) {
 		t.Fatalf("Expected error with nil signer but succeeded")
 	}
 }
+
+func TestSignedCDSPackageWithPolicyTemplate(t *testing.T) {
+	pdir := newTempDir()
+	defer os.RemoveAll(pdir)
+	defer func() { instantiationPolicy = "" }()
+
+	ccpackfile := pdir + "/ccpack.file"
+	err := createSignedCDSPackage([]string{"-n", "somecc", "-p", "some/go/package", "-v", "0", "-s", "-i", "MAJORITY Admins", ccpackfile}, false)
+	if err != nil {
+		t.Fatalf("could not create signed cds package %s", err)
+	}
+
+	b, err := ioutil.ReadFile(ccpackfile)
+	if err != nil {
+		t.Fatalf("package file %s not created", ccpackfile)
+	}
+	e := &pcommon.Envelope{}
+	err = proto.Unmarshal(b, e)
+	if err != nil {
+		t.Fatalf("could not unmarshall envelope")
+	}
+
+	_, p, err := extractSignedCCDepSpec(e)
+	if err != nil {
+		t.Fatalf("could not extract signed dep spec")
+	}
+
+	template, isTemplate, err := ccpackage.InstantiationPolicyTemplate(p.InstantiationPolicy)
+	if err != nil || !isTemplate {
+		t.Fatalf("expected an instantiation policy template, got %v (%v)", template, err)
+	}
+	if template.Rule != pcommon.ImplicitMetaPolicy_MAJORITY || template.SubPolicy != "Admins" {
+		t.Fatalf("unexpected instantiation policy template %v", template)
+	}
+}
@@ -40,6 +40,12 @@ func queryCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 		"If true, output the query value as raw bytes, otherwise format as a printable string")
 	chaincodeQueryCmd.Flags().BoolVarP(&chaincodeQueryHex, "hex", "x", false,
 		"If true, output the query value byte array in hexadecimal. Incompatible with --raw")
+	chaincodeQueryCmd.Flags().StringVarP(&chaincodeQueryOutput, "output", "O", "",
+		fmt.Sprintf("Format of the query value, one of %s, %s, %s or %s. Defaults to %s, or to the format of --raw/--hex", queryOutputString, queryOutputRaw, queryOutputHex, queryOutputJSON, queryOutputString))
+	chaincodeQueryCmd.Flags().StringVarP(&chaincodeQueryOutputFile, "outputFile", "f", "",
+		"Write the formatted query value to this file instead of printing it, e.g. to save binary values with --output raw")
+	chaincodeQueryCmd.Flags().BoolVarP(&chaincodeQueryOnly, "queryOnly", "", false,
+		"If true, hint the peers that the query only reads the ledger, so that they execute it against a read-only view of the ledger and do not endorse its results")
 
 	return chaincodeQueryCmd
 }
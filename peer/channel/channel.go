@@ -40,11 +40,15 @@ var (
 	genesisBlockPath string
 
 	// create related variables
-	chainID          string
-	channelTxFile    string
-	orderingEndpoint string
-	tls              bool
-	caFile           string
+	chainID           string
+	channelTxFile     string
+	orderingEndpoint  string
+	tls               bool
+	caFile            string
+	connectionProfile string
+
+	// getinfo related variables
+	getInfoOutput string
 )
 
 // Cmd returns the cobra command for Node
@@ -55,6 +59,8 @@ func Cmd(cf *ChannelCmdFactory) *cobra.Command {
 	channelCmd.AddCommand(createCmd(cf))
 	channelCmd.AddCommand(fetchCmd(cf))
 	channelCmd.AddCommand(listCmd(cf))
+	channelCmd.AddCommand(getinfoCmd(cf))
+	channelCmd.AddCommand(checkUpdateCmd(cf))
 
 	return channelCmd
 }
@@ -69,6 +75,8 @@ func AddFlags(cmd *cobra.Command) {
 	flags.StringVarP(&orderingEndpoint, "orderer", "o", "", "Ordering service endpoint")
 	flags.BoolVarP(&tls, "tls", "", false, "Use TLS when communicating with the orderer endpoint")
 	flags.StringVarP(&caFile, "cafile", "", "", "Path to file containing PEM-encoded trusted certificate(s) for the ordering endpoint")
+	flags.StringVarP(&connectionProfile, "connectionProfile", "", "", "Connection profile to resolve the ordering service endpoint of the chain from, if --orderer is not set")
+	flags.StringVarP(&getInfoOutput, "output", "O", getInfoOutputJSON, fmt.Sprintf("Output format of getinfo, %s or %s", getInfoOutputJSON, getInfoOutputText))
 }
 
 var channelCmd = &cobra.Command{
@@ -88,12 +96,40 @@ type ChannelCmdFactory struct {
 	BroadcastFactory BroadcastClientFactory
 }
 
+// applyConnectionProfile sets the ordering service endpoint, and its TLS root
+// certificate, from the connection profile if one is given and --orderer isn't
+func applyConnectionProfile() error {
+	if connectionProfile == "" || orderingEndpoint != "" {
+		return nil
+	}
+
+	profile, err := common.LoadConnectionProfile(connectionProfile)
+	if err != nil {
+		return err
+	}
+	orderer, err := profile.ChannelOrderer(chainID)
+	if err != nil {
+		return err
+	}
+	orderingEndpoint = orderer.URL
+	if orderer.TLSEnabled() {
+		tls = true
+		caFile = orderer.TLSCACerts.Path
+	}
+	logger.Infof("Using orderer %s at %s of the connection profile", orderer.Name, orderingEndpoint)
+	return nil
+}
+
 // InitCmdFactory init the ChannelCmdFactor with default clients
 func InitCmdFactory(isOrdererRequired bool) (*ChannelCmdFactory, error) {
 	var err error
 
 	cmdFact := &ChannelCmdFactory{}
 
+	if err = applyConnectionProfile(); err != nil {
+		return nil, err
+	}
+
 	cmdFact.Signer, err = common.GetDefaultSigner()
 	if err != nil {
 		return nil, fmt.Errorf("Error getting default signer: %s", err)
@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/cobra"
+)
+
+const checkUpdateCmdDescription = "Check a config update against the config block of a channel before submitting it."
+
+func checkUpdateCmd(cf *ChannelCmdFactory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "checkupdate",
+		Short: checkUpdateCmdDescription,
+		Long:  checkUpdateCmdDescription + " The config block, given with -b, is the latest config block of the channel, as retrieved with fetch, and the config update, given with -f, must carry the signatures it will be submitted with.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkUpdate(cmd, args, cf)
+		},
+	}
+}
+
+func checkUpdate(cmd *cobra.Command, args []string, cf *ChannelCmdFactory) error {
+	if genesisBlockPath == common.UndefinedParamValue {
+		return errors.New("Must supply the config block file")
+	}
+	if channelTxFile == "" {
+		return errors.New("Must supply the config update file")
+	}
+
+	blockBytes, err := ioutil.ReadFile(genesisBlockPath)
+	if err != nil {
+		return fmt.Errorf("Error reading the config block: %s", err)
+	}
+	block, err := utils.GetBlockFromBlockBytes(blockBytes)
+	if err != nil {
+		return fmt.Errorf("Error unmarshaling the config block: %s", err)
+	}
+	config, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return fmt.Errorf("Error extracting the config envelope: %s", err)
+	}
+
+	updateBytes, err := ioutil.ReadFile(channelTxFile)
+	if err != nil {
+		return ConfigTxFileNotFound(err.Error())
+	}
+	update, err := utils.UnmarshalEnvelope(updateBytes)
+	if err != nil {
+		return fmt.Errorf("Error unmarshaling the config update: %s", err)
+	}
+
+	configEnv, err := configtx.ValidateConfigUpdate(config, update, configtx.NewInitializer())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Config update is valid and produces config sequence %d\n", configEnv.Config.Sequence)
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeConfigUpdateTx(chainID string) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(cb.HeaderType_CONFIG_UPDATE),
+					ChannelId: chainID,
+				}),
+			},
+			Data: utils.MarshalOrPanic(&cb.ConfigUpdateEnvelope{
+				ConfigUpdate: utils.MarshalOrPanic(&cb.ConfigUpdate{
+					ChannelId: chainID,
+					ReadSet:   cb.NewConfigGroup(),
+					WriteSet:  &cb.ConfigGroup{Version: 1},
+				}),
+			}),
+		}),
+	}
+}
+
+func TestCheckUpdate(t *testing.T) {
+	InitMSP()
+
+	dir, err := ioutil.TempDir("", "checkupdate")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	block, err := configtxtest.MakeGenesisBlock("mychannel")
+	assert.NoError(t, err)
+	blockFile := filepath.Join(dir, "mychannel.block")
+	assert.NoError(t, ioutil.WriteFile(blockFile, utils.MarshalOrPanic(block), 0644))
+
+	otherFile := filepath.Join(dir, "other.tx")
+	assert.NoError(t, ioutil.WriteFile(otherFile, utils.MarshalOrPanic(makeConfigUpdateTx("other")), 0644))
+	// Modifies the channel group without the signatures its modification policy requires
+	unsignedFile := filepath.Join(dir, "unsigned.tx")
+	assert.NoError(t, ioutil.WriteFile(unsignedFile, utils.MarshalOrPanic(makeConfigUpdateTx("mychannel")), 0644))
+
+	cmd := checkUpdateCmd(nil)
+	AddFlags(cmd)
+
+	cmd.SetArgs([]string{"-b", common.UndefinedParamValue, "-f", unsignedFile})
+	assert.Error(t, cmd.Execute(), "checkupdate should have failed without config block")
+
+	cmd.SetArgs([]string{"-b", blockFile, "-f", ""})
+	assert.Error(t, cmd.Execute(), "checkupdate should have failed without config update")
+
+	cmd.SetArgs([]string{"-b", blockFile, "-f", filepath.Join(dir, "missing.tx")})
+	assert.Error(t, cmd.Execute(), "checkupdate should have failed with a missing config update file")
+
+	cmd.SetArgs([]string{"-b", blockFile, "-f", otherFile})
+	assert.Error(t, cmd.Execute(), "checkupdate should have failed with an update of another channel")
+
+	cmd.SetArgs([]string{"-b", blockFile, "-f", unsignedFile})
+	assert.Error(t, cmd.Execute(), "checkupdate should have failed with an update not satisfying the modification policy")
+}
@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/scc/qscc"
+	"github.com/hyperledger/fabric/peer/common"
+	common2 "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+const (
+	getInfoOutputJSON = "json"
+	getInfoOutputText = "text"
+)
+
+// chainInfo is the output of getinfo, with the hashes hex encoded
+type chainInfo struct {
+	Height            uint64 `json:"height"`
+	CurrentBlockHash  string `json:"currentBlockHash"`
+	PreviousBlockHash string `json:"previousBlockHash"`
+}
+
+func (cc *endorserClient) getBlockChainInfo() (*common2.BlockchainInfo, error) {
+	var err error
+
+	invocation := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value["GOLANG"]),
+			ChaincodeId: &pb.ChaincodeID{Name: "qscc"},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(qscc.GetChainInfo), []byte(chainID)}},
+		},
+	}
+
+	var prop *pb.Proposal
+	c, _ := cc.cf.Signer.Serialize()
+	prop, _, err = utils.CreateProposalFromCIS(common2.HeaderType_ENDORSER_TRANSACTION, chainID, invocation, c)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot create proposal, due to %s", err)
+	}
+
+	var signedProp *pb.SignedProposal
+	signedProp, err = utils.GetSignedProposal(prop, cc.cf.Signer)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot create signed proposal, due to %s", err)
+	}
+
+	proposalResp, err := cc.cf.EndorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed sending proposal, got %s", err)
+	}
+
+	if proposalResp.Response == nil {
+		return nil, errors.New("Received bad response, no response")
+	}
+	if proposalResp.Response.Status != 200 {
+		return nil, fmt.Errorf("Received bad response, status %d: %s", proposalResp.Response.Status, proposalResp.Response.Message)
+	}
+
+	blockChainInfo := &common2.BlockchainInfo{}
+	err = proto.Unmarshal(proposalResp.Response.Payload, blockChainInfo)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read chain info response, %s", err)
+	}
+
+	return blockChainInfo, nil
+}
+
+func getinfoCmd(cf *ChannelCmdFactory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "getinfo",
+		Short: "Get blockchain information of a specified channel.",
+		Long:  "Get the height, current block hash and previous block hash of a channel the peer has joined, as JSON by default.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getinfo(cf)
+		},
+	}
+}
+
+func getinfo(cf *ChannelCmdFactory) error {
+	if chainID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+	if getInfoOutput != getInfoOutputJSON && getInfoOutput != getInfoOutputText {
+		return fmt.Errorf("Invalid output format %s, expected %s or %s", getInfoOutput, getInfoOutputJSON, getInfoOutputText)
+	}
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(true)
+		if err != nil {
+			return err
+		}
+	}
+
+	client := &endorserClient{cf}
+
+	blockChainInfo, err := client.getBlockChainInfo()
+	if err != nil {
+		return err
+	}
+
+	return printChainInfo(os.Stdout, blockChainInfo, getInfoOutput)
+}
+
+// printChainInfo writes the blockchain information in the given format
+func printChainInfo(w io.Writer, blockChainInfo *common2.BlockchainInfo, format string) error {
+	info := &chainInfo{
+		Height:            blockChainInfo.Height,
+		CurrentBlockHash:  hex.EncodeToString(blockChainInfo.CurrentBlockHash),
+		PreviousBlockHash: hex.EncodeToString(blockChainInfo.PreviousBlockHash),
+	}
+
+	if format == getInfoOutputText {
+		_, err := fmt.Fprintf(w, "Height: %d\nCurrent block hash: %s\nPrevious block hash: %s\n", info.Height, info.CurrentBlockHash, info.PreviousBlockHash)
+		return err
+	}
+
+	out, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", out)
+	return err
+}
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/peer/common"
+	common2 "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChannelInfo(t *testing.T) {
+	InitMSP()
+
+	mockBlockchainInfo := &common2.BlockchainInfo{
+		Height:            1,
+		CurrentBlockHash:  []byte("CurrentBlockHash"),
+		PreviousBlockHash: []byte("PreviousBlockHash"),
+	}
+	mockPayload, err := proto.Marshal(mockBlockchainInfo)
+	assert.NoError(t, err)
+
+	mockResponse := &pb.ProposalResponse{
+		Response: &pb.Response{
+			Status:  200,
+			Payload: mockPayload,
+		},
+		Endorsement: &pb.Endorsement{},
+	}
+
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err)
+
+	mockCF := &ChannelCmdFactory{
+		EndorserClient:   common.GetMockEndorserClient(mockResponse, nil),
+		BroadcastFactory: mockBroadcastClientFactory,
+		Signer:           signer,
+	}
+
+	cmd := getinfoCmd(mockCF)
+	AddFlags(cmd)
+
+	cmd.SetArgs([]string{"-c", "mychannel"})
+	assert.NoError(t, cmd.Execute())
+
+	cmd.SetArgs([]string{"-c", "mychannel", "-O", "yaml"})
+	assert.Error(t, cmd.Execute(), "getinfo should have failed with an invalid output format")
+
+	cmd.SetArgs([]string{"-c", common.UndefinedParamValue, "-O", getInfoOutputJSON})
+	assert.Error(t, cmd.Execute(), "getinfo should have failed without channel ID")
+}
+
+func TestPrintChainInfo(t *testing.T) {
+	info := &common2.BlockchainInfo{Height: 12, CurrentBlockHash: []byte{0xca, 0xfe}, PreviousBlockHash: []byte{0xbe, 0xef}}
+
+	var out bytes.Buffer
+	assert.NoError(t, printChainInfo(&out, info, getInfoOutputJSON))
+	parsed := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &parsed))
+	assert.Equal(t, map[string]interface{}{"height": float64(12), "currentBlockHash": "cafe", "previousBlockHash": "beef"}, parsed)
+
+	out.Reset()
+	assert.NoError(t, printChainInfo(&out, info, getInfoOutputText))
+	assert.Equal(t, "Height: 12\nCurrent block hash: cafe\nPrevious block hash: beef\n", out.String())
+}
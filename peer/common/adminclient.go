@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hyperledger/fabric/core"
+	"github.com/hyperledger/fabric/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// signingAdminClient is an AdminClient signing every request with the
+// enrollment certificate of the signer, for peers enforcing access control
+// on the admin service
+type signingAdminClient struct {
+	client pb.AdminClient
+	signer msp.SigningIdentity
+}
+
+func (c *signingAdminClient) GetStatus(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*pb.ServerStatus, error) {
+	ctx, err := core.SignAdminRequest(ctx, core.GetStatusOp, in, c.signer)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.GetStatus(ctx, in, opts...)
+}
+
+func (c *signingAdminClient) StartServer(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*pb.ServerStatus, error) {
+	ctx, err := core.SignAdminRequest(ctx, core.StartServerOp, in, c.signer)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.StartServer(ctx, in, opts...)
+}
+
+func (c *signingAdminClient) StopServer(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*pb.ServerStatus, error) {
+	ctx, err := core.SignAdminRequest(ctx, core.StopServerOp, in, c.signer)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.StopServer(ctx, in, opts...)
+}
+
+func (c *signingAdminClient) GetModuleLogLevel(ctx context.Context, in *pb.LogLevelRequest, opts ...grpc.CallOption) (*pb.LogLevelResponse, error) {
+	ctx, err := core.SignAdminRequest(ctx, core.GetModuleLogLevelOp, in, c.signer)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.GetModuleLogLevel(ctx, in, opts...)
+}
+
+func (c *signingAdminClient) SetModuleLogLevel(ctx context.Context, in *pb.LogLevelRequest, opts ...grpc.CallOption) (*pb.LogLevelResponse, error) {
+	ctx, err := core.SignAdminRequest(ctx, core.SetModuleLogLevelOp, in, c.signer)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.SetModuleLogLevel(ctx, in, opts...)
+}
+
+func (c *signingAdminClient) RevertLogLevels(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*empty.Empty, error) {
+	ctx, err := core.SignAdminRequest(ctx, core.RevertLogLevelsOp, in, c.signer)
+	if err != nil {
+		return nil, err
+	}
+	return c.client.RevertLogLevels(ctx, in, opts...)
+}
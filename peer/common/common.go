@@ -18,18 +18,28 @@ package common
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/hyperledger/fabric/bccsp/factory"
+	channelconfig "github.com/hyperledger/fabric/common/config"
+	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/errors"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/viperutil"
+	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/scc/cscc"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/enrollment"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	pcommon "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
 	logging "github.com/op/go-logging"
 	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
 )
 
 // UndefinedParamValue defines what undefined parameters in the command line will initialise to
@@ -56,6 +66,18 @@ func InitCrypto(mspMgrConfigDir string, localMSPID string) error {
 		return fmt.Errorf("Could not parse YAML config [%s]", err)
 	}
 
+	// Enroll with the Fabric CA, if configured, before loading the MSP
+	enrollmentConfig := &enrollment.Config{}
+	err = viperutil.EnhancedExactUnmarshalKey("peer.enrollment", enrollmentConfig)
+	if err != nil {
+		return fmt.Errorf("Could not parse the enrollment config [%s]", err)
+	}
+	enrollmentConfig.TranslatePaths(filepath.Dir(viper.ConfigFileUsed()))
+	err = enrollment.Enroll(enrollmentConfig, mspMgrConfigDir, bccspConfig, config.GetPath("peer.tls.cert.file"), config.GetPath("peer.tls.key.file"))
+	if err != nil {
+		return fmt.Errorf("Could not enroll with the Fabric CA: %s", err)
+	}
+
 	err = mspmgmt.LoadLocalMsp(mspMgrConfigDir, bccspConfig, localMSPID)
 	if err != nil {
 		return fmt.Errorf("Fatal error when setting up MSP from directory %s: err %s\n", mspMgrConfigDir, err)
@@ -75,17 +97,102 @@ func GetEndorserClient() (pb.EndorserClient, error) {
 	return endorserClient, nil
 }
 
-// GetAdminClient returns a new admin client connection for this peer
+// GetEndorserClientWithAddress returns a new endorser client connection for
+// the peer at peerAddress. If TLS is enabled, the TLS certificate of the peer
+// must be issued by the root certificate in tlsRootCertFile, or by the
+// configured one if tlsRootCertFile is empty
+func GetEndorserClientWithAddress(peerAddress string, tlsRootCertFile string) (pb.EndorserClient, error) {
+	var clientConn *grpc.ClientConn
+	var err error
+	if comm.TLSEnabled() {
+		if tlsRootCertFile == "" {
+			tlsRootCertFile = config.GetPath("peer.tls.rootcert.file")
+		}
+		clientConn, err = comm.NewClientConnectionWithAddress(peerAddress, true, true, comm.InitTLSForPeerWithRootCert(tlsRootCertFile))
+	} else {
+		clientConn, err = comm.NewClientConnectionWithAddress(peerAddress, true, false, nil)
+	}
+	if err != nil {
+		err = errors.ErrorWithCallstack("PER", "404", "Error trying to connect to peer %s", peerAddress).WrapError(err)
+		return nil, err
+	}
+	return pb.NewEndorserClient(clientConn), nil
+}
+
+// GetAdminClient returns a new admin client connection for this peer.
+// The requests of the client are signed with the default signing identity.
 func GetAdminClient() (pb.AdminClient, error) {
+	signer, err := GetDefaultSigner()
+	if err != nil {
+		return nil, err
+	}
 	clientConn, err := peer.NewPeerClientConnection()
 	if err != nil {
 		err = errors.ErrorWithCallstack("PER", "404", "Error trying to connect to local peer").WrapError(err)
 		return nil, err
 	}
-	adminClient := pb.NewAdminClient(clientConn)
+	adminClient := &signingAdminClient{client: pb.NewAdminClient(clientConn), signer: signer}
 	return adminClient, nil
 }
 
+// GetOrdererEndpointOfChain returns the orderer endpoints of the chain the
+// organization of the signer should connect to, as found in the latest config
+// block of the chain held by the peer
+func GetOrdererEndpointOfChain(chainID string, signer msp.SigningIdentity, endorserClient pb.EndorserClient) ([]string, error) {
+	invocation := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value["GOLANG"]),
+			ChaincodeId: &pb.ChaincodeID{Name: "cscc"},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(cscc.GetConfigBlock), []byte(chainID)}},
+		},
+	}
+
+	creator, err := signer.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("Error serializing identity for signer: %s", err)
+	}
+
+	prop, _, err := putils.CreateProposalFromCIS(pcommon.HeaderType_CONFIG, "", invocation, creator)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating GetConfigBlock proposal: %s", err)
+	}
+
+	signedProp, err := putils.GetSignedProposal(prop, signer)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating signed GetConfigBlock proposal: %s", err)
+	}
+
+	proposalResp, err := endorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return nil, fmt.Errorf("Error endorsing GetConfigBlock: %s", err)
+	}
+
+	if proposalResp == nil || proposalResp.Response == nil {
+		return nil, fmt.Errorf("Received nil proposal response for GetConfigBlock")
+	}
+
+	if proposalResp.Response.Status != 0 && proposalResp.Response.Status != 200 {
+		return nil, fmt.Errorf("Error bad proposal response %d: %s", proposalResp.Response.Status, proposalResp.Response.Message)
+	}
+
+	configBlock, err := putils.GetBlockFromBlockBytes(proposalResp.Response.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("Error unmarshaling config block: %s", err)
+	}
+
+	envelopeConfig, err := putils.ExtractEnvelope(configBlock, 0)
+	if err != nil {
+		return nil, fmt.Errorf("Error extracting config envelope: %s", err)
+	}
+
+	configtxManager, err := configtx.NewManagerImpl(envelopeConfig, configtx.NewInitializer(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error loading config block: %s", err)
+	}
+
+	return channelconfig.OrdererAddressesOfOrg(signer.GetMSPIdentifier(), configtxManager.ChannelConfig(), configtxManager.OrdererConfig()), nil
+}
+
 // SetLogLevelFromViper sets the log level for 'module' logger to the value in
 // core.yaml
 func SetLogLevelFromViper(module string) error {
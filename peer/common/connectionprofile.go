@@ -0,0 +1,234 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ConnectionProfile describes the endpoints of a network, and which of them
+// serve each channel, so that the CLI can target peers and orderers by name
+// instead of by address. Relative paths are relative to the directory of the
+// profile. For example:
+//
+//	orderers:
+//	  orderer0:
+//	    url: orderer0.example.com:7050
+//	    tlsCACerts:
+//	      path: orderer/tls/ca.pem
+//	peers:
+//	  peer0.org1:
+//	    url: peer0.org1.example.com:7051
+//	    tlsCACerts:
+//	      path: org1/tls/ca.pem
+//	organizations:
+//	  Org1:
+//	    mspid: Org1MSP
+//	    peers: [peer0.org1]
+//	channels:
+//	  mychannel:
+//	    orderers: [orderer0]
+//	    peers: [peer0.org1]
+type ConnectionProfile struct {
+	Name          string                         `yaml:"name"`
+	Orderers      map[string]*EndpointConfig     `yaml:"orderers"`
+	Peers         map[string]*EndpointConfig     `yaml:"peers"`
+	Organizations map[string]*OrganizationConfig `yaml:"organizations"`
+	Channels      map[string]*ChannelConfig      `yaml:"channels"`
+}
+
+// EndpointConfig is the endpoint of a peer or orderer
+type EndpointConfig struct {
+	// Name is the name of the endpoint in the profile, set when loading it
+	Name       string           `yaml:"-"`
+	URL        string           `yaml:"url"`
+	TLSCACerts TLSCACertsConfig `yaml:"tlsCACerts"`
+}
+
+// TLSEnabled returns whether TLS is used with the endpoint
+func (ec *EndpointConfig) TLSEnabled() bool {
+	return ec.TLSCACerts.Path != ""
+}
+
+// TLSCACertsConfig locates the root certificate of the TLS certificate of an
+// endpoint. TLS is used with the endpoints that have one
+type TLSCACertsConfig struct {
+	Path string `yaml:"path"`
+}
+
+// OrganizationConfig is an organization and the names of its peers
+type OrganizationConfig struct {
+	MSPID string   `yaml:"mspid"`
+	Peers []string `yaml:"peers"`
+}
+
+// ChannelConfig holds the names of the orderers and peers serving a channel
+type ChannelConfig struct {
+	Orderers []string `yaml:"orderers"`
+	Peers    []string `yaml:"peers"`
+}
+
+// LoadConnectionProfile reads and checks the connection profile at path
+func LoadConnectionProfile(path string) (*ConnectionProfile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading connection profile %s: %s", path, err)
+	}
+
+	profile := &ConnectionProfile{}
+	if err = yaml.Unmarshal(data, profile); err != nil {
+		return nil, fmt.Errorf("Error parsing connection profile %s: %s", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	for _, endpoints := range []map[string]*EndpointConfig{profile.Orderers, profile.Peers} {
+		for name, endpoint := range endpoints {
+			if endpoint == nil || endpoint.URL == "" {
+				return nil, fmt.Errorf("Endpoint %s of connection profile %s has no url", name, path)
+			}
+			endpoint.Name = name
+			if endpoint.TLSCACerts.Path != "" && !filepath.IsAbs(endpoint.TLSCACerts.Path) {
+				endpoint.TLSCACerts.Path = filepath.Join(dir, endpoint.TLSCACerts.Path)
+			}
+		}
+	}
+	for name, org := range profile.Organizations {
+		if org == nil {
+			return nil, fmt.Errorf("Organization %s of connection profile %s is empty", name, path)
+		}
+		for _, peer := range org.Peers {
+			if _, ok := profile.Peers[peer]; !ok {
+				return nil, fmt.Errorf("Organization %s of connection profile %s has unknown peer %s", name, path, peer)
+			}
+		}
+	}
+	for name, channel := range profile.Channels {
+		if channel == nil {
+			return nil, fmt.Errorf("Channel %s of connection profile %s is empty", name, path)
+		}
+		for _, orderer := range channel.Orderers {
+			if _, ok := profile.Orderers[orderer]; !ok {
+				return nil, fmt.Errorf("Channel %s of connection profile %s has unknown orderer %s", name, path, orderer)
+			}
+		}
+		for _, peer := range channel.Peers {
+			if _, ok := profile.Peers[peer]; !ok {
+				return nil, fmt.Errorf("Channel %s of connection profile %s has unknown peer %s", name, path, peer)
+			}
+		}
+	}
+
+	return profile, nil
+}
+
+// ChannelOrderer returns the first orderer of the channel, or the first one,
+// by name, of the profile if the channel lists none
+func (cp *ConnectionProfile) ChannelOrderer(channelID string) (*EndpointConfig, error) {
+	if channel, ok := cp.Channels[channelID]; ok && len(channel.Orderers) != 0 {
+		return cp.Orderers[channel.Orderers[0]], nil
+	}
+
+	var first string
+	for name := range cp.Orderers {
+		if first == "" || name < first {
+			first = name
+		}
+	}
+	if first == "" {
+		return nil, fmt.Errorf("No orderer in the connection profile for channel %s", channelID)
+	}
+	return cp.Orderers[first], nil
+}
+
+// ChannelPeers returns the peers of the channel designated by targets. Each
+// target is either the name of a peer, or the name of an organization,
+// designating its peers serving the channel, or "org/peer" for one of the
+// peers of the organization. Without targets, all the peers of the channel
+// are returned. Peers are returned once, in the order of the targets
+func (cp *ConnectionProfile) ChannelPeers(channelID string, targets []string) ([]*EndpointConfig, error) {
+	channel, ok := cp.Channels[channelID]
+	if !ok {
+		return nil, fmt.Errorf("Channel %s is not in the connection profile", channelID)
+	}
+	inChannel := map[string]bool{}
+	for _, peer := range channel.Peers {
+		inChannel[peer] = true
+	}
+
+	if len(targets) == 0 {
+		targets = channel.Peers
+	}
+
+	var peers []*EndpointConfig
+	selected := map[string]bool{}
+	selectPeer := func(name string) {
+		if !selected[name] {
+			selected[name] = true
+			peers = append(peers, cp.Peers[name])
+		}
+	}
+
+	for _, target := range targets {
+		if i := strings.Index(target, "/"); i >= 0 {
+			org, ok := cp.Organizations[target[:i]]
+			if !ok {
+				return nil, fmt.Errorf("Organization %s is not in the connection profile", target[:i])
+			}
+			if !contains(org.Peers, target[i+1:]) {
+				return nil, fmt.Errorf("Peer %s is not a peer of organization %s", target[i+1:], target[:i])
+			}
+			if !inChannel[target[i+1:]] {
+				return nil, fmt.Errorf("Peer %s is not a peer of channel %s", target[i+1:], channelID)
+			}
+			selectPeer(target[i+1:])
+		} else if org, ok := cp.Organizations[target]; ok {
+			found := false
+			for _, peer := range org.Peers {
+				if inChannel[peer] {
+					selectPeer(peer)
+					found = true
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("Organization %s has no peer in channel %s", target, channelID)
+			}
+		} else if inChannel[target] {
+			selectPeer(target)
+		} else {
+			return nil, fmt.Errorf("%s is neither an organization nor a peer of channel %s in the connection profile", target, channelID)
+		}
+	}
+
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("No peer in the connection profile for channel %s", channelID)
+	}
+	return peers, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
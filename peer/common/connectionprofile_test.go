@@ -0,0 +1,130 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testProfile = `
+name: testnetwork
+orderers:
+  orderer0:
+    url: orderer0:7050
+    tlsCACerts:
+      path: orderer/ca.pem
+peers:
+  peer0.org1:
+    url: peer0.org1:7051
+    tlsCACerts:
+      path: /abs/org1/ca.pem
+  peer1.org1:
+    url: peer1.org1:7051
+  peer0.org2:
+    url: peer0.org2:7051
+organizations:
+  Org1:
+    mspid: Org1MSP
+    peers: [peer0.org1, peer1.org1]
+  Org2:
+    mspid: Org2MSP
+    peers: [peer0.org2]
+channels:
+  mychannel:
+    orderers: [orderer0]
+    peers: [peer0.org1, peer0.org2]
+`
+
+func writeProfile(t *testing.T, dir string, profile string) string {
+	path := filepath.Join(dir, "profile.yaml")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(profile), 0644))
+	return path
+}
+
+func names(endpoints []*EndpointConfig) []string {
+	var result []string
+	for _, e := range endpoints {
+		result = append(result, e.Name)
+	}
+	return result
+}
+
+func TestConnectionProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "connectionprofile")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	profile, err := LoadConnectionProfile(writeProfile(t, dir, testProfile))
+	assert.NoError(t, err)
+
+	orderer, err := profile.ChannelOrderer("mychannel")
+	assert.NoError(t, err)
+	assert.Equal(t, "orderer0:7050", orderer.URL)
+	assert.True(t, orderer.TLSEnabled())
+	assert.Equal(t, filepath.Join(dir, "orderer/ca.pem"), orderer.TLSCACerts.Path)
+
+	// Channels without orderers fall back to the orderers of the profile
+	orderer, err = profile.ChannelOrderer("otherchannel")
+	assert.NoError(t, err)
+	assert.Equal(t, "orderer0", orderer.Name)
+
+	peers, err := profile.ChannelPeers("mychannel", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peer0.org1", "peer0.org2"}, names(peers))
+	assert.Equal(t, "/abs/org1/ca.pem", peers[0].TLSCACerts.Path)
+	assert.False(t, peers[1].TLSEnabled())
+
+	peers, err = profile.ChannelPeers("mychannel", []string{"Org2", "Org1", "peer0.org2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peer0.org2", "peer0.org1"}, names(peers))
+
+	peers, err = profile.ChannelPeers("mychannel", []string{"Org1/peer0.org1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peer0.org1"}, names(peers))
+
+	for _, targets := range [][]string{{"peer1.org1"}, {"Org1/peer1.org1"}, {"Org2/peer0.org1"}, {"Org3"}, {"Org3/peer0.org1"}} {
+		_, err = profile.ChannelPeers("mychannel", targets)
+		assert.Error(t, err, "targets %v", targets)
+	}
+	_, err = profile.ChannelPeers("otherchannel", nil)
+	assert.Error(t, err)
+}
+
+func TestInvalidConnectionProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "connectionprofile")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, profile := range []string{
+		"peers: [",
+		"peers:\n  peer0:\n    tlsCACerts:\n      path: ca.pem\n",
+		"organizations:\n  Org1:\n    peers: [peer0]\n",
+		"channels:\n  mychannel:\n    orderers: [orderer0]\n",
+		"channels:\n  mychannel:\n",
+	} {
+		_, err = LoadConnectionProfile(writeProfile(t, dir, profile))
+		assert.Error(t, err, "profile %s", profile)
+	}
+
+	_, err = LoadConnectionProfile(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+}
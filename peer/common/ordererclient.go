@@ -82,6 +82,9 @@ func (s *broadcastClient) getAck() error {
 		return err
 	}
 	if msg.Status != cb.Status_SUCCESS {
+		if msg.Info != "" {
+			return fmt.Errorf("Got unexpected status: %v (%s)", msg.Status, msg.Info)
+		}
 		return fmt.Errorf("Got unexpected status: %v", msg.Status)
 	}
 	return nil
@@ -50,6 +50,9 @@ type mspMessageCryptoService struct {
 	channelPolicyManagerGetter policies.ChannelPolicyManagerGetter
 	localSigner                crypto.LocalSigner
 	deserializer               mgmt.DeserializersManager
+	// blockAttestations is the number of distinct ordering nodes which must
+	// have signed a block, on top of its BlockValidation policy
+	blockAttestations int
 }
 
 // New creates a new instance of mspMessageCryptoService
@@ -59,7 +62,21 @@ type mspMessageCryptoService struct {
 // 2. an instance of crypto.LocalSigner
 // 3. an identity deserializer manager
 func New(channelPolicyManagerGetter policies.ChannelPolicyManagerGetter, localSigner crypto.LocalSigner, deserializer mgmt.DeserializersManager) api.MessageCryptoService {
-	return &mspMessageCryptoService{channelPolicyManagerGetter: channelPolicyManagerGetter, localSigner: localSigner, deserializer: deserializer}
+	return NewWithBlockAttestations(channelPolicyManagerGetter, localSigner, deserializer, 0)
+}
+
+// NewWithBlockAttestations creates a new instance of mspMessageCryptoService
+// like New, which additionally considers blocks valid only once they carry
+// the signatures of blockAttestations distinct ordering nodes, e.g. f+1 for
+// a byzantine fault tolerant ordering service tolerating f faulty nodes, so
+// that a single faulty ordering node cannot make the peer accept a block
+func NewWithBlockAttestations(channelPolicyManagerGetter policies.ChannelPolicyManagerGetter, localSigner crypto.LocalSigner, deserializer mgmt.DeserializersManager, blockAttestations int) api.MessageCryptoService {
+	return &mspMessageCryptoService{
+		channelPolicyManagerGetter: channelPolicyManagerGetter,
+		localSigner:                localSigner,
+		deserializer:               deserializer,
+		blockAttestations:          blockAttestations,
+	}
 }
 
 // ValidateIdentity validates the identity of a remote peer.
@@ -167,13 +184,34 @@ func (s *mspMessageCryptoService) VerifyBlock(chainID common.ChainID, signedBloc
 	// ok is true if it was the policy requested, or false if it is the default policy
 	logger.Debugf("Got block validation policy for channel [%s] with flag [%s]", channelID, ok)
 
-	// - Prepare SignedData
+	// Get the deserializer of the identities of the channel
+	deserializer, ok := s.deserializer.GetChannelDeserializers()[channelID]
+	if !ok {
+		return fmt.Errorf("Could not acquire identity deserializer for channel %s", channelID)
+	}
+
+	// - Prepare SignedData, counting a single signature per creator so that a policy
+	// requiring the signatures of several ordering nodes cannot be satisfied by one.
+	// The creators are compared once deserialized, an identity having several
+	// serialized forms
 	signatureSet := []*pcommon.SignedData{}
+	creators := make(map[msp.IdentityIdentifier]struct{})
 	for _, metadataSignature := range metadata.Signatures {
 		shdr, err := utils.GetSignatureHeader(metadataSignature.SignatureHeader)
 		if err != nil {
 			return fmt.Errorf("Failed unmarshalling signature header for block with id [%d] on channel [%s]: [%s]", block.Header.Number, chainID, err)
 		}
+		identity, err := deserializer.DeserializeIdentity(shdr.Creator)
+		if err != nil {
+			logger.Warningf("Ignoring signature of an invalid creator for block with id [%d] on channel [%s]: [%s]", block.Header.Number, chainID, err)
+			continue
+		}
+		creator := *identity.GetIdentifier()
+		if _, signed := creators[creator]; signed {
+			logger.Warningf("Ignoring duplicate signature of the same creator for block with id [%d] on channel [%s]", block.Header.Number, chainID)
+			continue
+		}
+		creators[creator] = struct{}{}
 		signatureSet = append(
 			signatureSet,
 			&pcommon.SignedData{
@@ -185,7 +223,50 @@ func (s *mspMessageCryptoService) VerifyBlock(chainID common.ChainID, signedBloc
 	}
 
 	// - Evaluate policy
-	return policy.Evaluate(signatureSet)
+	if err := policy.Evaluate(signatureSet); err != nil {
+		return err
+	}
+
+	// - Count the attestations
+	if s.blockAttestations > 1 {
+		return verifyAttestations(cpm, deserializer, signatureSet, s.blockAttestations, block.Header.Number, chainID)
+	}
+	return nil
+}
+
+// verifyAttestations checks that at least quorum of the signatures of a
+// block, which come from distinct identities, are valid signatures of ordering
+// nodes, that is satisfy the orderer writers policy of the channel on their own
+func verifyAttestations(cpm policies.Manager, deserializer msp.IdentityDeserializer, signatureSet []*pcommon.SignedData, quorum int, blockNumber uint64, chainID common.ChainID) error {
+	policy, ok := cpm.GetPolicy(policies.ChannelOrdererWriters)
+	if !ok {
+		return fmt.Errorf("No orderer writers policy to count the attestations of block with id [%d] on channel [%s]", blockNumber, chainID)
+	}
+
+	// An identity attests a block once, however many times and in however
+	// many serialized forms it signed it
+	attesters := make(map[msp.IdentityIdentifier]struct{})
+	for _, signedData := range signatureSet {
+		identity, err := deserializer.DeserializeIdentity(signedData.Identity)
+		if err != nil {
+			logger.Debugf("Not counting signature of an invalid creator as attestation of block with id [%d] on channel [%s]: [%s]", blockNumber, chainID, err)
+			continue
+		}
+		attester := *identity.GetIdentifier()
+		if _, attested := attesters[attester]; attested {
+			continue
+		}
+		if err := policy.Evaluate([]*pcommon.SignedData{signedData}); err != nil {
+			logger.Debugf("Not counting signature as attestation of block with id [%d] on channel [%s]: [%s]", blockNumber, chainID, err)
+			continue
+		}
+		attesters[attester] = struct{}{}
+	}
+
+	if len(attesters) < quorum {
+		return fmt.Errorf("Block with id [%d] on channel [%s] carries %d attestations of ordering nodes, %d are required", blockNumber, chainID, len(attesters), quorum)
+	}
+	return nil
 }
 
 // Sign signs msg with this peer's signing key and outputs
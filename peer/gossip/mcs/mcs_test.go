@@ -147,6 +147,7 @@ func TestVerifyBlock(t *testing.T) {
 			channelDeserializers: map[string]msp.IdentityDeserializer{
 				"A": &mockIdentityDeserializer{[]byte("Bob"), []byte("msg2")},
 				"B": &mockIdentityDeserializer{[]byte("Charlie"), []byte("msg3")},
+				"C": &mockIdentityDeserializer{[]byte("Alice"), []byte("msg1")},
 			},
 		},
 	)
@@ -174,6 +175,149 @@ func TestVerifyBlock(t *testing.T) {
 	assert.Error(t, msgCryptoService.VerifyBlock([]byte("C"), blockRaw))
 }
 
+type signatureCountingPolicy struct {
+	signatures int
+}
+
+func (p *signatureCountingPolicy) Evaluate(signatureSet []*common.SignedData) error {
+	p.signatures = len(signatureSet)
+	if len(signatureSet) < 2 {
+		return fmt.Errorf("Expected 2 signatures, got %d", len(signatureSet))
+	}
+	return nil
+}
+
+func TestVerifyBlockDuplicateSignatures(t *testing.T) {
+	aliceSigner := &mockscrypto.LocalSigner{Identity: []byte("Alice")}
+	policy := &signatureCountingPolicy{}
+	policyManagerGetter := &mockChannelPolicyManagerGetter2{
+		map[string]policies.Manager{
+			"C": &mockChannelPolicyManager{policy},
+		},
+	}
+	msgCryptoService := New(
+		policyManagerGetter,
+		aliceSigner,
+		&mockDeserializersManager{
+			localDeserializer: &mockIdentityDeserializer{[]byte("Alice"), []byte("msg1")},
+			channelDeserializers: map[string]msp.IdentityDeserializer{
+				// Alice's identity serialized in two different ways
+				"C": &mockIdentitiesDeserializer{map[string]string{"Alice": "alice", "Alice\n": "alice", "Bob": "bob"}},
+			},
+		},
+	)
+
+	// - Prepare a block carrying the signature of Alice and the given other signatures
+	signedBlock := func(others ...*common.MetadataSignature) []byte {
+		blockRaw, _ := mockBlock(t, "C", aliceSigner, nil)
+		block := &common.Block{}
+		assert.NoError(t, proto.Unmarshal(blockRaw, block))
+		metadata, err := utils.GetMetadataFromBlock(block, common.BlockMetadataIndex_SIGNATURES)
+		assert.NoError(t, err)
+		for _, other := range others {
+			if other == nil {
+				other = metadata.Signatures[0]
+			}
+			metadata.Signatures = append(metadata.Signatures, other)
+		}
+		block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(metadata)
+		return utils.MarshalOrPanic(block)
+	}
+	signatureOf := func(creator string) *common.MetadataSignature {
+		shdr, err := (&mockscrypto.LocalSigner{Identity: []byte(creator)}).NewSignatureHeader()
+		assert.NoError(t, err)
+		return &common.MetadataSignature{SignatureHeader: utils.MarshalOrPanic(shdr)}
+	}
+
+	// - Verify block, the duplicate signature should not count
+	assert.Error(t, msgCryptoService.VerifyBlock([]byte("C"), signedBlock(nil)))
+	assert.Equal(t, 1, policy.signatures, "The duplicate signature should have been ignored")
+
+	// - Nor should the signature of the same identity serialized differently
+	assert.Error(t, msgCryptoService.VerifyBlock([]byte("C"), signedBlock(signatureOf("Alice\n"))))
+	assert.Equal(t, 1, policy.signatures, "The signature of the same identity should have been ignored")
+
+	// - Nor should the signature of an unknown creator
+	assert.Error(t, msgCryptoService.VerifyBlock([]byte("C"), signedBlock(signatureOf("Mallory"))))
+	assert.Equal(t, 1, policy.signatures, "The signature of an invalid creator should have been ignored")
+
+	assert.NoError(t, msgCryptoService.VerifyBlock([]byte("C"), signedBlock(signatureOf("Bob"))))
+	assert.Equal(t, 2, policy.signatures)
+}
+
+// creatorsPolicy is satisfied by the signatures of any of its creators
+type creatorsPolicy struct {
+	creators []string
+}
+
+func (p *creatorsPolicy) Evaluate(signatureSet []*common.SignedData) error {
+	for _, signedData := range signatureSet {
+		for _, creator := range p.creators {
+			if string(signedData.Identity) == creator {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("No signature of %v", p.creators)
+}
+
+func TestVerifyBlockAttestations(t *testing.T) {
+	aliceSigner := &mockscrypto.LocalSigner{Identity: []byte("Alice")}
+	policyManagerGetter := &mockChannelPolicyManagerGetter2{
+		map[string]policies.Manager{
+			"C": &mockChannelPolicyManager{&creatorsPolicy{[]string{"Alice", "Bob"}}},
+		},
+	}
+	deserializersManager := &mockDeserializersManager{
+		localDeserializer: &mockIdentityDeserializer{[]byte("Alice"), []byte("msg1")},
+		channelDeserializers: map[string]msp.IdentityDeserializer{
+			"C": &mockIdentitiesDeserializer{map[string]string{"Alice": "alice", "Bob": "bob", "Mallory": "mallory"}},
+		},
+	}
+
+	// - Prepare a block signed by Alice and the given other signers
+	signedBlock := func(others ...string) []byte {
+		blockRaw, _ := mockBlock(t, "C", aliceSigner, nil)
+		block := &common.Block{}
+		assert.NoError(t, proto.Unmarshal(blockRaw, block))
+		metadata, err := utils.GetMetadataFromBlock(block, common.BlockMetadataIndex_SIGNATURES)
+		assert.NoError(t, err)
+		for _, other := range others {
+			shdr, err := (&mockscrypto.LocalSigner{Identity: []byte(other)}).NewSignatureHeader()
+			assert.NoError(t, err)
+			metadata.Signatures = append(metadata.Signatures, &common.MetadataSignature{SignatureHeader: utils.MarshalOrPanic(shdr)})
+		}
+		block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(metadata)
+		return utils.MarshalOrPanic(block)
+	}
+
+	msgCryptoService := New(policyManagerGetter, aliceSigner, deserializersManager)
+	assert.NoError(t, msgCryptoService.VerifyBlock([]byte("C"), signedBlock("Mallory")), "The block validation policy alone should suffice by default")
+
+	msgCryptoService = NewWithBlockAttestations(policyManagerGetter, aliceSigner, deserializersManager, 2)
+	assert.NoError(t, msgCryptoService.VerifyBlock([]byte("C"), signedBlock("Bob")))
+	err := msgCryptoService.VerifyBlock([]byte("C"), signedBlock("Mallory"))
+	if assert.Error(t, err, "The signature of Mallory should not count as an attestation") {
+		assert.Contains(t, err.Error(), "carries 1 attestations of ordering nodes, 2 are required")
+	}
+	assert.Error(t, msgCryptoService.VerifyBlock([]byte("C"), signedBlock("Alice")), "The duplicate signature of Alice should not count")
+}
+
+func TestVerifyAttestationsDistinctIdentities(t *testing.T) {
+	cpm := &mockChannelPolicyManager{&creatorsPolicy{[]string{"Alice", "Alice\n", "Bob"}}}
+	deserializer := &mockIdentitiesDeserializer{map[string]string{"Alice": "alice", "Alice\n": "alice", "Bob": "bob"}}
+	signatureSet := []*common.SignedData{{Identity: []byte("Alice")}, {Identity: []byte("Alice\n")}}
+
+	// Alice's identity serialized in two different ways attests the block once
+	err := verifyAttestations(cpm, deserializer, signatureSet, 2, 1, []byte("C"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "carries 1 attestations of ordering nodes, 2 are required")
+	}
+
+	signatureSet = append(signatureSet, &common.SignedData{Identity: []byte("Bob")})
+	assert.NoError(t, verifyAttestations(cpm, deserializer, signatureSet, 2, 1, []byte("C")))
+}
+
 func mockBlock(t *testing.T, channel string, localSigner crypto.LocalSigner, dataHash []byte) ([]byte, []byte) {
 	block := common.NewBlock(0, nil)
 
@@ -115,6 +115,29 @@ func (d *mockIdentityDeserializer) DeserializeIdentity(serializedIdentity []byte
 	return nil, errors.New("Invalid identity")
 }
 
+// mockIdentitiesDeserializer deserializes the identities it knows, given by
+// their serialized forms, into identities with the given identifiers
+type mockIdentitiesDeserializer struct {
+	identifiers map[string]string
+}
+
+func (d *mockIdentitiesDeserializer) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	if id, ok := d.identifiers[string(serializedIdentity)]; ok {
+		return &mockNamedIdentity{id: id}, nil
+	}
+
+	return nil, errors.New("Invalid identity")
+}
+
+type mockNamedIdentity struct {
+	mockIdentity
+	id string
+}
+
+func (id *mockNamedIdentity) GetIdentifier() *msp.IdentityIdentifier {
+	return &msp.IdentityIdentifier{Mspid: "mock", Id: id.id}
+}
+
 type mockIdentity struct {
 	msg []byte
 }
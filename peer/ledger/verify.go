@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/ledgeraudit"
+	"github.com/spf13/cobra"
+)
+
+func verifyCmd() *cobra.Command {
+	ledgerVerifyCmd.Flags().StringVarP(&chainID, "channelID", "c", "", "The channel ID of the ledger to verify.")
+	return ledgerVerifyCmd
+}
+
+var ledgerVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verifies the integrity of the ledger of a channel.",
+	Long: `Walks the block files of a channel and verifies the chaining of the block headers, ` +
+		`the signatures of the blocks against the config in effect at their height and the ` +
+		`index of the transactions by ID, then prints an audit report. ` +
+		`The node should be stopped while running this command.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return verify()
+	},
+}
+
+func verify() error {
+	if chainID == "" {
+		return errors.New("Must supply channel ID")
+	}
+
+	logger.Infof("Verifying the ledger of channel %s", chainID)
+	report, err := ledgeraudit.VerifyLedger(chainID)
+	if err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+
+	if !report.OK() {
+		return fmt.Errorf("found %d issues in the ledger of channel %s", len(report.Issues), chainID)
+	}
+	return nil
+}
@@ -34,7 +34,9 @@ import (
 	"github.com/hyperledger/fabric/peer/channel"
 	"github.com/hyperledger/fabric/peer/clilogging"
 	"github.com/hyperledger/fabric/peer/common"
+	"github.com/hyperledger/fabric/peer/ledger"
 	"github.com/hyperledger/fabric/peer/node"
+	"github.com/hyperledger/fabric/peer/tx"
 	"github.com/hyperledger/fabric/peer/version"
 )
 
@@ -100,6 +102,8 @@ func main() {
 	mainCmd.AddCommand(chaincode.Cmd(nil))
 	mainCmd.AddCommand(clilogging.Cmd())
 	mainCmd.AddCommand(channel.Cmd(nil))
+	mainCmd.AddCommand(ledger.Cmd())
+	mainCmd.AddCommand(tx.Cmd(nil))
 
 	runtime.GOMAXPROCS(viper.GetInt("peer.gomaxprocs"))
 
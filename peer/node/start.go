@@ -32,12 +32,15 @@ import (
 	"github.com/hyperledger/fabric/common/configtx/tool/provisional"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/common/tracing"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core"
 	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/committer"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/core/endorser"
+	"github.com/hyperledger/fabric/core/endorser/interceptor"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/scc"
@@ -50,6 +53,7 @@ import (
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/grpclog"
 )
@@ -124,6 +128,7 @@ func serve(args []string) error {
 	if err != nil {
 		logger.Fatalf("Error loading secure config for peer (%s)", err)
 	}
+	secureConfig.UnaryInterceptors, secureConfig.StreamInterceptors = peer.GetServerInterceptors()
 	peerServer, err := peer.CreatePeerServer(listenAddr, secureConfig)
 	if err != nil {
 		logger.Fatalf("Failed to create peer server (%s)", err)
@@ -147,10 +152,15 @@ func serve(args []string) error {
 	logger.Debugf("Running peer")
 
 	// Register the Admin server
-	pb.RegisterAdminServer(peerServer.Server(), core.NewAdminServer())
+	adminServer := createAdminServer()
+	pb.RegisterAdminServer(peerServer.Server(), adminServer)
 
 	// Register the Endorser server
-	serverEndorser := endorser.NewEndorserServer()
+	interceptors, err := interceptor.Load()
+	if err != nil {
+		logger.Fatalf("Failed loading the proposal interceptors: %s", err)
+	}
+	serverEndorser := endorser.NewEndorserServerWithInterceptors(interceptors)
 	pb.RegisterEndorserServer(peerServer.Server(), serverEndorser)
 
 	// Initialize gossip component
@@ -161,12 +171,12 @@ func serve(args []string) error {
 		logger.Panicf("Failed serializing self identity: %v", err)
 	}
 
-	messageCryptoService := mcs.New(
+	messageCryptoService := mcs.NewWithBlockAttestations(
 		peer.NewChannelPolicyManagerGetter(),
 		localmsp.NewSigner(),
-		mgmt.NewDeserializersManager())
+		mgmt.NewDeserializersManager(),
+		viper.GetInt("peer.deliveryclient.blockAttestations"))
 	service.InitGossipService(serializedIdentity, peerEndpoint.Address, peerServer.Server(), messageCryptoService, bootstrap...)
-	defer service.GetGossipService().Stop()
 
 	//initialize system chaincodes
 	initSysCCs()
@@ -215,6 +225,10 @@ func serve(args []string) error {
 		scc.DeploySysCCs(cid)
 	})
 
+	if chaincode.GetChain().ReapOrphansEnabled() {
+		reapOrphanChaincodes()
+	}
+
 	logger.Infof("Starting peer with ID=[%s], network ID=[%s], address=[%s]",
 		peerEndpoint.Id, viper.GetString("peer.networkId"), peerEndpoint.Address)
 
@@ -222,13 +236,32 @@ func serve(args []string) error {
 	// genesis block if needed.
 	serve := make(chan error)
 
+	// Only the first reason to stop is received from serve, the later ones
+	// are dropped once the peer is stopped
+	stopped := make(chan struct{})
+	stop := func(err error) {
+		select {
+		case serve <- err:
+		case <-stopped:
+		}
+	}
+
+	// Stopping the peer through the admin service shuts it down like a signal
+	adminServer.SetStopHandler(func() {
+		stop(nil)
+	})
+
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
 	go func() {
-		sig := <-sigs
-		fmt.Println()
-		fmt.Println(sig)
-		serve <- nil
+		select {
+		case sig := <-sigs:
+			fmt.Println()
+			fmt.Println(sig)
+			stop(nil)
+		case <-stopped:
+		}
 	}()
 
 	go func() {
@@ -238,7 +271,7 @@ func serve(args []string) error {
 		} else {
 			logger.Info("peer server exited")
 		}
-		serve <- grpcErr
+		stop(grpcErr)
 	}()
 
 	if err := writePid(config.GetPath("peer.fileSystemPath")+"/peer.pid", os.Getpid()); err != nil {
@@ -250,8 +283,11 @@ func serve(args []string) error {
 		go ehubGrpcServer.Start()
 	}
 
+	tracing.Init("peer", viper.GetString("peer.tracing.endpoint"))
+
 	// Start profiling http endpoint if enabled
 	if viper.GetBool("peer.profile.enabled") {
+		http.HandleFunc("/healthz", committer.HealthHandler)
 		go func() {
 			profileListenAddress := viper.GetString("peer.profile.listenAddress")
 			logger.Infof("Starting profiling server with listenAddress = %s", profileListenAddress)
@@ -276,8 +312,38 @@ func serve(args []string) error {
 
 	flogging.SetPeerStartupModulesMap()
 
-	// Block until grpc server exits
-	return <-serve
+	if chaincode.GetChain().WarmStartEnabled() {
+		go warmStartChaincodes()
+	}
+
+	// Block until grpc server exits or the peer is asked to stop
+	err = <-serve
+	close(stopped)
+	shutdown(peerServer, ehubGrpcServer)
+	return err
+}
+
+// shutdown stops the peer gracefully: the delivery of blocks from the ordering
+// service stops and the blocks in flight are committed, then the servers and
+// the chaincode containers are stopped so that no request uses the ledgers
+// any longer when they are closed
+func shutdown(peerServer comm.GRPCServer, ehubGrpcServer comm.GRPCServer) {
+	logger.Info("Shutting down peer")
+
+	service.GetGossipService().Stop()
+
+	if ehubGrpcServer != nil {
+		ehubGrpcServer.Stop()
+	}
+	peerServer.Stop()
+	chaincode.GetChain().StopAll(context.Background())
+
+	ledgermgmt.Close()
+
+	pidFile := config.GetPath("peer.fileSystemPath") + "/peer.pid"
+	logger.Debugf("Remove pid file %s", pidFile)
+	os.Remove(pidFile)
+	logger.Info("Peer stopped")
 }
 
 //NOTE - when we implment JOIN we will no longer pass the chainID as param
@@ -303,6 +369,52 @@ func registerChaincodeSupport(grpcServer *grpc.Server) {
 	pb.RegisterChaincodeSupportServer(grpcServer, ccSrv)
 }
 
+// warmStartChaincodes launches the containers of the chaincodes
+// instantiated on the chains the peer has joined
+func warmStartChaincodes() {
+	for _, ch := range peer.GetChannelsInfo() {
+		logger.Infof("Warm starting chaincodes of chain <%s>", ch.ChannelId)
+		if err := chaincode.GetChain().WarmStart(ch.ChannelId); err != nil {
+			logger.Warningf("Failed warm starting chaincodes of chain <%s>: %s", ch.ChannelId, err)
+		}
+	}
+}
+
+// reapOrphanChaincodes removes the chaincode containers and images the peer
+// left behind, before the chaincodes are launched again
+func reapOrphanChaincodes() {
+	var chainIDs []string
+	for _, ch := range peer.GetChannelsInfo() {
+		chainIDs = append(chainIDs, ch.ChannelId)
+	}
+	if err := chaincode.GetChain().ReapOrphans(chainIDs); err != nil {
+		logger.Warningf("Failed removing orphan chaincode containers: %s", err)
+	}
+}
+
+func createAdminServer() *core.ServerAdmin {
+	if !viper.GetBool("peer.adminService.accessControl.enabled") {
+		logger.Warning("Access control of the admin service is disabled")
+		return core.NewAdminServer()
+	}
+
+	policies := make(map[string]string)
+	for operation := range core.DefaultAdminPolicies() {
+		if policy := viper.GetString("peer.adminService.accessControl.policies." + operation); policy != "" {
+			policies[operation] = policy
+		}
+	}
+	mspID, err := mgmt.GetLocalMSP().GetIdentifier()
+	if err != nil {
+		logger.Panicf("Failed getting local MSP identifier: %s", err)
+	}
+	ac, err := core.NewAdminAccessControl(policies, mspID, mgmt.GetLocalMSP(), mgmt.NewLocalMSPPrincipalGetter())
+	if err != nil {
+		logger.Panicf("Failed creating access control of the admin service: %s", err)
+	}
+	return core.NewAdminServerWithAccessControl(ac)
+}
+
 func createEventHubServer(secureConfig comm.SecureServerConfig) (comm.GRPCServer, error) {
 	var lis net.Listener
 	var err error
@@ -316,9 +428,11 @@ func createEventHubServer(secureConfig comm.SecureServerConfig) (comm.GRPCServer
 		fmt.Println("Failed to return new GRPC server: ", err)
 		return nil, err
 	}
-	ehServer := producer.NewEventsServer(
+	ehServer := producer.NewEventsServerWithFlowControl(
 		uint(viper.GetInt("peer.events.buffersize")),
-		viper.GetInt("peer.events.timeout"))
+		viper.GetInt("peer.events.timeout"),
+		viper.GetInt("peer.events.sendBuffer"),
+		viper.GetDuration("peer.events.sendTimeout"))
 
 	pb.RegisterEventsServer(grpcServer.Server(), ehServer)
 	return grpcServer, nil
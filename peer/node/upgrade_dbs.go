@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/spf13/cobra"
+)
+
+func upgradeDBsCmd() *cobra.Command {
+	return nodeUpgradeDBsCmd
+}
+
+var nodeUpgradeDBsCmd = &cobra.Command{
+	Use:   "upgrade-dbs",
+	Short: "Upgrades the ledger data of the node.",
+	Long: `Upgrades the ledger data of the node to the format of this version of the peer. ` +
+		`The node must be stopped and its data backed up before running this command.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return upgradeDBs()
+	},
+}
+
+func upgradeDBs() error {
+	logger.Info("Upgrading ledger data")
+	if err := ledgermgmt.UpgradeDBs(); err != nil {
+		logger.Errorf("Failed to upgrade ledger data: %s", err)
+		return err
+	}
+	logger.Info("Ledger data upgraded")
+	return nil
+}
@@ -0,0 +1,419 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tx
+
+import (
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/scc/qscc"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+// decodedTx is the output of decode
+type decodedTx struct {
+	TxID           string           `json:"txID"`
+	ChannelID      string           `json:"channelID"`
+	Type           string           `json:"type"`
+	Timestamp      string           `json:"timestamp,omitempty"`
+	ValidationCode string           `json:"validationCode,omitempty"`
+	Creator        *identity        `json:"creator"`
+	Actions        []*decodedAction `json:"actions,omitempty"`
+}
+
+// identity is a serialized identity, with the subject of its certificate if
+// it can be parsed
+type identity struct {
+	MSPID   string `json:"mspID"`
+	Subject string `json:"subject,omitempty"`
+}
+
+// decodedAction is an endorsed chaincode action of a transaction
+type decodedAction struct {
+	Chaincode string          `json:"chaincode,omitempty"`
+	Status    int32           `json:"status"`
+	Message   string          `json:"message,omitempty"`
+	Endorsers []*identity     `json:"endorsers"`
+	RwSets    []*nsRwSet      `json:"rwsets"`
+	Event     *chaincodeEvent `json:"event,omitempty"`
+}
+
+// nsRwSet is the read-write set of a transaction on one namespace
+type nsRwSet struct {
+	Namespace      string           `json:"namespace"`
+	Reads          []*read          `json:"reads,omitempty"`
+	RangeQueries   []*rangeQuery    `json:"rangeQueries,omitempty"`
+	Writes         []*write         `json:"writes,omitempty"`
+	MetadataWrites []*metadataWrite `json:"metadataWrites,omitempty"`
+}
+
+// read is a key read, with the version it was read at. A nil version means
+// the key didn't exist when the transaction was simulated
+type read struct {
+	Key     string       `json:"key"`
+	Version *readVersion `json:"version"`
+}
+
+type readVersion struct {
+	BlockNum uint64 `json:"blockNum"`
+	TxNum    uint64 `json:"txNum"`
+}
+
+type rangeQuery struct {
+	StartKey     string  `json:"startKey"`
+	EndKey       string  `json:"endKey"`
+	ItrExhausted bool    `json:"itrExhausted"`
+	Reads        []*read `json:"reads,omitempty"`
+	MerkleHashes int     `json:"merkleHashes,omitempty"`
+}
+
+type write struct {
+	Key      string `json:"key"`
+	IsDelete bool   `json:"isDelete,omitempty"`
+	Value    string `json:"value,omitempty"`
+}
+
+type metadataWrite struct {
+	Key     string            `json:"key"`
+	Entries map[string]string `json:"entries"`
+}
+
+type chaincodeEvent struct {
+	ChaincodeID string `json:"chaincodeID"`
+	EventName   string `json:"eventName"`
+	Payload     string `json:"payload,omitempty"`
+}
+
+func decodeCmd(cf *TxCmdFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decode",
+		Short: "Decodes a transaction and prints its read-write sets.",
+		Long: `Decodes a transaction envelope, read from a file or fetched from the peer by ID, ` +
+			`and prints as JSON its creator, and for each of its actions the endorsers, the reads ` +
+			`with the versions they were read at, the range queries, the writes and the chaincode event.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return decode(cf)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&txFile, "file", "f", "", "Path to a file containing a marshaled transaction envelope")
+	flags.StringVarP(&txID, "txID", "t", "", "ID of a committed transaction to fetch from the peer, instead of --file")
+	flags.StringVarP(&chainID, "channelID", "c", "", "The channel of the transaction to fetch with --txID")
+	return cmd
+}
+
+func decode(cf *TxCmdFactory) error {
+	if (txFile == "") == (txID == "") {
+		return errors.New("Must supply exactly one of --file or --txID")
+	}
+
+	var env *cb.Envelope
+	var validationCode string
+	if txFile != "" {
+		envBytes, err := ioutil.ReadFile(txFile)
+		if err != nil {
+			return fmt.Errorf("Error reading transaction file %s: %s", txFile, err)
+		}
+		env, err = utils.UnmarshalEnvelope(envBytes)
+		if err != nil {
+			return err
+		}
+	} else {
+		if chainID == "" {
+			return errors.New("Must supply channel ID to fetch a transaction")
+		}
+		var err error
+		if cf == nil {
+			cf, err = InitCmdFactory()
+			if err != nil {
+				return err
+			}
+		}
+		processedTx, err := getTransactionByID(cf, chainID, txID)
+		if err != nil {
+			return err
+		}
+		env = processedTx.TransactionEnvelope
+		validationCode = pb.TxValidationCode(processedTx.ValidationCode).String()
+	}
+
+	decoded, err := decodeEnvelope(env)
+	if err != nil {
+		return err
+	}
+	decoded.ValidationCode = validationCode
+
+	return printDecodedTx(os.Stdout, decoded)
+}
+
+// getTransactionByID fetches a committed transaction from the peer with qscc
+func getTransactionByID(cf *TxCmdFactory, chainID, txID string) (*pb.ProcessedTransaction, error) {
+	invocation := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: "qscc"},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(qscc.GetTransactionByID), []byte(chainID), []byte(txID)}},
+		},
+	}
+
+	creator, err := cf.Signer.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("Error serializing the signer: %s", err)
+	}
+	prop, _, err := utils.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, chainID, invocation, creator)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot create proposal, due to %s", err)
+	}
+	signedProp, err := utils.GetSignedProposal(prop, cf.Signer)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot create signed proposal, due to %s", err)
+	}
+
+	proposalResp, err := cf.EndorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return nil, fmt.Errorf("Failed sending proposal, got %s", err)
+	}
+	if proposalResp.Response == nil {
+		return nil, errors.New("Received bad response, no response")
+	}
+	if proposalResp.Response.Status != 200 {
+		return nil, fmt.Errorf("Received bad response, status %d: %s", proposalResp.Response.Status, proposalResp.Response.Message)
+	}
+
+	processedTx := &pb.ProcessedTransaction{}
+	if err := proto.Unmarshal(proposalResp.Response.Payload, processedTx); err != nil {
+		return nil, fmt.Errorf("Cannot read transaction response, %s", err)
+	}
+	if processedTx.TransactionEnvelope == nil {
+		return nil, fmt.Errorf("Transaction %s has no envelope", txID)
+	}
+	return processedTx, nil
+}
+
+// decodeEnvelope decodes the headers of a transaction envelope and, for
+// endorser transactions, its actions
+func decodeEnvelope(env *cb.Envelope) (*decodedTx, error) {
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("Transaction has no header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := &decodedTx{
+		TxID:      chdr.TxId,
+		ChannelID: chdr.ChannelId,
+		Type:      cb.HeaderType(chdr.Type).String(),
+		Creator:   decodeIdentity(shdr.Creator),
+	}
+	if chdr.Timestamp != nil {
+		decoded.Timestamp = time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos)).UTC().Format(time.RFC3339Nano)
+	}
+
+	if cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+		return decoded, nil
+	}
+
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	for i, txAction := range tx.Actions {
+		action, err := decodeAction(txAction)
+		if err != nil {
+			return nil, fmt.Errorf("Error decoding action %d: %s", i, err)
+		}
+		decoded.Actions = append(decoded.Actions, action)
+	}
+	return decoded, nil
+}
+
+func decodeAction(txAction *pb.TransactionAction) (*decodedAction, error) {
+	ccActionPayload, ccAction, err := utils.GetPayloads(txAction)
+	if err != nil {
+		return nil, err
+	}
+	if ccAction == nil {
+		return nil, errors.New("no chaincode action in the proposal response payload")
+	}
+
+	action := &decodedAction{}
+	if ccAction.ChaincodeId != nil {
+		action.Chaincode = ccAction.ChaincodeId.Name
+		if ccAction.ChaincodeId.Version != "" {
+			action.Chaincode += ":" + ccAction.ChaincodeId.Version
+		}
+	}
+	if ccAction.Response != nil {
+		action.Status = ccAction.Response.Status
+		action.Message = ccAction.Response.Message
+	}
+	if ccActionPayload.Action != nil {
+		for _, endorsement := range ccActionPayload.Action.Endorsements {
+			action.Endorsers = append(action.Endorsers, decodeIdentity(endorsement.Endorser))
+		}
+	}
+
+	if len(ccAction.Results) > 0 {
+		txRWSet := &rwset.TxReadWriteSet{}
+		if err := proto.Unmarshal(ccAction.Results, txRWSet); err != nil {
+			return nil, fmt.Errorf("Error unmarshaling the read-write set: %s", err)
+		}
+		for _, nsRWSet := range txRWSet.NsRwset {
+			decodedRWSet, err := decodeNsRwSet(nsRWSet)
+			if err != nil {
+				return nil, err
+			}
+			action.RwSets = append(action.RwSets, decodedRWSet)
+		}
+	}
+
+	if len(ccAction.Events) > 0 {
+		event, err := utils.GetChaincodeEvents(ccAction.Events)
+		if err != nil {
+			return nil, err
+		}
+		if event.EventName != "" {
+			action.Event = &chaincodeEvent{
+				ChaincodeID: event.ChaincodeId,
+				EventName:   event.EventName,
+				Payload:     printable(event.Payload),
+			}
+		}
+	}
+	return action, nil
+}
+
+func decodeNsRwSet(nsRWSet *rwset.NsReadWriteSet) (*nsRwSet, error) {
+	kvRWSet := &kvrwset.KVRWSet{}
+	if err := proto.Unmarshal(nsRWSet.Rwset, kvRWSet); err != nil {
+		return nil, fmt.Errorf("Error unmarshaling the read-write set of namespace %s: %s", nsRWSet.Namespace, err)
+	}
+
+	decoded := &nsRwSet{Namespace: nsRWSet.Namespace}
+	decoded.Reads = decodeReads(kvRWSet.Reads)
+	for _, rqi := range kvRWSet.RangeQueriesInfo {
+		rq := &rangeQuery{
+			StartKey:     rqi.StartKey,
+			EndKey:       rqi.EndKey,
+			ItrExhausted: rqi.ItrExhausted,
+		}
+		if rawReads := rqi.GetRawReads(); rawReads != nil {
+			rq.Reads = decodeReads(rawReads.KvReads)
+		}
+		if summary := rqi.GetReadsMerkleHashes(); summary != nil {
+			rq.MerkleHashes = len(summary.MaxLevelHashes)
+		}
+		decoded.RangeQueries = append(decoded.RangeQueries, rq)
+	}
+	for _, kvWrite := range kvRWSet.Writes {
+		decoded.Writes = append(decoded.Writes, &write{
+			Key:      kvWrite.Key,
+			IsDelete: kvWrite.IsDelete,
+			Value:    printable(kvWrite.Value),
+		})
+	}
+	for _, kvMetadataWrite := range kvRWSet.MetadataWrites {
+		mw := &metadataWrite{Key: kvMetadataWrite.Key, Entries: map[string]string{}}
+		for _, entry := range kvMetadataWrite.Entries {
+			mw.Entries[entry.Name] = printable(entry.Value)
+		}
+		decoded.MetadataWrites = append(decoded.MetadataWrites, mw)
+	}
+	return decoded, nil
+}
+
+func decodeReads(kvReads []*kvrwset.KVRead) []*read {
+	var reads []*read
+	for _, kvRead := range kvReads {
+		r := &read{Key: kvRead.Key}
+		if kvRead.Version != nil {
+			r.Version = &readVersion{BlockNum: kvRead.Version.BlockNum, TxNum: kvRead.Version.TxNum}
+		}
+		reads = append(reads, r)
+	}
+	return reads
+}
+
+// decodeIdentity decodes a serialized identity, leaving the subject empty if
+// the identity isn't a PEM encoded certificate
+func decodeIdentity(serializedIdentity []byte) *identity {
+	sID := &mspprotos.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedIdentity, sID); err != nil {
+		logger.Warningf("Could not unmarshal serialized identity: %s", err)
+		return &identity{}
+	}
+
+	id := &identity{MSPID: sID.Mspid}
+	block, _ := pem.Decode(sID.IdBytes)
+	if block == nil {
+		return id
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		logger.Warningf("Could not parse the certificate of an identity of MSP %s: %s", sID.Mspid, err)
+		return id
+	}
+	id.Subject = cert.Subject.String()
+	return id
+}
+
+// printable returns the bytes as a string if they are valid UTF-8, and hex
+// encoded with a 0x prefix otherwise
+func printable(b []byte) string {
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	return "0x" + hex.EncodeToString(b)
+}
+
+// printDecodedTx writes the decoded transaction as JSON
+func printDecodedTx(w io.Writer, decoded *decodedTx) error {
+	out, err := json.MarshalIndent(decoded, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", out)
+	return err
+}
@@ -0,0 +1,183 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/msp/mgmt/testtools"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func serializedIdentity(t *testing.T, mspID string) []byte {
+	sID, err := proto.Marshal(&mspprotos.SerializedIdentity{Mspid: mspID, IdBytes: []byte("not a certificate")})
+	assert.NoError(t, err)
+	return sID
+}
+
+func newTestEnvelope(t *testing.T) *cb.Envelope {
+	rwsBuilder := rwsetutil.NewRWSetBuilder()
+	rwsBuilder.AddToReadSet("mycc", "a", version.NewHeight(3, 1))
+	rwsBuilder.AddToReadSet("mycc", "b", nil)
+	rwsBuilder.AddToWriteSet("mycc", "a", []byte("90"))
+	rwsBuilder.AddToWriteSet("mycc", "c", []byte{0xff, 0xfe})
+	rwsBuilder.AddToWriteSet("mycc", "d", nil)
+	results, err := rwsBuilder.GetTxReadWriteSet().ToProtoBytes()
+	assert.NoError(t, err)
+
+	events, err := proto.Marshal(&pb.ChaincodeEvent{ChaincodeId: "mycc", TxId: "tx1", EventName: "moved", Payload: []byte("10")})
+	assert.NoError(t, err)
+	ccAction, err := proto.Marshal(&pb.ChaincodeAction{
+		Results:     results,
+		Events:      events,
+		Response:    &pb.Response{Status: 200, Message: "OK"},
+		ChaincodeId: &pb.ChaincodeID{Name: "mycc", Version: "1.0"},
+	})
+	assert.NoError(t, err)
+	prp, err := proto.Marshal(&pb.ProposalResponsePayload{Extension: ccAction})
+	assert.NoError(t, err)
+	ccActionPayload, err := proto.Marshal(&pb.ChaincodeActionPayload{
+		Action: &pb.ChaincodeEndorsedAction{
+			ProposalResponsePayload: prp,
+			Endorsements: []*pb.Endorsement{
+				{Endorser: serializedIdentity(t, "Org1MSP")},
+				{Endorser: serializedIdentity(t, "Org2MSP")},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	tx, err := proto.Marshal(&pb.Transaction{Actions: []*pb.TransactionAction{{Payload: ccActionPayload}}})
+	assert.NoError(t, err)
+
+	chdr := utils.MakeChannelHeader(cb.HeaderType_ENDORSER_TRANSACTION, 0, "mychannel", 0)
+	chdr.TxId = "tx1"
+	payload := &cb.Payload{
+		Header: utils.MakePayloadHeader(chdr, &cb.SignatureHeader{Creator: serializedIdentity(t, "Org1MSP")}),
+		Data:   tx,
+	}
+	payloadBytes, err := proto.Marshal(payload)
+	assert.NoError(t, err)
+	return &cb.Envelope{Payload: payloadBytes}
+}
+
+func TestDecodeEnvelope(t *testing.T) {
+	decoded, err := decodeEnvelope(newTestEnvelope(t))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "tx1", decoded.TxID)
+	assert.Equal(t, "mychannel", decoded.ChannelID)
+	assert.Equal(t, "ENDORSER_TRANSACTION", decoded.Type)
+	assert.Equal(t, &identity{MSPID: "Org1MSP"}, decoded.Creator)
+
+	assert.Len(t, decoded.Actions, 1)
+	action := decoded.Actions[0]
+	assert.Equal(t, "mycc:1.0", action.Chaincode)
+	assert.Equal(t, int32(200), action.Status)
+	assert.Equal(t, []*identity{{MSPID: "Org1MSP"}, {MSPID: "Org2MSP"}}, action.Endorsers)
+	assert.Equal(t, &chaincodeEvent{ChaincodeID: "mycc", EventName: "moved", Payload: "10"}, action.Event)
+
+	assert.Len(t, action.RwSets, 1)
+	rwSet := action.RwSets[0]
+	assert.Equal(t, "mycc", rwSet.Namespace)
+	assert.Equal(t, []*read{{Key: "a", Version: &readVersion{BlockNum: 3, TxNum: 1}}, {Key: "b"}}, rwSet.Reads)
+	assert.Equal(t, []*write{{Key: "a", Value: "90"}, {Key: "c", Value: "0xfffe"}, {Key: "d", IsDelete: true}}, rwSet.Writes)
+}
+
+func TestDecodeEnvelopeNonEndorserTx(t *testing.T) {
+	chdr := utils.MakeChannelHeader(cb.HeaderType_CONFIG, 0, "mychannel", 0)
+	payloadBytes, err := proto.Marshal(&cb.Payload{
+		Header: utils.MakePayloadHeader(chdr, &cb.SignatureHeader{Creator: serializedIdentity(t, "OrdererMSP")}),
+		Data:   []byte("config"),
+	})
+	assert.NoError(t, err)
+
+	decoded, err := decodeEnvelope(&cb.Envelope{Payload: payloadBytes})
+	assert.NoError(t, err)
+	assert.Equal(t, "CONFIG", decoded.Type)
+	assert.Empty(t, decoded.Actions)
+
+	_, err = decodeEnvelope(&cb.Envelope{Payload: []byte("garbage")})
+	assert.Error(t, err)
+}
+
+func TestPrintDecodedTx(t *testing.T) {
+	decoded, err := decodeEnvelope(newTestEnvelope(t))
+	assert.NoError(t, err)
+
+	var out bytes.Buffer
+	assert.NoError(t, printDecodedTx(&out, decoded))
+	parsed := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &parsed))
+	assert.Equal(t, "tx1", parsed["txID"])
+}
+
+func TestDecodeCmd(t *testing.T) {
+	envBytes, err := proto.Marshal(newTestEnvelope(t))
+	assert.NoError(t, err)
+	dir, err := ioutil.TempDir("", "txdecode")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	txPath := filepath.Join(dir, "tx.pb")
+	assert.NoError(t, ioutil.WriteFile(txPath, envBytes, 0644))
+
+	assert.NoError(t, msptesttools.LoadMSPSetupForTesting())
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err)
+
+	processedTx, err := proto.Marshal(&pb.ProcessedTransaction{TransactionEnvelope: newTestEnvelope(t), ValidationCode: int32(pb.TxValidationCode_MVCC_READ_CONFLICT)})
+	assert.NoError(t, err)
+	mockCF := &TxCmdFactory{
+		EndorserClient: common.GetMockEndorserClient(&pb.ProposalResponse{Response: &pb.Response{Status: 200, Payload: processedTx}}, nil),
+		Signer:         signer,
+	}
+
+	for _, test := range []struct {
+		name    string
+		args    []string
+		success bool
+	}{
+		{"file", []string{"-f", txPath, "-t", "", "-c", ""}, true},
+		{"txID", []string{"-f", "", "-t", "tx1", "-c", "mychannel"}, true},
+		{"none", []string{"-f", "", "-t", "", "-c", ""}, false},
+		{"both", []string{"-f", txPath, "-t", "tx1", "-c", "mychannel"}, false},
+		{"no channel", []string{"-f", "", "-t", "tx1", "-c", ""}, false},
+		{"missing file", []string{"-f", filepath.Join(dir, "missing"), "-t", "", "-c", ""}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cmd := decodeCmd(mockCF)
+			cmd.SetArgs(test.args)
+			if test.success {
+				assert.NoError(t, cmd.Execute())
+			} else {
+				assert.Error(t, cmd.Execute())
+			}
+		})
+	}
+}
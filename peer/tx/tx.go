@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tx
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/cobra"
+)
+
+const txFuncName = "tx"
+
+var logger = flogging.MustGetLogger("txCmd")
+
+var (
+	chainID string
+	txFile  string
+	txID    string
+)
+
+// Cmd returns the cobra command for Tx
+func Cmd(cf *TxCmdFactory) *cobra.Command {
+	txCmd.AddCommand(decodeCmd(cf))
+
+	return txCmd
+}
+
+var txCmd = &cobra.Command{
+	Use:   txFuncName,
+	Short: fmt.Sprintf("%s specific commands.", txFuncName),
+	Long:  fmt.Sprintf("%s specific commands.", txFuncName),
+}
+
+// TxCmdFactory holds the clients used by TxCmd
+type TxCmdFactory struct {
+	EndorserClient pb.EndorserClient
+	Signer         msp.SigningIdentity
+}
+
+// InitCmdFactory init the TxCmdFactory with the clients needed to fetch a
+// transaction from the peer
+func InitCmdFactory() (*TxCmdFactory, error) {
+	endorserClient, err := common.GetEndorserClient()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting endorser client %s: %s", txFuncName, err)
+	}
+
+	signer, err := common.GetDefaultSigner()
+	if err != nil {
+		return nil, fmt.Errorf("Error getting default signer: %s", err)
+	}
+
+	return &TxCmdFactory{
+		EndorserClient: endorserClient,
+		Signer:         signer,
+	}, nil
+}
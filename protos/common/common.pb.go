@@ -39,6 +39,7 @@ It has these top-level messages:
 	HashingAlgorithm
 	BlockDataHashingStructure
 	OrdererAddresses
+	Consortium
 	BlockchainInfo
 	Policy
 	SignaturePolicyEnvelope
@@ -147,6 +148,7 @@ const (
 	BlockMetadataIndex_LAST_CONFIG         BlockMetadataIndex = 1
 	BlockMetadataIndex_TRANSACTIONS_FILTER BlockMetadataIndex = 2
 	BlockMetadataIndex_ORDERER             BlockMetadataIndex = 3
+	BlockMetadataIndex_MVCC_CONFLICTS      BlockMetadataIndex = 4
 )
 
 var BlockMetadataIndex_name = map[int32]string{
@@ -154,12 +156,14 @@ var BlockMetadataIndex_name = map[int32]string{
 	1: "LAST_CONFIG",
 	2: "TRANSACTIONS_FILTER",
 	3: "ORDERER",
+	4: "MVCC_CONFLICTS",
 }
 var BlockMetadataIndex_value = map[string]int32{
 	"SIGNATURES":          0,
 	"LAST_CONFIG":         1,
 	"TRANSACTIONS_FILTER": 2,
 	"ORDERER":             3,
+	"MVCC_CONFLICTS":      4,
 }
 
 func (x BlockMetadataIndex) String() string {
@@ -242,6 +246,9 @@ type ChannelHeader struct {
 	Epoch uint64 `protobuf:"varint,6,opt,name=epoch" json:"epoch,omitempty"`
 	// Extension that may be attached based on the header type
 	Extension []byte `protobuf:"bytes,7,opt,name=extension,proto3" json:"extension,omitempty"`
+	// If mutual TLS is employed, this represents
+	// the hash of the client's TLS certificate
+	TlsCertHash []byte `protobuf:"bytes,8,opt,name=tls_cert_hash,json=tlsCertHash,proto3" json:"tls_cert_hash,omitempty"`
 }
 
 func (m *ChannelHeader) Reset()                    { *m = ChannelHeader{} }
@@ -390,62 +397,62 @@ func init() {
 func init() { proto.RegisterFile("common/common.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 900 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x55, 0xdf, 0x6e, 0xe3, 0xc4,
-	0x1b, 0xad, 0xe3, 0xfc, 0x69, 0xbe, 0x34, 0xad, 0x3b, 0xd9, 0xfe, 0xd6, 0xbf, 0xc2, 0x6a, 0x23,
-	0xc3, 0xa2, 0xd2, 0x4a, 0x89, 0x28, 0x37, 0x70, 0xe9, 0xd8, 0x93, 0xd6, 0x6a, 0xd6, 0x2e, 0x63,
-	0x67, 0x11, 0xbb, 0x48, 0x96, 0x93, 0x4c, 0x13, 0x8b, 0xc4, 0x8e, 0x6c, 0xa7, 0x6a, 0x5f, 0x02,
-	0x21, 0xc1, 0x0d, 0x17, 0xbc, 0x00, 0x4f, 0xc2, 0x5b, 0xf0, 0x12, 0x48, 0xdc, 0xa2, 0xf1, 0x8c,
-	0xbd, 0x49, 0x59, 0x89, 0xab, 0xcc, 0x39, 0x73, 0x3c, 0xdf, 0x99, 0xf3, 0x7d, 0xb1, 0xa1, 0x33,
-	0x8d, 0x57, 0xab, 0x38, 0xea, 0xf3, 0x9f, 0xde, 0x3a, 0x89, 0xb3, 0x18, 0xd5, 0x39, 0x3a, 0x7d,
-	0x39, 0x8f, 0xe3, 0xf9, 0x92, 0xf6, 0x73, 0x76, 0xb2, 0xb9, 0xeb, 0x67, 0xe1, 0x8a, 0xa6, 0x59,
-	0xb0, 0x5a, 0x73, 0xa1, 0xa6, 0x01, 0x8c, 0x82, 0x34, 0x33, 0xe2, 0xe8, 0x2e, 0x9c, 0xa3, 0x67,
-	0x50, 0x0b, 0xa3, 0x19, 0x7d, 0x50, 0xa5, 0xae, 0x74, 0x56, 0x25, 0x1c, 0x68, 0xef, 0x60, 0xff,
-	0x35, 0xcd, 0x82, 0x59, 0x90, 0x05, 0x4c, 0x71, 0x1f, 0x2c, 0x37, 0x34, 0x57, 0x1c, 0x10, 0x0e,
-	0xd0, 0xd7, 0x00, 0x69, 0x38, 0x8f, 0x82, 0x6c, 0x93, 0xd0, 0x54, 0xad, 0x74, 0xe5, 0xb3, 0xd6,
-	0xe5, 0xff, 0x7b, 0xc2, 0x51, 0xf1, 0xac, 0x5b, 0x28, 0xc8, 0x96, 0x58, 0xfb, 0x1e, 0x8e, 0xff,
-	0x25, 0x40, 0x9f, 0x83, 0x52, 0x4a, 0xfc, 0x05, 0x0d, 0x66, 0x34, 0x11, 0x05, 0x8f, 0x4a, 0xfe,
-	0x3a, 0xa7, 0xd1, 0xc7, 0xd0, 0x2c, 0x29, 0xb5, 0x92, 0x6b, 0xde, 0x13, 0xda, 0x5b, 0xa8, 0x0b,
-	0xdd, 0x2b, 0x38, 0x9c, 0x2e, 0x82, 0x28, 0xa2, 0xcb, 0xdd, 0x03, 0xdb, 0x82, 0x15, 0xb2, 0x0f,
-	0x55, 0xae, 0x7c, 0xb0, 0xb2, 0xf6, 0xa7, 0x04, 0x6d, 0x63, 0xe7, 0x61, 0x04, 0xd5, 0xec, 0x71,
-	0xcd, 0xb3, 0xa9, 0x91, 0x7c, 0x8d, 0x54, 0x68, 0xdc, 0xd3, 0x24, 0x0d, 0xe3, 0x28, 0x3f, 0xa7,
-	0x46, 0x0a, 0x88, 0xbe, 0x82, 0x66, 0xd9, 0x0d, 0x55, 0xee, 0x4a, 0x67, 0xad, 0xcb, 0xd3, 0x1e,
-	0xef, 0x57, 0xaf, 0xe8, 0x57, 0xcf, 0x2b, 0x14, 0xe4, 0xbd, 0x18, 0xbd, 0x00, 0x28, 0xee, 0x12,
-	0xce, 0xd4, 0x6a, 0x57, 0x3a, 0x6b, 0x92, 0xa6, 0x60, 0xac, 0x19, 0xea, 0x40, 0x2d, 0x7b, 0x60,
-	0x3b, 0xb5, 0x7c, 0xa7, 0x9a, 0x3d, 0x58, 0x33, 0xd6, 0x38, 0xba, 0x8e, 0xa7, 0x0b, 0xb5, 0xce,
-	0x5b, 0x9b, 0x03, 0x96, 0x1e, 0x7d, 0xc8, 0x68, 0x94, 0xfb, 0x6b, 0xf0, 0xf4, 0x4a, 0x42, 0xd3,
-	0xe1, 0xc8, 0x7d, 0x12, 0xb7, 0x0a, 0x8d, 0x69, 0x42, 0x83, 0x2c, 0x2e, 0xf2, 0x2b, 0x20, 0x2b,
-	0x10, 0xc5, 0xd1, 0xb4, 0x68, 0x02, 0x07, 0x1a, 0x86, 0xc6, 0x6d, 0xf0, 0xb8, 0x8c, 0x83, 0x19,
-	0xfa, 0x0c, 0xea, 0x5b, 0xc9, 0xb7, 0x2e, 0x0f, 0x8b, 0x01, 0xe1, 0x47, 0x13, 0xb1, 0xcb, 0x52,
-	0x64, 0xd3, 0x20, 0xce, 0xc9, 0xd7, 0xda, 0x00, 0xf6, 0x71, 0x74, 0x4f, 0x97, 0x31, 0x4f, 0x74,
-	0xcd, 0x8f, 0x2c, 0x2c, 0x08, 0xf8, 0x1f, 0xb3, 0xf0, 0xa3, 0x04, 0xb5, 0xc1, 0x32, 0x9e, 0xfe,
-	0x80, 0x2e, 0x9e, 0x38, 0xe9, 0x14, 0x4e, 0xf2, 0xed, 0x27, 0x76, 0x5e, 0x6d, 0xd9, 0x69, 0x5d,
-	0x1e, 0xef, 0x48, 0xcd, 0x20, 0x0b, 0xb8, 0x43, 0xf4, 0x05, 0xec, 0xaf, 0xc4, 0x1c, 0x8b, 0x66,
-	0x9e, 0xec, 0x48, 0x8b, 0x21, 0x27, 0xa5, 0x4c, 0x9b, 0x43, 0x6b, 0xab, 0x20, 0xfa, 0x1f, 0xd4,
-	0xa3, 0xcd, 0x6a, 0x22, 0x5c, 0x55, 0x89, 0x40, 0xe8, 0x13, 0x68, 0xaf, 0x13, 0x7a, 0x1f, 0xc6,
-	0x9b, 0xd4, 0x5f, 0x04, 0xe9, 0x42, 0xdc, 0xec, 0xa0, 0x20, 0xaf, 0x83, 0x74, 0x81, 0x3e, 0x82,
-	0x26, 0x3b, 0x93, 0x0b, 0xe4, 0x5c, 0xb0, 0xcf, 0x08, 0xb6, 0xa9, 0xbd, 0x84, 0x66, 0x69, 0xb7,
-	0x8c, 0x57, 0xea, 0xca, 0x65, 0xbc, 0x17, 0xd0, 0xde, 0x31, 0x89, 0x4e, 0xb7, 0x6e, 0xc3, 0x85,
-	0x25, 0x3e, 0xff, 0x5d, 0x82, 0xba, 0x9b, 0x05, 0xd9, 0x26, 0x45, 0x2d, 0x68, 0x8c, 0xed, 0x1b,
-	0xdb, 0xf9, 0xd6, 0x56, 0xf6, 0xd0, 0x01, 0x34, 0xdc, 0xb1, 0x61, 0x60, 0xd7, 0x55, 0xfe, 0x90,
-	0x90, 0x02, 0xad, 0x81, 0x6e, 0xfa, 0x04, 0x7f, 0x33, 0xc6, 0xae, 0xa7, 0xfc, 0x24, 0xa3, 0x43,
-	0x68, 0x0e, 0x1d, 0x32, 0xb0, 0x4c, 0x13, 0xdb, 0xca, 0xcf, 0x39, 0xb6, 0x1d, 0xcf, 0x1f, 0x3a,
-	0x63, 0xdb, 0x54, 0x7e, 0x91, 0xd1, 0x0b, 0x50, 0x85, 0xda, 0xc7, 0xb6, 0x67, 0x79, 0xdf, 0xf9,
-	0x9e, 0xe3, 0xf8, 0x23, 0x9d, 0x5c, 0x61, 0xe5, 0x37, 0x19, 0x9d, 0xc2, 0x89, 0x65, 0x7b, 0x98,
-	0xd8, 0xfa, 0xc8, 0x77, 0x31, 0x79, 0x83, 0x89, 0x8f, 0x09, 0x71, 0x88, 0xf2, 0x97, 0x8c, 0x54,
-	0xe8, 0x30, 0xca, 0x32, 0xb0, 0x3f, 0xb6, 0xf5, 0x37, 0xba, 0x35, 0xd2, 0x07, 0x23, 0xac, 0xfc,
-	0x2d, 0x9f, 0xff, 0x2a, 0x01, 0xf0, 0x7c, 0x3d, 0xf6, 0x6f, 0x6c, 0x41, 0xe3, 0x35, 0x76, 0x5d,
-	0xfd, 0x0a, 0x2b, 0x7b, 0x08, 0xa0, 0x6e, 0x38, 0xf6, 0xd0, 0xba, 0x52, 0x24, 0x74, 0x0c, 0x6d,
-	0xbe, 0xf6, 0xc7, 0xb7, 0xa6, 0xee, 0x61, 0xa5, 0x82, 0x54, 0x78, 0x86, 0x6d, 0xd3, 0x21, 0x2e,
-	0x26, 0xbe, 0x47, 0x74, 0xdb, 0xd5, 0x0d, 0xcf, 0x72, 0x6c, 0x45, 0x46, 0xcf, 0xa1, 0xe3, 0x10,
-	0x13, 0x93, 0x27, 0x1b, 0x55, 0x74, 0x02, 0xc7, 0x26, 0x1e, 0x59, 0xcc, 0x9b, 0x8b, 0xf1, 0x8d,
-	0x6f, 0xd9, 0x43, 0x47, 0xa9, 0x31, 0xda, 0xb8, 0xd6, 0x2d, 0xdb, 0x70, 0x4c, 0xec, 0xdf, 0xea,
-	0xc6, 0x0d, 0xab, 0x5f, 0x3f, 0x7f, 0x07, 0x68, 0x27, 0x75, 0x8b, 0xbd, 0x6d, 0xd1, 0x21, 0x80,
-	0x6b, 0x5d, 0xd9, 0xba, 0x37, 0x26, 0xd8, 0x55, 0xf6, 0xd0, 0x11, 0xb4, 0x46, 0xba, 0xeb, 0xf9,
-	0xa5, 0xd5, 0xe7, 0xd0, 0xd9, 0xaa, 0xea, 0xfa, 0x43, 0x6b, 0xe4, 0x61, 0xa2, 0x54, 0xd8, 0xe5,
-	0x84, 0x2d, 0x45, 0x1e, 0xb8, 0xf0, 0x69, 0x9c, 0xcc, 0x7b, 0x8b, 0xc7, 0x35, 0x4d, 0x96, 0x74,
-	0x36, 0xa7, 0x49, 0xef, 0x2e, 0x98, 0x24, 0xe1, 0x94, 0xbf, 0x5b, 0x52, 0x31, 0x9c, 0x6f, 0x2f,
-	0xe6, 0x61, 0xb6, 0xd8, 0x4c, 0x18, 0xec, 0x6f, 0x89, 0xfb, 0x5c, 0xcc, 0x3f, 0x1c, 0xa9, 0xf8,
-	0xb8, 0x4c, 0xea, 0x39, 0xfc, 0xf2, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa1, 0xcb, 0xe1, 0xb4,
-	0x74, 0x06, 0x00, 0x00,
+	// 907 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x55, 0xd1, 0x6e, 0xe3, 0x44,
+	0x14, 0xad, 0xeb, 0xc4, 0x69, 0x6e, 0xda, 0x74, 0x3a, 0xd9, 0xb2, 0xa6, 0xb0, 0xda, 0xc8, 0xb0,
+	0xa8, 0xb4, 0x52, 0x22, 0xca, 0x0b, 0x3c, 0x3a, 0xf6, 0xa4, 0xb5, 0x9a, 0xda, 0x65, 0xc6, 0x29,
+	0x62, 0x41, 0xb2, 0x9c, 0x64, 0x9a, 0x44, 0x24, 0x76, 0x64, 0x3b, 0x55, 0xfb, 0x13, 0x08, 0x09,
+	0x5e, 0x78, 0xe0, 0x07, 0xf8, 0x12, 0xfe, 0x82, 0x9f, 0x40, 0xe2, 0x15, 0xd9, 0x63, 0x7b, 0x93,
+	0xb2, 0x12, 0x4f, 0xf1, 0x39, 0x73, 0x66, 0xee, 0x99, 0x73, 0x6f, 0x6c, 0x68, 0x8d, 0xc3, 0xe5,
+	0x32, 0x0c, 0xba, 0xe2, 0xa7, 0xb3, 0x8a, 0xc2, 0x24, 0xc4, 0x8a, 0x40, 0x27, 0xaf, 0xa7, 0x61,
+	0x38, 0x5d, 0xf0, 0x6e, 0xc6, 0x8e, 0xd6, 0xf7, 0xdd, 0x64, 0xbe, 0xe4, 0x71, 0xe2, 0x2f, 0x57,
+	0x42, 0xa8, 0x69, 0x00, 0x03, 0x3f, 0x4e, 0x8c, 0x30, 0xb8, 0x9f, 0x4f, 0xf1, 0x0b, 0xa8, 0xce,
+	0x83, 0x09, 0x7f, 0x54, 0xa5, 0xb6, 0x74, 0x5a, 0xa1, 0x02, 0x68, 0xdf, 0xc3, 0xde, 0x0d, 0x4f,
+	0xfc, 0x89, 0x9f, 0xf8, 0xa9, 0xe2, 0xc1, 0x5f, 0xac, 0x79, 0xa6, 0xd8, 0xa7, 0x02, 0xe0, 0xaf,
+	0x01, 0xe2, 0xf9, 0x34, 0xf0, 0x93, 0x75, 0xc4, 0x63, 0x75, 0xb7, 0x2d, 0x9f, 0x36, 0x2e, 0x3e,
+	0xec, 0xe4, 0x8e, 0x8a, 0xbd, 0xac, 0x50, 0xd0, 0x0d, 0xb1, 0xf6, 0x03, 0x1c, 0xfd, 0x47, 0x80,
+	0x3f, 0x07, 0x54, 0x4a, 0xbc, 0x19, 0xf7, 0x27, 0x3c, 0xca, 0x0b, 0x1e, 0x96, 0xfc, 0x55, 0x46,
+	0xe3, 0x8f, 0xa1, 0x5e, 0x52, 0xea, 0x6e, 0xa6, 0x79, 0x47, 0x68, 0x6f, 0x41, 0xc9, 0x75, 0x6f,
+	0xa0, 0x39, 0x9e, 0xf9, 0x41, 0xc0, 0x17, 0xdb, 0x07, 0x1e, 0xe4, 0x6c, 0x2e, 0x7b, 0x5f, 0xe5,
+	0xdd, 0xf7, 0x56, 0xd6, 0xfe, 0x92, 0xe0, 0xc0, 0xd8, 0xda, 0x8c, 0xa1, 0x92, 0x3c, 0xad, 0x44,
+	0x36, 0x55, 0x9a, 0x3d, 0x63, 0x15, 0x6a, 0x0f, 0x3c, 0x8a, 0xe7, 0x61, 0x90, 0x9d, 0x53, 0xa5,
+	0x05, 0xc4, 0x5f, 0x41, 0xbd, 0xec, 0x86, 0x2a, 0xb7, 0xa5, 0xd3, 0xc6, 0xc5, 0x49, 0x47, 0xf4,
+	0xab, 0x53, 0xf4, 0xab, 0xe3, 0x16, 0x0a, 0xfa, 0x4e, 0x8c, 0x5f, 0x01, 0x14, 0x77, 0x99, 0x4f,
+	0xd4, 0x4a, 0x5b, 0x3a, 0xad, 0xd3, 0x7a, 0xce, 0x58, 0x13, 0xdc, 0x82, 0x6a, 0xf2, 0x98, 0xae,
+	0x54, 0xb3, 0x95, 0x4a, 0xf2, 0x68, 0x4d, 0xd2, 0xc6, 0xf1, 0x55, 0x38, 0x9e, 0xa9, 0x8a, 0x68,
+	0x6d, 0x06, 0xd2, 0xf4, 0xf8, 0x63, 0xc2, 0x83, 0xcc, 0x5f, 0x4d, 0xa4, 0x57, 0x12, 0x9a, 0x0e,
+	0x87, 0xec, 0x59, 0xdc, 0x2a, 0xd4, 0xc6, 0x11, 0xf7, 0x93, 0xb0, 0xc8, 0xaf, 0x80, 0x69, 0x81,
+	0x20, 0x0c, 0xc6, 0x45, 0x13, 0x04, 0xd0, 0x08, 0xd4, 0x6e, 0xfd, 0xa7, 0x45, 0xe8, 0x4f, 0xf0,
+	0x67, 0xa0, 0x6c, 0x24, 0xdf, 0xb8, 0x68, 0x16, 0x03, 0x22, 0x8e, 0xa6, 0xca, 0xac, 0x4c, 0x31,
+	0x9d, 0x86, 0xfc, 0x9c, 0xec, 0x59, 0xeb, 0xc1, 0x1e, 0x09, 0x1e, 0xf8, 0x22, 0x14, 0x89, 0xae,
+	0xc4, 0x91, 0x85, 0x85, 0x1c, 0xfe, 0xcf, 0x2c, 0xfc, 0x24, 0x41, 0xb5, 0xb7, 0x08, 0xc7, 0x3f,
+	0xe2, 0xf3, 0x67, 0x4e, 0x5a, 0x85, 0x93, 0x6c, 0xf9, 0x99, 0x9d, 0x37, 0x1b, 0x76, 0x1a, 0x17,
+	0x47, 0x5b, 0x52, 0xd3, 0x4f, 0x7c, 0xe1, 0x10, 0x7f, 0x01, 0x7b, 0xcb, 0x7c, 0x8e, 0xf3, 0x66,
+	0x1e, 0x6f, 0x49, 0x8b, 0x21, 0xa7, 0xa5, 0x4c, 0x9b, 0x42, 0x63, 0xa3, 0x20, 0xfe, 0x00, 0x94,
+	0x60, 0xbd, 0x1c, 0xe5, 0xae, 0x2a, 0x34, 0x47, 0xf8, 0x13, 0x38, 0x58, 0x45, 0xfc, 0x61, 0x1e,
+	0xae, 0x63, 0x6f, 0xe6, 0xc7, 0xb3, 0xfc, 0x66, 0xfb, 0x05, 0x79, 0xe5, 0xc7, 0x33, 0xfc, 0x11,
+	0xd4, 0xd3, 0x33, 0x85, 0x40, 0xce, 0x04, 0x7b, 0x29, 0x91, 0x2e, 0x6a, 0xaf, 0xa1, 0x5e, 0xda,
+	0x2d, 0xe3, 0x95, 0xda, 0x72, 0x19, 0xef, 0x39, 0x1c, 0x6c, 0x99, 0xc4, 0x27, 0x1b, 0xb7, 0x11,
+	0xc2, 0x12, 0x9f, 0xfd, 0x21, 0x81, 0xc2, 0x12, 0x3f, 0x59, 0xc7, 0xb8, 0x01, 0xb5, 0xa1, 0x7d,
+	0x6d, 0x3b, 0xdf, 0xda, 0x68, 0x07, 0xef, 0x43, 0x8d, 0x0d, 0x0d, 0x83, 0x30, 0x86, 0xfe, 0x94,
+	0x30, 0x82, 0x46, 0x4f, 0x37, 0x3d, 0x4a, 0xbe, 0x19, 0x12, 0xe6, 0xa2, 0x9f, 0x65, 0xdc, 0x84,
+	0x7a, 0xdf, 0xa1, 0x3d, 0xcb, 0x34, 0x89, 0x8d, 0x7e, 0xc9, 0xb0, 0xed, 0xb8, 0x5e, 0xdf, 0x19,
+	0xda, 0x26, 0xfa, 0x55, 0xc6, 0xaf, 0x40, 0xcd, 0xd5, 0x1e, 0xb1, 0x5d, 0xcb, 0xfd, 0xce, 0x73,
+	0x1d, 0xc7, 0x1b, 0xe8, 0xf4, 0x92, 0xa0, 0xdf, 0x65, 0x7c, 0x02, 0xc7, 0x96, 0xed, 0x12, 0x6a,
+	0xeb, 0x03, 0x8f, 0x11, 0x7a, 0x47, 0xa8, 0x47, 0x28, 0x75, 0x28, 0xfa, 0x5b, 0xc6, 0x2a, 0xb4,
+	0x52, 0xca, 0x32, 0x88, 0x37, 0xb4, 0xf5, 0x3b, 0xdd, 0x1a, 0xe8, 0xbd, 0x01, 0x41, 0xff, 0xc8,
+	0x67, 0xbf, 0x49, 0x00, 0x22, 0x5f, 0x37, 0xfd, 0x37, 0x36, 0xa0, 0x76, 0x43, 0x18, 0xd3, 0x2f,
+	0x09, 0xda, 0xc1, 0x00, 0x8a, 0xe1, 0xd8, 0x7d, 0xeb, 0x12, 0x49, 0xf8, 0x08, 0x0e, 0xc4, 0xb3,
+	0x37, 0xbc, 0x35, 0x75, 0x97, 0xa0, 0x5d, 0xac, 0xc2, 0x0b, 0x62, 0x9b, 0x0e, 0x65, 0x84, 0x7a,
+	0x2e, 0xd5, 0x6d, 0xa6, 0x1b, 0xae, 0xe5, 0xd8, 0x48, 0xc6, 0x2f, 0xa1, 0xe5, 0x50, 0x93, 0xd0,
+	0x67, 0x0b, 0x15, 0x7c, 0x0c, 0x47, 0x26, 0x19, 0x58, 0xa9, 0x37, 0x46, 0xc8, 0xb5, 0x67, 0xd9,
+	0x7d, 0x07, 0x55, 0x53, 0xda, 0xb8, 0xd2, 0x2d, 0xdb, 0x70, 0x4c, 0xe2, 0xdd, 0xea, 0xc6, 0x75,
+	0x5a, 0x5f, 0x39, 0x0b, 0x01, 0x6f, 0xa5, 0x6e, 0xa5, 0x6f, 0x5b, 0xdc, 0x04, 0x60, 0xd6, 0xa5,
+	0xad, 0xbb, 0x43, 0x4a, 0x18, 0xda, 0xc1, 0x87, 0xd0, 0x18, 0xe8, 0xcc, 0xf5, 0x4a, 0xab, 0x2f,
+	0xa1, 0xb5, 0x51, 0x95, 0x79, 0x7d, 0x6b, 0xe0, 0x12, 0x8a, 0x76, 0xd3, 0xcb, 0xe5, 0xb6, 0x90,
+	0x8c, 0x31, 0x34, 0x6f, 0xee, 0x0c, 0x23, 0xdb, 0x36, 0xb0, 0x0c, 0x97, 0xa1, 0x4a, 0x8f, 0xc1,
+	0xa7, 0x61, 0x34, 0xed, 0xcc, 0x9e, 0x56, 0x3c, 0x5a, 0xf0, 0xc9, 0x94, 0x47, 0x9d, 0x7b, 0x7f,
+	0x14, 0xcd, 0xc7, 0xe2, 0x7d, 0x13, 0xe7, 0x03, 0xfb, 0xf6, 0x7c, 0x3a, 0x4f, 0x66, 0xeb, 0x51,
+	0x0a, 0xbb, 0x1b, 0xe2, 0xae, 0x10, 0x8b, 0x8f, 0x49, 0x9c, 0x7f, 0x70, 0x46, 0x4a, 0x06, 0xbf,
+	0xfc, 0x77, 0x00, 0x32, 0xb9, 0xef, 0xc9, 0x88, 0x06, 0x00, 0x00,
 }
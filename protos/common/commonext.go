@@ -0,0 +1,184 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/msp"
+)
+
+// This file describes the marshaled messages held by the bytes fields of the
+// messages of this package, for common/tools/protolator
+
+// payloadDataProtos holds the factories of the messages carried by the data
+// of payloads, by header type
+var payloadDataProtos = map[HeaderType]func() proto.Message{
+	HeaderType_CONFIG:              func() proto.Message { return &ConfigEnvelope{} },
+	HeaderType_CONFIG_UPDATE:       func() proto.Message { return &ConfigUpdateEnvelope{} },
+	HeaderType_ORDERER_TRANSACTION: func() proto.Message { return &Envelope{} },
+}
+
+// RegisterPayloadDataProto registers the message carried by the data of the
+// payloads of a header type. It is meant to be called from the init function
+// of the packages defining the messages, such as protos/peer for endorser
+// transactions. The data of payloads of unregistered types is left as bytes
+func RegisterPayloadDataProto(headerType HeaderType, factory func() proto.Message) {
+	payloadDataProtos[headerType] = factory
+}
+
+func unknownFieldErr(msg proto.Message, name string) error {
+	return fmt.Errorf("%T has no opaque field %s", msg, name)
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (e *Envelope) StaticallyOpaqueFields() []string {
+	return []string{"payload"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (e *Envelope) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "payload" {
+		return nil, unknownFieldErr(e, name)
+	}
+	return &Payload{}, nil
+}
+
+// VariablyOpaqueFields implements protolator.VariablyOpaqueFieldProto
+func (p *Payload) VariablyOpaqueFields() []string {
+	return []string{"data"}
+}
+
+// VariablyOpaqueFieldProto implements protolator.VariablyOpaqueFieldProto
+func (p *Payload) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "data" {
+		return nil, unknownFieldErr(p, name)
+	}
+	if p.Header == nil {
+		return nil, fmt.Errorf("cannot determine the type of the payload data without header")
+	}
+	chdr := &ChannelHeader{}
+	if err := proto.Unmarshal(p.Header.ChannelHeader, chdr); err != nil {
+		return nil, fmt.Errorf("cannot determine the type of the payload data: %s", err)
+	}
+	factory, ok := payloadDataProtos[HeaderType(chdr.Type)]
+	if !ok {
+		return nil, nil
+	}
+	return factory(), nil
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (h *Header) StaticallyOpaqueFields() []string {
+	return []string{"channel_header", "signature_header"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (h *Header) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	switch name {
+	case "channel_header":
+		return &ChannelHeader{}, nil
+	case "signature_header":
+		return &SignatureHeader{}, nil
+	}
+	return nil, unknownFieldErr(h, name)
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (sh *SignatureHeader) StaticallyOpaqueFields() []string {
+	return []string{"creator"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (sh *SignatureHeader) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "creator" {
+		return nil, unknownFieldErr(sh, name)
+	}
+	return &msp.SerializedIdentity{}, nil
+}
+
+// StaticallyOpaqueSliceFields implements protolator.StaticallyOpaqueSliceFieldProto
+func (bd *BlockData) StaticallyOpaqueSliceFields() []string {
+	return []string{"data"}
+}
+
+// StaticallyOpaqueSliceFieldProto implements protolator.StaticallyOpaqueSliceFieldProto
+func (bd *BlockData) StaticallyOpaqueSliceFieldProto(name string, index int) (proto.Message, error) {
+	if name != "data" {
+		return nil, unknownFieldErr(bd, name)
+	}
+	return &Envelope{}, nil
+}
+
+// VariablyOpaqueSliceFields implements protolator.VariablyOpaqueSliceFieldProto
+func (bm *BlockMetadata) VariablyOpaqueSliceFields() []string {
+	return []string{"metadata"}
+}
+
+// VariablyOpaqueSliceFieldProto implements protolator.VariablyOpaqueSliceFieldProto
+// The transactions filter, and the metadata the peer adds past the indexes
+// defined here, are left as bytes
+func (bm *BlockMetadata) VariablyOpaqueSliceFieldProto(name string, index int) (proto.Message, error) {
+	if name != "metadata" {
+		return nil, unknownFieldErr(bm, name)
+	}
+	switch BlockMetadataIndex(index) {
+	case BlockMetadataIndex_SIGNATURES, BlockMetadataIndex_ORDERER:
+		return &Metadata{}, nil
+	case BlockMetadataIndex_LAST_CONFIG:
+		return &lastConfigMetadata{&Metadata{}}, nil
+	}
+	return nil, nil
+}
+
+// lastConfigMetadata decorates the Metadata of the LAST_CONFIG index, whose
+// value is a LastConfig
+type lastConfigMetadata struct {
+	*Metadata
+}
+
+// Underlying implements protolator.DecoratedProto
+func (lcm *lastConfigMetadata) Underlying() proto.Message {
+	return lcm.Metadata
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (lcm *lastConfigMetadata) StaticallyOpaqueFields() []string {
+	return []string{"value"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (lcm *lastConfigMetadata) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "value" {
+		return nil, unknownFieldErr(lcm.Metadata, name)
+	}
+	return &LastConfig{}, nil
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (ms *MetadataSignature) StaticallyOpaqueFields() []string {
+	return []string{"signature_header"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (ms *MetadataSignature) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "signature_header" {
+		return nil, unknownFieldErr(ms, name)
+	}
+	return &SignatureHeader{}, nil
+}
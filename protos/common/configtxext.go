@@ -0,0 +1,210 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/msp"
+)
+
+// Types of the config groups, which determine the messages of their values
+const (
+	ChannelGroupType        = "Channel"
+	OrdererGroupType        = "Orderer"
+	OrdererOrgGroupType     = "OrdererOrg"
+	ApplicationGroupType    = "Application"
+	ApplicationOrgGroupType = "ApplicationOrg"
+)
+
+// configValueProtos holds the factories of the messages of the config values,
+// by group type and key
+var configValueProtos = map[string]map[string]func() proto.Message{
+	ChannelGroupType: {
+		"HashingAlgorithm":          func() proto.Message { return &HashingAlgorithm{} },
+		"BlockDataHashingStructure": func() proto.Message { return &BlockDataHashingStructure{} },
+		"OrdererAddresses":          func() proto.Message { return &OrdererAddresses{} },
+	},
+	OrdererOrgGroupType: {
+		"MSP":              func() proto.Message { return &msp.MSPConfig{} },
+		"OrdererEndpoints": func() proto.Message { return &OrdererAddresses{} },
+	},
+	ApplicationGroupType: {
+		"Capabilities": func() proto.Message { return &Capabilities{} },
+	},
+	ApplicationOrgGroupType: {
+		"MSP": func() proto.Message { return &msp.MSPConfig{} },
+	},
+}
+
+// RegisterConfigValueProto registers the message of the config values with
+// the key in the groups of the type. It is meant to be called from the init
+// function of the packages defining the messages, such as protos/orderer for
+// the orderer config. The values of unregistered keys are left as bytes
+func RegisterConfigValueProto(groupType string, key string, factory func() proto.Message) {
+	protos, ok := configValueProtos[groupType]
+	if !ok {
+		protos = map[string]func() proto.Message{}
+		configValueProtos[groupType] = protos
+	}
+	protos[key] = factory
+}
+
+// subGroupType returns the type of the group with the key in a group of the type
+func subGroupType(groupType string, key string) string {
+	switch groupType {
+	case ChannelGroupType:
+		switch key {
+		case OrdererGroupType:
+			return OrdererGroupType
+		case ApplicationGroupType:
+			return ApplicationGroupType
+		}
+	case OrdererGroupType:
+		return OrdererOrgGroupType
+	case ApplicationGroupType:
+		return ApplicationOrgGroupType
+	}
+	return ""
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (cue *ConfigUpdateEnvelope) StaticallyOpaqueFields() []string {
+	return []string{"config_update"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (cue *ConfigUpdateEnvelope) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "config_update" {
+		return nil, unknownFieldErr(cue, name)
+	}
+	return &ConfigUpdate{}, nil
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (cs *ConfigSignature) StaticallyOpaqueFields() []string {
+	return []string{"signature_header"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (cs *ConfigSignature) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "signature_header" {
+		return nil, unknownFieldErr(cs, name)
+	}
+	return &SignatureHeader{}, nil
+}
+
+func channelGroup(name string, base proto.Message) (*DynamicConfigGroup, error) {
+	cg, ok := base.(*ConfigGroup)
+	if !ok {
+		return nil, fmt.Errorf("field %s should hold a ConfigGroup, not %T", name, base)
+	}
+	return &DynamicConfigGroup{ConfigGroup: cg, groupType: ChannelGroupType}, nil
+}
+
+// DynamicFields implements protolator.DynamicFieldProto
+func (c *Config) DynamicFields() []string {
+	return []string{"channel_group"}
+}
+
+// DynamicFieldProto implements protolator.DynamicFieldProto
+func (c *Config) DynamicFieldProto(name string, base proto.Message) (proto.Message, error) {
+	if name != "channel_group" {
+		return nil, unknownFieldErr(c, name)
+	}
+	return channelGroup(name, base)
+}
+
+// DynamicFields implements protolator.DynamicFieldProto
+func (cu *ConfigUpdate) DynamicFields() []string {
+	return []string{"read_set", "write_set"}
+}
+
+// DynamicFieldProto implements protolator.DynamicFieldProto
+func (cu *ConfigUpdate) DynamicFieldProto(name string, base proto.Message) (proto.Message, error) {
+	if name != "read_set" && name != "write_set" {
+		return nil, unknownFieldErr(cu, name)
+	}
+	return channelGroup(name, base)
+}
+
+// DynamicConfigGroup decorates a ConfigGroup with its type, which determines
+// the messages of its values and the types of its groups
+type DynamicConfigGroup struct {
+	*ConfigGroup
+	groupType string
+}
+
+// Underlying implements protolator.DecoratedProto
+func (dcg *DynamicConfigGroup) Underlying() proto.Message {
+	return dcg.ConfigGroup
+}
+
+// DynamicMapFields implements protolator.DynamicMapFieldProto
+func (dcg *DynamicConfigGroup) DynamicMapFields() []string {
+	return []string{"groups", "values"}
+}
+
+// DynamicMapFieldProto implements protolator.DynamicMapFieldProto
+func (dcg *DynamicConfigGroup) DynamicMapFieldProto(name string, key string, base proto.Message) (proto.Message, error) {
+	switch name {
+	case "groups":
+		cg, ok := base.(*ConfigGroup)
+		if !ok {
+			return nil, fmt.Errorf("groups should hold ConfigGroups, not %T", base)
+		}
+		return &DynamicConfigGroup{ConfigGroup: cg, groupType: subGroupType(dcg.groupType, key)}, nil
+	case "values":
+		cv, ok := base.(*ConfigValue)
+		if !ok {
+			return nil, fmt.Errorf("values should hold ConfigValues, not %T", base)
+		}
+		return &DynamicConfigValue{ConfigValue: cv, groupType: dcg.groupType, key: key}, nil
+	}
+	return nil, unknownFieldErr(dcg.ConfigGroup, name)
+}
+
+// DynamicConfigValue decorates a ConfigValue with its key and the type of its
+// group, which determine the message of its value
+type DynamicConfigValue struct {
+	*ConfigValue
+	groupType string
+	key       string
+}
+
+// Underlying implements protolator.DecoratedProto
+func (dcv *DynamicConfigValue) Underlying() proto.Message {
+	return dcv.ConfigValue
+}
+
+// VariablyOpaqueFields implements protolator.VariablyOpaqueFieldProto
+func (dcv *DynamicConfigValue) VariablyOpaqueFields() []string {
+	return []string{"value"}
+}
+
+// VariablyOpaqueFieldProto implements protolator.VariablyOpaqueFieldProto
+func (dcv *DynamicConfigValue) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "value" {
+		return nil, unknownFieldErr(dcv.ConfigValue, name)
+	}
+	factory, ok := configValueProtos[dcv.groupType][dcv.key]
+	if !ok {
+		return nil, nil
+	}
+	return factory(), nil
+}
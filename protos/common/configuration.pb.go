@@ -49,28 +49,68 @@ func (m *OrdererAddresses) String() string            { return proto.CompactText
 func (*OrdererAddresses) ProtoMessage()               {}
 func (*OrdererAddresses) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{2} }
 
+// Consortium represents the consortium context in which the channel was created
+type Consortium struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *Consortium) Reset()                    { *m = Consortium{} }
+func (m *Consortium) String() string            { return proto.CompactTextString(m) }
+func (*Consortium) ProtoMessage()               {}
+func (*Consortium) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{3} }
+
+// Capabilities is encoded into the configuration transaction as a configuration item of type Application
+// with a Key of "Capabilities" and a Value of Capabilities as marshaled protobuf bytes. It holds, by name,
+// the capabilities the channel requires of its peers, which change the rules they validate transactions with
+type Capabilities struct {
+	Capabilities map[string]*Capability `protobuf:"bytes,1,rep,name=capabilities" json:"capabilities,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *Capabilities) Reset()         { *m = Capabilities{} }
+func (m *Capabilities) String() string { return proto.CompactTextString(m) }
+func (*Capabilities) ProtoMessage()    {}
+
+func (m *Capabilities) GetCapabilities() map[string]*Capability {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+// Capability is empty, a capability is required by the mere presence of its name in Capabilities
+type Capability struct {
+}
+
+func (m *Capability) Reset()         { *m = Capability{} }
+func (m *Capability) String() string { return proto.CompactTextString(m) }
+func (*Capability) ProtoMessage()    {}
+
 func init() {
 	proto.RegisterType((*HashingAlgorithm)(nil), "common.HashingAlgorithm")
 	proto.RegisterType((*BlockDataHashingStructure)(nil), "common.BlockDataHashingStructure")
 	proto.RegisterType((*OrdererAddresses)(nil), "common.OrdererAddresses")
+	proto.RegisterType((*Consortium)(nil), "common.Consortium")
+	proto.RegisterType((*Capabilities)(nil), "common.Capabilities")
+	proto.RegisterType((*Capability)(nil), "common.Capability")
 }
 
 func init() { proto.RegisterFile("common/configuration.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 218 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x8e, 0x41, 0x4b, 0x03, 0x31,
-	0x10, 0x85, 0x59, 0xd4, 0xc2, 0x0e, 0x08, 0x25, 0x78, 0xa8, 0xe2, 0xa1, 0x2c, 0x22, 0x05, 0x61,
-	0xa3, 0xf8, 0x0b, 0x5a, 0x3c, 0x78, 0x13, 0xb6, 0x37, 0x6f, 0xd9, 0x64, 0x9a, 0x04, 0x77, 0x33,
-	0x65, 0x32, 0x8b, 0xf8, 0xef, 0xc5, 0x8d, 0x62, 0x6f, 0xf3, 0xcd, 0xfb, 0x1e, 0x3c, 0xb8, 0xb1,
-	0x34, 0x8e, 0x94, 0xb4, 0xa5, 0x74, 0x88, 0x7e, 0x62, 0x23, 0x91, 0x52, 0x7b, 0x64, 0x12, 0x52,
-	0x8b, 0x92, 0x35, 0xf7, 0xb0, 0x7c, 0x35, 0x39, 0xc4, 0xe4, 0xb7, 0x83, 0x27, 0x8e, 0x12, 0x46,
-	0xa5, 0xe0, 0x3c, 0x99, 0x11, 0x57, 0xd5, 0xba, 0xda, 0xd4, 0xdd, 0x7c, 0x37, 0x4f, 0x70, 0xbd,
-	0x1b, 0xc8, 0x7e, 0xbc, 0x18, 0x31, 0xbf, 0x85, 0xbd, 0xf0, 0x64, 0x65, 0x62, 0x54, 0x57, 0x70,
-	0xf1, 0x19, 0x9d, 0x84, 0xb9, 0x71, 0xd9, 0x15, 0x68, 0x1e, 0x61, 0xf9, 0xc6, 0x0e, 0x19, 0x79,
-	0xeb, 0x1c, 0x63, 0xce, 0x98, 0xd5, 0x2d, 0xd4, 0xe6, 0x0f, 0x56, 0xd5, 0xfa, 0x6c, 0x53, 0x77,
-	0xff, 0x8f, 0xdd, 0x1e, 0xee, 0x88, 0x7d, 0x1b, 0xbe, 0x8e, 0xc8, 0x03, 0x3a, 0x8f, 0xdc, 0x1e,
-	0x4c, 0xcf, 0xd1, 0x96, 0xd1, 0xb9, 0x2d, 0xa3, 0xdf, 0x1f, 0x7c, 0x94, 0x30, 0xf5, 0x3f, 0xa8,
-	0x4f, 0x64, 0x5d, 0x64, 0x5d, 0x64, 0x5d, 0xe4, 0x7e, 0x31, 0xe3, 0xf3, 0x77, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0xf0, 0x7e, 0xef, 0xa1, 0x0e, 0x01, 0x00, 0x00,
+	// 227 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x8f, 0xc1, 0x4a, 0x03, 0x31,
+	0x10, 0x86, 0x59, 0xd4, 0xc2, 0x0e, 0x08, 0x25, 0x78, 0xa8, 0xe2, 0x61, 0x59, 0x44, 0x0a, 0xc2,
+	0x46, 0xf1, 0x09, 0x5a, 0x3d, 0x78, 0x13, 0xb6, 0x37, 0x6f, 0xd9, 0x24, 0x4d, 0x82, 0xbb, 0x99,
+	0x32, 0x99, 0x20, 0xbe, 0xbd, 0xb8, 0x51, 0xf4, 0xd0, 0xdb, 0x7c, 0x33, 0xdf, 0x0f, 0xf3, 0xc3,
+	0x95, 0xc6, 0x69, 0xc2, 0x28, 0x35, 0xc6, 0x7d, 0x70, 0x99, 0x14, 0x07, 0x8c, 0xdd, 0x81, 0x90,
+	0x51, 0x2c, 0xca, 0xad, 0xbd, 0x85, 0xe5, 0x8b, 0x4a, 0x3e, 0x44, 0xb7, 0x19, 0x1d, 0x52, 0x60,
+	0x3f, 0x09, 0x01, 0xa7, 0x51, 0x4d, 0x76, 0x55, 0x35, 0xd5, 0xba, 0xee, 0xe7, 0xb9, 0x7d, 0x80,
+	0xcb, 0xed, 0x88, 0xfa, 0xfd, 0x59, 0xb1, 0xfa, 0x09, 0xec, 0x98, 0xb2, 0xe6, 0x4c, 0x56, 0x5c,
+	0xc0, 0xd9, 0x47, 0x30, 0xec, 0xe7, 0xc4, 0x79, 0x5f, 0xa0, 0xbd, 0x87, 0xe5, 0x2b, 0x19, 0x4b,
+	0x96, 0x36, 0xc6, 0x90, 0x4d, 0xc9, 0x26, 0x71, 0x0d, 0xb5, 0xfa, 0x85, 0x55, 0xd5, 0x9c, 0xac,
+	0xeb, 0xfe, 0x6f, 0xd1, 0x36, 0x00, 0x4f, 0x18, 0x13, 0x12, 0x87, 0x7c, 0xf4, 0x8d, 0xed, 0x0e,
+	0x6e, 0x90, 0x5c, 0xe7, 0x3f, 0x0f, 0x96, 0x46, 0x6b, 0x9c, 0xa5, 0x6e, 0xaf, 0x06, 0x0a, 0xba,
+	0xd4, 0x4a, 0x5d, 0xa9, 0xf5, 0x76, 0xe7, 0x02, 0xfb, 0x3c, 0x7c, 0xa3, 0xfc, 0x27, 0xcb, 0x22,
+	0xcb, 0x22, 0xcb, 0x22, 0x0f, 0x8b, 0x19, 0x1f, 0xbf, 0x06, 0x00, 0xb6, 0x15, 0xb9, 0xb4, 0x30,
+	0x01, 0x00, 0x00,
 }
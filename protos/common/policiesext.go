@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+// VariablyOpaqueFields implements protolator.VariablyOpaqueFieldProto
+func (p *Policy) VariablyOpaqueFields() []string {
+	return []string{"policy"}
+}
+
+// VariablyOpaqueFieldProto implements protolator.VariablyOpaqueFieldProto
+// MSP policies are left as bytes
+func (p *Policy) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "policy" {
+		return nil, unknownFieldErr(p, name)
+	}
+	switch Policy_PolicyType(p.Type) {
+	case Policy_SIGNATURE:
+		return &SignaturePolicyEnvelope{}, nil
+	case Policy_IMPLICIT_META:
+		return &ImplicitMetaPolicy{}, nil
+	}
+	return nil, nil
+}
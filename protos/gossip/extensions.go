@@ -340,6 +340,9 @@ type ConnectionInfo struct {
 	ID       common.PKIidType
 	Auth     *AuthInfo
 	Identity api.PeerIdentityType
+	// TLSCertHash is the hash of the TLS certificate the remote peer
+	// presented and signed at the connection time, nil if TLS isn't used
+	TLSCertHash []byte
 }
 
 func (connInfo *ConnectionInfo) IsAuthenticated() bool {
@@ -1016,9 +1016,10 @@ func (*Payload) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14}
 // AliveMessage is sent to inform remote peers
 // of a peer's existence and activity
 type AliveMessage struct {
-	Membership *Member   `protobuf:"bytes,1,opt,name=membership" json:"membership,omitempty"`
-	Timestamp  *PeerTime `protobuf:"bytes,2,opt,name=timestamp" json:"timestamp,omitempty"`
-	Identity   []byte    `protobuf:"bytes,4,opt,name=identity,proto3" json:"identity,omitempty"`
+	Membership  *Member   `protobuf:"bytes,1,opt,name=membership" json:"membership,omitempty"`
+	Timestamp   *PeerTime `protobuf:"bytes,2,opt,name=timestamp" json:"timestamp,omitempty"`
+	Identity    []byte    `protobuf:"bytes,4,opt,name=identity,proto3" json:"identity,omitempty"`
+	TlsCertHash []byte    `protobuf:"bytes,5,opt,name=tls_cert_hash,json=tlsCertHash,proto3" json:"tls_cert_hash,omitempty"`
 }
 
 func (m *AliveMessage) Reset()                    { *m = AliveMessage{} }
@@ -1341,90 +1342,91 @@ var _Gossip_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("gossip/message.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1352 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x57, 0xdd, 0x6f, 0xe3, 0x44,
-	0x10, 0x8f, 0x9b, 0x4f, 0x4f, 0x3e, 0x9a, 0x6e, 0x7b, 0x60, 0xca, 0x71, 0xaa, 0x2c, 0xee, 0x54,
-	0xe8, 0x91, 0x9e, 0x7a, 0x80, 0x4e, 0x3a, 0x81, 0x94, 0x36, 0xa1, 0x09, 0x5c, 0xd2, 0xca, 0xcd,
-	0x09, 0x8e, 0x17, 0x6b, 0x13, 0x6f, 0x1d, 0x73, 0xf6, 0xda, 0xf5, 0x6e, 0x0e, 0xfa, 0x08, 0x8f,
-	0x3c, 0xf0, 0xcc, 0x9f, 0x8b, 0xbc, 0x6b, 0x3b, 0x76, 0xdd, 0x9e, 0xd4, 0x93, 0x78, 0xf3, 0x7c,
-	0xfc, 0x66, 0x66, 0x67, 0x67, 0x67, 0xc6, 0xb0, 0x63, 0xfb, 0x8c, 0x39, 0xc1, 0xa1, 0x47, 0x18,
-	0xc3, 0x36, 0xe9, 0x05, 0xa1, 0xcf, 0x7d, 0x54, 0x93, 0x5c, 0xfd, 0x2f, 0x05, 0x1a, 0x43, 0xfa,
-	0x8e, 0xb8, 0x7e, 0x40, 0x90, 0x06, 0xf5, 0x00, 0x5f, 0xbb, 0x3e, 0xb6, 0x34, 0x65, 0x4f, 0xd9,
-	0x6f, 0x19, 0x09, 0x89, 0x1e, 0x82, 0xca, 0x1c, 0x9b, 0x62, 0xbe, 0x0a, 0x89, 0xb6, 0x21, 0x64,
-	0x6b, 0x06, 0xfa, 0x1e, 0x3a, 0x8c, 0x2c, 0x42, 0xc2, 0x13, 0x4b, 0x5a, 0x79, 0x4f, 0xd9, 0x6f,
-	0x1e, 0x7d, 0xd4, 0x93, 0x5e, 0x7a, 0x17, 0x39, 0xa9, 0x71, 0x43, 0x5b, 0x1f, 0x41, 0x27, 0xaf,
-	0xf1, 0xa1, 0x91, 0xe8, 0x7d, 0xa8, 0x49, 0x4b, 0xe8, 0x29, 0x74, 0x1d, 0xca, 0x49, 0x48, 0xb1,
-	0x3b, 0xa4, 0x56, 0xe0, 0x3b, 0x94, 0x0b, 0x53, 0xea, 0xa8, 0x64, 0x14, 0x24, 0xc7, 0x2a, 0xd4,
-	0x17, 0x3e, 0xe5, 0x84, 0x72, 0xfd, 0x5f, 0x15, 0xda, 0xa7, 0x22, 0xec, 0x89, 0xcc, 0x18, 0xda,
-	0x81, 0x2a, 0xf5, 0xe9, 0x82, 0x08, 0x7c, 0xc5, 0x90, 0x44, 0x14, 0xe2, 0x62, 0x89, 0x29, 0x25,
-	0x6e, 0x1c, 0x46, 0x42, 0xa2, 0x03, 0x28, 0x73, 0x6c, 0x8b, 0x1c, 0x74, 0x8e, 0x3e, 0x49, 0x72,
-	0x90, 0xb3, 0xd9, 0x9b, 0x61, 0xdb, 0x88, 0xb4, 0xd0, 0x73, 0x50, 0xb1, 0xeb, 0xbc, 0x23, 0xa6,
-	0xc7, 0x6c, 0xad, 0x2a, 0xd2, 0xb6, 0x93, 0x40, 0xfa, 0x91, 0x20, 0x46, 0x8c, 0x4a, 0x46, 0x43,
-	0x28, 0x4e, 0x98, 0x8d, 0xbe, 0x86, 0xba, 0x47, 0x3c, 0x33, 0x24, 0x57, 0x5a, 0x4d, 0x40, 0x52,
-	0x2f, 0x13, 0xe2, 0xcd, 0x49, 0xc8, 0x96, 0x4e, 0x60, 0x90, 0xab, 0x15, 0x61, 0x7c, 0x54, 0x32,
-	0x6a, 0x1e, 0xf1, 0x0c, 0x72, 0x85, 0xbe, 0x49, 0x50, 0x4c, 0xab, 0x0b, 0xd4, 0xee, 0x6d, 0x28,
-	0x16, 0xf8, 0x94, 0x91, 0x14, 0xc6, 0xd0, 0x33, 0x68, 0x58, 0x98, 0x63, 0x11, 0x60, 0x43, 0xe0,
-	0xb6, 0x13, 0xdc, 0x00, 0x73, 0xbc, 0x8e, 0xaf, 0x1e, 0xa9, 0x45, 0xe1, 0x1d, 0x40, 0x75, 0x49,
-	0x5c, 0xd7, 0xd7, 0xd4, 0xbc, 0xba, 0x4c, 0xc1, 0x28, 0x12, 0x8d, 0x4a, 0x86, 0xd4, 0x41, 0x87,
-	0xb1, 0x79, 0xcb, 0xb1, 0x35, 0x10, 0xfa, 0x28, 0x6b, 0x7e, 0xe0, 0xd8, 0xf2, 0x14, 0xc2, 0xfa,
-	0xc0, 0xb1, 0xd3, 0x78, 0xa2, 0xd3, 0x37, 0x8b, 0xf1, 0xac, 0xcf, 0x2d, 0x10, 0xf2, 0xe0, 0x4d,
-	0x81, 0x58, 0x05, 0x16, 0xe6, 0x44, 0x6b, 0x15, 0xbd, 0xbc, 0x16, 0x92, 0x51, 0xc9, 0x00, 0x2b,
-	0xa5, 0xd0, 0x63, 0xa8, 0x12, 0x2f, 0xe0, 0xd7, 0x5a, 0x5b, 0x00, 0xda, 0x09, 0x60, 0x18, 0x31,
-	0xa3, 0x03, 0x08, 0x29, 0x3a, 0x80, 0xca, 0xc2, 0xa7, 0x54, 0xeb, 0x08, 0xad, 0x07, 0x89, 0xd6,
-	0x89, 0x4f, 0xe9, 0x90, 0x71, 0x3c, 0x77, 0x1d, 0xb6, 0x1c, 0x95, 0x0c, 0xa1, 0x84, 0x8e, 0x00,
-	0x18, 0xc7, 0x9c, 0x98, 0x0e, 0xbd, 0xf4, 0xb5, 0x4d, 0x01, 0xd9, 0x4a, 0x9f, 0x49, 0x24, 0x19,
-	0xd3, 0xcb, 0x28, 0x3b, 0x2a, 0x4b, 0x08, 0x74, 0x0c, 0x1d, 0x89, 0x61, 0x14, 0x07, 0x6c, 0xe9,
-	0x73, 0xad, 0x9b, 0xbf, 0xf4, 0x14, 0x77, 0x11, 0x2b, 0x8c, 0x4a, 0x46, 0x5b, 0x40, 0x12, 0x06,
-	0x9a, 0xc0, 0xf6, 0xda, 0xaf, 0x19, 0xac, 0x5c, 0x57, 0xe4, 0x6f, 0x4b, 0x18, 0x7a, 0x58, 0x30,
-	0x74, 0xbe, 0x72, 0xdd, 0x75, 0x22, 0xbb, 0xec, 0x06, 0x1f, 0xf5, 0x41, 0xda, 0x8f, 0x8c, 0x44,
-	0x4a, 0x1a, 0xca, 0x17, 0x94, 0x41, 0x3c, 0x9f, 0x13, 0x61, 0x6e, 0x6d, 0xa6, 0xc5, 0x32, 0x34,
-	0x1a, 0x24, 0xa7, 0x0a, 0xe3, 0x92, 0xd3, 0xb6, 0x85, 0x8d, 0x4f, 0x6f, 0xb5, 0x91, 0x56, 0x65,
-	0x9b, 0x65, 0x19, 0x51, 0x6e, 0x5c, 0x82, 0x2d, 0x59, 0xbc, 0xa2, 0x44, 0x77, 0xf2, 0xb9, 0x79,
-	0x95, 0x4a, 0xd7, 0x85, 0xda, 0x5e, 0x43, 0xa2, 0x72, 0x7d, 0x09, 0xed, 0x80, 0x90, 0xd0, 0x74,
-	0x2c, 0x42, 0xb9, 0xc3, 0xaf, 0xb5, 0x07, 0xf9, 0x67, 0x78, 0x4e, 0x48, 0x38, 0x8e, 0x65, 0xd1,
-	0x31, 0x82, 0x0c, 0xad, 0x9b, 0x50, 0x9e, 0x61, 0x1b, 0xb5, 0x41, 0x7d, 0x3d, 0x1d, 0x0c, 0x7f,
-	0x18, 0x4f, 0x87, 0x83, 0x6e, 0x09, 0xa9, 0x50, 0x1d, 0x4e, 0xce, 0x67, 0x6f, 0xba, 0x0a, 0x6a,
-	0x41, 0xe3, 0xcc, 0x38, 0x35, 0xcf, 0xa6, 0xaf, 0xde, 0x74, 0x37, 0x22, 0xbd, 0x93, 0x51, 0x7f,
-	0x2a, 0xc9, 0x32, 0xea, 0x42, 0x4b, 0x90, 0xfd, 0xe9, 0xc0, 0x3c, 0x33, 0x4e, 0xbb, 0x15, 0xb4,
-	0x09, 0x4d, 0xa9, 0x60, 0x08, 0x46, 0x35, 0xdb, 0x9a, 0xfe, 0x51, 0x40, 0x4d, 0xaf, 0x08, 0xed,
-	0x42, 0xc3, 0x23, 0x1c, 0x47, 0x05, 0x1b, 0x37, 0xc9, 0x94, 0x46, 0x3d, 0x50, 0xb9, 0xe3, 0x11,
-	0xc6, 0xb1, 0x17, 0x88, 0xf6, 0xd4, 0x3c, 0xea, 0x66, 0x8f, 0x33, 0x73, 0x3c, 0x62, 0xac, 0x55,
-	0xd0, 0x03, 0xa8, 0x05, 0x6f, 0x1d, 0xd3, 0xb1, 0x44, 0xd7, 0x6a, 0x19, 0xd5, 0xe0, 0xad, 0x33,
-	0xb6, 0xd0, 0x23, 0x80, 0xb8, 0xa9, 0x4d, 0xfa, 0x27, 0x5a, 0x45, 0x88, 0x32, 0x1c, 0xbd, 0x0f,
-	0x5b, 0x85, 0xda, 0x43, 0x4f, 0xa1, 0x41, 0x5c, 0xe2, 0x11, 0xca, 0x99, 0xa6, 0xec, 0x95, 0xb3,
-	0xae, 0xd3, 0x09, 0x90, 0x6a, 0xe8, 0xdf, 0xc2, 0xce, 0x6d, 0x55, 0x77, 0xc3, 0xb5, 0x52, 0x70,
-	0x3d, 0x85, 0x76, 0xee, 0x85, 0x65, 0x8e, 0xa0, 0x64, 0x8f, 0x80, 0xa0, 0xb2, 0x20, 0x21, 0x8f,
-	0x7b, 0xb4, 0xf8, 0x8e, 0x78, 0x4b, 0xcc, 0x96, 0xf1, 0x59, 0xc5, 0xb7, 0xfe, 0x1a, 0x5a, 0xd9,
-	0x7b, 0xbe, 0x8f, 0xb9, 0xec, 0x45, 0x94, 0xf3, 0x17, 0xa1, 0x7b, 0xd0, 0xcc, 0x34, 0xa5, 0xbb,
-	0x47, 0x89, 0x25, 0xda, 0x1c, 0xd3, 0x36, 0xf6, 0xca, 0xfb, 0xaa, 0x91, 0x90, 0xa8, 0x07, 0x0d,
-	0x8f, 0xd9, 0x26, 0xbf, 0x8e, 0x67, 0x6a, 0x67, 0xdd, 0xeb, 0xa2, 0x64, 0x4d, 0x98, 0x3d, 0xbb,
-	0x0e, 0x88, 0x51, 0xf7, 0xe4, 0x87, 0xee, 0x43, 0x33, 0xd3, 0x64, 0xef, 0x70, 0x97, 0x8d, 0x77,
-	0xa3, 0x50, 0x38, 0xf7, 0x73, 0xf8, 0x07, 0xc0, 0xba, 0x7f, 0xde, 0xe1, 0xef, 0x73, 0xa8, 0xc4,
-	0xbe, 0x6e, 0x2f, 0x86, 0xca, 0x07, 0x79, 0x76, 0xa5, 0x67, 0x39, 0x1f, 0xfe, 0xf7, 0xc4, 0xbe,
-	0x90, 0xf7, 0x98, 0xac, 0x04, 0x5f, 0xe4, 0xf7, 0x93, 0xe6, 0xd1, 0x66, 0x8a, 0x96, 0xec, 0x74,
-	0x61, 0xd1, 0x7f, 0x84, 0x7a, 0xcc, 0x43, 0x1f, 0x43, 0x9d, 0x91, 0x2b, 0x93, 0xae, 0xbc, 0x38,
-	0xcc, 0x1a, 0x23, 0x57, 0xd3, 0x95, 0x97, 0x16, 0x64, 0x74, 0x1b, 0xaa, 0x2c, 0xc8, 0x88, 0x97,
-	0xa9, 0x28, 0xf1, 0xad, 0xff, 0xad, 0x40, 0x2b, 0xbb, 0x14, 0xa0, 0x1e, 0x80, 0x97, 0xce, 0xee,
-	0x38, 0x94, 0x4e, 0x7e, 0xaa, 0x1b, 0x19, 0x8d, 0x7b, 0xf7, 0x85, 0x5d, 0x68, 0xa4, 0x5d, 0x51,
-	0x3e, 0xff, 0x94, 0xd6, 0xff, 0x54, 0x60, 0xab, 0xd0, 0x5d, 0xef, 0x7a, 0x37, 0xf7, 0x75, 0xfc,
-	0x18, 0x3a, 0x0e, 0x33, 0x2d, 0xb2, 0x70, 0x71, 0x88, 0xb9, 0xe3, 0x53, 0x91, 0x87, 0x86, 0xd1,
-	0x76, 0xd8, 0x60, 0xcd, 0xd4, 0x8f, 0xa1, 0x91, 0xa0, 0xd1, 0x67, 0x00, 0x0e, 0x5d, 0x44, 0xd9,
-	0x9d, 0x93, 0x30, 0x4e, 0xb0, 0xea, 0xd0, 0xc5, 0x54, 0x30, 0xb2, 0xc9, 0xdf, 0xc8, 0x26, 0x5f,
-	0xbf, 0x84, 0xad, 0xc2, 0xd6, 0x84, 0x5e, 0x42, 0x97, 0x11, 0xf7, 0x52, 0x8c, 0xcb, 0xd0, 0x93,
-	0x11, 0x28, 0xf9, 0xb0, 0xd3, 0xfa, 0xdd, 0x8c, 0x34, 0xc7, 0x6b, 0xc5, 0xa8, 0x18, 0xdf, 0x52,
-	0xff, 0x77, 0x2a, 0x8a, 0xae, 0x65, 0x48, 0x42, 0x9f, 0x03, 0x2a, 0xee, 0x59, 0xe8, 0x09, 0x54,
-	0xc5, 0x5a, 0x77, 0x67, 0xab, 0x94, 0x62, 0xf1, 0x88, 0x08, 0xb6, 0xde, 0xf3, 0x88, 0x08, 0xb6,
-	0xf4, 0x9f, 0xa1, 0x26, 0x7d, 0x44, 0x37, 0x47, 0x72, 0x7b, 0xaf, 0x91, 0xd2, 0xef, 0x6d, 0x00,
-	0xb7, 0x4f, 0x02, 0xbd, 0x0e, 0x55, 0xb1, 0xf6, 0xe8, 0xbf, 0x00, 0x2a, 0x0e, 0x77, 0xa4, 0x8b,
-	0x7d, 0x20, 0xe4, 0x66, 0xbe, 0xbe, 0x9b, 0x82, 0x79, 0x21, 0x8b, 0xfc, 0x11, 0x34, 0x09, 0xb5,
-	0xcc, 0xfc, 0x25, 0xa8, 0x84, 0x5a, 0x52, 0xae, 0x1f, 0xc3, 0xf6, 0x2d, 0x23, 0x1f, 0x1d, 0x40,
-	0x23, 0x7e, 0x4a, 0xc9, 0x38, 0x29, 0xbc, 0xb5, 0x54, 0xe1, 0xcb, 0xef, 0xa0, 0x99, 0x79, 0xbe,
-	0x37, 0xa7, 0x72, 0x1b, 0xd4, 0xe3, 0x57, 0x67, 0x27, 0x3f, 0x99, 0x93, 0x8b, 0xd3, 0xae, 0x12,
-	0x0d, 0xdf, 0xf1, 0x60, 0x38, 0x9d, 0x8d, 0x67, 0x6f, 0x04, 0x67, 0xe3, 0xe8, 0x37, 0xa8, 0xc9,
-	0xf6, 0x89, 0x5e, 0x40, 0x4b, 0x7e, 0x5d, 0xf0, 0x90, 0x60, 0x0f, 0x15, 0x12, 0xbe, 0x5b, 0xe0,
-	0xe8, 0xa5, 0x7d, 0xe5, 0x99, 0x82, 0x9e, 0x40, 0xe5, 0xdc, 0xa1, 0x36, 0xca, 0xaf, 0x8b, 0xbb,
-	0x79, 0x52, 0x2f, 0x1d, 0x7f, 0xf5, 0xeb, 0x81, 0xed, 0xf0, 0xe5, 0x6a, 0xde, 0x5b, 0xf8, 0xde,
-	0xe1, 0xf2, 0x3a, 0x20, 0xa1, 0x4b, 0x2c, 0x9b, 0x84, 0x87, 0x97, 0x78, 0x1e, 0x3a, 0x8b, 0x43,
-	0xf1, 0xa3, 0xc6, 0x0e, 0x25, 0x6c, 0x5e, 0x13, 0xe4, 0xf3, 0xff, 0x02, 0x00, 0x00, 0xff, 0xff,
-	0xd6, 0x2d, 0x62, 0x5a, 0xcf, 0x0d, 0x00, 0x00,
+	// 1367 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x17, 0x6d, 0x6f, 0xdb, 0x44,
+	0x38, 0x6e, 0xf3, 0xe6, 0x27, 0x2f, 0x4d, 0xaf, 0x1d, 0x98, 0x32, 0xa6, 0xca, 0x62, 0x53, 0xa1,
+	0x23, 0x9d, 0x3a, 0x40, 0x93, 0x26, 0x90, 0xda, 0x26, 0x34, 0x85, 0x25, 0xad, 0xdc, 0x4e, 0x30,
+	0xbe, 0x58, 0xd7, 0xf8, 0xea, 0x98, 0xd9, 0x67, 0xd7, 0x77, 0x19, 0xf4, 0x23, 0xfc, 0x00, 0x3e,
+	0xf3, 0x2b, 0xf8, 0x8d, 0xe8, 0xee, 0x6c, 0xc7, 0xae, 0xd3, 0x49, 0x9d, 0xc4, 0x37, 0x3f, 0xef,
+	0xcf, 0x3d, 0xef, 0x86, 0x4d, 0x37, 0x64, 0xcc, 0x8b, 0xf6, 0x02, 0xc2, 0x18, 0x76, 0x49, 0x3f,
+	0x8a, 0x43, 0x1e, 0xa2, 0xba, 0xc2, 0x9a, 0x7f, 0x69, 0xd0, 0x1c, 0xd2, 0x77, 0xc4, 0x0f, 0x23,
+	0x82, 0x0c, 0x68, 0x44, 0xf8, 0xc6, 0x0f, 0xb1, 0x63, 0x68, 0xdb, 0xda, 0x4e, 0xdb, 0x4a, 0x41,
+	0xf4, 0x10, 0x74, 0xe6, 0xb9, 0x14, 0xf3, 0x79, 0x4c, 0x8c, 0x15, 0x49, 0x5b, 0x20, 0xd0, 0xf7,
+	0xd0, 0x65, 0x64, 0x1a, 0x13, 0x9e, 0x6a, 0x32, 0x56, 0xb7, 0xb5, 0x9d, 0xd6, 0xfe, 0x47, 0x7d,
+	0x65, 0xa5, 0x7f, 0x5e, 0xa0, 0x5a, 0xb7, 0xb8, 0xcd, 0x11, 0x74, 0x8b, 0x1c, 0x1f, 0xea, 0x89,
+	0x79, 0x00, 0x75, 0xa5, 0x09, 0x3d, 0x85, 0x9e, 0x47, 0x39, 0x89, 0x29, 0xf6, 0x87, 0xd4, 0x89,
+	0x42, 0x8f, 0x72, 0xa9, 0x4a, 0x1f, 0x55, 0xac, 0x12, 0xe5, 0x50, 0x87, 0xc6, 0x34, 0xa4, 0x9c,
+	0x50, 0x6e, 0xfe, 0xa3, 0x43, 0xe7, 0x58, 0xba, 0x3d, 0x56, 0x11, 0x43, 0x9b, 0x50, 0xa3, 0x21,
+	0x9d, 0x12, 0x29, 0x5f, 0xb5, 0x14, 0x20, 0x5c, 0x9c, 0xce, 0x30, 0xa5, 0xc4, 0x4f, 0xdc, 0x48,
+	0x41, 0xb4, 0x0b, 0xab, 0x1c, 0xbb, 0x32, 0x06, 0xdd, 0xfd, 0x4f, 0xd2, 0x18, 0x14, 0x74, 0xf6,
+	0x2f, 0xb0, 0x6b, 0x09, 0x2e, 0xf4, 0x1c, 0x74, 0xec, 0x7b, 0xef, 0x88, 0x1d, 0x30, 0xd7, 0xa8,
+	0xc9, 0xb0, 0x6d, 0xa6, 0x22, 0x07, 0x82, 0x90, 0x48, 0x8c, 0x2a, 0x56, 0x53, 0x32, 0x8e, 0x99,
+	0x8b, 0xbe, 0x86, 0x46, 0x40, 0x02, 0x3b, 0x26, 0xd7, 0x46, 0x5d, 0x8a, 0x64, 0x56, 0xc6, 0x24,
+	0xb8, 0x24, 0x31, 0x9b, 0x79, 0x91, 0x45, 0xae, 0xe7, 0x84, 0xf1, 0x51, 0xc5, 0xaa, 0x07, 0x24,
+	0xb0, 0xc8, 0x35, 0xfa, 0x26, 0x95, 0x62, 0x46, 0x43, 0x4a, 0x6d, 0x2d, 0x93, 0x62, 0x51, 0x48,
+	0x19, 0xc9, 0xc4, 0x18, 0x7a, 0x06, 0x4d, 0x07, 0x73, 0x2c, 0x1d, 0x6c, 0x4a, 0xb9, 0x8d, 0x54,
+	0x6e, 0x80, 0x39, 0x5e, 0xf8, 0xd7, 0x10, 0x6c, 0xc2, 0xbd, 0x5d, 0xa8, 0xcd, 0x88, 0xef, 0x87,
+	0x86, 0x5e, 0x64, 0x57, 0x21, 0x18, 0x09, 0xd2, 0xa8, 0x62, 0x29, 0x1e, 0xb4, 0x97, 0xa8, 0x77,
+	0x3c, 0xd7, 0x00, 0xc9, 0x8f, 0xf2, 0xea, 0x07, 0x9e, 0xab, 0x5e, 0x21, 0xb5, 0x0f, 0x3c, 0x37,
+	0xf3, 0x47, 0xbc, 0xbe, 0x55, 0xf6, 0x67, 0xf1, 0x6e, 0x29, 0xa1, 0x1e, 0xde, 0x92, 0x12, 0xf3,
+	0xc8, 0xc1, 0x9c, 0x18, 0xed, 0xb2, 0x95, 0xd7, 0x92, 0x32, 0xaa, 0x58, 0xe0, 0x64, 0x10, 0x7a,
+	0x0c, 0x35, 0x12, 0x44, 0xfc, 0xc6, 0xe8, 0x48, 0x81, 0x4e, 0x2a, 0x30, 0x14, 0x48, 0xf1, 0x00,
+	0x49, 0x45, 0xbb, 0x50, 0x9d, 0x86, 0x94, 0x1a, 0x5d, 0xc9, 0xf5, 0x20, 0xe5, 0x3a, 0x0a, 0x29,
+	0x1d, 0x32, 0x8e, 0x2f, 0x7d, 0x8f, 0xcd, 0x46, 0x15, 0x4b, 0x32, 0xa1, 0x7d, 0x00, 0xc6, 0x31,
+	0x27, 0xb6, 0x47, 0xaf, 0x42, 0x63, 0x4d, 0x8a, 0xac, 0x67, 0x6d, 0x22, 0x28, 0x27, 0xf4, 0x4a,
+	0x44, 0x47, 0x67, 0x29, 0x80, 0x0e, 0xa1, 0xab, 0x64, 0x18, 0xc5, 0x11, 0x9b, 0x85, 0xdc, 0xe8,
+	0x15, 0x93, 0x9e, 0xc9, 0x9d, 0x27, 0x0c, 0xa3, 0x8a, 0xd5, 0x91, 0x22, 0x29, 0x02, 0x8d, 0x61,
+	0x63, 0x61, 0xd7, 0x8e, 0xe6, 0xbe, 0x2f, 0xe3, 0xb7, 0x2e, 0x15, 0x3d, 0x2c, 0x29, 0x3a, 0x9b,
+	0xfb, 0xfe, 0x22, 0x90, 0x3d, 0x76, 0x0b, 0x8f, 0x0e, 0x40, 0xe9, 0xb7, 0x63, 0xc5, 0x64, 0xa0,
+	0x62, 0x41, 0x59, 0x24, 0x08, 0x39, 0x91, 0xea, 0x16, 0x6a, 0xda, 0x2c, 0x07, 0xa3, 0x41, 0xfa,
+	0xaa, 0x38, 0x29, 0x39, 0x63, 0x43, 0xea, 0xf8, 0x74, 0xa9, 0x8e, 0xac, 0x2a, 0x3b, 0x2c, 0x8f,
+	0x10, 0xb1, 0xf1, 0x09, 0x76, 0x54, 0xf1, 0xca, 0x12, 0xdd, 0x2c, 0xc6, 0xe6, 0x55, 0x46, 0x5d,
+	0x14, 0x6a, 0x67, 0x21, 0x22, 0xca, 0xf5, 0x25, 0x74, 0x22, 0x42, 0x62, 0xdb, 0x73, 0x08, 0xe5,
+	0x1e, 0xbf, 0x31, 0x1e, 0x14, 0xdb, 0xf0, 0x8c, 0x90, 0xf8, 0x24, 0xa1, 0x89, 0x67, 0x44, 0x39,
+	0xd8, 0xb4, 0x61, 0xf5, 0x02, 0xbb, 0xa8, 0x03, 0xfa, 0xeb, 0xc9, 0x60, 0xf8, 0xc3, 0xc9, 0x64,
+	0x38, 0xe8, 0x55, 0x90, 0x0e, 0xb5, 0xe1, 0xf8, 0xec, 0xe2, 0x4d, 0x4f, 0x43, 0x6d, 0x68, 0x9e,
+	0x5a, 0xc7, 0xf6, 0xe9, 0xe4, 0xd5, 0x9b, 0xde, 0x8a, 0xe0, 0x3b, 0x1a, 0x1d, 0x4c, 0x14, 0xb8,
+	0x8a, 0x7a, 0xd0, 0x96, 0xe0, 0xc1, 0x64, 0x60, 0x9f, 0x5a, 0xc7, 0xbd, 0x2a, 0x5a, 0x83, 0x96,
+	0x62, 0xb0, 0x24, 0xa2, 0x96, 0x1f, 0x4d, 0x7f, 0x6b, 0xa0, 0x67, 0x29, 0x42, 0x5b, 0xd0, 0x0c,
+	0x08, 0xc7, 0xa2, 0x60, 0x93, 0x21, 0x99, 0xc1, 0xa8, 0x0f, 0x3a, 0xf7, 0x02, 0xc2, 0x38, 0x0e,
+	0x22, 0x39, 0x9e, 0x5a, 0xfb, 0xbd, 0xfc, 0x73, 0x2e, 0xbc, 0x80, 0x58, 0x0b, 0x16, 0xf4, 0x00,
+	0xea, 0xd1, 0x5b, 0xcf, 0xf6, 0x1c, 0x39, 0xb5, 0xda, 0x56, 0x2d, 0x7a, 0xeb, 0x9d, 0x38, 0xe8,
+	0x11, 0x40, 0x32, 0xd4, 0xc6, 0x07, 0x47, 0x46, 0x55, 0x92, 0x72, 0x18, 0xf3, 0x00, 0xd6, 0x4b,
+	0xb5, 0x87, 0x9e, 0x42, 0x93, 0xf8, 0x24, 0x20, 0x94, 0x33, 0x43, 0xdb, 0x5e, 0xcd, 0x9b, 0xce,
+	0x36, 0x40, 0xc6, 0x61, 0x7e, 0x0b, 0x9b, 0xcb, 0xaa, 0xee, 0x96, 0x69, 0xad, 0x64, 0x7a, 0x02,
+	0x9d, 0x42, 0x87, 0xe5, 0x9e, 0xa0, 0xe5, 0x9f, 0x80, 0xa0, 0x3a, 0x25, 0x31, 0x4f, 0x66, 0xb4,
+	0xfc, 0x16, 0xb8, 0x19, 0x66, 0xb3, 0xe4, 0xad, 0xf2, 0xdb, 0x7c, 0x0d, 0xed, 0x7c, 0x9e, 0xef,
+	0xa3, 0x2e, 0x9f, 0x88, 0xd5, 0x62, 0x22, 0xcc, 0x00, 0x5a, 0xb9, 0xa1, 0x74, 0xf7, 0x2a, 0x71,
+	0xe4, 0x98, 0x63, 0xc6, 0xca, 0xf6, 0xea, 0x8e, 0x6e, 0xa5, 0x20, 0xea, 0x43, 0x33, 0x60, 0xae,
+	0xcd, 0x6f, 0x92, 0x9d, 0xda, 0x5d, 0xcc, 0x3a, 0x11, 0xac, 0x31, 0x73, 0x2f, 0x6e, 0x22, 0x62,
+	0x35, 0x02, 0xf5, 0x61, 0x86, 0xd0, 0xca, 0x0d, 0xd9, 0x3b, 0xcc, 0xe5, 0xfd, 0x5d, 0x29, 0x15,
+	0xce, 0xfd, 0x0c, 0xfe, 0x01, 0xb0, 0x98, 0x9f, 0x77, 0xd8, 0xfb, 0x1c, 0xaa, 0x89, 0xad, 0xe5,
+	0xc5, 0x50, 0xfd, 0x20, 0xcb, 0x3e, 0xc0, 0x62, 0x3f, 0xfc, 0xef, 0x81, 0x7d, 0xa1, 0xf2, 0x98,
+	0x9e, 0x04, 0x5f, 0x14, 0xef, 0x93, 0xd6, 0xfe, 0x5a, 0x26, 0xad, 0xd0, 0xd9, 0xc1, 0x62, 0xfe,
+	0x08, 0x8d, 0x04, 0x87, 0x3e, 0x86, 0x06, 0x23, 0xd7, 0x36, 0x9d, 0x07, 0x89, 0x9b, 0x75, 0x46,
+	0xae, 0x27, 0xf3, 0x20, 0x2b, 0x48, 0x91, 0x0d, 0x5d, 0x15, 0xa4, 0xc0, 0xe5, 0x2a, 0x4a, 0x7e,
+	0x9b, 0xff, 0x6a, 0xd0, 0xce, 0x1f, 0x05, 0xa8, 0x0f, 0x10, 0x64, 0xbb, 0x3b, 0x71, 0xa5, 0x5b,
+	0xdc, 0xea, 0x56, 0x8e, 0xe3, 0xde, 0x73, 0x61, 0x0b, 0x9a, 0xd9, 0x54, 0x54, 0xed, 0x9f, 0xc1,
+	0xc8, 0x84, 0x0e, 0xf7, 0x99, 0x2d, 0x5a, 0xc0, 0x96, 0xde, 0xd7, 0x24, 0x43, 0x8b, 0xfb, 0xec,
+	0x88, 0xc4, 0x7c, 0x24, 0xba, 0xea, 0x4f, 0x0d, 0xd6, 0x4b, 0x13, 0xf8, 0xae, 0xde, 0xba, 0xaf,
+	0x73, 0x8f, 0xa1, 0xeb, 0x31, 0xdb, 0x21, 0x53, 0x1f, 0xc7, 0x98, 0x7b, 0x21, 0x95, 0xb1, 0x6a,
+	0x5a, 0x1d, 0x8f, 0x0d, 0x16, 0x48, 0xf3, 0x10, 0x9a, 0xa9, 0x34, 0xfa, 0x0c, 0xc0, 0xa3, 0x53,
+	0x91, 0x81, 0x4b, 0x12, 0x27, 0x49, 0xd0, 0x3d, 0x3a, 0x9d, 0x48, 0x44, 0x3e, 0x41, 0x2b, 0xf9,
+	0x04, 0x99, 0x57, 0xb0, 0x5e, 0xba, 0xac, 0xd0, 0x4b, 0xe8, 0x31, 0xe2, 0x5f, 0xc9, 0x95, 0x1a,
+	0x07, 0xca, 0x03, 0x6d, 0x5b, 0x5b, 0x5a, 0xe3, 0x6b, 0x82, 0xf3, 0x64, 0xc1, 0x28, 0x0a, 0xf6,
+	0x2d, 0x0d, 0x7f, 0xa7, 0xb2, 0x30, 0xdb, 0x96, 0x02, 0xcc, 0x4b, 0x40, 0xe5, 0x5b, 0x0c, 0x3d,
+	0x81, 0x9a, 0x3c, 0xfd, 0xee, 0x1c, 0xa7, 0x8a, 0x2c, 0x1b, 0x8d, 0x60, 0xe7, 0x3d, 0x8d, 0x46,
+	0xb0, 0x63, 0xfe, 0x0c, 0x75, 0x65, 0x43, 0x64, 0x97, 0x14, 0x6e, 0x63, 0x2b, 0x83, 0xdf, 0x3b,
+	0x24, 0x96, 0x6f, 0x0b, 0xb3, 0x01, 0x35, 0x79, 0x1a, 0x99, 0xbf, 0x00, 0x2a, 0x1f, 0x00, 0xa2,
+	0x5e, 0x18, 0xc7, 0x31, 0xb7, 0x8b, 0x3d, 0xd0, 0x92, 0xc8, 0x73, 0xd5, 0x08, 0x8f, 0xa0, 0x45,
+	0xa8, 0x63, 0x17, 0x93, 0xa0, 0x13, 0xea, 0x28, 0xba, 0x79, 0x08, 0x1b, 0x4b, 0xce, 0x02, 0xb4,
+	0x0b, 0xcd, 0xa4, 0xdd, 0xd2, 0x95, 0x53, 0xea, 0xc7, 0x8c, 0xe1, 0xcb, 0xef, 0xa0, 0x95, 0x6b,
+	0xf1, 0xdb, 0x9b, 0xbb, 0x03, 0xfa, 0xe1, 0xab, 0xd3, 0xa3, 0x9f, 0xec, 0xf1, 0xf9, 0x71, 0x4f,
+	0x13, 0x0b, 0xfa, 0x64, 0x30, 0x9c, 0x5c, 0x9c, 0x5c, 0xbc, 0x91, 0x98, 0x95, 0xfd, 0xdf, 0xa0,
+	0xae, 0x46, 0x2c, 0x7a, 0x01, 0x6d, 0xf5, 0x75, 0xce, 0x63, 0x82, 0x03, 0x54, 0x0a, 0xf8, 0x56,
+	0x09, 0x63, 0x56, 0x76, 0xb4, 0x67, 0x1a, 0x7a, 0x02, 0xd5, 0x33, 0x8f, 0xba, 0xa8, 0x78, 0x52,
+	0x6e, 0x15, 0x41, 0xb3, 0x72, 0xf8, 0xd5, 0xaf, 0xbb, 0xae, 0xc7, 0x67, 0xf3, 0xcb, 0xfe, 0x34,
+	0x0c, 0xf6, 0x66, 0x37, 0x11, 0x89, 0x7d, 0xe2, 0xb8, 0x24, 0xde, 0xbb, 0xc2, 0x97, 0xb1, 0x37,
+	0xdd, 0x93, 0x3f, 0x73, 0x6c, 0x4f, 0x89, 0x5d, 0xd6, 0x25, 0xf8, 0xfc, 0xbf, 0x01, 0x00, 0x19,
+	0xce, 0xc7, 0x1a, 0xf3, 0x0d, 0x00, 0x00,
 }
@@ -16,6 +16,8 @@ It has these top-level messages:
 	RangeQueryInfo
 	QueryReads
 	QueryReadsMerkleSummary
+	KVMetadataWrite
+	KVMetadataEntry
 */
 package kvrwset
 
@@ -36,9 +38,10 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 // KVRWSet encapsulates the read-write set for a chaincode that operates upon a KV or Document data model
 type KVRWSet struct {
-	Reads            []*KVRead         `protobuf:"bytes,1,rep,name=reads" json:"reads,omitempty"`
-	RangeQueriesInfo []*RangeQueryInfo `protobuf:"bytes,2,rep,name=range_queries_info,json=rangeQueriesInfo" json:"range_queries_info,omitempty"`
-	Writes           []*KVWrite        `protobuf:"bytes,3,rep,name=writes" json:"writes,omitempty"`
+	Reads            []*KVRead          `protobuf:"bytes,1,rep,name=reads" json:"reads,omitempty"`
+	RangeQueriesInfo []*RangeQueryInfo  `protobuf:"bytes,2,rep,name=range_queries_info,json=rangeQueriesInfo" json:"range_queries_info,omitempty"`
+	Writes           []*KVWrite         `protobuf:"bytes,3,rep,name=writes" json:"writes,omitempty"`
+	MetadataWrites   []*KVMetadataWrite `protobuf:"bytes,4,rep,name=metadata_writes,json=metadataWrites" json:"metadata_writes,omitempty"`
 }
 
 func (m *KVRWSet) Reset()                    { *m = KVRWSet{} }
@@ -67,6 +70,13 @@ func (m *KVRWSet) GetWrites() []*KVWrite {
 	return nil
 }
 
+func (m *KVRWSet) GetMetadataWrites() []*KVMetadataWrite {
+	if m != nil {
+		return m.MetadataWrites
+	}
+	return nil
+}
+
 // KVRead captures a read operation performed during transaction simulation
 // A 'nil' version indicates a non-existing key read by the transaction
 type KVRead struct {
@@ -275,6 +285,37 @@ func (m *QueryReadsMerkleSummary) String() string            { return proto.Comp
 func (*QueryReadsMerkleSummary) ProtoMessage()               {}
 func (*QueryReadsMerkleSummary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
+// KVMetadataWrite captures all the entries in the metadata associated with a key,
+// e.g. the validation parameters of key-level endorsement policies. The entries
+// replace the whole metadata of the key, no entries delete the metadata of the key
+type KVMetadataWrite struct {
+	Key     string             `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Entries []*KVMetadataEntry `protobuf:"bytes,2,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *KVMetadataWrite) Reset()                    { *m = KVMetadataWrite{} }
+func (m *KVMetadataWrite) String() string            { return proto.CompactTextString(m) }
+func (*KVMetadataWrite) ProtoMessage()               {}
+func (*KVMetadataWrite) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *KVMetadataWrite) GetEntries() []*KVMetadataEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// KVMetadataEntry captures a 'name'ed entry in the metadata of a key
+type KVMetadataEntry struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *KVMetadataEntry) Reset()                    { *m = KVMetadataEntry{} }
+func (m *KVMetadataEntry) String() string            { return proto.CompactTextString(m) }
+func (*KVMetadataEntry) ProtoMessage()               {}
+func (*KVMetadataEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
 func init() {
 	proto.RegisterType((*KVRWSet)(nil), "kvrwset.KVRWSet")
 	proto.RegisterType((*KVRead)(nil), "kvrwset.KVRead")
@@ -283,45 +324,51 @@ func init() {
 	proto.RegisterType((*RangeQueryInfo)(nil), "kvrwset.RangeQueryInfo")
 	proto.RegisterType((*QueryReads)(nil), "kvrwset.QueryReads")
 	proto.RegisterType((*QueryReadsMerkleSummary)(nil), "kvrwset.QueryReadsMerkleSummary")
+	proto.RegisterType((*KVMetadataWrite)(nil), "kvrwset.KVMetadataWrite")
+	proto.RegisterType((*KVMetadataEntry)(nil), "kvrwset.KVMetadataEntry")
 }
 
 func init() { proto.RegisterFile("ledger/rwset/kvrwset/kv_rwset.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 552 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x7c, 0x53, 0x4d, 0x6b, 0xdb, 0x40,
-	0x10, 0x8d, 0xec, 0xd8, 0x92, 0xa7, 0x4e, 0xea, 0x6e, 0x5a, 0x22, 0x28, 0x05, 0xa3, 0x50, 0x30,
-	0x39, 0x48, 0x90, 0x5e, 0xda, 0x43, 0x2f, 0x25, 0x29, 0x29, 0x69, 0x03, 0xdd, 0x80, 0x03, 0xbd,
-	0x88, 0xb5, 0x35, 0xb6, 0x85, 0xf5, 0x91, 0xee, 0xae, 0x6c, 0xf9, 0x54, 0xfa, 0x53, 0xfa, 0x4f,
-	0xcb, 0x8e, 0x24, 0x3b, 0x01, 0xb7, 0x27, 0xed, 0xbc, 0x37, 0x6f, 0x76, 0xe6, 0x69, 0x16, 0xce,
-	0x12, 0x8c, 0xe6, 0x28, 0x03, 0xb9, 0x56, 0xa8, 0x83, 0xe5, 0xaa, 0xf9, 0x86, 0x74, 0xf0, 0x1f,
-	0x64, 0xae, 0x73, 0x66, 0xd7, 0xb8, 0xf7, 0xc7, 0x02, 0xfb, 0x66, 0xcc, 0xef, 0xef, 0x50, 0xb3,
-	0xb7, 0xd0, 0x91, 0x28, 0x22, 0xe5, 0x5a, 0xc3, 0xf6, 0xe8, 0xd9, 0xc5, 0x73, 0xbf, 0x4e, 0xf2,
-	0x6f, 0xc6, 0x1c, 0x45, 0xc4, 0x2b, 0x96, 0x5d, 0x01, 0x93, 0x22, 0x9b, 0x63, 0xf8, 0xb3, 0x40,
-	0x19, 0xa3, 0x0a, 0xe3, 0x6c, 0x96, 0xbb, 0x2d, 0xd2, 0x9c, 0x6e, 0x35, 0xdc, 0xa4, 0x7c, 0x2f,
-	0x50, 0x6e, 0xbe, 0x64, 0xb3, 0x9c, 0x0f, 0x64, 0x13, 0xc7, 0xa8, 0x0c, 0xc2, 0x46, 0xd0, 0x5d,
-	0xcb, 0x58, 0xa3, 0x72, 0xdb, 0x24, 0x1d, 0x3c, 0xba, 0xee, 0xde, 0x10, 0xbc, 0xe6, 0xbd, 0xcf,
-	0xd0, 0xad, 0x3a, 0x60, 0x03, 0x68, 0x2f, 0x71, 0xe3, 0x5a, 0x43, 0x6b, 0xd4, 0xe3, 0xe6, 0xc8,
-	0xce, 0xc1, 0x5e, 0xa1, 0x54, 0x71, 0x9e, 0xb9, 0xad, 0xa1, 0xf5, 0xa4, 0xcc, 0xb8, 0xc2, 0x79,
-	0x93, 0xe0, 0xdd, 0x9a, 0x51, 0xa9, 0xf4, 0x9e, 0x42, 0xaf, 0xa1, 0x17, 0xab, 0x30, 0xc2, 0x04,
-	0x35, 0x52, 0x29, 0x87, 0x3b, 0xb1, 0xba, 0xa4, 0x98, 0xbd, 0x84, 0xce, 0x4a, 0x24, 0x05, 0xba,
-	0xed, 0xa1, 0x35, 0xea, 0xf3, 0x2a, 0xf0, 0x3e, 0x82, 0x5d, 0xdf, 0x61, 0xd4, 0x93, 0x24, 0x9f,
-	0x2e, 0xc3, 0xac, 0x48, 0xa9, 0xea, 0x21, 0x77, 0x08, 0xb8, 0x2d, 0x52, 0xf6, 0x0a, 0xba, 0xba,
-	0x24, 0xa6, 0x45, 0x4c, 0x47, 0x97, 0xb7, 0x45, 0xea, 0xfd, 0x6e, 0xc1, 0xf1, 0x53, 0x97, 0x4c,
-	0x19, 0xa5, 0x85, 0xd4, 0xe1, 0xae, 0x39, 0x87, 0x80, 0x1b, 0xdc, 0xb0, 0x53, 0xb0, 0x31, 0x8b,
-	0x88, 0x6a, 0x11, 0xd5, 0xc5, 0x2c, 0x32, 0xc4, 0x19, 0x1c, 0xc5, 0x5a, 0x86, 0x58, 0x2e, 0x44,
-	0xa1, 0x34, 0x46, 0xd4, 0xa5, 0xc3, 0xfb, 0xb1, 0x96, 0x57, 0x0d, 0xc6, 0x2e, 0xa0, 0x27, 0xc5,
-	0x3a, 0xac, 0x7e, 0xf0, 0x21, 0x59, 0x75, 0xb2, 0xb5, 0x8a, 0x3a, 0x30, 0x0e, 0xab, 0xeb, 0x03,
-	0xee, 0x48, 0xb1, 0xa6, 0x33, 0xe3, 0x70, 0x42, 0xf9, 0x61, 0x8a, 0x72, 0x99, 0x60, 0xb8, 0x10,
-	0x6a, 0x81, 0xca, 0xed, 0x90, 0x7a, 0xb8, 0x47, 0xfd, 0x8d, 0xf2, 0xee, 0x8a, 0x34, 0x15, 0x72,
-	0x73, 0x7d, 0xc0, 0x5f, 0xc8, 0x1d, 0x7a, 0x4d, 0xe2, 0x4f, 0x7d, 0x80, 0xaa, 0xa6, 0xd9, 0x1a,
-	0xef, 0x3d, 0xc0, 0x4e, 0xcd, 0xce, 0xc1, 0x31, 0x7b, 0xfa, 0xbf, 0x1d, 0xb4, 0x97, 0x2b, 0xca,
-	0xf5, 0x7e, 0xc1, 0xe9, 0x3f, 0xee, 0x65, 0x6f, 0x00, 0x52, 0x51, 0x86, 0x11, 0xce, 0x25, 0x22,
-	0xd9, 0x78, 0xc4, 0x7b, 0xa9, 0x28, 0x2f, 0x09, 0x30, 0x26, 0x1b, 0x3a, 0xc1, 0x15, 0x26, 0xe4,
-	0xe4, 0x11, 0x77, 0x52, 0x51, 0x7e, 0x35, 0x31, 0x1b, 0xc1, 0x60, 0x4b, 0x36, 0xf3, 0x9a, 0xfd,
-	0xec, 0xf3, 0xe3, 0x26, 0xa7, 0x1e, 0x24, 0x87, 0x8b, 0x5c, 0xce, 0xfd, 0xc5, 0xe6, 0x01, 0x65,
-	0xf5, 0xe4, 0xfc, 0x99, 0x98, 0xc8, 0x78, 0x5a, 0x3d, 0x31, 0xe5, 0xd7, 0x60, 0xd5, 0x7e, 0x3d,
-	0xc6, 0x8f, 0x0f, 0xf3, 0x58, 0x2f, 0x8a, 0x89, 0x3f, 0xcd, 0xd3, 0xe0, 0x91, 0x34, 0xa8, 0xa4,
-	0x41, 0x25, 0x0d, 0xf6, 0x3d, 0xe1, 0x49, 0x97, 0xc8, 0x77, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff,
-	0x7e, 0x2f, 0xc5, 0xa9, 0xe1, 0x03, 0x00, 0x00,
+	// 618 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x54, 0x5b, 0x6b, 0xdb, 0x30,
+	0x14, 0x6e, 0xee, 0xce, 0x59, 0xda, 0x64, 0xea, 0x46, 0x0d, 0x63, 0x10, 0x5c, 0x06, 0xa1, 0x0f,
+	0x0e, 0x64, 0x2f, 0x1b, 0x63, 0x0f, 0x2b, 0xed, 0xe8, 0xe8, 0x5a, 0x98, 0x0a, 0x2d, 0xec, 0xc5,
+	0x28, 0xf5, 0x69, 0x62, 0xe2, 0x4b, 0x27, 0xc9, 0xb9, 0x3c, 0x8d, 0xfd, 0xd7, 0xfd, 0x90, 0xa1,
+	0x23, 0xbb, 0x49, 0x4b, 0xba, 0xa7, 0x48, 0xdf, 0xe5, 0xe8, 0xe8, 0xe4, 0xb3, 0xe0, 0x30, 0xc6,
+	0x70, 0x82, 0x72, 0x28, 0x17, 0x0a, 0xf5, 0x70, 0x36, 0x2f, 0x7f, 0x03, 0x5a, 0xf8, 0xf7, 0x32,
+	0xd3, 0x19, 0x6b, 0x15, 0xb8, 0xf7, 0xb7, 0x02, 0xad, 0xf3, 0x6b, 0x7e, 0x73, 0x85, 0x9a, 0xbd,
+	0x83, 0x86, 0x44, 0x11, 0x2a, 0xb7, 0xd2, 0xaf, 0x0d, 0x5e, 0x8c, 0xba, 0x7e, 0x21, 0xf2, 0xcf,
+	0xaf, 0x39, 0x8a, 0x90, 0x5b, 0x96, 0x9d, 0x02, 0x93, 0x22, 0x9d, 0x60, 0xf0, 0x2b, 0x47, 0x19,
+	0xa1, 0x0a, 0xa2, 0xf4, 0x2e, 0x73, 0xab, 0xe4, 0x39, 0x78, 0xf0, 0x70, 0x23, 0xf9, 0x91, 0xa3,
+	0x5c, 0x7d, 0x4b, 0xef, 0x32, 0xde, 0x93, 0xe5, 0x3e, 0x42, 0x65, 0x10, 0x36, 0x80, 0xe6, 0x42,
+	0x46, 0x1a, 0x95, 0x5b, 0x23, 0x6b, 0x6f, 0xe3, 0xb8, 0x1b, 0x43, 0xf0, 0x82, 0x67, 0x5f, 0xa0,
+	0x9b, 0xa0, 0x16, 0xa1, 0xd0, 0x22, 0x28, 0x2c, 0x75, 0xb2, 0xb8, 0x1b, 0x96, 0x8b, 0x42, 0x61,
+	0xad, 0x7b, 0xc9, 0xe6, 0x56, 0x79, 0x5f, 0xa1, 0x69, 0x2f, 0xc1, 0x7a, 0x50, 0x9b, 0xe1, 0xca,
+	0xad, 0xf4, 0x2b, 0x83, 0x36, 0x37, 0x4b, 0x76, 0x04, 0xad, 0x39, 0x4a, 0x15, 0x65, 0xa9, 0x5b,
+	0xed, 0x57, 0x1e, 0x75, 0x72, 0x6d, 0x71, 0x5e, 0x0a, 0xbc, 0x4b, 0x33, 0x2d, 0xaa, 0xb9, 0xa5,
+	0xd0, 0x1b, 0x68, 0x47, 0x2a, 0x08, 0x31, 0x46, 0x8d, 0x54, 0xca, 0xe1, 0x4e, 0xa4, 0x4e, 0x68,
+	0xcf, 0x5e, 0x41, 0x63, 0x2e, 0xe2, 0x1c, 0xdd, 0x5a, 0xbf, 0x32, 0xe8, 0x70, 0xbb, 0xf1, 0x3e,
+	0x43, 0xab, 0x38, 0xc3, 0xb8, 0xc7, 0x71, 0x76, 0x3b, 0x0b, 0xd2, 0x3c, 0xa1, 0xaa, 0x75, 0xee,
+	0x10, 0x70, 0x99, 0x27, 0xec, 0x35, 0x34, 0xf5, 0x92, 0x98, 0x2a, 0x31, 0x0d, 0xbd, 0xbc, 0xcc,
+	0x13, 0xef, 0x4f, 0x15, 0xf6, 0x1e, 0x0f, 0xda, 0x94, 0x51, 0x5a, 0x48, 0x1d, 0xac, 0x9b, 0x73,
+	0x08, 0x38, 0xc7, 0x15, 0x3b, 0x80, 0x16, 0xa6, 0x21, 0x51, 0x55, 0xa2, 0x9a, 0x98, 0x86, 0x86,
+	0x38, 0x84, 0xdd, 0x48, 0xcb, 0x00, 0x97, 0x53, 0x91, 0x2b, 0x8d, 0x21, 0x75, 0xe9, 0xf0, 0x4e,
+	0xa4, 0xe5, 0x69, 0x89, 0xb1, 0x11, 0xb4, 0xa5, 0x58, 0x04, 0x36, 0x23, 0x75, 0x1a, 0xd5, 0xfe,
+	0xc3, 0xa8, 0xa8, 0x03, 0x33, 0x61, 0x75, 0xb6, 0xc3, 0x1d, 0x29, 0x16, 0xb4, 0x66, 0x1c, 0xf6,
+	0x49, 0x1f, 0x24, 0x28, 0x67, 0x31, 0x06, 0x53, 0xa1, 0xa6, 0xa8, 0xdc, 0x06, 0xb9, 0xfb, 0x5b,
+	0xdc, 0x17, 0xa4, 0xbb, 0xca, 0x93, 0x44, 0xc8, 0xd5, 0xd9, 0x0e, 0x7f, 0x29, 0xd7, 0xe8, 0x19,
+	0x99, 0x8f, 0x3b, 0x00, 0xb6, 0xa6, 0x09, 0x9e, 0xf7, 0x01, 0x60, 0xed, 0x66, 0x47, 0xe0, 0x98,
+	0xa8, 0xff, 0x2f, 0xc6, 0xad, 0xd9, 0x9c, 0xb4, 0xde, 0x6f, 0x38, 0x78, 0xe6, 0x5c, 0xf6, 0x16,
+	0x20, 0x11, 0xcb, 0x20, 0xc4, 0x89, 0x44, 0xa4, 0x31, 0xee, 0xf2, 0x76, 0x22, 0x96, 0x27, 0x04,
+	0x98, 0x21, 0x1b, 0x3a, 0xc6, 0x39, 0xc6, 0x34, 0xc9, 0x5d, 0xee, 0x24, 0x62, 0xf9, 0xdd, 0xec,
+	0xd9, 0x00, 0x7a, 0x0f, 0x64, 0x79, 0x5f, 0x13, 0xf1, 0x0e, 0xdf, 0x2b, 0x35, 0xf6, 0x22, 0xde,
+	0x0d, 0x74, 0x9f, 0x04, 0x77, 0x4b, 0xaa, 0x46, 0xe6, 0x3f, 0xd3, 0xe6, 0xb3, 0x71, 0xab, 0xcf,
+	0xa6, 0xfe, 0x34, 0xd5, 0x72, 0xc5, 0x4b, 0xa1, 0xf7, 0x09, 0xba, 0x4f, 0x38, 0xc6, 0xa0, 0x9e,
+	0x8a, 0x04, 0x8b, 0xca, 0xb4, 0x5e, 0x67, 0xb2, 0xba, 0x91, 0xc9, 0xe3, 0x0c, 0x46, 0x99, 0x9c,
+	0xf8, 0xd3, 0xd5, 0x3d, 0x4a, 0xfb, 0x96, 0xf8, 0x77, 0x62, 0x2c, 0xa3, 0x5b, 0xfb, 0x76, 0x28,
+	0xbf, 0x00, 0x6d, 0x0f, 0x45, 0x2f, 0x3f, 0x3f, 0x4e, 0x22, 0x3d, 0xcd, 0xc7, 0xfe, 0x6d, 0x96,
+	0x0c, 0x37, 0xac, 0x43, 0x6b, 0x1d, 0x5a, 0xeb, 0x70, 0xdb, 0xdb, 0x34, 0x6e, 0x12, 0xf9, 0xfe,
+	0xdf, 0x00, 0x15, 0x3d, 0xbe, 0xdf, 0xba, 0x04, 0x00, 0x00,
 }
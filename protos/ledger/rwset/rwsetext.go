@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rwset
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+)
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+// KV is the only data model of the read-write sets
+func (nrws *NsReadWriteSet) StaticallyOpaqueFields() []string {
+	return []string{"rwset"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (nrws *NsReadWriteSet) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "rwset" {
+		return nil, fmt.Errorf("%T has no opaque field %s", nrws, name)
+	}
+	return &kvrwset.KVRWSet{}, nil
+}
@@ -21,6 +21,8 @@ It has these top-level messages:
 	MSPPrincipal
 	OrganizationUnit
 	MSPRole
+	MSPIdentityAnonymity
+	CombinedPrincipal
 */
 package msp
 
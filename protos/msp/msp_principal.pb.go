@@ -24,17 +24,27 @@ const (
 	// E.g., this can well be represented by an MSP's
 	// Organization unit
 	MSPPrincipal_IDENTITY MSPPrincipal_Classification = 2
+	// Denotes a principal that is satisfied by the nominal
+	// or the anonymous identities of an MSP
+	MSPPrincipal_ANONYMITY MSPPrincipal_Classification = 3
+	// Denotes a principal that is made of other principals,
+	// all of which an identity has to satisfy
+	MSPPrincipal_COMBINED MSPPrincipal_Classification = 4
 )
 
 var MSPPrincipal_Classification_name = map[int32]string{
 	0: "ROLE",
 	1: "ORGANIZATION_UNIT",
 	2: "IDENTITY",
+	3: "ANONYMITY",
+	4: "COMBINED",
 }
 var MSPPrincipal_Classification_value = map[string]int32{
 	"ROLE":              0,
 	"ORGANIZATION_UNIT": 1,
 	"IDENTITY":          2,
+	"ANONYMITY":         3,
+	"COMBINED":          4,
 }
 
 func (x MSPPrincipal_Classification) String() string {
@@ -65,6 +75,31 @@ func (x MSPRole_MSPRoleType) String() string {
 }
 func (MSPRole_MSPRoleType) EnumDescriptor() ([]byte, []int) { return fileDescriptor2, []int{2, 0} }
 
+type MSPIdentityAnonymity_MSPIdentityAnonymityType int32
+
+const (
+	// Represents a nominal MSP Identity
+	MSPIdentityAnonymity_NOMINAL MSPIdentityAnonymity_MSPIdentityAnonymityType = 0
+	// Represents an anonymous MSP Identity
+	MSPIdentityAnonymity_ANONYMOUS MSPIdentityAnonymity_MSPIdentityAnonymityType = 1
+)
+
+var MSPIdentityAnonymity_MSPIdentityAnonymityType_name = map[int32]string{
+	0: "NOMINAL",
+	1: "ANONYMOUS",
+}
+var MSPIdentityAnonymity_MSPIdentityAnonymityType_value = map[string]int32{
+	"NOMINAL":   0,
+	"ANONYMOUS": 1,
+}
+
+func (x MSPIdentityAnonymity_MSPIdentityAnonymityType) String() string {
+	return proto.EnumName(MSPIdentityAnonymity_MSPIdentityAnonymityType_name, int32(x))
+}
+func (MSPIdentityAnonymity_MSPIdentityAnonymityType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor2, []int{3, 0}
+}
+
 // MSPPrincipal aims to represent an MSP-centric set of identities.
 // In particular, this structure allows for definition of
 //  - a group of identities that are member of the same MSP
@@ -75,7 +110,7 @@ func (MSPRole_MSPRoleType) EnumDescriptor() ([]byte, []int) { return fileDescrip
 // Expressing these groups is done given two fields of the fields below
 //  - Classification, that defines the type of classification of identities
 //    in an MSP this principal would be defined on; Classification can take
-//    three values:
+//    the following values:
 //     (i)  ByMSPRole: that represents a classification of identities within
 //          MSP based on one of the two pre-defined MSP rules, "member" and "admin"
 //     (ii) ByOrganizationUnit: that represents a classification of identities
@@ -83,6 +118,10 @@ func (MSPRole_MSPRoleType) EnumDescriptor() ([]byte, []int) { return fileDescrip
 //     (iii)ByIdentity that denotes that MSPPrincipal is mapped to a single
 //          identity/certificate; this would mean that the Principal bytes
 //          message
+//     (iv) ByAnonymity that represents a classification of identities
+//          based on whether they are nominal or anonymous
+//     (v)  ByCombination that denotes that MSPPrincipal is the combination
+//          of other principals, e.g. a role and an organization unit
 type MSPPrincipal struct {
 	// Classification describes the way that one should process
 	// Principal. An Classification value of "ByOrganizationUnit" reflects
@@ -142,41 +181,84 @@ func (m *MSPRole) String() string            { return proto.CompactTextString(m)
 func (*MSPRole) ProtoMessage()               {}
 func (*MSPRole) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{2} }
 
+// MSPIdentityAnonymity governs the organization of the Principal
+// field of an MSPPrincipal when it aims to define whether an identity
+// is nominal or anonymous.
+type MSPIdentityAnonymity struct {
+	AnonymityType MSPIdentityAnonymity_MSPIdentityAnonymityType `protobuf:"varint,1,opt,name=anonymity_type,json=anonymityType,enum=common.MSPIdentityAnonymity_MSPIdentityAnonymityType" json:"anonymity_type,omitempty"`
+}
+
+func (m *MSPIdentityAnonymity) Reset()                    { *m = MSPIdentityAnonymity{} }
+func (m *MSPIdentityAnonymity) String() string            { return proto.CompactTextString(m) }
+func (*MSPIdentityAnonymity) ProtoMessage()               {}
+func (*MSPIdentityAnonymity) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{3} }
+
+// CombinedPrincipal governs the organization of the Principal
+// field of an MSPPrincipal when it aims to combine other principals,
+// e.g. a role and an organization unit.
+type CombinedPrincipal struct {
+	// Principals refer to the principals an identity has to
+	// satisfy all together
+	Principals []*MSPPrincipal `protobuf:"bytes,1,rep,name=principals" json:"principals,omitempty"`
+}
+
+func (m *CombinedPrincipal) Reset()                    { *m = CombinedPrincipal{} }
+func (m *CombinedPrincipal) String() string            { return proto.CompactTextString(m) }
+func (*CombinedPrincipal) ProtoMessage()               {}
+func (*CombinedPrincipal) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{4} }
+
+func (m *CombinedPrincipal) GetPrincipals() []*MSPPrincipal {
+	if m != nil {
+		return m.Principals
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*MSPPrincipal)(nil), "common.MSPPrincipal")
 	proto.RegisterType((*OrganizationUnit)(nil), "common.OrganizationUnit")
 	proto.RegisterType((*MSPRole)(nil), "common.MSPRole")
+	proto.RegisterType((*MSPIdentityAnonymity)(nil), "common.MSPIdentityAnonymity")
+	proto.RegisterType((*CombinedPrincipal)(nil), "common.CombinedPrincipal")
 	proto.RegisterEnum("common.MSPPrincipal_Classification", MSPPrincipal_Classification_name, MSPPrincipal_Classification_value)
 	proto.RegisterEnum("common.MSPRole_MSPRoleType", MSPRole_MSPRoleType_name, MSPRole_MSPRoleType_value)
+	proto.RegisterEnum("common.MSPIdentityAnonymity_MSPIdentityAnonymityType", MSPIdentityAnonymity_MSPIdentityAnonymityType_name, MSPIdentityAnonymity_MSPIdentityAnonymityType_value)
 }
 
 func init() { proto.RegisterFile("msp/msp_principal.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 386 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x92, 0xdf, 0x8e, 0x93, 0x40,
-	0x14, 0x87, 0x77, 0xea, 0x5a, 0xb7, 0xc7, 0x4a, 0x70, 0xe2, 0x66, 0x9b, 0xb8, 0x31, 0x1b, 0xac,
-	0x49, 0xaf, 0x20, 0x69, 0x1f, 0xc0, 0xb4, 0x96, 0x18, 0x12, 0xf9, 0x93, 0x29, 0xbd, 0xb0, 0x17,
-	0x12, 0x4a, 0xa7, 0x74, 0x12, 0x60, 0x26, 0x03, 0xbd, 0xa8, 0x2f, 0xe0, 0x0b, 0xf9, 0x1a, 0xbe,
-	0x93, 0x01, 0x2c, 0x9d, 0x7a, 0xb5, 0x57, 0x30, 0xe7, 0xf7, 0x7d, 0x67, 0x66, 0xe0, 0xc0, 0x43,
-	0x5e, 0x0a, 0x2b, 0x2f, 0x45, 0x24, 0x24, 0x2b, 0x12, 0x26, 0xe2, 0xcc, 0x14, 0x92, 0x57, 0x1c,
-	0xf7, 0x13, 0x9e, 0xe7, 0xbc, 0x30, 0xfe, 0x20, 0x18, 0xba, 0xab, 0x20, 0x38, 0xc7, 0xf8, 0x07,
-	0x8c, 0x3a, 0x36, 0x4a, 0xb2, 0xb8, 0x2c, 0xd9, 0x9e, 0x25, 0x71, 0xc5, 0x78, 0x31, 0x42, 0x4f,
-	0x68, 0xa2, 0x4d, 0x3f, 0x9a, 0xad, 0x6b, 0xaa, 0x9e, 0xf9, 0xe5, 0x0a, 0x25, 0x0f, 0x5d, 0x93,
-	0xeb, 0x00, 0x3f, 0xc2, 0xa0, 0x8b, 0x46, 0xbd, 0x27, 0x34, 0x19, 0x92, 0x4b, 0xc1, 0xf8, 0x0c,
-	0xda, 0x7f, 0xfc, 0x1d, 0xdc, 0x12, 0xff, 0x9b, 0xad, 0xdf, 0xe0, 0x7b, 0x78, 0xeb, 0x93, 0xaf,
-	0x73, 0xcf, 0xd9, 0xcc, 0x43, 0xc7, 0xf7, 0xa2, 0xb5, 0xe7, 0x84, 0x3a, 0xc2, 0x43, 0xb8, 0x73,
-	0x96, 0xb6, 0x17, 0x3a, 0xe1, 0x77, 0xbd, 0x67, 0xfc, 0x46, 0xa0, 0xfb, 0x32, 0x8d, 0x0b, 0xf6,
-	0xb3, 0xf1, 0xd7, 0x05, 0xab, 0xf0, 0x27, 0xd0, 0xea, 0x6f, 0xc0, 0x76, 0xb4, 0xa8, 0xd8, 0x9e,
-	0x51, 0xd9, 0xdc, 0x64, 0x40, 0xde, 0xe4, 0xa5, 0x70, 0xba, 0x22, 0x5e, 0xc2, 0x07, 0xae, 0xa8,
-	0x71, 0x16, 0x1d, 0x0b, 0x56, 0xa9, 0x5a, 0xaf, 0xd1, 0x1e, 0xaf, 0xa9, 0x7a, 0x0b, 0xa5, 0xcb,
-	0x0c, 0xee, 0x13, 0x2a, 0xdb, 0x45, 0xa9, 0xca, 0x2f, 0x9a, 0xcb, 0xbe, 0xbb, 0x84, 0x17, 0xc9,
-	0xf8, 0x85, 0xe0, 0x95, 0xbb, 0x0a, 0x08, 0xcf, 0xe8, 0x73, 0x4f, 0x6b, 0xc1, 0xad, 0xe4, 0x19,
-	0x6d, 0xce, 0xa4, 0x4d, 0xdf, 0x2b, 0x3f, 0xa5, 0xee, 0x72, 0x7e, 0x86, 0x27, 0x41, 0x49, 0x03,
-	0x1a, 0x63, 0x78, 0xad, 0x14, 0x31, 0x40, 0xdf, 0xb5, 0xdd, 0x85, 0x4d, 0xf4, 0x1b, 0x3c, 0x80,
-	0x97, 0xf3, 0xa5, 0xeb, 0x78, 0x3a, 0x5a, 0x04, 0x30, 0xe6, 0x32, 0x35, 0x0f, 0x27, 0x41, 0x65,
-	0x46, 0x77, 0x29, 0x95, 0xe6, 0x3e, 0xde, 0x4a, 0x96, 0xb4, 0x83, 0x53, 0xfe, 0xdb, 0x67, 0x33,
-	0x49, 0x59, 0x75, 0x38, 0x6e, 0xeb, 0xa5, 0xa5, 0xc0, 0x56, 0x0b, 0x5b, 0x2d, 0x5c, 0x8f, 0xde,
-	0xb6, 0xdf, 0xbc, 0xcf, 0xfe, 0x06, 0x00, 0x00, 0xff, 0xff, 0xf1, 0xd3, 0xfe, 0xdb, 0x8c, 0x02,
-	0x00, 0x00,
+	// 499 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0xcd, 0x6e, 0xda, 0x40,
+	0x10, 0xc7, 0x59, 0xa0, 0x24, 0x0c, 0x1f, 0x72, 0x56, 0x44, 0x41, 0x6a, 0x54, 0x21, 0x37, 0x95,
+	0x38, 0x19, 0x89, 0xb4, 0xbd, 0x9b, 0x0f, 0x55, 0x96, 0xe2, 0x35, 0x32, 0x70, 0x48, 0x14, 0x15,
+	0x19, 0xb3, 0x90, 0x95, 0xec, 0x5d, 0x6b, 0xed, 0x1c, 0xdc, 0x17, 0xe8, 0xcb, 0xf4, 0xd8, 0xa7,
+	0xea, 0x53, 0x54, 0xb6, 0x03, 0x2c, 0x6d, 0x2a, 0xf5, 0x04, 0x33, 0xf3, 0xfb, 0xff, 0x67, 0xbc,
+	0x3b, 0x0b, 0x57, 0x61, 0x1c, 0x0d, 0xc2, 0x38, 0x5a, 0x45, 0x92, 0x71, 0x9f, 0x45, 0x5e, 0x60,
+	0x44, 0x52, 0x24, 0x02, 0xd7, 0x7c, 0x11, 0x86, 0x82, 0xeb, 0xbf, 0x10, 0x34, 0xed, 0xf9, 0x6c,
+	0xb6, 0x2f, 0xe3, 0xaf, 0xd0, 0x3d, 0xb0, 0x2b, 0x3f, 0xf0, 0xe2, 0x98, 0x6d, 0x99, 0xef, 0x25,
+	0x4c, 0xf0, 0x2e, 0xea, 0xa1, 0x7e, 0x7b, 0xf8, 0xde, 0x28, 0xb4, 0x86, 0xaa, 0x33, 0xc6, 0x27,
+	0xa8, 0x7b, 0x75, 0x30, 0x39, 0x2d, 0xe0, 0x6b, 0xa8, 0x1f, 0x4a, 0xdd, 0x72, 0x0f, 0xf5, 0x9b,
+	0xee, 0x31, 0xa1, 0x3f, 0x42, 0xfb, 0x0f, 0xfe, 0x1c, 0xaa, 0xae, 0x73, 0x37, 0xd5, 0x4a, 0xf8,
+	0x12, 0x2e, 0x1c, 0xf7, 0x8b, 0x49, 0xac, 0x07, 0x73, 0x61, 0x39, 0x64, 0xb5, 0x24, 0xd6, 0x42,
+	0x43, 0xb8, 0x09, 0xe7, 0xd6, 0x64, 0x4a, 0x16, 0xd6, 0xe2, 0x5e, 0x2b, 0xe3, 0x16, 0xd4, 0x4d,
+	0xe2, 0x90, 0x7b, 0x3b, 0x0b, 0x2b, 0x59, 0x71, 0xec, 0xd8, 0x23, 0x8b, 0x4c, 0x27, 0x5a, 0x55,
+	0xff, 0x89, 0x40, 0x73, 0xe4, 0xce, 0xe3, 0xec, 0x5b, 0x6e, 0xbe, 0xe4, 0x2c, 0xc1, 0x1f, 0xa0,
+	0x9d, 0x1d, 0x10, 0xdb, 0x50, 0x9e, 0xb0, 0x2d, 0xa3, 0x32, 0xff, 0xcc, 0xba, 0xdb, 0x0a, 0xe3,
+	0xc8, 0x3a, 0x24, 0xf1, 0x04, 0xde, 0x09, 0x45, 0xea, 0x05, 0xab, 0x67, 0xce, 0x12, 0x55, 0x56,
+	0xce, 0x65, 0xd7, 0xa7, 0x54, 0xd6, 0x42, 0x71, 0xb9, 0x85, 0x4b, 0x9f, 0xca, 0x22, 0x88, 0x55,
+	0x71, 0x25, 0x3f, 0x89, 0xce, 0xb1, 0x78, 0x14, 0xe9, 0xdf, 0x11, 0x9c, 0xd9, 0xf3, 0x99, 0x2b,
+	0x02, 0xfa, 0xbf, 0xd3, 0x0e, 0xa0, 0x2a, 0x45, 0x40, 0xf3, 0x99, 0xda, 0xc3, 0xb7, 0xca, 0x8d,
+	0x65, 0x2e, 0xfb, 0xdf, 0x45, 0x1a, 0x51, 0x37, 0x07, 0xf5, 0x1b, 0x68, 0x28, 0x49, 0x0c, 0x50,
+	0xb3, 0xa7, 0xf6, 0x68, 0xea, 0x6a, 0x25, 0x5c, 0x87, 0x37, 0xe6, 0xc4, 0xb6, 0x88, 0x86, 0xf4,
+	0x1f, 0x08, 0x3a, 0xf6, 0x7c, 0x56, 0x34, 0x4a, 0x52, 0x93, 0x0b, 0x9e, 0x86, 0x2c, 0x49, 0xf1,
+	0x23, 0xb4, 0xbd, 0x7d, 0xb0, 0x4a, 0xd2, 0x88, 0xbe, 0xec, 0xca, 0x27, 0xa5, 0xf3, 0x5f, 0xaa,
+	0x57, 0x93, 0xf9, 0x4c, 0x2d, 0x4f, 0x0d, 0xf5, 0xcf, 0xd0, 0xfd, 0x17, 0x8a, 0x1b, 0x70, 0x46,
+	0x1c, 0xdb, 0x22, 0xe6, 0x9d, 0x56, 0x3a, 0xde, 0xbe, 0xb3, 0x9c, 0x6b, 0x48, 0xb7, 0xe0, 0x62,
+	0x2c, 0xc2, 0x35, 0xe3, 0x74, 0x73, 0x5c, 0xf0, 0x8f, 0x00, 0x87, 0x7d, 0x8b, 0xbb, 0xa8, 0x57,
+	0xe9, 0x37, 0x86, 0x9d, 0xd7, 0x56, 0xda, 0x55, 0xb8, 0xd1, 0x0c, 0x6e, 0x84, 0xdc, 0x19, 0x4f,
+	0x69, 0x44, 0x65, 0x40, 0x37, 0x3b, 0x2a, 0x8d, 0xad, 0xb7, 0x96, 0xcc, 0x2f, 0xde, 0x53, 0xfc,
+	0x62, 0xf0, 0xd0, 0xdf, 0xb1, 0xe4, 0xe9, 0x79, 0x9d, 0x85, 0x03, 0x05, 0x1e, 0x14, 0xf0, 0xa0,
+	0x80, 0xb3, 0x17, 0xb9, 0xae, 0xe5, 0xff, 0x6f, 0x7f, 0x0f, 0x00, 0x53, 0x4f, 0xee, 0xd0, 0xa3,
+	0x03, 0x00, 0x00,
 }
@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package msp
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// This file describes the marshaled messages held by the bytes fields of the
+// messages of this package, for common/tools/protolator
+
+// VariablyOpaqueFields implements protolator.VariablyOpaqueFieldProto
+func (mc *MSPConfig) VariablyOpaqueFields() []string {
+	return []string{"config"}
+}
+
+// VariablyOpaqueFieldProto implements protolator.VariablyOpaqueFieldProto
+// The config of MSPs of other types than the default one is left as bytes
+func (mc *MSPConfig) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "config" {
+		return nil, fmt.Errorf("%T has no opaque field %s", mc, name)
+	}
+	if mc.Type != 0 {
+		return nil, nil
+	}
+	return &FabricMSPConfig{}, nil
+}
+
+// VariablyOpaqueFields implements protolator.VariablyOpaqueFieldProto
+func (mp *MSPPrincipal) VariablyOpaqueFields() []string {
+	return []string{"principal"}
+}
+
+// VariablyOpaqueFieldProto implements protolator.VariablyOpaqueFieldProto
+func (mp *MSPPrincipal) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "principal" {
+		return nil, fmt.Errorf("%T has no opaque field %s", mp, name)
+	}
+	switch mp.PrincipalClassification {
+	case MSPPrincipal_ROLE:
+		return &MSPRole{}, nil
+	case MSPPrincipal_ORGANIZATION_UNIT:
+		return &OrganizationUnit{}, nil
+	case MSPPrincipal_IDENTITY:
+		return &SerializedIdentity{}, nil
+	}
+	return nil, nil
+}
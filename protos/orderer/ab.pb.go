@@ -9,6 +9,7 @@ It is generated from these files:
 	orderer/ab.proto
 	orderer/configuration.proto
 	orderer/kafka.proto
+	orderer/cosign.proto
 
 It has these top-level messages:
 	BroadcastResponse
@@ -30,6 +31,8 @@ It has these top-level messages:
 	KafkaMessageTimeToCut
 	KafkaMessageConnect
 	KafkaMetadata
+	CosignRequest
+	CosignResponse
 */
 package orderer
 
@@ -76,7 +79,10 @@ func (x SeekInfo_SeekBehavior) String() string {
 func (SeekInfo_SeekBehavior) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{5, 0} }
 
 type BroadcastResponse struct {
+	// Status code, which may be used to programmatically respond to success/failure
 	Status common.Status `protobuf:"varint,1,opt,name=status,enum=common.Status" json:"status,omitempty"`
+	// Info string which may contain additional information about the status returned
+	Info string `protobuf:"bytes,2,opt,name=info" json:"info,omitempty"`
 }
 
 func (m *BroadcastResponse) Reset()                    { *m = BroadcastResponse{} }
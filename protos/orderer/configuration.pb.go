@@ -13,8 +13,32 @@ var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
+// State defines the orientation of the orderer, in the normal state
+// transactions are ordered, in maintenance only config transactions are
+type ConsensusType_State int32
+
+const (
+	ConsensusType_STATE_NORMAL      ConsensusType_State = 0
+	ConsensusType_STATE_MAINTENANCE ConsensusType_State = 1
+)
+
+var ConsensusType_State_name = map[int32]string{
+	0: "STATE_NORMAL",
+	1: "STATE_MAINTENANCE",
+}
+var ConsensusType_State_value = map[string]int32{
+	"STATE_NORMAL":      0,
+	"STATE_MAINTENANCE": 1,
+}
+
+func (x ConsensusType_State) String() string {
+	return proto.EnumName(ConsensusType_State_name, int32(x))
+}
+func (ConsensusType_State) EnumDescriptor() ([]byte, []int) { return fileDescriptor1, []int{0, 0} }
+
 type ConsensusType struct {
-	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	Type  string              `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	State ConsensusType_State `protobuf:"varint,2,opt,name=state,enum=orderer.ConsensusType_State" json:"state,omitempty"`
 }
 
 func (m *ConsensusType) Reset()                    { *m = ConsensusType{} }
@@ -111,6 +135,7 @@ func init() {
 	proto.RegisterType((*ChainCreationPolicyNames)(nil), "orderer.ChainCreationPolicyNames")
 	proto.RegisterType((*KafkaBrokers)(nil), "orderer.KafkaBrokers")
 	proto.RegisterType((*ChannelRestrictions)(nil), "orderer.ChannelRestrictions")
+	proto.RegisterEnum("orderer.ConsensusType_State", ConsensusType_State_name, ConsensusType_State_value)
 }
 
 func init() { proto.RegisterFile("orderer/configuration.proto", fileDescriptor1) }
@@ -0,0 +1,162 @@
+// Code generated by protoc-gen-go.
+// source: orderer/cosign.proto
+// DO NOT EDIT!
+
+package orderer
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import common "github.com/hyperledger/fabric/protos/common"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// CosignRequest asks an ordering node to sign the header of a block of a channel
+type CosignRequest struct {
+	ChannelId string              `protobuf:"bytes,1,opt,name=channel_id,json=channelId" json:"channel_id,omitempty"`
+	Header    *common.BlockHeader `protobuf:"bytes,2,opt,name=header" json:"header,omitempty"`
+}
+
+func (m *CosignRequest) Reset()                    { *m = CosignRequest{} }
+func (m *CosignRequest) String() string            { return proto.CompactTextString(m) }
+func (*CosignRequest) ProtoMessage()               {}
+func (*CosignRequest) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{0} }
+
+func (m *CosignRequest) GetHeader() *common.BlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+type CosignResponse struct {
+	Status    common.Status             `protobuf:"varint,1,opt,name=status,enum=common.Status" json:"status,omitempty"`
+	Signature *common.MetadataSignature `protobuf:"bytes,2,opt,name=signature" json:"signature,omitempty"`
+	Info      string                    `protobuf:"bytes,3,opt,name=info" json:"info,omitempty"`
+}
+
+func (m *CosignResponse) Reset()                    { *m = CosignResponse{} }
+func (m *CosignResponse) String() string            { return proto.CompactTextString(m) }
+func (*CosignResponse) ProtoMessage()               {}
+func (*CosignResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{1} }
+
+func (m *CosignResponse) GetSignature() *common.MetadataSignature {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*CosignRequest)(nil), "orderer.CosignRequest")
+	proto.RegisterType((*CosignResponse)(nil), "orderer.CosignResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion3
+
+// Client API for BlockCosigner service
+
+type BlockCosignerClient interface {
+	// Cosign returns the signature of the ordering node over the header of a block, once it
+	// produced the same block itself, so that blocks may carry the signatures of several ordering nodes
+	Cosign(ctx context.Context, in *CosignRequest, opts ...grpc.CallOption) (*CosignResponse, error)
+}
+
+type blockCosignerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBlockCosignerClient(cc *grpc.ClientConn) BlockCosignerClient {
+	return &blockCosignerClient{cc}
+}
+
+func (c *blockCosignerClient) Cosign(ctx context.Context, in *CosignRequest, opts ...grpc.CallOption) (*CosignResponse, error) {
+	out := new(CosignResponse)
+	err := grpc.Invoke(ctx, "/orderer.BlockCosigner/Cosign", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for BlockCosigner service
+
+type BlockCosignerServer interface {
+	// Cosign returns the signature of the ordering node over the header of a block, once it
+	// produced the same block itself, so that blocks may carry the signatures of several ordering nodes
+	Cosign(context.Context, *CosignRequest) (*CosignResponse, error)
+}
+
+func RegisterBlockCosignerServer(s *grpc.Server, srv BlockCosignerServer) {
+	s.RegisterService(&_BlockCosigner_serviceDesc, srv)
+}
+
+func _BlockCosigner_Cosign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CosignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockCosignerServer).Cosign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/orderer.BlockCosigner/Cosign",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockCosignerServer).Cosign(ctx, req.(*CosignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _BlockCosigner_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "orderer.BlockCosigner",
+	HandlerType: (*BlockCosignerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Cosign",
+			Handler:    _BlockCosigner_Cosign_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: fileDescriptor3,
+}
+
+func init() { proto.RegisterFile("orderer/cosign.proto", fileDescriptor3) }
+
+var fileDescriptor3 = []byte{
+	// 290 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x90, 0xc1, 0x4b, 0xc3, 0x30,
+	0x14, 0xc6, 0xad, 0x4a, 0x65, 0x4f, 0xb6, 0x43, 0x26, 0x5a, 0x07, 0xc2, 0x28, 0x28, 0x03, 0x25,
+	0x81, 0x7a, 0xf0, 0xe0, 0x6d, 0x5e, 0x14, 0xf4, 0xd2, 0xe1, 0x45, 0x0f, 0x92, 0x36, 0x6f, 0x6d,
+	0xb1, 0x4b, 0x6a, 0x92, 0x1e, 0xfc, 0x03, 0xfc, 0xbf, 0x65, 0x49, 0x8a, 0x8a, 0xa7, 0xbc, 0x7c,
+	0xef, 0xf7, 0x5e, 0xbe, 0x7c, 0x70, 0xa4, 0xb4, 0x40, 0x8d, 0x9a, 0x95, 0xca, 0x34, 0x95, 0xa4,
+	0x9d, 0x56, 0x56, 0x91, 0x83, 0xa0, 0xce, 0xa6, 0xa5, 0xda, 0x6c, 0x94, 0x64, 0xfe, 0xf0, 0xdd,
+	0xf4, 0x15, 0xc6, 0x77, 0x8e, 0xce, 0xf1, 0xa3, 0x47, 0x63, 0xc9, 0x19, 0x40, 0x59, 0x73, 0x29,
+	0xb1, 0x7d, 0x6b, 0x44, 0x12, 0xcd, 0xa3, 0xc5, 0x28, 0x1f, 0x05, 0xe5, 0x41, 0x90, 0x4b, 0x88,
+	0x6b, 0xe4, 0x02, 0x75, 0xb2, 0x3b, 0x8f, 0x16, 0x87, 0xd9, 0x94, 0x86, 0x75, 0xcb, 0x56, 0x95,
+	0xef, 0xf7, 0xae, 0x95, 0x07, 0x24, 0xfd, 0x8a, 0x60, 0x32, 0x6c, 0x37, 0x9d, 0x92, 0x06, 0xc9,
+	0x05, 0xc4, 0xc6, 0x72, 0xdb, 0x1b, 0xb7, 0x7a, 0x92, 0x4d, 0x86, 0xf9, 0x95, 0x53, 0xf3, 0xd0,
+	0x25, 0x37, 0x30, 0xda, 0xce, 0x71, 0xdb, 0x6b, 0x0c, 0x4f, 0x9d, 0x0e, 0xe8, 0x13, 0x5a, 0x2e,
+	0xb8, 0xe5, 0xab, 0x01, 0xc8, 0x7f, 0x58, 0x42, 0x60, 0xbf, 0x91, 0x6b, 0x95, 0xec, 0x39, 0xe7,
+	0xae, 0xce, 0x1e, 0x61, 0xec, 0xec, 0x79, 0x2f, 0xa8, 0xc9, 0x2d, 0xc4, 0xbe, 0x26, 0xc7, 0x34,
+	0xc4, 0x43, 0xff, 0xc4, 0x30, 0x3b, 0xf9, 0xa7, 0xfb, 0x0f, 0xa4, 0x3b, 0xcb, 0x67, 0x38, 0x57,
+	0xba, 0xa2, 0xf5, 0x67, 0x87, 0xba, 0x45, 0x51, 0xa1, 0xa6, 0x6b, 0x5e, 0xe8, 0xa6, 0xf4, 0x91,
+	0x9a, 0x61, 0xf2, 0xe5, 0xaa, 0x6a, 0x6c, 0xdd, 0x17, 0x5b, 0xdb, 0xec, 0x17, 0xcd, 0x3c, 0xcd,
+	0x3c, 0xcd, 0x02, 0x5d, 0xc4, 0xee, 0x7e, 0xfd, 0x3d, 0x00, 0x56, 0x38, 0x6d, 0x1f, 0xc6, 0x01,
+	0x00, 0x00,
+}
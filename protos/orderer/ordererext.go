@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orderer
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// Registers the messages of this package carried by the messages of
+// protos/common, for common/tools/protolator
+func init() {
+	common.RegisterPayloadDataProto(common.HeaderType_DELIVER_SEEK_INFO, func() proto.Message { return &SeekInfo{} })
+
+	for key, factory := range map[string]func() proto.Message{
+		"ConsensusType":            func() proto.Message { return &ConsensusType{} },
+		"BatchSize":                func() proto.Message { return &BatchSize{} },
+		"BatchTimeout":             func() proto.Message { return &BatchTimeout{} },
+		"ChainCreationPolicyNames": func() proto.Message { return &ChainCreationPolicyNames{} },
+		"KafkaBrokers":             func() proto.Message { return &KafkaBrokers{} },
+		"ChannelRestrictions":      func() proto.Message { return &ChannelRestrictions{} },
+		"CreationPolicy":           func() proto.Message { return &CreationPolicy{} },
+	} {
+		common.RegisterConfigValueProto(common.OrdererGroupType, key, factory)
+	}
+}
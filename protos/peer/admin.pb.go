@@ -58,6 +58,8 @@ It has these top-level messages:
 	Response
 	ProposalResponsePayload
 	Endorsement
+	ChaincodeInterest
+	ChaincodeCall
 	ChaincodeQueryResponse
 	ChaincodeInfo
 	ChannelQueryResponse
@@ -41,6 +41,8 @@ const (
 	ChaincodeMessage_QUERY_STATE_CLOSE   ChaincodeMessage_Type = 17
 	ChaincodeMessage_KEEPALIVE           ChaincodeMessage_Type = 18
 	ChaincodeMessage_GET_HISTORY_FOR_KEY ChaincodeMessage_Type = 19
+	ChaincodeMessage_GET_STATE_METADATA  ChaincodeMessage_Type = 20
+	ChaincodeMessage_PUT_STATE_METADATA  ChaincodeMessage_Type = 21
 )
 
 var ChaincodeMessage_Type_name = map[int32]string{
@@ -63,6 +65,8 @@ var ChaincodeMessage_Type_name = map[int32]string{
 	17: "QUERY_STATE_CLOSE",
 	18: "KEEPALIVE",
 	19: "GET_HISTORY_FOR_KEY",
+	20: "GET_STATE_METADATA",
+	21: "PUT_STATE_METADATA",
 }
 var ChaincodeMessage_Type_value = map[string]int32{
 	"UNDEFINED":           0,
@@ -84,6 +88,8 @@ var ChaincodeMessage_Type_value = map[string]int32{
 	"QUERY_STATE_CLOSE":   17,
 	"KEEPALIVE":           18,
 	"GET_HISTORY_FOR_KEY": 19,
+	"GET_STATE_METADATA":  20,
+	"PUT_STATE_METADATA":  21,
 }
 
 func (x ChaincodeMessage_Type) String() string {
@@ -185,6 +191,66 @@ func (m *QueryStateClose) String() string            { return proto.CompactTextS
 func (*QueryStateClose) ProtoMessage()               {}
 func (*QueryStateClose) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{6} }
 
+// GetStateMetadata is the payload of a GET_STATE_METADATA message, which
+// reads the metadata of a key
+type GetStateMetadata struct {
+	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+}
+
+func (m *GetStateMetadata) Reset()                    { *m = GetStateMetadata{} }
+func (m *GetStateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*GetStateMetadata) ProtoMessage()               {}
+func (*GetStateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{7} }
+
+// PutStateMetadata is the payload of a PUT_STATE_METADATA message, which sets
+// one entry of the metadata of a key, an empty value removing it
+type PutStateMetadata struct {
+	Key      string         `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Metadata *StateMetadata `protobuf:"bytes,2,opt,name=metadata" json:"metadata,omitempty"`
+}
+
+func (m *PutStateMetadata) Reset()                    { *m = PutStateMetadata{} }
+func (m *PutStateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*PutStateMetadata) ProtoMessage()               {}
+func (*PutStateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{8} }
+
+func (m *PutStateMetadata) GetMetadata() *StateMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// StateMetadata is an entry of the metadata of a key, e.g. its key-level
+// validation parameter
+type StateMetadata struct {
+	Metakey string `protobuf:"bytes,1,opt,name=metakey" json:"metakey,omitempty"`
+	Value   []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *StateMetadata) Reset()                    { *m = StateMetadata{} }
+func (m *StateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*StateMetadata) ProtoMessage()               {}
+func (*StateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{9} }
+
+// StateMetadataResult is the payload of the response to a GET_STATE_METADATA
+// message
+type StateMetadataResult struct {
+	Entries []*StateMetadata `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *StateMetadataResult) Reset()                    { *m = StateMetadataResult{} }
+func (m *StateMetadataResult) String() string            { return proto.CompactTextString(m) }
+func (*StateMetadataResult) ProtoMessage()               {}
+func (*StateMetadataResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{10} }
+
+func (m *StateMetadataResult) GetEntries() []*StateMetadata {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
 type QueryResultBytes struct {
 	ResultBytes []byte `protobuf:"bytes,1,opt,name=resultBytes,proto3" json:"resultBytes,omitempty"`
 }
@@ -192,7 +258,7 @@ type QueryResultBytes struct {
 func (m *QueryResultBytes) Reset()                    { *m = QueryResultBytes{} }
 func (m *QueryResultBytes) String() string            { return proto.CompactTextString(m) }
 func (*QueryResultBytes) ProtoMessage()               {}
-func (*QueryResultBytes) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{7} }
+func (*QueryResultBytes) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{11} }
 
 type QueryResponse struct {
 	Results []*QueryResultBytes `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
@@ -203,7 +269,7 @@ type QueryResponse struct {
 func (m *QueryResponse) Reset()                    { *m = QueryResponse{} }
 func (m *QueryResponse) String() string            { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()               {}
-func (*QueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{8} }
+func (*QueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{12} }
 
 func (m *QueryResponse) GetResults() []*QueryResultBytes {
 	if m != nil {
@@ -220,6 +286,10 @@ func init() {
 	proto.RegisterType((*GetHistoryForKey)(nil), "protos.GetHistoryForKey")
 	proto.RegisterType((*QueryStateNext)(nil), "protos.QueryStateNext")
 	proto.RegisterType((*QueryStateClose)(nil), "protos.QueryStateClose")
+	proto.RegisterType((*GetStateMetadata)(nil), "protos.GetStateMetadata")
+	proto.RegisterType((*PutStateMetadata)(nil), "protos.PutStateMetadata")
+	proto.RegisterType((*StateMetadata)(nil), "protos.StateMetadata")
+	proto.RegisterType((*StateMetadataResult)(nil), "protos.StateMetadataResult")
 	proto.RegisterType((*QueryResultBytes)(nil), "protos.QueryResultBytes")
 	proto.RegisterType((*QueryResponse)(nil), "protos.QueryResponse")
 	proto.RegisterEnum("protos.ChaincodeMessage_Type", ChaincodeMessage_Type_name, ChaincodeMessage_Type_value)
@@ -332,54 +402,59 @@ var _ChaincodeSupport_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor3) }
 
 var fileDescriptor3 = []byte{
-	// 771 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x94, 0x6f, 0x6f, 0xe2, 0x46,
-	0x10, 0xc6, 0x8f, 0x3f, 0x49, 0x60, 0x20, 0xb0, 0xb7, 0xb9, 0xa6, 0x3e, 0xa4, 0xaa, 0xd4, 0xaa,
-	0x2a, 0xfa, 0xc6, 0xb4, 0xb4, 0xaa, 0xfa, 0xae, 0x22, 0xb0, 0x21, 0x56, 0x88, 0xcd, 0xad, 0x9d,
-	0xd3, 0xd1, 0x37, 0x96, 0x03, 0x1b, 0x63, 0x15, 0x58, 0x77, 0x77, 0x39, 0x9d, 0x3f, 0x61, 0xbf,
-	0x4c, 0x3f, 0xc4, 0x69, 0x6d, 0x4c, 0xb8, 0x9c, 0xf2, 0xca, 0x7e, 0x66, 0x7e, 0xf3, 0xcc, 0xec,
-	0x6a, 0x35, 0xf0, 0x36, 0x61, 0x4c, 0xf4, 0x17, 0xab, 0x30, 0xde, 0x2e, 0xf8, 0x92, 0x05, 0x72,
-	0x15, 0x6f, 0xac, 0x44, 0x70, 0xc5, 0xf1, 0x69, 0xf6, 0x91, 0x9d, 0xce, 0x33, 0x84, 0x7d, 0x64,
-	0x5b, 0x95, 0x33, 0x9d, 0x8b, 0x2c, 0x97, 0x08, 0x9e, 0x70, 0x19, 0xae, 0xf7, 0xc1, 0xef, 0x23,
-	0xce, 0xa3, 0x35, 0xeb, 0x67, 0xea, 0x61, 0xf7, 0xd8, 0x57, 0xf1, 0x86, 0x49, 0x15, 0x6e, 0x92,
-	0x1c, 0x30, 0xff, 0xaf, 0x02, 0x1a, 0x15, 0x7e, 0x77, 0x4c, 0xca, 0x30, 0x62, 0xf8, 0x57, 0xa8,
-	0xaa, 0x34, 0x61, 0x46, 0xa9, 0x5b, 0xea, 0xb5, 0x06, 0xdf, 0xe5, 0xa8, 0xb4, 0x9e, 0x73, 0x96,
-	0x9f, 0x26, 0x8c, 0x66, 0x28, 0xfe, 0x13, 0xea, 0x07, 0x6b, 0xa3, 0xdc, 0x2d, 0xf5, 0x1a, 0x83,
-	0x8e, 0x95, 0x37, 0xb7, 0x8a, 0xe6, 0x96, 0x5f, 0x10, 0xf4, 0x09, 0xc6, 0x06, 0x9c, 0x25, 0x61,
-	0xba, 0xe6, 0xe1, 0xd2, 0xa8, 0x74, 0x4b, 0xbd, 0x26, 0x2d, 0x24, 0xc6, 0x50, 0x55, 0x9f, 0xe2,
-	0xa5, 0x51, 0xed, 0x96, 0x7a, 0x75, 0x9a, 0xfd, 0xe3, 0x01, 0xd4, 0x8a, 0x23, 0x1a, 0x27, 0x59,
-	0x9b, 0xcb, 0x62, 0x3c, 0x2f, 0x8e, 0xb6, 0x6c, 0x39, 0xdb, 0x67, 0xe9, 0x81, 0xc3, 0x7f, 0x41,
-	0xfb, 0xd9, 0x95, 0x19, 0xa7, 0x5f, 0x96, 0x1e, 0x4e, 0x46, 0x74, 0x96, 0xb6, 0x16, 0x5f, 0x68,
-	0xf3, 0xbf, 0x32, 0x54, 0xf5, 0x59, 0xf1, 0x39, 0xd4, 0xef, 0x9d, 0x31, 0xb9, 0xb6, 0x1d, 0x32,
-	0x46, 0xaf, 0x70, 0x13, 0x6a, 0x94, 0x4c, 0x6c, 0xcf, 0x27, 0x14, 0x95, 0x70, 0x0b, 0xa0, 0x50,
-	0x64, 0x8c, 0xca, 0xb8, 0x06, 0x55, 0xdb, 0xb1, 0x7d, 0x54, 0xc1, 0x75, 0x38, 0xa1, 0x64, 0x38,
-	0x9e, 0xa3, 0x2a, 0x6e, 0x43, 0xc3, 0xa7, 0x43, 0xc7, 0x1b, 0x8e, 0x7c, 0xdb, 0x75, 0xd0, 0x89,
-	0xb6, 0x1c, 0xb9, 0x77, 0xb3, 0x29, 0xf1, 0xc9, 0x18, 0x9d, 0x6a, 0x94, 0x50, 0xea, 0x52, 0x74,
-	0xa6, 0x33, 0x13, 0xe2, 0x07, 0x9e, 0x3f, 0xf4, 0x09, 0xaa, 0x69, 0x39, 0xbb, 0x2f, 0x64, 0x5d,
-	0xcb, 0x31, 0x99, 0xee, 0x25, 0xe0, 0x37, 0x80, 0x6c, 0xe7, 0xbd, 0x7b, 0x4b, 0x82, 0xd1, 0xcd,
-	0xd0, 0x76, 0x46, 0xee, 0x98, 0xa0, 0x46, 0x3e, 0xa0, 0x37, 0x73, 0x1d, 0x8f, 0xa0, 0x73, 0x7c,
-	0x09, 0xf8, 0x60, 0x18, 0x5c, 0xcd, 0x03, 0x3a, 0x74, 0x26, 0x04, 0xb5, 0x74, 0xad, 0x8e, 0xbf,
-	0xbb, 0x27, 0x74, 0x1e, 0x50, 0xe2, 0xdd, 0x4f, 0x7d, 0xd4, 0xd6, 0xd1, 0x3c, 0x92, 0xf3, 0x0e,
-	0xf9, 0xe0, 0x23, 0x84, 0xbf, 0x81, 0xd7, 0xc7, 0xd1, 0xd1, 0xd4, 0xf5, 0x08, 0x7a, 0xad, 0xa7,
-	0xb9, 0x25, 0x64, 0x36, 0x9c, 0xda, 0xef, 0x09, 0xc2, 0xf8, 0x5b, 0xb8, 0xd0, 0x8e, 0x37, 0xb6,
-	0xe7, 0xbb, 0x74, 0x1e, 0x5c, 0xbb, 0x34, 0xb8, 0x25, 0x73, 0x74, 0x61, 0xfe, 0x01, 0xcd, 0xd9,
-	0x4e, 0x79, 0x2a, 0x54, 0xcc, 0xde, 0x3e, 0x72, 0x8c, 0xa0, 0xf2, 0x0f, 0x4b, 0xb3, 0x87, 0x56,
-	0xa7, 0xfa, 0x17, 0xbf, 0x81, 0x93, 0x8f, 0xe1, 0x7a, 0xc7, 0xb2, 0x47, 0xd4, 0xa4, 0xb9, 0x30,
-	0x09, 0xb4, 0x27, 0x2c, 0xaf, 0xbb, 0x4a, 0x69, 0xb8, 0x8d, 0x18, 0xee, 0x40, 0x4d, 0xaa, 0x50,
-	0xa8, 0xdb, 0x43, 0xfd, 0x41, 0xe3, 0x4b, 0x38, 0x65, 0xdb, 0xa5, 0xce, 0x94, 0xb3, 0xcc, 0x5e,
-	0x99, 0x3f, 0x41, 0x6b, 0xc2, 0xd4, 0xbb, 0x1d, 0x13, 0x29, 0x65, 0x72, 0xb7, 0x56, 0xba, 0xdd,
-	0xbf, 0x5a, 0xee, 0x2d, 0x72, 0x61, 0xfe, 0x08, 0x68, 0xc2, 0xd4, 0x4d, 0x2c, 0x15, 0x17, 0xe9,
-	0x35, 0x17, 0xda, 0xf3, 0xab, 0x51, 0xcd, 0x2e, 0xb4, 0x32, 0xab, 0x6c, 0x2c, 0x87, 0x7d, 0x52,
-	0xb8, 0x05, 0xe5, 0x78, 0xb9, 0x47, 0xca, 0xf1, 0xd2, 0xfc, 0x01, 0xda, 0x4f, 0xc4, 0x68, 0xcd,
-	0x25, 0xfb, 0x0a, 0xf9, 0x1d, 0xd0, 0xd1, 0x3c, 0x57, 0xa9, 0x62, 0x12, 0x77, 0xa1, 0x21, 0x9e,
-	0x64, 0x06, 0x37, 0xe9, 0x71, 0xc8, 0xdc, 0xc2, 0x79, 0x51, 0x95, 0xf0, 0xad, 0x64, 0x78, 0x00,
-	0x67, 0x79, 0x5e, 0xe3, 0x95, 0x5e, 0x63, 0x60, 0x14, 0x6f, 0xfb, 0xb9, 0x3b, 0x2d, 0x40, 0xfc,
-	0x16, 0x6a, 0xab, 0x50, 0x06, 0x1b, 0x2e, 0xf2, 0xdb, 0xae, 0xd1, 0xb3, 0x55, 0x28, 0xef, 0xb8,
-	0x28, 0xa6, 0xac, 0x14, 0x53, 0x0e, 0x3e, 0x1c, 0x6d, 0x09, 0x6f, 0x97, 0x24, 0x5c, 0x28, 0x3c,
-	0x86, 0x1a, 0x65, 0x51, 0x2c, 0x15, 0x13, 0xd8, 0x78, 0x69, 0x47, 0x74, 0x5e, 0xcc, 0x98, 0xaf,
-	0x7a, 0xa5, 0x5f, 0x4a, 0x57, 0x2e, 0x98, 0x5c, 0x44, 0xd6, 0x2a, 0x4d, 0x98, 0x58, 0xb3, 0x65,
-	0xc4, 0x84, 0xf5, 0x18, 0x3e, 0x88, 0x78, 0x51, 0xd4, 0xe9, 0xb5, 0xf6, 0xf7, 0xcf, 0x51, 0xac,
-	0x56, 0xbb, 0x07, 0x6b, 0xc1, 0x37, 0xfd, 0x23, 0xb4, 0x9f, 0xa3, 0xf9, 0x7a, 0x93, 0x7d, 0x8d,
-	0x3e, 0xe4, 0xbb, 0xf2, 0xb7, 0xcf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xad, 0x9f, 0x80, 0x68, 0x4f,
-	0x05, 0x00, 0x00,
+	// 855 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x95, 0x5d, 0x6f, 0xe2, 0x46,
+	0x14, 0x86, 0x97, 0xaf, 0x00, 0x27, 0x04, 0x66, 0x27, 0x1f, 0xf5, 0x22, 0x55, 0xa5, 0x56, 0x55,
+	0xd1, 0x1b, 0xe8, 0xd2, 0xaa, 0xea, 0xdd, 0xca, 0x81, 0x09, 0xb1, 0x02, 0x36, 0x3b, 0x36, 0xdb,
+	0xa5, 0x37, 0xc8, 0x81, 0x09, 0x58, 0x05, 0xc6, 0xf5, 0x0c, 0xab, 0xf8, 0xbf, 0xf4, 0x7f, 0xf6,
+	0xb6, 0x1a, 0x1b, 0x13, 0x48, 0x1a, 0xed, 0x95, 0xfd, 0x9e, 0xf3, 0x9c, 0xf7, 0x9c, 0xf1, 0xd8,
+	0x1e, 0x78, 0x17, 0x30, 0x16, 0xb6, 0x67, 0x4b, 0xcf, 0xdf, 0xcc, 0xf8, 0x9c, 0x4d, 0xc5, 0xd2,
+	0x5f, 0xb7, 0x82, 0x90, 0x4b, 0x8e, 0x4f, 0xe2, 0x8b, 0xa8, 0xd7, 0x9f, 0x21, 0xec, 0x0b, 0xdb,
+	0xc8, 0x84, 0xa9, 0x9f, 0xc7, 0xb9, 0x20, 0xe4, 0x01, 0x17, 0xde, 0x6a, 0x17, 0xfc, 0x6e, 0xc1,
+	0xf9, 0x62, 0xc5, 0xda, 0xb1, 0xba, 0xdf, 0x3e, 0xb4, 0xa5, 0xbf, 0x66, 0x42, 0x7a, 0xeb, 0x20,
+	0x01, 0xf4, 0x7f, 0x0a, 0x80, 0xba, 0xa9, 0xdf, 0x90, 0x09, 0xe1, 0x2d, 0x18, 0x7e, 0x0f, 0x79,
+	0x19, 0x05, 0x4c, 0xcb, 0x34, 0x32, 0xcd, 0x6a, 0xe7, 0xdb, 0x04, 0x15, 0xad, 0xe7, 0x5c, 0xcb,
+	0x8d, 0x02, 0x46, 0x63, 0x14, 0xff, 0x0e, 0xe5, 0xbd, 0xb5, 0x96, 0x6d, 0x64, 0x9a, 0xa7, 0x9d,
+	0x7a, 0x2b, 0x69, 0xde, 0x4a, 0x9b, 0xb7, 0xdc, 0x94, 0xa0, 0x4f, 0x30, 0xd6, 0xa0, 0x18, 0x78,
+	0xd1, 0x8a, 0x7b, 0x73, 0x2d, 0xd7, 0xc8, 0x34, 0x2b, 0x34, 0x95, 0x18, 0x43, 0x5e, 0x3e, 0xfa,
+	0x73, 0x2d, 0xdf, 0xc8, 0x34, 0xcb, 0x34, 0xbe, 0xc7, 0x1d, 0x28, 0xa5, 0x4b, 0xd4, 0x0a, 0x71,
+	0x9b, 0xab, 0x74, 0x3c, 0xc7, 0x5f, 0x6c, 0xd8, 0x7c, 0xb4, 0xcb, 0xd2, 0x3d, 0x87, 0x3f, 0x40,
+	0xed, 0xd9, 0x23, 0xd3, 0x4e, 0x8e, 0x4b, 0xf7, 0x2b, 0x23, 0x2a, 0x4b, 0xab, 0xb3, 0x23, 0xad,
+	0xff, 0x9b, 0x85, 0xbc, 0x5a, 0x2b, 0x3e, 0x83, 0xf2, 0xd8, 0xea, 0x91, 0x1b, 0xd3, 0x22, 0x3d,
+	0xf4, 0x06, 0x57, 0xa0, 0x44, 0x49, 0xdf, 0x74, 0x5c, 0x42, 0x51, 0x06, 0x57, 0x01, 0x52, 0x45,
+	0x7a, 0x28, 0x8b, 0x4b, 0x90, 0x37, 0x2d, 0xd3, 0x45, 0x39, 0x5c, 0x86, 0x02, 0x25, 0x46, 0x6f,
+	0x82, 0xf2, 0xb8, 0x06, 0xa7, 0x2e, 0x35, 0x2c, 0xc7, 0xe8, 0xba, 0xa6, 0x6d, 0xa1, 0x82, 0xb2,
+	0xec, 0xda, 0xc3, 0xd1, 0x80, 0xb8, 0xa4, 0x87, 0x4e, 0x14, 0x4a, 0x28, 0xb5, 0x29, 0x2a, 0xaa,
+	0x4c, 0x9f, 0xb8, 0x53, 0xc7, 0x35, 0x5c, 0x82, 0x4a, 0x4a, 0x8e, 0xc6, 0xa9, 0x2c, 0x2b, 0xd9,
+	0x23, 0x83, 0x9d, 0x04, 0x7c, 0x01, 0xc8, 0xb4, 0x3e, 0xd9, 0x77, 0x64, 0xda, 0xbd, 0x35, 0x4c,
+	0xab, 0x6b, 0xf7, 0x08, 0x3a, 0x4d, 0x06, 0x74, 0x46, 0xb6, 0xe5, 0x10, 0x74, 0x86, 0xaf, 0x00,
+	0xef, 0x0d, 0xa7, 0xd7, 0x93, 0x29, 0x35, 0xac, 0x3e, 0x41, 0x55, 0x55, 0xab, 0xe2, 0x1f, 0xc7,
+	0x84, 0x4e, 0xa6, 0x94, 0x38, 0xe3, 0x81, 0x8b, 0x6a, 0x2a, 0x9a, 0x44, 0x12, 0xde, 0x22, 0x9f,
+	0x5d, 0x84, 0xf0, 0x25, 0xbc, 0x3d, 0x8c, 0x76, 0x07, 0xb6, 0x43, 0xd0, 0x5b, 0x35, 0xcd, 0x1d,
+	0x21, 0x23, 0x63, 0x60, 0x7e, 0x22, 0x08, 0xe3, 0x6f, 0xe0, 0x5c, 0x39, 0xde, 0x9a, 0x8e, 0x6b,
+	0xd3, 0xc9, 0xf4, 0xc6, 0xa6, 0xd3, 0x3b, 0x32, 0x41, 0xe7, 0xc7, 0x23, 0x0c, 0x89, 0x6b, 0xf4,
+	0x0c, 0xd7, 0x40, 0x17, 0x2a, 0x3e, 0x1a, 0xbf, 0x88, 0x5f, 0xea, 0xbf, 0x41, 0x65, 0xb4, 0x95,
+	0x8e, 0xf4, 0x24, 0x33, 0x37, 0x0f, 0x1c, 0x23, 0xc8, 0xfd, 0xc5, 0xa2, 0xf8, 0xc5, 0x2c, 0x53,
+	0x75, 0x8b, 0x2f, 0xa0, 0xf0, 0xc5, 0x5b, 0x6d, 0x59, 0xfc, 0xd2, 0x55, 0x68, 0x22, 0x74, 0x02,
+	0xb5, 0x3e, 0x4b, 0xea, 0xae, 0x23, 0xea, 0x6d, 0x16, 0x0c, 0xd7, 0xa1, 0x24, 0xa4, 0x17, 0xca,
+	0xbb, 0x7d, 0xfd, 0x5e, 0xe3, 0x2b, 0x38, 0x61, 0x9b, 0xb9, 0xca, 0x64, 0xe3, 0xcc, 0x4e, 0xe9,
+	0x3f, 0x42, 0xb5, 0xcf, 0xe4, 0xc7, 0x2d, 0x0b, 0x23, 0xca, 0xc4, 0x76, 0x25, 0x55, 0xbb, 0xbf,
+	0x95, 0xdc, 0x59, 0x24, 0x42, 0xff, 0x01, 0x50, 0x9f, 0xc9, 0x5b, 0x5f, 0x48, 0x1e, 0x46, 0x37,
+	0x3c, 0x54, 0x9e, 0x2f, 0x46, 0xd5, 0x1b, 0x50, 0x8d, 0xad, 0xe2, 0xb1, 0x2c, 0xf6, 0x28, 0x71,
+	0x15, 0xb2, 0xfe, 0x7c, 0x87, 0x64, 0xfd, 0xb9, 0xfe, 0x3d, 0xd4, 0x9e, 0x88, 0xee, 0x8a, 0x0b,
+	0xf6, 0x02, 0x49, 0x5a, 0xc5, 0xc0, 0x90, 0x49, 0x6f, 0xee, 0x49, 0xef, 0x7f, 0x5a, 0xfd, 0x01,
+	0x68, 0xb4, 0xfd, 0x1a, 0x85, 0xdf, 0x43, 0x69, 0xbd, 0xcb, 0xee, 0xbe, 0xd9, 0xcb, 0xfd, 0xc7,
+	0x74, 0x58, 0x4a, 0xf7, 0x98, 0xfe, 0x01, 0xce, 0x8e, 0x5d, 0x35, 0x28, 0xaa, 0xe4, 0x93, 0x73,
+	0x2a, 0x5f, 0xd9, 0x99, 0x1b, 0x38, 0x3f, 0xf6, 0x4e, 0x9e, 0x6b, 0x1b, 0x8a, 0x6c, 0x23, 0x43,
+	0x9f, 0x09, 0x2d, 0xd3, 0xc8, 0xbd, 0x3e, 0x49, 0x4a, 0xe9, 0xbf, 0x02, 0x3a, 0xd8, 0x97, 0xeb,
+	0x48, 0x32, 0x81, 0x1b, 0x70, 0x1a, 0x3e, 0xc9, 0x78, 0x9e, 0x0a, 0x3d, 0x0c, 0xe9, 0x1b, 0x38,
+	0x4b, 0xab, 0x02, 0xbe, 0x11, 0x0c, 0x77, 0xa0, 0x98, 0xe4, 0xd3, 0xbe, 0x5a, 0xda, 0xf7, 0xb9,
+	0x3b, 0x4d, 0x41, 0xfc, 0x0e, 0x4a, 0x4b, 0x4f, 0x4c, 0xd7, 0x3c, 0x4c, 0xd6, 0x56, 0xa2, 0xc5,
+	0xa5, 0x27, 0x86, 0x3c, 0x4c, 0x77, 0x2b, 0x97, 0xee, 0x56, 0xe7, 0xf3, 0xc1, 0xdf, 0xd5, 0xd9,
+	0x06, 0x01, 0x0f, 0x25, 0xee, 0x41, 0x89, 0xb2, 0x85, 0x2f, 0x24, 0x0b, 0xb1, 0xf6, 0xda, 0xbf,
+	0xb5, 0xfe, 0x6a, 0x46, 0x7f, 0xd3, 0xcc, 0xfc, 0x9c, 0xb9, 0xb6, 0x41, 0xe7, 0xe1, 0xa2, 0xb5,
+	0x8c, 0x02, 0x16, 0xae, 0xd8, 0x7c, 0xc1, 0xc2, 0xd6, 0x83, 0x77, 0x1f, 0xfa, 0xb3, 0xb4, 0x4e,
+	0x1d, 0x07, 0x7f, 0xfe, 0xb4, 0xf0, 0xe5, 0x72, 0x7b, 0xdf, 0x9a, 0xf1, 0x75, 0xfb, 0x00, 0x6d,
+	0x27, 0x68, 0x72, 0x2c, 0x88, 0xb6, 0x42, 0xef, 0x93, 0x33, 0xe6, 0x97, 0xff, 0x06, 0x00, 0x79,
+	0xf6, 0x0f, 0x84, 0x87, 0x06, 0x00, 0x00,
 }
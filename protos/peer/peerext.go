@@ -0,0 +1,180 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package peer
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/msp"
+)
+
+// This file describes the marshaled messages held by the bytes fields of the
+// messages of this package, and registers the messages of this package
+// carried by the messages of protos/common, for common/tools/protolator
+
+func init() {
+	common.RegisterPayloadDataProto(common.HeaderType_ENDORSER_TRANSACTION, func() proto.Message { return &Transaction{} })
+	common.RegisterConfigValueProto(common.ApplicationOrgGroupType, "AnchorPeers", func() proto.Message { return &AnchorPeers{} })
+}
+
+func unknownFieldErr(msg proto.Message, name string) error {
+	return fmt.Errorf("%T has no opaque field %s", msg, name)
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (ta *TransactionAction) StaticallyOpaqueFields() []string {
+	return []string{"header", "payload"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (ta *TransactionAction) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	switch name {
+	case "header":
+		return &common.SignatureHeader{}, nil
+	case "payload":
+		return &ChaincodeActionPayload{}, nil
+	}
+	return nil, unknownFieldErr(ta, name)
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (cp *ChaincodeActionPayload) StaticallyOpaqueFields() []string {
+	return []string{"chaincode_proposal_payload"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (cp *ChaincodeActionPayload) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "chaincode_proposal_payload" {
+		return nil, unknownFieldErr(cp, name)
+	}
+	return &ChaincodeProposalPayload{}, nil
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (cea *ChaincodeEndorsedAction) StaticallyOpaqueFields() []string {
+	return []string{"proposal_response_payload"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (cea *ChaincodeEndorsedAction) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "proposal_response_payload" {
+		return nil, unknownFieldErr(cea, name)
+	}
+	return &ProposalResponsePayload{}, nil
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+// The extension of proposal responses is a ChaincodeAction for chaincode
+// proposals, the only proposals endorsed
+func (prp *ProposalResponsePayload) StaticallyOpaqueFields() []string {
+	return []string{"extension"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (prp *ProposalResponsePayload) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "extension" {
+		return nil, unknownFieldErr(prp, name)
+	}
+	return &ChaincodeAction{}, nil
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (ca *ChaincodeAction) StaticallyOpaqueFields() []string {
+	return []string{"results", "events"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (ca *ChaincodeAction) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	switch name {
+	case "results":
+		return &rwset.TxReadWriteSet{}, nil
+	case "events":
+		return &ChaincodeEvent{}, nil
+	}
+	return nil, unknownFieldErr(ca, name)
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (e *Endorsement) StaticallyOpaqueFields() []string {
+	return []string{"endorser"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (e *Endorsement) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "endorser" {
+		return nil, unknownFieldErr(e, name)
+	}
+	return &msp.SerializedIdentity{}, nil
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (cpp *ChaincodeProposalPayload) StaticallyOpaqueFields() []string {
+	return []string{"input"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (cpp *ChaincodeProposalPayload) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "input" {
+		return nil, unknownFieldErr(cpp, name)
+	}
+	return &ChaincodeInvocationSpec{}, nil
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (p *Proposal) StaticallyOpaqueFields() []string {
+	return []string{"header", "payload"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (p *Proposal) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	switch name {
+	case "header":
+		return &common.Header{}, nil
+	case "payload":
+		return &ChaincodeProposalPayload{}, nil
+	}
+	return nil, unknownFieldErr(p, name)
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (sp *SignedProposal) StaticallyOpaqueFields() []string {
+	return []string{"proposal_bytes"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (sp *SignedProposal) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "proposal_bytes" {
+		return nil, unknownFieldErr(sp, name)
+	}
+	return &Proposal{}, nil
+}
+
+// StaticallyOpaqueFields implements protolator.StaticallyOpaqueFieldProto
+func (pr *ProposalResponse) StaticallyOpaqueFields() []string {
+	return []string{"payload"}
+}
+
+// StaticallyOpaqueFieldProto implements protolator.StaticallyOpaqueFieldProto
+func (pr *ProposalResponse) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != "payload" {
+		return nil, unknownFieldErr(pr, name)
+	}
+	return &ProposalResponsePayload{}, nil
+}
@@ -105,6 +105,15 @@ type ChaincodeHeaderExtension struct {
 	PayloadVisibility []byte `protobuf:"bytes,1,opt,name=payload_visibility,json=payloadVisibility,proto3" json:"payload_visibility,omitempty"`
 	// The ID of the chaincode to target.
 	ChaincodeId *ChaincodeID `protobuf:"bytes,2,opt,name=chaincode_id,json=chaincodeId" json:"chaincode_id,omitempty"`
+	// QueryOnly hints that the proposal only reads the ledger and is not
+	// meant to be submitted as a transaction. The endorser then executes the
+	// chaincode against a read-only view of the ledger, without recording the
+	// read and write sets of the simulation nor providing a history of keys.
+	QueryOnly bool `protobuf:"varint,3,opt,name=query_only,json=queryOnly" json:"query_only,omitempty"`
+	// DryRun asks the endorser to simulate the proposal and return the read
+	// and write sets of the simulation and the chaincode response, without
+	// endorsing them. The response can't be submitted as a transaction.
+	DryRun bool `protobuf:"varint,4,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
 }
 
 func (m *ChaincodeHeaderExtension) Reset()                    { *m = ChaincodeHeaderExtension{} }
@@ -194,34 +203,36 @@ func init() {
 func init() { proto.RegisterFile("peer/proposal.proto", fileDescriptor7) }
 
 var fileDescriptor7 = []byte{
-	// 452 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x8c, 0x53, 0x4d, 0x6f, 0xd3, 0x4c,
-	0x10, 0x96, 0x93, 0xf7, 0xed, 0xc7, 0x24, 0xf4, 0x63, 0x5b, 0x21, 0x2b, 0xea, 0xa1, 0xb2, 0x84,
-	0x54, 0x24, 0xb0, 0xa5, 0x20, 0x21, 0xc4, 0x05, 0x11, 0xa8, 0x44, 0x0f, 0x48, 0x95, 0x81, 0x1e,
-	0x7a, 0x09, 0x6b, 0x7b, 0x70, 0x56, 0x35, 0xbb, 0xab, 0xdd, 0x75, 0x84, 0x8f, 0xfc, 0x1c, 0x7e,
-	0x0a, 0xff, 0x0a, 0xd9, 0xfb, 0xd1, 0x94, 0x5c, 0x38, 0x25, 0x33, 0xf3, 0x3c, 0xcf, 0xcc, 0x3c,
-	0xb3, 0x86, 0x13, 0x89, 0xa8, 0x32, 0xa9, 0x84, 0x14, 0x9a, 0x36, 0xa9, 0x54, 0xc2, 0x08, 0xb2,
-	0x33, 0xfc, 0xe8, 0xd9, 0xe9, 0x50, 0x2c, 0x57, 0x94, 0xf1, 0x52, 0x54, 0x68, 0xab, 0xb3, 0xb3,
-	0x07, 0x94, 0xa5, 0x42, 0x2d, 0x05, 0xd7, 0xae, 0x9a, 0x7c, 0x81, 0x83, 0x4f, 0xac, 0xe6, 0x58,
-	0x5d, 0x3b, 0x00, 0x79, 0x02, 0x07, 0x01, 0x5c, 0x74, 0x06, 0x75, 0x1c, 0x9d, 0x47, 0x17, 0xd3,
-	0xfc, 0x91, 0xcf, 0x2e, 0xfa, 0x24, 0x39, 0x83, 0x7d, 0xcd, 0x6a, 0x4e, 0x4d, 0xab, 0x30, 0x1e,
-	0x0d, 0x88, 0xfb, 0x44, 0x72, 0x0b, 0x7b, 0x41, 0xf0, 0x31, 0xec, 0xac, 0x90, 0x56, 0xa8, 0x9c,
-	0x90, 0x8b, 0x48, 0x0c, 0xbb, 0x92, 0x76, 0x8d, 0xa0, 0x95, 0xe3, 0xfb, 0xb0, 0xd7, 0xc6, 0x1f,
-	0x06, 0xb9, 0x66, 0x82, 0xc7, 0x63, 0xab, 0x1d, 0x12, 0xc9, 0xcf, 0x08, 0xe2, 0x77, 0x7e, 0xc9,
-	0x0f, 0x83, 0xd6, 0xa5, 0x2f, 0x92, 0xe7, 0x40, 0x9c, 0xca, 0x72, 0xcd, 0x34, 0x2b, 0x58, 0xc3,
-	0x4c, 0xe7, 0x1a, 0x1f, 0xbb, 0xca, 0x4d, 0x28, 0x90, 0x97, 0x30, 0x0d, 0x7e, 0x2d, 0x99, 0x1d,
-	0x64, 0x32, 0x3f, 0xb1, 0xe6, 0xe8, 0x34, 0xb4, 0xb9, 0x7a, 0x9f, 0x4f, 0x02, 0xf0, 0xaa, 0x4a,
-	0x7e, 0x6f, 0xce, 0xe0, 0x37, 0xbd, 0x76, 0xe3, 0x9f, 0xc2, 0xff, 0x8c, 0xcb, 0xd6, 0xb8, 0xb6,
-	0x36, 0x20, 0x37, 0x30, 0xfd, 0xac, 0x28, 0xd7, 0x0c, 0xb9, 0xf9, 0x48, 0x65, 0x3c, 0x3a, 0x1f,
-	0x5f, 0x4c, 0xe6, 0xf3, 0xad, 0x56, 0x7f, 0xa9, 0xa5, 0x9b, 0xa4, 0x4b, 0x6e, 0x54, 0x97, 0x3f,
-	0xd0, 0x99, 0xbd, 0x81, 0xe3, 0x2d, 0x08, 0x39, 0x82, 0xf1, 0x1d, 0xda, 0xbd, 0xf7, 0xf3, 0xfe,
-	0x6f, 0x3f, 0xd4, 0x9a, 0x36, 0xad, 0xbf, 0x95, 0x0d, 0x5e, 0x8f, 0x5e, 0x45, 0xc9, 0xaf, 0x08,
-	0x0e, 0x43, 0xf7, 0xb7, 0xa5, 0xe9, 0x6d, 0x8c, 0x61, 0x57, 0xa1, 0x6e, 0x1b, 0xe3, 0xaf, 0xef,
-	0xc3, 0xfe, 0x9a, 0xb8, 0x46, 0x6e, 0xb4, 0x13, 0x72, 0x11, 0x79, 0x06, 0x7b, 0xfe, 0x69, 0x0d,
-	0x27, 0x9b, 0xcc, 0x8f, 0xfc, 0x6a, 0xb9, 0xcb, 0xe7, 0x01, 0xb1, 0xe5, 0xfb, 0x7f, 0xff, 0xe6,
-	0xfb, 0xe2, 0x2b, 0x24, 0x42, 0xd5, 0xe9, 0xaa, 0x93, 0xa8, 0x1a, 0xac, 0x6a, 0x54, 0xe9, 0x37,
-	0x5a, 0x28, 0x56, 0x7a, 0x66, 0xff, 0xd8, 0x17, 0x87, 0xf7, 0x1e, 0x96, 0x77, 0xb4, 0xc6, 0xdb,
-	0xa7, 0x35, 0x33, 0xab, 0xb6, 0x48, 0x4b, 0xf1, 0x3d, 0xdb, 0xe0, 0x66, 0x96, 0x9b, 0x59, 0x6e,
-	0xd6, 0x73, 0x0b, 0xfb, 0x31, 0xbd, 0xf8, 0x13, 0x00, 0x00, 0xff, 0xff, 0x12, 0x75, 0xb6, 0xaf,
-	0x6a, 0x03, 0x00, 0x00,
+	// 486 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0x5d, 0x6f, 0xd3, 0x30,
+	0x14, 0x55, 0xd7, 0xd1, 0x8f, 0xdb, 0xb2, 0x0f, 0x6f, 0x82, 0xa8, 0x1a, 0xd2, 0x14, 0x09, 0x69,
+	0x48, 0x90, 0x4a, 0x45, 0x42, 0x88, 0x17, 0x44, 0x61, 0x12, 0x7b, 0x40, 0x4c, 0x01, 0xf6, 0xb0,
+	0x97, 0xe0, 0x24, 0x97, 0xd4, 0x5a, 0xb0, 0x83, 0xed, 0x54, 0xf8, 0x27, 0xf1, 0x13, 0xf8, 0x09,
+	0xfc, 0x2b, 0x94, 0xd8, 0xce, 0x3a, 0xfa, 0xc2, 0x53, 0x72, 0xef, 0xb9, 0xe7, 0xf8, 0xf8, 0xd8,
+	0x86, 0xa3, 0x0a, 0x51, 0xce, 0x2b, 0x29, 0x2a, 0xa1, 0x68, 0x19, 0x55, 0x52, 0x68, 0x41, 0x06,
+	0xed, 0x47, 0xcd, 0x8e, 0x5b, 0x30, 0x5b, 0x51, 0xc6, 0x33, 0x91, 0xa3, 0x45, 0x67, 0x27, 0x77,
+	0x28, 0x89, 0x44, 0x55, 0x09, 0xae, 0x1c, 0x1a, 0x7e, 0x81, 0xbd, 0x4f, 0xac, 0xe0, 0x98, 0x5f,
+	0xba, 0x01, 0xf2, 0x18, 0xf6, 0xba, 0xe1, 0xd4, 0x68, 0x54, 0x41, 0xef, 0xb4, 0x77, 0x36, 0x8d,
+	0xef, 0xfb, 0xee, 0xb2, 0x69, 0x92, 0x13, 0x18, 0x2b, 0x56, 0x70, 0xaa, 0x6b, 0x89, 0xc1, 0x4e,
+	0x3b, 0x71, 0xdb, 0x08, 0xaf, 0x61, 0xd4, 0x09, 0x3e, 0x80, 0xc1, 0x0a, 0x69, 0x8e, 0xd2, 0x09,
+	0xb9, 0x8a, 0x04, 0x30, 0xac, 0xa8, 0x29, 0x05, 0xcd, 0x1d, 0xdf, 0x97, 0x8d, 0x36, 0xfe, 0xd4,
+	0xc8, 0x15, 0x13, 0x3c, 0xe8, 0x5b, 0xed, 0xae, 0x11, 0xfe, 0xee, 0x41, 0xf0, 0xd6, 0x6f, 0xf2,
+	0x7d, 0xab, 0x75, 0xee, 0x41, 0xf2, 0x0c, 0x88, 0x53, 0x49, 0xd6, 0x4c, 0xb1, 0x94, 0x95, 0x4c,
+	0x1b, 0xb7, 0xf0, 0xa1, 0x43, 0xae, 0x3a, 0x80, 0xbc, 0x80, 0x69, 0x97, 0x57, 0xc2, 0xac, 0x91,
+	0xc9, 0xe2, 0xc8, 0x86, 0xa3, 0xa2, 0x6e, 0x99, 0x8b, 0x77, 0xf1, 0xa4, 0x1b, 0xbc, 0xc8, 0xc9,
+	0x23, 0x80, 0x1f, 0x35, 0x4a, 0x93, 0x08, 0x5e, 0x9a, 0xd6, 0xe2, 0x28, 0x1e, 0xb7, 0x9d, 0x8f,
+	0xbc, 0x34, 0xe4, 0x21, 0x0c, 0x73, 0x69, 0x12, 0x59, 0xf3, 0x60, 0xb7, 0xc5, 0x06, 0xb9, 0x34,
+	0x71, 0xcd, 0xc3, 0x3f, 0x9b, 0xde, 0x7d, 0x42, 0x97, 0x6e, 0xdb, 0xc7, 0x70, 0x8f, 0xf1, 0xaa,
+	0xd6, 0xce, 0xae, 0x2d, 0xc8, 0x15, 0x4c, 0x3f, 0x4b, 0xca, 0x15, 0x43, 0xae, 0x3f, 0xd0, 0x2a,
+	0xd8, 0x39, 0xed, 0x9f, 0x4d, 0x16, 0x8b, 0x2d, 0x8b, 0xff, 0xa8, 0x45, 0x9b, 0xa4, 0x73, 0xae,
+	0xa5, 0x89, 0xef, 0xe8, 0xcc, 0x5e, 0xc3, 0xe1, 0xd6, 0x08, 0x39, 0x80, 0xfe, 0x0d, 0xda, 0xbc,
+	0xc6, 0x71, 0xf3, 0xdb, 0x98, 0x5a, 0xd3, 0xb2, 0xf6, 0x67, 0x6c, 0x8b, 0x57, 0x3b, 0x2f, 0x7b,
+	0xe1, 0xaf, 0x1e, 0xec, 0x77, 0xab, 0xbf, 0xc9, 0x74, 0x13, 0x7f, 0x00, 0x43, 0x89, 0xaa, 0x2e,
+	0xb5, 0xbf, 0x35, 0xbe, 0x6c, 0x6e, 0x01, 0xae, 0x91, 0x6b, 0xe5, 0x84, 0x5c, 0x45, 0x9e, 0xc2,
+	0xc8, 0x5f, 0xc9, 0x36, 0xc7, 0xc9, 0xe2, 0xc0, 0x6f, 0x2d, 0x76, 0xfd, 0xb8, 0x9b, 0xd8, 0x3a,
+	0xaf, 0xdd, 0xff, 0x3b, 0xaf, 0xe5, 0x57, 0x08, 0x85, 0x2c, 0xa2, 0x95, 0xa9, 0x50, 0x96, 0x98,
+	0x17, 0x28, 0xa3, 0x6f, 0x34, 0x95, 0x2c, 0xf3, 0xcc, 0xe6, 0x91, 0x2c, 0xf7, 0x6f, 0x33, 0xcc,
+	0x6e, 0x68, 0x81, 0xd7, 0x4f, 0x0a, 0xa6, 0x57, 0x75, 0x1a, 0x65, 0xe2, 0xfb, 0x7c, 0x83, 0x3b,
+	0xb7, 0xdc, 0xb9, 0xe5, 0xce, 0x1b, 0x6e, 0x6a, 0x1f, 0xe1, 0xf3, 0xbf, 0x03, 0x00, 0x54, 0x8b,
+	0xf5, 0x09, 0xa2, 0x03, 0x00, 0x00,
 }
@@ -36,6 +36,10 @@ type ProposalResponse struct {
 	// The endorsement of the proposal, basically
 	// the endorser's signature over the payload
 	Endorsement *Endorsement `protobuf:"bytes,6,opt,name=endorsement" json:"endorsement,omitempty"`
+	// The chaincodes the simulation of the proposal touched, so that the
+	// client can find which other endorsements the transaction needs before
+	// submitting it. It is a hint and is not covered by the endorsement
+	Interest *ChaincodeInterest `protobuf:"bytes,7,opt,name=interest" json:"interest,omitempty"`
 }
 
 func (m *ProposalResponse) Reset()                    { *m = ProposalResponse{} }
@@ -64,6 +68,13 @@ func (m *ProposalResponse) GetEndorsement() *Endorsement {
 	return nil
 }
 
+func (m *ProposalResponse) GetInterest() *ChaincodeInterest {
+	if m != nil {
+		return m.Interest
+	}
+	return nil
+}
+
 // A response with a representation similar to an HTTP response that can
 // be used within another message.
 type Response struct {
@@ -137,38 +148,80 @@ func (m *Endorsement) String() string            { return proto.CompactTextStrin
 func (*Endorsement) ProtoMessage()               {}
 func (*Endorsement) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{3} }
 
+// ChaincodeInterest describes the chaincodes a proposal invokes, directly or
+// through chaincode to chaincode calls, as found in its read-write set
+type ChaincodeInterest struct {
+	// The invoked chaincode, followed by the other chaincodes whose
+	// namespaces the simulation touched
+	Chaincodes []*ChaincodeCall `protobuf:"bytes,1,rep,name=chaincodes" json:"chaincodes,omitempty"`
+}
+
+func (m *ChaincodeInterest) Reset()                    { *m = ChaincodeInterest{} }
+func (m *ChaincodeInterest) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeInterest) ProtoMessage()               {}
+func (*ChaincodeInterest) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{4} }
+
+func (m *ChaincodeInterest) GetChaincodes() []*ChaincodeCall {
+	if m != nil {
+		return m.Chaincodes
+	}
+	return nil
+}
+
+// ChaincodeCall is a chaincode whose namespace the simulation of a proposal touched
+type ChaincodeCall struct {
+	// The name of the chaincode
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Whether the simulation only read the namespace of the chaincode, in
+	// which case its endorsement policy does not apply to the transaction
+	ReadOnly bool `protobuf:"varint,2,opt,name=read_only,json=readOnly" json:"read_only,omitempty"`
+}
+
+func (m *ChaincodeCall) Reset()                    { *m = ChaincodeCall{} }
+func (m *ChaincodeCall) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeCall) ProtoMessage()               {}
+func (*ChaincodeCall) Descriptor() ([]byte, []int) { return fileDescriptor8, []int{5} }
+
 func init() {
 	proto.RegisterType((*ProposalResponse)(nil), "protos.ProposalResponse")
 	proto.RegisterType((*Response)(nil), "protos.Response")
 	proto.RegisterType((*ProposalResponsePayload)(nil), "protos.ProposalResponsePayload")
 	proto.RegisterType((*Endorsement)(nil), "protos.Endorsement")
+	proto.RegisterType((*ChaincodeInterest)(nil), "protos.ChaincodeInterest")
+	proto.RegisterType((*ChaincodeCall)(nil), "protos.ChaincodeCall")
 }
 
 func init() { proto.RegisterFile("peer/proposal_response.proto", fileDescriptor8) }
 
 var fileDescriptor8 = []byte{
-	// 367 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x92, 0x51, 0x4b, 0xfb, 0x30,
-	0x14, 0xc5, 0xe9, 0xfe, 0xff, 0xcd, 0x2d, 0x9b, 0x30, 0x2a, 0x68, 0x19, 0x03, 0x47, 0x7d, 0x99,
-	0x20, 0x29, 0x28, 0x82, 0xcf, 0x03, 0xd1, 0xc7, 0x11, 0xc4, 0x07, 0x11, 0x24, 0xdd, 0xee, 0xd2,
-	0x62, 0xdb, 0x84, 0xdc, 0x54, 0xdc, 0x07, 0xf6, 0x7b, 0x48, 0xd3, 0xa6, 0xab, 0xe2, 0xd3, 0x38,
-	0x77, 0x27, 0xbf, 0x7b, 0xcf, 0xed, 0x25, 0x73, 0x05, 0xa0, 0x23, 0xa5, 0xa5, 0x92, 0xc8, 0xb3,
-	0x37, 0x0d, 0xa8, 0x64, 0x81, 0x40, 0x95, 0x96, 0x46, 0xfa, 0x03, 0xfb, 0x83, 0xb3, 0x73, 0x21,
-	0xa5, 0xc8, 0x20, 0xb2, 0x32, 0x2e, 0x77, 0x91, 0x49, 0x73, 0x40, 0xc3, 0x73, 0x55, 0x1b, 0xc3,
-	0x2f, 0x8f, 0x4c, 0xd7, 0x0d, 0x84, 0x35, 0x0c, 0x3f, 0x20, 0x47, 0x1f, 0xa0, 0x31, 0x95, 0x45,
-	0xe0, 0x2d, 0xbc, 0x65, 0x9f, 0x39, 0xe9, 0xdf, 0x91, 0x51, 0x4b, 0x08, 0x7a, 0x0b, 0x6f, 0x39,
-	0xbe, 0x9e, 0xd1, 0xba, 0x07, 0x75, 0x3d, 0xe8, 0x93, 0x73, 0xb0, 0x83, 0xd9, 0xbf, 0x22, 0x43,
-	0x37, 0x63, 0xf0, 0xdf, 0x3e, 0x9c, 0xd6, 0x2f, 0x90, 0xba, 0xbe, 0xac, 0x75, 0x54, 0x13, 0x28,
-	0xbe, 0xcf, 0x24, 0xdf, 0x06, 0xfd, 0x85, 0xb7, 0x9c, 0x30, 0x27, 0xfd, 0x5b, 0x32, 0x86, 0x62,
-	0x2b, 0x35, 0x42, 0x0e, 0x85, 0x09, 0x06, 0x16, 0x75, 0xe2, 0x50, 0xf7, 0x87, 0xbf, 0x58, 0xd7,
-	0x17, 0x3e, 0x93, 0x61, 0x1b, 0xef, 0x94, 0x0c, 0xd0, 0x70, 0x53, 0x62, 0x93, 0xae, 0x51, 0x55,
-	0xd3, 0x1c, 0x10, 0xb9, 0x00, 0x1b, 0x6d, 0xc4, 0x9c, 0xec, 0x8e, 0xf3, 0xef, 0xc7, 0x38, 0xe1,
-	0x2b, 0x39, 0xfb, 0xbd, 0xbe, 0x75, 0x33, 0xe9, 0x05, 0x39, 0x6e, 0x3f, 0x4f, 0xc2, 0x31, 0xb1,
-	0xdd, 0x26, 0x6c, 0xe2, 0x8a, 0x8f, 0x1c, 0x13, 0x7f, 0x4e, 0x46, 0xf0, 0x69, 0xa0, 0xb0, 0xcb,
-	0xee, 0x59, 0xc3, 0xa1, 0x10, 0x3e, 0x90, 0x71, 0x27, 0x91, 0x3f, 0x23, 0xc3, 0x26, 0x93, 0x6e,
-	0x60, 0xad, 0xae, 0x40, 0x98, 0x8a, 0x82, 0x9b, 0x52, 0x83, 0x03, 0xb5, 0x85, 0x55, 0x42, 0x42,
-	0xa9, 0x05, 0x4d, 0xf6, 0x0a, 0x74, 0x06, 0x5b, 0x01, 0x9a, 0xee, 0x78, 0xac, 0xd3, 0x8d, 0x5b,
-	0x5c, 0x75, 0x4d, 0xab, 0x3f, 0xa2, 0x6c, 0xde, 0xb9, 0x80, 0x97, 0x4b, 0x91, 0x9a, 0xa4, 0x8c,
-	0xe9, 0x46, 0xe6, 0x51, 0x87, 0x11, 0xd5, 0x8c, 0xfa, 0xba, 0x30, 0xaa, 0x18, 0x71, 0x7d, 0x79,
-	0x37, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x0e, 0x52, 0x0b, 0x35, 0xa0, 0x02, 0x00, 0x00,
+	// 457 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x53, 0xdf, 0x6b, 0xd4, 0x40,
+	0x10, 0xe6, 0xae, 0xed, 0x35, 0x99, 0xbb, 0x42, 0x5d, 0x51, 0xe3, 0x59, 0xf0, 0x88, 0x2f, 0x27,
+	0x48, 0x02, 0x95, 0x03, 0x1f, 0xa5, 0x45, 0xfc, 0xf1, 0x62, 0x59, 0xc4, 0x07, 0x11, 0xca, 0x5e,
+	0x32, 0x4d, 0x82, 0xc9, 0x6e, 0xd8, 0xd9, 0x13, 0xf3, 0xbf, 0xf8, 0xc7, 0x4a, 0x36, 0xd9, 0x5c,
+	0xea, 0xf5, 0x29, 0xf9, 0x66, 0xbf, 0xf9, 0x66, 0xe6, 0xdb, 0x1d, 0xb8, 0xa8, 0x11, 0x75, 0x5c,
+	0x6b, 0x55, 0x2b, 0x12, 0xe5, 0xad, 0x46, 0xaa, 0x95, 0x24, 0x8c, 0x6a, 0xad, 0x8c, 0x62, 0x33,
+	0xfb, 0xa1, 0xe5, 0xcb, 0x4c, 0xa9, 0xac, 0xc4, 0xd8, 0xc2, 0xed, 0xee, 0x2e, 0x36, 0x45, 0x85,
+	0x64, 0x44, 0x55, 0x77, 0xc4, 0xf0, 0xef, 0x14, 0xce, 0x6f, 0x7a, 0x11, 0xde, 0x6b, 0xb0, 0x00,
+	0x4e, 0x7f, 0xa3, 0xa6, 0x42, 0xc9, 0x60, 0xb2, 0x9a, 0xac, 0x4f, 0xb8, 0x83, 0xec, 0x1d, 0xf8,
+	0x83, 0x42, 0x30, 0x5d, 0x4d, 0xd6, 0xf3, 0xcb, 0x65, 0xd4, 0xd5, 0x88, 0x5c, 0x8d, 0xe8, 0x9b,
+	0x63, 0xf0, 0x3d, 0x99, 0xbd, 0x01, 0xcf, 0xf5, 0x18, 0x1c, 0xdb, 0xc4, 0xf3, 0x2e, 0x83, 0x22,
+	0x57, 0x97, 0x7b, 0x7a, 0xd4, 0x41, 0x2d, 0x9a, 0x52, 0x89, 0x34, 0x38, 0x59, 0x4d, 0xd6, 0x0b,
+	0xee, 0x20, 0xdb, 0xc0, 0x1c, 0x65, 0xaa, 0x34, 0x61, 0x85, 0xd2, 0x04, 0x33, 0x2b, 0xf5, 0xd8,
+	0x49, 0x7d, 0xd8, 0x1f, 0xf1, 0x31, 0x8f, 0x6d, 0xc0, 0x2b, 0xa4, 0x41, 0x8d, 0x64, 0x82, 0x53,
+	0x9b, 0xf3, 0xdc, 0xe5, 0x5c, 0xe7, 0xa2, 0x90, 0x89, 0x4a, 0xf1, 0x73, 0x4f, 0xe0, 0x03, 0x35,
+	0xfc, 0x0e, 0xde, 0xe0, 0xca, 0x53, 0x98, 0x91, 0x11, 0x66, 0x47, 0xbd, 0x29, 0x3d, 0x6a, 0x7b,
+	0xad, 0x90, 0x48, 0x64, 0x68, 0x1d, 0xf1, 0xb9, 0x83, 0xe3, 0x29, 0x8e, 0xee, 0x4d, 0x11, 0xfe,
+	0x84, 0x67, 0xff, 0xbb, 0x7e, 0xd3, 0x0f, 0xf8, 0x0a, 0xce, 0x86, 0x5b, 0xcd, 0x05, 0xe5, 0xb6,
+	0xda, 0x82, 0x2f, 0x5c, 0xf0, 0x93, 0xa0, 0x9c, 0x5d, 0x80, 0x8f, 0x7f, 0x0c, 0x4a, 0x7b, 0x47,
+	0x53, 0x4b, 0xd8, 0x07, 0xc2, 0x8f, 0x30, 0x1f, 0x19, 0xc1, 0x96, 0xe0, 0xf5, 0x56, 0xe8, 0x5e,
+	0x6c, 0xc0, 0xad, 0x10, 0x15, 0x99, 0x14, 0x66, 0xa7, 0xd1, 0x09, 0x0d, 0x81, 0xf0, 0x0b, 0x3c,
+	0x3a, 0x70, 0x87, 0x6d, 0x00, 0x12, 0x17, 0x6c, 0xbd, 0x38, 0x5a, 0xcf, 0x2f, 0x9f, 0x1c, 0x98,
+	0x79, 0x2d, 0xca, 0x92, 0x8f, 0x88, 0xe1, 0x7b, 0x38, 0xbb, 0x77, 0xc8, 0x18, 0x1c, 0x4b, 0x51,
+	0xa1, 0x6d, 0xc9, 0xe7, 0xf6, 0x9f, 0xbd, 0x00, 0x5f, 0xa3, 0x48, 0x6f, 0x95, 0x2c, 0x1b, 0xdb,
+	0x8e, 0xd7, 0x3e, 0x0a, 0x91, 0x7e, 0x95, 0x65, 0x73, 0x95, 0x43, 0xa8, 0x74, 0x16, 0xe5, 0x4d,
+	0x8d, 0xba, 0xc4, 0x34, 0x43, 0x1d, 0xdd, 0x89, 0xad, 0x2e, 0x12, 0x57, 0xbc, 0x5d, 0x89, 0xab,
+	0x07, 0x8c, 0x4d, 0x7e, 0x89, 0x0c, 0x7f, 0xbc, 0xce, 0x0a, 0x93, 0xef, 0xb6, 0x51, 0xa2, 0xaa,
+	0x78, 0xa4, 0x11, 0x77, 0x1a, 0xdd, 0x8a, 0x50, 0xdc, 0x6a, 0x6c, 0xbb, 0xf5, 0x79, 0xfb, 0x6f,
+	0x00, 0xab, 0xbe, 0xf8, 0xd7, 0x65, 0x03, 0x00, 0x00,
 }
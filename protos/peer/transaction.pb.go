@@ -36,7 +36,10 @@ const (
 	TxValidationCode_MARSHAL_TX_ERROR             TxValidationCode = 15
 	TxValidationCode_NIL_TXACTION                 TxValidationCode = 16
 	TxValidationCode_EXPIRED_CHAINCODE            TxValidationCode = 17
-	TxValidationCode_INVALID_OTHER_REASON         TxValidationCode = 255
+	// the transaction read a key written by a preceding invalid transaction
+	// of the same block, when intra-block dependencies are checked
+	TxValidationCode_DEPENDENCY_ON_INVALID_TX TxValidationCode = 18
+	TxValidationCode_INVALID_OTHER_REASON     TxValidationCode = 255
 )
 
 var TxValidationCode_name = map[int32]string{
@@ -58,6 +61,7 @@ var TxValidationCode_name = map[int32]string{
 	15:  "MARSHAL_TX_ERROR",
 	16:  "NIL_TXACTION",
 	17:  "EXPIRED_CHAINCODE",
+	18:  "DEPENDENCY_ON_INVALID_TX",
 	255: "INVALID_OTHER_REASON",
 }
 var TxValidationCode_value = map[string]int32{
@@ -79,6 +83,7 @@ var TxValidationCode_value = map[string]int32{
 	"MARSHAL_TX_ERROR":             15,
 	"NIL_TXACTION":                 16,
 	"EXPIRED_CHAINCODE":            17,
+	"DEPENDENCY_ON_INVALID_TX":     18,
 	"INVALID_OTHER_REASON":         255,
 }
 
@@ -242,53 +247,54 @@ func init() {
 func init() { proto.RegisterFile("peer/transaction.proto", fileDescriptor11) }
 
 var fileDescriptor11 = []byte{
-	// 761 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x74, 0x54, 0xc1, 0x6e, 0xe3, 0x36,
+	// 774 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0xc1, 0x6e, 0xe3, 0x36,
 	0x14, 0xac, 0x93, 0x26, 0x69, 0x9e, 0xd3, 0x84, 0x66, 0xb2, 0x5e, 0xc7, 0x08, 0xba, 0x0b, 0x1f,
 	0x8a, 0x6d, 0x0b, 0xd8, 0x40, 0xf6, 0x50, 0xa0, 0xe8, 0x85, 0x96, 0x98, 0x58, 0xa8, 0x4c, 0x0a,
-	0x34, 0x9d, 0x26, 0x3d, 0x94, 0x90, 0x2d, 0xae, 0x63, 0xd4, 0x16, 0x05, 0x49, 0x59, 0x34, 0xd7,
-	0x7e, 0x40, 0xfb, 0x7d, 0xfd, 0x9a, 0x16, 0x12, 0xa5, 0xc4, 0xce, 0xb6, 0x17, 0xcb, 0x9c, 0x19,
-	0xbe, 0x99, 0xf7, 0x44, 0x11, 0xda, 0x89, 0xd6, 0xe9, 0x20, 0x4f, 0xc3, 0x38, 0x0b, 0xe7, 0xf9,
-	0xd2, 0xc4, 0xfd, 0x24, 0x35, 0xb9, 0xc1, 0xfb, 0xe5, 0x23, 0xeb, 0xbe, 0x59, 0x18, 0xb3, 0x58,
-	0xe9, 0x41, 0xb9, 0x9c, 0x3d, 0x7c, 0x18, 0xe4, 0xcb, 0xb5, 0xce, 0xf2, 0x70, 0x9d, 0x58, 0x61,
-	0xf7, 0xa2, 0x2c, 0x90, 0xa4, 0x26, 0x31, 0x59, 0xb8, 0x52, 0xa9, 0xce, 0x12, 0x13, 0x67, 0xba,
-	0x62, 0x4f, 0xe7, 0x66, 0xbd, 0x36, 0xf1, 0xc0, 0x3e, 0x2c, 0xd8, 0xfb, 0x15, 0x5a, 0x93, 0xe5,
-	0x22, 0xd6, 0x91, 0x7c, 0xb6, 0xc5, 0xdf, 0x41, 0x6b, 0x23, 0x85, 0x9a, 0x3d, 0xe6, 0x3a, 0xeb,
-	0x34, 0xde, 0x36, 0xde, 0x1d, 0x09, 0xb4, 0x41, 0x0c, 0x0b, 0x1c, 0x5f, 0xc0, 0x61, 0xb6, 0x5c,
-	0xc4, 0x61, 0xfe, 0x90, 0xea, 0xce, 0x4e, 0x29, 0x7a, 0x06, 0x7a, 0x7f, 0x34, 0xe0, 0x2c, 0x48,
-	0xcd, 0x5c, 0x67, 0xd9, 0xb6, 0xc7, 0x10, 0x4e, 0x37, 0x4a, 0xd1, 0xf8, 0xa3, 0x5e, 0x99, 0x44,
-	0x97, 0x2e, 0xcd, 0x4b, 0xd4, 0xaf, 0x42, 0xd6, 0xb8, 0xf8, 0x2f, 0x31, 0xfe, 0x1a, 0x8e, 0x3f,
-	0x86, 0xab, 0x65, 0x14, 0x16, 0xa8, 0x63, 0x22, 0xeb, 0xbf, 0x27, 0x5e, 0xa0, 0xbd, 0x21, 0x34,
-	0x37, 0xad, 0xdf, 0xc3, 0x81, 0xfd, 0x57, 0x34, 0xb5, 0xfb, 0xae, 0x79, 0x79, 0x6e, 0x87, 0x91,
-	0xf5, 0x37, 0x54, 0xa4, 0xfc, 0x15, 0xb5, 0xb2, 0x47, 0xa1, 0xf5, 0x09, 0x8b, 0xdb, 0xb0, 0x7f,
-	0xaf, 0xc3, 0x48, 0xa7, 0xd5, 0x74, 0xaa, 0x15, 0xee, 0xc0, 0x41, 0x12, 0x3e, 0xae, 0x4c, 0x18,
-	0x55, 0x13, 0xa9, 0x97, 0xbd, 0xbf, 0x1a, 0xd0, 0x76, 0xee, 0xc3, 0x65, 0x3c, 0x37, 0x91, 0xb6,
-	0x55, 0x02, 0x4b, 0xe1, 0x1f, 0xa1, 0x3b, 0xaf, 0x19, 0xf5, 0xf4, 0x12, 0xeb, 0x3a, 0xd6, 0xa0,
-	0xf3, 0xa4, 0x08, 0x2a, 0x41, 0xbd, 0xfb, 0x7b, 0xd8, 0xb7, 0xd1, 0x4a, 0xc7, 0xe6, 0xe5, 0x9b,
-	0xba, 0xa7, 0x27, 0x37, 0x1a, 0x47, 0x26, 0xcd, 0x74, 0x54, 0x75, 0x56, 0xc9, 0x7b, 0x7f, 0x36,
-	0xe0, 0xf5, 0xff, 0x68, 0xf0, 0x0f, 0x70, 0xfe, 0xc9, 0x69, 0x7a, 0x91, 0xe8, 0x75, 0x2d, 0x10,
-	0x15, 0xff, 0x1c, 0xe8, 0x48, 0xdb, 0x6a, 0x6b, 0x1d, 0xe7, 0x59, 0x67, 0xa7, 0x1c, 0xf5, 0x69,
-	0x1d, 0x8b, 0x3e, 0x73, 0x62, 0x4b, 0xf8, 0xed, 0xdf, 0xbb, 0x80, 0xe4, 0xef, 0x37, 0x5b, 0xaf,
-	0x10, 0x1f, 0xc2, 0xde, 0x0d, 0xf1, 0x3d, 0x17, 0x7d, 0x86, 0x11, 0x1c, 0x31, 0xcf, 0x57, 0x94,
-	0xdd, 0x50, 0x9f, 0x07, 0x14, 0x35, 0xf0, 0x09, 0x34, 0x87, 0xc4, 0x55, 0x01, 0xb9, 0xf3, 0x39,
-	0x71, 0xd1, 0x0e, 0x7e, 0x05, 0xad, 0x02, 0x70, 0xf8, 0x78, 0xcc, 0x99, 0x1a, 0x51, 0xe2, 0x52,
-	0x81, 0x76, 0xf1, 0x39, 0xbc, 0x2a, 0x61, 0x41, 0x89, 0xe4, 0x42, 0x4d, 0xbc, 0x6b, 0x46, 0xe4,
-	0x54, 0x50, 0xf4, 0x39, 0x7e, 0x0b, 0x17, 0x1e, 0x2b, 0x1d, 0x14, 0x65, 0x2e, 0x17, 0x13, 0x2a,
-	0x94, 0x14, 0x84, 0x4d, 0x88, 0x23, 0x3d, 0xce, 0xd0, 0x1e, 0xfe, 0x0a, 0xba, 0xb5, 0xc2, 0xe1,
-	0xec, 0xca, 0xbb, 0xde, 0xe2, 0xf7, 0x71, 0x17, 0xda, 0x53, 0x36, 0x99, 0x06, 0x01, 0x17, 0x92,
-	0xba, 0x4a, 0xde, 0x3e, 0xe5, 0x39, 0xa8, 0xf3, 0x04, 0x82, 0x07, 0x7c, 0x42, 0x7c, 0x25, 0x6f,
-	0x3d, 0x17, 0x7d, 0x81, 0x31, 0x1c, 0xbb, 0xd3, 0xc0, 0xf7, 0x1c, 0x22, 0xa9, 0xc5, 0x0e, 0x0b,
-	0x9b, 0x2a, 0xc0, 0x98, 0x32, 0xa9, 0x02, 0xee, 0x7b, 0xce, 0x9d, 0xba, 0x22, 0x9e, 0x5f, 0x04,
-	0x05, 0xdc, 0x06, 0x3c, 0xbe, 0x71, 0x1c, 0x25, 0x28, 0xb1, 0x41, 0x7c, 0xcf, 0x91, 0xa8, 0x59,
-	0xf4, 0x16, 0x8c, 0x08, 0x93, 0x7c, 0xfc, 0x82, 0x3a, 0xc2, 0xa7, 0x70, 0x32, 0x65, 0x3f, 0x31,
-	0xfe, 0x33, 0x2b, 0x52, 0xc9, 0xbb, 0x80, 0xa2, 0x2f, 0x8b, 0xb8, 0x92, 0x88, 0x6b, 0x2a, 0x95,
-	0x33, 0x22, 0x1e, 0x53, 0x8c, 0x4b, 0x75, 0xc5, 0xa7, 0xcc, 0x45, 0xc7, 0xf8, 0x0c, 0xd0, 0x98,
-	0x88, 0xc9, 0xa8, 0x4c, 0xaa, 0xa8, 0x10, 0x5c, 0xa0, 0x93, 0x7a, 0xee, 0xf2, 0xb6, 0x6a, 0x19,
-	0x15, 0x6d, 0xd1, 0xdb, 0xc0, 0x13, 0xd4, 0xb5, 0x45, 0x1c, 0xee, 0x52, 0xd4, 0xc2, 0xe7, 0x70,
-	0x56, 0x4f, 0x8a, 0xcb, 0x11, 0x15, 0x45, 0xa0, 0x09, 0x67, 0xe8, 0x9f, 0xc6, 0x70, 0x0e, 0x3d,
-	0x93, 0x2e, 0xfa, 0xf7, 0x8f, 0x89, 0x4e, 0x57, 0x3a, 0x5a, 0xe8, 0xb4, 0xff, 0x21, 0x9c, 0xa5,
-	0xcb, 0x79, 0x7d, 0x2c, 0x8a, 0x1b, 0x6c, 0x88, 0x37, 0xbe, 0xb4, 0x20, 0x9c, 0xff, 0x16, 0x2e,
-	0xf4, 0x2f, 0xdf, 0x2c, 0x96, 0xf9, 0xfd, 0xc3, 0xac, 0xb8, 0x18, 0x06, 0x1b, 0xdb, 0x07, 0x76,
-	0xbb, 0xbd, 0x13, 0xb3, 0x41, 0xb1, 0x7d, 0x66, 0xef, 0xcb, 0xf7, 0xff, 0x06, 0x00, 0x00, 0xff,
-	0xff, 0xc1, 0x81, 0xd7, 0x0c, 0x50, 0x05, 0x00, 0x00,
+	0x34, 0x9d, 0x3a, 0x3d, 0x94, 0x90, 0x2d, 0xae, 0x63, 0xd4, 0x16, 0x05, 0x49, 0x59, 0x34, 0xd7,
+	0x7e, 0x40, 0xfb, 0xb9, 0x3d, 0xb6, 0x90, 0x28, 0x25, 0x4e, 0xb6, 0xbd, 0x58, 0xe6, 0xcc, 0xf0,
+	0xcd, 0xbc, 0x27, 0x8a, 0xd0, 0x4e, 0xb4, 0x4e, 0x07, 0x79, 0x1a, 0xc6, 0x59, 0xb8, 0xc8, 0x57,
+	0x26, 0xee, 0x27, 0xa9, 0xc9, 0x0d, 0xde, 0x2f, 0x1f, 0x59, 0xf7, 0xcd, 0xd2, 0x98, 0xe5, 0x5a,
+	0x0f, 0xca, 0xe5, 0xfc, 0xfe, 0xc3, 0x20, 0x5f, 0x6d, 0x74, 0x96, 0x87, 0x9b, 0xc4, 0x0a, 0xbb,
+	0x17, 0x65, 0x81, 0x24, 0x35, 0x89, 0xc9, 0xc2, 0xb5, 0x4a, 0x75, 0x96, 0x98, 0x38, 0xd3, 0x15,
+	0x7b, 0xba, 0x30, 0x9b, 0x8d, 0x89, 0x07, 0xf6, 0x61, 0xc1, 0xde, 0xaf, 0xd0, 0x9a, 0xac, 0x96,
+	0xb1, 0x8e, 0xe4, 0x93, 0x2d, 0xfe, 0x0e, 0x5a, 0x5b, 0x29, 0xd4, 0xfc, 0x21, 0xd7, 0x59, 0xa7,
+	0xf1, 0xb6, 0xf1, 0xee, 0x48, 0xa0, 0x2d, 0x62, 0x58, 0xe0, 0xf8, 0x02, 0x0e, 0xb3, 0xd5, 0x32,
+	0x0e, 0xf3, 0xfb, 0x54, 0x77, 0x76, 0x4a, 0xd1, 0x13, 0xd0, 0xfb, 0xa3, 0x01, 0x67, 0x41, 0x6a,
+	0x16, 0x3a, 0xcb, 0x9e, 0x7b, 0x0c, 0xe1, 0x74, 0xab, 0x14, 0x8d, 0x3f, 0xea, 0xb5, 0x49, 0x74,
+	0xe9, 0xd2, 0xbc, 0x44, 0xfd, 0x2a, 0x64, 0x8d, 0x8b, 0xff, 0x12, 0xe3, 0xaf, 0xe1, 0xf8, 0x63,
+	0xb8, 0x5e, 0x45, 0x61, 0x81, 0x3a, 0x26, 0xb2, 0xfe, 0x7b, 0xe2, 0x05, 0xda, 0x1b, 0x42, 0x73,
+	0xdb, 0xfa, 0x3d, 0x1c, 0xd8, 0x7f, 0x45, 0x53, 0xbb, 0xef, 0x9a, 0x97, 0xe7, 0x76, 0x18, 0x59,
+	0x7f, 0x4b, 0x45, 0xca, 0x5f, 0x51, 0x2b, 0x7b, 0x14, 0x5a, 0x9f, 0xb0, 0xb8, 0x0d, 0xfb, 0x77,
+	0x3a, 0x8c, 0x74, 0x5a, 0x4d, 0xa7, 0x5a, 0xe1, 0x0e, 0x1c, 0x24, 0xe1, 0xc3, 0xda, 0x84, 0x51,
+	0x35, 0x91, 0x7a, 0xd9, 0xfb, 0xab, 0x01, 0x6d, 0xe7, 0x2e, 0x5c, 0xc5, 0x0b, 0x13, 0x69, 0x5b,
+	0x25, 0xb0, 0x14, 0xfe, 0x11, 0xba, 0x8b, 0x9a, 0x51, 0x8f, 0x2f, 0xb1, 0xae, 0x63, 0x0d, 0x3a,
+	0x8f, 0x8a, 0xa0, 0x12, 0xd4, 0xbb, 0xbf, 0x87, 0x7d, 0x1b, 0xad, 0x74, 0x6c, 0x5e, 0xbe, 0xa9,
+	0x7b, 0x7a, 0x74, 0xa3, 0x71, 0x64, 0xd2, 0x4c, 0x47, 0x55, 0x67, 0x95, 0xbc, 0xf7, 0x67, 0x03,
+	0x5e, 0xff, 0x8f, 0x06, 0xff, 0x00, 0xe7, 0x9f, 0x9c, 0xa6, 0x17, 0x89, 0x5e, 0xd7, 0x02, 0x51,
+	0xf1, 0x4f, 0x81, 0x8e, 0xb4, 0xad, 0xb6, 0xd1, 0x71, 0x9e, 0x75, 0x76, 0xca, 0x51, 0x9f, 0xd6,
+	0xb1, 0xe8, 0x13, 0x27, 0x9e, 0x09, 0xbf, 0xfd, 0x7b, 0x17, 0x90, 0xfc, 0xfd, 0xe6, 0xd9, 0x2b,
+	0xc4, 0x87, 0xb0, 0x77, 0x43, 0x7c, 0xcf, 0x45, 0x9f, 0x61, 0x04, 0x47, 0xcc, 0xf3, 0x15, 0x65,
+	0x37, 0xd4, 0xe7, 0x01, 0x45, 0x0d, 0x7c, 0x02, 0xcd, 0x21, 0x71, 0x55, 0x40, 0x6e, 0x7d, 0x4e,
+	0x5c, 0xb4, 0x83, 0x5f, 0x41, 0xab, 0x00, 0x1c, 0x3e, 0x1e, 0x73, 0xa6, 0x46, 0x94, 0xb8, 0x54,
+	0xa0, 0x5d, 0x7c, 0x0e, 0xaf, 0x4a, 0x58, 0x50, 0x22, 0xb9, 0x50, 0x13, 0xef, 0x9a, 0x11, 0x39,
+	0x15, 0x14, 0x7d, 0x8e, 0xdf, 0xc2, 0x85, 0xc7, 0x4a, 0x07, 0x45, 0x99, 0xcb, 0xc5, 0x84, 0x0a,
+	0x25, 0x05, 0x61, 0x13, 0xe2, 0x48, 0x8f, 0x33, 0xb4, 0x87, 0xbf, 0x82, 0x6e, 0xad, 0x70, 0x38,
+	0xbb, 0xf2, 0xae, 0x9f, 0xf1, 0xfb, 0xb8, 0x0b, 0xed, 0x29, 0x9b, 0x4c, 0x83, 0x80, 0x0b, 0x49,
+	0x5d, 0x25, 0x67, 0x8f, 0x79, 0x0e, 0xea, 0x3c, 0x81, 0xe0, 0x01, 0x9f, 0x10, 0x5f, 0xc9, 0x99,
+	0xe7, 0xa2, 0x2f, 0x30, 0x86, 0x63, 0x77, 0x1a, 0xf8, 0x9e, 0x43, 0x24, 0xb5, 0xd8, 0x61, 0x61,
+	0x53, 0x05, 0x18, 0x53, 0x26, 0x55, 0xc0, 0x7d, 0xcf, 0xb9, 0x55, 0x57, 0xc4, 0xf3, 0x8b, 0xa0,
+	0x80, 0xdb, 0x80, 0xc7, 0x37, 0x8e, 0xa3, 0x04, 0x25, 0x36, 0x88, 0xef, 0x39, 0x12, 0x35, 0x8b,
+	0xde, 0x82, 0x11, 0x61, 0x92, 0x8f, 0x5f, 0x50, 0x47, 0xf8, 0x14, 0x4e, 0xa6, 0xec, 0x27, 0xc6,
+	0x7f, 0x66, 0x45, 0x2a, 0x79, 0x1b, 0x50, 0xf4, 0x65, 0x11, 0x57, 0x12, 0x71, 0x4d, 0xa5, 0x72,
+	0x46, 0xc4, 0x63, 0x8a, 0x71, 0xa9, 0xae, 0xf8, 0x94, 0xb9, 0xe8, 0x18, 0x9f, 0x01, 0x1a, 0x13,
+	0x31, 0x19, 0x95, 0x49, 0x15, 0x15, 0x82, 0x0b, 0x74, 0x52, 0xcf, 0x5d, 0xce, 0xaa, 0x96, 0x51,
+	0xd1, 0x16, 0x9d, 0x05, 0x9e, 0xa0, 0xae, 0x2d, 0xe2, 0x70, 0x97, 0xa2, 0x16, 0xbe, 0x80, 0x8e,
+	0x4b, 0x03, 0xca, 0x5c, 0xca, 0x9c, 0x5b, 0xc5, 0x99, 0xaa, 0xe7, 0x26, 0x67, 0x08, 0xe3, 0x73,
+	0x38, 0xab, 0xd7, 0x5c, 0x8e, 0xa8, 0x28, 0xe2, 0x4e, 0x38, 0x43, 0xff, 0x34, 0x86, 0x0b, 0xe8,
+	0x99, 0x74, 0xd9, 0xbf, 0x7b, 0x48, 0x74, 0xba, 0xd6, 0xd1, 0x52, 0xa7, 0xfd, 0x0f, 0xe1, 0x3c,
+	0x5d, 0x2d, 0xea, 0x43, 0x53, 0xdc, 0x6f, 0x43, 0xbc, 0xf5, 0x1d, 0x06, 0xe1, 0xe2, 0xb7, 0x70,
+	0xa9, 0x7f, 0xf9, 0x66, 0xb9, 0xca, 0xef, 0xee, 0xe7, 0xc5, 0xb5, 0x31, 0xd8, 0xda, 0x3e, 0xb0,
+	0xdb, 0xed, 0x8d, 0x99, 0x0d, 0x8a, 0xed, 0x73, 0x7b, 0x9b, 0xbe, 0xff, 0x77, 0x00, 0xb7, 0xa3,
+	0xf5, 0x41, 0x6e, 0x05, 0x00, 0x00,
 }
@@ -21,6 +21,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
 )
 
 // GetChainIDFromBlockBytes returns chain ID given byte array which represents the block
@@ -105,6 +106,86 @@ func GetBlockFromBlockBytes(blockBytes []byte) (*cb.Block, error) {
 	return block, err
 }
 
+// GetTxIDsFromBlock returns the IDs of the transactions of a block, in order,
+// with an empty ID for the malformed ones
+func GetTxIDsFromBlock(block *cb.Block) []string {
+	if block.Data == nil {
+		return nil
+	}
+	txIDs := make([]string, len(block.Data.Data))
+	for i := range block.Data.Data {
+		env, err := ExtractEnvelope(block, i)
+		if err != nil {
+			continue
+		}
+		payload, err := GetPayload(env)
+		if err != nil || payload.Header == nil {
+			continue
+		}
+		chdr, err := UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			continue
+		}
+		txIDs[i] = chdr.TxId
+	}
+	return txIDs
+}
+
+// GetTxValidationCodesFromBlock returns the validation codes the committer
+// recorded in the TRANSACTIONS_FILTER metadata of a block, in the order of
+// its transactions. It fails for the blocks which do not carry the code of
+// each of their transactions, like the blocks not validated yet
+func GetTxValidationCodesFromBlock(block *cb.Block) ([]peer.TxValidationCode, error) {
+	var txCount int
+	if block.Data != nil {
+		txCount = len(block.Data.Data)
+	}
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		return nil, fmt.Errorf("block has no transactions filter")
+	}
+	txsFilter := block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER]
+	if len(txsFilter) != txCount {
+		return nil, fmt.Errorf("transactions filter has %d validation codes for %d transactions", len(txsFilter), txCount)
+	}
+	codes := make([]peer.TxValidationCode, txCount)
+	for i, flag := range txsFilter {
+		codes[i] = peer.TxValidationCode(flag)
+	}
+	return codes, nil
+}
+
+// GetTxValidationCodeFromBlock returns the validation code the committer
+// recorded for the transaction at txIndex in the block
+func GetTxValidationCodeFromBlock(block *cb.Block, txIndex int) (peer.TxValidationCode, error) {
+	codes, err := GetTxValidationCodesFromBlock(block)
+	if err != nil {
+		return 0, err
+	}
+	if txIndex < 0 || txIndex >= len(codes) {
+		return 0, fmt.Errorf("block has no transaction at index %d", txIndex)
+	}
+	return codes[txIndex], nil
+}
+
+// GetTxValidationCodesByTxID maps the IDs of the transactions of a block to
+// their validation codes. When several transactions carry the same ID, only
+// the first one, which is the only one the committer may have accepted, is
+// kept. The malformed transactions, without ID, are left out
+func GetTxValidationCodesByTxID(block *cb.Block) (map[string]peer.TxValidationCode, error) {
+	codes, err := GetTxValidationCodesFromBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	codesByTxID := make(map[string]peer.TxValidationCode, len(codes))
+	for i, txID := range GetTxIDsFromBlock(block) {
+		if _, ok := codesByTxID[txID]; ok || txID == "" {
+			continue
+		}
+		codesByTxID[txID] = codes[i]
+	}
+	return codesByTxID, nil
+}
+
 // CopyBlockMetadata copies metadata from one block into another
 func CopyBlockMetadata(src *cb.Block, dst *cb.Block) {
 	dst.Metadata = src.Metadata
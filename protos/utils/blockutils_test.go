@@ -23,6 +23,7 @@ import (
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/protos/common"
 	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 )
 
@@ -70,6 +71,70 @@ func TestGetBlockFromBlockBytes(t *testing.T) {
 	}
 }
 
+func TestGetTxIDsFromBlock(t *testing.T) {
+	gb, err := configtxtest.MakeGenesisBlock("myuniquetestchainid")
+	if err != nil {
+		t.Fatalf("failed to create test configuration block: %s", err)
+	}
+	gb.Data.Data = append(gb.Data.Data, []byte("garbage"))
+
+	txIDs := utils.GetTxIDsFromBlock(gb)
+	if len(txIDs) != 2 {
+		t.Fatalf("expected 2 transaction IDs, got %d", len(txIDs))
+	}
+	payload, err := utils.GetPayload(utils.ExtractEnvelopeOrPanic(gb, 0))
+	if err != nil {
+		t.Fatalf("failed to get payload: %s", err)
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		t.Fatalf("failed to get channel header: %s", err)
+	}
+	if txIDs[0] != chdr.TxId || txIDs[1] != "" {
+		t.Fatalf("unexpected transaction IDs %v", txIDs)
+	}
+}
+
+func TestGetTxValidationCodesFromBlock(t *testing.T) {
+	gb, err := configtxtest.MakeGenesisBlock("myuniquetestchainid")
+	if err != nil {
+		t.Fatalf("failed to create test configuration block: %s", err)
+	}
+	// a duplicate of the configuration transaction and a malformed one
+	gb.Data.Data = append(gb.Data.Data, gb.Data.Data[0], []byte("garbage"))
+
+	if _, err = utils.GetTxValidationCodesFromBlock(gb); err == nil {
+		t.Fatalf("expected an error for a block without the validation codes of all its transactions")
+	}
+
+	gb.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{
+		uint8(pb.TxValidationCode_VALID), uint8(pb.TxValidationCode_DUPLICATE_TXID), uint8(pb.TxValidationCode_BAD_PAYLOAD)}
+	codes, err := utils.GetTxValidationCodesFromBlock(gb)
+	if err != nil {
+		t.Fatalf("failed to get validation codes: %s", err)
+	}
+	if len(codes) != 3 || codes[1] != pb.TxValidationCode_DUPLICATE_TXID {
+		t.Fatalf("unexpected validation codes %v", codes)
+	}
+
+	code, err := utils.GetTxValidationCodeFromBlock(gb, 2)
+	if err != nil || code != pb.TxValidationCode_BAD_PAYLOAD {
+		t.Fatalf("unexpected validation code %s for transaction 2: %v", code, err)
+	}
+	if _, err = utils.GetTxValidationCodeFromBlock(gb, 3); err == nil {
+		t.Fatalf("expected an error for a transaction index out of the block")
+	}
+
+	codesByTxID, err := utils.GetTxValidationCodesByTxID(gb)
+	if err != nil {
+		t.Fatalf("failed to get validation codes by transaction ID: %s", err)
+	}
+	txID := utils.GetTxIDsFromBlock(gb)[0]
+	if len(codesByTxID) != 1 || codesByTxID[txID] != pb.TxValidationCode_VALID {
+		t.Fatalf("unexpected validation codes by transaction ID %v", codesByTxID)
+	}
+}
+
 func TestGetMetadataFromNewBlock(t *testing.T) {
 	block := common.NewBlock(0, nil)
 	md, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_ORDERER)
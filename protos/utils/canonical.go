@@ -0,0 +1,322 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// The protobuf library encodes the fields of a message in field number order,
+// but the entries of its maps in random order. The encoding of messages with
+// maps, such as the channel configuration, therefore differs from one marshal
+// to the next and from one client to the other, which breaks the signatures
+// and hashes computed over it. MarshalCanonical encodes map entries sorted by
+// key, and falls back to proto.Marshal for the messages without maps.
+
+var (
+	hasMapsLock  sync.Mutex
+	hasMapsCache = make(map[reflect.Type]bool)
+)
+
+// MarshalCanonical serializes a protobuf message canonically: fields are
+// encoded in field number order, and map entries in key order. Equal messages
+// have the same canonical encoding, whatever encoded them. It is the encoding
+// to use for the bytes that are signed or hashed.
+func MarshalCanonical(pb proto.Message) ([]byte, error) {
+	v := reflect.ValueOf(pb)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct || !hasMaps(v.Elem().Type()) {
+		return proto.Marshal(pb)
+	}
+	return marshalCanonicalStruct(v.Elem())
+}
+
+// MarshalCanonicalOrPanic serializes a protobuf message canonically and
+// panics if this operation fails.
+func MarshalCanonicalOrPanic(pb proto.Message) []byte {
+	data, err := MarshalCanonical(pb)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// CheckCanonical checks that data, which pb was unmarshaled from, is the
+// canonical encoding of pb. Signed bytes which are not canonical cannot be
+// reproduced from the message they encode, and are rejected to avoid
+// signature mismatches between differently built clients.
+func CheckCanonical(pb proto.Message, data []byte) error {
+	canonical, err := MarshalCanonical(pb)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal %T canonically: %s", pb, err)
+	}
+	if !bytes.Equal(canonical, data) {
+		return fmt.Errorf("%T is not canonically encoded", pb)
+	}
+	return nil
+}
+
+// hasMaps tells whether messages of type t, a struct, contain maps, directly
+// or through their submessages
+func hasMaps(t reflect.Type) bool {
+	hasMapsLock.Lock()
+	defer hasMapsLock.Unlock()
+	return hasMapsLocked(t)
+}
+
+func hasMapsLocked(t reflect.Type) bool {
+	if res, ok := hasMapsCache[t]; ok {
+		return res
+	}
+	// recursive messages are assumed map free until proven otherwise
+	hasMapsCache[t] = false
+
+	var fieldTypes []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		fieldTypes = append(fieldTypes, t.Field(i).Type)
+	}
+	for _, oneof := range proto.GetProperties(t).OneofTypes {
+		fieldTypes = append(fieldTypes, oneof.Type.Elem().Field(0).Type)
+	}
+
+	res := false
+	for _, ft := range fieldTypes {
+		if ft.Kind() == reflect.Map {
+			res = true
+			break
+		}
+		if ft.Kind() == reflect.Slice {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Struct && hasMapsLocked(ft.Elem()) {
+			res = true
+			break
+		}
+	}
+	hasMapsCache[t] = res
+	return res
+}
+
+type canonicalField struct {
+	num    int
+	encode func(*proto.Buffer) error
+}
+
+// canonicalFields sorts fields by field number
+type canonicalFields []canonicalField
+
+func (fs canonicalFields) Len() int           { return len(fs) }
+func (fs canonicalFields) Swap(i, j int)      { fs[i], fs[j] = fs[j], fs[i] }
+func (fs canonicalFields) Less(i, j int) bool { return fs[i].num < fs[j].num }
+
+func marshalCanonicalStruct(v reflect.Value) ([]byte, error) {
+	t := v.Type()
+	sprops := proto.GetProperties(t)
+
+	var fields []canonicalField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fv := v.Field(i)
+		if strings.HasPrefix(sf.Name, "XXX_") {
+			continue
+		}
+
+		if sf.Tag.Get("protobuf_oneof") != "" {
+			if fv.IsNil() {
+				continue
+			}
+			for _, oneof := range sprops.OneofTypes {
+				if oneof.Type == fv.Elem().Type() {
+					prop, wrapped := oneof.Prop, fv.Elem().Elem().Field(0)
+					fields = append(fields, canonicalField{prop.Tag, func(buf *proto.Buffer) error {
+						return encodeCanonicalValue(buf, prop, wrapped)
+					}})
+				}
+			}
+			continue
+		}
+
+		prop := &proto.Properties{}
+		prop.Parse(sf.Tag.Get("protobuf"))
+		fields = append(fields, canonicalField{prop.Tag, canonicalFieldEncoder(v, i, prop)})
+	}
+	sort.Sort(canonicalFields(fields))
+
+	buf := proto.NewBuffer(nil)
+	for _, f := range fields {
+		if err := f.encode(buf); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// canonicalFieldEncoder returns the function encoding the i-th field of the
+// struct v
+func canonicalFieldEncoder(v reflect.Value, i int, prop *proto.Properties) func(*proto.Buffer) error {
+	sf := v.Type().Field(i)
+	fv := v.Field(i)
+
+	switch {
+	case fv.Kind() == reflect.Map:
+		return func(buf *proto.Buffer) error {
+			return encodeCanonicalMap(buf, prop.Tag, sf, fv)
+		}
+	case fv.Kind() == reflect.Ptr && hasMaps(sf.Type.Elem()):
+		return func(buf *proto.Buffer) error {
+			if fv.IsNil() {
+				return nil
+			}
+			return encodeCanonicalValue(buf, prop, fv)
+		}
+	case fv.Kind() == reflect.Slice && sf.Type.Elem().Kind() == reflect.Ptr && hasMaps(sf.Type.Elem().Elem()):
+		return func(buf *proto.Buffer) error {
+			for j := 0; j < fv.Len(); j++ {
+				if fv.Index(j).IsNil() {
+					return fmt.Errorf("proto: repeated field %s has nil element", sf.Name)
+				}
+				if err := encodeCanonicalValue(buf, prop, fv.Index(j)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	default:
+		// the encoding of the field alone is deterministic
+		return func(buf *proto.Buffer) error {
+			single := reflect.New(v.Type())
+			single.Elem().Field(i).Set(fv)
+			return buf.Marshal(single.Interface().(proto.Message))
+		}
+	}
+}
+
+func encodeCanonicalMap(buf *proto.Buffer, num int, sf reflect.StructField, fv reflect.Value) error {
+	keyProp := &proto.Properties{}
+	keyProp.Parse(sf.Tag.Get("protobuf_key"))
+	valProp := &proto.Properties{}
+	valProp.Parse(sf.Tag.Get("protobuf_val"))
+
+	keys := fv.MapKeys()
+	sort.Sort(mapKeys(keys))
+
+	for _, key := range keys {
+		val := fv.MapIndex(key)
+		if val.Kind() == reflect.Ptr && val.IsNil() {
+			return fmt.Errorf("proto: map %s has nil element", sf.Name)
+		}
+		entry := proto.NewBuffer(nil)
+		if err := encodeCanonicalValue(entry, keyProp, key); err != nil {
+			return err
+		}
+		if err := encodeCanonicalValue(entry, valProp, val); err != nil {
+			return err
+		}
+		buf.EncodeVarint(uint64(num)<<3 | proto.WireBytes)
+		buf.EncodeRawBytes(entry.Bytes())
+	}
+	return nil
+}
+
+// mapKeys sorts the keys of a map, which protobuf restricts to integral and
+// string types
+type mapKeys []reflect.Value
+
+func (ks mapKeys) Len() int      { return len(ks) }
+func (ks mapKeys) Swap(i, j int) { ks[i], ks[j] = ks[j], ks[i] }
+func (ks mapKeys) Less(i, j int) bool {
+	a, b := ks[i], ks[j]
+	switch a.Kind() {
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	case reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	default:
+		return a.Uint() < b.Uint()
+	}
+}
+
+// encodeCanonicalValue encodes a single value, always, even a zero one, as
+// map entries and oneof fields are
+func encodeCanonicalValue(buf *proto.Buffer, prop *proto.Properties, v reflect.Value) error {
+	tag := uint64(prop.Tag) << 3
+	switch prop.Wire {
+	case "bytes":
+		buf.EncodeVarint(tag | proto.WireBytes)
+		switch v.Kind() {
+		case reflect.String:
+			return buf.EncodeStringBytes(v.String())
+		case reflect.Slice:
+			return buf.EncodeRawBytes(v.Bytes())
+		case reflect.Ptr:
+			data, err := MarshalCanonical(v.Interface().(proto.Message))
+			if err != nil {
+				return err
+			}
+			return buf.EncodeRawBytes(data)
+		}
+	case "varint":
+		buf.EncodeVarint(tag | proto.WireVarint)
+		switch v.Kind() {
+		case reflect.Bool:
+			if v.Bool() {
+				return buf.EncodeVarint(1)
+			}
+			return buf.EncodeVarint(0)
+		case reflect.Int32, reflect.Int64:
+			return buf.EncodeVarint(uint64(v.Int()))
+		case reflect.Uint32, reflect.Uint64:
+			return buf.EncodeVarint(v.Uint())
+		}
+	case "zigzag32":
+		buf.EncodeVarint(tag | proto.WireVarint)
+		return buf.EncodeZigzag32(uint64(v.Int()))
+	case "zigzag64":
+		buf.EncodeVarint(tag | proto.WireVarint)
+		return buf.EncodeZigzag64(uint64(v.Int()))
+	case "fixed32":
+		buf.EncodeVarint(tag | proto.WireFixed32)
+		switch v.Kind() {
+		case reflect.Float32:
+			return buf.EncodeFixed32(uint64(math.Float32bits(float32(v.Float()))))
+		case reflect.Int32:
+			return buf.EncodeFixed32(uint64(v.Int()))
+		case reflect.Uint32:
+			return buf.EncodeFixed32(v.Uint())
+		}
+	case "fixed64":
+		buf.EncodeVarint(tag | proto.WireFixed64)
+		switch v.Kind() {
+		case reflect.Float64:
+			return buf.EncodeFixed64(math.Float64bits(v.Float()))
+		case reflect.Int64:
+			return buf.EncodeFixed64(uint64(v.Int()))
+		case reflect.Uint64:
+			return buf.EncodeFixed64(v.Uint())
+		}
+	}
+	return fmt.Errorf("proto: cannot canonically encode %s as %s", v.Type(), prop.Wire)
+}
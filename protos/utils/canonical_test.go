@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. 2017 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+                 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+func sampleConfig() *cb.Config {
+	root := cb.NewConfigGroup()
+	for i := 0; i < 10; i++ {
+		org := cb.NewConfigGroup()
+		org.Version = uint64(i)
+		org.Values[fmt.Sprintf("value%d", i)] = &cb.ConfigValue{Value: []byte{byte(i)}, ModPolicy: "Admins"}
+		org.Values["MSP"] = &cb.ConfigValue{Value: []byte("msp"), ModPolicy: "Admins"}
+		org.Policies["Admins"] = &cb.ConfigPolicy{Policy: &cb.Policy{Type: 1, Policy: []byte{byte(i)}}}
+		root.Groups[fmt.Sprintf("Org%d", i)] = org
+	}
+	return &cb.Config{Sequence: 3, ChannelGroup: root}
+}
+
+func TestMarshalCanonicalMaps(t *testing.T) {
+	config := sampleConfig()
+
+	canonical, err := MarshalCanonical(config)
+	if err != nil {
+		t.Fatalf("MarshalCanonical failed: %s", err)
+	}
+	for i := 0; i < 10; i++ {
+		if again := MarshalCanonicalOrPanic(config); !bytes.Equal(canonical, again) {
+			t.Fatalf("Canonical encoding should not change between marshals")
+		}
+	}
+
+	decoded := &cb.Config{}
+	if err = proto.Unmarshal(canonical, decoded); err != nil {
+		t.Fatalf("Canonical encoding should be valid protobuf: %s", err)
+	}
+	if !proto.Equal(config, decoded) {
+		t.Fatalf("Canonical encoding should decode to the original message")
+	}
+	if err = CheckCanonical(decoded, canonical); err != nil {
+		t.Fatalf("CheckCanonical failed on canonical encoding: %s", err)
+	}
+}
+
+func TestMarshalCanonicalSingleEntry(t *testing.T) {
+	// with a single entry per map, proto.Marshal is deterministic
+	group := cb.NewConfigGroup()
+	group.Version = 1
+	group.Values["key"] = &cb.ConfigValue{Version: 2, ModPolicy: "Admins"}
+	group.Groups["zero"] = cb.NewConfigGroup()
+	group.Policies["empty"] = &cb.ConfigPolicy{}
+
+	expected, err := proto.Marshal(group)
+	if err != nil {
+		t.Fatalf("proto.Marshal failed: %s", err)
+	}
+	if canonical := MarshalCanonicalOrPanic(group); !bytes.Equal(canonical, expected) {
+		t.Fatalf("Expected %x, got %x", expected, canonical)
+	}
+
+	group.Groups["nil"] = nil
+	if _, err = MarshalCanonical(group); err == nil {
+		t.Fatalf("MarshalCanonical should have failed on nil map value")
+	}
+}
+
+func TestMarshalCanonicalWithoutMaps(t *testing.T) {
+	prp := &pb.ProposalResponsePayload{ProposalHash: []byte("hash"), Extension: []byte("extension")}
+	expected, err := proto.Marshal(prp)
+	if err != nil {
+		t.Fatalf("proto.Marshal failed: %s", err)
+	}
+	if canonical, err := Marshal(prp); err != nil || !bytes.Equal(canonical, expected) {
+		t.Fatalf("Expected %x, got %x (err %v)", expected, canonical, err)
+	}
+}
+
+func TestCheckCanonical(t *testing.T) {
+	// the fields of the channel header encoded out of order
+	reordered := append(MarshalOrPanic(&cb.ChannelHeader{TxId: "txid"}), MarshalOrPanic(&cb.ChannelHeader{ChannelId: "channel"})...)
+	chdr, err := UnmarshalChannelHeader(reordered)
+	if err != nil {
+		t.Fatalf("UnmarshalChannelHeader failed: %s", err)
+	}
+	if chdr.TxId != "txid" || chdr.ChannelId != "channel" {
+		t.Fatalf("Unexpected channel header %v", chdr)
+	}
+	if err = CheckCanonical(chdr, reordered); err == nil {
+		t.Fatalf("CheckCanonical should have rejected fields out of order")
+	}
+	if err = CheckCanonical(chdr, MarshalOrPanic(chdr)); err != nil {
+		t.Fatalf("CheckCanonical failed on canonical encoding: %s", err)
+	}
+}
@@ -29,18 +29,14 @@ import (
 	"github.com/hyperledger/fabric/common/crypto"
 )
 
-// MarshalOrPanic serializes a protobuf message and panics if this operation fails.
+// MarshalOrPanic serializes a protobuf message canonically and panics if this operation fails.
 func MarshalOrPanic(pb proto.Message) []byte {
-	data, err := proto.Marshal(pb)
-	if err != nil {
-		panic(err)
-	}
-	return data
+	return MarshalCanonicalOrPanic(pb)
 }
 
-// Marshal serializes a protobuf message.
+// Marshal serializes a protobuf message canonically.
 func Marshal(pb proto.Message) ([]byte, error) {
-	return proto.Marshal(pb)
+	return MarshalCanonical(pb)
 }
 
 // CreateNonceOrPanic generates a nonce using the common/crypto package
@@ -342,18 +342,18 @@ func CreateChaincodeProposalWithTransient(typ common.HeaderType, chainID string,
 // CreateChaincodeProposalWithTxIDNonceAndTransient creates a proposal from given input
 func CreateChaincodeProposalWithTxIDNonceAndTransient(txid string, typ common.HeaderType, chainID string, cis *peer.ChaincodeInvocationSpec, nonce, creator []byte, transientMap map[string][]byte) (*peer.Proposal, string, error) {
 	ccHdrExt := &peer.ChaincodeHeaderExtension{ChaincodeId: cis.ChaincodeSpec.ChaincodeId}
-	ccHdrExtBytes, err := proto.Marshal(ccHdrExt)
+	ccHdrExtBytes, err := Marshal(ccHdrExt)
 	if err != nil {
 		return nil, "", err
 	}
 
-	cisBytes, err := proto.Marshal(cis)
+	cisBytes, err := Marshal(cis)
 	if err != nil {
 		return nil, "", err
 	}
 
 	ccPropPayload := &peer.ChaincodeProposalPayload{Input: cisBytes, TransientMap: transientMap}
-	ccPropPayloadBytes, err := proto.Marshal(ccPropPayload)
+	ccPropPayloadBytes, err := Marshal(ccPropPayload)
 	if err != nil {
 		return nil, "", err
 	}
@@ -373,7 +373,7 @@ func CreateChaincodeProposalWithTxIDNonceAndTransient(txid string, typ common.He
 		Epoch:     epoch}),
 		SignatureHeader: MarshalOrPanic(&common.SignatureHeader{Nonce: nonce, Creator: creator})}
 
-	hdrBytes, err := proto.Marshal(hdr)
+	hdrBytes, err := Marshal(hdr)
 	if err != nil {
 		return nil, "", err
 	}
@@ -382,15 +382,15 @@ func CreateChaincodeProposalWithTxIDNonceAndTransient(txid string, typ common.He
 }
 
 // GetBytesProposalResponsePayload gets proposal response payload
-func GetBytesProposalResponsePayload(hash []byte, response *peer.Response, result []byte, event []byte) ([]byte, error) {
-	cAct := &peer.ChaincodeAction{Events: event, Results: result, Response: response}
-	cActBytes, err := proto.Marshal(cAct)
+func GetBytesProposalResponsePayload(hash []byte, response *peer.Response, result []byte, event []byte, ccid *peer.ChaincodeID) ([]byte, error) {
+	cAct := &peer.ChaincodeAction{Events: event, Results: result, Response: response, ChaincodeId: ccid}
+	cActBytes, err := Marshal(cAct)
 	if err != nil {
 		return nil, err
 	}
 
 	prp := &peer.ProposalResponsePayload{Extension: cActBytes, ProposalHash: hash}
-	prpBytes, err := proto.Marshal(prp)
+	prpBytes, err := Marshal(prp)
 	if err != nil {
 		return nil, err
 	}
@@ -400,7 +400,7 @@ func GetBytesProposalResponsePayload(hash []byte, response *peer.Response, resul
 
 // GetBytesChaincodeProposalPayload gets the chaincode proposal payload
 func GetBytesChaincodeProposalPayload(cpp *peer.ChaincodeProposalPayload) ([]byte, error) {
-	cppBytes, err := proto.Marshal(cpp)
+	cppBytes, err := Marshal(cpp)
 	if err != nil {
 		return nil, err
 	}
@@ -410,7 +410,7 @@ func GetBytesChaincodeProposalPayload(cpp *peer.ChaincodeProposalPayload) ([]byt
 
 // GetBytesResponse gets the bytes of Response
 func GetBytesResponse(res *peer.Response) ([]byte, error) {
-	resBytes, err := proto.Marshal(res)
+	resBytes, err := Marshal(res)
 	if err != nil {
 		return nil, err
 	}
@@ -420,7 +420,7 @@ func GetBytesResponse(res *peer.Response) ([]byte, error) {
 
 // GetBytesChaincodeEvent gets the bytes of ChaincodeEvent
 func GetBytesChaincodeEvent(event *peer.ChaincodeEvent) ([]byte, error) {
-	eventBytes, err := proto.Marshal(event)
+	eventBytes, err := Marshal(event)
 	if err != nil {
 		return nil, err
 	}
@@ -430,7 +430,7 @@ func GetBytesChaincodeEvent(event *peer.ChaincodeEvent) ([]byte, error) {
 
 // GetBytesChaincodeActionPayload get the bytes of ChaincodeActionPayload from the message
 func GetBytesChaincodeActionPayload(cap *peer.ChaincodeActionPayload) ([]byte, error) {
-	capBytes, err := proto.Marshal(cap)
+	capBytes, err := Marshal(cap)
 	if err != nil {
 		return nil, err
 	}
@@ -440,7 +440,7 @@ func GetBytesChaincodeActionPayload(cap *peer.ChaincodeActionPayload) ([]byte, e
 
 // GetBytesProposalResponse gets propoal bytes response
 func GetBytesProposalResponse(pr *peer.ProposalResponse) ([]byte, error) {
-	respBytes, err := proto.Marshal(pr)
+	respBytes, err := Marshal(pr)
 	if err != nil {
 		return nil, err
 	}
@@ -450,7 +450,7 @@ func GetBytesProposalResponse(pr *peer.ProposalResponse) ([]byte, error) {
 
 // GetBytesProposal returns the bytes of a proposal message
 func GetBytesProposal(prop *peer.Proposal) ([]byte, error) {
-	propBytes, err := proto.Marshal(prop)
+	propBytes, err := Marshal(prop)
 	if err != nil {
 		return nil, err
 	}
@@ -460,7 +460,7 @@ func GetBytesProposal(prop *peer.Proposal) ([]byte, error) {
 
 // GetBytesHeader get the bytes of Header from the message
 func GetBytesHeader(hdr *common.Header) ([]byte, error) {
-	bytes, err := proto.Marshal(hdr)
+	bytes, err := Marshal(hdr)
 	if err != nil {
 		return nil, err
 	}
@@ -470,7 +470,7 @@ func GetBytesHeader(hdr *common.Header) ([]byte, error) {
 
 // GetBytesSignatureHeader get the bytes of SignatureHeader from the message
 func GetBytesSignatureHeader(hdr *common.SignatureHeader) ([]byte, error) {
-	bytes, err := proto.Marshal(hdr)
+	bytes, err := Marshal(hdr)
 	if err != nil {
 		return nil, err
 	}
@@ -480,7 +480,7 @@ func GetBytesSignatureHeader(hdr *common.SignatureHeader) ([]byte, error) {
 
 // GetBytesTransaction get the bytes of Transaction from the message
 func GetBytesTransaction(tx *peer.Transaction) ([]byte, error) {
-	bytes, err := proto.Marshal(tx)
+	bytes, err := Marshal(tx)
 	if err != nil {
 		return nil, err
 	}
@@ -490,7 +490,7 @@ func GetBytesTransaction(tx *peer.Transaction) ([]byte, error) {
 
 // GetBytesPayload get the bytes of Payload from the message
 func GetBytesPayload(payl *common.Payload) ([]byte, error) {
-	bytes, err := proto.Marshal(payl)
+	bytes, err := Marshal(payl)
 	if err != nil {
 		return nil, err
 	}
@@ -500,7 +500,7 @@ func GetBytesPayload(payl *common.Payload) ([]byte, error) {
 
 // GetBytesEnvelope get the bytes of Envelope from the message
 func GetBytesEnvelope(env *common.Envelope) ([]byte, error) {
-	bytes, err := proto.Marshal(env)
+	bytes, err := Marshal(env)
 	if err != nil {
 		return nil, err
 	}
@@ -538,6 +538,70 @@ func CreateProposalFromCIS(typ common.HeaderType, chainID string, cis *peer.Chai
 	return CreateChaincodeProposal(typ, chainID, cis, creator)
 }
 
+// SetProposalTLSCertHash binds the proposal to the hash of the TLS client
+// certificate of its creator, which endorsers check against the certificate of
+// the mutual TLS connection they receive the proposal on
+func SetProposalTLSCertHash(prop *peer.Proposal, tlsCertHash []byte) error {
+	hdr, err := GetHeader(prop.Header)
+	if err != nil {
+		return err
+	}
+	chdr, err := UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return err
+	}
+
+	chdr.TlsCertHash = tlsCertHash
+	if hdr.ChannelHeader, err = Marshal(chdr); err != nil {
+		return err
+	}
+	prop.Header, err = GetBytesHeader(hdr)
+	return err
+}
+
+// SetProposalQueryOnly hints the endorsers that the proposal only reads the
+// ledger, which lets them execute it without simulating a transaction
+func SetProposalQueryOnly(prop *peer.Proposal) error {
+	return setProposalHeaderExtension(prop, func(hdrExt *peer.ChaincodeHeaderExtension) {
+		hdrExt.QueryOnly = true
+	})
+}
+
+// SetProposalDryRun asks the endorsers to simulate the proposal and return
+// the results of the simulation without endorsing them
+func SetProposalDryRun(prop *peer.Proposal) error {
+	return setProposalHeaderExtension(prop, func(hdrExt *peer.ChaincodeHeaderExtension) {
+		hdrExt.DryRun = true
+	})
+}
+
+// setProposalHeaderExtension applies set to the chaincode header extension of
+// the proposal and re-encodes its header
+func setProposalHeaderExtension(prop *peer.Proposal, set func(*peer.ChaincodeHeaderExtension)) error {
+	hdr, err := GetHeader(prop.Header)
+	if err != nil {
+		return err
+	}
+	chdr, err := UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return err
+	}
+	hdrExt, err := GetChaincodeHeaderExtension(hdr)
+	if err != nil {
+		return err
+	}
+
+	set(hdrExt)
+	if chdr.Extension, err = Marshal(hdrExt); err != nil {
+		return err
+	}
+	if hdr.ChannelHeader, err = Marshal(chdr); err != nil {
+		return err
+	}
+	prop.Header, err = GetBytesHeader(hdr)
+	return err
+}
+
 // CreateInstallProposalFromCDS returns a install proposal given a serialized identity and a ChaincodeDeploymentSpec
 func CreateInstallProposalFromCDS(ccpack proto.Message, creator []byte) (*peer.Proposal, string, error) {
 	return createProposalFromCDS("", ccpack, creator, nil, nil, nil, "install")
@@ -560,7 +624,7 @@ func createProposalFromCDS(chainID string, msg proto.Message, creator []byte, po
 	var b []byte
 	var err error
 	if msg != nil {
-		b, err = proto.Marshal(msg)
+		b, err = Marshal(msg)
 		if err != nil {
 			return nil, "", err
 		}
@@ -172,7 +172,7 @@ func TestProposalResponse(t *testing.T) {
 	}
 
 	// get the bytes of the ProposalResponsePayload
-	prpBytes, err := utils.GetBytesProposalResponsePayload(pHashBytes, pResponse, results, eventBytes)
+	prpBytes, err := utils.GetBytesProposalResponsePayload(pHashBytes, pResponse, results, eventBytes, nil)
 	if err != nil {
 		t.Fatalf("Failure while marshalling the ProposalResponsePayload")
 		return
@@ -251,7 +251,7 @@ func TestEnvelope(t *testing.T) {
 	response := &pb.Response{Status: 200, Payload: []byte("payload")}
 	result := []byte("res")
 
-	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, response, result, nil, nil, signer)
+	presp, err := utils.CreateProposalResponse(prop.Header, prop.Payload, response, result, nil, nil, nil, signer)
 	if err != nil {
 		t.Fatalf("Could not create proposal response, err %s\n", err)
 		return
@@ -386,6 +386,59 @@ func TestComputeProposalTxID(t *testing.T) {
 	assert.Equal(t, txid, txid2)
 }
 
+func TestSetProposalTLSCertHash(t *testing.T) {
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}}}
+	prop, txid, err := utils.CreateProposalFromCIS(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), cis, []byte("creator"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, utils.SetProposalTLSCertHash(prop, []byte("hash")))
+
+	hdr, err := utils.GetHeader(prop.Header)
+	assert.NoError(t, err)
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hash"), chdr.TlsCertHash)
+	assert.Equal(t, txid, chdr.TxId)
+
+	assert.Error(t, utils.SetProposalTLSCertHash(&pb.Proposal{Header: []byte("garbage")}, []byte("hash")))
+}
+
+func TestSetProposalQueryOnly(t *testing.T) {
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}}}
+	prop, txid, err := utils.CreateProposalFromCIS(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), cis, []byte("creator"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, utils.SetProposalQueryOnly(prop))
+
+	hdr, err := utils.GetHeader(prop.Header)
+	assert.NoError(t, err)
+	hdrExt, err := utils.GetChaincodeHeaderExtension(hdr)
+	assert.NoError(t, err)
+	assert.True(t, hdrExt.QueryOnly)
+	assert.Equal(t, "mycc", hdrExt.ChaincodeId.Name)
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, txid, chdr.TxId)
+
+	assert.Error(t, utils.SetProposalQueryOnly(&pb.Proposal{Header: []byte("garbage")}))
+}
+
+func TestSetProposalDryRun(t *testing.T) {
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}}}
+	prop, _, err := utils.CreateProposalFromCIS(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), cis, []byte("creator"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, utils.SetProposalDryRun(prop))
+
+	hdr, err := utils.GetHeader(prop.Header)
+	assert.NoError(t, err)
+	hdrExt, err := utils.GetChaincodeHeaderExtension(hdr)
+	assert.NoError(t, err)
+	assert.True(t, hdrExt.DryRun)
+	assert.False(t, hdrExt.QueryOnly)
+	assert.Equal(t, "mycc", hdrExt.ChaincodeId.Name)
+}
+
 var signer msp.SigningIdentity
 var signerSerialized []byte
 
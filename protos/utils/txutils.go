@@ -82,7 +82,7 @@ func CreateSignedEnvelope(txType common.HeaderType, channelID string, signer cry
 		return nil, err
 	}
 
-	data, err := proto.Marshal(dataMsg)
+	data, err := Marshal(dataMsg)
 	if err != nil {
 		return nil, err
 	}
@@ -209,8 +209,9 @@ func CreateSignedTx(proposal *peer.Proposal, signer msp.SigningIdentity, resps .
 	return &common.Envelope{Payload: paylBytes, Signature: sig}, nil
 }
 
-// CreateProposalResponse creates a proposal response.
-func CreateProposalResponse(hdrbytes []byte, payl []byte, response *peer.Response, results []byte, events []byte, visibility []byte, signingEndorser msp.SigningIdentity) (*peer.ProposalResponse, error) {
+// CreateProposalResponse creates a proposal response. The chaincode ID, if
+// not nil, records the name and version of the chaincode which was executed
+func CreateProposalResponse(hdrbytes []byte, payl []byte, response *peer.Response, results []byte, events []byte, ccid *peer.ChaincodeID, visibility []byte, signingEndorser msp.SigningIdentity) (*peer.ProposalResponse, error) {
 	hdr, err := GetHeader(hdrbytes)
 	if err != nil {
 		return nil, err
@@ -223,7 +224,7 @@ func CreateProposalResponse(hdrbytes []byte, payl []byte, response *peer.Respons
 	}
 
 	// get the bytes of the proposal response payload - we need to sign them
-	prpBytes, err := GetBytesProposalResponsePayload(pHashBytes, response, results, events)
+	prpBytes, err := GetBytesProposalResponsePayload(pHashBytes, response, results, events, ccid)
 	if err != nil {
 		return nil, errors.New("Failure while unmarshalling the ProposalResponsePayload")
 	}
@@ -277,7 +278,7 @@ func GetSignedEvent(evt *peer.Event, signer msp.SigningIdentity) (*peer.SignedEv
 		return nil, errors.New("nil arguments")
 	}
 
-	evtBytes, err := proto.Marshal(evt)
+	evtBytes, err := Marshal(evt)
 	if err != nil {
 		return nil, err
 	}